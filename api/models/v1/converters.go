@@ -0,0 +1,280 @@
+package v1
+
+import (
+	"ethereum-validator-api/service"
+	"time"
+)
+
+// NewEffectivenessComponentResponse converts a service component into its response representation
+func NewEffectivenessComponentResponse(component service.EffectivenessComponent) EffectivenessComponentResponse {
+	return EffectivenessComponentResponse{
+		Duties:    component.Duties,
+		Fulfilled: component.Fulfilled,
+		Rate:      component.Rate,
+	}
+}
+
+// NewAPRSourceResponse converts the return of one source into its response representation
+func NewAPRSourceResponse(source service.APRSource) APRSourceResponse {
+	return APRSourceResponse{
+		Amount: source.Amount,
+		APR:    source.APR,
+	}
+}
+
+// NewAttestationResponse converts the attestation performance of an epoch into its response representation
+func NewAttestationResponse(perf *service.AttestationPerformance) AttestationResponse {
+	return AttestationResponse{
+		Epoch:          perf.Epoch,
+		Included:       perf.Included,
+		InclusionDelay: perf.InclusionDelay,
+		HeadCorrect:    perf.HeadCorrect,
+		TargetCorrect:  perf.TargetCorrect,
+		SourceCorrect:  perf.SourceCorrect,
+		Reward:         perf.Reward,
+	}
+}
+
+// NewRelayBidResponse converts a service bid into its response representation
+func NewRelayBidResponse(bid *service.RelayBid) *RelayBidResponse {
+	if bid == nil {
+		return nil
+	}
+	return &RelayBidResponse{
+		Relay:         bid.Relay,
+		BuilderPubkey: bid.BuilderPubkey,
+		Builder:       bid.Builder,
+		BlockHash:     bid.BlockHash,
+		Value:         bid.Value.String(),
+	}
+}
+
+// NewTransactionRewardResponse converts a service transaction reward into its response representation
+func NewTransactionRewardResponse(tx service.TransactionReward, unit string) TransactionRewardResponse {
+	return TransactionRewardResponse{
+		Hash:                 tx.Hash,
+		Index:                tx.Index,
+		Type:                 tx.Type,
+		GasUsed:              tx.GasUsed,
+		From:                 tx.From,
+		To:                   tx.To,
+		EffectivePriorityFee: FormatAmount(tx.EffectivePriorityFee, unit),
+		Contribution:         FormatAmount(tx.Contribution, unit),
+		Share:                tx.Share,
+	}
+}
+
+// NewBlockRewardResponse converts a service reward into its response representation
+func NewBlockRewardResponse(reward *service.BlockReward, unit string) BlockRewardResponse {
+	response := BlockRewardResponse{
+		Status:    reward.Status,
+		Reward:    FormatAmount(reward.Wei(), unit),
+		Unit:      unit,
+		Builder:   reward.Builder,
+		ExtraData: reward.ExtraData,
+
+		FeeRecipient:      reward.FeeRecipient,
+		FeeRecipientLabel: reward.FeeRecipientLabel,
+		ValuePercentile:   reward.ValuePercentile,
+		Finalized:         reward.Finalized,
+	}
+	// Deprecated int64 aliases, kept in GWEI whatever the unit while clients move to the string amounts
+	response.RewardGwei = gweiAmount(reward.Wei())
+	response.BlockInfo.ProposerPayment = response.Reward
+	response.BlockInfo.ProposerPaymentGwei = response.RewardGwei
+	response.BlockInfo.IsMEVBoost = reward.Status == "mev"
+	return response
+}
+
+// NewCheckpointResponse converts a service checkpoint into its response representation
+func NewCheckpointResponse(checkpoint service.Checkpoint) CheckpointResponse {
+	return CheckpointResponse{
+		Epoch: checkpoint.Epoch,
+		Root:  checkpoint.Root,
+	}
+}
+
+// NewGraffitiMatch converts an indexed block into a graffiti search result
+func NewGraffitiMatch(block *service.BlockDetail) GraffitiMatch {
+	return GraffitiMatch{
+		Slot:           block.Slot,
+		ProposerIndex:  block.ProposerIndex,
+		ProposerPubkey: block.ProposerPubkey,
+		Graffiti:       block.Graffiti,
+		BlockRoot:      block.BlockRoot,
+	}
+}
+
+// NewBlockRewardJobResponse converts a service job into its API response
+func NewBlockRewardJobResponse(job *service.BlockRewardJob, unit string) BlockRewardJobResponse {
+	total := int(job.ToSlot - job.FromSlot + 1)
+	response := BlockRewardJobResponse{
+		ID:        job.ID,
+		Status:    string(job.Status),
+		FromSlot:  job.FromSlot,
+		ToSlot:    job.ToSlot,
+		Processed: job.Processed,
+		Total:     total,
+		Progress:  float64(job.Processed) / float64(total),
+		CreatedAt: job.CreatedAt.Format(time.RFC3339),
+		Error:     job.Error,
+		Results:   make([]BlockRewardJobResultResponse, 0, len(job.Results)),
+	}
+	if !job.FinishedAt.IsZero() {
+		response.FinishedAt = job.FinishedAt.Format(time.RFC3339)
+	}
+
+	for _, result := range job.Results {
+		entry := BlockRewardJobResultResponse{
+			Slot:   result.Slot,
+			Missed: result.Missed,
+			Error:  result.Error,
+		}
+		if result.Reward != nil {
+			reward := NewBlockRewardResponse(result.Reward, unit)
+			entry.Reward = &reward
+		}
+		response.Results = append(response.Results, entry)
+	}
+	return response
+}
+
+// NewShareResponses converts service share statistics into their response representation
+func NewShareResponses(shares []service.ShareStat) []ShareResponse {
+	responses := make([]ShareResponse, 0, len(shares))
+	for _, share := range shares {
+		responses = append(responses, ShareResponse{
+			Name:   share.Name,
+			Blocks: share.Blocks,
+			Share:  share.Share,
+		})
+	}
+	return responses
+}
+
+// NewValidatorProposalResponse converts a service proposal into its response representation, with the reward in unit
+func NewValidatorProposalResponse(proposal service.ValidatorProposal, unit string) ValidatorProposalResponse {
+	response := ValidatorProposalResponse{
+		Slot:      proposal.Slot,
+		Epoch:     proposal.Epoch,
+		Outcome:   proposal.Outcome,
+		BlockRoot: proposal.BlockRoot,
+	}
+	if reward := proposal.Reward; reward != nil {
+		response.Reward = FormatAmount(reward.Wei(), unit)
+		response.Unit = unit
+		response.RewardGwei = gweiAmount(reward.Wei())
+		response.Status = reward.Status
+		response.Builder = reward.Builder
+		response.Relay = reward.Relay
+	}
+	return response
+}
+
+// NewValidatorStatusResponse converts a service validator status into its response representation
+func NewValidatorStatusResponse(status *service.ValidatorStatus) ValidatorStatusResponse {
+	response := ValidatorStatusResponse{
+		Index:                      status.Index,
+		Pubkey:                     status.Pubkey,
+		Status:                     status.Status,
+		Balance:                    status.Balance,
+		EffectiveBalance:           status.EffectiveBalance,
+		Slashed:                    status.Slashed,
+		ActivationEligibilityEpoch: status.ActivationEligibilityEpoch,
+		ActivationEpoch:            status.ActivationEpoch,
+		ExitEpoch:                  status.ExitEpoch,
+		WithdrawableEpoch:          status.WithdrawableEpoch,
+		QueuePosition:              status.QueuePosition,
+		AsOf:                       status.StateID,
+	}
+	if status.Operator != nil {
+		response.Pool = status.Operator.Pool
+		response.Operator = status.Operator.Operator
+	}
+	return response
+}
+
+// NewValidatorBlockRewardResponse converts a service reward into its response representation
+func NewValidatorBlockRewardResponse(reward *service.ValidatorBlockReward, unit string) ValidatorBlockRewardResponse {
+	return ValidatorBlockRewardResponse{
+		Slot:                reward.Slot,
+		ValidatorIndex:      reward.ValidatorIndex,
+		BlockRewardResponse: NewBlockRewardResponse(reward.BlockReward, unit),
+	}
+}
+
+// NewValidatorSummaryResponse converts the summary of a validator into its response representation
+func NewValidatorSummaryResponse(validator service.ValidatorSummary) ValidatorSummaryResponse {
+	response := ValidatorSummaryResponse{
+		ID:           validator.ID,
+		Found:        validator.Status != nil,
+		Duties:       make([]DutyResponse, 0, len(validator.Duties)),
+		Attestations: NewEffectivenessComponentResponse(validator.Attestations),
+	}
+	if validator.Status != nil {
+		status := NewValidatorStatusResponse(validator.Status)
+		response.Status = &status
+	}
+	for _, duty := range validator.Duties {
+		response.Duties = append(response.Duties, DutyResponse{
+			Kind:      duty.Kind,
+			StartSlot: duty.StartSlot,
+			EndSlot:   duty.EndSlot,
+			Start:     duty.Start.UTC().Format(time.RFC3339),
+			End:       duty.End.UTC().Format(time.RFC3339),
+		})
+	}
+	return response
+}
+
+// NewVerificationResponse converts the cross-check of a block with the upstream providers into its response
+// representation
+func NewVerificationResponse(verification *service.Verification) VerificationResponse {
+	response := VerificationResponse{
+		Providers:   verification.Providers,
+		Divergent:   len(verification.Divergences) > 0,
+		Divergences: make([]DivergenceResponse, 0, len(verification.Divergences)),
+	}
+	for _, divergence := range verification.Divergences {
+		response.Divergences = append(response.Divergences, DivergenceResponse{
+			Provider: divergence.Provider,
+			Field:    divergence.Field,
+			Expected: divergence.Expected,
+			Actual:   divergence.Actual,
+		})
+	}
+	return response
+}
+
+// NewMetaResponse converts the provenance recorded for a request into its response representation
+func NewMetaResponse(summary service.ProvenanceSummary) MetaResponse {
+	meta := MetaResponse{
+		Providers:      summary.Providers,
+		Cache:          "hit",
+		DataAgeSeconds: summary.DataAge.Seconds(),
+		Slot:           summary.Slot,
+	}
+	switch {
+	case summary.Upstream > 0 && summary.CacheHits > 0:
+		meta.Cache = "partial"
+	case summary.Upstream > 0:
+		meta.Cache = "miss"
+	}
+	if summary.Slot != nil {
+		meta.Finalized = &summary.Finalized
+	}
+	return meta
+}
+
+// NewWatchlistResponse converts the watchlist of an account into its response representation
+func NewWatchlistResponse(watchlist service.Watchlist) WatchlistResponse {
+	rules := make([]AlertRuleResponse, 0, len(watchlist.Rules))
+	for _, rule := range watchlist.Rules {
+		rules = append(rules, AlertRuleResponse{Event: rule.Event, Validators: rule.Validators})
+	}
+	return WatchlistResponse{
+		Validators: watchlist.Validators,
+		Rules:      rules,
+		Channels:   watchlist.Channels,
+	}
+}
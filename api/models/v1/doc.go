@@ -0,0 +1,5 @@
+// Package v1 holds the request and response models of version 1 of the public JSON API. They are the contract with
+// API clients and are only built from service types through the converters of this package, so changes to the
+// internal structs, like rewards moving to big.Int, have to be mapped here explicitly instead of changing the JSON
+// silently. Breaking changes to these models belong in a new version package
+package v1
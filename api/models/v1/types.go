@@ -0,0 +1,963 @@
+package v1
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// BlockRewardResponse represents the response structure for block rewards
+type BlockRewardResponse struct {
+	Status            string   `json:"status" example:"mev" description:"mev or vanilla"`                  // Block type (MEV or vanilla)
+	Reward            string   `json:"reward" example:"123456.789" description:"reward in unit"`           // Total block reward in unit, as an exact decimal
+	Unit              string   `json:"unit" example:"gwei"`                                                // Unit of all amounts of the response: wei, gwei or eth
+	RewardGwei        int64    `json:"reward_gwei" example:"123456" extensions:"x-deprecated=true"`        // Deprecated: total block reward in whole GWEI, use reward
+	Builder           string   `json:"builder,omitempty" example:"Flashbots"`                              // Normalized builder name for MEV blocks
+	ExtraData         string   `json:"extra_data" example:"0x496c6c756d696e617465"`                        // Raw extraData of the execution payload
+	FeeRecipient      string   `json:"fee_recipient" example:"0x388c818ca8b9251b393131c08a736a67ccb19297"` // Address that received the priority fees
+	FeeRecipientLabel string   `json:"fee_recipient_label,omitempty" example:"Lido"`                       // Known entity or ENS name of the fee recipient
+	ValuePercentile   *float64 `json:"value_percentile,omitempty" example:"87.5"`                          // Percentile of the reward among the indexed blocks of the 1000 slots before it
+	Finalized         bool     `json:"finalized" example:"true"`                                           // Whether the slot is finalized, otherwise the reward may still change
+	BlockInfo         struct {
+		ProposerPayment     string `json:"proposer_payment" example:"123456.789"`                                 // Payment to block proposer in unit
+		ProposerPaymentGwei int64  `json:"proposer_payment_gwei" example:"123456" extensions:"x-deprecated=true"` // Deprecated: payment to block proposer in whole GWEI, use proposer_payment
+		IsMEVBoost          bool   `json:"is_mev_boost" example:"true"`                                           // Whether MEV-Boost was used
+	} `json:"block_info"`
+	Transactions []TransactionRewardResponse `json:"transactions,omitempty"` // Per-transaction breakdown when requested with include=transactions
+	Fiat         []FiatValueResponse         `json:"fiat,omitempty"`         // Value of the reward in the currencies requested with currency
+	Verification *VerificationResponse       `json:"verification,omitempty"` // Cross-check of the block with the upstream providers while verification is enabled
+}
+
+// FiatValueResponse represents the value of a reward in a fiat currency at the time of its block
+type FiatValueResponse struct {
+	Currency  string  `json:"currency" example:"usd"`                    // Lower-cased currency code
+	Price     float64 `json:"price" example:"3456.78"`                   // Price of one ETH in the currency
+	PriceTime string  `json:"price_time" example:"2024-01-01T12:00:00Z"` // Time of the price closest to the block
+	Value     float64 `json:"value" example:"426.81"`                    // Value of the reward in the currency
+}
+
+// TransactionRewardResponse represents the contribution of a single transaction to the proposer reward
+type TransactionRewardResponse struct {
+	Hash                 string  `json:"hash" example:"0x5c50..."`                                            // Transaction hash
+	Index                int64   `json:"index" example:"0"`                                                   // Position of the transaction in the block
+	Type                 int64   `json:"type" example:"2"`                                                    // EIP-2718 transaction type
+	GasUsed              int64   `json:"gas_used" example:"21000"`                                            // Gas used by the transaction
+	From                 string  `json:"from,omitempty" example:"0xae2fc483527b8ef99eb5d9b44875f005ba1fae13"` // Sender of the transaction
+	To                   string  `json:"to,omitempty" example:"0x3fc91a3afd70395cd496c647d5a6cc9d4b2b7fad"`   // Recipient of the transaction, absent for contract creations
+	EffectivePriorityFee string  `json:"effective_priority_fee" example:"1.5"`                                // Priority fee per gas paid to the proposer in unit
+	Contribution         string  `json:"contribution" example:"31500"`                                        // Contribution to the proposer reward in unit
+	Share                float64 `json:"share" example:"0.12"`                                                // Fraction of the priority fees of the block
+}
+
+// TopTransactionsResponse represents the transactions contributing most to the proposer reward of a block
+type TopTransactionsResponse struct {
+	Slot         int64                       `json:"slot" example:"4700000"` // Beacon chain slot
+	Unit         string                      `json:"unit" example:"gwei"`    // Unit of all amounts: wei, gwei or eth
+	Transactions []TransactionRewardResponse `json:"transactions"`           // Transactions by descending contribution
+}
+
+// RelayBidResponse represents a builder bid as seen by a relay
+type RelayBidResponse struct {
+	Relay         string `json:"relay" example:"https://boost-relay.flashbots.net"` // Relay that received the bid
+	BuilderPubkey string `json:"builder_pubkey" example:"0xa1dead..."`              // Builder BLS pubkey
+	Builder       string `json:"builder,omitempty" example:"Flashbots"`             // Normalized builder name
+	BlockHash     string `json:"block_hash" example:"0x5e1f..."`                    // Execution block hash of the bid
+	Value         string `json:"value" example:"52064115720813510"`                 // Bid value paid to the proposer in Wei
+}
+
+// RelaySummaryResponse represents the bids a single relay received for a slot
+type RelaySummaryResponse struct {
+	Relay     string            `json:"relay" example:"https://boost-relay.flashbots.net"` // Relay URL
+	BidCount  int               `json:"bid_count" example:"412"`                           // Number of bids the relay received
+	TopBid    *RelayBidResponse `json:"top_bid,omitempty"`                                 // Highest bid the relay received
+	Delivered bool              `json:"delivered" example:"true"`                          // Whether this relay delivered the payload
+	Error     string            `json:"error,omitempty" example:""`                        // Set when the relay could not be queried
+}
+
+// BidComparisonResponse represents the comparison of the delivered payload with the best available bid
+type BidComparisonResponse struct {
+	Slot        int64                  `json:"slot" example:"4700000"`                  // Beacon chain slot
+	Delivered   *RelayBidResponse      `json:"delivered"`                               // Winning bid, null if no configured relay delivered
+	BestBid     *RelayBidResponse      `json:"best_bid"`                                // Highest bid across all relays
+	MissedValue string                 `json:"missed_value" example:"1200000000000000"` // Best bid minus delivered value in Wei
+	Relays      []RelaySummaryResponse `json:"relays"`                                  // Per-relay bid summary
+}
+
+// SyncDutiesResponse represents the response structure for sync committee duties
+type SyncDutiesResponse struct {
+	Validators       []string `json:"validators" example:"['0x1234...','0x5678...']"` // List of validator public keys in the sync committee
+	ValidatorIndices []int64  `json:"validator_indices" example:"123456,234567"`      // Validator indices, aligned with validators
+	Total            int      `json:"total" example:"512"`                            // Number of committee members matching the filter before pagination
+	Offset           int      `json:"offset" example:"0"`                             // Number of committee members skipped
+	SyncInfo         struct {
+		SyncPeriod      int64  `json:"sync_period" example:"123"`            // Current sync committee period number
+		CommitteeSize   int    `json:"committee_size" example:"512"`         // Size of the sync committee
+		StartSlot       int64  `json:"start_slot" example:"1007616"`         // First slot of the sync committee period
+		EndSlot         int64  `json:"end_slot" example:"1015807"`           // Last slot of the sync committee period
+		AggregatePubkey string `json:"aggregate_pubkey" example:"0xa9f1..."` // Aggregate pubkey of the committee, empty if unavailable
+	} `json:"sync_info"`
+}
+
+// ErrorResponse represents the standard error response structure
+type ErrorResponse struct {
+	Error   string `json:"error" example:"Internal server error"`                                   // Error message
+	Code    string `json:"code,omitempty" example:"upstream_rate_limited"`                          // Machine readable cause of upstream failures
+	Details string `json:"details,omitempty" example:"slot \"abc\" must be a non-negative integer"` // What exactly was wrong with the request, if known
+}
+
+// ProblemResponse represents an RFC 7807 problem details response
+type ProblemResponse struct {
+	Type      string `json:"type" example:"about:blank"`                               // URI identifying the problem type
+	Title     string `json:"title" example:"Internal Server Error"`                    // Short summary of the problem type
+	Status    int    `json:"status" example:"500"`                                     // HTTP status code
+	Detail    string `json:"detail" example:"The server failed to handle the request"` // Explanation of this occurrence
+	Instance  string `json:"instance" example:"/blockreward/4700000"`                  // Request path the problem occurred on
+	RequestID string `json:"request_id" example:"4f9c2a7e1b3d4c5a"`                    // ID of the request, also in the X-Request-ID header
+}
+
+// BlockDetailResponse represents the response structure for block details
+type BlockDetailResponse struct {
+	Slot           int64  `json:"slot" example:"4700000"`               // Beacon chain slot
+	ProposerIndex  int64  `json:"proposer_index" example:"123456"`      // Index of the block proposer
+	ProposerPubkey string `json:"proposer_pubkey" example:"0x8000..."`  // Public key of the block proposer
+	Graffiti       string `json:"graffiti" example:"Lighthouse/v4.5.0"` // Graffiti decoded to UTF-8
+	BlockRoot      string `json:"block_root" example:"0x4d61..."`       // Root of the beacon block
+	ParentRoot     string `json:"parent_root" example:"0x9a3c..."`      // Root of the parent beacon block
+	StateRoot      string `json:"state_root" example:"0x1f2e..."`       // Root of the post-block beacon state
+	ExecutionInfo  struct {
+		BlockNumber      int64                    `json:"block_number" example:"17034870"`   // Execution layer block number
+		BlockHash        string                   `json:"block_hash" example:"0x5e1f..."`    // Execution layer block hash
+		FeeRecipient     string                   `json:"fee_recipient" example:"0x3882..."` // Address receiving priority fees
+		GasUsed          int64                    `json:"gas_used" example:"15000000"`       // Gas used by the block
+		GasLimit         int64                    `json:"gas_limit" example:"30000000"`      // Gas limit of the block
+		TxCount          int                      `json:"tx_count" example:"150"`            // Number of transactions in the block
+		TransactionTypes TransactionTypesResponse `json:"transaction_types"`                 // Transactions of the block by type
+	} `json:"execution_info"`
+	SyncAggregate struct {
+		Participants  int     `json:"participants" example:"500"`     // Sync committee members that signed
+		CommitteeSize int     `json:"committee_size" example:"512"`   // Size of the sync committee
+		Participation float64 `json:"participation" example:"0.9765"` // Share of the committee that signed
+	} `json:"sync_aggregate"`
+	Verification *VerificationResponse `json:"verification,omitempty"` // Cross-check of the block with the upstream providers while verification is enabled
+	Verified     *bool                 `json:"verified,omitempty"`     // Whether the block root is proven by the light client, set while light client verification is enabled
+}
+
+// VerificationResponse represents the cross-check of a block with each configured upstream provider
+type VerificationResponse struct {
+	Providers   int                  `json:"providers" example:"2"`     // Providers that answered the cross-check
+	Divergent   bool                 `json:"divergent" example:"false"` // Whether any provider disagreed with the served block
+	Divergences []DivergenceResponse `json:"divergences"`
+}
+
+// DivergenceResponse represents a value a provider disagreed on with the served block
+type DivergenceResponse struct {
+	Provider string `json:"provider" example:"secondary"` // primary or secondary
+	Field    string `json:"field" example:"block_root"`   // block_root or execution_block_hash
+	Expected string `json:"expected" example:"0x4d61..."` // Value of the served block
+	Actual   string `json:"actual" example:"0x7b02..."`   // Value reported by the provider, empty when it doesn't know the block
+}
+
+// TransactionTypesResponse represents the number of transactions of a block by type
+type TransactionTypesResponse struct {
+	Legacy           int `json:"legacy" example:"20"`           // Legacy transactions
+	AccessList       int `json:"access_list" example:"1"`       // EIP-2930 access list transactions
+	EIP1559          int `json:"eip1559" example:"120"`         // EIP-1559 dynamic fee transactions
+	Blob             int `json:"blob" example:"6"`              // EIP-4844 blob transactions
+	SetCode          int `json:"set_code" example:"3"`          // EIP-7702 set code transactions
+	ContractCreation int `json:"contract_creation" example:"2"` // Transactions deploying a contract, counted in addition to their type
+	Unknown          int `json:"unknown" example:"0"`           // Transactions that could not be decoded
+}
+
+// BlockGasResponse represents the gas usage and base fee of a block
+type BlockGasResponse struct {
+	Slot        int64   `json:"slot" example:"4700000"`          // Beacon chain slot
+	BlockNumber int64   `json:"block_number" example:"17034870"` // Execution layer block number
+	GasUsed     int64   `json:"gas_used" example:"15000000"`     // Gas used by the block
+	GasLimit    int64   `json:"gas_limit" example:"30000000"`    // Gas limit of the block
+	Utilization float64 `json:"utilization" example:"50"`        // Percentage of the gas limit used
+	BaseFee     string  `json:"base_fee" example:"12.5"`         // Base fee per gas in unit, "" before the merge
+	Unit        string  `json:"unit" example:"gwei"`             // Unit of the base fee: wei, gwei or eth
+}
+
+// ExecutionBlockRewardResponse represents the block reward of an execution block together with its slot
+type ExecutionBlockRewardResponse struct {
+	Slot        int64  `json:"slot" example:"4700000"`          // Slot whose beacon block carried the execution block
+	BlockNumber int64  `json:"block_number" example:"17034870"` // Execution layer block number
+	BlockHash   string `json:"block_hash" example:"0x5e1f..."`  // Execution layer block hash
+	BlockRewardResponse
+}
+
+// BlockNumberConversionResponse maps a slot to the execution block its beacon block carried
+type BlockNumberConversionResponse struct {
+	Slot        int64 `json:"slot" example:"4700000"`
+	BlockNumber int64 `json:"block_number" example:"17034870"` // Execution layer block number
+}
+
+// ValidatorBlockRewardResponse represents the block reward of a slot proposed by a specific validator
+type ValidatorBlockRewardResponse struct {
+	Slot           int64 `json:"slot" example:"4700000"`           // Slot the validator proposed
+	ValidatorIndex int64 `json:"validator_index" example:"123456"` // Index of the proposing validator
+	BlockRewardResponse
+}
+
+// ValidatorProposalsResponse represents the proposal history of a validator
+type ValidatorProposalsResponse struct {
+	ValidatorIndex int64                       `json:"validator_index" example:"123456"`        // Index of the validator
+	FromEpoch      int64                       `json:"from_epoch" example:"146775"`             // First epoch of the range
+	ToEpoch        int64                       `json:"to_epoch" example:"146874"`               // Last epoch of the range
+	Proposed       int                         `json:"proposed" example:"2"`                    // Proposals that made it on chain
+	Missed         int                         `json:"missed" example:"0"`                      // Proposals without a block
+	Orphaned       int                         `json:"orphaned" example:"1"`                    // Proposals whose block was reorged out
+	Proposals      []ValidatorProposalResponse `json:"proposals"`                               // Assigned proposals, oldest first
+	NextCursor     string                      `json:"next_cursor,omitempty" example:"4700031"` // Cursor of the next page, only with cursor pagination
+	HasMore        *bool                       `json:"has_more,omitempty" example:"true"`       // Whether later slots are known yet, only with cursor pagination
+}
+
+// ValidatorProposalResponse represents a proposal assigned to a validator and its outcome
+type ValidatorProposalResponse struct {
+	Slot      int64  `json:"slot" example:"4700000"`                   // Slot of the proposal
+	Epoch     int64  `json:"epoch" example:"146875"`                   // Epoch of the proposal
+	Outcome   string `json:"outcome" example:"proposed"`               // proposed, missed or orphaned
+	BlockRoot string `json:"block_root,omitempty" example:"0x9a3c..."` // Root of the canonical or orphaned block
+	Reward    string `json:"reward,omitempty" example:"123456.789"`    // Block reward in the unit, only for proposed blocks
+	Unit      string `json:"unit,omitempty" example:"gwei"`            // Unit of the reward: wei, gwei or eth
+	Status    string `json:"status,omitempty" example:"mev"`           // mev or vanilla, only for proposed blocks
+	Builder   string `json:"builder,omitempty" example:"Flashbots"`    // Normalized builder name of MEV blocks
+	Relay     string `json:"relay,omitempty" example:"flashbots"`      // Relay that delivered the payload of MEV blocks
+
+	RewardGwei int64 `json:"reward_gwei,omitempty" example:"123456"` // Deprecated: integer reward in GWEI, read reward instead
+}
+
+// FeeRecipientCheckResponse represents the outcome of checking a validator's proposals against an expected fee recipient
+type FeeRecipientCheckResponse struct {
+	ValidatorIndex int64                          `json:"validator_index" example:"123456"`                              // Index of the validator
+	Expected       string                         `json:"expected" example:"0x388c818ca8b9251b393131c08a736a67ccb19297"` // Expected fee recipient, lowercase
+	FromEpoch      int64                          `json:"from_epoch" example:"146775"`                                   // First epoch of the range
+	ToEpoch        int64                          `json:"to_epoch" example:"146874"`                                     // Last epoch of the range
+	Compliant      bool                           `json:"compliant" example:"true"`                                      // No proposal paid another fee recipient
+	Matches        int                            `json:"matches" example:"2"`                                           // Proposals paying the expected fee recipient
+	Mismatches     int                            `json:"mismatches" example:"0"`                                        // Proposals paying another fee recipient
+	Unknown        int                            `json:"unknown" example:"1"`                                           // MEV proposals without relay data to check
+	Proposals      []FeeRecipientProposalResponse `json:"proposals"`                                                     // Proposed blocks, oldest first
+}
+
+// FeeRecipientProposalResponse represents the fee recipient used by a proposal
+type FeeRecipientProposalResponse struct {
+	Slot         int64  `json:"slot" example:"4700000"`                                                       // Slot of the proposal
+	Status       string `json:"status" example:"mev"`                                                         // mev or vanilla
+	FeeRecipient string `json:"fee_recipient,omitempty" example:"0x388c818ca8b9251b393131c08a736a67ccb19297"` // Fee recipient paid, absent if unknown
+	Source       string `json:"source" example:"relay"`                                                       // payload for vanilla blocks, relay for MEV blocks
+	Result       string `json:"result" example:"match"`                                                       // match, mismatch or unknown
+}
+
+// ProposerMismatchResponse represents the error returned when a slot was proposed by another validator
+type ProposerMismatchResponse struct {
+	Error         string `json:"error" example:"Validator did not propose this slot"` // Error message
+	Slot          int64  `json:"slot" example:"4700000"`                              // Requested slot
+	ProposerIndex int64  `json:"proposer_index" example:"654321"`                     // Validator that actually proposed the slot
+}
+
+// GraffitiMatch represents an indexed block whose graffiti matched a search
+type GraffitiMatch struct {
+	Slot           int64  `json:"slot" example:"4700000"`               // Beacon chain slot
+	ProposerIndex  int64  `json:"proposer_index" example:"123456"`      // Index of the block proposer
+	ProposerPubkey string `json:"proposer_pubkey" example:"0x8000..."`  // Public key of the block proposer
+	Graffiti       string `json:"graffiti" example:"Lighthouse/v4.5.0"` // Graffiti decoded to UTF-8
+	BlockRoot      string `json:"block_root" example:"0x4d61..."`       // Root of the beacon block
+}
+
+// GraffitiSearchResponse represents the response structure for graffiti searches
+type GraffitiSearchResponse struct {
+	Query   string          `json:"query" example:"Lighthouse"` // Search query
+	Count   int             `json:"count" example:"1"`          // Number of matches returned
+	Results []GraffitiMatch `json:"results"`                    // Matching blocks ordered by slot
+}
+
+// ValidatorStatusResponse represents the response structure for validator status lookups
+type ValidatorStatusResponse struct {
+	Index                      int64  `json:"index" example:"123456"`                            // Validator index
+	Pubkey                     string `json:"pubkey" example:"0x8000..."`                        // Validator public key
+	Status                     string `json:"status" example:"active_ongoing"`                   // Validator status as reported by the beacon node
+	Balance                    int64  `json:"balance" example:"32001234567"`                     // Current balance in GWEI
+	EffectiveBalance           int64  `json:"effective_balance" example:"32000000000"`           // Effective balance in GWEI
+	Slashed                    bool   `json:"slashed" example:"false"`                           // Whether the validator has been slashed
+	ActivationEligibilityEpoch string `json:"activation_eligibility_epoch" example:"1000"`       // Epoch the validator became eligible for activation
+	ActivationEpoch            string `json:"activation_epoch" example:"1005"`                   // Epoch the validator was activated
+	ExitEpoch                  string `json:"exit_epoch" example:"18446744073709551615"`         // Epoch the validator exits (far future if not exiting)
+	WithdrawableEpoch          string `json:"withdrawable_epoch" example:"18446744073709551615"` // Epoch the validator becomes withdrawable
+	QueuePosition              int    `json:"queue_position,omitempty" example:"42"`             // Position in the activation queue when pending
+	AsOf                       string `json:"as_of" example:"head"`                              // State the status was evaluated at ("head" or a slot)
+	Pool                       string `json:"pool,omitempty" example:"Lido"`                     // Staking pool running the validator, omitted if untagged
+	Operator                   string `json:"operator,omitempty" example:"Staking Facilities"`   // Node operator within the pool, omitted if unknown
+}
+
+// ShareResponse represents the number of blocks attributed to an entity and its share
+type ShareResponse struct {
+	Name   string  `json:"name" example:"Titan"` // Builder or relay name
+	Blocks int     `json:"blocks" example:"120"` // Number of MEV blocks
+	Share  float64 `json:"share" example:"0.42"` // Fraction of MEV blocks
+}
+
+// MEVStatsResponse represents the response structure for network-wide MEV statistics
+type MEVStatsResponse struct {
+	FromEpoch      int64           `json:"from_epoch" example:"146875"`                   // First epoch of the range
+	ToEpoch        int64           `json:"to_epoch" example:"147100"`                     // Last epoch of the range
+	Blocks         int             `json:"blocks" example:"7000"`                         // Number of indexed blocks in the range
+	MEVBlocks      int             `json:"mev_blocks" example:"6300"`                     // Number of MEV-Boost blocks
+	VanillaBlocks  int             `json:"vanilla_blocks" example:"700"`                  // Number of locally built blocks
+	MEVShare       float64         `json:"mev_share" example:"0.9"`                       // Fraction of blocks built via MEV-Boost
+	Unit           string          `json:"unit" example:"gwei"`                           // Unit of all rewards: wei, gwei or eth
+	AverageReward  string          `json:"average_reward" example:"51234567.89"`          // Average proposer reward
+	MedianReward   string          `json:"median_reward" example:"32123456.7"`            // Median proposer reward
+	AverageMEV     string          `json:"average_mev_reward" example:"55123456.123"`     // Average reward of MEV blocks
+	AverageVanilla string          `json:"average_vanilla_reward" example:"21234567.001"` // Average reward of vanilla blocks
+	Builders       []ShareResponse `json:"builders"`                                      // MEV block share per builder
+	Relays         []ShareResponse `json:"relays"`                                        // MEV block share per relay
+	Pools          []PoolResponse  `json:"pools"`                                         // Block share and rewards per staking pool of the proposer
+}
+
+// PoolResponse represents the blocks proposed by validators of a staking pool
+type PoolResponse struct {
+	Name          string  `json:"name" example:"Lido"`                 // Staking pool, "other" for untagged proposers or "unknown"
+	Blocks        int     `json:"blocks" example:"2100"`               // Number of blocks proposed by the pool
+	Share         float64 `json:"share" example:"0.3"`                 // Fraction of all blocks in the range
+	MEVBlocks     int     `json:"mev_blocks" example:"2050"`           // Number of the pool's blocks built via MEV-Boost
+	MEVShare      float64 `json:"mev_share" example:"0.976"`           // Fraction of the pool's blocks built via MEV-Boost
+	AverageReward string  `json:"average_reward" example:"52345678.9"` // Average proposer reward of the pool's blocks, in the unit of the statistics
+}
+
+// RewardStatsResponse represents the response structure for proposer reward statistics
+type RewardStatsResponse struct {
+	Window   string  `json:"window" example:"1d"`          // Requested window
+	FromSlot int64   `json:"from_slot" example:"4692801"`  // First slot of the window
+	ToSlot   int64   `json:"to_slot" example:"4700000"`    // Last slot of the window
+	Blocks   int     `json:"blocks" example:"7000"`        // Number of indexed blocks in the window
+	Unit     string  `json:"unit" example:"gwei"`          // Unit of all rewards: wei, gwei or eth
+	Min      string  `json:"min" example:"1000.5"`         // Minimum proposer reward
+	Median   string  `json:"median" example:"32123456.7"`  // Median proposer reward
+	P90      string  `json:"p90" example:"98123456.12"`    // 90th percentile proposer reward
+	P99      string  `json:"p99" example:"512123456.3"`    // 99th percentile proposer reward
+	Max      string  `json:"max" example:"2012123456.789"` // Maximum proposer reward
+	MEVRatio float64 `json:"mev_ratio" example:"0.9"`      // Fraction of blocks built via MEV-Boost
+}
+
+// BaseFeeStatsResponse represents the base fee trend across a window of indexed blocks
+type BaseFeeStatsResponse struct {
+	Window      string                 `json:"window" example:"1d"`         // Requested window
+	FromSlot    int64                  `json:"from_slot" example:"4692801"` // First slot of the window
+	ToSlot      int64                  `json:"to_slot" example:"4700000"`   // Last slot of the window
+	Blocks      int                    `json:"blocks" example:"7000"`       // Number of indexed blocks with a base fee in the window
+	BucketSlots int64                  `json:"bucket_slots" example:"10"`   // Number of slots averaged into each point
+	Unit        string                 `json:"unit" example:"gwei"`         // Unit of all base fees: wei, gwei or eth
+	Min         string                 `json:"min" example:"8.1"`           // Minimum base fee per gas
+	Median      string                 `json:"median" example:"12.5"`       // Median base fee per gas
+	Max         string                 `json:"max" example:"45.25"`         // Maximum base fee per gas
+	Points      []BaseFeePointResponse `json:"points"`                      // Series of base fees, oldest first
+}
+
+// BaseFeePointResponse represents the base fee of the indexed blocks within a bucket of consecutive slots
+type BaseFeePointResponse struct {
+	Slot        int64   `json:"slot" example:"4692801"`         // First slot of the bucket
+	Timestamp   int64   `json:"timestamp" example:"1682000000"` // Unix time of the first block of the bucket
+	Blocks      int     `json:"blocks" example:"10"`            // Number of indexed blocks in the bucket
+	BaseFee     string  `json:"base_fee" example:"12.5"`        // Average base fee per gas
+	Min         string  `json:"min" example:"11.9"`             // Minimum base fee per gas
+	Max         string  `json:"max" example:"13.2"`             // Maximum base fee per gas
+	Utilization float64 `json:"utilization" example:"51.3"`     // Average percentage of the gas limit used
+}
+
+// CheckpointResponse represents a finality checkpoint
+type CheckpointResponse struct {
+	Epoch int64  `json:"epoch" example:"146874"`   // Checkpoint epoch
+	Root  string `json:"root" example:"0x4d61..."` // Checkpoint block root
+}
+
+// ChainHeadResponse represents the response structure for the chain head
+type ChainHeadResponse struct {
+	HeadSlot          int64              `json:"head_slot" example:"4700000"`   // Slot of the current head
+	HeadRoot          string             `json:"head_root" example:"0x9a3c..."` // Block root of the current head
+	Epoch             int64              `json:"epoch" example:"146875"`        // Current epoch
+	SyncPeriod        int64              `json:"sync_period" example:"573"`     // Current sync committee period
+	PreviousJustified CheckpointResponse `json:"previous_justified"`            // Previous justified checkpoint
+	CurrentJustified  CheckpointResponse `json:"current_justified"`             // Current justified checkpoint
+	Finalized         CheckpointResponse `json:"finalized"`                     // Finalized checkpoint
+}
+
+// ChainSpecResponse represents the response structure for the beacon node's spec configuration
+type ChainSpecResponse struct {
+	Data map[string]interface{} `json:"data"` // Spec parameters as reported by the beacon node, e.g. SECONDS_PER_SLOT
+}
+
+// ForkResponse represents an entry of the fork schedule
+type ForkResponse struct {
+	PreviousVersion string `json:"previous_version" example:"0x03000000"` // Fork version before the fork
+	CurrentVersion  string `json:"current_version" example:"0x04000000"`  // Fork version after the fork
+	Epoch           string `json:"epoch" example:"269568"`                // Epoch the fork activates at
+}
+
+// ForkScheduleResponse represents the response structure for the fork schedule
+type ForkScheduleResponse struct {
+	Forks []ForkResponse `json:"forks"` // Forks ordered by activation epoch
+}
+
+// NodeSyncingResponse represents the sync status of the beacon and execution nodes
+type NodeSyncingResponse struct {
+	Beacon    BeaconSyncingResponse    `json:"beacon"`
+	Execution ExecutionSyncingResponse `json:"execution"`
+}
+
+// BeaconSyncingResponse represents the sync status of the beacon node
+type BeaconSyncingResponse struct {
+	HeadSlot     int64 `json:"head_slot" example:"4700000"`   // Slot of the node's head
+	SyncDistance int64 `json:"sync_distance" example:"0"`     // Slots the head is behind the current slot
+	IsSyncing    bool  `json:"is_syncing" example:"false"`    // Whether the node is syncing
+	IsOptimistic bool  `json:"is_optimistic" example:"false"` // Whether the head's payload wasn't validated by the execution node yet
+	ELOffline    bool  `json:"el_offline" example:"false"`    // Whether the beacon node can't reach its execution node
+}
+
+// ExecutionSyncingResponse represents the sync status of the execution node
+type ExecutionSyncingResponse struct {
+	IsSyncing    bool  `json:"is_syncing" example:"false"`       // Whether the node is syncing
+	CurrentBlock int64 `json:"current_block" example:"19500000"` // Latest block, the block being synced while syncing
+	HighestBlock int64 `json:"highest_block" example:"19500000"` // Highest known block
+}
+
+// NodeVersionResponse represents the client versions of the beacon and execution nodes
+type NodeVersionResponse struct {
+	Beacon    string `json:"beacon" example:"Lighthouse/v5.3.0-d6ba8c3/x86_64-linux"`       // Version reported by /eth/v1/node/version
+	Execution string `json:"execution" example:"Geth/v1.14.11-stable/linux-amd64/go1.23.2"` // Version reported by web3_clientVersion
+}
+
+// NodePeersResponse represents the peers of the beacon and execution nodes
+type NodePeersResponse struct {
+	Beacon    BeaconPeersResponse    `json:"beacon"`
+	Execution ExecutionPeersResponse `json:"execution"`
+}
+
+// BeaconPeersResponse represents the peer counts of the beacon node by connection state
+type BeaconPeersResponse struct {
+	Connected     int64 `json:"connected" example:"80"`
+	Connecting    int64 `json:"connecting" example:"2"`
+	Disconnected  int64 `json:"disconnected" example:"120"`
+	Disconnecting int64 `json:"disconnecting" example:"1"`
+}
+
+// ExecutionPeersResponse represents the peer count of the execution node
+type ExecutionPeersResponse struct {
+	Connected int64 `json:"connected" example:"50"` // Peers reported by net_peerCount
+}
+
+// SyncCommitteeMemberResponse represents a validator assigned to a sync committee
+type SyncCommitteeMemberResponse struct {
+	Index  int64  `json:"index" example:"123456"`     // Validator index
+	Pubkey string `json:"pubkey" example:"0x8000..."` // Validator public key
+}
+
+// NextSyncCommitteeResponse represents the response structure for the upcoming sync committee
+type NextSyncCommitteeResponse struct {
+	SyncPeriod int64                         `json:"sync_period" example:"1250"`    // Sync committee period the committee serves
+	StartSlot  int64                         `json:"start_slot" example:"10240000"` // First slot of the period
+	EndSlot    int64                         `json:"end_slot" example:"10248191"`   // Last slot of the period
+	Members    []SyncCommitteeMemberResponse `json:"members"`                       // Committee members in committee order
+}
+
+// SyncPeriodProbabilityResponse represents the chance of a validator being selected for the sync committee of a period
+type SyncPeriodProbabilityResponse struct {
+	SyncPeriod  int64   `json:"sync_period" example:"1251"`    // Sync committee period
+	StartSlot   int64   `json:"start_slot" example:"10248192"` // First slot of the period
+	EndSlot     int64   `json:"end_slot" example:"10256383"`   // Last slot of the period
+	Probability float64 `json:"probability" example:"0.00049"` // Chance of selection in the period
+	Known       bool    `json:"known" example:"false"`         // Whether the committee is already selected, the probability is then 0 or 1
+}
+
+// SyncProbabilityResponse represents the response structure for a validator's chances of upcoming sync committee selection
+type SyncProbabilityResponse struct {
+	ValidatorIndex     int64                           `json:"validator_index" example:"123456"`           // Validator index
+	Status             string                          `json:"status" example:"active_ongoing"`            // Validator status, validators that are not active are never selected
+	EffectiveBalance   int64                           `json:"effective_balance" example:"32000000000"`    // Effective balance in GWEI
+	ActiveValidators   int                             `json:"active_validators" example:"1050000"`        // Number of active validators
+	ActiveBalance      int64                           `json:"active_balance" example:"34000000000000000"` // Total effective balance of active validators in GWEI
+	PeriodProbability  float64                         `json:"period_probability" example:"0.00049"`       // Chance of selection in a period whose committee is not selected yet
+	Probability        float64                         `json:"probability" example:"0.0044"`               // Chance of selection in at least one of the periods
+	ExpectedSelections float64                         `json:"expected_selections" example:"0.0044"`       // Expected number of periods the validator serves in
+	Periods            []SyncPeriodProbabilityResponse `json:"periods"`                                    // Upcoming periods starting with the next one
+}
+
+// AttestationResponse represents the attestation performance of a validator for one epoch
+type AttestationResponse struct {
+	Epoch          int64 `json:"epoch" example:"146875"`        // Epoch of the attestation
+	Included       bool  `json:"included" example:"true"`       // Whether the attestation was included in time to be rewarded
+	InclusionDelay int64 `json:"inclusion_delay" example:"1"`   // Inclusion delay in slots, 1 when the head vote was rewarded, 0 if unknown or not included
+	HeadCorrect    bool  `json:"head_correct" example:"true"`   // Head vote was correct and included within 1 slot
+	TargetCorrect  bool  `json:"target_correct" example:"true"` // Target vote was correct and included within 32 slots
+	SourceCorrect  bool  `json:"source_correct" example:"true"` // Source vote was correct and included within 5 slots
+	Reward         int64 `json:"reward" example:"14523"`        // Head, target and source rewards in GWEI, negative when penalized
+}
+
+// AttestationPerformanceResponse represents the response structure for a validator's attestation performance
+type AttestationPerformanceResponse struct {
+	Epochs       int                   `json:"epochs" example:"10"`   // Number of epochs the validator was attesting in
+	Included     int                   `json:"included" example:"10"` // Number of included attestations
+	Attestations []AttestationResponse `json:"attestations"`          // Per-epoch performance, newest first
+}
+
+// EffectivenessComponentResponse represents one component of the effectiveness score
+type EffectivenessComponentResponse struct {
+	Duties    int     `json:"duties" example:"10"`    // Number of duties assigned within the window
+	Fulfilled int     `json:"fulfilled" example:"10"` // Number of duties performed
+	Rate      float64 `json:"rate" example:"0.97"`    // Effectiveness of the component between 0 and 1
+}
+
+// EffectivenessResponse represents the response structure for a validator's effectiveness score
+type EffectivenessResponse struct {
+	ValidatorIndex int64                          `json:"validator_index" example:"123456"` // Validator index
+	FromEpoch      int64                          `json:"from_epoch" example:"146866"`      // First epoch of the window
+	ToEpoch        int64                          `json:"to_epoch" example:"146875"`        // Last epoch of the window
+	Score          float64                        `json:"score" example:"97.5"`             // Weighted effectiveness between 0 and 100
+	Attestation    EffectivenessComponentResponse `json:"attestation"`                      // Share of correct head, target and source votes
+	Proposal       EffectivenessComponentResponse `json:"proposal"`                         // Share of assigned proposals that made it on chain
+	SyncCommittee  EffectivenessComponentResponse `json:"sync_committee"`                   // Share of indexed blocks the validator's sync signature was included in
+}
+
+// APRSourceResponse represents the rewards of one source and the annualized return they amount to
+type APRSourceResponse struct {
+	Amount int64   `json:"amount" example:"42000000"` // Rewards in GWEI
+	APR    float64 `json:"apr" example:"0.028"`       // Annualized return on the principal, 0.028 for 2.8%
+}
+
+// APRSourcesResponse represents the breakdown of a validator set's return per source
+type APRSourcesResponse struct {
+	Consensus     APRSourceResponse `json:"consensus"`      // Attestation rewards, annualized over the indexed attestation epochs
+	ExecutionTips APRSourceResponse `json:"execution_tips"` // Priority fees of proposed blocks built locally
+	MEV           APRSourceResponse `json:"mev"`            // Builder payments of proposed blocks built via MEV-Boost
+}
+
+// WithdrawalsResponse represents the withdrawals of a validator set within a window
+type WithdrawalsResponse struct {
+	Count  int   `json:"count" example:"4"`         // Number of withdrawals
+	Amount int64 `json:"amount" example:"68000000"` // Withdrawn amount in GWEI
+}
+
+// ValidatorAPRResponse represents the response structure for the annualized return of a validator set
+type ValidatorAPRResponse struct {
+	Validators        []int64                `json:"validators" example:"123456,123457"` // Indices of the validators
+	Window            string                 `json:"window" example:"30d"`               // Window the return is computed over
+	FromSlot          int64                  `json:"from_slot" example:"9784000"`        // First slot of the window
+	ToSlot            int64                  `json:"to_slot" example:"9999999"`          // Last slot of the window, the latest indexed slot
+	Principal         int64                  `json:"principal" example:"64000000000"`    // Stake in GWEI, 32 ETH per validator
+	APR               float64                `json:"apr" example:"0.034"`                // Annualized return of all sources
+	Rewards           int64                  `json:"rewards" example:"180000000"`        // Rewards of all sources in GWEI
+	Sources           APRSourcesResponse     `json:"sources"`                            // Return per source
+	Withdrawals       WithdrawalsResponse    `json:"withdrawals"`                        // Withdrawals, reported apart as they pay out consensus rewards
+	AttestationEpochs int64                  `json:"attestation_epochs" example:"450"`   // Validator epochs with indexed attestation rewards
+	BlockCoverage     float64                `json:"block_coverage" example:"0.98"`      // Share of the window's slots with an indexed block reward
+	VsNetwork         *APRComparisonResponse `json:"vs_network,omitempty"`               // Comparison with the network average, left out while network statistics are not available
+}
+
+// APRComparisonResponse represents the comparison of a validator set's return with the network average
+type APRComparisonResponse struct {
+	NetworkAPR float64 `json:"network_apr" example:"0.031"` // Network average annualized return over the same window
+	Difference float64 `json:"difference" example:"0.003"`  // Return of the validators minus the network average
+}
+
+// NetworkAPRSourcesResponse represents the breakdown of the network average return per source
+type NetworkAPRSourcesResponse struct {
+	Consensus     float64 `json:"consensus" example:"0.028"`       // Ideal attestation rewards scaled by the average participation
+	ExecutionTips float64 `json:"execution_tips" example:"0.0008"` // Priority fees of indexed locally built blocks on the active balance
+	MEV           float64 `json:"mev" example:"0.0022"`            // Builder payments of indexed MEV-Boost blocks on the active balance
+}
+
+// NetworkAPRResponse represents the response structure for the network average annualized return
+type NetworkAPRResponse struct {
+	Window                 string                    `json:"window" example:"30d"`                       // Window the return is computed over
+	FromSlot               int64                     `json:"from_slot" example:"9784000"`                // First slot of the window
+	ToSlot                 int64                     `json:"to_slot" example:"9999999"`                  // Last slot of the window, the latest indexed slot
+	Epoch                  int64                     `json:"epoch" example:"360000"`                     // Epoch the ideal attestation rewards are taken from
+	ActiveValidators       int                       `json:"active_validators" example:"1050000"`        // Number of active validators
+	ActiveBalance          int64                     `json:"active_balance" example:"34000000000000000"` // Total effective balance of active validators in GWEI
+	APR                    float64                   `json:"apr" example:"0.031"`                        // Average annualized return of all sources
+	Sources                NetworkAPRSourcesResponse `json:"sources"`                                    // Return per source
+	IdealAttestationReward int64                     `json:"ideal_attestation_reward" example:"14000"`   // Reward of a 32 ETH validator voting correctly in the epoch, in GWEI
+	Participation          float64                   `json:"participation" example:"0.99"`               // Average participation of the recent epochs
+	ExecutionRewards       int64                     `json:"execution_rewards" example:"40000000000000"` // Execution rewards of the indexed blocks within the window in GWEI
+	BlockCoverage          float64                   `json:"block_coverage" example:"0.98"`              // Share of the window's slots with an indexed block reward
+}
+
+// ForecastRewardsResponse represents the expected rewards of a forecast per source, in the unit of the forecast
+type ForecastRewardsResponse struct {
+	Attestation       string `json:"attestation" example:"22050000"`      // Attestation rewards when attesting correctly every epoch
+	SyncCommittee     string `json:"sync_committee" example:"800000"`     // Expected sync committee rewards
+	ProposerConsensus string `json:"proposer_consensus" example:"420000"` // Expected consensus rewards of proposed blocks
+	Execution         string `json:"execution" example:"1500000"`         // Expected priority fees and MEV of proposed blocks
+}
+
+// ForecastProposalsResponse represents the expected block proposals of a forecast
+type ForecastProposalsResponse struct {
+	Expected           float64 `json:"expected" example:"0.048"`                // Expected number of proposals within the period
+	Probability        float64 `json:"probability" example:"0.047"`             // Probability of proposing at least one block
+	AverageBlockReward string  `json:"average_block_reward" example:"45000000"` // Average execution reward of the indexed blocks of the last 30 days, in the unit of the forecast
+	MEVShare           float64 `json:"mev_share" example:"0.92"`                // Share of those blocks built via MEV-Boost
+}
+
+// ForecastSyncCommitteeResponse represents the sync committee chances of a forecast
+type ForecastSyncCommitteeResponse struct {
+	Probability float64 `json:"probability" example:"0.003"` // Probability of being selected for at least one sync committee period
+	Payout      string  `json:"payout" example:"120000000"`  // Rewards of a full sync committee period once selected, in the unit of the forecast
+}
+
+// ForecastNetworkResponse represents the network conditions a forecast is based on
+type ForecastNetworkResponse struct {
+	Epoch                  int64   `json:"epoch" example:"360000"`                     // Epoch the ideal attestation rewards are taken from
+	ActiveValidators       int     `json:"active_validators" example:"1050000"`        // Number of active validators
+	ActiveBalance          int64   `json:"active_balance" example:"34000000000000000"` // Total effective balance of active validators in GWEI
+	IdealAttestationReward int64   `json:"ideal_attestation_reward" example:"14000"`   // Reward of a 32 ETH validator voting correctly in the epoch in GWEI
+	Participation          float64 `json:"participation" example:"0.99"`               // Average participation of the recent epochs
+}
+
+// ForecastResponse represents the response structure for the expected rewards of a validator over a coming period
+type ForecastResponse struct {
+	ValidatorIndex   int64                         `json:"validator_index" example:"123456"`        // Validator index
+	Status           string                        `json:"status" example:"active_ongoing"`         // Validator status, validators that are not active are forecast no rewards
+	EffectiveBalance int64                         `json:"effective_balance" example:"32000000000"` // Effective balance in GWEI
+	Period           string                        `json:"period" example:"7d"`                     // Period the rewards are forecast for
+	Epochs           int64                         `json:"epochs" example:"1575"`                   // Length of the period in epochs
+	Unit             string                        `json:"unit" example:"gwei"`                     // Unit of the expected rewards: wei, gwei or eth
+	Total            string                        `json:"total" example:"24770000"`                // Expected rewards of all sources
+	APR              float64                       `json:"apr" example:"0.04"`                      // Expected rewards annualized on the effective balance
+	Rewards          ForecastRewardsResponse       `json:"rewards"`                                 // Expected rewards per source
+	Proposals        ForecastProposalsResponse     `json:"proposals"`                               // Expected block proposals
+	SyncCommittee    ForecastSyncCommitteeResponse `json:"sync_committee"`                          // Sync committee chances
+	Network          ForecastNetworkResponse       `json:"network"`                                 // Network conditions the forecast is based on
+}
+
+// QueueResponse represents the response structure for the validator entry and exit queues
+type QueueResponse struct {
+	Epoch            int64 `json:"epoch" example:"360000"`                     // Epoch of the head state
+	ActiveValidators int   `json:"active_validators" example:"1050000"`        // Number of active validators
+	ActiveBalance    int64 `json:"active_balance" example:"34000000000000000"` // Total effective balance of active validators in GWEI
+	ChurnLimit       int64 `json:"churn_limit" example:"256000000000"`         // Balance that can enter or exit per epoch in GWEI
+	Entry            struct {
+		PendingDeposits int   `json:"pending_deposits" example:"1200"`          // Deposits waiting to be processed
+		PendingBalance  int64 `json:"pending_balance" example:"38400000000000"` // Total pending deposit amount in GWEI
+		WaitEpochs      int64 `json:"wait_epochs" example:"150"`                // Estimated epochs until a new deposit is processed
+		WaitSeconds     int64 `json:"wait_seconds" example:"57600"`             // Estimated wait for a new deposit in seconds
+	} `json:"entry"`
+	Exit struct {
+		Validators  int   `json:"validators" example:"300"`        // Validators waiting to exit
+		Balance     int64 `json:"balance" example:"9600000000000"` // Total effective balance waiting to exit in GWEI
+		WaitEpochs  int64 `json:"wait_epochs" example:"38"`        // Estimated epochs until a new exit takes effect
+		WaitSeconds int64 `json:"wait_seconds" example:"14592"`    // Estimated wait for a new exit in seconds
+	} `json:"exit"`
+}
+
+// EpochParticipationResponse represents the attestation participation of an epoch
+type EpochParticipationResponse struct {
+	Epoch int64   `json:"epoch" example:"360000"` // Epoch
+	Rate  float64 `json:"rate" example:"0.985"`   // Share of active validators whose target vote was rewarded
+}
+
+// NetworkStatsResponse represents the response structure for network-wide validator statistics
+type NetworkStatsResponse struct {
+	Epoch                 int64                        `json:"epoch" example:"360002"`                              // Head epoch at the last refresh
+	ActiveValidators      int                          `json:"active_validators" example:"1050000"`                 // Number of active validators
+	TotalEffectiveBalance int64                        `json:"total_effective_balance" example:"34000000000000000"` // Total effective balance of active validators in GWEI
+	Participation         []EpochParticipationResponse `json:"participation"`                                       // Recent participation, oldest epoch first
+	UpdatedAt             string                       `json:"updated_at" example:"2024-01-01T00:00:00Z"`           // Time of the last refresh
+}
+
+// CommitteeResponse represents a beacon attestation committee
+type CommitteeResponse struct {
+	Index      int64   `json:"index" example:"0"`                  // Committee index within the slot
+	Validators []int64 `json:"validators" example:"123456,234567"` // Validator indices in committee order
+}
+
+// CommitteesResponse represents the response structure for a page of committees of a slot
+type CommitteesResponse struct {
+	Slot       int64               `json:"slot" example:"4700000"` // Beacon chain slot
+	Total      int                 `json:"total" example:"64"`     // Total number of committees in the slot
+	Offset     int                 `json:"offset" example:"0"`     // Number of committees skipped
+	Committees []CommitteeResponse `json:"committees"`             // Committees of this page ordered by index
+}
+
+// CacheStatsResponse represents the response structure for response cache statistics
+type CacheStatsResponse struct {
+	Entries     int     `json:"entries" example:"1200"`        // Number of cached entries
+	Expired     int     `json:"expired" example:"40"`          // Entries past their TTL, still kept to be served stale until they are swept
+	Evictions   int64   `json:"evictions" example:"30"`        // Entries evicted to keep the cache within its entry and memory limits
+	Hits        int64   `json:"hits" example:"9500"`           // Lookups served from the cache
+	Misses      int64   `json:"misses" example:"500"`          // Lookups that went upstream
+	HitRatio    float64 `json:"hit_ratio" example:"0.95"`      // Share of lookups served from the cache
+	MemoryBytes int64   `json:"memory_bytes" example:"524288"` // Approximate memory held by the cached values
+}
+
+// UpstreamBudgetResponse represents the upstream usage of a provider host during the current UTC day
+type UpstreamBudgetResponse struct {
+	Host      string `json:"host" example:"example.quiknode.pro"`  // Provider host, providers sharing a host share its quota
+	Day       string `json:"day" example:"2024-01-15"`             // UTC day the usage is counted for
+	Calls     int64  `json:"calls" example:"120000"`               // Requests sent to the provider
+	Credits   int64  `json:"credits" example:"2400000"`            // Compute units of the requests
+	Quota     int64  `json:"quota,omitempty" example:"3000000"`    // Credits per day, omitted if unlimited
+	Remaining *int64 `json:"remaining,omitempty" example:"600000"` // Credits left until UTC midnight, omitted if unlimited
+	Low       bool   `json:"low" example:"false"`                  // Whether the quota is nearly used up, batch requests are then shed and stale cached data served
+}
+
+// UpstreamBudgetsResponse represents the daily usage of the upstream providers
+type UpstreamBudgetsResponse struct {
+	Upstreams []UpstreamBudgetResponse `json:"upstreams"` // Provider hosts ordered by host
+}
+
+// CacheInvalidationResponse represents the response structure for a cache invalidation
+type CacheInvalidationResponse struct {
+	Removed int `json:"removed" example:"3"` // Number of cached entries removed
+}
+
+// ReloadResponse represents the outcome of a configuration reload
+type ReloadResponse struct {
+	Applied []string `json:"applied" example:"upstreams,cors,builders"` // Settings applied: rate_limit and upstreams when they changed, cors and builders
+}
+
+// UsageResponse represents the request counts matching the filters of a usage query
+type UsageResponse struct {
+	Requests int64                 `json:"requests" example:"1520"` // Requests of all matching records
+	Errors   int64                 `json:"errors" example:"12"`     // Requests answered with a 4xx or 5xx status
+	Records  []UsageRecordResponse `json:"records"`                 // Matching records ordered by day, client and endpoint
+}
+
+// UsageRecordResponse represents the requests of a client to an endpoint on a day, fields summed over by group_by are omitted
+type UsageRecordResponse struct {
+	Day      string `json:"day,omitempty" example:"2024-01-01"`              // UTC day
+	Client   string `json:"client,omitempty" example:"key:9f86d081884c7d65"` // Subject of the JWT prefixed with sub:, hash of the API key prefixed with key:, or the client IP prefixed with ip:
+	Endpoint string `json:"endpoint,omitempty" example:"GET /validator/:id"` // Method and route
+	Requests int64  `json:"requests" example:"120"`
+	Errors   int64  `json:"errors" example:"2"`
+}
+
+// AuditEntryResponse represents an admin action recorded in the audit log
+type AuditEntryResponse struct {
+	Time   string          `json:"time" example:"2024-01-01T00:00:00Z"`   // Time of the action
+	Actor  string          `json:"actor" example:"key:9f86d081884c7d65"`  // Client that performed the action, or signal:SIGHUP
+	Action string          `json:"action" example:"builder.put"`          // builder.put, builder.delete, cache.delete, config.reload, capture.put, account.create or account.delete
+	Target string          `json:"target,omitempty" example:"Titan"`      // Builder name, slot or account ID the action applied to
+	Before json.RawMessage `json:"before,omitempty" swaggertype:"object"` // Value before the action
+	After  json.RawMessage `json:"after,omitempty" swaggertype:"object"`  // Value after the action
+}
+
+// UpstreamCaptureRequest represents the request structure to turn the upstream capture on or off
+type UpstreamCaptureRequest struct {
+	Enabled *bool `json:"enabled" binding:"required" example:"true"` // Whether upstream exchanges are captured
+	Clear   bool  `json:"clear" example:"false"`                     // Drop the exchanges captured so far
+}
+
+// UpstreamCaptureResponse represents the upstream exchanges captured for debugging
+type UpstreamCaptureResponse struct {
+	Enabled   bool                       `json:"enabled" example:"true"` // Whether upstream exchanges are captured
+	Size      int                        `json:"size" example:"100"`     // Number of exchanges kept, older ones are dropped
+	Exchanges []UpstreamExchangeResponse `json:"exchanges"`              // Captured exchanges, newest first
+}
+
+// UpstreamExchangeResponse represents an upstream request with its response, provider API keys are redacted
+type UpstreamExchangeResponse struct {
+	Time         string `json:"time" example:"2024-01-01T00:00:00Z"`                                              // Time the request was sent
+	Method       string `json:"method" example:"GET"`                                                             // HTTP method
+	URL          string `json:"url" example:"https://example.quiknode.pro/[REDACTED]/eth/v1/beacon/headers/head"` // Provider URL with API keys redacted
+	Status       int    `json:"status,omitempty" example:"200"`                                                   // HTTP status, omitted if the request failed
+	Error        string `json:"error,omitempty" example:""`                                                       // Why the request failed
+	DurationMs   int64  `json:"duration_ms" example:"120"`                                                        // Time until the response was read
+	RequestBody  string `json:"request_body,omitempty" example:""`                                                // Start of the request body
+	ResponseBody string `json:"response_body,omitempty" example:"{\"data\":{}}"`                                  // Start of the response body
+	Truncated    bool   `json:"truncated" example:"false"`                                                        // Whether a body was cut off after 4 KiB
+}
+
+// UpstreamsHealthResponse represents the state of the upstream providers and the client of the beacon node
+type UpstreamsHealthResponse struct {
+	Available bool               `json:"available" example:"true"` // Whether the upstream providers were reached
+	Beacon    BeaconNodeResponse `json:"beacon"`
+}
+
+// BeaconNodeResponse represents the detected client of the beacon node
+type BeaconNodeResponse struct {
+	Version string   `json:"version,omitempty" example:"Lighthouse/v5.3.0-d6ba8c3/x86_64-linux"` // Version reported by /eth/v1/node/version
+	Client  string   `json:"client" example:"lighthouse"`                                        // lighthouse, prysm, teku, nimbus, lodestar or unknown
+	Shims   []string `json:"shims"`                                                              // Workarounds applied for the client's quirks, e.g. validators-by-query or not-found-errors
+}
+
+// ScheduledTaskResponse represents the run status of a recurring background task
+type ScheduledTaskResponse struct {
+	Name           string `json:"name" example:"network-stats"`                        // Task name
+	Schedule       string `json:"schedule" example:"@every 6m24s"`                     // Cron expression or interval the task runs on
+	Running        bool   `json:"running" example:"false"`                             // Whether a run is in progress
+	Runs           int64  `json:"runs" example:"42"`                                   // Completed runs, including failed ones
+	Failures       int64  `json:"failures" example:"1"`                                // Runs that returned an error
+	Skipped        int64  `json:"skipped" example:"0"`                                 // Runs skipped as the previous run was still going
+	LastStart      string `json:"last_start,omitempty" example:"2024-01-01T00:00:00Z"` // Start of the last run
+	LastDurationMs int64  `json:"last_duration_ms" example:"5300"`                     // Duration of the last completed run in milliseconds
+	LastError      string `json:"last_error,omitempty"`                                // Error of the last run
+	NextRun        string `json:"next_run,omitempty" example:"2024-01-01T00:06:24Z"`   // Time of the next scheduled run
+}
+
+// BlockRewardJobRequest represents the request body of a block reward job
+type BlockRewardJobRequest struct {
+	FromSlot *int64 `json:"from_slot" example:"4700000"` // First slot of the range
+	ToSlot   *int64 `json:"to_slot" example:"4709999"`   // Last slot of the range, inclusive
+}
+
+// BlockRewardJobResponse represents the state of a block reward job
+type BlockRewardJobResponse struct {
+	ID         string                         `json:"id" example:"3f2a9c1e8b7d4e6fa0c5b2d1e9f8a7b6"` // Job ID
+	Status     string                         `json:"status" example:"running"`                      // queued, running, completed or failed
+	FromSlot   int64                          `json:"from_slot" example:"4700000"`                   // First slot of the range
+	ToSlot     int64                          `json:"to_slot" example:"4709999"`                     // Last slot of the range
+	Processed  int                            `json:"processed" example:"2500"`                      // Slots handled so far
+	Total      int                            `json:"total" example:"10000"`                         // Slots in the range
+	Progress   float64                        `json:"progress" example:"0.25"`                       // Share of the range handled, between 0 and 1
+	CreatedAt  string                         `json:"created_at" example:"2024-01-01T00:00:00Z"`     // When the job was submitted
+	FinishedAt string                         `json:"finished_at,omitempty"`                         // When the job completed or failed
+	Error      string                         `json:"error,omitempty"`                               // Why the job failed
+	Results    []BlockRewardJobResultResponse `json:"results"`                                       // Results of the handled slots in slot order
+}
+
+// BlockRewardJobResultResponse represents the outcome of a single slot of a block reward job
+type BlockRewardJobResultResponse struct {
+	Slot   int64                `json:"slot" example:"4700000"`     // Beacon chain slot
+	Reward *BlockRewardResponse `json:"reward,omitempty"`           // Block reward, absent for missed or failed slots
+	Missed bool                 `json:"missed" example:"false"`     // No block was proposed in the slot
+	Error  string               `json:"error,omitempty" example:""` // Why the reward could not be fetched
+}
+
+// ValidatorsSummaryRequest represents the request body of a validator list summary
+type ValidatorsSummaryRequest struct {
+	IDs []string `json:"ids" example:"123456,0x8000..."` // Validator indices or pubkeys
+}
+
+// ValidatorsSummaryResponse represents the summaries of a list of validators
+type ValidatorsSummaryResponse struct {
+	HeadSlot   int64                      `json:"head_slot" example:"4700013"` // Head slot the summaries were evaluated at
+	FromEpoch  int64                      `json:"from_epoch" example:"146871"` // First epoch of the attestation window
+	ToEpoch    int64                      `json:"to_epoch" example:"146875"`   // Last epoch of the attestation window
+	Validators []ValidatorSummaryResponse `json:"validators"`                  // Summaries in request order, without duplicates
+}
+
+// ValidatorSummaryResponse represents the status, upcoming duties and recent performance of a validator
+type ValidatorSummaryResponse struct {
+	ID           string                         `json:"id" example:"123456"`  // Index or pubkey as requested
+	Found        bool                           `json:"found" example:"true"` // Whether the validator exists
+	Status       *ValidatorStatusResponse       `json:"status,omitempty"`     // Status and balance at the head, omitted if not found
+	Duties       []DutyResponse                 `json:"duties"`               // Upcoming proposals and sync committee windows
+	Attestations EffectivenessComponentResponse `json:"attestations"`         // Share of correct votes within the attestation window
+}
+
+// DutyResponse represents an upcoming duty window of a validator
+type DutyResponse struct {
+	Kind      string `json:"kind" example:"proposal"`              // proposal or sync_committee
+	StartSlot int64  `json:"start_slot" example:"4700020"`         // First slot of the window
+	EndSlot   int64  `json:"end_slot" example:"4700020"`           // Last slot of the window
+	Start     string `json:"start" example:"2024-01-01T12:00:00Z"` // Start time of the window
+	End       string `json:"end" example:"2024-01-01T12:00:12Z"`   // End time of the window
+}
+
+// MetaResponse represents the provenance of the data of a response, added with meta=true
+type MetaResponse struct {
+	Providers      []string `json:"providers"`                           // Upstream providers that answered, primary or secondary
+	Cache          string   `json:"cache" example:"miss"`                // hit when served without upstream calls, miss when fetched upstream only, partial when cached data was used too
+	DataAgeSeconds float64  `json:"data_age_seconds" example:"4.2"`      // Age of the oldest cached data used, 0 when everything was fetched for the request
+	Slot           *int64   `json:"slot,omitempty" example:"4700000"`    // Slot the response is about, omitted for responses not about a slot
+	Finalized      *bool    `json:"finalized,omitempty" example:"false"` // Whether the slot was finalized, omitted with slot
+}
+
+// EnvelopeResponse represents a page of a list endpoint in the standard envelope, requested with envelope=true
+type EnvelopeResponse struct {
+	Data  interface{}           `json:"data"`  // Entries of the page, as listed by the endpoint without the envelope
+	Meta  EnvelopeMetaResponse  `json:"meta"`  // Pagination, and the provenance of the data with meta=true
+	Links EnvelopeLinksResponse `json:"links"` // Links to the current and the next page
+}
+
+// EnvelopeMetaResponse represents the metadata of a page in the standard envelope
+type EnvelopeMetaResponse struct {
+	Pagination PaginationResponse `json:"pagination"`
+	Provenance *MetaResponse      `json:"provenance,omitempty"` // Omitted without meta=true
+}
+
+// PaginationResponse represents the position of a page within a list
+type PaginationResponse struct {
+	Offset int `json:"offset" example:"0"` // Number of entries skipped
+	Limit  int `json:"limit" example:"16"` // Maximum number of entries per page
+	Count  int `json:"count" example:"16"` // Number of entries in this page
+	Total  int `json:"total" example:"64"` // Number of entries in the whole list
+}
+
+// EnvelopeLinksResponse represents the links of a page in the standard envelope
+type EnvelopeLinksResponse struct {
+	Self string `json:"self" example:"/committees/4700000?envelope=true&limit=16&offset=0"`            // This page
+	Next string `json:"next,omitempty" example:"/committees/4700000?envelope=true&limit=16&offset=16"` // The next page, omitted on the last page
+}
+
+// GrafanaSearchRequest represents a metric search of the Grafana JSON datasource
+type GrafanaSearchRequest struct {
+	Target string `json:"target" example:"attestation"` // Substring of the metric names to return, all metrics when empty
+}
+
+// GrafanaQueryRequest represents a query of the Grafana JSON datasource
+type GrafanaQueryRequest struct {
+	Range struct {
+		From time.Time `json:"from" example:"2024-01-01T00:00:00Z"` // Start of the time range
+		To   time.Time `json:"to" example:"2024-01-02T00:00:00Z"`   // End of the time range
+	} `json:"range"`
+	MaxDataPoints int                  `json:"maxDataPoints" example:"1000"` // Maximum points per series, 1000 when omitted
+	Targets       []GrafanaTargetQuery `json:"targets"`                      // Series to return
+}
+
+// GrafanaTargetQuery represents one series requested by a Grafana query
+type GrafanaTargetQuery struct {
+	Target string `json:"target" example:"block_reward"` // Metric name as returned by search
+	RefID  string `json:"refId" example:"A"`             // Query reference of the panel
+	Type   string `json:"type" example:"timeserie"`      // timeserie (default) or table
+}
+
+// GrafanaSeriesResponse represents one series of a Grafana query result
+type GrafanaSeriesResponse struct {
+	Target     string       `json:"target" example:"block_reward"` // Metric name
+	RefID      string       `json:"refId,omitempty" example:"A"`   // Query reference the series answers
+	Datapoints [][2]float64 `json:"datapoints"`                    // [value, unix milliseconds] pairs, oldest first
+}
+
+// GrafanaTableResponse represents a series of a Grafana query result requested as table
+type GrafanaTableResponse struct {
+	Type    string                  `json:"type" example:"table"`        // Always table
+	RefID   string                  `json:"refId,omitempty" example:"A"` // Query reference the table answers
+	Columns []GrafanaColumnResponse `json:"columns"`                     // Time and value columns
+	Rows    [][2]float64            `json:"rows"`                        // [unix milliseconds, value] rows, oldest first
+}
+
+// GrafanaColumnResponse represents a column of a Grafana table result
+type GrafanaColumnResponse struct {
+	Text string `json:"text" example:"Time"` // Column name
+	Type string `json:"type" example:"time"` // time or number
+}
+
+// AccountResponse represents a watchlist account created by an admin
+type AccountResponse struct {
+	ID     string `json:"id" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`      // Account ID, used to remove the account
+	APIKey string `json:"api_key" example:"4c1f0e4a7d9b2c3e5f6a8b0d1e2f3a4b5c6d7e8f9a0b1c2d3e4f5a6b7c8d9e0f"` // API key of the account, only returned once
+}
+
+// AlertRuleResponse represents an alert rule of a watchlist
+type AlertRuleResponse struct {
+	Event      string  `json:"event" example:"missed_proposal"`       // missed_attestation, missed_proposal or slashed
+	Validators []int64 `json:"validators,omitempty" example:"123456"` // Watched validators the rule applies to, all of them if empty
+}
+
+// WatchlistResponse represents the watched validators, alert rules and notification channels of an account
+type WatchlistResponse struct {
+	Validators []int64             `json:"validators" example:"123456,654321"`                                  // Watched validator indices, ascending
+	Rules      []AlertRuleResponse `json:"rules"`                                                               // Events alerted about
+	Channels   []string            `json:"channels" example:"discord:https://discord.com/api/webhooks/1/token"` // Channels alerts are sent to
+}
+
+// WatchlistRulesRequest represents the request body replacing the alert rules of a watchlist
+type WatchlistRulesRequest struct {
+	Rules []AlertRuleResponse `json:"rules"` // Alert rules, an empty list turns alerts off
+}
+
+// WatchlistChannelsRequest represents the request body replacing the notification channels of a watchlist
+type WatchlistChannelsRequest struct {
+	Channels []string `json:"channels" example:"telegram:123456:ABC-DEF:-1001234567890"` // telegram:<bot token>:<chat id> or discord:<webhook URL>
+}
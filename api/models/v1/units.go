@@ -0,0 +1,65 @@
+package v1
+
+import (
+	"math/big"
+	"strings"
+)
+
+// Units amounts can be reported in
+const (
+	UnitWei  = "wei"
+	UnitGwei = "gwei"
+	UnitETH  = "eth"
+)
+
+// unitDecimals maps each unit to the number of decimals of its amounts expressed in Wei
+var unitDecimals = map[string]int{
+	UnitWei:  0,
+	UnitGwei: 9,
+	UnitETH:  18,
+}
+
+// ValidUnit reports whether amounts can be reported in unit
+func ValidUnit(unit string) bool {
+	_, ok := unitDecimals[unit]
+	return ok
+}
+
+// gweiAmount converts an amount of Wei to whole Gwei for the deprecated int64 amount fields
+func gweiAmount(wei *big.Int) int64 {
+	if wei == nil {
+		return 0
+	}
+	return new(big.Int).Quo(wei, big.NewInt(1e9)).Int64()
+}
+
+// FormatGwei formats an amount of GWEI in a unit like FormatAmount
+func FormatGwei(gwei int64, unit string) string {
+	return FormatAmount(new(big.Int).Mul(big.NewInt(gwei), big.NewInt(1e9)), unit)
+}
+
+// FormatAmount formats an amount of Wei in a unit as an exact decimal string, without trailing zeros in the fraction
+func FormatAmount(wei *big.Int, unit string) string {
+	if wei == nil {
+		return "0"
+	}
+	decimals := unitDecimals[unit]
+	if decimals == 0 {
+		return wei.String()
+	}
+
+	digits := new(big.Int).Abs(wei).String()
+	if len(digits) <= decimals {
+		digits = strings.Repeat("0", decimals-len(digits)+1) + digits
+	}
+	integer, fraction := digits[:len(digits)-decimals], strings.TrimRight(digits[len(digits)-decimals:], "0")
+
+	sign := ""
+	if wei.Sign() < 0 {
+		sign = "-"
+	}
+	if fraction == "" {
+		return sign + integer
+	}
+	return sign + integer + "." + fraction
+}
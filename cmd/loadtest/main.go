@@ -0,0 +1,55 @@
+// Command loadtest drives load against running API endpoints and exits with status 1 when the service level
+// objectives are missed:
+//
+//	go run ./cmd/loadtest -c 50 -n 1000 -p95 500ms http://localhost:3004/blockreward/4700000
+package main
+
+import (
+	"context"
+	"ethereum-validator-api/loadtest"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+)
+
+func main() {
+	var config loadtest.Config
+	var slo loadtest.SLO
+	flag.IntVar(&config.Concurrency, "c", 50, "Number of concurrent workers")
+	flag.Float64Var(&config.Rate, "rps", 0, "Requests per second across all workers, 0 for no limit")
+	flag.IntVar(&config.Requests, "n", 1000, "Total number of requests, 0 to run for -z only")
+	flag.DurationVar(&config.Duration, "z", 0, "Maximum duration of the run, e.g. 30s")
+	flag.DurationVar(&config.Timeout, "t", 20*time.Second, "Timeout of a single request")
+	flag.DurationVar(&slo.P50, "p50", 0, "Highest acceptable median latency")
+	flag.DurationVar(&slo.P95, "p95", 0, "Highest acceptable 95th percentile latency")
+	flag.DurationVar(&slo.P99, "p99", 0, "Highest acceptable 99th percentile latency")
+	flag.Float64Var(&slo.MaxErrorRate, "max-error-rate", 0.01, "Highest acceptable share of failed requests, 0 to accept any")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] url...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	config.Targets = flag.Args()
+	if len(config.Targets) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	result, err := loadtest.Run(ctx, config)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	fmt.Println(result)
+
+	if err := slo.Check(result); err != nil {
+		fmt.Fprintf(os.Stderr, "SLO missed: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,400 @@
+package handler
+
+import (
+	"bytes"
+	"ethereum-validator-api/api/models/v1"
+	"ethereum-validator-api/service"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// @Summary List MEV Builder Signatures
+// @Description Lists the builder signatures used to identify MEV blocks
+// @Tags admin
+// @Security ApiKeyAuth
+// @Success 200 {array} service.BuilderSignature "Returns the configured builder signatures"
+// @Failure 401 {object} v1.ErrorResponse "Missing or invalid API key"
+// @Router /admin/builders [get]
+func (h *Handler) ListBuilders(c *gin.Context) {
+	c.JSON(http.StatusOK, h.ethService.Builders().List())
+}
+
+// @Summary Add MEV Builder Signature
+// @Description Adds a builder signature, replacing any existing signature with the same name
+// @Tags admin
+// @Security ApiKeyAuth
+// @Param signature body service.BuilderSignature true "Builder signature"
+// @Success 200 {object} service.BuilderSignature "Returns the stored builder signature"
+// @Failure 400 {object} v1.ErrorResponse "Invalid builder signature"
+// @Failure 401 {object} v1.ErrorResponse "Missing or invalid API key"
+// @Router /admin/builders [post]
+func (h *Handler) PutBuilder(c *gin.Context) {
+	var signature service.BuilderSignature
+	if err := c.ShouldBindJSON(&signature); err != nil {
+		c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: "Invalid request body"})
+		return
+	}
+	if err := signature.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	var before interface{}
+	if existing, ok := h.ethService.Builders().Get(signature.Name); ok {
+		before = existing
+	}
+	h.ethService.Builders().Put(signature)
+	h.recordAudit(c, service.AuditBuilderPut, signature.Name, before, signature)
+	c.JSON(http.StatusOK, signature)
+}
+
+// @Summary Remove MEV Builder Signature
+// @Description Removes the signature of a builder by name
+// @Tags admin
+// @Security ApiKeyAuth
+// @Param name path string true "Builder name"
+// @Success 204 "Builder signature removed"
+// @Failure 401 {object} v1.ErrorResponse "Missing or invalid API key"
+// @Failure 404 {object} v1.ErrorResponse "Builder not found"
+// @Router /admin/builders/{name} [delete]
+func (h *Handler) DeleteBuilder(c *gin.Context) {
+	name := c.Param("name")
+	before, _ := h.ethService.Builders().Get(name)
+	if !h.ethService.Builders().Remove(name) {
+		c.JSON(http.StatusNotFound, v1.ErrorResponse{Error: "Builder not found"})
+		return
+	}
+	h.recordAudit(c, service.AuditBuilderDelete, name, before, nil)
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Download Support Bundle
+// @Description Generates a gzipped tar archive with sanitized diagnostics (version, redacted config, provider health, index stats and recent logs) to attach to bug reports
+// @Tags admin
+// @Security ApiKeyAuth
+// @Produce application/gzip
+// @Success 200 {file} file "Support bundle archive"
+// @Failure 401 {object} v1.ErrorResponse "Missing or invalid API key"
+// @Failure 500 {object} v1.ErrorResponse "Internal server error"
+// @Router /admin/support-bundle [get]
+func (h *Handler) GetSupportBundle(c *gin.Context) {
+	var bundle bytes.Buffer
+	if err := h.ethService.WriteSupportBundle(c.Request.Context(), &bundle, h.logs); err != nil {
+		c.JSON(http.StatusInternalServerError, v1.ErrorResponse{Error: "Internal server error"})
+		return
+	}
+
+	filename := fmt.Sprintf("support-bundle-%s.tar.gz", time.Now().UTC().Format("20060102T150405Z"))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, "application/gzip", bundle.Bytes())
+}
+
+// @Summary Get Cache Statistics
+// @Description Reports the number of cached responses, the cache hit ratio, the approximate memory held by the cache and the entries evicted to keep it within its limits
+// @Tags admin
+// @Security ApiKeyAuth
+// @Success 200 {object} v1.CacheStatsResponse "Returns the cache statistics"
+// @Failure 401 {object} v1.ErrorResponse "Missing or invalid API key"
+// @Router /admin/cache/stats [get]
+func (h *Handler) GetCacheStats(c *gin.Context) {
+	stats := h.ethService.CacheStats()
+	c.JSON(http.StatusOK, v1.CacheStatsResponse{
+		Entries:     stats.Entries,
+		Expired:     stats.Expired,
+		Evictions:   stats.Evictions,
+		Hits:        stats.Hits,
+		Misses:      stats.Misses,
+		HitRatio:    stats.HitRatio,
+		MemoryBytes: stats.MemoryBytes,
+	})
+}
+
+// @Summary Get Upstream Budgets
+// @Description Reports the calls and credits sent to every upstream provider host during the current UTC day, with the remaining credits of the configured daily quotas. Once 90% of a quota is used, batch requests are shed and expired cached data is served
+// @Tags admin
+// @Security ApiKeyAuth
+// @Success 200 {object} v1.UpstreamBudgetsResponse "Returns the upstream budgets"
+// @Failure 401 {object} v1.ErrorResponse "Missing or invalid API key"
+// @Router /admin/upstreams [get]
+func (h *Handler) GetUpstreamBudgets(c *gin.Context) {
+	budgets := service.ProviderBudgets()
+	response := v1.UpstreamBudgetsResponse{Upstreams: make([]v1.UpstreamBudgetResponse, 0, len(budgets))}
+	for _, budget := range budgets {
+		upstream := v1.UpstreamBudgetResponse{
+			Host:    budget.Host,
+			Day:     budget.Day.Format(time.DateOnly),
+			Calls:   budget.Calls,
+			Credits: budget.Credits,
+			Quota:   budget.Quota,
+			Low:     budget.Low,
+		}
+		if budget.Quota > 0 {
+			remaining := budget.Remaining
+			upstream.Remaining = &remaining
+		}
+		response.Upstreams = append(response.Upstreams, upstream)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Get Upstream Capture
+// @Description Lists the last upstream requests with their responses, newest first, to debug provider responses. API keys in provider URLs are redacted and bodies are cut off after 4 KiB. Exchanges are only captured while the capture is enabled
+// @Tags admin
+// @Security ApiKeyAuth
+// @Success 200 {object} v1.UpstreamCaptureResponse "Returns the captured exchanges"
+// @Failure 401 {object} v1.ErrorResponse "Missing or invalid API key"
+// @Router /admin/debug/upstream [get]
+func (h *Handler) GetUpstreamCapture(c *gin.Context) {
+	c.JSON(http.StatusOK, newUpstreamCaptureResponse(h.ethService.UpstreamCapture()))
+}
+
+// @Summary Toggle Upstream Capture
+// @Description Turns capturing upstream exchanges on or off at runtime, optionally dropping the exchanges captured so far
+// @Tags admin
+// @Security ApiKeyAuth
+// @Param request body v1.UpstreamCaptureRequest true "Capture settings"
+// @Success 200 {object} v1.UpstreamCaptureResponse "Returns the capture after the change"
+// @Failure 400 {object} v1.ErrorResponse "Invalid request body"
+// @Failure 401 {object} v1.ErrorResponse "Missing or invalid API key"
+// @Router /admin/debug/upstream [put]
+func (h *Handler) PutUpstreamCapture(c *gin.Context) {
+	var request v1.UpstreamCaptureRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: "Invalid request body", Details: err.Error()})
+		return
+	}
+
+	capture := h.ethService.UpstreamCapture()
+	before := capture.Enabled()
+	capture.SetEnabled(*request.Enabled)
+	if request.Clear {
+		capture.Clear()
+	}
+	h.recordAudit(c, service.AuditCapturePut, "", gin.H{"enabled": before}, request)
+	c.JSON(http.StatusOK, newUpstreamCaptureResponse(capture))
+}
+
+// newUpstreamCaptureResponse lists the exchanges of an upstream capture newest first
+func newUpstreamCaptureResponse(capture *service.UpstreamCapture) v1.UpstreamCaptureResponse {
+	exchanges := capture.Exchanges()
+	response := v1.UpstreamCaptureResponse{
+		Enabled:   capture.Enabled(),
+		Size:      capture.Size(),
+		Exchanges: make([]v1.UpstreamExchangeResponse, 0, len(exchanges)),
+	}
+	for i := len(exchanges) - 1; i >= 0; i-- {
+		exchange := exchanges[i]
+		response.Exchanges = append(response.Exchanges, v1.UpstreamExchangeResponse{
+			Time:         exchange.Time.Format(time.RFC3339),
+			Method:       exchange.Method,
+			URL:          exchange.URL,
+			Status:       exchange.Status,
+			Error:        exchange.Error,
+			DurationMs:   exchange.Duration.Milliseconds(),
+			RequestBody:  exchange.RequestBody,
+			ResponseBody: exchange.ResponseBody,
+			Truncated:    exchange.Truncated,
+		})
+	}
+	return response
+}
+
+// @Summary Invalidate Cache
+// @Description Removes the cached responses of a slot, including the sync committee of its period, or the whole cache with slot=all
+// @Tags admin
+// @Security ApiKeyAuth
+// @Param slot query string true "Slot number, or all to clear the whole cache"
+// @Success 200 {object} v1.CacheInvalidationResponse "Returns the number of removed entries"
+// @Failure 400 {object} v1.ErrorResponse "Invalid slot"
+// @Failure 401 {object} v1.ErrorResponse "Missing or invalid API key"
+// @Router /admin/cache [delete]
+func (h *Handler) DeleteCache(c *gin.Context) {
+	slotParam := c.Query("slot")
+	if slotParam == "all" {
+		response := v1.CacheInvalidationResponse{Removed: h.ethService.ClearCache()}
+		h.recordAudit(c, service.AuditCacheDelete, slotParam, nil, response)
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	slot, err := parseSlot(slotParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: "Invalid slot, use a slot number or all", Details: err.Error()})
+		return
+	}
+	response := v1.CacheInvalidationResponse{Removed: h.ethService.InvalidateSlot(slot)}
+	h.recordAudit(c, service.AuditCacheDelete, slotParam, nil, response)
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Get API Usage
+// @Description Reports the requests per client, endpoint and UTC day. Clients authenticated with a JWT are identified by sub:<subject>, clients sending an API key by key:<first 16 hex digits of its SHA-256>, others by ip:<address>
+// @Tags admin
+// @Security ApiKeyAuth
+// @Param client query string false "Only count this client, e.g. ip:203.0.113.7"
+// @Param endpoint query string false "Only count this endpoint, e.g. GET /validator/:id"
+// @Param from query string false "First UTC day included (YYYY-MM-DD)"
+// @Param to query string false "Last UTC day included (YYYY-MM-DD)"
+// @Param group_by query string false "Sum the records per day, client or endpoint" Enums(day, client, endpoint)
+// @Success 200 {object} v1.UsageResponse "Returns the matching request counts"
+// @Failure 400 {object} v1.ErrorResponse "Invalid day or group_by"
+// @Failure 401 {object} v1.ErrorResponse "Missing or invalid API key"
+// @Router /admin/usage [get]
+func (h *Handler) GetUsage(c *gin.Context) {
+	filter := service.UsageFilter{Client: c.Query("client"), Endpoint: c.Query("endpoint"), GroupBy: c.Query("group_by")}
+	switch filter.GroupBy {
+	case "", service.UsageByDay, service.UsageByClient, service.UsageByEndpoint:
+	default:
+		c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: "Invalid group_by, use day, client or endpoint"})
+		return
+	}
+	for param, day := range map[string]*time.Time{"from": &filter.From, "to": &filter.To} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: fmt.Sprintf("Invalid %s, use YYYY-MM-DD", param), Details: err.Error()})
+			return
+		}
+		*day = parsed
+	}
+
+	records := h.ethService.Usage().Query(filter)
+	response := v1.UsageResponse{Records: make([]v1.UsageRecordResponse, 0, len(records))}
+	for _, record := range records {
+		response.Requests += record.Requests
+		response.Errors += record.Errors
+		response.Records = append(response.Records, v1.UsageRecordResponse{
+			Day:      record.Day,
+			Client:   record.Client,
+			Endpoint: record.Endpoint,
+			Requests: record.Requests,
+			Errors:   record.Errors,
+		})
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary List Scheduled Jobs
+// @Description Reports the recurring background tasks with their schedule, whether they are running, the outcome of their last run and when they run next
+// @Tags admin
+// @Security ApiKeyAuth
+// @Success 200 {array} v1.ScheduledTaskResponse "Returns the scheduled tasks"
+// @Failure 401 {object} v1.ErrorResponse "Missing or invalid API key"
+// @Router /admin/jobs [get]
+func (h *Handler) ListScheduledTasks(c *gin.Context) {
+	response := []v1.ScheduledTaskResponse{}
+	if h.scheduler != nil {
+		for _, status := range h.scheduler.Status() {
+			task := v1.ScheduledTaskResponse{
+				Name:           status.Name,
+				Schedule:       status.Schedule,
+				Running:        status.Running,
+				Runs:           status.Runs,
+				Failures:       status.Failures,
+				Skipped:        status.Skipped,
+				LastDurationMs: status.LastDuration.Milliseconds(),
+				LastError:      status.LastError,
+			}
+			if !status.LastStart.IsZero() {
+				task.LastStart = status.LastStart.Format(time.RFC3339)
+			}
+			if !status.NextRun.IsZero() {
+				task.NextRun = status.NextRun.UTC().Format(time.RFC3339)
+			}
+			response = append(response, task)
+		}
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Reload Configuration
+// @Description Re-reads the .env file and applies the upstream rate limit, upstream endpoints, CORS configuration and MEV builder signatures file without a restart, like sending SIGHUP. Nothing is applied when a setting is invalid
+// @Tags admin
+// @Security ApiKeyAuth
+// @Success 200 {object} v1.ReloadResponse "Returns the applied settings"
+// @Failure 401 {object} v1.ErrorResponse "Missing or invalid API key"
+// @Failure 422 {object} v1.ErrorResponse "Invalid configuration, the current configuration is kept"
+// @Failure 501 {object} v1.ErrorResponse "Reloading is not available"
+// @Router /admin/reload [post]
+func (h *Handler) ReloadConfig(c *gin.Context) {
+	if h.reload == nil {
+		c.JSON(http.StatusNotImplemented, v1.ErrorResponse{Error: "Reloading is not available"})
+		return
+	}
+
+	applied, err := h.reload(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, v1.ErrorResponse{Error: "Invalid configuration, keeping the current configuration", Details: err.Error()})
+		return
+	}
+	response := v1.ReloadResponse{Applied: applied}
+	h.recordAudit(c, service.AuditConfigReload, "", nil, response)
+	c.JSON(http.StatusOK, response)
+}
+
+// Number of audit entries listed unless requested otherwise, and the most that can be requested
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+// @Summary Get Audit Log
+// @Description Lists the admin actions newest first: builder signature changes, cache invalidations and configuration reloads, with the client that performed them and the values before and after. The actor is identified like in the usage, reloads triggered by SIGHUP have the actor signal:SIGHUP
+// @Tags admin
+// @Security ApiKeyAuth
+// @Param actor query string false "Only list actions of this actor"
+// @Param action query string false "Only list this action" Enums(builder.put, builder.delete, cache.delete, config.reload, capture.put, account.create, account.delete)
+// @Param since query string false "Only list actions at or after this time (RFC 3339)"
+// @Param limit query int false "Maximum number of entries (1-1000)" default(100)
+// @Success 200 {array} v1.AuditEntryResponse "Returns the matching audit entries"
+// @Failure 400 {object} v1.ErrorResponse "Invalid since or limit"
+// @Failure 401 {object} v1.ErrorResponse "Missing or invalid API key"
+// @Router /admin/audit [get]
+func (h *Handler) GetAuditLog(c *gin.Context) {
+	filter := service.AuditFilter{Actor: c.Query("actor"), Action: c.Query("action"), Limit: defaultAuditLimit}
+	if since := c.Query("since"); since != "" {
+		parsed, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: "Invalid since, use an RFC 3339 time", Details: err.Error()})
+			return
+		}
+		filter.Since = parsed
+	}
+	if limit := c.Query("limit"); limit != "" {
+		parsed, err := strconv.Atoi(limit)
+		if err != nil || parsed < 1 || parsed > maxAuditLimit {
+			c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: fmt.Sprintf("Invalid limit, use 1 to %d", maxAuditLimit)})
+			return
+		}
+		filter.Limit = parsed
+	}
+
+	entries := h.ethService.Audit().Query(filter)
+	response := make([]v1.AuditEntryResponse, 0, len(entries))
+	for _, entry := range entries {
+		response = append(response, v1.AuditEntryResponse{
+			Time:   entry.Time.Format(time.RFC3339),
+			Actor:  entry.Actor,
+			Action: entry.Action,
+			Target: entry.Target,
+			Before: entry.Before,
+			After:  entry.After,
+		})
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// recordAudit records an admin action of the client of the request, an entry that can't be persisted is logged
+func (h *Handler) recordAudit(c *gin.Context, action, target string, before, after interface{}) {
+	actor := requestClient(c)
+	if err := h.ethService.Audit().Record(actor, action, target, before, after); err != nil {
+		slog.Error("Failed to record admin action", "action", action, "error", err)
+	}
+}
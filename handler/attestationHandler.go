@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"ethereum-validator-api/api/models/v1"
+	"ethereum-validator-api/service"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// defaultAttestationEpochs is the number of epochs reported when epochs is not given
+const defaultAttestationEpochs = 10
+
+// @Summary Get Validator Attestation Performance
+// @Description Reports per epoch whether the validator's attestation was included, its inclusion delay and the correctness of its head, target and source votes, based on the beacon attestation rewards
+// @Tags validator
+// @Param id path string true "Validator index or pubkey"
+// @Param epochs query string false "Number of most recent rewarded epochs, as N or last_N (default last_10, max 100)"
+// @Produce json,application/x-ndjson
+// @Param fields query string false "Comma separated fields to return, nested fields of objects and lists selected with dots"
+// @Param meta query bool false "Add a meta field reporting the providers that served the data, cache use, data age and finalization"
+// @Success 200 {object} v1.AttestationPerformanceResponse "Returns the attestation performance, newest epoch first. With Accept: application/x-ndjson one v1.AttestationResponse per line"
+// @Failure 400 {object} v1.ErrorResponse "Invalid validator or epochs"
+// @Failure 404 {object} v1.ErrorResponse "Validator not found"
+// @Failure 429 {object} v1.ErrorResponse "Upstream provider is rate limiting requests"
+// @Failure 502 {object} v1.ErrorResponse "Upstream returned an invalid response"
+// @Failure 500 {object} v1.ErrorResponse "Internal server error"
+// @Failure 503 {object} v1.ErrorResponse "Upstream providers not reached since startup or upstream node not synced"
+// @Failure 504 {object} v1.ErrorResponse "Upstream request timed out"
+// @Router /validator/{id}/attestations [get]
+func (h *Handler) GetValidatorAttestations(c *gin.Context) {
+	epochs, ok := parseEpochsWindow(c)
+	if !ok {
+		return
+	}
+
+	if wantsNDJSON(c) {
+		stream := newNDJSONStream(c)
+		err := h.ethService.StreamValidatorAttestations(c.Request.Context(), c.Param("id"), epochs, func(perf *service.AttestationPerformance) error {
+			return stream.Write(v1.NewAttestationResponse(perf))
+		})
+		stream.Close(err, respondValidatorError)
+		return
+	}
+
+	performance, err := h.ethService.GetValidatorAttestations(c.Request.Context(), c.Param("id"), epochs)
+	if err != nil {
+		respondValidatorError(c, err)
+		return
+	}
+
+	// Create response object
+	response := v1.AttestationPerformanceResponse{
+		Attestations: make([]v1.AttestationResponse, 0, len(performance)),
+	}
+	for _, perf := range performance {
+		if perf.Included {
+			response.Included++
+		}
+		response.Attestations = append(response.Attestations, v1.NewAttestationResponse(perf))
+	}
+	response.Epochs = len(performance)
+
+	respondJSON(c, http.StatusOK, response)
+}
+
+// @Summary Get Validator Effectiveness
+// @Description Computes an effectiveness score from attestation correctness, proposal success and sync committee participation over the most recent rewarded epochs, together with its components
+// @Tags validator
+// @Param id path string true "Validator index or pubkey"
+// @Param epochs query string false "Number of most recent rewarded epochs, as N or last_N (default last_10, max 100)"
+// @Param fields query string false "Comma separated fields to return, nested fields of objects and lists selected with dots"
+// @Param meta query bool false "Add a meta field reporting the providers that served the data, cache use, data age and finalization"
+// @Success 200 {object} v1.EffectivenessResponse "Returns the effectiveness score and its components"
+// @Failure 400 {object} v1.ErrorResponse "Invalid validator or epochs"
+// @Failure 404 {object} v1.ErrorResponse "Validator not found"
+// @Failure 429 {object} v1.ErrorResponse "Upstream provider is rate limiting requests"
+// @Failure 502 {object} v1.ErrorResponse "Upstream returned an invalid response"
+// @Failure 500 {object} v1.ErrorResponse "Internal server error"
+// @Failure 503 {object} v1.ErrorResponse "Upstream providers not reached since startup or upstream node not synced"
+// @Failure 504 {object} v1.ErrorResponse "Upstream request timed out"
+// @Router /validator/{id}/effectiveness [get]
+func (h *Handler) GetValidatorEffectiveness(c *gin.Context) {
+	epochs, ok := parseEpochsWindow(c)
+	if !ok {
+		return
+	}
+
+	effectiveness, err := h.ethService.GetValidatorEffectiveness(c.Request.Context(), c.Param("id"), epochs)
+	if err != nil {
+		respondValidatorError(c, err)
+		return
+	}
+
+	// Create response object
+	response := v1.EffectivenessResponse{
+		ValidatorIndex: effectiveness.ValidatorIndex,
+		FromEpoch:      effectiveness.FromEpoch,
+		ToEpoch:        effectiveness.ToEpoch,
+		Score:          effectiveness.Score,
+		Attestation:    v1.NewEffectivenessComponentResponse(effectiveness.Attestation),
+		Proposal:       v1.NewEffectivenessComponentResponse(effectiveness.Proposal),
+		SyncCommittee:  v1.NewEffectivenessComponentResponse(effectiveness.SyncCommittee),
+	}
+
+	respondJSON(c, http.StatusOK, response)
+}
+
+// parseEpochsWindow parses the optional epochs query parameter, given as N or last_N,
+// and responds with 400 when it is invalid
+func parseEpochsWindow(c *gin.Context) (int64, bool) {
+	epochsParam, ok := c.GetQuery("epochs")
+	if !ok {
+		return defaultAttestationEpochs, true
+	}
+
+	epochs, err := strconv.ParseInt(strings.TrimPrefix(epochsParam, "last_"), 10, 64)
+	if err != nil || epochs < 1 || epochs > service.MaxAttestationEpochs {
+		c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: fmt.Sprintf("epochs must be between 1 and %d", service.MaxAttestationEpochs)})
+		return 0, false
+	}
+	return epochs, true
+}
@@ -0,0 +1,227 @@
+package handler
+
+import (
+	"errors"
+	"ethereum-validator-api/api/models/v1"
+	"ethereum-validator-api/service"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+// Bounds of the number of transactions returned by the top transactions endpoint
+const (
+	defaultTopTransactions = 10
+	maxTopTransactions     = 100
+)
+
+// @Summary Get Block Details
+// @Description Retrieves a cleaned-up view of the beacon block at a given slot, including proposer, graffiti, roots, execution payload summary with a breakdown of the transactions by type and sync aggregate participation. While light client verification is enabled, it reports whether the block root is proven by the sync committee
+// @Tags block
+// @Param slot path string true "Slot number in the Beacon Chain, or head, finalized, justified or genesis"
+// @Param fields query string false "Comma separated fields to return, nested fields of objects and lists selected with dots"
+// @Param meta query bool false "Add a meta field reporting the providers that served the data, cache use, data age and finalization"
+// @Success 200 {object} v1.BlockDetailResponse "Returns block details"
+// @Failure 400 {object} v1.ErrorResponse "Invalid slot number or future slot"
+// @Failure 404 {object} v1.ErrorResponse "Slot not found in chain"
+// @Failure 429 {object} v1.ErrorResponse "Upstream provider is rate limiting requests"
+// @Failure 500 {object} v1.ErrorResponse "Internal server error"
+// @Failure 502 {object} v1.ErrorResponse "Upstream returned inconsistent data while verification is enabled, or an invalid response"
+// @Failure 503 {object} v1.ErrorResponse "Upstream providers not reached since startup or upstream node not synced"
+// @Failure 504 {object} v1.ErrorResponse "Upstream request timed out"
+// @Router /block/{slot} [get]
+func (h *Handler) GetBlock(c *gin.Context) {
+	slot, ok := h.slotParam(c, slotTolerance)
+	if !ok {
+		return
+	}
+
+	block, err := h.ethService.GetBlockDetailBySlot(c.Request.Context(), slot)
+	if err != nil {
+		var statusCode int
+		var errMsg string
+
+		switch {
+		case errors.Is(err, service.ErrFutureSlot):
+			statusCode = http.StatusBadRequest
+			errMsg = "Slot is in the future"
+		case errors.Is(err, service.ErrSlotNotFound):
+			statusCode = http.StatusNotFound
+			errMsg = "Slot does not exist"
+		default:
+			respondInternalError(c, err)
+			return
+		}
+
+		c.JSON(statusCode, v1.ErrorResponse{Error: errMsg})
+		return
+	}
+
+	// Create response object
+	response := v1.BlockDetailResponse{
+		Slot:           block.Slot,
+		ProposerIndex:  block.ProposerIndex,
+		ProposerPubkey: block.ProposerPubkey,
+		Graffiti:       block.Graffiti,
+		BlockRoot:      block.BlockRoot,
+		ParentRoot:     block.ParentRoot,
+		StateRoot:      block.StateRoot,
+	}
+	response.ExecutionInfo.BlockNumber = block.ExecutionBlockNumber
+	response.ExecutionInfo.BlockHash = block.ExecutionBlockHash
+	response.ExecutionInfo.FeeRecipient = block.FeeRecipient
+	response.ExecutionInfo.GasUsed = block.GasUsed
+	response.ExecutionInfo.GasLimit = block.GasLimit
+	response.ExecutionInfo.TxCount = block.TxCount
+	response.ExecutionInfo.TransactionTypes = v1.TransactionTypesResponse{
+		Legacy:           block.TransactionTypes.Legacy,
+		AccessList:       block.TransactionTypes.AccessList,
+		EIP1559:          block.TransactionTypes.DynamicFee,
+		Blob:             block.TransactionTypes.Blob,
+		SetCode:          block.TransactionTypes.SetCode,
+		ContractCreation: block.TransactionTypes.ContractCreation,
+		Unknown:          block.TransactionTypes.Unknown,
+	}
+	response.SyncAggregate.Participants = block.SyncParticipation
+	response.SyncAggregate.CommitteeSize = service.SyncCommitteeSize
+	response.SyncAggregate.Participation = float64(block.SyncParticipation) / float64(service.SyncCommitteeSize)
+
+	if response.Verification, ok = h.verificationResponse(c, slot); !ok {
+		return
+	}
+	if response.Verified, ok = h.lightClientVerified(c, slot, block.BlockRoot); !ok {
+		return
+	}
+
+	respondJSON(c, http.StatusOK, response)
+}
+
+// @Summary Get Block Gas
+// @Description Returns the gas used and gas limit, the utilization of the gas limit and the base fee of the block at a given slot
+// @Tags block
+// @Param slot path string true "Slot number in the Beacon Chain, or head, finalized, justified or genesis"
+// @Param unit query string false "Unit of the base fee: wei, gwei (default) or eth"
+// @Param fields query string false "Comma separated fields to return, nested fields of objects and lists selected with dots"
+// @Param meta query bool false "Add a meta field reporting the providers that served the data, cache use, data age and finalization"
+// @Success 200 {object} v1.BlockGasResponse "Returns the gas usage of the block"
+// @Failure 400 {object} v1.ErrorResponse "Invalid slot number or future slot"
+// @Failure 404 {object} v1.ErrorResponse "Slot not found in chain"
+// @Failure 429 {object} v1.ErrorResponse "Upstream provider is rate limiting requests"
+// @Failure 502 {object} v1.ErrorResponse "Upstream returned an invalid response"
+// @Failure 500 {object} v1.ErrorResponse "Internal server error"
+// @Failure 503 {object} v1.ErrorResponse "Upstream providers not reached since startup or upstream node not synced"
+// @Failure 504 {object} v1.ErrorResponse "Upstream request timed out"
+// @Router /block/{slot}/gas [get]
+func (h *Handler) GetBlockGas(c *gin.Context) {
+	slot, ok := h.slotParam(c, slotTolerance)
+	if !ok {
+		return
+	}
+	unit, ok := parseUnit(c)
+	if !ok {
+		return
+	}
+
+	gas, err := h.ethService.GetBlockGas(c.Request.Context(), slot)
+	if err != nil {
+		var statusCode int
+		var errMsg string
+
+		switch {
+		case errors.Is(err, service.ErrFutureSlot):
+			statusCode = http.StatusBadRequest
+			errMsg = "Slot is in the future"
+		case errors.Is(err, service.ErrSlotNotFound):
+			statusCode = http.StatusNotFound
+			errMsg = "Slot does not exist"
+		default:
+			respondInternalError(c, err)
+			return
+		}
+
+		c.JSON(statusCode, v1.ErrorResponse{Error: errMsg})
+		return
+	}
+
+	response := v1.BlockGasResponse{
+		Slot:        gas.Slot,
+		BlockNumber: gas.BlockNumber,
+		GasUsed:     gas.GasUsed,
+		GasLimit:    gas.GasLimit,
+		Utilization: gas.Utilization,
+		Unit:        unit,
+	}
+	if gas.BaseFee != nil {
+		response.BaseFee = v1.FormatAmount(gas.BaseFee, unit)
+	}
+
+	respondJSON(c, http.StatusOK, response)
+}
+
+// @Summary Get Top Fee-Paying Transactions
+// @Description Returns the transactions of the block at a given slot contributing most to the proposer reward, with their effective priority fees and share of the block's priority fees
+// @Tags block
+// @Param slot path string true "Slot number in the Beacon Chain, or head, finalized, justified or genesis"
+// @Param n query int false "Number of transactions to return, between 1 and 100 (default 10)"
+// @Param unit query string false "Unit of the amounts: wei, gwei (default) or eth"
+// @Param fields query string false "Comma separated fields to return, nested fields of objects and lists selected with dots"
+// @Param meta query bool false "Add a meta field reporting the providers that served the data, cache use, data age and finalization"
+// @Success 200 {object} v1.TopTransactionsResponse "Returns the top transactions by contribution"
+// @Failure 400 {object} v1.ErrorResponse "Invalid slot number, future slot or invalid n"
+// @Failure 404 {object} v1.ErrorResponse "Slot not found in chain"
+// @Failure 429 {object} v1.ErrorResponse "Upstream provider is rate limiting requests"
+// @Failure 502 {object} v1.ErrorResponse "Upstream returned an invalid response"
+// @Failure 500 {object} v1.ErrorResponse "Internal server error"
+// @Failure 503 {object} v1.ErrorResponse "Upstream providers not reached since startup or upstream node not synced"
+// @Failure 504 {object} v1.ErrorResponse "Upstream request timed out"
+// @Router /block/{slot}/toptxs [get]
+func (h *Handler) GetTopTransactions(c *gin.Context) {
+	slot, ok := h.slotParam(c, slotTolerance)
+	if !ok {
+		return
+	}
+	unit, ok := parseUnit(c)
+	if !ok {
+		return
+	}
+
+	n := defaultTopTransactions
+	if nParam, ok := c.GetQuery("n"); ok {
+		parsed, err := strconv.Atoi(nParam)
+		if err != nil || parsed < 1 || parsed > maxTopTransactions {
+			c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: "Invalid n value", Details: fmt.Sprintf("n must be between 1 and %d", maxTopTransactions)})
+			return
+		}
+		n = parsed
+	}
+
+	transactions, err := h.ethService.GetTransactionRewardsBySlot(c.Request.Context(), slot, n)
+	if err != nil {
+		var statusCode int
+		var errMsg string
+
+		switch {
+		case errors.Is(err, service.ErrSlotNotFound):
+			statusCode = http.StatusNotFound
+			errMsg = "Slot does not exist"
+		default:
+			respondInternalError(c, err)
+			return
+		}
+
+		c.JSON(statusCode, v1.ErrorResponse{Error: errMsg})
+		return
+	}
+
+	response := v1.TopTransactionsResponse{
+		Slot:         slot,
+		Unit:         unit,
+		Transactions: make([]v1.TransactionRewardResponse, 0, len(transactions)),
+	}
+	for _, tx := range transactions {
+		response.Transactions = append(response.Transactions, v1.NewTransactionRewardResponse(tx, unit))
+	}
+
+	respondJSON(c, http.StatusOK, response)
+}
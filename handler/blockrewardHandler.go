@@ -2,29 +2,121 @@ package handler
 
 import (
 	"errors"
+	"ethereum-validator-api/api/models/v1"
 	"ethereum-validator-api/service"
 	"github.com/gin-gonic/gin"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // @Summary Get Block Rewards
 // @Description Retrieves block reward information including MEV status and proposer payments for a given slot
 // @Tags block
-// @Param slot path int true "Slot number in the Beacon Chain"
-// @Success 200 {object} BlockRewardResponse "Returns block reward details including MEV status and reward amounts in GWEI"
-// @Failure 400 {object} ErrorResponse "Invalid slot number or future slot"
-// @Failure 404 {object} ErrorResponse "Slot not found in chain"
-// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Param slot path string true "Slot number in the Beacon Chain, or head, finalized, justified or genesis"
+// @Param include query string false "Set to transactions to include the per-transaction reward breakdown"
+// @Param top query int false "Only include the top N transactions by contribution"
+// @Param unit query string false "Unit of the amounts: wei, gwei (default) or eth"
+// @Param currency query string false "Comma separated fiat currencies to value the reward in at the block's time, e.g. usd,eur"
+// @Param fields query string false "Comma separated fields to return, nested fields of objects and lists selected with dots"
+// @Param meta query bool false "Add a meta field reporting the providers that served the data, cache use, data age and finalization"
+// @Success 200 {object} v1.BlockRewardResponse "Returns block reward details including MEV status and reward amounts in the selected unit"
+// @Failure 400 {object} v1.ErrorResponse "Invalid slot number, future slot or untracked currency"
+// @Failure 404 {object} v1.ErrorResponse "Slot not found in chain"
+// @Failure 429 {object} v1.ErrorResponse "Upstream provider is rate limiting requests"
+// @Failure 500 {object} v1.ErrorResponse "Internal server error"
+// @Failure 502 {object} v1.ErrorResponse "Upstream returned inconsistent data while verification is enabled, or an invalid response"
+// @Failure 503 {object} v1.ErrorResponse "Fiat valuation disabled or no price known for the block's time, or upstream providers not reached since startup or not synced"
+// @Failure 504 {object} v1.ErrorResponse "Upstream request timed out"
 // @Router /blockreward/{slot} [get]
 func (h *Handler) GetBlockReward(c *gin.Context) {
-	slotParam := c.Param("slot")
-	slot, err := strconv.ParseInt(slotParam, 10, 64)
+	slot, ok := h.slotParam(c, slotTolerance)
+	if !ok {
+		return
+	}
+
+	response, ok := h.blockRewardResponse(c, slot)
+	if !ok {
+		return
+	}
+	respondJSON(c, http.StatusOK, response)
+}
+
+// @Summary Get Block Rewards by Execution Block
+// @Description Retrieves the block reward of an execution block identified by its number or hash, locating its slot through the index or the block's timestamp
+// @Tags block
+// @Param number_or_hash path string true "Execution block number, or 0x prefixed block hash"
+// @Param include query string false "Set to transactions to include the per-transaction reward breakdown"
+// @Param top query int false "Only include the top N transactions by contribution"
+// @Param unit query string false "Unit of the amounts: wei, gwei (default) or eth"
+// @Param currency query string false "Comma separated fiat currencies to value the reward in at the block's time, e.g. usd,eur"
+// @Param fields query string false "Comma separated fields to return, nested fields of objects and lists selected with dots"
+// @Param meta query bool false "Add a meta field reporting the providers that served the data, cache use, data age and finalization"
+// @Success 200 {object} v1.ExecutionBlockRewardResponse "Returns the slot of the block and its reward details"
+// @Failure 400 {object} v1.ErrorResponse "Invalid block number or hash, or untracked currency"
+// @Failure 404 {object} v1.ErrorResponse "Block not found or not proposed on the beacon chain"
+// @Failure 429 {object} v1.ErrorResponse "Upstream provider is rate limiting requests"
+// @Failure 500 {object} v1.ErrorResponse "Internal server error"
+// @Failure 502 {object} v1.ErrorResponse "Upstream returned inconsistent data while verification is enabled, or an invalid response"
+// @Failure 503 {object} v1.ErrorResponse "Fiat valuation disabled or no price known for the block's time, or upstream providers not reached since startup or not synced"
+// @Failure 504 {object} v1.ErrorResponse "Upstream request timed out"
+// @Router /blockreward/by-block/{number_or_hash} [get]
+func (h *Handler) GetBlockRewardByBlock(c *gin.Context) {
+	block, err := h.ethService.ResolveExecutionBlock(c.Request.Context(), c.Param("number_or_hash"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid slot number"})
+		switch {
+		case errors.Is(err, service.ErrInvalidBlockID):
+			c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: "Invalid block number or hash", Details: "expected a decimal block number or a 0x prefixed 32 byte block hash"})
+		case errors.Is(err, service.ErrSlotNotFound):
+			c.JSON(http.StatusNotFound, v1.ErrorResponse{Error: "Block does not exist", Details: err.Error()})
+		default:
+			respondInternalError(c, err)
+		}
 		return
 	}
 
+	reward, ok := h.blockRewardResponse(c, block.Slot)
+	if !ok {
+		return
+	}
+	respondJSON(c, http.StatusOK, v1.ExecutionBlockRewardResponse{
+		Slot:                block.Slot,
+		BlockNumber:         block.Number,
+		BlockHash:           block.Hash,
+		BlockRewardResponse: reward,
+	})
+}
+
+// blockRewardResponse builds the reward response of a slot from the unit, currency, include and top query parameters,
+// responding with the error status instead when they are invalid or the reward can't be computed
+func (h *Handler) blockRewardResponse(c *gin.Context, slot int64) (v1.BlockRewardResponse, bool) {
+	unit, ok := parseUnit(c)
+	if !ok {
+		return v1.BlockRewardResponse{}, false
+	}
+
+	var currencies []string
+	if currencyParam := c.Query("currency"); currencyParam != "" {
+		for _, currency := range strings.Split(currencyParam, ",") {
+			if currency = strings.TrimSpace(currency); currency != "" {
+				currencies = append(currencies, currency)
+			}
+		}
+	}
+
+	includeTransactions := hasInclude(c, "transactions")
+	top := 0
+	if topParam, ok := c.GetQuery("top"); ok {
+		parsed, err := strconv.Atoi(topParam)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: "Invalid top value"})
+			return v1.BlockRewardResponse{}, false
+		}
+		top = parsed
+		includeTransactions = true
+	}
+
 	reward, err := h.ethService.GetBlockRewardBySlot(c.Request.Context(), slot)
 	if err != nil {
 		var statusCode int
@@ -38,21 +130,131 @@ func (h *Handler) GetBlockReward(c *gin.Context) {
 			statusCode = http.StatusNotFound
 			errMsg = "Slot does not exist"
 		default:
-			statusCode = http.StatusInternalServerError
-			errMsg = "Internal server error"
+			respondInternalError(c, err)
+			return v1.BlockRewardResponse{}, false
 		}
 
-		c.JSON(statusCode, ErrorResponse{Error: errMsg})
+		c.JSON(statusCode, v1.ErrorResponse{Error: errMsg})
+		return v1.BlockRewardResponse{}, false
+	}
+
+	response := v1.NewBlockRewardResponse(reward, unit)
+
+	if len(currencies) > 0 {
+		values, err := h.ethService.GetFiatValues(c.Request.Context(), slot, reward.Wei(), currencies)
+		if err != nil {
+			respondFiatError(c, err)
+			return v1.BlockRewardResponse{}, false
+		}
+		for _, value := range values {
+			response.Fiat = append(response.Fiat, v1.FiatValueResponse{
+				Currency:  value.Currency,
+				Price:     value.Price,
+				PriceTime: value.PriceTime.UTC().Format(time.RFC3339),
+				Value:     value.Value,
+			})
+		}
+	}
+
+	if includeTransactions {
+		transactions, err := h.ethService.GetTransactionRewardsBySlot(c.Request.Context(), slot, top)
+		if err != nil {
+			respondInternalError(c, err)
+			return v1.BlockRewardResponse{}, false
+		}
+
+		response.Transactions = make([]v1.TransactionRewardResponse, 0, len(transactions))
+		for _, tx := range transactions {
+			response.Transactions = append(response.Transactions, v1.NewTransactionRewardResponse(tx, unit))
+		}
+	}
+
+	if response.Verification, ok = h.verificationResponse(c, slot); !ok {
+		return v1.BlockRewardResponse{}, false
+	}
+
+	return response, true
+}
+
+// hasInclude reports whether the comma separated include query parameter contains the given value
+func hasInclude(c *gin.Context, value string) bool {
+	for _, include := range strings.Split(c.Query("include"), ",") {
+		if strings.TrimSpace(include) == value {
+			return true
+		}
+	}
+	return false
+}
+
+// @Summary Get Relay Bids
+// @Description Fetches the builder bids for a slot from the configured MEV-Boost relays and compares the delivered payload with the highest available bid
+// @Tags block
+// @Param slot path string true "Slot number in the Beacon Chain, or head, finalized, justified or genesis"
+// @Param fields query string false "Comma separated fields to return, nested fields of objects and lists selected with dots"
+// @Param meta query bool false "Add a meta field reporting the providers that served the data, cache use, data age and finalization"
+// @Success 200 {object} v1.BidComparisonResponse "Returns the delivered and best bids with the missed value"
+// @Failure 400 {object} v1.ErrorResponse "Invalid slot number or future slot"
+// @Failure 502 {object} v1.ErrorResponse "No relay could be queried"
+// @Failure 503 {object} v1.ErrorResponse "Upstream providers not reached since startup"
+// @Router /blockreward/{slot}/bids [get]
+func (h *Handler) GetBlockRewardBids(c *gin.Context) {
+	slot, ok := h.slotParam(c, slotTolerance)
+	if !ok {
+		return
+	}
+
+	comparison, err := h.ethService.GetBidComparisonBySlot(c.Request.Context(), slot)
+	if err != nil {
+		var statusCode int
+		var errMsg string
+
+		switch {
+		case errors.Is(err, service.ErrFutureSlot):
+			statusCode = http.StatusBadRequest
+			errMsg = "Slot is in the future"
+		case errors.Is(err, service.ErrRPCFailed):
+			statusCode = http.StatusBadGateway
+			errMsg = "Relays are unavailable"
+		default:
+			respondInternalError(c, err)
+			return
+		}
+
+		c.JSON(statusCode, v1.ErrorResponse{Error: errMsg})
 		return
 	}
 
 	// Create response object
-	response := BlockRewardResponse{
-		Status: reward.Status,
-		Reward: reward.Reward.Int64(),
+	response := v1.BidComparisonResponse{
+		Slot:        comparison.Slot,
+		Delivered:   v1.NewRelayBidResponse(comparison.Delivered),
+		BestBid:     v1.NewRelayBidResponse(comparison.BestBid),
+		MissedValue: comparison.MissedValue.String(),
+		Relays:      make([]v1.RelaySummaryResponse, 0, len(comparison.Relays)),
+	}
+	for _, relay := range comparison.Relays {
+		response.Relays = append(response.Relays, v1.RelaySummaryResponse{
+			Relay:     relay.Relay,
+			BidCount:  relay.BidCount,
+			TopBid:    v1.NewRelayBidResponse(relay.TopBid),
+			Delivered: relay.Delivered,
+			Error:     relay.Error,
+		})
 	}
-	response.BlockInfo.ProposerPayment = reward.Reward.Int64()
-	response.BlockInfo.IsMEVBoost = reward.Status == "mev"
 
-	c.JSON(http.StatusOK, response)
+	respondJSON(c, http.StatusOK, response)
+}
+
+// respondFiatError maps errors of fiat valuation to their status codes
+func respondFiatError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrUnknownCurrency):
+		c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: "Invalid currency", Details: err.Error()})
+	case errors.Is(err, service.ErrPricesDisabled):
+		c.JSON(http.StatusServiceUnavailable, v1.ErrorResponse{Error: "Fiat valuation is not configured"})
+	case errors.Is(err, service.ErrPriceUnavailable):
+		c.JSON(http.StatusServiceUnavailable, v1.ErrorResponse{Error: "Price not available", Details: err.Error()})
+	default:
+		respondInternalError(c, err)
+	}
 }
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"ethereum-validator-api/api/models/v1"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// @Summary Get Chain Head
+// @Description Retrieves the current head slot and root, the justified and finalized checkpoints, and the current epoch and sync committee period
+// @Tags chain
+// @Param fields query string false "Comma separated fields to return, nested fields of objects and lists selected with dots"
+// @Param meta query bool false "Add a meta field reporting the providers that served the data, cache use, data age and finalization"
+// @Success 200 {object} v1.ChainHeadResponse "Returns the chain head and finality checkpoints"
+// @Failure 429 {object} v1.ErrorResponse "Upstream provider is rate limiting requests"
+// @Failure 502 {object} v1.ErrorResponse "Upstream returned an invalid response"
+// @Failure 500 {object} v1.ErrorResponse "Internal server error"
+// @Failure 503 {object} v1.ErrorResponse "Upstream providers not reached since startup or upstream node not synced"
+// @Failure 504 {object} v1.ErrorResponse "Upstream request timed out"
+// @Router /chain/head [get]
+func (h *Handler) GetChainHead(c *gin.Context) {
+	head, err := h.ethService.GetChainHead(c.Request.Context())
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	// Create response object
+	response := v1.ChainHeadResponse{
+		HeadSlot:          head.Slot,
+		HeadRoot:          head.Root,
+		Epoch:             head.Epoch,
+		SyncPeriod:        head.SyncPeriod,
+		PreviousJustified: v1.NewCheckpointResponse(head.PreviousJustified),
+		CurrentJustified:  v1.NewCheckpointResponse(head.CurrentJustified),
+		Finalized:         v1.NewCheckpointResponse(head.Finalized),
+	}
+
+	respondJSON(c, http.StatusOK, response)
+}
+
+// @Summary Get Chain Spec
+// @Description Passes through the beacon node's spec configuration so client tooling can auto-configure against the network the API is pointed at
+// @Tags chain
+// @Success 200 {object} v1.ChainSpecResponse "Returns the spec parameters"
+// @Failure 429 {object} v1.ErrorResponse "Upstream provider is rate limiting requests"
+// @Failure 502 {object} v1.ErrorResponse "Upstream returned an invalid response"
+// @Failure 500 {object} v1.ErrorResponse "Internal server error"
+// @Failure 503 {object} v1.ErrorResponse "Upstream providers not reached since startup or upstream node not synced"
+// @Failure 504 {object} v1.ErrorResponse "Upstream request timed out"
+// @Router /chain/spec [get]
+func (h *Handler) GetChainSpec(c *gin.Context) {
+	spec, err := h.ethService.GetChainSpec(c.Request.Context())
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.ChainSpecResponse{Data: spec})
+}
+
+// @Summary Get Fork Schedule
+// @Description Passes through the beacon node's fork schedule
+// @Tags chain
+// @Success 200 {object} v1.ForkScheduleResponse "Returns the fork schedule"
+// @Failure 429 {object} v1.ErrorResponse "Upstream provider is rate limiting requests"
+// @Failure 502 {object} v1.ErrorResponse "Upstream returned an invalid response"
+// @Failure 500 {object} v1.ErrorResponse "Internal server error"
+// @Failure 503 {object} v1.ErrorResponse "Upstream providers not reached since startup or upstream node not synced"
+// @Failure 504 {object} v1.ErrorResponse "Upstream request timed out"
+// @Router /chain/forks [get]
+func (h *Handler) GetForkSchedule(c *gin.Context) {
+	forks, err := h.ethService.GetForkSchedule(c.Request.Context())
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	// Create response object
+	response := v1.ForkScheduleResponse{
+		Forks: make([]v1.ForkResponse, 0, len(forks)),
+	}
+	for _, fork := range forks {
+		response.Forks = append(response.Forks, v1.ForkResponse{
+			PreviousVersion: fork.PreviousVersion,
+			CurrentVersion:  fork.CurrentVersion,
+			Epoch:           fork.Epoch,
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"errors"
+	"ethereum-validator-api/api/models/v1"
+	"ethereum-validator-api/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// Committees returned per page unless requested otherwise, and at most
+const (
+	defaultCommitteesPerPage = 16
+	maxCommitteesPerPage     = 64
+)
+
+// @Summary Get Committees
+// @Description Retrieves the beacon attestation committees of a slot (committee index to validator indices), paginated by committee since the payload is large
+// @Tags block
+// @Param slot path string true "Slot number in the Beacon Chain, or head, finalized, justified or genesis"
+// @Param offset query int false "Number of committees to skip (default 0)"
+// @Param limit query int false "Maximum number of committees (default 16, max 64)"
+// @Param fields query string false "Comma separated fields to return, nested fields of objects and lists selected with dots"
+// @Param meta query bool false "Add a meta field reporting the providers that served the data, cache use, data age and finalization"
+// @Param envelope query bool false "Return the committees of the page as data of the standard envelope, with pagination metadata and a link to the next page"
+// @Success 200 {object} v1.CommitteesResponse "Returns a page of committees, with envelope=true a v1.EnvelopeResponse"
+// @Failure 400 {object} v1.ErrorResponse "Invalid slot number or pagination"
+// @Failure 404 {object} v1.ErrorResponse "Slot not found in chain"
+// @Failure 429 {object} v1.ErrorResponse "Upstream provider is rate limiting requests"
+// @Failure 502 {object} v1.ErrorResponse "Upstream returned an invalid response"
+// @Failure 500 {object} v1.ErrorResponse "Internal server error"
+// @Failure 503 {object} v1.ErrorResponse "Upstream providers not reached since startup or upstream node not synced"
+// @Failure 504 {object} v1.ErrorResponse "Upstream request timed out"
+// @Router /committees/{slot} [get]
+func (h *Handler) GetCommittees(c *gin.Context) {
+	slot, ok := h.slotParam(c, slotTolerance)
+	if !ok {
+		return
+	}
+
+	p, ok := parsePage(c, defaultCommitteesPerPage, maxCommitteesPerPage)
+	if !ok {
+		return
+	}
+
+	committees, err := h.ethService.GetCommitteesBySlot(c.Request.Context(), slot)
+	if err != nil {
+		var statusCode int
+		var errMsg string
+
+		switch {
+		case errors.Is(err, service.ErrSlotNotFound):
+			statusCode = http.StatusNotFound
+			errMsg = "Slot does not exist"
+		default:
+			respondInternalError(c, err)
+			return
+		}
+
+		c.JSON(statusCode, v1.ErrorResponse{Error: errMsg})
+		return
+	}
+
+	// Create response object
+	response := v1.CommitteesResponse{
+		Slot:       slot,
+		Total:      len(committees),
+		Offset:     p.offset,
+		Committees: make([]v1.CommitteeResponse, 0, p.limit),
+	}
+	start, end := p.bounds(len(committees))
+	for _, committee := range committees[start:end] {
+		response.Committees = append(response.Committees, v1.CommitteeResponse{
+			Index:      committee.Index,
+			Validators: committee.Validators,
+		})
+	}
+
+	respondPage(c, http.StatusOK, response, response.Committees, p, len(committees))
+}
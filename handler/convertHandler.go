@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"errors"
+	"ethereum-validator-api/api/models/v1"
+	"ethereum-validator-api/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+// @Summary Convert Execution Block Number to Slot
+// @Description Returns the slot whose beacon block carried the execution block with the given number
+// @Tags convert
+// @Param number path int true "Execution block number"
+// @Param fields query string false "Comma separated fields to return, nested fields of objects and lists selected with dots"
+// @Param meta query bool false "Add a meta field reporting the providers that served the data, cache use, data age and finalization"
+// @Success 200 {object} v1.BlockNumberConversionResponse "Returns the slot of the execution block"
+// @Failure 400 {object} v1.ErrorResponse "Invalid block number"
+// @Failure 404 {object} v1.ErrorResponse "Block not found or not proposed on the beacon chain"
+// @Failure 429 {object} v1.ErrorResponse "Upstream provider is rate limiting requests"
+// @Failure 502 {object} v1.ErrorResponse "Upstream returned an invalid response"
+// @Failure 500 {object} v1.ErrorResponse "Internal server error"
+// @Failure 503 {object} v1.ErrorResponse "Upstream providers not reached since startup or upstream node not synced"
+// @Failure 504 {object} v1.ErrorResponse "Upstream request timed out"
+// @Router /convert/block/{number} [get]
+func (h *Handler) ConvertBlockNumber(c *gin.Context) {
+	number, err := strconv.ParseInt(c.Param("number"), 10, 64)
+	if err != nil || number < 0 {
+		c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: "Invalid block number", Details: "expected a non-negative decimal block number"})
+		return
+	}
+
+	slot, err := h.ethService.GetBlockNumberSlot(c.Request.Context(), number)
+	if err != nil {
+		if errors.Is(err, service.ErrSlotNotFound) {
+			c.JSON(http.StatusNotFound, v1.ErrorResponse{Error: "Block does not exist", Details: err.Error()})
+			return
+		}
+		respondInternalError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, v1.BlockNumberConversionResponse{Slot: slot, BlockNumber: number})
+}
+
+// @Summary Convert Slot to Execution Block Number
+// @Description Returns the number of the execution block carried by the beacon block of a slot
+// @Tags convert
+// @Param slot path string true "Slot number in the Beacon Chain, or head, finalized, justified or genesis"
+// @Param fields query string false "Comma separated fields to return, nested fields of objects and lists selected with dots"
+// @Param meta query bool false "Add a meta field reporting the providers that served the data, cache use, data age and finalization"
+// @Success 200 {object} v1.BlockNumberConversionResponse "Returns the execution block number of the slot"
+// @Failure 400 {object} v1.ErrorResponse "Invalid slot number or future slot"
+// @Failure 404 {object} v1.ErrorResponse "Slot not found in chain or without execution payload"
+// @Failure 429 {object} v1.ErrorResponse "Upstream provider is rate limiting requests"
+// @Failure 502 {object} v1.ErrorResponse "Upstream returned an invalid response"
+// @Failure 500 {object} v1.ErrorResponse "Internal server error"
+// @Failure 503 {object} v1.ErrorResponse "Upstream providers not reached since startup or upstream node not synced"
+// @Failure 504 {object} v1.ErrorResponse "Upstream request timed out"
+// @Router /convert/slot/{slot}/block [get]
+func (h *Handler) ConvertSlot(c *gin.Context) {
+	slot, ok := h.slotParam(c, slotTolerance)
+	if !ok {
+		return
+	}
+
+	number, err := h.ethService.GetExecutionBlockNumber(c.Request.Context(), slot)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrFutureSlot):
+			c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: "Slot is in the future"})
+		case errors.Is(err, service.ErrSlotNotFound):
+			c.JSON(http.StatusNotFound, v1.ErrorResponse{Error: "Slot does not exist", Details: err.Error()})
+		default:
+			respondInternalError(c, err)
+		}
+		return
+	}
+
+	respondJSON(c, http.StatusOK, v1.BlockNumberConversionResponse{Slot: slot, BlockNumber: number})
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"context"
+	"ethereum-validator-api/service"
+	"io"
+	"math/big"
+	"time"
+)
+
+// EthereumDataSource is the part of the Ethereum service the handlers use, so they can be tested against a mock
+type EthereumDataSource interface {
+	// Blocks and rewards
+	GetBlockRewardBySlot(ctx context.Context, slot int64) (*service.BlockReward, error)
+	GetBlockDetailBySlot(ctx context.Context, slot int64) (*service.BlockDetail, error)
+	GetBlockGas(ctx context.Context, slot int64) (*service.BlockGas, error)
+	GetTransactionRewardsBySlot(ctx context.Context, slot int64, top int) ([]service.TransactionReward, error)
+	GetBidComparisonBySlot(ctx context.Context, slot int64) (*service.BidComparison, error)
+	GetFiatValues(ctx context.Context, slot int64, wei *big.Int, currencies []string) ([]service.FiatValue, error)
+	GetExecutionBlockNumber(ctx context.Context, slot int64) (int64, error)
+	GetBlockNumberSlot(ctx context.Context, blockNumber int64) (int64, error)
+	ResolveExecutionBlock(ctx context.Context, id string) (*service.ExecutionBlockRef, error)
+	VerificationEnabled() bool
+	VerifyBlock(ctx context.Context, slot int64) (*service.Verification, error)
+	LightClientEnabled() bool
+	VerifyBlockRoot(ctx context.Context, slot int64, root string) (bool, error)
+
+	// Chain
+	GetChainHead(ctx context.Context) (*service.ChainHead, error)
+	GetChainSpec(ctx context.Context) (map[string]interface{}, error)
+	GetForkSchedule(ctx context.Context) ([]service.Fork, error)
+	GetCommitteesBySlot(ctx context.Context, slot int64) ([]service.Committee, error)
+	GetSyncCommitteeBySlot(ctx context.Context, slot int64) (*service.SyncCommittee, error)
+	GetNextSyncCommittee(ctx context.Context) (*service.SyncCommittee, error)
+	GetValidatorQueue(ctx context.Context) (*service.ValidatorQueue, error)
+
+	// Nodes
+	GetNodeSyncing(ctx context.Context) (*service.NodeSyncing, error)
+	GetNodeVersion(ctx context.Context) (*service.NodeVersion, error)
+	GetNodePeers(ctx context.Context) (*service.NodePeers, error)
+
+	// Validators
+	GetValidatorStatus(ctx context.Context, validatorID string, asOfSlot *int64) (*service.ValidatorStatus, error)
+	GetValidatorsSummary(ctx context.Context, validatorIDs []string) (*service.ValidatorsSummary, error)
+	GetValidatorDuties(ctx context.Context, validatorID string, epochs int64) ([]service.ValidatorDuty, error)
+	GetValidatorAttestations(ctx context.Context, validatorID string, epochs int64) ([]*service.AttestationPerformance, error)
+	StreamValidatorAttestations(ctx context.Context, validatorID string, epochs int64, emit func(*service.AttestationPerformance) error) error
+	GetValidatorEffectiveness(ctx context.Context, validatorID string, epochs int64) (*service.ValidatorEffectiveness, error)
+	GetValidatorAPR(ctx context.Context, validatorIDs []string, window time.Duration) (*service.ValidatorAPR, error)
+	GetValidatorForecast(ctx context.Context, validatorID string, period time.Duration) (*service.RewardsForecast, error)
+	GetSyncCommitteeProbability(ctx context.Context, validatorID string, periods int64) (*service.SyncCommitteeProbability, error)
+	GetValidatorProposals(ctx context.Context, validatorID string, fromEpoch, toEpoch *int64) (*service.ValidatorProposals, error)
+	StreamValidatorProposals(ctx context.Context, validatorID string, fromEpoch, toEpoch *int64, emit func(service.ValidatorProposal) error) (*service.ValidatorProposals, error)
+	GetValidatorProposalsPage(ctx context.Context, validatorID string, cursor, fromEpoch, toEpoch *int64, limit int) (*service.ValidatorProposalsPage, error)
+	GetValidatorBlockRewardBySlot(ctx context.Context, validatorID string, slot int64) (*service.ValidatorBlockReward, error)
+	GetValidatorLatestBlockReward(ctx context.Context, validatorID string) (*service.ValidatorBlockReward, error)
+	CheckFeeRecipient(ctx context.Context, validatorID, expected string, fromEpoch, toEpoch *int64) (*service.FeeRecipientCheck, error)
+
+	// Statistics
+	GetNetworkStats() (*service.NetworkStats, error)
+	GetNetworkAPR(ctx context.Context, window time.Duration) (*service.NetworkAPR, error)
+	GetMEVStats(fromEpoch, toEpoch int64) (*service.MEVStats, error)
+	GetRewardStats(window time.Duration) (*service.RewardStats, error)
+	GetBaseFeeSeries(window time.Duration) (*service.BaseFeeSeries, error)
+	Metrics() []string
+	GetMetricSeries(ctx context.Context, metric string, from, to time.Time, maxPoints int) ([]service.MetricPoint, error)
+
+	// Jobs
+	SubmitBlockRewardJob(client string, fromSlot, toSlot int64, idempotencyKey string) (job *service.BlockRewardJob, created bool, err error)
+	GetBlockRewardJob(id string) (*service.BlockRewardJob, error)
+
+	// Administration
+	Now() time.Time
+	Index() *service.BlockIndex
+	Builders() *service.BuilderRegistry
+	Usage() *service.UsageTracker
+	Audit() *service.AuditLog
+	Watchlists() *service.WatchlistStore
+	UpstreamCapture() *service.UpstreamCapture
+	CacheStats() service.CacheStats
+	ClearCache() int
+	InvalidateSlot(slot int64) int
+	UpstreamsAvailable() (bool, error)
+	GetUpstreamsHealth(ctx context.Context) *service.UpstreamsHealth
+	WriteSupportBundle(ctx context.Context, w io.Writer, logs *service.LogBuffer) error
+}
+
+var _ EthereumDataSource = (*service.EthereumService)(nil)
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"ethereum-validator-api/api/models/v1"
+	"ethereum-validator-api/service"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultDutyEpochs is the look-ahead window used when epochs is not given
+const defaultDutyEpochs = 2
+
+// @Summary Get Validator Duties Calendar
+// @Description Produces an iCalendar feed of the upcoming proposal and sync committee windows of a validator, so it can be subscribed to from calendar apps. Proposals are only known for the current and next epoch.
+// @Tags validator
+// @Produce text/calendar
+// @Param id path string true "Validator index or pubkey"
+// @Param epochs query int false "Number of epochs to look ahead (default 2, max 512)"
+// @Success 200 {string} string "iCalendar feed"
+// @Failure 400 {object} v1.ErrorResponse "Invalid validator or epochs"
+// @Failure 404 {object} v1.ErrorResponse "Validator not found"
+// @Failure 429 {object} v1.ErrorResponse "Upstream provider is rate limiting requests"
+// @Failure 502 {object} v1.ErrorResponse "Upstream returned an invalid response"
+// @Failure 500 {object} v1.ErrorResponse "Internal server error"
+// @Failure 503 {object} v1.ErrorResponse "Upstream providers not reached since startup or upstream node not synced"
+// @Failure 504 {object} v1.ErrorResponse "Upstream request timed out"
+// @Router /validator/{id}/duties.ics [get]
+func (h *Handler) GetValidatorDutiesCalendar(c *gin.Context) {
+	epochs := int64(defaultDutyEpochs)
+	if epochsParam, ok := c.GetQuery("epochs"); ok {
+		parsed, err := strconv.ParseInt(epochsParam, 10, 64)
+		if err != nil || parsed < 1 || parsed > service.MaxDutyEpochs {
+			c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: fmt.Sprintf("epochs must be between 1 and %d", service.MaxDutyEpochs)})
+			return
+		}
+		epochs = parsed
+	}
+
+	duties, err := h.ethService.GetValidatorDuties(c.Request.Context(), c.Param("id"), epochs)
+	if err != nil {
+		respondValidatorError(c, err)
+		return
+	}
+
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(renderDutiesCalendar(c.Param("id"), duties, time.Now())))
+}
+
+// renderDutiesCalendar renders duties as an RFC 5545 calendar
+func renderDutiesCalendar(validatorID string, duties []service.ValidatorDuty, now time.Time) string {
+	const stampFormat = "20060102T150405Z"
+
+	var b strings.Builder
+	b.WriteString("BEGIN:VCALENDAR\r\n")
+	b.WriteString("VERSION:2.0\r\n")
+	b.WriteString("PRODID:-//ethereum-validator-api//duties//EN\r\n")
+	b.WriteString("CALSCALE:GREGORIAN\r\n")
+	fmt.Fprintf(&b, "X-WR-CALNAME:Validator %s duties\r\n", validatorID)
+
+	for _, duty := range duties {
+		var summary string
+		switch duty.Kind {
+		case service.DutyProposal:
+			summary = fmt.Sprintf("Block proposal (slot %d)", duty.StartSlot)
+		case service.DutySyncCommittee:
+			summary = fmt.Sprintf("Sync committee (slots %d-%d)", duty.StartSlot, duty.EndSlot)
+		}
+
+		b.WriteString("BEGIN:VEVENT\r\n")
+		fmt.Fprintf(&b, "UID:%s-%s-%d@ethereum-validator-api\r\n", validatorID, duty.Kind, duty.StartSlot)
+		fmt.Fprintf(&b, "DTSTAMP:%s\r\n", now.UTC().Format(stampFormat))
+		fmt.Fprintf(&b, "DTSTART:%s\r\n", duty.Start.UTC().Format(stampFormat))
+		fmt.Fprintf(&b, "DTEND:%s\r\n", duty.End.UTC().Format(stampFormat))
+		fmt.Fprintf(&b, "SUMMARY:%s\r\n", summary)
+		b.WriteString("END:VEVENT\r\n")
+	}
+
+	b.WriteString("END:VCALENDAR\r\n")
+	return b.String()
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"ethereum-validator-api/api/models/v1"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"reflect"
+	"strconv"
+)
+
+// page is the part of a list requested with the offset and limit query parameters
+type page struct {
+	offset int
+	limit  int
+}
+
+// parsePage parses the offset and limit query parameters, limit defaulting to defaultLimit and bounded by maxLimit.
+// Invalid values are answered with 400
+func parsePage(c *gin.Context, defaultLimit, maxLimit int) (page, bool) {
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: "Invalid offset"})
+		return page{}, false
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultLimit)))
+	if err != nil || limit <= 0 || limit > maxLimit {
+		c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: "Invalid limit", Details: "use 1 to " + strconv.Itoa(maxLimit)})
+		return page{}, false
+	}
+	return page{offset: offset, limit: limit}, true
+}
+
+// bounds returns the start and end of the page within a list of total entries
+func (p page) bounds(total int) (int, int) {
+	start := min(p.offset, total)
+	return start, min(start+p.limit, total)
+}
+
+// envelopeRequested reports whether list entries were requested in the standard envelope with envelope=true
+func envelopeRequested(c *gin.Context) bool {
+	return c.Query("envelope") == "true"
+}
+
+// respondPage writes a page of a list endpoint. With envelope=true its entries are wrapped in the standard envelope
+// with the pagination and links to the next page, selected fields then apply to the entries. Otherwise the endpoint's
+// own response is written like respondJSON does
+func respondPage(c *gin.Context, statusCode int, response interface{}, entries interface{}, p page, total int) {
+	if !envelopeRequested(c) {
+		respondJSON(c, statusCode, response)
+		return
+	}
+
+	data := entries
+	if param, selected := c.GetQuery("fields"); selected {
+		fields, ok := selectedFields(c, param, reflect.TypeOf(entries))
+		if !ok {
+			return
+		}
+		decoded, err := decodedJSON(entries)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, v1.ErrorResponse{Error: "Internal server error"})
+			return
+		}
+		data = fields.project(decoded)
+	}
+
+	start, end := p.bounds(total)
+	envelope := v1.EnvelopeResponse{
+		Data: data,
+		Meta: v1.EnvelopeMetaResponse{Pagination: v1.PaginationResponse{
+			Offset: p.offset,
+			Limit:  p.limit,
+			Count:  end - start,
+			Total:  total,
+		}},
+		Links: v1.EnvelopeLinksResponse{Self: pageLink(c, p.offset, p.limit)},
+	}
+	if end < total {
+		envelope.Links.Next = pageLink(c, end, p.limit)
+	}
+	if metaRequested(c) {
+		meta := newMetaResponse(c)
+		envelope.Meta.Provenance = &meta
+	}
+	c.JSON(statusCode, envelope)
+}
+
+// pageLink returns the path and query of the request with the given page
+func pageLink(c *gin.Context, offset, limit int) string {
+	query := c.Request.URL.Query()
+	query.Set("offset", strconv.Itoa(offset))
+	query.Set("limit", strconv.Itoa(limit))
+	return c.Request.URL.Path + "?" + query.Encode()
+}
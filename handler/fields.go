@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"ethereum-validator-api/api/models/v1"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// fieldSet is a parsed sparse fieldset, mapping the JSON names of the selected fields to the selected fields of their
+// value. An empty fieldSet selects the whole value
+type fieldSet map[string]fieldSet
+
+// parseFields parses a comma separated list of fields, where nested fields of objects and of the entries of lists
+// are selected with dots, e.g. "proposed,proposals.slot,proposals.reward"
+func parseFields(param string) (fieldSet, error) {
+	fields := fieldSet{}
+	for _, path := range strings.Split(param, ",") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+
+		current := fields
+		for _, name := range strings.Split(path, ".") {
+			if name == "" {
+				return nil, fmt.Errorf("field %q is malformed", path)
+			}
+			if current[name] == nil {
+				current[name] = fieldSet{}
+			}
+			current = current[name]
+		}
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no fields selected")
+	}
+	return fields, nil
+}
+
+// validate checks that every selected field exists in the JSON representation of the given type
+func (f fieldSet) validate(t reflect.Type, prefix string) error {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return fmt.Errorf("field %q has no nested fields", strings.TrimSuffix(prefix, "."))
+	}
+
+	known := jsonFields(t)
+	names := make([]string, 0, len(f))
+	for name := range f {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fieldType, ok := known[name]
+		if !ok {
+			return fmt.Errorf("unknown field %q", prefix+name)
+		}
+		if len(f[name]) > 0 {
+			if err := f[name].validate(fieldType, prefix+name+"."); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// jsonFields returns the types of the fields of a struct keyed by their JSON name, including promoted fields of
+// embedded structs
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			for promoted, fieldType := range jsonFields(field.Type) {
+				fields[promoted] = fieldType
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
+
+// project reduces a decoded JSON value to the selected fields, applying nested selections to each entry of lists
+func (f fieldSet) project(value interface{}) interface{} {
+	if len(f) == 0 {
+		return value
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		projected := make(map[string]interface{}, len(f))
+		for name, nested := range f {
+			if fieldValue, ok := v[name]; ok {
+				projected[name] = nested.project(fieldValue)
+			}
+		}
+		return projected
+	case []interface{}:
+		projected := make([]interface{}, 0, len(v))
+		for _, entry := range v {
+			projected = append(projected, f.project(entry))
+		}
+		return projected
+	default:
+		return value
+	}
+}
+
+// respondJSON writes a JSON response, reduced to the fields selected with the fields query parameter when given.
+// Unknown fields are rejected with 400 so typos don't go unnoticed as silently missing data. With meta=true, the
+// provenance of the data is added to object responses in a meta field
+func respondJSON(c *gin.Context, statusCode int, response interface{}) {
+	param, selected := c.GetQuery("fields")
+	meta := metaRequested(c)
+	if !selected && !meta {
+		c.JSON(statusCode, response)
+		return
+	}
+
+	var fields fieldSet
+	if selected {
+		var ok bool
+		if fields, ok = selectedFields(c, param, reflect.TypeOf(response)); !ok {
+			return
+		}
+	}
+
+	decoded, err := decodedJSON(response)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, v1.ErrorResponse{Error: "Internal server error"})
+		return
+	}
+
+	projected := fields.project(decoded)
+	if object, ok := projected.(map[string]interface{}); ok && meta {
+		object["meta"] = newMetaResponse(c)
+	}
+	c.JSON(statusCode, projected)
+}
+
+// selectedFields parses the fields query parameter and checks the fields exist in the given type, answering 400 when
+// they don't
+func selectedFields(c *gin.Context, param string, t reflect.Type) (fieldSet, bool) {
+	fields, err := parseFields(param)
+	if err == nil {
+		err = fields.validate(t, "")
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: "Invalid fields", Details: err.Error()})
+		return nil, false
+	}
+	return fields, true
+}
+
+// decodedJSON returns the generic JSON representation of a response, numbers decoded as json.Number so large amounts
+// keep their exact value
+func decodedJSON(response interface{}) (interface{}, error) {
+	encoded, err := json.Marshal(response)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	var decoded interface{}
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, err
+	}
+	return decoded, nil
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"errors"
+	"ethereum-validator-api/api/models/v1"
+	"ethereum-validator-api/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strings"
+)
+
+// @Summary Grafana Connection Test
+// @Description Answers the connection test of the Grafana JSON (simple-json) and Infinity datasources
+// @Tags grafana
+// @Success 200 "The datasource is reachable"
+// @Failure 503 {object} v1.ErrorResponse "Upstream providers not reached since startup"
+// @Router /grafana [get]
+func (h *Handler) GrafanaTest(c *gin.Context) {
+	c.Status(http.StatusOK)
+}
+
+// @Summary Search Grafana Metrics
+// @Description Lists the time series that can be queried: block_reward (ETH), mev_share, base_fee (Gwei), gas_utilization (%), sync_participation and attestation_reward:<validator index> (Gwei per epoch) for each validator with indexed attestations
+// @Tags grafana
+// @Accept json
+// @Param request body v1.GrafanaSearchRequest false "Substring the metric names must contain"
+// @Success 200 {array} string "Returns the metric names"
+// @Failure 400 {object} v1.ErrorResponse "Invalid request body"
+// @Failure 503 {object} v1.ErrorResponse "Upstream providers not reached since startup"
+// @Router /grafana/search [post]
+func (h *Handler) GrafanaSearch(c *gin.Context) {
+	var request v1.GrafanaSearchRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: "Invalid request body", Details: err.Error()})
+			return
+		}
+	}
+
+	metrics := make([]string, 0)
+	for _, metric := range h.ethService.Metrics() {
+		if strings.Contains(metric, request.Target) {
+			metrics = append(metrics, metric)
+		}
+	}
+	c.JSON(http.StatusOK, metrics)
+}
+
+// @Summary Query Grafana Metrics
+// @Description Returns the requested time series within the time range of a Grafana panel, built from the block, reward and attestation index. Series with more points than maxDataPoints are averaged into buckets of equal duration
+// @Tags grafana
+// @Accept json
+// @Param request body v1.GrafanaQueryRequest true "Time range and targets of the panel"
+// @Success 200 {array} v1.GrafanaSeriesResponse "Returns one series per target, or a v1.GrafanaTableResponse for targets of type table"
+// @Failure 400 {object} v1.ErrorResponse "Invalid request body, time range or unknown metric"
+// @Failure 429 {object} v1.ErrorResponse "Upstream provider is rate limiting requests"
+// @Failure 502 {object} v1.ErrorResponse "Upstream returned an invalid response"
+// @Failure 500 {object} v1.ErrorResponse "Internal server error"
+// @Failure 503 {object} v1.ErrorResponse "Upstream providers not reached since startup or upstream node not synced"
+// @Failure 504 {object} v1.ErrorResponse "Upstream request timed out"
+// @Router /grafana/query [post]
+func (h *Handler) GrafanaQuery(c *gin.Context) {
+	var request v1.GrafanaQueryRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: "Invalid request body", Details: err.Error()})
+		return
+	}
+
+	response := make([]interface{}, 0, len(request.Targets))
+	for _, target := range request.Targets {
+		if target.Target == "" {
+			continue
+		}
+		points, err := h.ethService.GetMetricSeries(c.Request.Context(), target.Target, request.Range.From, request.Range.To, request.MaxDataPoints)
+		if err != nil {
+			switch {
+			case errors.Is(err, service.ErrUnknownMetric):
+				c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: "Unknown metric", Details: err.Error()})
+			case errors.Is(err, service.ErrInvalidRange):
+				c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: "Invalid time range"})
+			default:
+				respondInternalError(c, err)
+			}
+			return
+		}
+
+		if target.Type == "table" {
+			table := v1.GrafanaTableResponse{
+				Type:    "table",
+				RefID:   target.RefID,
+				Columns: []v1.GrafanaColumnResponse{{Text: "Time", Type: "time"}, {Text: target.Target, Type: "number"}},
+				Rows:    make([][2]float64, 0, len(points)),
+			}
+			for _, point := range points {
+				table.Rows = append(table.Rows, [2]float64{float64(point.Time.UnixMilli()), point.Value})
+			}
+			response = append(response, table)
+			continue
+		}
+		series := v1.GrafanaSeriesResponse{Target: target.Target, RefID: target.RefID, Datapoints: make([][2]float64, 0, len(points))}
+		for _, point := range points {
+			series.Datapoints = append(series.Datapoints, [2]float64{point.Value, float64(point.Time.UnixMilli())})
+		}
+		response = append(response, series)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
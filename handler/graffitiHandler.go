@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"ethereum-validator-api/api/models/v1"
+	"github.com/gin-gonic/gin"
+	"math"
+	"net/http"
+	"strconv"
+)
+
+// maxGraffitiResults bounds the number of matches returned by a graffiti search
+const maxGraffitiResults = 1000
+
+// @Summary Search Graffiti
+// @Description Searches the graffiti of indexed blocks (case-insensitive substring match) within an optional slot range
+// @Tags block
+// @Param q query string true "Graffiti text to search for"
+// @Param from query int false "First slot of the search range"
+// @Param to query int false "Last slot of the search range"
+// @Param limit query int false "Maximum number of results (default 100, max 1000)"
+// @Produce json,application/x-ndjson
+// @Param fields query string false "Comma separated fields to return, nested fields of objects and lists selected with dots"
+// @Param meta query bool false "Add a meta field reporting the providers that served the data, cache use, data age and finalization"
+// @Success 200 {object} v1.GraffitiSearchResponse "Returns the matching blocks. With Accept: application/x-ndjson one v1.GraffitiMatch per line"
+// @Failure 400 {object} v1.ErrorResponse "Missing query or invalid range"
+// @Failure 503 {object} v1.ErrorResponse "Upstream providers not reached since startup"
+// @Router /graffiti/search [get]
+func (h *Handler) SearchGraffiti(c *gin.Context) {
+	query := c.Query("q")
+	if query == "" {
+		c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: "Query parameter q is required"})
+		return
+	}
+
+	from, err := strconv.ParseInt(c.DefaultQuery("from", "0"), 10, 64)
+	if err != nil || from < 0 {
+		c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: "Invalid from slot"})
+		return
+	}
+
+	to, err := strconv.ParseInt(c.DefaultQuery("to", strconv.FormatInt(math.MaxInt64, 10)), 10, 64)
+	if err != nil || to < from {
+		c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: "Invalid to slot"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || limit <= 0 || limit > maxGraffitiResults {
+		c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: "Invalid limit"})
+		return
+	}
+
+	blocks := h.ethService.Index().SearchGraffiti(query, from, to)
+	if len(blocks) > limit {
+		blocks = blocks[:limit]
+	}
+
+	if wantsNDJSON(c) {
+		stream := newNDJSONStream(c)
+		for _, block := range blocks {
+			if err := stream.Write(v1.NewGraffitiMatch(block)); err != nil {
+				break
+			}
+		}
+		stream.Close(nil, nil)
+		return
+	}
+
+	// Create response object
+	response := v1.GraffitiSearchResponse{
+		Query:   query,
+		Count:   len(blocks),
+		Results: make([]v1.GraffitiMatch, 0, len(blocks)),
+	}
+	for _, block := range blocks {
+		response.Results = append(response.Results, v1.NewGraffitiMatch(block))
+	}
+
+	respondJSON(c, http.StatusOK, response)
+}
@@ -1,15 +1,36 @@
 package handler
 
-import "ethereum-validator-api/service"
+import (
+	"context"
+	"ethereum-validator-api/service"
+)
 
 // Handler manages HTTP request handling and coordinates with the Ethereum service
 type Handler struct {
-	ethService *service.EthereumService
+	ethService EthereumDataSource
+	logs       *service.LogBuffer
+	scheduler  *service.Scheduler
+	reload     func(ctx context.Context) ([]string, error)
 }
 
-// NewHandler creates a new Handler instance with the provided Ethereum service
-func NewHandler(ethService *service.EthereumService) *Handler {
+// NewHandler creates a new Handler instance with the provided Ethereum service, or any other data source
+func NewHandler(ethService EthereumDataSource) *Handler {
 	return &Handler{
 		ethService: ethService,
 	}
 }
+
+// SetLogBuffer sets the buffer of recent log lines included in support bundles
+func (h *Handler) SetLogBuffer(logs *service.LogBuffer) {
+	h.logs = logs
+}
+
+// SetScheduler sets the scheduler whose tasks are reported by the admin endpoints
+func (h *Handler) SetScheduler(scheduler *service.Scheduler) {
+	h.scheduler = scheduler
+}
+
+// SetReloader sets the function reloading the configuration on admin request, returning the settings it applied
+func (h *Handler) SetReloader(reload func(ctx context.Context) ([]string, error)) {
+	h.reload = reload
+}
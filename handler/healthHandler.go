@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"ethereum-validator-api/api/models/v1"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// @Summary Get Upstream Health
+// @Description Reports whether the upstream providers were reached and the detected client of the beacon node, with the workarounds applied for its known quirks
+// @Tags health
+// @Success 200 {object} v1.UpstreamsHealthResponse "Returns the upstream health, the providers were reached"
+// @Failure 503 {object} v1.UpstreamsHealthResponse "Upstream providers not reached since startup"
+// @Router /health/upstreams [get]
+func (h *Handler) GetUpstreamsHealth(c *gin.Context) {
+	health := h.ethService.GetUpstreamsHealth(c.Request.Context())
+
+	// Create response object
+	response := v1.UpstreamsHealthResponse{
+		Available: health.Available,
+		Beacon: v1.BeaconNodeResponse{
+			Version: health.BeaconVersion,
+			Client:  string(health.BeaconClient),
+			Shims:   health.Shims,
+		},
+	}
+
+	status := http.StatusOK
+	if !health.Available {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, response)
+}
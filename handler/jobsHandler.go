@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"errors"
+	"ethereum-validator-api/api/models/v1"
+	"ethereum-validator-api/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// @Summary Submit Block Reward Job
+// @Description Enqueues the computation of the block rewards of a slot range of up to 10000 slots. Requests of a client repeated with the same Idempotency-Key header return the job created first instead of starting another one. Each client may have 2 unfinished jobs
+// @Tags jobs
+// @Param Idempotency-Key header string false "Client chosen key identifying the request"
+// @Param request body v1.BlockRewardJobRequest true "Slot range"
+// @Param unit query string false "Unit of the reward amounts: wei, gwei (default) or eth"
+// @Param fields query string false "Comma separated fields to return, nested fields of objects and lists selected with dots"
+// @Param meta query bool false "Add a meta field reporting the providers that served the data, cache use, data age and finalization"
+// @Success 202 {object} v1.BlockRewardJobResponse "Job was created, poll the Location header for progress"
+// @Success 200 {object} v1.BlockRewardJobResponse "Job created earlier with the same idempotency key"
+// @Failure 400 {object} v1.ErrorResponse "Invalid slot range"
+// @Failure 409 {object} v1.ErrorResponse "Idempotency key was used for a different slot range"
+// @Failure 429 {object} v1.ErrorResponse "The client has too many unfinished jobs or the job queue is full"
+// @Failure 503 {object} v1.ErrorResponse "Upstream providers not reached since startup"
+// @Router /jobs/blockrewards [post]
+func (h *Handler) PostBlockRewardJob(c *gin.Context) {
+	unit, ok := parseUnit(c)
+	if !ok {
+		return
+	}
+
+	var request v1.BlockRewardJobRequest
+	if err := c.ShouldBindJSON(&request); err != nil || request.FromSlot == nil || request.ToSlot == nil {
+		c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: "Invalid request body", Details: "from_slot and to_slot are required"})
+		return
+	}
+
+	job, created, err := h.ethService.SubmitBlockRewardJob(requestClient(c), *request.FromSlot, *request.ToSlot, c.GetHeader("Idempotency-Key"))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidRange):
+			c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: "Invalid slot range", Details: err.Error()})
+		case errors.Is(err, service.ErrIdempotencyConflict):
+			c.JSON(http.StatusConflict, v1.ErrorResponse{Error: "Idempotency key was used for a different slot range"})
+		case errors.Is(err, service.ErrTooManyJobs):
+			c.Header("Retry-After", "60")
+			c.JSON(http.StatusTooManyRequests, v1.ErrorResponse{Error: "Too many unfinished jobs, retry once a job finished"})
+		case errors.Is(err, service.ErrJobQueueFull):
+			c.Header("Retry-After", "60")
+			c.JSON(http.StatusTooManyRequests, v1.ErrorResponse{Error: "Job queue is full, retry later"})
+		default:
+			c.JSON(http.StatusInternalServerError, v1.ErrorResponse{Error: "Internal server error"})
+		}
+		return
+	}
+
+	c.Header("Location", "/jobs/"+job.ID)
+	statusCode := http.StatusOK
+	if created {
+		statusCode = http.StatusAccepted
+	}
+	respondJSON(c, statusCode, v1.NewBlockRewardJobResponse(job, unit))
+}
+
+// @Summary Get Job
+// @Description Reports the progress of a block reward job and the rewards computed so far. Finished jobs are kept for 24 hours
+// @Tags jobs
+// @Param id path string true "Job ID"
+// @Param unit query string false "Unit of the reward amounts: wei, gwei (default) or eth"
+// @Param fields query string false "Comma separated fields to return, nested fields of objects and lists selected with dots"
+// @Param meta query bool false "Add a meta field reporting the providers that served the data, cache use, data age and finalization"
+// @Param offset query int false "Number of results to skip (default 0)"
+// @Param limit query int false "Maximum number of results to return (default and max 10000)"
+// @Param envelope query bool false "Return the results of the page as data of the standard envelope, with pagination metadata and a link to the next page"
+// @Success 200 {object} v1.BlockRewardJobResponse "Returns the job status and results, with envelope=true a v1.EnvelopeResponse of the results"
+// @Failure 400 {object} v1.ErrorResponse "Invalid unit or pagination"
+// @Failure 404 {object} v1.ErrorResponse "Job not found"
+// @Failure 503 {object} v1.ErrorResponse "Upstream providers not reached since startup"
+// @Router /jobs/{id} [get]
+func (h *Handler) GetJob(c *gin.Context) {
+	unit, ok := parseUnit(c)
+	if !ok {
+		return
+	}
+	p, ok := parsePage(c, service.MaxJobSlots, service.MaxJobSlots)
+	if !ok {
+		return
+	}
+
+	job, err := h.ethService.GetBlockRewardJob(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, v1.ErrorResponse{Error: "Job not found"})
+		return
+	}
+
+	response := v1.NewBlockRewardJobResponse(job, unit)
+	total := len(response.Results)
+	start, end := p.bounds(total)
+	response.Results = response.Results[start:end]
+	respondPage(c, http.StatusOK, response, response.Results, p, total)
+}
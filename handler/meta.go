@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"ethereum-validator-api/api/models/v1"
+	"ethereum-validator-api/service"
+	"github.com/gin-gonic/gin"
+)
+
+// Provenance returns a middleware recording the provenance of the data of requests asking for it with meta=true
+func Provenance() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if metaRequested(c) {
+			ctx, _ := service.WithProvenance(c.Request.Context())
+			c.Request = c.Request.WithContext(ctx)
+		}
+		c.Next()
+	}
+}
+
+// metaRequested reports whether the provenance of the data was requested with meta=true
+func metaRequested(c *gin.Context) bool {
+	return c.Query("meta") == "true"
+}
+
+// newMetaResponse summarizes the provenance recorded for the request
+func newMetaResponse(c *gin.Context) v1.MetaResponse {
+	return v1.NewMetaResponse(service.ProvenanceFrom(c.Request.Context()).Summary())
+}
@@ -0,0 +1,290 @@
+package handler
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"ethereum-validator-api/api/models/v1"
+	"ethereum-validator-api/service"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// APIKeyAuth returns a middleware that only lets requests carrying the given key through,
+// either in the X-API-Key header or as a bearer token
+func APIKeyAuth(apiKey string) gin.HandlerFunc {
+	return Authenticate(apiKey, nil)
+}
+
+// Authenticate returns a middleware that only lets requests through that carry the given API key like APIKeyAuth
+// expects, or a bearer JWT accepted by verifier. Either may be unset to only accept the other. The subject of an
+// accepted JWT identifies the client in the usage and the audit log
+func Authenticate(apiKey string, verifier *service.JWTVerifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := requestAPIKey(c)
+		if apiKey != "" && key != "" && subtle.ConstantTimeCompare([]byte(key), []byte(apiKey)) == 1 {
+			c.Set(apiKeyValidKey, true)
+			c.Next()
+			return
+		}
+
+		if token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); ok && verifier != nil {
+			claims, err := verifier.Verify(c.Request.Context(), token)
+			if err == nil {
+				c.Set(tokenSubjectKey, claims.Subject)
+				c.Next()
+				return
+			}
+			if !errors.Is(err, service.ErrInvalidToken) {
+				slog.Error("Failed to verify token", "error", err)
+			}
+		}
+
+		c.AbortWithStatusJSON(http.StatusUnauthorized, v1.ErrorResponse{Error: "Unauthorized"})
+	}
+}
+
+// AccountAuth returns a middleware that only lets requests through carrying the API key of a watchlist account, in
+// the X-API-Key header or as a bearer token. The watchlist endpoints act on the account of that key only
+func (h *Handler) AccountAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if key := requestAPIKey(c); key != "" && h.ethService.Watchlists().HasAccount(key) {
+			c.Set(apiKeyValidKey, true)
+			c.Next()
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusUnauthorized, v1.ErrorResponse{Error: "Unauthorized"})
+	}
+}
+
+// tokenSubjectKey is the gin context key holding the subject of the JWT a request was authenticated with
+const tokenSubjectKey = "token_subject"
+
+// apiKeyValidKey is the gin context key set once the API key of a request was validated
+const apiKeyValidKey = "api_key_valid"
+
+// requestAPIKey returns the API key of a request, given in the X-API-Key header or as a bearer token
+func requestAPIKey(c *gin.Context) string {
+	if key := c.GetHeader("X-API-Key"); key != "" {
+		return key
+	}
+	return strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+}
+
+// requestClient identifies the client of a request by the subject of its JWT or its API key, once validated by the
+// authentication middleware, or else by its IP address. Unvalidated keys are ignored, so clients can't make up
+// identities by sending arbitrary keys
+func requestClient(c *gin.Context) string {
+	if subject := c.GetString(tokenSubjectKey); subject != "" {
+		return "sub:" + subject
+	}
+	if c.GetBool(apiKeyValidKey) {
+		return service.UsageClient(requestAPIKey(c), c.ClientIP())
+	}
+	return service.UsageClient("", c.ClientIP())
+}
+
+// TrackUsage returns a middleware counting the requests of every client to every endpoint, clients are identified by
+// the subject of their JWT, their validated API key or their IP address
+func (h *Handler) TrackUsage() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		// Requests to unknown paths aren't counted so scanners can't grow the usage without bound
+		if c.FullPath() == "" {
+			return
+		}
+		client := requestClient(c)
+		h.ethService.Usage().Record(client, c.Request.Method+" "+c.FullPath(), h.ethService.Now(), c.Writer.Status() >= http.StatusBadRequest)
+	}
+}
+
+// requestIDKey is the gin context key holding the ID of a request
+const requestIDKey = "request_id"
+
+// validRequestID matches client supplied request IDs that are safe to log and echo
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+// RequestID returns a middleware assigning every request an ID, taken from a well-formed X-Request-ID header
+// or generated, and returning it in the X-Request-ID response header
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader("X-Request-ID")
+		if !validRequestID.MatchString(id) {
+			id = newRequestID()
+		}
+
+		c.Set(requestIDKey, id)
+		c.Header("X-Request-ID", id)
+		c.Next()
+	}
+}
+
+// newRequestID generates a random request ID
+func newRequestID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Recovery returns a middleware turning panics into RFC 7807 500 responses carrying the request ID.
+// The stack trace is logged and the panic counted in the health summary
+func Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			// Let the server abort the response, as the standard library does
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+
+			requestID := c.GetString(requestIDKey)
+			service.RecordPanic()
+			slog.Error("Recovered from panic",
+				"request_id", requestID,
+				"method", c.Request.Method,
+				"path", c.Request.URL.Path,
+				"panic", fmt.Sprint(recovered),
+				"stack", string(debug.Stack()),
+			)
+
+			// The status line can't be changed once the handler started writing
+			if c.Writer.Written() {
+				c.Abort()
+				return
+			}
+			c.Header("Content-Type", "application/problem+json")
+			c.AbortWithStatusJSON(http.StatusInternalServerError, v1.ProblemResponse{
+				Type:      "about:blank",
+				Title:     http.StatusText(http.StatusInternalServerError),
+				Status:    http.StatusInternalServerError,
+				Detail:    "The server failed to handle the request",
+				Instance:  c.Request.URL.Path,
+				RequestID: requestID,
+			})
+		}()
+		c.Next()
+	}
+}
+
+// SecurityHeaders returns a middleware setting headers that keep browsers from sniffing content types, framing
+// responses or leaking the URL in the Referer header. Strict-Transport-Security is sent on TLS connections, and on
+// all requests with hsts for deployments behind a proxy terminating TLS
+func SecurityHeaders(hsts bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("X-Content-Type-Options", "nosniff")
+		c.Header("X-Frame-Options", "DENY")
+		c.Header("Referrer-Policy", "no-referrer")
+		if hsts || c.Request.TLS != nil {
+			c.Header("Strict-Transport-Security", "max-age=31536000; includeSubDomains")
+		}
+		c.Next()
+	}
+}
+
+// LimitBody returns a middleware rejecting request bodies larger than maxBytes with 413. Bodies without a declared
+// length are cut off after maxBytes, failing the handler's read
+func LimitBody(maxBytes int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.ContentLength > maxBytes {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, v1.ErrorResponse{
+				Error:   "Request body too large",
+				Details: fmt.Sprintf("request bodies are limited to %d bytes", maxBytes),
+			})
+			return
+		}
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes)
+		c.Next()
+	}
+}
+
+// RequireJSON returns a middleware rejecting request bodies that aren't declared as application/json with 415,
+// all endpoints taking a body expect JSON
+func RequireJSON() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.ContentLength != 0 && c.ContentType() != gin.MIMEJSON {
+			c.AbortWithStatusJSON(http.StatusUnsupportedMediaType, v1.ErrorResponse{
+				Error:   "Unsupported content type",
+				Details: "request bodies must be sent as application/json",
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// DebugAuth returns a middleware for debugging and documentation endpoints that lets requests through carrying
+// the API key, like APIKeyAuth, or matching basic auth credentials, so the endpoints can be opened in a browser.
+// Empty credentials are not accepted
+func DebugAuth(apiKey, user, password string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if apiKey != "" {
+			key := c.GetHeader("X-API-Key")
+			if key == "" {
+				key = strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+			}
+			if key != "" && subtle.ConstantTimeCompare([]byte(key), []byte(apiKey)) == 1 {
+				c.Set(apiKeyValidKey, true)
+				c.Next()
+				return
+			}
+		}
+
+		if user != "" && password != "" {
+			requestUser, requestPassword, ok := c.Request.BasicAuth()
+			if ok && subtle.ConstantTimeCompare([]byte(requestUser), []byte(user)) == 1 &&
+				subtle.ConstantTimeCompare([]byte(requestPassword), []byte(password)) == 1 {
+				c.Next()
+				return
+			}
+			c.Header("WWW-Authenticate", `Basic realm="ethereum-validator-api"`)
+		}
+
+		c.AbortWithStatusJSON(http.StatusUnauthorized, v1.ErrorResponse{Error: "Unauthorized"})
+	}
+}
+
+// BatchPriority returns a middleware scheduling the upstream calls of a request behind interactive requests,
+// for endpoints covering many epochs that would otherwise use up the shared rate budget
+func BatchPriority() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(service.WithPriority(c.Request.Context(), service.PriorityBatch))
+		c.Next()
+	}
+}
+
+// RequestBudget returns a middleware bounding the total time the upstream calls of a request may take,
+// calls still running when the budget is spent fail with service.ErrUpstreamTimeout
+func RequestBudget(budget time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), budget)
+		defer cancel()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// RequireUpstreams returns a middleware answering 503 while the upstream providers haven't been reached since startup,
+// for the endpoints serving chain data. The reason is left out of the response as it may carry provider URLs
+func (h *Handler) RequireUpstreams() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if ok, _ := h.ethService.UpstreamsAvailable(); !ok {
+			c.Header("Retry-After", "10")
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, v1.ErrorResponse{Error: "Upstream providers are unavailable, retry later", Code: codeUpstreamUnavailable})
+			return
+		}
+		c.Next()
+	}
+}
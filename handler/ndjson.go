@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"ethereum-validator-api/api/models/v1"
+	"ethereum-validator-api/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strings"
+)
+
+// ndjsonContentType is the media type of newline delimited JSON responses
+const ndjsonContentType = "application/x-ndjson"
+
+// wantsNDJSON reports whether the client asked for a newline delimited JSON stream instead of a single document
+func wantsNDJSON(c *gin.Context) bool {
+	return strings.Contains(c.GetHeader("Accept"), ndjsonContentType)
+}
+
+// ndjsonStream writes the rows of a response as newline delimited JSON, flushing each row as it is written. The
+// response only starts with the first row, so errors occurring before can still be answered with a regular status
+type ndjsonStream struct {
+	c       *gin.Context
+	started bool
+}
+
+// newNDJSONStream creates a stream writing to the response of a request
+func newNDJSONStream(c *gin.Context) *ndjsonStream {
+	return &ndjsonStream{c: c}
+}
+
+// Write encodes a row as a single line and flushes it to the client
+func (s *ndjsonStream) Write(row interface{}) error {
+	s.start()
+	if err := json.NewEncoder(s.c.Writer).Encode(row); err != nil {
+		return err
+	}
+	s.c.Writer.Flush()
+	return nil
+}
+
+// Close ends the stream. An error is answered through respond while no row has been written, and reported as a
+// final ErrorResponse line afterwards since the status code was already sent
+func (s *ndjsonStream) Close(err error, respond func(*gin.Context, error)) {
+	if err == nil {
+		s.start()
+		s.c.Writer.WriteHeaderNow()
+		return
+	}
+	if !s.started {
+		respond(s.c, err)
+		return
+	}
+
+	message := "Internal server error"
+	switch {
+	case errors.Is(err, service.ErrUpstreamRateLimited):
+		message = "Upstream provider is rate limiting requests"
+	case errors.Is(err, service.ErrUpstreamTimeout):
+		message = "Upstream request timed out"
+	case errors.Is(err, service.ErrUpstreamInvalidResponse):
+		message = "Upstream returned an invalid response"
+	case errors.Is(err, service.ErrNotSynced):
+		message = "Upstream node is not synced"
+	}
+	json.NewEncoder(s.c.Writer).Encode(v1.ErrorResponse{Error: message})
+	s.c.Writer.Flush()
+}
+
+// start sends the status and headers of the stream once
+func (s *ndjsonStream) start() {
+	if s.started {
+		return
+	}
+	s.started = true
+	s.c.Header("Content-Type", ndjsonContentType)
+	// Reverse proxies like nginx would otherwise buffer the whole stream
+	s.c.Header("X-Accel-Buffering", "no")
+	s.c.Status(http.StatusOK)
+}
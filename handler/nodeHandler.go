@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"ethereum-validator-api/api/models/v1"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// @Summary Get Node Sync Status
+// @Description Passes through the sync status of the configured beacon and execution nodes, cached for 5 seconds, so operators can monitor their nodes through the API
+// @Tags node
+// @Success 200 {object} v1.NodeSyncingResponse "Returns the sync status of both nodes"
+// @Failure 429 {object} v1.ErrorResponse "Upstream provider is rate limiting requests"
+// @Failure 502 {object} v1.ErrorResponse "Upstream returned an invalid response"
+// @Failure 500 {object} v1.ErrorResponse "Internal server error"
+// @Failure 503 {object} v1.ErrorResponse "Upstream providers not reached since startup or upstream node not synced"
+// @Failure 504 {object} v1.ErrorResponse "Upstream request timed out"
+// @Router /node/syncing [get]
+func (h *Handler) GetNodeSyncing(c *gin.Context) {
+	syncing, err := h.ethService.GetNodeSyncing(c.Request.Context())
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	// Create response object
+	response := v1.NodeSyncingResponse{
+		Beacon: v1.BeaconSyncingResponse{
+			HeadSlot:     syncing.Beacon.HeadSlot,
+			SyncDistance: syncing.Beacon.SyncDistance,
+			IsSyncing:    syncing.Beacon.IsSyncing,
+			IsOptimistic: syncing.Beacon.IsOptimistic,
+			ELOffline:    syncing.Beacon.ELOffline,
+		},
+		Execution: v1.ExecutionSyncingResponse{
+			IsSyncing:    syncing.Execution.IsSyncing,
+			CurrentBlock: syncing.Execution.CurrentBlock,
+			HighestBlock: syncing.Execution.HighestBlock,
+		},
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Get Node Versions
+// @Description Passes through the client versions of the configured beacon and execution nodes, cached for a minute
+// @Tags node
+// @Success 200 {object} v1.NodeVersionResponse "Returns the versions of both nodes"
+// @Failure 429 {object} v1.ErrorResponse "Upstream provider is rate limiting requests"
+// @Failure 502 {object} v1.ErrorResponse "Upstream returned an invalid response"
+// @Failure 500 {object} v1.ErrorResponse "Internal server error"
+// @Failure 503 {object} v1.ErrorResponse "Upstream providers not reached since startup or upstream node not synced"
+// @Failure 504 {object} v1.ErrorResponse "Upstream request timed out"
+// @Router /node/version [get]
+func (h *Handler) GetNodeVersion(c *gin.Context) {
+	version, err := h.ethService.GetNodeVersion(c.Request.Context())
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NodeVersionResponse{Beacon: version.Beacon, Execution: version.Execution})
+}
+
+// @Summary Get Node Peers
+// @Description Passes through the peer counts of the configured beacon and execution nodes, cached for 5 seconds
+// @Tags node
+// @Success 200 {object} v1.NodePeersResponse "Returns the peer counts of both nodes"
+// @Failure 429 {object} v1.ErrorResponse "Upstream provider is rate limiting requests"
+// @Failure 502 {object} v1.ErrorResponse "Upstream returned an invalid response"
+// @Failure 500 {object} v1.ErrorResponse "Internal server error"
+// @Failure 503 {object} v1.ErrorResponse "Upstream providers not reached since startup or upstream node not synced"
+// @Failure 504 {object} v1.ErrorResponse "Upstream request timed out"
+// @Router /node/peers [get]
+func (h *Handler) GetNodePeers(c *gin.Context) {
+	peers, err := h.ethService.GetNodePeers(c.Request.Context())
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	// Create response object
+	response := v1.NodePeersResponse{
+		Beacon: v1.BeaconPeersResponse{
+			Connected:     peers.BeaconConnected,
+			Connecting:    peers.BeaconConnecting,
+			Disconnected:  peers.BeaconDisconnected,
+			Disconnecting: peers.BeaconDisconnecting,
+		},
+		Execution: v1.ExecutionPeersResponse{Connected: peers.ExecutionConnected},
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"ethereum-validator-api/api/models/v1"
+	"ethereum-validator-api/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// @Summary Get Validator Queues
+// @Description Reports the entry and exit queue lengths, the churn limit and the estimated wait for new deposits and exits, computed from the head state
+// @Tags network
+// @Param fields query string false "Comma separated fields to return, nested fields of objects and lists selected with dots"
+// @Param meta query bool false "Add a meta field reporting the providers that served the data, cache use, data age and finalization"
+// @Success 200 {object} v1.QueueResponse "Returns the queue lengths and estimated wait times"
+// @Failure 429 {object} v1.ErrorResponse "Upstream provider is rate limiting requests"
+// @Failure 502 {object} v1.ErrorResponse "Upstream returned an invalid response"
+// @Failure 500 {object} v1.ErrorResponse "Internal server error"
+// @Failure 503 {object} v1.ErrorResponse "Upstream providers not reached since startup or upstream node not synced"
+// @Failure 504 {object} v1.ErrorResponse "Upstream request timed out"
+// @Router /queue [get]
+func (h *Handler) GetValidatorQueue(c *gin.Context) {
+	queue, err := h.ethService.GetValidatorQueue(c.Request.Context())
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	epochSeconds := int64(service.SlotsPerEpoch * service.SecondsPerSlot)
+
+	// Create response object
+	response := v1.QueueResponse{
+		Epoch:            queue.Epoch,
+		ActiveValidators: queue.ActiveValidators,
+		ActiveBalance:    queue.ActiveBalance,
+		ChurnLimit:       queue.ChurnLimit,
+	}
+	response.Entry.PendingDeposits = queue.PendingDeposits
+	response.Entry.PendingBalance = queue.PendingBalance
+	response.Entry.WaitEpochs = queue.EntryWaitEpochs
+	response.Entry.WaitSeconds = queue.EntryWaitEpochs * epochSeconds
+	response.Exit.Validators = queue.ExitingValidators
+	response.Exit.Balance = queue.ExitingBalance
+	response.Exit.WaitEpochs = queue.ExitWaitEpochs
+	response.Exit.WaitSeconds = queue.ExitWaitEpochs * epochSeconds
+
+	respondJSON(c, http.StatusOK, response)
+}
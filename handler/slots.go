@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"ethereum-validator-api/api/models/v1"
+	"ethereum-validator-api/service"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+const (
+	// slotTolerance is how far past the head a slot may be, covering a lagging beacon node and committees known an epoch ahead
+	slotTolerance = 2 * service.SlotsPerEpoch
+
+	// syncSlotTolerance lets sync duties be requested up to the end of the next sync committee period
+	syncSlotTolerance = 2 * service.SlotsPerSyncPeriod
+)
+
+// parseSlot parses a slot number, rejecting anything that isn't a non-negative integer
+func parseSlot(value string) (int64, error) {
+	slot, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || slot < 0 {
+		return 0, fmt.Errorf("slot %q must be a non-negative integer", value)
+	}
+	return slot, nil
+}
+
+// slotParam resolves the :slot path parameter, either a slot number or an alias, and checks it is at most maxAhead
+// slots past the head. The slot and whether it is finalized are recorded in the provenance of the request. On failure
+// the error response is written and false returned
+func (h *Handler) slotParam(c *gin.Context, maxAhead int64) (int64, bool) {
+	value := c.Param("slot")
+	var slot int64
+	switch value {
+	case "genesis":
+		service.ProvenanceFrom(c.Request.Context()).SetSlot(0, true)
+		return 0, true
+	case "head", "finalized", "justified":
+	default:
+		var err error
+		if slot, err = parseSlot(value); err != nil {
+			c.JSON(http.StatusBadRequest, v1.ErrorResponse{
+				Error:   "Invalid slot number",
+				Details: err.Error() + " or one of head, finalized, justified and genesis",
+			})
+			return 0, false
+		}
+	}
+
+	head, err := h.ethService.GetChainHead(c.Request.Context())
+	if err != nil {
+		respondInternalError(c, err)
+		return 0, false
+	}
+
+	switch value {
+	case "head":
+		slot = head.Slot
+	case "finalized":
+		slot = head.Finalized.Epoch * service.SlotsPerEpoch
+	case "justified":
+		slot = head.CurrentJustified.Epoch * service.SlotsPerEpoch
+	default:
+		if slot > head.Slot+maxAhead {
+			c.JSON(http.StatusBadRequest, v1.ErrorResponse{
+				Error:   "Slot is in the future",
+				Details: fmt.Sprintf("slot %d is more than %d slots past the head slot %d", slot, maxAhead, head.Slot),
+			})
+			return 0, false
+		}
+	}
+
+	service.ProvenanceFrom(c.Request.Context()).SetSlot(slot, slot <= head.Finalized.Epoch*service.SlotsPerEpoch)
+	return slot, true
+}
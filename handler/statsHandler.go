@@ -0,0 +1,307 @@
+package handler
+
+import (
+	"errors"
+	"ethereum-validator-api/api/models/v1"
+	"ethereum-validator-api/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// defaultStatsEpochs is the number of epochs covered when no range is given (about one day)
+const defaultStatsEpochs = 225
+
+// statsWindows maps the supported window query values to their duration
+var statsWindows = map[string]time.Duration{
+	"1d":  24 * time.Hour,
+	"7d":  7 * 24 * time.Hour,
+	"30d": 30 * 24 * time.Hour,
+}
+
+// @Summary Get MEV Statistics
+// @Description Aggregates indexed blocks within an epoch range into MEV vs vanilla share, per-builder dominance, average/median rewards, relay market share and per staking pool block share
+// @Tags stats
+// @Param from_epoch query int false "First epoch of the range (defaults to one day before to_epoch)"
+// @Param to_epoch query int false "Last epoch of the range (defaults to the latest indexed epoch)"
+// @Param unit query string false "Unit of the rewards: wei, gwei (default) or eth"
+// @Param fields query string false "Comma separated fields to return, nested fields of objects and lists selected with dots"
+// @Param meta query bool false "Add a meta field reporting the providers that served the data, cache use, data age and finalization"
+// @Success 200 {object} v1.MEVStatsResponse "Returns the aggregated MEV statistics"
+// @Failure 400 {object} v1.ErrorResponse "Invalid epoch range or unit"
+// @Failure 503 {object} v1.ErrorResponse "Upstream providers not reached since startup"
+// @Router /stats/mev [get]
+func (h *Handler) GetMEVStats(c *gin.Context) {
+	fromEpoch, toEpoch, err := h.parseEpochRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: "Invalid epoch range"})
+		return
+	}
+	unit, ok := parseUnit(c)
+	if !ok {
+		return
+	}
+
+	stats, err := h.ethService.GetMEVStats(fromEpoch, toEpoch)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidRange) {
+			c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: "Invalid epoch range"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, v1.ErrorResponse{Error: "Internal server error"})
+		return
+	}
+
+	// Create response object
+	response := v1.MEVStatsResponse{
+		FromEpoch:      stats.FromEpoch,
+		ToEpoch:        stats.ToEpoch,
+		Blocks:         stats.Blocks,
+		MEVBlocks:      stats.MEVBlocks,
+		VanillaBlocks:  stats.VanillaBlocks,
+		MEVShare:       stats.MEVShare,
+		Unit:           unit,
+		AverageReward:  v1.FormatAmount(stats.AverageReward, unit),
+		MedianReward:   v1.FormatAmount(stats.MedianReward, unit),
+		AverageMEV:     v1.FormatAmount(stats.AverageMEV, unit),
+		AverageVanilla: v1.FormatAmount(stats.AverageVanilla, unit),
+		Builders:       v1.NewShareResponses(stats.Builders),
+		Relays:         v1.NewShareResponses(stats.Relays),
+		Pools:          make([]v1.PoolResponse, 0, len(stats.Pools)),
+	}
+	for _, pool := range stats.Pools {
+		response.Pools = append(response.Pools, v1.PoolResponse{
+			Name:          pool.Name,
+			Blocks:        pool.Blocks,
+			Share:         pool.Share,
+			MEVBlocks:     pool.MEVBlocks,
+			MEVShare:      pool.MEVShare,
+			AverageReward: v1.FormatAmount(pool.AverageReward, unit),
+		})
+	}
+
+	respondJSON(c, http.StatusOK, response)
+}
+
+// @Summary Get Reward Statistics
+// @Description Reports min/median/p90/p99/max proposer rewards and the MEV ratio across a window of indexed blocks ending at the latest indexed slot
+// @Tags stats
+// @Param window query string false "Window to aggregate over: 1d, 7d or 30d (default 1d)"
+// @Param unit query string false "Unit of the rewards: wei, gwei (default) or eth"
+// @Param fields query string false "Comma separated fields to return, nested fields of objects and lists selected with dots"
+// @Param meta query bool false "Add a meta field reporting the providers that served the data, cache use, data age and finalization"
+// @Success 200 {object} v1.RewardStatsResponse "Returns the reward statistics"
+// @Failure 400 {object} v1.ErrorResponse "Invalid window or unit"
+// @Failure 503 {object} v1.ErrorResponse "Upstream providers not reached since startup"
+// @Router /stats/rewards [get]
+func (h *Handler) GetRewardStats(c *gin.Context) {
+	windowParam := c.DefaultQuery("window", "1d")
+	window, ok := statsWindows[windowParam]
+	if !ok {
+		c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: "Invalid window, expected 1d, 7d or 30d"})
+		return
+	}
+	unit, ok := parseUnit(c)
+	if !ok {
+		return
+	}
+
+	stats, err := h.ethService.GetRewardStats(window)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, v1.ErrorResponse{Error: "Internal server error"})
+		return
+	}
+
+	// Create response object
+	response := v1.RewardStatsResponse{
+		Window:   windowParam,
+		FromSlot: stats.FromSlot,
+		ToSlot:   stats.ToSlot,
+		Blocks:   stats.Blocks,
+		Unit:     unit,
+		Min:      v1.FormatAmount(stats.Min, unit),
+		Median:   v1.FormatAmount(stats.Median, unit),
+		P90:      v1.FormatAmount(stats.P90, unit),
+		P99:      v1.FormatAmount(stats.P99, unit),
+		Max:      v1.FormatAmount(stats.Max, unit),
+		MEVRatio: stats.MEVRatio,
+	}
+
+	respondJSON(c, http.StatusOK, response)
+}
+
+// @Summary Get Base Fee Statistics
+// @Description Returns the base fee trend across a window of indexed blocks ending at the latest indexed slot, with consecutive blocks averaged into at most 720 points
+// @Tags stats
+// @Param window query string false "Window to aggregate over: 1d, 7d or 30d (default 1d)"
+// @Param unit query string false "Unit of the base fees: wei, gwei (default) or eth"
+// @Param fields query string false "Comma separated fields to return, nested fields of objects and lists selected with dots"
+// @Param meta query bool false "Add a meta field reporting the providers that served the data, cache use, data age and finalization"
+// @Success 200 {object} v1.BaseFeeStatsResponse "Returns the base fee series"
+// @Failure 400 {object} v1.ErrorResponse "Invalid window or unit"
+// @Failure 503 {object} v1.ErrorResponse "Upstream providers not reached since startup"
+// @Router /stats/basefee [get]
+func (h *Handler) GetBaseFeeStats(c *gin.Context) {
+	windowParam := c.DefaultQuery("window", "1d")
+	window, ok := statsWindows[windowParam]
+	if !ok {
+		c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: "Invalid window, expected 1d, 7d or 30d"})
+		return
+	}
+	unit, ok := parseUnit(c)
+	if !ok {
+		return
+	}
+
+	series, err := h.ethService.GetBaseFeeSeries(window)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, v1.ErrorResponse{Error: "Internal server error"})
+		return
+	}
+
+	// Create response object
+	response := v1.BaseFeeStatsResponse{
+		Window:      windowParam,
+		FromSlot:    series.FromSlot,
+		ToSlot:      series.ToSlot,
+		Blocks:      series.Blocks,
+		BucketSlots: series.BucketSlots,
+		Unit:        unit,
+		Min:         v1.FormatAmount(series.MinBaseFee, unit),
+		Median:      v1.FormatAmount(series.Median, unit),
+		Max:         v1.FormatAmount(series.MaxBaseFee, unit),
+		Points:      make([]v1.BaseFeePointResponse, 0, len(series.Points)),
+	}
+	for _, point := range series.Points {
+		response.Points = append(response.Points, v1.BaseFeePointResponse{
+			Slot:        point.Slot,
+			Timestamp:   point.Timestamp,
+			Blocks:      point.Blocks,
+			BaseFee:     v1.FormatAmount(point.BaseFee, unit),
+			Min:         v1.FormatAmount(point.MinBaseFee, unit),
+			Max:         v1.FormatAmount(point.MaxBaseFee, unit),
+			Utilization: point.Utilization,
+		})
+	}
+
+	respondJSON(c, http.StatusOK, response)
+}
+
+// @Summary Get Network Statistics
+// @Description Returns the active validator count, total effective balance and recent per-epoch participation rate, refreshed once per epoch by a background job
+// @Tags stats
+// @Param fields query string false "Comma separated fields to return, nested fields of objects and lists selected with dots"
+// @Param meta query bool false "Add a meta field reporting the providers that served the data, cache use, data age and finalization"
+// @Success 200 {object} v1.NetworkStatsResponse "Returns the network statistics"
+// @Failure 503 {object} v1.ErrorResponse "Network statistics not computed yet or disabled, or upstream providers not reached since startup"
+// @Router /stats/network [get]
+func (h *Handler) GetNetworkStats(c *gin.Context) {
+	stats, err := h.ethService.GetNetworkStats()
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, v1.ErrorResponse{Error: "Network statistics not available yet"})
+		return
+	}
+
+	// Create response object
+	response := v1.NetworkStatsResponse{
+		Epoch:                 stats.Epoch,
+		ActiveValidators:      stats.ActiveValidators,
+		TotalEffectiveBalance: stats.TotalEffectiveBalance,
+		Participation:         make([]v1.EpochParticipationResponse, 0, len(stats.Participation)),
+		UpdatedAt:             stats.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+	for _, participation := range stats.Participation {
+		response.Participation = append(response.Participation, v1.EpochParticipationResponse{
+			Epoch: participation.Epoch,
+			Rate:  participation.Rate,
+		})
+	}
+
+	respondJSON(c, http.StatusOK, response)
+}
+
+// @Summary Get Network APR
+// @Description Computes the average annualized return of the active validators over a window ending at the latest indexed slot, as a benchmark for /validator/{id}/apr. Attestation rewards are the ideal rewards of a 32 ETH validator scaled by the recent participation, execution tips and MEV are those of all indexed blocks spread over the active balance
+// @Tags stats
+// @Param window query string false "Window to compute the return over: 1d, 7d or 30d (default 30d)"
+// @Param fields query string false "Comma separated fields to return, nested fields of objects and lists selected with dots"
+// @Param meta query bool false "Add a meta field reporting the providers that served the data, cache use, data age and finalization"
+// @Success 200 {object} v1.NetworkAPRResponse "Returns the network average return and its breakdown per source"
+// @Failure 400 {object} v1.ErrorResponse "Invalid window"
+// @Failure 429 {object} v1.ErrorResponse "Upstream provider is rate limiting requests"
+// @Failure 502 {object} v1.ErrorResponse "Upstream returned an invalid response"
+// @Failure 500 {object} v1.ErrorResponse "Internal server error"
+// @Failure 503 {object} v1.ErrorResponse "Network statistics not computed yet or disabled, or upstream providers not reached since startup"
+// @Failure 504 {object} v1.ErrorResponse "Upstream request timed out"
+// @Router /stats/apr [get]
+func (h *Handler) GetNetworkAPR(c *gin.Context) {
+	windowParam := c.DefaultQuery("window", "30d")
+	window, ok := statsWindows[windowParam]
+	if !ok {
+		c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: "Invalid window, expected 1d, 7d or 30d"})
+		return
+	}
+
+	network, err := h.ethService.GetNetworkAPR(c.Request.Context(), window)
+	if err != nil {
+		if errors.Is(err, service.ErrNetworkStatsUnavailable) {
+			c.JSON(http.StatusServiceUnavailable, v1.ErrorResponse{Error: "Network statistics not available yet"})
+			return
+		}
+		respondInternalError(c, err)
+		return
+	}
+
+	// Create response object
+	response := v1.NetworkAPRResponse{
+		Window:           windowParam,
+		FromSlot:         network.FromSlot,
+		ToSlot:           network.ToSlot,
+		Epoch:            network.Epoch,
+		ActiveValidators: network.ActiveValidators,
+		ActiveBalance:    network.ActiveBalance,
+		APR:              network.Total,
+		Sources: v1.NetworkAPRSourcesResponse{
+			Consensus:     network.Consensus,
+			ExecutionTips: network.ExecutionTips,
+			MEV:           network.MEV,
+		},
+		IdealAttestationReward: network.IdealAttestationReward,
+		Participation:          network.Participation,
+		ExecutionRewards:       network.ExecutionRewards,
+		BlockCoverage:          network.BlockCoverage,
+	}
+
+	respondJSON(c, http.StatusOK, response)
+}
+
+// parseEpochRange parses the from_epoch and to_epoch query parameters, defaulting to the
+// last day of indexed data
+func (h *Handler) parseEpochRange(c *gin.Context) (int64, int64, error) {
+	toEpoch := int64(0)
+	if latest := h.ethService.Index().LatestSlot(); latest > 0 {
+		toEpoch = latest / service.SlotsPerEpoch
+	}
+	if toParam, ok := c.GetQuery("to_epoch"); ok {
+		parsed, err := strconv.ParseInt(toParam, 10, 64)
+		if err != nil {
+			return 0, 0, err
+		}
+		toEpoch = parsed
+	}
+
+	fromEpoch := toEpoch - defaultStatsEpochs
+	if fromEpoch < 0 {
+		fromEpoch = 0
+	}
+	if fromParam, ok := c.GetQuery("from_epoch"); ok {
+		parsed, err := strconv.ParseInt(fromParam, 10, 64)
+		if err != nil {
+			return 0, 0, err
+		}
+		fromEpoch = parsed
+	}
+
+	return fromEpoch, toEpoch, nil
+}
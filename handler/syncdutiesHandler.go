@@ -2,30 +2,52 @@ package handler
 
 import (
 	"errors"
+	"ethereum-validator-api/api/models/v1"
 	"ethereum-validator-api/service"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"net/http"
 	"strconv"
+	"strings"
 )
 
 // @Summary Get Sync Committee Duties
 // @Description Retrieves the sync committee duties for validators at a given slot in the Ethereum Proof of Stake chain
 // @Tags sync
-// @Param slot path int true "Slot number in the Beacon Chain"
-// @Success 200 {object} SyncDutiesResponse "Returns list of validator public keys and sync committee information"
-// @Failure 400 {object} ErrorResponse "Invalid slot number or slot too far in future"
-// @Failure 404 {object} ErrorResponse "Slot not found in chain"
-// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Param slot path string true "Slot number in the Beacon Chain, or head, finalized, justified or genesis"
+// @Param validators query string false "Comma separated pubkeys to only return these committee members"
+// @Param offset query int false "Number of committee members to skip (default 0)"
+// @Param limit query int false "Maximum number of committee members (default and max 512)"
+// @Param fields query string false "Comma separated fields to return, nested fields of objects and lists selected with dots"
+// @Param meta query bool false "Add a meta field reporting the providers that served the data, cache use, data age and finalization"
+// @Success 200 {object} v1.SyncDutiesResponse "Returns list of validator public keys and sync committee information"
+// @Failure 400 {object} v1.ErrorResponse "Invalid slot number, pagination or slot too far in future"
+// @Failure 404 {object} v1.ErrorResponse "Slot not found in chain"
+// @Failure 429 {object} v1.ErrorResponse "Upstream provider is rate limiting requests"
+// @Failure 502 {object} v1.ErrorResponse "Upstream returned an invalid response"
+// @Failure 500 {object} v1.ErrorResponse "Internal server error"
+// @Failure 503 {object} v1.ErrorResponse "Upstream providers not reached since startup or upstream node not synced"
+// @Failure 504 {object} v1.ErrorResponse "Upstream request timed out"
 // @Router /syncduties/{slot} [get]
 func (h *Handler) GetSyncDuties(c *gin.Context) {
-	slotParam := c.Param("slot")
-	slot, err := strconv.ParseInt(slotParam, 10, 64)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid slot number"})
+	slot, ok := h.slotParam(c, syncSlotTolerance)
+	if !ok {
+		return
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: "Invalid offset"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(service.SyncCommitteeSize)))
+	if err != nil || limit <= 0 || limit > service.SyncCommitteeSize {
+		c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: "Invalid limit"})
 		return
 	}
 
-	validators, err := h.ethService.GetSyncDutiesBySlot(c.Request.Context(), slot)
+	committee, err := h.ethService.GetSyncCommitteeBySlot(c.Request.Context(), slot)
 	if err != nil {
 		var statusCode int
 		var errMsg string
@@ -38,23 +60,147 @@ func (h *Handler) GetSyncDuties(c *gin.Context) {
 			statusCode = http.StatusNotFound
 			errMsg = "Slot does not exist"
 		default:
-			statusCode = http.StatusInternalServerError
-			errMsg = "Internal server error"
+			respondInternalError(c, err)
+			return
+		}
+
+		c.JSON(statusCode, v1.ErrorResponse{Error: errMsg})
+		return
+	}
+
+	// Only keep the requested keys, a validator can hold several positions in the committee
+	members := committee.Members
+	if filter := c.Query("validators"); filter != "" {
+		wanted := make(map[string]bool)
+		for _, pubkey := range strings.Split(filter, ",") {
+			wanted[strings.ToLower(strings.TrimSpace(pubkey))] = true
 		}
 
-		c.JSON(statusCode, ErrorResponse{Error: errMsg})
+		matched := make([]service.SyncCommitteeMember, 0)
+		for _, member := range members {
+			if wanted[strings.ToLower(member.Pubkey)] {
+				matched = append(matched, member)
+			}
+		}
+		members = matched
+	}
+	total := len(members)
+
+	// Apply pagination
+	if offset > len(members) {
+		offset = len(members)
+	}
+	members = members[offset:min(offset+limit, len(members))]
+
+	// Create response object
+	response := v1.SyncDutiesResponse{
+		Validators:       make([]string, 0, len(members)),
+		ValidatorIndices: make([]int64, 0, len(members)),
+		Total:            total,
+		Offset:           offset,
+	}
+	for _, member := range members {
+		response.Validators = append(response.Validators, member.Pubkey)
+		response.ValidatorIndices = append(response.ValidatorIndices, member.Index)
+	}
+	response.SyncInfo.SyncPeriod = committee.SyncPeriod
+	response.SyncInfo.CommitteeSize = len(committee.Members)
+	response.SyncInfo.StartSlot = committee.StartSlot
+	response.SyncInfo.EndSlot = committee.EndSlot
+	response.SyncInfo.AggregatePubkey = committee.AggregatePubkey
+
+	respondJSON(c, http.StatusOK, response)
+}
+
+// @Summary Get Next Sync Committee
+// @Description Retrieves the upcoming sync committee from the head state, giving about 27 hours of notice before its duties start
+// @Tags sync
+// @Param fields query string false "Comma separated fields to return, nested fields of objects and lists selected with dots"
+// @Param meta query bool false "Add a meta field reporting the providers that served the data, cache use, data age and finalization"
+// @Success 200 {object} v1.NextSyncCommitteeResponse "Returns the members and slot range of the next sync committee"
+// @Failure 429 {object} v1.ErrorResponse "Upstream provider is rate limiting requests"
+// @Failure 502 {object} v1.ErrorResponse "Upstream returned an invalid response"
+// @Failure 500 {object} v1.ErrorResponse "Internal server error"
+// @Failure 503 {object} v1.ErrorResponse "Upstream providers not reached since startup or upstream node not synced"
+// @Failure 504 {object} v1.ErrorResponse "Upstream request timed out"
+// @Router /syncduties/next [get]
+func (h *Handler) GetNextSyncCommittee(c *gin.Context) {
+	committee, err := h.ethService.GetNextSyncCommittee(c.Request.Context())
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	// Create response object
+	response := v1.NextSyncCommitteeResponse{
+		SyncPeriod: committee.SyncPeriod,
+		StartSlot:  committee.StartSlot,
+		EndSlot:    committee.EndSlot,
+		Members:    make([]v1.SyncCommitteeMemberResponse, 0, len(committee.Members)),
+	}
+	for _, member := range committee.Members {
+		response.Members = append(response.Members, v1.SyncCommitteeMemberResponse{
+			Index:  member.Index,
+			Pubkey: member.Pubkey,
+		})
+	}
+
+	respondJSON(c, http.StatusOK, response)
+}
+
+// defaultSyncProbabilityPeriods is the number of upcoming sync committee periods covered when none are given
+const defaultSyncProbabilityPeriods = 10
+
+// @Summary Get Validator Sync Committee Probability
+// @Description Computes the probability of a validator being selected for the sync committees of the upcoming periods, starting with the next one, from its effective balance and the cached active validator set. The committee of the next period is already selected, so its membership is reported instead of a chance
+// @Tags sync
+// @Param id path string true "Validator index or pubkey"
+// @Param periods query int false "Number of upcoming sync committee periods (default 10, max 100)"
+// @Param fields query string false "Comma separated fields to return, nested fields of objects and lists selected with dots"
+// @Param meta query bool false "Add a meta field reporting the providers that served the data, cache use, data age and finalization"
+// @Success 200 {object} v1.SyncProbabilityResponse "Returns the selection probability per period and over all of them"
+// @Failure 400 {object} v1.ErrorResponse "Invalid validator or periods"
+// @Failure 404 {object} v1.ErrorResponse "Validator not found"
+// @Failure 429 {object} v1.ErrorResponse "Upstream provider is rate limiting requests"
+// @Failure 502 {object} v1.ErrorResponse "Upstream returned an invalid response"
+// @Failure 500 {object} v1.ErrorResponse "Internal server error"
+// @Failure 503 {object} v1.ErrorResponse "Upstream providers not reached since startup or upstream node not synced"
+// @Failure 504 {object} v1.ErrorResponse "Upstream request timed out"
+// @Router /validator/{id}/syncprobability [get]
+func (h *Handler) GetValidatorSyncProbability(c *gin.Context) {
+	periods, err := strconv.ParseInt(c.DefaultQuery("periods", strconv.Itoa(defaultSyncProbabilityPeriods)), 10, 64)
+	if err != nil || periods < 1 || periods > service.MaxSyncProbabilityPeriods {
+		c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: fmt.Sprintf("periods must be between 1 and %d", service.MaxSyncProbabilityPeriods)})
 		return
 	}
 
-	// Calculate sync period
-	syncPeriod := slot / 8192 // Sync committee period changes every 256 epochs (8192 slots)
+	probability, err := h.ethService.GetSyncCommitteeProbability(c.Request.Context(), c.Param("id"), periods)
+	if err != nil {
+		respondValidatorError(c, err)
+		return
+	}
 
 	// Create response object
-	response := SyncDutiesResponse{
-		Validators: validators,
+	response := v1.SyncProbabilityResponse{
+		ValidatorIndex:     probability.ValidatorIndex,
+		Status:             probability.Status,
+		EffectiveBalance:   probability.EffectiveBalance,
+		ActiveValidators:   probability.ActiveValidators,
+		ActiveBalance:      probability.ActiveBalance,
+		PeriodProbability:  probability.PeriodProbability,
+		Probability:        probability.Probability,
+		ExpectedSelections: probability.ExpectedSelections,
+		Periods:            make([]v1.SyncPeriodProbabilityResponse, 0, len(probability.Periods)),
+	}
+	for _, period := range probability.Periods {
+		response.Periods = append(response.Periods, v1.SyncPeriodProbabilityResponse{
+			SyncPeriod:  period.Period,
+			StartSlot:   period.StartSlot,
+			EndSlot:     period.EndSlot,
+			Probability: period.Probability,
+			Known:       period.Known,
+		})
 	}
-	response.SyncInfo.SyncPeriod = syncPeriod
-	response.SyncInfo.CommitteeSize = len(validators)
 
-	c.JSON(http.StatusOK, response)
+	respondJSON(c, http.StatusOK, response)
 }
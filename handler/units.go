@@ -0,0 +1,18 @@
+package handler
+
+import (
+	"ethereum-validator-api/api/models/v1"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strings"
+)
+
+// parseUnit reads the unit query parameter, defaulting to gwei, and responds with 400 for unknown units
+func parseUnit(c *gin.Context) (string, bool) {
+	unit := strings.ToLower(c.DefaultQuery("unit", v1.UnitGwei))
+	if !v1.ValidUnit(unit) {
+		c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: "Invalid unit", Details: "unit must be one of wei, gwei and eth"})
+		return "", false
+	}
+	return unit, true
+}
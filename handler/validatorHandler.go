@@ -0,0 +1,489 @@
+package handler
+
+import (
+	"errors"
+	"ethereum-validator-api/api/models/v1"
+	"ethereum-validator-api/service"
+	"github.com/gin-gonic/gin"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// @Summary Get Validator Status
+// @Description Retrieves the status, balance, activation queue position and staking pool of a validator at the head or, with as_of_slot, at a historical state
+// @Tags validator
+// @Param id path string true "Validator index or pubkey"
+// @Param as_of_slot query int false "Evaluate the status as of this historical slot instead of the head"
+// @Param fields query string false "Comma separated fields to return, nested fields of objects and lists selected with dots"
+// @Param meta query bool false "Add a meta field reporting the providers that served the data, cache use, data age and finalization"
+// @Success 200 {object} v1.ValidatorStatusResponse "Returns the validator status"
+// @Failure 400 {object} v1.ErrorResponse "Invalid validator or slot"
+// @Failure 404 {object} v1.ErrorResponse "Validator not found"
+// @Failure 429 {object} v1.ErrorResponse "Upstream provider is rate limiting requests"
+// @Failure 502 {object} v1.ErrorResponse "Upstream returned an invalid response"
+// @Failure 500 {object} v1.ErrorResponse "Internal server error"
+// @Failure 503 {object} v1.ErrorResponse "Upstream providers not reached since startup or upstream node not synced"
+// @Failure 504 {object} v1.ErrorResponse "Upstream request timed out"
+// @Router /validator/{id} [get]
+func (h *Handler) GetValidator(c *gin.Context) {
+	asOfSlot, err := parseAsOfSlot(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: "Invalid as_of_slot"})
+		return
+	}
+
+	status, err := h.ethService.GetValidatorStatus(c.Request.Context(), c.Param("id"), asOfSlot)
+	if err != nil {
+		respondValidatorError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, v1.NewValidatorStatusResponse(status))
+}
+
+// @Summary Get Validator Block Reward
+// @Description Retrieves the block reward for a slot after verifying that the given validator proposed it
+// @Tags validator
+// @Param id path string true "Validator index or pubkey"
+// @Param slot path string true "Slot number in the Beacon Chain, or head, finalized, justified or genesis"
+// @Param unit query string false "Unit of the amounts: wei, gwei (default) or eth"
+// @Param fields query string false "Comma separated fields to return, nested fields of objects and lists selected with dots"
+// @Param meta query bool false "Add a meta field reporting the providers that served the data, cache use, data age and finalization"
+// @Success 200 {object} v1.ValidatorBlockRewardResponse "Returns block reward details for the validator's proposal"
+// @Failure 400 {object} v1.ErrorResponse "Invalid validator, slot number or future slot"
+// @Failure 404 {object} v1.ProposerMismatchResponse "Slot was proposed by another validator"
+// @Failure 429 {object} v1.ErrorResponse "Upstream provider is rate limiting requests"
+// @Failure 502 {object} v1.ErrorResponse "Upstream returned an invalid response"
+// @Failure 500 {object} v1.ErrorResponse "Internal server error"
+// @Failure 503 {object} v1.ErrorResponse "Upstream providers not reached since startup or upstream node not synced"
+// @Failure 504 {object} v1.ErrorResponse "Upstream request timed out"
+// @Router /validator/{id}/blockreward/{slot} [get]
+func (h *Handler) GetValidatorBlockReward(c *gin.Context) {
+	slot, ok := h.slotParam(c, slotTolerance)
+	if !ok {
+		return
+	}
+	unit, ok := parseUnit(c)
+	if !ok {
+		return
+	}
+
+	reward, err := h.ethService.GetValidatorBlockRewardBySlot(c.Request.Context(), c.Param("id"), slot)
+	if err != nil {
+		respondValidatorError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, v1.NewValidatorBlockRewardResponse(reward, unit))
+}
+
+// @Summary Get Validator Latest Block Reward
+// @Description Retrieves the block reward of the most recent slot proposed by the given validator
+// @Tags validator
+// @Param id path string true "Validator index or pubkey"
+// @Param unit query string false "Unit of the amounts: wei, gwei (default) or eth"
+// @Param fields query string false "Comma separated fields to return, nested fields of objects and lists selected with dots"
+// @Param meta query bool false "Add a meta field reporting the providers that served the data, cache use, data age and finalization"
+// @Success 200 {object} v1.ValidatorBlockRewardResponse "Returns block reward details for the validator's latest proposal"
+// @Failure 400 {object} v1.ErrorResponse "Invalid validator"
+// @Failure 404 {object} v1.ErrorResponse "Validator not found or no recent proposal"
+// @Failure 429 {object} v1.ErrorResponse "Upstream provider is rate limiting requests"
+// @Failure 502 {object} v1.ErrorResponse "Upstream returned an invalid response"
+// @Failure 500 {object} v1.ErrorResponse "Internal server error"
+// @Failure 503 {object} v1.ErrorResponse "Upstream providers not reached since startup, upstream node not synced or recent proposals not indexed yet"
+// @Failure 504 {object} v1.ErrorResponse "Upstream request timed out"
+// @Router /validator/{id}/blockreward/latest [get]
+func (h *Handler) GetValidatorLatestBlockReward(c *gin.Context) {
+	unit, ok := parseUnit(c)
+	if !ok {
+		return
+	}
+
+	reward, err := h.ethService.GetValidatorLatestBlockReward(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondValidatorError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, v1.NewValidatorBlockRewardResponse(reward, unit))
+}
+
+// @Summary Get Validator Proposals
+// @Description Lists the proposals assigned to a validator within an epoch range with their outcome (proposed, missed or orphaned), reward, MEV status and relay. Blocks seen by the indexer that are no longer canonical are reported as orphaned
+// @Tags validator
+// @Param id path string true "Validator index or pubkey"
+// @Param from_epoch query int false "First epoch of the range (defaults to 99 epochs before to_epoch)"
+// @Param to_epoch query int false "Last epoch of the range (defaults to the head epoch)"
+// @Param cursor query string false "Continue after the page that returned this next_cursor, the range then starts at its epoch and defaults to end at the head"
+// @Param limit query int false "Return pages of at most this many proposals with a next_cursor (1-1000, default 100 when a cursor is given)"
+// @Param unit query string false "Unit of the rewards: wei, gwei (default) or eth"
+// @Produce json,application/x-ndjson
+// @Param fields query string false "Comma separated fields to return, nested fields of objects and lists selected with dots"
+// @Param meta query bool false "Add a meta field reporting the providers that served the data, cache use, data age and finalization"
+// @Success 200 {object} v1.ValidatorProposalsResponse "Returns the assigned proposals, oldest first. With Accept: application/x-ndjson one v1.ValidatorProposalResponse per line"
+// @Failure 400 {object} v1.ErrorResponse "Invalid validator, epoch range, cursor, limit or unit"
+// @Failure 404 {object} v1.ErrorResponse "Validator not found"
+// @Failure 429 {object} v1.ErrorResponse "Upstream provider is rate limiting requests"
+// @Failure 502 {object} v1.ErrorResponse "Upstream returned an invalid response"
+// @Failure 500 {object} v1.ErrorResponse "Internal server error"
+// @Failure 503 {object} v1.ErrorResponse "Upstream providers not reached since startup or upstream node not synced"
+// @Failure 504 {object} v1.ErrorResponse "Upstream request timed out"
+// @Router /validator/{id}/proposals [get]
+func (h *Handler) GetValidatorProposals(c *gin.Context) {
+	fromEpoch, toEpoch, ok := parseOptionalEpochRange(c)
+	if !ok {
+		return
+	}
+	unit, ok := parseUnit(c)
+	if !ok {
+		return
+	}
+
+	if wantsNDJSON(c) {
+		stream := newNDJSONStream(c)
+		_, err := h.ethService.StreamValidatorProposals(c.Request.Context(), c.Param("id"), fromEpoch, toEpoch, func(proposal service.ValidatorProposal) error {
+			return stream.Write(v1.NewValidatorProposalResponse(proposal, unit))
+		})
+		stream.Close(err, respondValidatorError)
+		return
+	}
+
+	// Pages keyed by slot are requested with a cursor or a limit
+	_, paged := c.GetQuery("cursor")
+	if _, limited := c.GetQuery("limit"); limited {
+		paged = true
+	}
+	var proposals *service.ValidatorProposals
+	var page *service.ValidatorProposalsPage
+	var err error
+	if paged {
+		cursor, limit, ok := parseCursor(c, defaultProposalsPerPage, service.MaxProposalsPerPage)
+		if !ok {
+			return
+		}
+		if page, err = h.ethService.GetValidatorProposalsPage(c.Request.Context(), c.Param("id"), cursor, fromEpoch, toEpoch, limit); err == nil {
+			proposals = &page.ValidatorProposals
+		}
+	} else {
+		proposals, err = h.ethService.GetValidatorProposals(c.Request.Context(), c.Param("id"), fromEpoch, toEpoch)
+	}
+	if err != nil {
+		respondValidatorError(c, err)
+		return
+	}
+
+	// Create response object
+	response := v1.ValidatorProposalsResponse{
+		ValidatorIndex: proposals.ValidatorIndex,
+		FromEpoch:      proposals.FromEpoch,
+		ToEpoch:        proposals.ToEpoch,
+		Proposals:      make([]v1.ValidatorProposalResponse, 0, len(proposals.Proposals)),
+	}
+	if page != nil {
+		response.NextCursor = strconv.FormatInt(page.NextCursor, 10)
+		response.HasMore = &page.HasMore
+	}
+	for _, proposal := range proposals.Proposals {
+		switch proposal.Outcome {
+		case service.ProposalProposed:
+			response.Proposed++
+		case service.ProposalMissed:
+			response.Missed++
+		case service.ProposalOrphaned:
+			response.Orphaned++
+		}
+		response.Proposals = append(response.Proposals, v1.NewValidatorProposalResponse(proposal, unit))
+	}
+
+	respondJSON(c, http.StatusOK, response)
+}
+
+// @Summary Check Validator Fee Recipient
+// @Description Verifies that the blocks a validator proposed within an epoch range paid the expected fee recipient and flags mismatches. Vanilla blocks are checked against their payload, MEV blocks against the proposer fee recipient reported by the delivering relay
+// @Tags validator
+// @Param id path string true "Validator index or pubkey"
+// @Param expected query string true "Expected fee recipient address"
+// @Param from_epoch query int false "First epoch of the range (defaults to 99 epochs before to_epoch)"
+// @Param to_epoch query int false "Last epoch of the range (defaults to the head epoch)"
+// @Param fields query string false "Comma separated fields to return, nested fields of objects and lists selected with dots"
+// @Param meta query bool false "Add a meta field reporting the providers that served the data, cache use, data age and finalization"
+// @Success 200 {object} v1.FeeRecipientCheckResponse "Returns the fee recipient of each proposed block and whether it matches"
+// @Failure 400 {object} v1.ErrorResponse "Invalid validator, address or epoch range"
+// @Failure 404 {object} v1.ErrorResponse "Validator not found"
+// @Failure 429 {object} v1.ErrorResponse "Upstream provider is rate limiting requests"
+// @Failure 502 {object} v1.ErrorResponse "Upstream returned an invalid response"
+// @Failure 500 {object} v1.ErrorResponse "Internal server error"
+// @Failure 503 {object} v1.ErrorResponse "Upstream providers not reached since startup or upstream node not synced"
+// @Failure 504 {object} v1.ErrorResponse "Upstream request timed out"
+// @Router /validator/{id}/feerecipient/check [get]
+func (h *Handler) CheckFeeRecipient(c *gin.Context) {
+	fromEpoch, toEpoch, ok := parseOptionalEpochRange(c)
+	if !ok {
+		return
+	}
+
+	check, err := h.ethService.CheckFeeRecipient(c.Request.Context(), c.Param("id"), c.Query("expected"), fromEpoch, toEpoch)
+	if err != nil {
+		respondValidatorError(c, err)
+		return
+	}
+
+	// Create response object
+	response := v1.FeeRecipientCheckResponse{
+		ValidatorIndex: check.ValidatorIndex,
+		Expected:       check.Expected,
+		FromEpoch:      check.FromEpoch,
+		ToEpoch:        check.ToEpoch,
+		Compliant:      check.Mismatches == 0,
+		Matches:        check.Matches,
+		Mismatches:     check.Mismatches,
+		Unknown:        check.Unknown,
+		Proposals:      make([]v1.FeeRecipientProposalResponse, 0, len(check.Proposals)),
+	}
+	for _, proposal := range check.Proposals {
+		response.Proposals = append(response.Proposals, v1.FeeRecipientProposalResponse{
+			Slot:         proposal.Slot,
+			Status:       proposal.Status,
+			FeeRecipient: proposal.FeeRecipient,
+			Source:       proposal.Source,
+			Result:       proposal.Result,
+		})
+	}
+
+	respondJSON(c, http.StatusOK, response)
+}
+
+// forecastPeriods maps the supported period query values of the rewards forecast to their duration
+var forecastPeriods = map[string]time.Duration{
+	"7d":  7 * 24 * time.Hour,
+	"30d": 30 * 24 * time.Hour,
+}
+
+// @Summary Get Validator Rewards Forecast
+// @Description Estimates the rewards a validator can expect over the next week or month from its effective balance and the current network conditions: active balance, ideal attestation rewards and participation, the average execution reward and MEV share of the indexed blocks of the last 30 days, and the chances of proposing and of being selected for a sync committee. Proposals and sync committee duties are spread by their probability. Validators that are not active are forecast no rewards
+// @Tags validator
+// @Param id path string true "Validator index or pubkey"
+// @Param period query string false "Period to forecast: 7d or 30d (default 7d)"
+// @Param unit query string false "Unit of the expected rewards: wei, gwei (default) or eth"
+// @Param fields query string false "Comma separated fields to return, nested fields of objects and lists selected with dots"
+// @Param meta query bool false "Add a meta field reporting the providers that served the data, cache use, data age and finalization"
+// @Success 200 {object} v1.ForecastResponse "Returns the expected rewards per source"
+// @Failure 400 {object} v1.ErrorResponse "Invalid validator, period or unit"
+// @Failure 404 {object} v1.ErrorResponse "Validator not found"
+// @Failure 429 {object} v1.ErrorResponse "Upstream provider is rate limiting requests"
+// @Failure 502 {object} v1.ErrorResponse "Upstream returned an invalid response"
+// @Failure 500 {object} v1.ErrorResponse "Internal server error"
+// @Failure 503 {object} v1.ErrorResponse "Network statistics not computed yet or disabled, upstream providers not reached since startup or upstream node not synced"
+// @Failure 504 {object} v1.ErrorResponse "Upstream request timed out"
+// @Router /validator/{id}/forecast [get]
+func (h *Handler) GetValidatorForecast(c *gin.Context) {
+	periodParam := c.DefaultQuery("period", "7d")
+	period, ok := forecastPeriods[periodParam]
+	if !ok {
+		c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: "Invalid period, expected 7d or 30d"})
+		return
+	}
+	unit, ok := parseUnit(c)
+	if !ok {
+		return
+	}
+
+	forecast, err := h.ethService.GetValidatorForecast(c.Request.Context(), c.Param("id"), period)
+	if err != nil {
+		if errors.Is(err, service.ErrNetworkStatsUnavailable) {
+			c.JSON(http.StatusServiceUnavailable, v1.ErrorResponse{Error: "Network statistics not available yet"})
+			return
+		}
+		respondValidatorError(c, err)
+		return
+	}
+
+	// Create response object
+	response := v1.ForecastResponse{
+		ValidatorIndex:   forecast.ValidatorIndex,
+		Status:           forecast.Status,
+		EffectiveBalance: forecast.EffectiveBalance,
+		Period:           periodParam,
+		Epochs:           forecast.Epochs,
+		Unit:             unit,
+		Total:            v1.FormatGwei(forecast.Total, unit),
+		APR:              forecast.APR,
+		Rewards: v1.ForecastRewardsResponse{
+			Attestation:       v1.FormatGwei(forecast.Attestation, unit),
+			SyncCommittee:     v1.FormatGwei(forecast.SyncCommittee, unit),
+			ProposerConsensus: v1.FormatGwei(forecast.ProposerConsensus, unit),
+			Execution:         v1.FormatGwei(forecast.Execution, unit),
+		},
+		Proposals: v1.ForecastProposalsResponse{
+			Expected:           forecast.ExpectedProposals,
+			Probability:        forecast.ProposalProbability,
+			AverageBlockReward: v1.FormatGwei(forecast.AverageBlockReward, unit),
+			MEVShare:           forecast.MEVShare,
+		},
+		SyncCommittee: v1.ForecastSyncCommitteeResponse{
+			Probability: forecast.SyncCommitteeProbability,
+			Payout:      v1.FormatGwei(forecast.SyncCommitteePayout, unit),
+		},
+		Network: v1.ForecastNetworkResponse{
+			Epoch:                  forecast.Network.Epoch,
+			ActiveValidators:       forecast.Network.ActiveValidators,
+			ActiveBalance:          forecast.Network.ActiveBalance,
+			IdealAttestationReward: forecast.Network.IdealAttestationReward,
+			Participation:          forecast.Network.Participation,
+		},
+	}
+
+	respondJSON(c, http.StatusOK, response)
+}
+
+// parseOptionalEpochRange parses the optional from_epoch and to_epoch query parameters, leaving the defaults to
+// the service, and responds with 400 when one is invalid
+func parseOptionalEpochRange(c *gin.Context) (fromEpoch, toEpoch *int64, ok bool) {
+	epochs := make([]*int64, 2)
+	for i, param := range []string{"from_epoch", "to_epoch"} {
+		value, ok := c.GetQuery(param)
+		if !ok {
+			continue
+		}
+		epoch, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || epoch < 0 {
+			c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: "Invalid epoch range", Details: param + " must be a non-negative integer"})
+			return nil, nil, false
+		}
+		epochs[i] = &epoch
+	}
+	return epochs[0], epochs[1], true
+}
+
+// defaultProposalsPerPage is the number of proposals per page when a cursor is given without a limit
+const defaultProposalsPerPage = 100
+
+// parseCursor parses the cursor and limit query parameters of endpoints paginated by slot, the cursor being the slot
+// the previous page ended at. Invalid values are answered with 400
+func parseCursor(c *gin.Context, defaultLimit, maxLimit int) (cursor *int64, limit int, ok bool) {
+	if value, ok := c.GetQuery("cursor"); ok {
+		slot, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || slot < 0 {
+			c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: "Invalid cursor", Details: "use the next_cursor of the previous page"})
+			return nil, 0, false
+		}
+		cursor = &slot
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultLimit)))
+	if err != nil || limit <= 0 || limit > maxLimit {
+		c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: "Invalid limit", Details: "use 1 to " + strconv.Itoa(maxLimit)})
+		return nil, 0, false
+	}
+	return cursor, limit, true
+}
+
+// Codes of the upstream failures, reported in the code field of the error response
+const (
+	codeUpstreamRateLimited     = "upstream_rate_limited"
+	codeUpstreamTimeout         = "upstream_timeout"
+	codeUpstreamInconsistent    = "upstream_inconsistent"
+	codeUpstreamInvalidResponse = "upstream_invalid_response"
+	codeUpstreamNotSynced       = "upstream_not_synced"
+	codeUpstreamUnavailable     = "upstream_unavailable"
+)
+
+// respondInternalError responds to errors without a more specific status, mapping the upstream failures to 429, 502,
+// 503 and 504 so clients can tell a busy or broken provider from a bug
+func respondInternalError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrUpstreamRateLimited):
+		// Tell the client how long the provider backs off, so it can schedule the retry itself
+		retryAfter := time.Second
+		var rateLimitErr *service.RateLimitError
+		if errors.As(err, &rateLimitErr) {
+			retryAfter = max(rateLimitErr.RetryAfter, time.Second)
+		}
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		c.JSON(http.StatusTooManyRequests, v1.ErrorResponse{Error: "Upstream provider is rate limiting requests, retry later", Code: codeUpstreamRateLimited})
+		return
+	case errors.Is(err, service.ErrUpstreamTimeout):
+		c.JSON(http.StatusGatewayTimeout, v1.ErrorResponse{Error: "Upstream request timed out", Code: codeUpstreamTimeout})
+		return
+	case errors.Is(err, service.ErrInconsistentResponse):
+		c.JSON(http.StatusBadGateway, v1.ErrorResponse{Error: "Upstream returned inconsistent data", Code: codeUpstreamInconsistent, Details: err.Error()})
+		return
+	case errors.Is(err, service.ErrUpstreamInvalidResponse):
+		c.JSON(http.StatusBadGateway, v1.ErrorResponse{Error: "Upstream returned an invalid response", Code: codeUpstreamInvalidResponse})
+		return
+	case errors.Is(err, service.ErrNotSynced):
+		c.Header("Retry-After", "10")
+		c.JSON(http.StatusServiceUnavailable, v1.ErrorResponse{Error: "Upstream node is not synced, retry later", Code: codeUpstreamNotSynced})
+		return
+	case errors.Is(err, service.ErrUpstreamUnavailable):
+		c.Header("Retry-After", "10")
+		c.JSON(http.StatusServiceUnavailable, v1.ErrorResponse{Error: "Upstream providers are unavailable, retry later", Code: codeUpstreamUnavailable})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, v1.ErrorResponse{Error: "Internal server error"})
+}
+
+// respondValidatorError maps service errors of the validator lookups to HTTP responses
+func respondValidatorError(c *gin.Context, err error) {
+	var mismatch *service.ProposerMismatchError
+	if errors.As(err, &mismatch) {
+		c.JSON(http.StatusNotFound, v1.ProposerMismatchResponse{
+			Error:         "Validator did not propose this slot",
+			Slot:          mismatch.Slot,
+			ProposerIndex: mismatch.ProposerIndex,
+		})
+		return
+	}
+
+	var statusCode int
+	var errMsg string
+
+	switch {
+	case errors.Is(err, service.ErrInvalidValidatorID):
+		statusCode = http.StatusBadRequest
+		errMsg = "Invalid validator index or pubkey"
+	case errors.Is(err, service.ErrFutureSlot):
+		statusCode = http.StatusBadRequest
+		errMsg = "Slot is in the future"
+	case errors.Is(err, service.ErrInvalidFeeRecipient):
+		statusCode = http.StatusBadRequest
+		errMsg = "Invalid fee recipient address"
+	case errors.Is(err, service.ErrInvalidRange):
+		statusCode = http.StatusBadRequest
+		errMsg = "Invalid epoch range"
+	case errors.Is(err, service.ErrValidatorNotFound):
+		statusCode = http.StatusNotFound
+		errMsg = "Validator does not exist"
+	case errors.Is(err, service.ErrSlotNotFound):
+		statusCode = http.StatusNotFound
+		errMsg = "Slot does not exist"
+	case errors.Is(err, service.ErrNoRecentProposal):
+		statusCode = http.StatusNotFound
+		errMsg = "No recent proposal found for validator"
+	case errors.Is(err, service.ErrProposalLookupIncomplete):
+		c.Header("Retry-After", "10")
+		statusCode = http.StatusServiceUnavailable
+		errMsg = "Recent proposals are not indexed yet, retry later"
+	default:
+		respondInternalError(c, err)
+		return
+	}
+
+	c.JSON(statusCode, v1.ErrorResponse{Error: errMsg})
+}
+
+// parseAsOfSlot parses the optional as_of_slot query parameter
+func parseAsOfSlot(c *gin.Context) (*int64, error) {
+	asOfParam, ok := c.GetQuery("as_of_slot")
+	if !ok {
+		return nil, nil
+	}
+
+	asOfSlot, err := parseSlot(asOfParam)
+	if err != nil {
+		return nil, errors.New("invalid as_of_slot")
+	}
+	return &asOfSlot, nil
+}
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"errors"
+	"ethereum-validator-api/api/models/v1"
+	"ethereum-validator-api/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strings"
+)
+
+// @Summary Summarize Validators
+// @Description Returns the status, balance, upcoming duties and attestation performance of the last 5 rewarded epochs of up to 500 validators in one call. Upstream lookups are shared by the whole list
+// @Tags validator
+// @Accept json
+// @Produce json,application/x-ndjson
+// @Param request body v1.ValidatorsSummaryRequest true "Validator indices or pubkeys"
+// @Param fields query string false "Comma separated fields to return, nested fields of objects and lists selected with dots"
+// @Param meta query bool false "Add a meta field reporting the providers that served the data, cache use, data age and finalization"
+// @Param offset query int false "Number of validators of the list to skip (default 0)"
+// @Param limit query int false "Maximum number of validators to return (default and max 500)"
+// @Param envelope query bool false "Return the validator summaries of the page as data of the standard envelope, with pagination metadata and a link to the next page"
+// @Success 200 {object} v1.ValidatorsSummaryResponse "Returns a summary per validator. With Accept: application/x-ndjson one v1.ValidatorSummaryResponse per line, with envelope=true a v1.EnvelopeResponse"
+// @Failure 400 {object} v1.ErrorResponse "Empty or too long list, invalid validator or pagination"
+// @Failure 429 {object} v1.ErrorResponse "Upstream provider is rate limiting requests"
+// @Failure 502 {object} v1.ErrorResponse "Upstream returned an invalid response"
+// @Failure 500 {object} v1.ErrorResponse "Internal server error"
+// @Failure 503 {object} v1.ErrorResponse "Upstream providers not reached since startup or upstream node not synced"
+// @Failure 504 {object} v1.ErrorResponse "Upstream request timed out"
+// @Router /validators/summary [post]
+func (h *Handler) PostValidatorsSummary(c *gin.Context) {
+	var request v1.ValidatorsSummaryRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: "Invalid request body", Details: "ids must be a list of validator indices or pubkeys"})
+		return
+	}
+	p, ok := parsePage(c, service.MaxSummaryValidators, service.MaxSummaryValidators)
+	if !ok {
+		return
+	}
+
+	summary, err := h.ethService.GetValidatorsSummary(c.Request.Context(), request.IDs)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidValidatorList):
+			c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: "Invalid validator list", Details: err.Error()})
+		case errors.Is(err, service.ErrInvalidValidatorID):
+			c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: "Invalid validator index or pubkey", Details: err.Error()})
+		default:
+			respondInternalError(c, err)
+		}
+		return
+	}
+	start, end := p.bounds(len(summary.Validators))
+	validators := summary.Validators[start:end]
+
+	if wantsNDJSON(c) {
+		stream := newNDJSONStream(c)
+		for _, validator := range validators {
+			if err := stream.Write(v1.NewValidatorSummaryResponse(validator)); err != nil {
+				break
+			}
+		}
+		stream.Close(nil, nil)
+		return
+	}
+
+	response := v1.ValidatorsSummaryResponse{
+		HeadSlot:   summary.HeadSlot,
+		FromEpoch:  summary.FromEpoch,
+		ToEpoch:    summary.ToEpoch,
+		Validators: make([]v1.ValidatorSummaryResponse, 0, len(validators)),
+	}
+	for _, validator := range validators {
+		response.Validators = append(response.Validators, v1.NewValidatorSummaryResponse(validator))
+	}
+
+	respondPage(c, http.StatusOK, response, response.Validators, p, len(summary.Validators))
+}
+
+// @Summary Get Validator APR
+// @Description Computes the annualized return of a validator or a validator set against the 32 ETH principal per validator from indexed consensus rewards, execution tips and MEV over a window ending at the latest indexed slot, with a breakdown per source and a comparison with the network average. Consensus rewards are annualized over the epochs their attestations are indexed for. Withdrawals within the window are reported apart, as they pay out consensus rewards
+// @Tags validator
+// @Param id path string true "Validator index or pubkey, or a comma separated list of up to 500"
+// @Param window query string false "Window to compute the return over: 1d, 7d or 30d (default 30d)"
+// @Param fields query string false "Comma separated fields to return, nested fields of objects and lists selected with dots"
+// @Param meta query bool false "Add a meta field reporting the providers that served the data, cache use, data age and finalization"
+// @Success 200 {object} v1.ValidatorAPRResponse "Returns the annualized return and its breakdown per source"
+// @Failure 400 {object} v1.ErrorResponse "Invalid window, too long list, or invalid validator"
+// @Failure 404 {object} v1.ErrorResponse "Validator not found"
+// @Failure 429 {object} v1.ErrorResponse "Upstream provider is rate limiting requests"
+// @Failure 502 {object} v1.ErrorResponse "Upstream returned an invalid response"
+// @Failure 500 {object} v1.ErrorResponse "Internal server error"
+// @Failure 503 {object} v1.ErrorResponse "Upstream providers not reached since startup or upstream node not synced"
+// @Failure 504 {object} v1.ErrorResponse "Upstream request timed out"
+// @Router /validator/{id}/apr [get]
+func (h *Handler) GetValidatorAPR(c *gin.Context) {
+	windowParam := c.DefaultQuery("window", "30d")
+	window, ok := statsWindows[windowParam]
+	if !ok {
+		c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: "Invalid window, expected 1d, 7d or 30d"})
+		return
+	}
+
+	apr, err := h.ethService.GetValidatorAPR(c.Request.Context(), strings.Split(c.Param("id"), ","), window)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidValidatorList) {
+			c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: "Invalid validator list", Details: err.Error()})
+			return
+		}
+		respondValidatorError(c, err)
+		return
+	}
+
+	// Create response object
+	response := v1.ValidatorAPRResponse{
+		Validators: apr.Validators,
+		Window:     windowParam,
+		FromSlot:   apr.FromSlot,
+		ToSlot:     apr.ToSlot,
+		Principal:  apr.Principal,
+		APR:        apr.Total.APR,
+		Rewards:    apr.Total.Amount,
+		Sources: v1.APRSourcesResponse{
+			Consensus:     v1.NewAPRSourceResponse(apr.Consensus),
+			ExecutionTips: v1.NewAPRSourceResponse(apr.ExecutionTips),
+			MEV:           v1.NewAPRSourceResponse(apr.MEV),
+		},
+		Withdrawals: v1.WithdrawalsResponse{
+			Count:  apr.WithdrawalCount,
+			Amount: apr.Withdrawals,
+		},
+		AttestationEpochs: apr.AttestationEpochs,
+		BlockCoverage:     apr.BlockCoverage,
+	}
+	if apr.Network != nil {
+		response.VsNetwork = &v1.APRComparisonResponse{
+			NetworkAPR: apr.Network.Total,
+			Difference: apr.Total.APR - apr.Network.Total,
+		}
+	}
+
+	respondJSON(c, http.StatusOK, response)
+}
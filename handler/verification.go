@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"errors"
+	"ethereum-validator-api/api/models/v1"
+	"ethereum-validator-api/service"
+	"github.com/gin-gonic/gin"
+	"log"
+)
+
+// verificationResponse cross-checks the block of a slot with the upstream providers while verification is enabled,
+// responding with the error status instead when the block can't be looked up. It returns nil while disabled
+func (h *Handler) verificationResponse(c *gin.Context, slot int64) (*v1.VerificationResponse, bool) {
+	if !h.ethService.VerificationEnabled() {
+		return nil, true
+	}
+
+	verification, err := h.ethService.VerifyBlock(c.Request.Context(), slot)
+	if err != nil {
+		respondInternalError(c, err)
+		return nil, false
+	}
+
+	response := v1.NewVerificationResponse(verification)
+	return &response, true
+}
+
+// lightClientVerified checks the block root of a slot against the light client while it is enabled, responding with
+// the error status instead when the root contradicts the verified chain. Roots that can't be checked, e.g. because the
+// upstream failed while walking back to them, are reported as unverified. It returns nil while disabled
+func (h *Handler) lightClientVerified(c *gin.Context, slot int64, root string) (*bool, bool) {
+	if !h.ethService.LightClientEnabled() {
+		return nil, true
+	}
+
+	verified, err := h.ethService.VerifyBlockRoot(c.Request.Context(), slot, root)
+	if errors.Is(err, service.ErrInconsistentResponse) {
+		respondInternalError(c, err)
+		return nil, false
+	}
+	if err != nil {
+		log.Printf("Warning: failed to verify block root of slot %d with the light client: %v", slot, err)
+	}
+	return &verified, true
+}
@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"errors"
+	"ethereum-validator-api/api/models/v1"
+	"ethereum-validator-api/service"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+// @Summary Get Watchlist
+// @Description Returns the validators, alert rules and notification channels of the account of the API key
+// @Tags watchlist
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} v1.WatchlistResponse "Returns the watchlist"
+// @Failure 401 {object} v1.ErrorResponse "Missing or unknown account API key"
+// @Router /watchlist [get]
+func (h *Handler) GetWatchlist(c *gin.Context) {
+	watchlist, err := h.ethService.Watchlists().Get(requestAPIKey(c))
+	if err != nil {
+		h.handleWatchlistError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, v1.NewWatchlistResponse(watchlist))
+}
+
+// @Summary Watch Validator
+// @Description Adds a validator to the watchlist of the account of the API key, at most 1000 validators can be watched
+// @Tags watchlist
+// @Security ApiKeyAuth
+// @Produce json
+// @Param index path int true "Validator index"
+// @Success 200 {object} v1.WatchlistResponse "Returns the updated watchlist"
+// @Failure 400 {object} v1.ErrorResponse "Invalid validator index or too many watched validators"
+// @Failure 401 {object} v1.ErrorResponse "Missing or unknown account API key"
+// @Router /watchlist/validators/{index} [put]
+func (h *Handler) PutWatchedValidator(c *gin.Context) {
+	index, ok := parseWatchedValidator(c)
+	if !ok {
+		return
+	}
+	h.updateWatchlist(c, h.ethService.Watchlists().Watch(requestAPIKey(c), index))
+}
+
+// @Summary Unwatch Validator
+// @Description Removes a validator from the watchlist of the account of the API key
+// @Tags watchlist
+// @Security ApiKeyAuth
+// @Produce json
+// @Param index path int true "Validator index"
+// @Success 200 {object} v1.WatchlistResponse "Returns the updated watchlist"
+// @Failure 400 {object} v1.ErrorResponse "Invalid validator index"
+// @Failure 401 {object} v1.ErrorResponse "Missing or unknown account API key"
+// @Router /watchlist/validators/{index} [delete]
+func (h *Handler) DeleteWatchedValidator(c *gin.Context) {
+	index, ok := parseWatchedValidator(c)
+	if !ok {
+		return
+	}
+	h.updateWatchlist(c, h.ethService.Watchlists().Unwatch(requestAPIKey(c), index))
+}
+
+// @Summary Set Alert Rules
+// @Description Replaces the alert rules of the account of the API key. A rule selects an event, missed_attestation, missed_proposal or slashed, of all watched validators or of the listed ones
+// @Tags watchlist
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param rules body v1.WatchlistRulesRequest true "Alert rules"
+// @Success 200 {object} v1.WatchlistResponse "Returns the updated watchlist"
+// @Failure 400 {object} v1.ErrorResponse "Invalid alert rule"
+// @Failure 401 {object} v1.ErrorResponse "Missing or unknown account API key"
+// @Router /watchlist/rules [put]
+func (h *Handler) PutWatchlistRules(c *gin.Context) {
+	var request v1.WatchlistRulesRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: "Invalid request body", Details: "rules must be a list of alert rules"})
+		return
+	}
+	rules := make([]service.AlertRule, 0, len(request.Rules))
+	for _, rule := range request.Rules {
+		rules = append(rules, service.AlertRule{Event: rule.Event, Validators: rule.Validators})
+	}
+	h.updateWatchlist(c, h.ethService.Watchlists().SetRules(requestAPIKey(c), rules))
+}
+
+// @Summary Set Notification Channels
+// @Description Replaces the channels alerts of the account of the API key are sent to, given as telegram:<bot token>:<chat id> or discord:<webhook URL>
+// @Tags watchlist
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param channels body v1.WatchlistChannelsRequest true "Notification channels"
+// @Success 200 {object} v1.WatchlistResponse "Returns the updated watchlist"
+// @Failure 400 {object} v1.ErrorResponse "Invalid notification channel"
+// @Failure 401 {object} v1.ErrorResponse "Missing or unknown account API key"
+// @Router /watchlist/channels [put]
+func (h *Handler) PutWatchlistChannels(c *gin.Context) {
+	var request v1.WatchlistChannelsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: "Invalid request body", Details: "channels must be a list of notification channels"})
+		return
+	}
+	h.updateWatchlist(c, h.ethService.Watchlists().SetChannels(requestAPIKey(c), request.Channels))
+}
+
+// @Summary Delete Account
+// @Description Deletes the account of the API key with its watchlist, the key is no longer accepted afterwards
+// @Tags watchlist
+// @Security ApiKeyAuth
+// @Success 204 "Account deleted"
+// @Failure 401 {object} v1.ErrorResponse "Missing or unknown account API key"
+// @Router /watchlist [delete]
+func (h *Handler) DeleteWatchlist(c *gin.Context) {
+	if err := h.ethService.Watchlists().RemoveAccount(requestAPIKey(c)); err != nil {
+		h.handleWatchlistError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Create Watchlist Account
+// @Description Creates a watchlist account with a new API key. The key is only returned in this response
+// @Tags admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 201 {object} v1.AccountResponse "Returns the ID and API key of the account"
+// @Failure 401 {object} v1.ErrorResponse "Missing or invalid API key"
+// @Router /admin/accounts [post]
+func (h *Handler) PostAccount(c *gin.Context) {
+	apiKey, id, err := h.ethService.Watchlists().NewAccount()
+	if err != nil {
+		h.handleWatchlistError(c, err)
+		return
+	}
+	h.recordAudit(c, service.AuditAccountCreate, id, nil, nil)
+	c.JSON(http.StatusCreated, v1.AccountResponse{ID: id, APIKey: apiKey})
+}
+
+// @Summary Delete Watchlist Account
+// @Description Deletes a watchlist account by its ID, revoking its API key
+// @Tags admin
+// @Security ApiKeyAuth
+// @Param id path string true "Account ID"
+// @Success 204 "Account deleted"
+// @Failure 401 {object} v1.ErrorResponse "Missing or invalid API key"
+// @Failure 404 {object} v1.ErrorResponse "Account not found"
+// @Router /admin/accounts/{id} [delete]
+func (h *Handler) DeleteAccount(c *gin.Context) {
+	id := c.Param("id")
+	if err := h.ethService.Watchlists().RemoveAccountID(id); err != nil {
+		h.handleWatchlistError(c, err)
+		return
+	}
+	h.recordAudit(c, service.AuditAccountDelete, id, nil, nil)
+	c.Status(http.StatusNoContent)
+}
+
+// parseWatchedValidator parses the validator index of a watchlist path, answering 400 if it is invalid
+func parseWatchedValidator(c *gin.Context) (int64, bool) {
+	index, err := strconv.ParseInt(c.Param("index"), 10, 64)
+	if err != nil || index < 0 {
+		c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: "Invalid validator index"})
+		return 0, false
+	}
+	return index, true
+}
+
+// updateWatchlist answers a change of the watchlist of the request's account with the updated watchlist
+func (h *Handler) updateWatchlist(c *gin.Context, err error) {
+	if err != nil {
+		h.handleWatchlistError(c, err)
+		return
+	}
+	h.GetWatchlist(c)
+}
+
+// handleWatchlistError maps watchlist errors to responses
+func (h *Handler) handleWatchlistError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidRule), errors.Is(err, service.ErrInvalidChannel),
+		errors.Is(err, service.ErrInvalidValidatorID), errors.Is(err, service.ErrInvalidRange):
+		c.JSON(http.StatusBadRequest, v1.ErrorResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrUnknownAccount):
+		c.JSON(http.StatusNotFound, v1.ErrorResponse{Error: "Account not found"})
+	default:
+		slog.Error("Failed to update watchlist", "error", err)
+		c.JSON(http.StatusInternalServerError, v1.ErrorResponse{Error: "Internal server error"})
+	}
+}
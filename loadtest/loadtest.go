@@ -0,0 +1,194 @@
+// Package loadtest drives concurrent, rate limited HTTP load against the API and checks the latencies and error rate
+// against service level objectives
+package loadtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Config describes a load test run
+type Config struct {
+	Targets     []string      // URLs requested in turn
+	Concurrency int           // Number of concurrent workers
+	Rate        float64       // Requests per second across all workers, 0 for no limit
+	Requests    int           // Total number of requests, 0 to run for Duration only
+	Duration    time.Duration // Maximum duration of the run, 0 to stop after Requests only
+	Timeout     time.Duration // Timeout of a single request
+	Client      *http.Client  // Client sending the requests, a default client if nil
+}
+
+// Result summarizes a load test run
+type Result struct {
+	Requests    int
+	Errors      int         // Requests that failed or were answered with a 5xx status
+	StatusCodes map[int]int // Responses by status code
+	Elapsed     time.Duration
+	latencies   []time.Duration // Sorted latencies of all requests
+}
+
+// RPS returns the achieved requests per second
+func (r *Result) RPS() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.Requests) / r.Elapsed.Seconds()
+}
+
+// ErrorRate returns the share of failed requests, 0 without requests
+func (r *Result) ErrorRate() float64 {
+	if r.Requests == 0 {
+		return 0
+	}
+	return float64(r.Errors) / float64(r.Requests)
+}
+
+// Percentile returns the latency below which the given percentage of requests completed
+func (r *Result) Percentile(p float64) time.Duration {
+	if len(r.latencies) == 0 {
+		return 0
+	}
+	index := int(float64(len(r.latencies))*p/100+0.5) - 1
+	return r.latencies[max(0, min(index, len(r.latencies)-1))]
+}
+
+// String formats the result as a short report
+func (r *Result) String() string {
+	return fmt.Sprintf("%d requests in %v (%.1f/s), %d errors (%.2f%%), p50 %v, p95 %v, p99 %v, status codes %v",
+		r.Requests, r.Elapsed.Round(time.Millisecond), r.RPS(), r.Errors, r.ErrorRate()*100,
+		r.Percentile(50), r.Percentile(95), r.Percentile(99), r.StatusCodes)
+}
+
+// SLO are the objectives a run is checked against, zero values are not checked
+type SLO struct {
+	P50          time.Duration
+	P95          time.Duration
+	P99          time.Duration
+	MaxErrorRate float64 // Highest acceptable share of failed requests
+}
+
+// Check returns an error listing the objectives the result misses
+func (s SLO) Check(r *Result) error {
+	var violations []error
+	for _, objective := range []struct {
+		percentile float64
+		limit      time.Duration
+	}{{50, s.P50}, {95, s.P95}, {99, s.P99}} {
+		if latency := r.Percentile(objective.percentile); objective.limit > 0 && latency > objective.limit {
+			violations = append(violations, fmt.Errorf("p%g latency %v above %v", objective.percentile, latency, objective.limit))
+		}
+	}
+	if s.MaxErrorRate > 0 && r.ErrorRate() > s.MaxErrorRate {
+		violations = append(violations, fmt.Errorf("error rate %.2f%% above %.2f%%", r.ErrorRate()*100, s.MaxErrorRate*100))
+	}
+	return errors.Join(violations...)
+}
+
+// Run sends the configured load and returns its result. It stops after the configured number of requests, the
+// duration or when ctx is done, whichever comes first
+func Run(ctx context.Context, config Config) (*Result, error) {
+	if len(config.Targets) == 0 {
+		return nil, errors.New("no targets to load")
+	}
+	if config.Concurrency <= 0 {
+		return nil, errors.New("concurrency must be positive")
+	}
+	if config.Requests <= 0 && config.Duration <= 0 {
+		return nil, errors.New("either requests or duration must be set")
+	}
+
+	client := config.Client
+	if client == nil {
+		client = &http.Client{Timeout: config.Timeout}
+	}
+	if config.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.Duration)
+		defer cancel()
+	}
+	limiter := rate.NewLimiter(rate.Inf, 0)
+	if config.Rate > 0 {
+		limiter = rate.NewLimiter(rate.Limit(config.Rate), 1)
+	}
+
+	var mu sync.Mutex
+	result := &Result{StatusCodes: make(map[int]int)}
+	next := 0
+	// claim reserves the next request, reporting false once all requests are sent
+	claim := func() (string, bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		if config.Requests > 0 && next >= config.Requests {
+			return "", false
+		}
+		target := config.Targets[next%len(config.Targets)]
+		next++
+		return target, true
+	}
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for range config.Concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				target, ok := claim()
+				if !ok || limiter.Wait(ctx) != nil {
+					return
+				}
+				latency, status, err := send(ctx, client, target, config.Timeout)
+				if ctx.Err() != nil {
+					// Requests cut short by the end of the run are not counted
+					return
+				}
+
+				mu.Lock()
+				result.Requests++
+				result.latencies = append(result.latencies, latency)
+				if err != nil || status >= http.StatusInternalServerError {
+					result.Errors++
+				}
+				if err == nil {
+					result.StatusCodes[status]++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	result.Elapsed = time.Since(start)
+	sort.Slice(result.latencies, func(i, j int) bool { return result.latencies[i] < result.latencies[j] })
+	return result, nil
+}
+
+// send requests the target and reads the whole response, returning how long it took
+func send(ctx context.Context, client *http.Client, target string, timeout time.Duration) (time.Duration, int, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	start := time.Now()
+	response, err := client.Do(request)
+	if err != nil {
+		return time.Since(start), 0, err
+	}
+	defer response.Body.Close()
+	_, err = io.Copy(io.Discard, response.Body)
+	return time.Since(start), response.StatusCode, err
+}
@@ -1,16 +1,12 @@
 package main
 
 import (
+	"context"
 	_ "ethereum-validator-api/docs" // This is important - imports the swagger docs
+	"ethereum-validator-api/handler"
 	"ethereum-validator-api/utils"
-	"github.com/gin-contrib/cors"
-	"github.com/gin-contrib/pprof"
 	"github.com/gin-gonic/gin"
-	swaggerFiles "github.com/swaggo/files"
-	ginSwagger "github.com/swaggo/gin-swagger"
 	"log"
-	"net/http"
-	"os"
 )
 
 // @title           Ethereum Validator API
@@ -27,53 +23,52 @@ import (
 // @host      localhost:3004
 // @BasePath  /
 
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+
 func main() {
 	utils.InitializeENV(".env")
-	router := gin.Default()
+	// Panics are answered with problem details carrying the request ID instead of gin's plain dump
+	router := gin.New()
+	router.Use(gin.Logger(), handler.RequestID(), handler.Recovery())
 
-	// Enable pprof endpoints (only in development/localhost)
-	if gin.Mode() != gin.ReleaseMode {
-		pprof.Register(router)
-		log.Println("pprof endpoints enabled at http://localhost:3004/debug/pprof/")
+	// Take client IPs from forwarding headers only when set by a trusted proxy
+	if err := utils.ConfigureTrustedProxies(router); err != nil {
+		log.Fatalf("Failed to configure trusted proxies: %v", err)
 	}
 
-	// Set up CORS with proper configuration
-	corsOrigin := os.Getenv("CORS_ORIGIN")
-	if corsOrigin == "" {
-		corsOrigin = "https://sf.dogukangun.de"
+	// Security headers, body limits and content type checks, as the API is internet-facing
+	if err := utils.ApplyHardening(router); err != nil {
+		log.Fatalf("Failed to configure request hardening: %v", err)
 	}
-	localCorsOrigin := "http://localhost:3003"
-	apiDomain := "https://sf-api.dogukangun.de"
-	
-	router.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{corsOrigin, localCorsOrigin, apiDomain, "https://sf.dogukangun.de"},
-		AllowMethods:     []string{"GET", "POST", "OPTIONS", "HEAD"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Requested-With"},
-		ExposeHeaders:    []string{"Content-Length"},
-		AllowCredentials: true,
-		MaxAge:           12 * 60 * 60,
-	}))
 
-	// Swagger documentation routes
-	// Redirect /docs to /swagger/index.html for better UX
-	router.GET("/docs", func(c *gin.Context) {
-		c.Redirect(http.StatusMovedPermanently, "/swagger/index.html")
-	})
-	
-	// Use the standard Swagger handler
-	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	// Set up CORS from the environment, reloaded with the rest of the reloadable configuration
+	corsConfig, err := utils.CORSConfigFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure CORS: %v", err)
+	}
+	corsMiddleware := utils.NewCORSMiddleware(corsConfig)
+	router.Use(corsMiddleware.Handle)
+	reloader := utils.NewReloader(".env")
+	reloader.SetCORS(corsMiddleware)
+
+	// Profiling and documentation endpoints, pprof only when enabled and always behind auth
+	if err := utils.RegisterDebugEndpoints(router); err != nil {
+		log.Fatalf("Failed to setup debug endpoints: %v", err)
+	}
 
 	// Setup the API endpoints
-	err := utils.SetupEndpoints(router)
+	err = utils.SetupEndpoints(router, reloader)
 	if err != nil {
 		log.Fatalf("Failed to setup endpoints: %v", err)
 	}
-	
-	// Start the server
-	log.Println("Server starting at http://localhost:3004")
-	log.Println("Swagger UI available at http://localhost:3004/swagger/index.html")
-	
-	if err := router.Run(":3004"); err != nil {
+
+	// Reload the configuration on SIGHUP
+	go reloader.WatchSignals(context.Background())
+
+	// Start the server, over HTTPS when TLS is configured
+	if err := utils.Serve(router); err != nil {
 		log.Fatalf("Failed to start server: %v", err)
 	}
 }
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// notificationTimeout bounds the delivery of a notification to a single channel
+const notificationTimeout = 10 * time.Second
+
+// Alert is an event of a watched validator, delivered to the channels of the accounts with a rule for it
+type Alert struct {
+	Event     string // One of the Alert constants
+	Validator int64
+	Epoch     int64
+	Slot      int64 // Slot of a missed proposal, 0 otherwise
+}
+
+// notification renders the alert as a notification
+func (a Alert) notification() Notification {
+	switch a.Event {
+	case AlertMissedAttestation:
+		return Notification{
+			Title:   fmt.Sprintf("Validator %d missed an attestation", a.Validator),
+			Message: fmt.Sprintf("The attestation of validator %d for epoch %d was not rewarded.", a.Validator, a.Epoch),
+		}
+	case AlertMissedProposal:
+		return Notification{
+			Title:   fmt.Sprintf("Validator %d missed a proposal", a.Validator),
+			Message: fmt.Sprintf("Validator %d was assigned slot %d in epoch %d, but no block was proposed.", a.Validator, a.Slot, a.Epoch),
+		}
+	default:
+		return Notification{
+			Title:   fmt.Sprintf("Validator %d was slashed", a.Validator),
+			Message: fmt.Sprintf("Validator %d was found slashed in epoch %d.", a.Validator, a.Epoch),
+		}
+	}
+}
+
+// AlertTask returns the task notifying accounts about the missed attestations, missed proposals and slashings of the
+// validators they watch. It checks the last rewarded epoch once per epoch, epochs skipped while the task couldn't
+// run aren't checked afterwards. Validators already slashed when they are first seen don't raise an alert
+func (s *EthereumService) AlertTask() Task {
+	// Notifications go to third parties, so they don't use the upstream client and its budgets
+	client := &http.Client{Timeout: notificationTimeout}
+
+	// Runs of a task never overlap, so the closure needs no locking
+	checked := int64(-1)
+	slashed := make(map[int64]bool) // Whether each watched validator was slashed at the last check
+	return Task{
+		Name:     "watchlist-alerts",
+		Schedule: Every(epochDuration),
+		Run: func(ctx context.Context) error {
+			ctx = WithPriority(ctx, PriorityBatch)
+			watched := s.watchlists.Watched()
+			if len(watched) == 0 {
+				return nil
+			}
+
+			head, err := s.GetChainHead(ctx)
+			if err != nil {
+				return err
+			}
+			epoch := lastRewardedEpoch(head.Slot)
+			if epoch < 0 || epoch <= checked {
+				return nil
+			}
+
+			alerts, states, err := s.findAlerts(ctx, head, epoch, watched, slashed)
+			if err != nil {
+				return err
+			}
+			checked, slashed = epoch, states
+			return s.sendAlerts(ctx, client, alerts)
+		},
+	}
+}
+
+// findAlerts returns the alerts of the watched validators for an epoch and whether each of them is slashed. A slashing
+// is alerted when the validator wasn't slashed at the previous check, given by slashed
+func (s *EthereumService) findAlerts(ctx context.Context, head *ChainHead, epoch int64, watched []int64, slashed map[int64]bool) ([]Alert, map[int64]bool, error) {
+	indices := make([]string, 0, len(watched))
+	for _, index := range watched {
+		indices = append(indices, strconv.FormatInt(index, 10))
+	}
+
+	var alerts []Alert
+
+	// A vote that was neither rewarded for its source, target nor head wasn't included in time
+	var rewards attestationRewardsResponse
+	if err := s.postBeaconJSON(ctx, fmt.Sprintf("/eth/v1/beacon/rewards/attestations/%d", epoch), indices, &rewards); err != nil {
+		return nil, nil, fmt.Errorf("failed to get attestation rewards of epoch %d: %w", epoch, err)
+	}
+	for _, total := range rewards.Data.TotalRewards {
+		if parseDecimal(total.Head) <= 0 && parseDecimal(total.Target) <= 0 && parseDecimal(total.Source) <= 0 {
+			alerts = append(alerts, Alert{Event: AlertMissedAttestation, Validator: parseDecimal(total.ValidatorIndex), Epoch: epoch})
+		}
+	}
+
+	duties, err := s.getProposerDuties(ctx, epoch, epoch <= head.Finalized.Epoch)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, duty := range duties.Data {
+		index := parseDecimal(duty.ValidatorIndex)
+		if _, watching := slices.BinarySearch(watched, index); !watching {
+			continue
+		}
+		slot := parseDecimal(duty.Slot)
+		var root blockRootResponse
+		err := s.getBeaconJSON(ctx, fmt.Sprintf("/eth/v1/beacon/blocks/%d/root", slot), &root)
+		if errors.Is(err, ErrSlotNotFound) {
+			alerts = append(alerts, Alert{Event: AlertMissedProposal, Validator: index, Epoch: epoch, Slot: slot})
+		} else if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var validators validatorsResponse
+	if err := s.getBeaconJSON(ctx, "/eth/v1/beacon/states/head/validators?id="+strings.Join(indices, ","), &validators); err != nil {
+		return nil, nil, fmt.Errorf("failed to get watched validators: %w", err)
+	}
+	states := make(map[int64]bool, len(validators.Data))
+	for _, validator := range validators.Data {
+		index := parseDecimal(validator.Index)
+		states[index] = validator.Validator.Slashed
+		if was, seen := slashed[index]; seen && !was && validator.Validator.Slashed {
+			alerts = append(alerts, Alert{Event: AlertSlashed, Validator: index, Epoch: head.Epoch})
+		}
+	}
+	return alerts, states, nil
+}
+
+// sendAlerts delivers every alert to the channels of the accounts watching for it, each channel at most once per
+// alert. Failed deliveries don't stop the others
+func (s *EthereumService) sendAlerts(ctx context.Context, client *http.Client, alerts []Alert) error {
+	sent, failed := 0, 0
+	for _, alert := range alerts {
+		channels := s.watchlists.Watchers(alert.Event, alert.Validator)
+		slices.Sort(channels)
+		for _, channel := range slices.Compact(channels) {
+			notifier, err := ParseNotifier(client, channel)
+			if err == nil {
+				err = notifier.Notify(ctx, alert.notification())
+			}
+			if err != nil {
+				// Channels carry credentials, only their kind is logged
+				kind, _, _ := strings.Cut(channel, ":")
+				slog.Warn("Failed to send alert", "event", alert.Event, "validator", alert.Validator, "channel", kind, "error", err)
+				failed++
+				continue
+			}
+			sent++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d alert notifications failed", failed, sent+failed)
+	}
+	return nil
+}
@@ -0,0 +1,298 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// principalGwei is the stake returns are measured against, 32 ETH per validator
+const principalGwei = 32_000_000_000
+
+// yearDuration is the period returns are annualized to
+const yearDuration = 365 * 24 * time.Hour
+
+// APRSource represents the rewards of one source and the annualized return they amount to
+type APRSource struct {
+	Amount int64   // in GWEI
+	APR    float64 // Annualized return on the principal, 0.03 for 3%
+}
+
+// ValidatorAPR represents the annualized return of a set of validators over a window of indexed data
+type ValidatorAPR struct {
+	Validators []int64
+	FromSlot   int64
+	ToSlot     int64
+	Principal  int64 // in GWEI, 32 ETH per validator
+
+	Consensus     APRSource // Attestation rewards, annualized over the epochs they are indexed for
+	ExecutionTips APRSource // Priority fees of blocks built locally
+	MEV           APRSource // Payments of builders for blocks built via MEV-Boost
+	Total         APRSource
+
+	Withdrawals       int64 // Withdrawals of the validators within the window, in GWEI
+	WithdrawalCount   int
+	AttestationEpochs int64   // Validator epochs with indexed attestation rewards
+	BlockCoverage     float64 // Share of the window's slots with an indexed block reward
+
+	Network *NetworkAPR // Network average over the same window, nil while network statistics are not available
+}
+
+// NetworkAPR represents the average annualized return of the network's validators over a window
+type NetworkAPR struct {
+	FromSlot         int64
+	ToSlot           int64
+	Epoch            int64 // Epoch the ideal attestation rewards are taken from
+	ActiveValidators int
+	ActiveBalance    int64 // in GWEI
+
+	Consensus     float64 // Ideal attestation rewards of 32 ETH scaled by the average participation
+	ExecutionTips float64 // Priority fees of all indexed locally built blocks on the active balance
+	MEV           float64 // Builder payments of all indexed MEV-Boost blocks on the active balance
+	Total         float64
+
+	IdealAttestationReward int64   // Reward of a 32 ETH validator voting correctly in Epoch, in GWEI
+	Participation          float64 // Average participation of the recent epochs
+	ExecutionRewards       int64   // Execution rewards of the indexed blocks within the window, in GWEI
+	BlockCoverage          float64 // Share of the window's slots with an indexed block reward
+}
+
+// GetValidatorAPR computes the annualized return of validators from the rewards indexed within a window ending at
+// the latest indexed slot. validatorIDs are indices or pubkeys, at most MaxSummaryValidators of them
+func (s *EthereumService) GetValidatorAPR(ctx context.Context, validatorIDs []string, window time.Duration) (*ValidatorAPR, error) {
+	if len(validatorIDs) == 0 || len(validatorIDs) > MaxSummaryValidators {
+		return nil, fmt.Errorf("%w: between 1 and %d validators expected", ErrInvalidValidatorList, MaxSummaryValidators)
+	}
+	if window <= 0 {
+		return nil, ErrInvalidRange
+	}
+	for _, id := range validatorIDs {
+		if err := validateValidatorID(id); err != nil {
+			return nil, fmt.Errorf("%w: %q", err, id)
+		}
+	}
+
+	indices, err := s.resolveValidatorIndices(ctx, validatorIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	toSlot := s.index.LatestRewardSlot()
+	if toSlot < 0 {
+		toSlot = s.index.LatestSlot()
+	}
+	fromSlot := max(toSlot-int64(window/(SecondsPerSlot*time.Second))+1, 0)
+
+	apr := &ValidatorAPR{
+		Validators: indices,
+		FromSlot:   fromSlot,
+		ToSlot:     toSlot,
+		Principal:  principalGwei * int64(len(indices)),
+	}
+	members := make(map[int64]bool, len(indices))
+	for _, index := range indices {
+		members[index] = true
+	}
+
+	// Consensus rewards are only indexed for validators whose attestations were looked up, so they are annualized
+	// over the epochs they cover rather than the window
+	for _, index := range indices {
+		for _, attestation := range s.index.Attestations(index, fromSlot/SlotsPerEpoch, toSlot/SlotsPerEpoch) {
+			apr.Consensus.Amount += attestation.Reward
+			apr.AttestationEpochs++
+		}
+	}
+
+	// Execution rewards of the validators' proposals, split by whether a builder paid for the block
+	slots := s.index.RewardSlots(fromSlot, toSlot)
+	for _, slot := range slots {
+		block, ok := s.index.Get(slot)
+		if !ok || !members[block.ProposerIndex] {
+			continue
+		}
+		reward, ok := s.index.GetReward(slot)
+		if !ok || reward.Reward == nil {
+			continue
+		}
+		if reward.Status == "mev" {
+			apr.MEV.Amount += reward.Reward.Int64()
+		} else {
+			apr.ExecutionTips.Amount += reward.Reward.Int64()
+		}
+	}
+
+	// Withdrawals pay out consensus rewards, so they are reported apart from the return
+	for _, block := range s.index.Range(fromSlot, toSlot, nil) {
+		for _, withdrawal := range block.Withdrawals {
+			if members[withdrawal.ValidatorIndex] {
+				apr.Withdrawals += withdrawal.Amount
+				apr.WithdrawalCount++
+			}
+		}
+	}
+
+	windowSlots := toSlot - fromSlot + 1
+	if toSlot >= 0 && windowSlots > 0 {
+		apr.BlockCoverage = float64(len(slots)) / float64(windowSlots)
+	}
+
+	// Each validator's covered epochs count towards the principal of one validator
+	epochYears := float64(apr.AttestationEpochs) * float64(epochDuration) / float64(yearDuration)
+	apr.Consensus.APR = annualize(apr.Consensus.Amount, principalGwei, epochYears)
+	windowYears := float64(windowSlots) * SecondsPerSlot * float64(time.Second) / float64(yearDuration)
+	apr.ExecutionTips.APR = annualize(apr.ExecutionTips.Amount, apr.Principal, windowYears)
+	apr.MEV.APR = annualize(apr.MEV.Amount, apr.Principal, windowYears)
+	apr.Total = APRSource{
+		Amount: apr.Consensus.Amount + apr.ExecutionTips.Amount + apr.MEV.Amount,
+		APR:    apr.Consensus.APR + apr.ExecutionTips.APR + apr.MEV.APR,
+	}
+
+	// The comparison is left out rather than failing the validator's return while the network average is unknown
+	if network, err := s.GetNetworkAPR(ctx, window); err == nil {
+		apr.Network = network
+	}
+	return apr, nil
+}
+
+// GetNetworkAPR computes the average annualized return of the active validators over a window ending at the latest
+// indexed slot, cached for an epoch. Attestation rewards are the ideal rewards of the latest rewarded epoch scaled by
+// the recent participation, execution rewards are those of all indexed blocks spread over the active balance
+func (s *EthereumService) GetNetworkAPR(ctx context.Context, window time.Duration) (*NetworkAPR, error) {
+	if window <= 0 {
+		return nil, ErrInvalidRange
+	}
+	cacheKey := "network:apr:" + window.String()
+	if cached, ok := s.cacheGet(ctx, cacheKey); ok {
+		return cached.(*NetworkAPR), nil
+	}
+
+	stats, err := s.GetNetworkStats()
+	if err != nil {
+		return nil, err
+	}
+	if len(stats.Participation) == 0 || stats.TotalEffectiveBalance <= 0 {
+		return nil, ErrNetworkStatsUnavailable
+	}
+	epoch := stats.Participation[len(stats.Participation)-1].Epoch
+
+	ideal, err := s.getIdealAttestationReward(ctx, epoch)
+	if err != nil {
+		return nil, err
+	}
+
+	toSlot := s.index.LatestRewardSlot()
+	if toSlot < 0 {
+		toSlot = s.index.LatestSlot()
+	}
+	fromSlot := max(toSlot-int64(window/(SecondsPerSlot*time.Second))+1, 0)
+
+	network := &NetworkAPR{
+		FromSlot:               fromSlot,
+		ToSlot:                 toSlot,
+		Epoch:                  epoch,
+		ActiveValidators:       stats.ActiveValidators,
+		ActiveBalance:          stats.TotalEffectiveBalance,
+		IdealAttestationReward: ideal,
+	}
+	for _, participation := range stats.Participation {
+		network.Participation += participation.Rate
+	}
+	network.Participation /= float64(len(stats.Participation))
+
+	var tips, mev int64
+	slots := s.index.RewardSlots(fromSlot, toSlot)
+	for _, slot := range slots {
+		reward, ok := s.index.GetReward(slot)
+		if !ok || reward.Reward == nil {
+			continue
+		}
+		if reward.Status == "mev" {
+			mev += reward.Reward.Int64()
+		} else {
+			tips += reward.Reward.Int64()
+		}
+	}
+	network.ExecutionRewards = tips + mev
+
+	windowSlots := toSlot - fromSlot + 1
+	if toSlot >= 0 && windowSlots > 0 {
+		network.BlockCoverage = float64(len(slots)) / float64(windowSlots)
+	}
+
+	epochYears := float64(epochDuration) / float64(yearDuration)
+	network.Consensus = annualize(ideal, principalGwei, epochYears) * network.Participation
+	windowYears := float64(windowSlots) * SecondsPerSlot * float64(time.Second) / float64(yearDuration)
+	network.ExecutionTips = annualize(tips, stats.TotalEffectiveBalance, windowYears)
+	network.MEV = annualize(mev, stats.TotalEffectiveBalance, windowYears)
+	network.Total = network.Consensus + network.ExecutionTips + network.MEV
+
+	s.cache.Set(cacheKey, network, epochDuration)
+	return network, nil
+}
+
+// getIdealAttestationReward returns the head, target and source rewards of a 32 ETH validator voting correctly in an
+// epoch. The ideal rewards are reported for every effective balance, so a single validator is requested
+func (s *EthereumService) getIdealAttestationReward(ctx context.Context, epoch int64) (int64, error) {
+	var rewards attestationRewardsResponse
+	if err := s.postBeaconJSON(ctx, fmt.Sprintf("/eth/v1/beacon/rewards/attestations/%d", epoch), []string{"0"}, &rewards); err != nil {
+		return 0, err
+	}
+	for _, ideal := range rewards.Data.IdealRewards {
+		if parseDecimal(ideal.EffectiveBalance) == principalGwei {
+			return parseDecimal(ideal.Head) + parseDecimal(ideal.Target) + parseDecimal(ideal.Source), nil
+		}
+	}
+	return 0, fmt.Errorf("%w: no ideal attestation rewards for 32 ETH in epoch %d", ErrUpstreamInvalidResponse, epoch)
+}
+
+// annualize converts an amount earned on principal over a number of years into an annual rate
+func annualize(amount, principal int64, years float64) float64 {
+	if principal <= 0 || years <= 0 {
+		return 0
+	}
+	return float64(amount) / float64(principal) / years
+}
+
+// resolveValidatorIndices returns the indices of validators given by index or pubkey without duplicates. Indices are
+// taken as they are, pubkeys are looked up at the head
+func (s *EthereumService) resolveValidatorIndices(ctx context.Context, validatorIDs []string) ([]int64, error) {
+	indices := make([]int64, 0, len(validatorIDs))
+	seen := make(map[int64]bool, len(validatorIDs))
+	var pubkeys []string
+	for _, id := range validatorIDs {
+		if !strings.HasPrefix(id, "0x") {
+			index, _ := strconv.ParseInt(id, 10, 64)
+			if !seen[index] {
+				seen[index] = true
+				indices = append(indices, index)
+			}
+			continue
+		}
+		pubkeys = append(pubkeys, strings.ToLower(id))
+	}
+	if len(pubkeys) == 0 {
+		return indices, nil
+	}
+
+	var validators validatorsResponse
+	if err := s.getValidatorsByID(ctx, "head", pubkeys, &validators); err != nil {
+		return nil, err
+	}
+	found := make(map[string]int64, len(validators.Data))
+	for _, data := range validators.Data {
+		found[strings.ToLower(data.Validator.Pubkey)] = parseDecimal(data.Index)
+	}
+	for _, pubkey := range pubkeys {
+		index, ok := found[pubkey]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrValidatorNotFound, pubkey)
+		}
+		if !seen[index] {
+			seen[index] = true
+			indices = append(indices, index)
+		}
+	}
+	return indices, nil
+}
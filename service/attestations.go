@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// MaxAttestationEpochs bounds how many epochs of attestation performance can be requested at once
+const MaxAttestationEpochs = 100
+
+// AttestationPerformance represents how a validator's attestation for an epoch was rewarded.
+// After Altair a vote is only rewarded when it is both correct and included in time, so the
+// correctness flags also imply timeliness: source within 5 slots, target within 32, head within 1.
+type AttestationPerformance struct {
+	Epoch          int64
+	Included       bool
+	InclusionDelay int64 // in slots, 1 when the head vote was rewarded, 0 if unknown or not included
+	HeadCorrect    bool
+	TargetCorrect  bool
+	SourceCorrect  bool
+	Reward         int64 // sum of the head, target and source rewards in GWEI, negative when penalized
+}
+
+// attestationRewardsResponse represents the response from /eth/v1/beacon/rewards/attestations/{epoch}
+type attestationRewardsResponse struct {
+	Data struct {
+		TotalRewards []struct {
+			ValidatorIndex string `json:"validator_index"`
+			Head           string `json:"head"`
+			Target         string `json:"target"`
+			Source         string `json:"source"`
+		} `json:"total_rewards"`
+		IdealRewards []struct {
+			EffectiveBalance string `json:"effective_balance"`
+			Head             string `json:"head"`
+			Target           string `json:"target"`
+			Source           string `json:"source"`
+		} `json:"ideal_rewards"`
+	} `json:"data"`
+}
+
+// GetValidatorAttestations returns the attestation performance of a validator for the last epochs
+// that have been rewarded, newest first. Results are kept in the block index so repeated lookups
+// only fetch epochs that were not seen before.
+func (s *EthereumService) GetValidatorAttestations(ctx context.Context, validatorID string, epochs int64) ([]*AttestationPerformance, error) {
+	performance := make([]*AttestationPerformance, 0, epochs)
+	err := s.StreamValidatorAttestations(ctx, validatorID, epochs, func(perf *AttestationPerformance) error {
+		performance = append(performance, perf)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return performance, nil
+}
+
+// StreamValidatorAttestations is GetValidatorAttestations passing the performance of each epoch to emit as soon as it
+// is known, newest first. An error returned by emit stops the lookup
+func (s *EthereumService) StreamValidatorAttestations(ctx context.Context, validatorID string, epochs int64, emit func(*AttestationPerformance) error) error {
+	index, err := s.resolveValidatorIndex(ctx, validatorID)
+	if err != nil {
+		return err
+	}
+
+	headSlot, err := s.getHeadSlot(ctx)
+	if err != nil {
+		return err
+	}
+
+	return s.eachAttestationUntil(ctx, index, lastRewardedEpoch(headSlot), epochs, emit)
+}
+
+// lastRewardedEpoch returns the latest epoch whose rewards are known at the head slot,
+// which requires the following epoch to have been processed
+func lastRewardedEpoch(headSlot int64) int64 {
+	return headSlot/SlotsPerEpoch - 2
+}
+
+// getAttestationsUntil returns the attestation performance of a validator for the epochs ending at lastEpoch, newest first
+func (s *EthereumService) getAttestationsUntil(ctx context.Context, index, lastEpoch, epochs int64) ([]*AttestationPerformance, error) {
+	performance := make([]*AttestationPerformance, 0, epochs)
+	err := s.eachAttestationUntil(ctx, index, lastEpoch, epochs, func(perf *AttestationPerformance) error {
+		performance = append(performance, perf)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return performance, nil
+}
+
+// eachAttestationUntil passes the attestation performance of a validator for the epochs ending at lastEpoch to emit,
+// newest first, skipping epochs the validator was not attesting in
+func (s *EthereumService) eachAttestationUntil(ctx context.Context, index, lastEpoch, epochs int64, emit func(*AttestationPerformance) error) error {
+	for epoch := lastEpoch; epoch >= 0 && epoch > lastEpoch-epochs; epoch-- {
+		perf, err := s.getAttestationPerformance(ctx, index, epoch)
+		if err != nil {
+			return err
+		}
+		if perf == nil {
+			continue
+		}
+		if err := emit(perf); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getAttestationPerformance returns the attestation performance of a validator for an epoch,
+// or nil if the validator was not attesting in that epoch
+func (s *EthereumService) getAttestationPerformance(ctx context.Context, validatorIndex, epoch int64) (*AttestationPerformance, error) {
+	if s.index != nil {
+		if perf, ok := s.index.GetAttestation(validatorIndex, epoch); ok {
+			return perf, nil
+		}
+	}
+
+	var rewards attestationRewardsResponse
+	path := fmt.Sprintf("/eth/v1/beacon/rewards/attestations/%d", epoch)
+	if err := s.postBeaconJSON(ctx, path, []string{strconv.FormatInt(validatorIndex, 10)}, &rewards); err != nil {
+		return nil, err
+	}
+	if len(rewards.Data.TotalRewards) == 0 {
+		return nil, nil
+	}
+
+	total := rewards.Data.TotalRewards[0]
+	head, target, source := parseDecimal(total.Head), parseDecimal(total.Target), parseDecimal(total.Source)
+	perf := &AttestationPerformance{
+		Epoch:         epoch,
+		Included:      head > 0 || target > 0 || source > 0,
+		HeadCorrect:   head > 0,
+		TargetCorrect: target > 0,
+		SourceCorrect: source > 0,
+		Reward:        head + target + source,
+	}
+	if perf.HeadCorrect {
+		perf.InclusionDelay = 1
+	}
+
+	if s.index != nil {
+		s.index.PutAttestation(validatorIndex, perf)
+	}
+	return perf, nil
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Admin actions recorded in the audit log
+const (
+	AuditBuilderPut    = "builder.put"
+	AuditBuilderDelete = "builder.delete"
+	AuditCacheDelete   = "cache.delete"
+	AuditConfigReload  = "config.reload"
+	AuditCapturePut    = "capture.put"
+	AuditAccountCreate = "account.create"
+	AuditAccountDelete = "account.delete"
+)
+
+// AuditEntry records an admin action with the values it replaced and the values it set
+type AuditEntry struct {
+	Time   time.Time       `json:"time"`
+	Actor  string          `json:"actor"`            // Client that performed the action, identified like in the usage, or the signal it was triggered by
+	Action string          `json:"action"`           // One of the Audit constants
+	Target string          `json:"target,omitempty"` // Object the action applied to, e.g. the builder name or the slot
+	Before json.RawMessage `json:"before,omitempty"` // Value before the action, omitted when there was none
+	After  json.RawMessage `json:"after,omitempty"`  // Value after the action, omitted when it was removed
+}
+
+// AuditFilter selects audit entries, empty fields match every entry
+type AuditFilter struct {
+	Actor  string
+	Action string
+	Since  time.Time // Only entries at or after this time
+	Limit  int       // Maximum number of entries, all if zero
+}
+
+// AuditLog keeps the admin actions in order. Unlike the usage, every entry is appended to the file of the log right
+// away so a crash can't lose it
+type AuditLog struct {
+	mu      sync.RWMutex
+	entries []AuditEntry
+	path    string // JSON lines file the log is appended to, "" to keep it in memory only
+	clock   Clock  // Clock the entries are stamped with
+}
+
+// NewAuditLog creates an empty AuditLog kept in memory only
+func NewAuditLog() *AuditLog {
+	return &AuditLog{clock: SystemClock{}}
+}
+
+// LoadAuditLog creates an AuditLog appended to a JSON lines file, starting from the entries it already contains. A
+// missing file is created by the first Record
+func LoadAuditLog(path string) (*AuditLog, error) {
+	audit := &AuditLog{path: path, clock: SystemClock{}}
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return audit, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("failed to decode audit log: %w", err)
+		}
+		audit.entries = append(audit.entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	return audit, nil
+}
+
+// Record appends an action to the log, before and after are encoded as JSON and omitted when nil. The entry is kept
+// in memory even when it can't be written to the file
+func (l *AuditLog) Record(actor, action, target string, before, after interface{}) error {
+	l.mu.RLock()
+	now := l.clock.Now()
+	l.mu.RUnlock()
+
+	entry := AuditEntry{Time: now.UTC(), Actor: actor, Action: action, Target: target}
+	var err error
+	if before != nil {
+		if entry.Before, err = json.Marshal(before); err != nil {
+			return fmt.Errorf("failed to encode audit entry: %w", err)
+		}
+	}
+	if after != nil {
+		if entry.After, err = json.Marshal(after); err != nil {
+			return fmt.Errorf("failed to encode audit entry: %w", err)
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, entry)
+	if l.path == "" {
+		return nil
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+	file, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// Query returns the entries matching the filter, newest first
+func (l *AuditLog) Query(filter AuditFilter) []AuditEntry {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	entries := make([]AuditEntry, 0)
+	for i := len(l.entries) - 1; i >= 0; i-- {
+		entry := l.entries[i]
+		if filter.Limit > 0 && len(entries) == filter.Limit {
+			break
+		}
+		if (filter.Actor != "" && entry.Actor != filter.Actor) || (filter.Action != "" && entry.Action != filter.Action) ||
+			entry.Time.Before(filter.Since) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// Audit returns the log of admin actions
+func (s *EthereumService) Audit() *AuditLog {
+	return s.audit
+}
+
+// SetAudit replaces the log of admin actions, e.g. with one appended to a file
+func (s *EthereumService) SetAudit(audit *AuditLog) {
+	audit.setClock(s.clock)
+	s.audit = audit
+}
+
+// setClock replaces the clock the entries are stamped with
+func (l *AuditLog) setClock(clock Clock) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.clock = clock
+}
@@ -0,0 +1,256 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// beaconErrorResponse represents the error body returned by the Beacon API
+type beaconErrorResponse struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// HTTPBeaconClient is a BeaconClient talking to a Beacon API over HTTP
+type HTTPBeaconClient struct {
+	baseURL string
+	client  *http.Client
+
+	validatorsMu sync.Mutex
+	validators   map[string]validatedResponse // Responses to conditional requests keyed by path
+}
+
+// validatedResponse is a response body with the validators a provider sent for it
+type validatedResponse struct {
+	etag         string
+	lastModified string
+	body         []byte
+}
+
+// maxValidatedResponses bounds the responses kept for conditional requests, near-head refreshes only cover the last
+// epochs
+const maxValidatedResponses = 256
+
+// conditionalKey is the context key marking upstream requests as conditional
+type conditionalKey struct{}
+
+// WithConditional returns a context whose Beacon API GET requests send the validators of an earlier response, so a
+// provider supporting them answers 304 Not Modified instead of sending the unchanged body again
+func WithConditional(ctx context.Context) context.Context {
+	return context.WithValue(ctx, conditionalKey{}, true)
+}
+
+// isConditional reports whether the requests of ctx are conditional
+func isConditional(ctx context.Context) bool {
+	conditional, _ := ctx.Value(conditionalKey{}).(bool)
+	return conditional
+}
+
+// NewHTTPBeaconClient creates a BeaconClient for the Beacon API at baseURL
+func NewHTTPBeaconClient(baseURL string, client *http.Client) *HTTPBeaconClient {
+	return &HTTPBeaconClient{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		client:     client,
+		validators: make(map[string]validatedResponse),
+	}
+}
+
+// GetJSON performs a GET request against the Beacon API and decodes the response into out
+func (b *HTTPBeaconClient) GetJSON(ctx context.Context, path string, out interface{}) error {
+	return b.do(ctx, "GET", path, nil, out)
+}
+
+// PostJSON performs a POST request with a JSON body against the Beacon API and decodes the response into out
+func (b *HTTPBeaconClient) PostJSON(ctx context.Context, path string, body interface{}, out interface{}) error {
+	reqBody := getBuffer()
+	defer putBuffer(reqBody)
+	if err := json.NewEncoder(reqBody).Encode(body); err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+	return b.do(ctx, "POST", path, reqBody.Bytes(), out)
+}
+
+// do performs a request against the Beacon API and decodes the response into out
+func (b *HTTPBeaconClient) do(ctx context.Context, method, path string, body []byte, out interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		reqBody = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, b.baseURL+path, reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	conditional := method == http.MethodGet && isConditional(ctx)
+	validated, haveValidated := b.validatedResponse(path)
+	if conditional && haveValidated {
+		if validated.etag != "" {
+			req.Header.Set("If-None-Match", validated.etag)
+		}
+		if validated.lastModified != "" {
+			req.Header.Set("If-Modified-Since", validated.lastModified)
+		}
+	}
+
+	// Respect the provider's and the shared upstream rate limit
+	if err := waitForUpstream(ctx, req.URL.Host, defaultComputeUnits); err != nil {
+		return wrapUpstreamError(ctx, err)
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return wrapUpstreamError(ctx, err)
+	}
+	defer resp.Body.Close()
+
+	// The provider confirmed the earlier response is still current
+	if resp.StatusCode == http.StatusNotModified && conditional && haveValidated {
+		upstreamAccepted(req.URL.Host)
+		if err := json.Unmarshal(validated.body, out); err != nil {
+			return fmt.Errorf("%w: failed to decode response: %v", ErrUpstreamInvalidResponse, err)
+		}
+		return nil
+	}
+
+	// Responses to conditional requests are kept with their validators for the next refresh
+	etag, lastModified := resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+	if resp.StatusCode == http.StatusOK && conditional && (etag != "" || lastModified != "") {
+		upstreamAccepted(req.URL.Host)
+		respBody, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return wrapUpstreamError(ctx, err)
+		}
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return fmt.Errorf("%w: failed to decode response: %v", ErrUpstreamInvalidResponse, err)
+		}
+		b.storeValidatedResponse(path, validatedResponse{etag: etag, lastModified: lastModified, body: respBody})
+		return nil
+	}
+
+	// Large successful responses such as validator sets are decoded while they stream in
+	if resp.StatusCode == http.StatusOK {
+		upstreamAccepted(req.URL.Host)
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			if ctx.Err() != nil {
+				return wrapUpstreamError(ctx, err)
+			}
+			return fmt.Errorf("%w: failed to decode response: %v", ErrUpstreamInvalidResponse, err)
+		}
+		return nil
+	}
+
+	respBody, err := readPooled(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %v", err)
+	}
+	defer putBuffer(respBody)
+
+	// Back off when rate limited, e.g. QuickNode's "request limit reached", the retry waits until the provider
+	// accepts requests again
+	if resp.StatusCode == http.StatusTooManyRequests || bytes.Contains(respBody.Bytes(), []byte("request limit reached")) {
+		upstreamRejected(req.URL.Host, parseRetryAfter(resp.Header))
+		return b.do(ctx, method, path, body, out)
+	}
+	upstreamAccepted(req.URL.Host)
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrSlotNotFound
+	}
+
+	// Beacon nodes answer 503 while they are syncing or their execution client is
+	cause := ErrRPCFailed
+	if resp.StatusCode == http.StatusServiceUnavailable {
+		cause = ErrNotSynced
+	}
+	var apiErr beaconErrorResponse
+	if err := json.Unmarshal(respBody.Bytes(), &apiErr); err == nil && apiErr.Message != "" {
+		return fmt.Errorf("%w: %s (code: %d)", cause, apiErr.Message, apiErr.Code)
+	}
+	return fmt.Errorf("%w: unexpected status %d", cause, resp.StatusCode)
+}
+
+// validatedResponse returns the response kept for conditional requests of a path
+func (b *HTTPBeaconClient) validatedResponse(path string) (validatedResponse, bool) {
+	b.validatorsMu.Lock()
+	defer b.validatorsMu.Unlock()
+	response, ok := b.validators[path]
+	return response, ok
+}
+
+// storeValidatedResponse keeps a response for conditional requests of a path, dropping an arbitrary one once
+// maxValidatedResponses are kept
+func (b *HTTPBeaconClient) storeValidatedResponse(path string, response validatedResponse) {
+	b.validatorsMu.Lock()
+	defer b.validatorsMu.Unlock()
+	if _, ok := b.validators[path]; !ok && len(b.validators) >= maxValidatedResponses {
+		for stale := range b.validators {
+			delete(b.validators, stale)
+			break
+		}
+	}
+	b.validators[path] = response
+}
+
+// beaconFlightTimeout bounds a coalesced Beacon API request, which keeps running when the callers that joined it
+// give up
+const beaconFlightTimeout = 30 * time.Second
+
+// beaconFlight is the outcome of a coalesced Beacon API request
+type beaconFlight struct {
+	value      reflect.Value // Pointer to the decoded response
+	provenance *Provenance
+}
+
+// getBeaconJSON performs a GET request against the configured Beacon API and decodes the response into out
+// Identical concurrent requests of the same priority are coalesced into one upstream call. The call doesn't stop when
+// one of its callers is canceled and its response is decoded once, so callers sharing it must not modify the slices
+// and maps of out
+func (s *EthereumService) getBeaconJSON(ctx context.Context, path string, out interface{}) error {
+	target := reflect.TypeOf(out)
+	if target.Kind() != reflect.Pointer {
+		return fmt.Errorf("decode target of %s must be a pointer, got %s", path, target)
+	}
+
+	// Batch callers would queue interactive callers behind the batch budget, and callers decoding into another type
+	// can't share the value
+	key := fmt.Sprintf("%d %s %s", priorityFrom(ctx), target, path)
+	result := s.inflight.DoChan(key, func() (interface{}, error) {
+		flightCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), beaconFlightTimeout)
+		defer cancel()
+		// The providers that answered are recorded apart from the first caller so each caller can be told
+		flightCtx, provenance := WithProvenance(flightCtx)
+		value := reflect.New(target.Elem())
+		err := s.beaconClient().GetJSON(flightCtx, path, value.Interface())
+		return beaconFlight{value: value, provenance: provenance}, err
+	})
+
+	select {
+	case res := <-result:
+		if res.Err != nil {
+			return s.clientQuirks().classify(res.Err)
+		}
+		flight := res.Val.(beaconFlight)
+		ProvenanceFrom(ctx).recordUpstream(flight.provenance)
+		reflect.ValueOf(out).Elem().Set(flight.value.Elem())
+		return nil
+	case <-ctx.Done():
+		return wrapUpstreamError(ctx, ctx.Err())
+	}
+}
+
+// postBeaconJSON performs a POST request against the configured Beacon API and decodes the response into out
+func (s *EthereumService) postBeaconJSON(ctx context.Context, path string, body interface{}, out interface{}) error {
+	return s.clientQuirks().classify(s.beaconClient().PostJSON(ctx, path, body, out))
+}
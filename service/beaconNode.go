@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync/atomic"
+)
+
+// ConsensusClient is the implementation of a beacon node
+type ConsensusClient string
+
+const (
+	ClientLighthouse ConsensusClient = "lighthouse"
+	ClientPrysm      ConsensusClient = "prysm"
+	ClientTeku       ConsensusClient = "teku"
+	ClientNimbus     ConsensusClient = "nimbus"
+	ClientLodestar   ConsensusClient = "lodestar"
+	ClientUnknown    ConsensusClient = "unknown"
+)
+
+// clientQuirks are the known deviations of a client from the Beacon API that the service works around
+type clientQuirks struct {
+	validatorsByQuery bool     // Validators are requested with the id query parameter of a GET instead of a POST body
+	notFoundMessages  []string // Messages of server errors that mean the requested block or state doesn't exist
+}
+
+// consensusClientQuirks holds the quirks of the clients that need shims, clients following the spec have none
+var consensusClientQuirks = map[ConsensusClient]clientQuirks{
+	// Prysm answers 500 instead of 404 for some unknown blocks and states
+	ClientPrysm: {notFoundMessages: []string{"could not find", "not found"}},
+	// Older Nimbus releases don't accept the POST variant of the validators endpoint
+	ClientNimbus: {validatorsByQuery: true},
+}
+
+// beaconNode is the primary beacon node as reported by /eth/v1/node/version
+type beaconNode struct {
+	version atomic.Pointer[string]
+}
+
+// nodeVersionResponse represents the response from /eth/v1/node/version
+type nodeVersionResponse struct {
+	Data struct {
+		Version string `json:"version"`
+	} `json:"data"`
+}
+
+// DetectConsensusClient identifies the client from the version a beacon node reports, e.g.
+// "Lighthouse/v5.3.0-d6ba8c3/x86_64-linux" or "Prysm/v5.1.0 (linux amd64)"
+func DetectConsensusClient(version string) ConsensusClient {
+	name, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(version)), "/")
+	switch client := ConsensusClient(name); client {
+	case ClientLighthouse, ClientPrysm, ClientTeku, ClientNimbus, ClientLodestar:
+		return client
+	}
+	return ClientUnknown
+}
+
+// BeaconNodeVersion returns the version the beacon node reported when it was last asked, "" if unknown
+func (s *EthereumService) BeaconNodeVersion() string {
+	if version := s.beaconNode.version.Load(); version != nil {
+		return *version
+	}
+	return ""
+}
+
+// ConsensusClient returns the client of the primary beacon node
+func (s *EthereumService) ConsensusClient() ConsensusClient {
+	return DetectConsensusClient(s.BeaconNodeVersion())
+}
+
+// Shims returns the names of the workarounds applied for the client of the beacon node
+func (s *EthereumService) Shims() []string {
+	quirks := s.clientQuirks()
+	shims := []string{}
+	if quirks.validatorsByQuery {
+		shims = append(shims, "validators-by-query")
+	}
+	if len(quirks.notFoundMessages) > 0 {
+		shims = append(shims, "not-found-errors")
+	}
+	return shims
+}
+
+// DetectBeaconNode asks the beacon node for its version again, e.g. after it was upgraded or replaced
+func (s *EthereumService) DetectBeaconNode(ctx context.Context) error {
+	var version json.RawMessage
+	if err := s.getBeaconJSON(ctx, "/eth/v1/node/version", &version); err != nil {
+		return err
+	}
+	s.recordBeaconVersion(version)
+	return nil
+}
+
+// recordBeaconVersion keeps the version of the beacon node from a /eth/v1/node/version response
+func (s *EthereumService) recordBeaconVersion(raw json.RawMessage) {
+	var response nodeVersionResponse
+	if err := json.Unmarshal(raw, &response); err != nil {
+		return
+	}
+	version := response.Data.Version
+	if previous := s.beaconNode.version.Swap(&version); previous == nil || *previous != version {
+		log.Printf("Upstreams: beacon node is %s (%s)", version, DetectConsensusClient(version))
+	}
+}
+
+// clientQuirks returns the quirks of the beacon node's client
+func (s *EthereumService) clientQuirks() clientQuirks {
+	return consensusClientQuirks[s.ConsensusClient()]
+}
+
+// classify maps the errors a client reports for unknown blocks and states to ErrSlotNotFound
+func (q clientQuirks) classify(err error) error {
+	if err == nil || !errors.Is(err, ErrRPCFailed) {
+		return err
+	}
+	message := strings.ToLower(err.Error())
+	for _, notFound := range q.notFoundMessages {
+		if strings.Contains(message, notFound) {
+			return fmt.Errorf("%w: %v", ErrSlotNotFound, err)
+		}
+	}
+	return err
+}
+
+// getValidatorsByID reads the validators with the given indices or public keys from a state, with a POST so long
+// lists fit, or a GET for clients that don't accept the POST
+func (s *EthereumService) getValidatorsByID(ctx context.Context, stateID string, ids []string, out interface{}) error {
+	path := "/eth/v1/beacon/states/" + stateID + "/validators"
+	if s.clientQuirks().validatorsByQuery {
+		return s.getBeaconJSON(ctx, path+"?id="+strings.Join(ids, ","), out)
+	}
+	return s.postBeaconJSON(ctx, path, map[string][]string{"ids": ids}, out)
+}
@@ -0,0 +1,315 @@
+package service
+
+import (
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultIndexWindow is how many slots behind the latest indexed slot the index keeps, the 30 days the longest
+// statistics cover
+const DefaultIndexWindow = 30 * 24 * 60 * 60 / SecondsPerSlot
+
+// maxAttestedValidators bounds the validators whose attestation performance is indexed
+const maxAttestedValidators = 4096
+
+// BlockIndex is an in-memory index of block details and rewards keyed by slot, and of attestation performance keyed
+// by validator and epoch. It only keeps a window of slots behind the latest indexed slot, older entries are pruned
+type BlockIndex struct {
+	mu           sync.RWMutex
+	blocks       map[int64]*BlockDetail
+	rewards      map[int64]*BlockReward
+	attestations map[int64]map[int64]*AttestationPerformance
+	window       int64 // Slots kept behind the latest indexed slot
+	latest       int64 // Highest slot of an indexed block or reward, -1 before the first
+	pruned       int64 // Latest indexed slot when the index was last pruned
+}
+
+// NewBlockIndex creates an empty BlockIndex keeping DefaultIndexWindow slots
+func NewBlockIndex() *BlockIndex {
+	return &BlockIndex{
+		blocks:       make(map[int64]*BlockDetail),
+		rewards:      make(map[int64]*BlockReward),
+		attestations: make(map[int64]map[int64]*AttestationPerformance),
+		window:       DefaultIndexWindow,
+		latest:       -1,
+	}
+}
+
+// SetWindow changes how many slots behind the latest indexed slot the index keeps, pruning older entries
+func (i *BlockIndex) SetWindow(slots int64) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.window = slots
+	i.prune()
+}
+
+// Window returns how many slots behind the latest indexed slot the index keeps
+func (i *BlockIndex) Window() int64 {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.window
+}
+
+// inWindow reports whether a slot is recent enough to be kept, the caller must hold the lock
+func (i *BlockIndex) inWindow(slot int64) bool {
+	return slot > i.latest-i.window
+}
+
+// advance records a newly indexed slot, pruning the entries that fell out of the window once per epoch.
+// The caller must hold the write lock
+func (i *BlockIndex) advance(slot int64) {
+	if slot <= i.latest {
+		return
+	}
+	i.latest = slot
+	if i.latest-i.pruned >= SlotsPerEpoch {
+		i.prune()
+	}
+}
+
+// prune drops the entries of slots and epochs behind the window, the caller must hold the write lock
+func (i *BlockIndex) prune() {
+	i.pruned = i.latest
+	for slot := range i.blocks {
+		if !i.inWindow(slot) {
+			delete(i.blocks, slot)
+		}
+	}
+	for slot := range i.rewards {
+		if !i.inWindow(slot) {
+			delete(i.rewards, slot)
+		}
+	}
+	for validatorIndex, perfs := range i.attestations {
+		for epoch := range perfs {
+			if !i.inWindow((epoch+1)*SlotsPerEpoch - 1) {
+				delete(perfs, epoch)
+			}
+		}
+		if len(perfs) == 0 {
+			delete(i.attestations, validatorIndex)
+		}
+	}
+}
+
+// Put stores or replaces the block detail for its slot, blocks behind the window are ignored
+func (i *BlockIndex) Put(block *BlockDetail) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if !i.inWindow(block.Slot) {
+		return
+	}
+	i.blocks[block.Slot] = block
+	i.advance(block.Slot)
+}
+
+// Get returns the indexed block detail for a slot
+func (i *BlockIndex) Get(slot int64) (*BlockDetail, bool) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	block, ok := i.blocks[slot]
+	return block, ok
+}
+
+// Len returns the number of indexed blocks
+func (i *BlockIndex) Len() int {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return len(i.blocks)
+}
+
+// LatestSlot returns the highest indexed slot, or -1 if the index is empty
+func (i *BlockIndex) LatestSlot() int64 {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	latest := int64(-1)
+	for slot := range i.blocks {
+		if slot > latest {
+			latest = slot
+		}
+	}
+	return latest
+}
+
+// FindExecutionBlock returns the indexed block whose execution payload has the given hash, or the given number when
+// hash is empty
+func (i *BlockIndex) FindExecutionBlock(number int64, hash string) (*BlockDetail, bool) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	for _, block := range i.blocks {
+		if block.ExecutionBlockHash == "" {
+			continue
+		}
+		if (hash != "" && strings.EqualFold(block.ExecutionBlockHash, hash)) || (hash == "" && block.ExecutionBlockNumber == number) {
+			return block, true
+		}
+	}
+	return nil, false
+}
+
+// Range returns the indexed blocks within [from, to] that match the filter, ordered by slot
+func (i *BlockIndex) Range(from, to int64, filter func(*BlockDetail) bool) []*BlockDetail {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	blocks := make([]*BlockDetail, 0)
+	for slot, block := range i.blocks {
+		if slot < from || slot > to {
+			continue
+		}
+		if filter != nil && !filter(block) {
+			continue
+		}
+		blocks = append(blocks, block)
+	}
+
+	sort.Slice(blocks, func(a, b int) bool {
+		return blocks[a].Slot < blocks[b].Slot
+	})
+	return blocks
+}
+
+// SearchGraffiti returns the indexed blocks within [from, to] whose graffiti contains the query (case-insensitive)
+func (i *BlockIndex) SearchGraffiti(query string, from, to int64) []*BlockDetail {
+	query = strings.ToLower(query)
+	return i.Range(from, to, func(block *BlockDetail) bool {
+		return strings.Contains(strings.ToLower(block.Graffiti), query)
+	})
+}
+
+// PutReward stores or replaces the block reward of a slot, rewards behind the window are ignored
+func (i *BlockIndex) PutReward(slot int64, reward *BlockReward) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if !i.inWindow(slot) {
+		return
+	}
+	i.rewards[slot] = reward
+	i.advance(slot)
+}
+
+// GetReward returns the indexed block reward of a slot
+func (i *BlockIndex) GetReward(slot int64) (*BlockReward, bool) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	reward, ok := i.rewards[slot]
+	return reward, ok
+}
+
+// LatestRewardSlot returns the highest slot with an indexed reward, or -1 if there is none
+func (i *BlockIndex) LatestRewardSlot() int64 {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	latest := int64(-1)
+	for slot := range i.rewards {
+		if slot > latest {
+			latest = slot
+		}
+	}
+	return latest
+}
+
+// RewardSlots returns the slots with an indexed reward within [from, to], ordered by slot
+func (i *BlockIndex) RewardSlots(from, to int64) []int64 {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	slots := make([]int64, 0)
+	for slot := range i.rewards {
+		if slot >= from && slot <= to {
+			slots = append(slots, slot)
+		}
+	}
+	sort.Slice(slots, func(a, b int) bool {
+		return slots[a] < slots[b]
+	})
+	return slots
+}
+
+// RewardPercentile returns the percentile (0-100) of a reward among the indexed rewards of the window slots before
+// slot, and false if none of them is indexed
+func (i *BlockIndex) RewardPercentile(slot int64, reward *big.Int, window int) (float64, bool) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	// Count equal rewards as half below so identical values land in the middle
+	compared, below := 0, 0.0
+	for before := slot - int64(window); before < slot; before++ {
+		indexed, ok := i.rewards[before]
+		if !ok || indexed.Reward == nil {
+			continue
+		}
+		compared++
+		switch indexed.Reward.Cmp(reward) {
+		case -1:
+			below++
+		case 0:
+			below += 0.5
+		}
+	}
+	if compared == 0 {
+		return 0, false
+	}
+	return below / float64(compared) * 100, true
+}
+
+// PutAttestation stores or replaces the attestation performance of a validator for its epoch. Epochs behind the
+// window, and further validators once maxAttestedValidators are indexed, are ignored
+func (i *BlockIndex) PutAttestation(validatorIndex int64, perf *AttestationPerformance) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	lastSlot := (perf.Epoch+1)*SlotsPerEpoch - 1
+	if !i.inWindow(lastSlot) {
+		return
+	}
+	if i.attestations[validatorIndex] == nil {
+		if len(i.attestations) >= maxAttestedValidators {
+			return
+		}
+		i.attestations[validatorIndex] = make(map[int64]*AttestationPerformance)
+	}
+	i.attestations[validatorIndex][perf.Epoch] = perf
+	i.advance(lastSlot)
+}
+
+// GetAttestation returns the indexed attestation performance of a validator for an epoch
+func (i *BlockIndex) GetAttestation(validatorIndex, epoch int64) (*AttestationPerformance, bool) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	perf, ok := i.attestations[validatorIndex][epoch]
+	return perf, ok
+}
+
+// AttestedValidators returns the validators with indexed attestation performance, ordered by index
+func (i *BlockIndex) AttestedValidators() []int64 {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	validators := make([]int64, 0, len(i.attestations))
+	for validatorIndex := range i.attestations {
+		validators = append(validators, validatorIndex)
+	}
+	sort.Slice(validators, func(a, b int) bool {
+		return validators[a] < validators[b]
+	})
+	return validators
+}
+
+// Attestations returns the indexed attestation performance of a validator within [fromEpoch, toEpoch], ordered by epoch
+func (i *BlockIndex) Attestations(validatorIndex, fromEpoch, toEpoch int64) []*AttestationPerformance {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	perfs := make([]*AttestationPerformance, 0)
+	for epoch, perf := range i.attestations[validatorIndex] {
+		if epoch >= fromEpoch && epoch <= toEpoch {
+			perfs = append(perfs, perf)
+		}
+	}
+	sort.Slice(perfs, func(a, b int) bool {
+		return perfs[a].Epoch < perfs[b].Epoch
+	})
+	return perfs
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// BlockNumberIndex maps slots to the number of the execution block their beacon block carried and back. Slots
+// without execution payload, missed slots and slots before the merge are not mapped
+type BlockNumberIndex struct {
+	mu      sync.RWMutex
+	numbers map[int64]int64 // Execution block number keyed by slot
+	slots   map[int64]int64 // Slot keyed by execution block number
+	path    string          // File the index is persisted to, "" to keep it in memory only
+	dirty   bool            // Whether mappings were added since the last save
+}
+
+// blockNumberMapping is the persisted form of a mapping
+type blockNumberMapping struct {
+	Slot        int64 `json:"slot"`
+	BlockNumber int64 `json:"block_number"`
+}
+
+// NewBlockNumberIndex creates an empty BlockNumberIndex kept in memory only
+func NewBlockNumberIndex() *BlockNumberIndex {
+	return &BlockNumberIndex{
+		numbers: make(map[int64]int64),
+		slots:   make(map[int64]int64),
+	}
+}
+
+// LoadBlockNumberIndex creates a BlockNumberIndex persisted to a JSON file, starting from the mappings it already
+// contains. A missing file is created by the first Save
+func LoadBlockNumberIndex(path string) (*BlockNumberIndex, error) {
+	index := NewBlockNumberIndex()
+	index.path = path
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return index, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read block number index: %w", err)
+	}
+
+	var mappings []blockNumberMapping
+	if err := json.Unmarshal(data, &mappings); err != nil {
+		return nil, fmt.Errorf("failed to decode block number index: %w", err)
+	}
+	for _, mapping := range mappings {
+		index.numbers[mapping.Slot] = mapping.BlockNumber
+		index.slots[mapping.BlockNumber] = mapping.Slot
+	}
+	return index, nil
+}
+
+// Put maps a slot to the execution block number its beacon block carried
+func (i *BlockNumberIndex) Put(slot, blockNumber int64) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if current, ok := i.numbers[slot]; ok {
+		if current == blockNumber {
+			return
+		}
+		// The slot was reorged onto another block
+		delete(i.slots, current)
+	}
+	i.numbers[slot] = blockNumber
+	i.slots[blockNumber] = slot
+	i.dirty = true
+}
+
+// BlockNumber returns the execution block number of a slot, reporting whether the slot is mapped
+func (i *BlockNumberIndex) BlockNumber(slot int64) (int64, bool) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	number, ok := i.numbers[slot]
+	return number, ok
+}
+
+// Slot returns the slot of an execution block number, reporting whether the block is mapped
+func (i *BlockNumberIndex) Slot(blockNumber int64) (int64, bool) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	slot, ok := i.slots[blockNumber]
+	return slot, ok
+}
+
+// Len returns the number of mapped slots
+func (i *BlockNumberIndex) Len() int {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return len(i.numbers)
+}
+
+// Save writes the mappings to the file of the index when they changed since the last save. The file is replaced
+// atomically so a crash never leaves a truncated index behind
+func (i *BlockNumberIndex) Save() error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.path == "" || !i.dirty {
+		return nil
+	}
+
+	mappings := make([]blockNumberMapping, 0, len(i.numbers))
+	for slot, number := range i.numbers {
+		mappings = append(mappings, blockNumberMapping{Slot: slot, BlockNumber: number})
+	}
+	sort.Slice(mappings, func(a, b int) bool {
+		return mappings[a].Slot < mappings[b].Slot
+	})
+	data, err := json.Marshal(mappings)
+	if err != nil {
+		return fmt.Errorf("failed to encode block number index: %w", err)
+	}
+
+	if err := writeFileAtomic(i.path, data); err != nil {
+		return fmt.Errorf("failed to save block number index: %w", err)
+	}
+
+	i.dirty = false
+	return nil
+}
+
+// writeFileAtomic replaces the file at path with data through a temporary file in the same directory
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// BlockNumbers returns the slot to execution block number mapping
+func (s *EthereumService) BlockNumbers() *BlockNumberIndex {
+	return s.blockNumbers
+}
+
+// SetBlockNumbers replaces the slot to execution block number mapping, e.g. with one loaded from disk
+func (s *EthereumService) SetBlockNumbers(blockNumbers *BlockNumberIndex) {
+	s.blockNumbers = blockNumbers
+}
+
+// BlockNumbersSaveTask returns the task persisting new slot to execution block number mappings on the given schedule
+func (s *EthereumService) BlockNumbersSaveTask(schedule Schedule) Task {
+	return Task{
+		Name:     "block-numbers-save",
+		Schedule: schedule,
+		Run: func(ctx context.Context) error {
+			return s.blockNumbers.Save()
+		},
+	}
+}
+
+// GetExecutionBlockNumber returns the number of the execution block carried by the beacon block of a slot. It is
+// served from the mapping or the block index when possible, and looked up in the beacon block otherwise. Missed slots
+// and slots without execution payload are reported as ErrSlotNotFound
+func (s *EthereumService) GetExecutionBlockNumber(ctx context.Context, slot int64) (int64, error) {
+	if number, ok := s.blockNumbers.BlockNumber(slot); ok {
+		return number, nil
+	}
+
+	block, ok := s.index.Get(slot)
+	if !ok {
+		var err error
+		if block, err = s.getBlockDetail(ctx, slot); err != nil {
+			return 0, err
+		}
+	}
+	if block.ExecutionBlockHash == "" {
+		return 0, fmt.Errorf("%w: slot %d has no execution payload", ErrSlotNotFound, slot)
+	}
+
+	s.blockNumbers.Put(slot, block.ExecutionBlockNumber)
+	return block.ExecutionBlockNumber, nil
+}
+
+// GetBlockNumberSlot returns the slot whose beacon block carried the execution block with the given number
+func (s *EthereumService) GetBlockNumberSlot(ctx context.Context, blockNumber int64) (int64, error) {
+	if slot, ok := s.blockNumbers.Slot(blockNumber); ok {
+		return slot, nil
+	}
+	block, err := s.ResolveExecutionBlock(ctx, fmt.Sprint(blockNumber))
+	if err != nil {
+		return 0, err
+	}
+	return block.Slot, nil
+}
@@ -0,0 +1,349 @@
+package service
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"math/bits"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/bellatrix"
+	"github.com/attestantio/go-eth2-client/spec/capella"
+	"github.com/ethereum/go-ethereum/core/types"
+	"golang.org/x/sync/errgroup"
+)
+
+// SyncCommitteeSize is the number of validators in a sync committee
+const SyncCommitteeSize = 512
+
+// BlockDetail represents a cleaned-up view of a beacon block
+type BlockDetail struct {
+	Slot                 int64
+	ProposerIndex        int64
+	ProposerPubkey       string
+	Graffiti             string
+	BlockRoot            string
+	ParentRoot           string
+	StateRoot            string
+	ExecutionBlockNumber int64
+	ExecutionBlockHash   string
+	FeeRecipient         string
+	GasUsed              int64
+	GasLimit             int64
+	BaseFee              *big.Int // Base fee per gas of the execution payload in Wei, nil before Bellatrix
+	Timestamp            int64    // Unix time of the execution payload, 0 before Bellatrix
+	TxCount              int
+	TransactionTypes     TransactionTypes
+	SyncParticipation    int          // Number of sync committee members that signed
+	SyncCommitteeBits    string       // Hex encoded bitvector of the sync committee members that signed
+	Withdrawals          []Withdrawal // Withdrawals of the execution payload, none before Capella
+}
+
+// Withdrawal is a transfer of a validator's balance above its effective balance, or of its whole balance once it
+// exited, to its withdrawal address
+type Withdrawal struct {
+	ValidatorIndex int64
+	Amount         int64 // in GWEI
+}
+
+// TransactionTypes counts the transactions of a block by EIP-2718 type. Contract creations are counted in addition to
+// their type
+type TransactionTypes struct {
+	Legacy           int
+	AccessList       int // EIP-2930
+	DynamicFee       int // EIP-1559
+	Blob             int // EIP-4844
+	SetCode          int // EIP-7702
+	ContractCreation int
+	Unknown          int // Transactions that could not be decoded
+}
+
+// blockRootResponse represents the response from /eth/v1/beacon/blocks/{block_id}/root
+type blockRootResponse struct {
+	Data struct {
+		Root string `json:"root"`
+	} `json:"data"`
+}
+
+// GetBlockDetailBySlot retrieves the beacon block for a slot along with its root and proposer pubkey
+func (s *EthereumService) GetBlockDetailBySlot(ctx context.Context, slot int64) (*BlockDetail, error) {
+	// Validate slot is not in the future
+	currentSlot := s.wallClockSlot()
+	if slot > currentSlot {
+		return nil, fmt.Errorf("%w (current slot: %d)", ErrFutureSlot, currentSlot)
+	}
+
+	detail, err := s.getBlockDetail(ctx, slot)
+	if err != nil {
+		return nil, err
+	}
+
+	var proposer validatorResponse
+	if err := s.getBeaconJSON(ctx, fmt.Sprintf("/eth/v1/beacon/states/%d/validators/%d", slot, detail.ProposerIndex), &proposer); err != nil {
+		// The pubkey is a convenience field, so don't fail the whole request over it
+		sampledLogf("proposer pubkey", "Warning: failed to get proposer pubkey: %v", err)
+	} else {
+		detail.ProposerPubkey = proposer.Data.Validator.Pubkey
+	}
+
+	if detail.ExecutionBlockHash != "" {
+		s.blockNumbers.Put(slot, detail.ExecutionBlockNumber)
+	}
+
+	return detail, nil
+}
+
+// getBlockDetail decodes the block of a slot, preferring the fork-aware types of the beacon client when available
+func (s *EthereumService) getBlockDetail(ctx context.Context, slot int64) (*BlockDetail, error) {
+	if decoder, ok := s.beaconClient().(BlockDecoder); ok {
+		var block *spec.VersionedSignedBeaconBlock
+		err := trackUpstream(ctx, func(ctx context.Context) error {
+			var err error
+			block, err = decoder.SignedBeaconBlock(ctx, strconv.FormatInt(slot, 10))
+			return err
+		})
+		if err == nil {
+			reported, err := block.Slot()
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode block: %w", err)
+			}
+			if err := s.verifyBeaconBlockSlot(slot, int64(reported)); err != nil {
+				return nil, err
+			}
+			return blockDetailFromVersioned(slot, block)
+		}
+		if !errors.Is(err, errTypedClientUnavailable) {
+			return nil, err
+		}
+	}
+
+	// The block and its root are independent lookups
+	var block BeaconBlockResponse
+	var root blockRootResponse
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		return s.getBeaconJSON(gctx, fmt.Sprintf("/eth/v2/beacon/blocks/%d", slot), &block)
+	})
+	g.Go(func() error {
+		return s.getBeaconJSON(gctx, fmt.Sprintf("/eth/v1/beacon/blocks/%d/root", slot), &root)
+	})
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	message := block.Data.Message
+	if err := s.verifyBeaconBlockSlot(slot, parseDecimal(message.Slot)); err != nil {
+		return nil, err
+	}
+	payload := message.Body.ExecutionPayload
+
+	transactions := make([][]byte, 0, len(payload.Transactions))
+	for _, transaction := range payload.Transactions {
+		raw, err := hex.DecodeString(strings.TrimPrefix(transaction, "0x"))
+		if err != nil {
+			raw = nil
+		}
+		transactions = append(transactions, raw)
+	}
+
+	var withdrawals []Withdrawal
+	for _, withdrawal := range payload.Withdrawals {
+		withdrawals = append(withdrawals, Withdrawal{ValidatorIndex: parseDecimal(withdrawal.ValidatorIndex), Amount: parseDecimal(withdrawal.Amount)})
+	}
+
+	return &BlockDetail{
+		Slot:                 slot,
+		ProposerIndex:        parseDecimal(message.ProposerIndex),
+		Graffiti:             DecodeGraffiti(message.Body.Graffiti),
+		BlockRoot:            root.Data.Root,
+		ParentRoot:           message.ParentRoot,
+		StateRoot:            message.StateRoot,
+		ExecutionBlockNumber: parseDecimal(payload.BlockNumber),
+		ExecutionBlockHash:   payload.BlockHash,
+		FeeRecipient:         payload.FeeRecipient,
+		GasUsed:              parseDecimal(payload.GasUsed),
+		GasLimit:             parseDecimal(payload.GasLimit),
+		BaseFee:              parseBigDecimal(payload.BaseFeePerGas),
+		Timestamp:            parseDecimal(payload.Timestamp),
+		TxCount:              len(payload.Transactions),
+		TransactionTypes:     countTransactionTypes(transactions),
+		SyncParticipation:    countSetBits(message.Body.SyncAggregate.SyncCommitteeBits),
+		SyncCommitteeBits:    message.Body.SyncAggregate.SyncCommitteeBits,
+		Withdrawals:          withdrawals,
+	}, nil
+}
+
+// blockDetailFromVersioned maps a fork-aware block onto a BlockDetail, the block root is computed locally
+func blockDetailFromVersioned(slot int64, block *spec.VersionedSignedBeaconBlock) (*BlockDetail, error) {
+	proposerIndex, err := block.ProposerIndex()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode block: %w", err)
+	}
+	root, err := block.Root()
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute block root: %w", err)
+	}
+	parentRoot, _ := block.ParentRoot()
+	stateRoot, _ := block.StateRoot()
+	graffiti, _ := block.Graffiti()
+
+	detail := &BlockDetail{
+		Slot:          slot,
+		ProposerIndex: int64(proposerIndex),
+		Graffiti:      DecodeGraffiti(hex.EncodeToString(graffiti[:])),
+		BlockRoot:     root.String(),
+		ParentRoot:    parentRoot.String(),
+		StateRoot:     stateRoot.String(),
+	}
+
+	// Blocks before Altair have no sync aggregate
+	if syncAggregate, err := block.SyncAggregate(); err == nil {
+		detail.SyncCommitteeBits = "0x" + hex.EncodeToString(syncAggregate.SyncCommitteeBits)
+		detail.SyncParticipation = countSetBits(detail.SyncCommitteeBits)
+	}
+
+	// Blocks before Bellatrix have no execution payload. The base fee is little-endian until Capella and a uint256 after
+	var payload *bellatrix.ExecutionPayload
+	var baseFee *big.Int
+	var withdrawals []*capella.Withdrawal
+	switch block.Version {
+	case spec.DataVersionBellatrix:
+		payload = block.Bellatrix.Message.Body.ExecutionPayload
+		baseFee = littleEndianToBig(payload.BaseFeePerGas)
+	case spec.DataVersionCapella:
+		p := block.Capella.Message.Body.ExecutionPayload
+		withdrawals = p.Withdrawals
+		payload = &bellatrix.ExecutionPayload{FeeRecipient: p.FeeRecipient, GasLimit: p.GasLimit, GasUsed: p.GasUsed, Timestamp: p.Timestamp, BlockHash: p.BlockHash, BlockNumber: p.BlockNumber, Transactions: p.Transactions}
+		baseFee = littleEndianToBig(p.BaseFeePerGas)
+	case spec.DataVersionDeneb:
+		p := block.Deneb.Message.Body.ExecutionPayload
+		withdrawals = p.Withdrawals
+		payload = &bellatrix.ExecutionPayload{FeeRecipient: p.FeeRecipient, GasLimit: p.GasLimit, GasUsed: p.GasUsed, Timestamp: p.Timestamp, BlockHash: p.BlockHash, BlockNumber: p.BlockNumber, Transactions: p.Transactions}
+		baseFee = p.BaseFeePerGas.ToBig()
+	case spec.DataVersionElectra:
+		p := block.Electra.Message.Body.ExecutionPayload
+		withdrawals = p.Withdrawals
+		payload = &bellatrix.ExecutionPayload{FeeRecipient: p.FeeRecipient, GasLimit: p.GasLimit, GasUsed: p.GasUsed, Timestamp: p.Timestamp, BlockHash: p.BlockHash, BlockNumber: p.BlockNumber, Transactions: p.Transactions}
+		baseFee = p.BaseFeePerGas.ToBig()
+	}
+	if payload != nil {
+		detail.ExecutionBlockNumber = int64(payload.BlockNumber)
+		detail.ExecutionBlockHash = "0x" + hex.EncodeToString(payload.BlockHash[:])
+		detail.FeeRecipient = "0x" + hex.EncodeToString(payload.FeeRecipient[:])
+		detail.GasUsed = int64(payload.GasUsed)
+		detail.GasLimit = int64(payload.GasLimit)
+		detail.BaseFee = baseFee
+		detail.Timestamp = int64(payload.Timestamp)
+		detail.TxCount = len(payload.Transactions)
+
+		transactions := make([][]byte, 0, len(payload.Transactions))
+		for _, transaction := range payload.Transactions {
+			transactions = append(transactions, transaction)
+		}
+		detail.TransactionTypes = countTransactionTypes(transactions)
+	}
+	for _, withdrawal := range withdrawals {
+		detail.Withdrawals = append(detail.Withdrawals, Withdrawal{ValidatorIndex: int64(withdrawal.ValidatorIndex), Amount: int64(withdrawal.Amount)})
+	}
+
+	return detail, nil
+}
+
+// countTransactionTypes decodes the raw transactions of an execution payload and counts them by type
+func countTransactionTypes(transactions [][]byte) TransactionTypes {
+	var counts TransactionTypes
+	for _, raw := range transactions {
+		var transaction types.Transaction
+		if err := transaction.UnmarshalBinary(raw); err != nil {
+			counts.Unknown++
+			continue
+		}
+
+		switch transaction.Type() {
+		case types.LegacyTxType:
+			counts.Legacy++
+		case types.AccessListTxType:
+			counts.AccessList++
+		case types.DynamicFeeTxType:
+			counts.DynamicFee++
+		case types.BlobTxType:
+			counts.Blob++
+		case types.SetCodeTxType:
+			counts.SetCode++
+		}
+		if transaction.To() == nil {
+			counts.ContractCreation++
+		}
+	}
+	return counts
+}
+
+// DecodeGraffiti converts the hex encoded graffiti of a beacon block to a UTF-8 string
+func DecodeGraffiti(graffiti string) string {
+	raw, err := hex.DecodeString(strings.TrimPrefix(graffiti, "0x"))
+	if err != nil {
+		return ""
+	}
+
+	// Graffiti is a fixed 32 byte field padded with zeros
+	decoded := strings.TrimRight(string(raw), "\x00")
+	if !utf8.ValidString(decoded) {
+		return strings.ToValidUTF8(decoded, "")
+	}
+	return decoded
+}
+
+// parseDecimal parses a decimal string returned by the Beacon API, defaulting to zero
+func parseDecimal(value string) int64 {
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return parsed
+}
+
+// parseBigDecimal parses a decimal amount returned by the Beacon API, nil when it is missing or malformed
+func parseBigDecimal(value string) *big.Int {
+	parsed, ok := new(big.Int).SetString(value, 10)
+	if !ok {
+		return nil
+	}
+	return parsed
+}
+
+// littleEndianToBig converts a little-endian SSZ uint256 to a big.Int
+func littleEndianToBig(value [32]byte) *big.Int {
+	bigEndian := make([]byte, len(value))
+	for i, b := range value {
+		bigEndian[len(value)-1-i] = b
+	}
+	return new(big.Int).SetBytes(bigEndian)
+}
+
+// countSetBits counts the set bits in a hex encoded bitvector
+func countSetBits(bitvector string) int {
+	raw, err := hex.DecodeString(strings.TrimPrefix(bitvector, "0x"))
+	if err != nil {
+		return 0
+	}
+
+	count := 0
+	for _, b := range raw {
+		count += bits.OnesCount8(b)
+	}
+	return count
+}
+
+// isBitSet reports whether the bit at position is set in a hex encoded SSZ bitvector
+func isBitSet(bitvector string, position int) bool {
+	raw, err := hex.DecodeString(strings.TrimPrefix(bitvector, "0x"))
+	if err != nil || position < 0 || position/8 >= len(raw) {
+		return false
+	}
+	return raw[position/8]&(1<<(position%8)) != 0
+}
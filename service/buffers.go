@@ -0,0 +1,41 @@
+package service
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// maxPooledBufferSize keeps buffers grown by unusually large responses out of the pool
+const maxPooledBufferSize = 4 << 20 // 4 MiB
+
+// bufferPool reuses request and response buffers across upstream calls to reduce GC pressure
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+// getBuffer returns an empty buffer from the pool
+func getBuffer() *bytes.Buffer {
+	return bufferPool.Get().(*bytes.Buffer)
+}
+
+// putBuffer returns a buffer to the pool, its contents must no longer be referenced
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBufferSize {
+		return
+	}
+	buf.Reset()
+	bufferPool.Put(buf)
+}
+
+// readPooled reads r into a pooled buffer, the caller must release it with putBuffer
+func readPooled(r io.Reader) (*bytes.Buffer, error) {
+	buf := getBuffer()
+	if _, err := buf.ReadFrom(r); err != nil {
+		putBuffer(buf)
+		return nil, err
+	}
+	return buf, nil
+}
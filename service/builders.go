@@ -0,0 +1,311 @@
+package service
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxLearnedBuilderKeys bounds the builder pubkeys learned from delivered payloads
+const maxLearnedBuilderKeys = 1024
+
+// BuilderSignature maps extraData tokens and builder pubkeys to a normalized builder name
+type BuilderSignature struct {
+	Name       string   `json:"name"`                  // Normalized builder name returned to clients
+	ExtraData  []string `json:"extra_data"`            // Case-insensitive words or phrases of the decoded extraData
+	PublicKeys []string `json:"public_keys,omitempty"` // Builder BLS pubkeys as reported by relays
+}
+
+// DefaultBuilderSignatures returns the built-in list of known MEV-Boost builders. Their pubkeys rotate, so they
+// are learned from the payloads relays delivered instead of being listed
+func DefaultBuilderSignatures() []BuilderSignature {
+	return []BuilderSignature{
+		{Name: "Flashbots", ExtraData: []string{"flashbots", "illuminate dmocratize dstribute"}},
+		{Name: "builder0x69", ExtraData: []string{"builder0x69"}},
+		{Name: "rsync-builder", ExtraData: []string{"rsync-builder", "rsyncbuilder"}},
+		{Name: "Titan", ExtraData: []string{"titanbuilder", "titan builder"}},
+		{Name: "beaverbuild", ExtraData: []string{"beaverbuild"}},
+		{Name: "bloXroute", ExtraData: []string{"bloxroute"}},
+		{Name: "Manifold", ExtraData: []string{"manifold"}},
+		{Name: "eth-builder", ExtraData: []string{"eth-builder"}},
+		{Name: "BuilderNet", ExtraData: []string{"buildernet"}},
+		{Name: "Eden", ExtraData: []string{"eden network", "edennetwork"}},
+	}
+}
+
+// BuilderRegistry holds the builder signatures used to identify MEV blocks and can be updated at runtime
+type BuilderRegistry struct {
+	mu         sync.RWMutex
+	signatures map[string]BuilderSignature // keyed by lower-cased builder name
+	learned    map[string]string           // Builder names of pubkeys learned from delivered payloads, keyed by lower-cased pubkey
+}
+
+// NewBuilderRegistry creates a BuilderRegistry with the given signatures
+func NewBuilderRegistry(signatures []BuilderSignature) *BuilderRegistry {
+	r := &BuilderRegistry{
+		signatures: make(map[string]BuilderSignature),
+		learned:    make(map[string]string),
+	}
+	for _, signature := range signatures {
+		r.Put(signature)
+	}
+	return r
+}
+
+// LoadBuilderRegistry creates a BuilderRegistry from a JSON file containing a list of signatures
+func LoadBuilderRegistry(path string) (*BuilderRegistry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open builder signatures file: %w", err)
+	}
+	defer file.Close()
+
+	signatures, err := decodeBuilderSignatures(file)
+	if err != nil {
+		return nil, err
+	}
+	return NewBuilderRegistry(signatures), nil
+}
+
+// Put adds a builder signature, replacing any existing signature with the same name
+func (r *BuilderRegistry) Put(signature BuilderSignature) {
+	for i, extraData := range signature.ExtraData {
+		signature.ExtraData[i] = strings.ToLower(extraData)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.signatures[strings.ToLower(signature.Name)] = signature
+}
+
+// Get returns the signature of a builder, reporting whether it exists
+func (r *BuilderRegistry) Get(name string) (BuilderSignature, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	signature, ok := r.signatures[strings.ToLower(name)]
+	return signature, ok
+}
+
+// Remove deletes the signature of a builder, reporting whether it existed
+func (r *BuilderRegistry) Remove(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := strings.ToLower(name)
+	signature, ok := r.signatures[key]
+	if !ok {
+		return false
+	}
+	delete(r.signatures, key)
+	for pubkey, learned := range r.learned {
+		if learned == signature.Name {
+			delete(r.learned, pubkey)
+		}
+	}
+	return true
+}
+
+// List returns all builder signatures ordered by name
+func (r *BuilderRegistry) List() []BuilderSignature {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	signatures := make([]BuilderSignature, 0, len(r.signatures))
+	for _, signature := range r.signatures {
+		signatures = append(signatures, signature)
+	}
+	sort.Slice(signatures, func(a, b int) bool {
+		return strings.ToLower(signatures[a].Name) < strings.ToLower(signatures[b].Name)
+	})
+	return signatures
+}
+
+// Resolve returns the normalized builder name for a block's extraData or builder pubkey, or "" if unknown. A pubkey
+// listed in a signature or learned from a delivered payload takes precedence over the extraData, which any builder
+// or proposer can set
+func (r *BuilderRegistry) Resolve(extraData, builderPubkey string) string {
+	signatures := r.List()
+
+	if builderPubkey != "" {
+		for _, builder := range signatures {
+			for _, pubkey := range builder.PublicKeys {
+				if strings.EqualFold(pubkey, builderPubkey) {
+					return builder.Name
+				}
+			}
+		}
+
+		r.mu.RLock()
+		name, ok := r.learned[strings.ToLower(builderPubkey)]
+		r.mu.RUnlock()
+		if ok {
+			return name
+		}
+	}
+
+	return resolveExtraData(signatures, extraData)
+}
+
+// Learn records the builder pubkey of a payload a relay delivered, named after the builder its extraData resolves to.
+// Relay bids carry no extraData, so this lets the bids of known builders be named by their pubkey
+func (r *BuilderRegistry) Learn(extraData, builderPubkey string) {
+	if builderPubkey == "" {
+		return
+	}
+	name := resolveExtraData(r.List(), extraData)
+	if name == "" {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := strings.ToLower(builderPubkey)
+	if _, ok := r.learned[key]; !ok && len(r.learned) >= maxLearnedBuilderKeys {
+		return
+	}
+	r.learned[key] = name
+}
+
+// resolveExtraData returns the name of the first builder with a signature matching the decoded extraData, or ""
+func resolveExtraData(signatures []BuilderSignature, extraData string) string {
+	text := strings.ToLower(DecodeExtraData(extraData))
+	if text == "" {
+		return ""
+	}
+
+	for _, builder := range signatures {
+		for _, signature := range builder.ExtraData {
+			if containsToken(text, signature) {
+				return builder.Name
+			}
+		}
+	}
+	return ""
+}
+
+// containsToken reports whether token occurs in text as whole words, not preceded or followed by a letter or digit.
+// Short signatures like "eden" would otherwise match any extraData that merely contains them, such as "golden"
+func containsToken(text, token string) bool {
+	if token == "" {
+		return false
+	}
+	for offset := 0; offset+len(token) <= len(text); {
+		index := strings.Index(text[offset:], token)
+		if index < 0 {
+			return false
+		}
+		start, end := offset+index, offset+index+len(token)
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if !isWordRune(before) && !isWordRune(after) {
+			return true
+		}
+		offset = start + 1
+	}
+	return false
+}
+
+// isWordRune reports whether a character is part of a word
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// Refresh fetches a JSON list of signatures from a URL and merges it into the registry
+func (r *BuilderRegistry) Refresh(ctx context.Context, client *http.Client, sourceURL string) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", sourceURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch builder signatures: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("builder signatures URL responded with status %d", resp.StatusCode)
+	}
+
+	signatures, err := decodeBuilderSignatures(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	// Merge rather than replace so signatures added through the admin API survive a refresh
+	for _, signature := range signatures {
+		r.Put(signature)
+	}
+	return nil
+}
+
+// RunRefresher refreshes the registry from a URL every interval until the context is cancelled
+func (r *BuilderRegistry) RunRefresher(ctx context.Context, sourceURL string, interval time.Duration) {
+	client := &http.Client{
+		Timeout: time.Second * 10,
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.Refresh(ctx, client, sourceURL); err != nil {
+			log.Printf("Builder registry: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// decodeBuilderSignatures decodes and validates a JSON list of builder signatures
+func decodeBuilderSignatures(reader io.Reader) ([]BuilderSignature, error) {
+	var signatures []BuilderSignature
+	if err := json.NewDecoder(reader).Decode(&signatures); err != nil {
+		return nil, fmt.Errorf("failed to decode builder signatures: %w", err)
+	}
+
+	for _, signature := range signatures {
+		if err := signature.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return signatures, nil
+}
+
+// Validate checks that a signature has a name and at least one way to match it
+func (b BuilderSignature) Validate() error {
+	if strings.TrimSpace(b.Name) == "" {
+		return fmt.Errorf("builder signature name cannot be empty")
+	}
+	if len(b.ExtraData) == 0 && len(b.PublicKeys) == 0 {
+		return fmt.Errorf("builder signature %q needs at least one extra_data or public_keys entry", b.Name)
+	}
+	return nil
+}
+
+// DecodeExtraData converts hex encoded extraData to text, returning the input unchanged if it is not hex
+func DecodeExtraData(extraData string) string {
+	if !strings.HasPrefix(extraData, "0x") {
+		return extraData
+	}
+
+	raw, err := hex.DecodeString(strings.TrimPrefix(extraData, "0x"))
+	if err != nil {
+		return extraData
+	}
+	return strings.ToValidUTF8(string(raw), "")
+}
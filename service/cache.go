@@ -0,0 +1,372 @@
+package service
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultCacheMaxEntries is how many entries the response cache holds before the least recently used are evicted
+	DefaultCacheMaxEntries = 16384
+
+	// DefaultCacheMaxBytes is the approximate memory the cached values may hold before the least recently used are evicted
+	DefaultCacheMaxBytes = 256 << 20
+
+	// staleCacheRetention is how long expired entries are kept to be served stale before a sweep removes them
+	staleCacheRetention = time.Hour
+
+	// cacheSweepInterval is how often expired entries are swept from the response cache
+	cacheSweepInterval = time.Minute
+)
+
+// cacheEntry represents a cached value and its expiry
+type cacheEntry struct {
+	key     string
+	value   interface{}
+	stored  time.Time
+	expires time.Time
+	size    int64 // Approximate memory held by the key and value
+}
+
+// responseCache is a concurrency-safe in-memory cache with per-entry TTLs. It is bounded by the number of entries and
+// their approximate memory, evicting the least recently used entries beyond either
+type responseCache struct {
+	mu         sync.Mutex
+	entries    map[string]*list.Element // Elements of lru holding a *cacheEntry
+	lru        *list.List               // Entries ordered from the most to the least recently used
+	bytes      int64                    // Approximate memory held by all entries
+	maxEntries int
+	maxBytes   int64
+	refreshing map[string]bool // Keys with a background refresh in flight
+	hits       atomic.Int64
+	misses     atomic.Int64
+	evictions  atomic.Int64
+	clock      Clock // Source of the current time entries expire by
+}
+
+// newResponseCache creates an empty responseCache whose entries expire by the given clock
+func newResponseCache(clock Clock) *responseCache {
+	return &responseCache{
+		clock:      clock,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+		maxEntries: DefaultCacheMaxEntries,
+		maxBytes:   DefaultCacheMaxBytes,
+		refreshing: make(map[string]bool),
+	}
+}
+
+// Get returns the cached value for key if it exists and has not expired
+func (c *responseCache) Get(key string) (interface{}, bool) {
+	entry, ok := c.getEntry(key)
+	return entry.value, ok
+}
+
+// getEntry returns the entry of key if it exists and has not expired
+func (c *responseCache) getEntry(key string) (cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok || c.clock.Now().After(element.Value.(*cacheEntry).expires) {
+		c.misses.Add(1)
+		return cacheEntry{}, false
+	}
+	c.hits.Add(1)
+	c.lru.MoveToFront(element)
+	return *element.Value.(*cacheEntry), true
+}
+
+// Set caches value under key for ttl, evicting the least recently used entries while the cache is over its limits
+func (c *responseCache) Set(key string, value interface{}, ttl time.Duration) {
+	// Sizing walks the value, so it's done before taking the lock
+	size := int64(len(key)) + valueSize(value)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.clock.Now()
+	entry := &cacheEntry{
+		key:     key,
+		value:   value,
+		stored:  now,
+		expires: now.Add(ttl),
+		size:    size,
+	}
+	if element, ok := c.entries[key]; ok {
+		c.bytes -= element.Value.(*cacheEntry).size
+		element.Value = entry
+		c.lru.MoveToFront(element)
+	} else {
+		c.entries[key] = c.lru.PushFront(entry)
+	}
+	c.bytes += size
+
+	for c.lru.Len() > 1 && (c.lru.Len() > c.maxEntries || c.bytes > c.maxBytes) {
+		c.remove(c.lru.Back())
+		c.evictions.Add(1)
+	}
+}
+
+// SetLimits changes the number of entries and the approximate memory the cache may hold, evicting the least
+// recently used entries beyond the new limits
+func (c *responseCache) SetLimits(maxEntries int, maxBytes int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.maxEntries = maxEntries
+	c.maxBytes = maxBytes
+	for c.lru.Len() > 0 && (c.lru.Len() > c.maxEntries || c.bytes > c.maxBytes) {
+		c.remove(c.lru.Back())
+		c.evictions.Add(1)
+	}
+}
+
+// remove drops an element of lru, the caller must hold the lock
+func (c *responseCache) remove(element *list.Element) {
+	entry := c.lru.Remove(element).(*cacheEntry)
+	delete(c.entries, entry.key)
+	c.bytes -= entry.size
+}
+
+// getStaleEntry returns the entry of key even if it has expired
+func (c *responseCache) getStaleEntry(key string) (cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		c.misses.Add(1)
+		return cacheEntry{}, false
+	}
+	c.hits.Add(1)
+	c.lru.MoveToFront(element)
+	return *element.Value.(*cacheEntry), true
+}
+
+// Sweep removes the entries that expired more than staleCacheRetention ago and returns the number of entries removed
+func (c *responseCache) Sweep() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cutoff := c.clock.Now().Add(-staleCacheRetention)
+	removed := 0
+	for element := c.lru.Back(); element != nil; {
+		previous := element.Prev()
+		if element.Value.(*cacheEntry).expires.Before(cutoff) {
+			c.remove(element)
+			removed++
+		}
+		element = previous
+	}
+	return removed
+}
+
+// Revalidate runs refresh in the background unless a refresh of key is already running
+func (c *responseCache) Revalidate(key string, refresh func()) {
+	c.mu.Lock()
+	if c.refreshing[key] {
+		c.mu.Unlock()
+		return
+	}
+	c.refreshing[key] = true
+	c.mu.Unlock()
+
+	go func() {
+		defer func() {
+			c.mu.Lock()
+			delete(c.refreshing, key)
+			c.mu.Unlock()
+		}()
+		refresh()
+	}()
+}
+
+// Delete removes the given keys and returns the number of entries removed
+func (c *responseCache) Delete(keys ...string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := 0
+	for _, key := range keys {
+		if element, ok := c.entries[key]; ok {
+			c.remove(element)
+			removed++
+		}
+	}
+	return removed
+}
+
+// Clear removes all entries and returns the number of entries removed
+func (c *responseCache) Clear() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := len(c.entries)
+	c.entries = make(map[string]*list.Element)
+	c.lru.Init()
+	c.bytes = 0
+	return removed
+}
+
+// CacheStats summarizes the contents and effectiveness of the response cache
+type CacheStats struct {
+	Entries     int
+	Expired     int   // Entries past their TTL that are kept to be served stale until they are swept
+	Evictions   int64 // Entries evicted to keep the cache within its limits
+	Hits        int64
+	Misses      int64
+	HitRatio    float64 // Share of lookups served from the cache, 0 before the first lookup
+	MemoryBytes int64   // Approximate memory held by the cached values
+}
+
+// Stats returns the current cache statistics
+func (c *responseCache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := CacheStats{
+		Entries:     len(c.entries),
+		Evictions:   c.evictions.Load(),
+		Hits:        c.hits.Load(),
+		Misses:      c.misses.Load(),
+		MemoryBytes: c.bytes,
+	}
+	if lookups := stats.Hits + stats.Misses; lookups > 0 {
+		stats.HitRatio = float64(stats.Hits) / float64(lookups)
+	}
+
+	now := c.clock.Now()
+	for _, element := range c.entries {
+		if now.After(element.Value.(*cacheEntry).expires) {
+			stats.Expired++
+		}
+	}
+	return stats
+}
+
+// valueSize estimates the memory held by a cached value
+func valueSize(value interface{}) int64 {
+	v := reflect.ValueOf(value)
+	if !v.IsValid() {
+		return 0
+	}
+	return int64(v.Type().Size()) + referencedSize(v, make(map[uintptr]bool))
+}
+
+// referencedSize estimates the memory v references beyond its own inline size, memory shared through pointers is counted once
+func referencedSize(v reflect.Value, seen map[uintptr]bool) int64 {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() || seen[v.Pointer()] {
+			return 0
+		}
+		seen[v.Pointer()] = true
+		return int64(v.Type().Elem().Size()) + referencedSize(v.Elem(), seen)
+	case reflect.Interface:
+		if v.IsNil() {
+			return 0
+		}
+		return int64(v.Elem().Type().Size()) + referencedSize(v.Elem(), seen)
+	case reflect.String:
+		return int64(v.Len())
+	case reflect.Slice:
+		size := int64(v.Cap()) * int64(v.Type().Elem().Size())
+		if !holdsReferences(v.Type().Elem()) {
+			return size
+		}
+		for i := 0; i < v.Len(); i++ {
+			size += referencedSize(v.Index(i), seen)
+		}
+		return size
+	case reflect.Array:
+		var size int64
+		if !holdsReferences(v.Type().Elem()) {
+			return 0
+		}
+		for i := 0; i < v.Len(); i++ {
+			size += referencedSize(v.Index(i), seen)
+		}
+		return size
+	case reflect.Struct:
+		var size int64
+		for i := 0; i < v.NumField(); i++ {
+			size += referencedSize(v.Field(i), seen)
+		}
+		return size
+	case reflect.Map:
+		entrySize := int64(v.Type().Key().Size() + v.Type().Elem().Size())
+		size := int64(v.Len()) * entrySize
+		iter := v.MapRange()
+		for iter.Next() {
+			size += referencedSize(iter.Key(), seen) + referencedSize(iter.Value(), seen)
+		}
+		return size
+	default:
+		return 0
+	}
+}
+
+// holdsReferences reports whether values of type t may reference memory beyond their inline size
+func holdsReferences(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return false
+	case reflect.Array:
+		return holdsReferences(t.Elem())
+	default:
+		return true
+	}
+}
+
+// CacheStats returns statistics of the response cache
+func (s *EthereumService) CacheStats() CacheStats {
+	return s.cache.Stats()
+}
+
+// SetCacheLimits bounds the response cache to maxEntries entries holding about maxBytes of memory, the least recently
+// used entries are evicted beyond either
+func (s *EthereumService) SetCacheLimits(maxEntries int, maxBytes int64) error {
+	if maxEntries <= 0 || maxBytes <= 0 {
+		return fmt.Errorf("cache limits must be positive, got %d entries and %d bytes", maxEntries, maxBytes)
+	}
+	s.cache.SetLimits(maxEntries, maxBytes)
+	return nil
+}
+
+// CacheSweepTask returns the task removing cached responses that expired longer ago than they may be served stale
+func (s *EthereumService) CacheSweepTask() Task {
+	return Task{
+		Name:     "cache-sweep",
+		Schedule: Every(cacheSweepInterval),
+		Run: func(ctx context.Context) error {
+			if removed := s.cache.Sweep(); removed > 0 {
+				slog.Debug("Swept expired cache entries", "removed", removed)
+			}
+			return nil
+		},
+	}
+}
+
+// InvalidateSlot removes the cached responses covering a slot, including the sync committee of its period,
+// and returns the number of entries removed
+func (s *EthereumService) InvalidateSlot(slot int64) int {
+	return s.cache.Delete(
+		fmt.Sprintf("reward:%d", slot),
+		fmt.Sprintf("committees:%d", slot),
+		syncCommitteeKey(slot/SlotsPerSyncPeriod),
+	)
+}
+
+// ClearCache removes all cached responses and returns the number of entries removed
+func (s *EthereumService) ClearCache() int {
+	return s.cache.Clear()
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"time"
+)
+
+// chainHeadTTL is how long the chain head is cached, well below the slot duration
+const chainHeadTTL = 4 * time.Second
+
+// SlotsPerSyncPeriod is the number of slots a sync committee serves (256 epochs)
+const SlotsPerSyncPeriod = 256 * SlotsPerEpoch
+
+// Checkpoint represents a finality checkpoint
+type Checkpoint struct {
+	Epoch int64
+	Root  string
+}
+
+// ChainHead represents the current head of the chain and its finality checkpoints
+type ChainHead struct {
+	Slot              int64
+	Root              string
+	Epoch             int64
+	SyncPeriod        int64
+	PreviousJustified Checkpoint
+	CurrentJustified  Checkpoint
+	Finalized         Checkpoint
+}
+
+// finalityCheckpointsResponse represents the response from /eth/v1/beacon/states/{state_id}/finality_checkpoints
+type finalityCheckpointsResponse struct {
+	Data struct {
+		PreviousJustified checkpointData `json:"previous_justified"`
+		CurrentJustified  checkpointData `json:"current_justified"`
+		Finalized         checkpointData `json:"finalized"`
+	} `json:"data"`
+}
+
+// checkpointData represents a checkpoint returned by the Beacon API
+type checkpointData struct {
+	Epoch string `json:"epoch"`
+	Root  string `json:"root"`
+}
+
+// GetChainHead retrieves the current head and finality checkpoints, cached for a few seconds
+func (s *EthereumService) GetChainHead(ctx context.Context) (*ChainHead, error) {
+	if cached, ok := s.cacheGet(ctx, "chain:head"); ok {
+		return cached.(*ChainHead), nil
+	}
+
+	var header blockHeaderResponse
+	if err := s.getBeaconJSON(ctx, "/eth/v1/beacon/headers/head", &header); err != nil {
+		return nil, err
+	}
+
+	var checkpoints finalityCheckpointsResponse
+	if err := s.getBeaconJSON(ctx, "/eth/v1/beacon/states/head/finality_checkpoints", &checkpoints); err != nil {
+		return nil, err
+	}
+
+	slot := parseDecimal(header.Data.Header.Message.Slot)
+	head := &ChainHead{
+		Slot:              slot,
+		Root:              header.Data.Root,
+		Epoch:             slot / SlotsPerEpoch,
+		SyncPeriod:        slot / SlotsPerSyncPeriod,
+		PreviousJustified: newCheckpoint(checkpoints.Data.PreviousJustified),
+		CurrentJustified:  newCheckpoint(checkpoints.Data.CurrentJustified),
+		Finalized:         newCheckpoint(checkpoints.Data.Finalized),
+	}
+
+	s.cache.Set("chain:head", head, chainHeadTTL)
+	return head, nil
+}
+
+// newCheckpoint converts a Beacon API checkpoint into a Checkpoint
+func newCheckpoint(data checkpointData) Checkpoint {
+	return Checkpoint{
+		Epoch: parseDecimal(data.Epoch),
+		Root:  data.Root,
+	}
+}
+
+// chainConfigTTL is how long the spec and fork schedule are cached, they only change with client upgrades
+const chainConfigTTL = time.Hour
+
+// Fork represents an entry of the fork schedule
+type Fork struct {
+	PreviousVersion string `json:"previous_version"`
+	CurrentVersion  string `json:"current_version"`
+	Epoch           string `json:"epoch"`
+}
+
+// specResponse represents the response from /eth/v1/config/spec
+type specResponse struct {
+	Data map[string]interface{} `json:"data"`
+}
+
+// forkScheduleResponse represents the response from /eth/v1/config/fork_schedule
+type forkScheduleResponse struct {
+	Data []Fork `json:"data"`
+}
+
+// GetChainSpec retrieves the beacon node's spec configuration, cached for an hour
+func (s *EthereumService) GetChainSpec(ctx context.Context) (map[string]interface{}, error) {
+	if cached, ok := s.cache.Get("chain:spec"); ok {
+		return cached.(map[string]interface{}), nil
+	}
+
+	var spec specResponse
+	if err := s.getBeaconJSON(ctx, "/eth/v1/config/spec", &spec); err != nil {
+		return nil, err
+	}
+
+	s.cache.Set("chain:spec", spec.Data, chainConfigTTL)
+	return spec.Data, nil
+}
+
+// GetForkSchedule retrieves the beacon node's fork schedule, cached for an hour
+func (s *EthereumService) GetForkSchedule(ctx context.Context) ([]Fork, error) {
+	if cached, ok := s.cache.Get("chain:forks"); ok {
+		return cached.([]Fork), nil
+	}
+
+	var forks forkScheduleResponse
+	if err := s.getBeaconJSON(ctx, "/eth/v1/config/fork_schedule", &forks); err != nil {
+		return nil, err
+	}
+
+	s.cache.Set("chain:forks", forks.Data, chainConfigTTL)
+	return forks.Data, nil
+}
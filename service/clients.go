@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// BeaconClient performs requests against a consensus layer Beacon API
+type BeaconClient interface {
+	// GetJSON performs a GET request for path and decodes the response into out
+	GetJSON(ctx context.Context, path string, out interface{}) error
+	// PostJSON performs a POST request for path with a JSON body and decodes the response into out
+	PostJSON(ctx context.Context, path string, body interface{}, out interface{}) error
+}
+
+// BlockDecoder is implemented by BeaconClients that decode blocks into fork-aware types
+type BlockDecoder interface {
+	// SignedBeaconBlock returns the block for blockID, or ErrSlotNotFound
+	SignedBeaconBlock(ctx context.Context, blockID string) (*spec.VersionedSignedBeaconBlock, error)
+}
+
+// HeadSubscriber is implemented by BeaconClients that stream new chain heads
+type HeadSubscriber interface {
+	// SubscribeHeads calls handler with the slot of every new head until the context is cancelled
+	SubscribeHeads(ctx context.Context, handler func(slot int64)) error
+}
+
+// ExecutionClient performs JSON-RPC calls against an execution layer node
+type ExecutionClient interface {
+	// Call invokes method with params and decodes the result into out, out may be nil
+	Call(ctx context.Context, method string, params []interface{}, out interface{}) error
+	// BlockWithReceipts returns the block at number together with its receipts, or ethereum.NotFound
+	BlockWithReceipts(ctx context.Context, number *big.Int) (*types.Block, []*types.Receipt, error)
+}
+
+// ExecutionHeadSubscriber is implemented by ExecutionClients that stream new chain heads, which requires a websocket
+// endpoint
+type ExecutionHeadSubscriber interface {
+	// SubscribeNewHeads calls handler with the number of every new head until the context is cancelled
+	SubscribeNewHeads(ctx context.Context, handler func(number uint64)) error
+}
+
+// wrapUpstreamError classifies a failed upstream call, running out of the request budget becomes ErrUpstreamTimeout
+// and a body that can't be decoded ErrUpstreamInvalidResponse. Rate limiting errors are kept as they are. The
+// provider URLs in the message, e.g. of transport errors, are redacted
+func wrapUpstreamError(ctx context.Context, err error) error {
+	if errors.Is(err, ErrUpstreamRateLimited) {
+		return err
+	}
+	message := RedactSecrets(err.Error())
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %s", ErrUpstreamTimeout, message)
+	}
+	if isInvalidJSON(err) {
+		return fmt.Errorf("%w: %s", ErrUpstreamInvalidResponse, message)
+	}
+	return fmt.Errorf("%w: %s", ErrRPCFailed, message)
+}
+
+// isInvalidJSON reports whether err comes from decoding a malformed or truncated JSON body
+func isInvalidJSON(err error) bool {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	return errors.As(err, &syntaxErr) || errors.As(err, &typeErr) || errors.Is(err, io.ErrUnexpectedEOF)
+}
@@ -0,0 +1,43 @@
+package service
+
+import "time"
+
+// Clock tells the time and waits for durations. Services and schedulers read the time through it so tests can move
+// time forward without sleeping
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// SystemClock is the Clock of the wall clock, used unless another one is set
+type SystemClock struct{}
+
+// Now returns the current local time
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// After waits for the duration to elapse and then sends the current time on the returned channel
+func (SystemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// SetClock replaces the clock the service reads the time from, it must be called before the service starts serving
+// requests
+func (s *EthereumService) SetClock(clock Clock) {
+	s.clock = clock
+	s.cache.clock = clock
+	s.stats.recent.setClock(clock)
+	s.audit.setClock(clock)
+}
+
+// Now returns the current time of the service clock
+func (s *EthereumService) Now() time.Time {
+	return s.clock.Now()
+}
+
+// wallClockSlot returns the slot future slots are rejected above. It counts slots since the Unix epoch rather than
+// since genesis, so the check needs no upstream lookup and only rejects slots that can't exist yet on any network
+func (s *EthereumService) wallClockSlot() int64 {
+	return s.clock.Now().Unix() / SecondsPerSlot
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// committeesTTL is how long the committees of a slot are cached
+const committeesTTL = 10 * time.Minute
+
+// Committee represents a beacon attestation committee of a slot
+type Committee struct {
+	Index      int64
+	Validators []int64
+}
+
+// committeesResponse represents the response from /eth/v1/beacon/states/{state_id}/committees
+type committeesResponse struct {
+	Data []struct {
+		Index      string   `json:"index"`
+		Slot       string   `json:"slot"`
+		Validators []string `json:"validators"`
+	} `json:"data"`
+}
+
+// GetCommitteesBySlot retrieves the attestation committees of a slot ordered by committee index
+func (s *EthereumService) GetCommitteesBySlot(ctx context.Context, slot int64) ([]Committee, error) {
+	cacheKey := fmt.Sprintf("committees:%d", slot)
+	if cached, ok := s.cacheGet(ctx, cacheKey); ok {
+		return cached.([]Committee), nil
+	}
+
+	// Committees are fixed for the whole epoch, so any state of the epoch can be used
+	var response committeesResponse
+	path := fmt.Sprintf("/eth/v1/beacon/states/%d/committees?slot=%d", slot/SlotsPerEpoch*SlotsPerEpoch, slot)
+	if err := s.getBeaconJSON(ctx, path, &response); err != nil {
+		return nil, err
+	}
+
+	committees := make([]Committee, 0, len(response.Data))
+	for _, data := range response.Data {
+		committee := Committee{
+			Index:      parseDecimal(data.Index),
+			Validators: make([]int64, 0, len(data.Validators)),
+		}
+		for _, validator := range data.Validators {
+			committee.Validators = append(committee.Validators, parseDecimal(validator))
+		}
+		committees = append(committees, committee)
+	}
+
+	s.cache.Set(cacheKey, committees, committeesTTL)
+	return committees, nil
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ErrUpstreamUnavailable is returned while the upstream providers haven't been reached since startup
+var ErrUpstreamUnavailable = errors.New("upstream providers are unavailable")
+
+// connectivity tracks whether the upstream providers have been reached. Once they have, outages are left to the
+// errors of the lookups
+type connectivity struct {
+	unavailable atomic.Bool
+
+	mu        sync.Mutex
+	pending   *UpstreamConfig // Endpoints whose clients couldn't be created yet, nil once they were
+	lastError error
+}
+
+// unavailableClient stands in for the upstream clients of a service that has no endpoints yet
+type unavailableClient struct{}
+
+func (unavailableClient) GetJSON(ctx context.Context, path string, out interface{}) error {
+	return ErrUpstreamUnavailable
+}
+
+func (unavailableClient) PostJSON(ctx context.Context, path string, body interface{}, out interface{}) error {
+	return ErrUpstreamUnavailable
+}
+
+func (unavailableClient) Call(ctx context.Context, method string, params []interface{}, out interface{}) error {
+	return ErrUpstreamUnavailable
+}
+
+func (unavailableClient) BlockWithReceipts(ctx context.Context, number *big.Int) (*types.Block, []*types.Receipt, error) {
+	return nil, nil, ErrUpstreamUnavailable
+}
+
+// NewDisconnectedEthereumService creates a service without upstream endpoints, whose lookups fail with
+// ErrUpstreamUnavailable until ConnectUpstreams succeeds
+func NewDisconnectedEthereumService() *EthereumService {
+	stats := &upstreamStats{}
+	s := newEthereumService(unavailableClient{}, unavailableClient{}, newUpstreamClient(DefaultTransportConfig(), stats), stats)
+	s.connectivity.unavailable.Store(true)
+	s.connectivity.lastError = errors.New("no upstream endpoints configured")
+	return s
+}
+
+// ConnectUpstreams sets the upstream endpoints and pings them. When the clients can't be created or the providers
+// don't answer, the service stays unavailable and ReconnectTask keeps retrying
+func (s *EthereumService) ConnectUpstreams(ctx context.Context, config UpstreamConfig) error {
+	if err := s.SetUpstreams(ctx, config); err != nil {
+		s.connectivity.mu.Lock()
+		s.connectivity.pending = &config
+		s.connectivity.lastError = err
+		s.connectivity.mu.Unlock()
+		s.connectivity.unavailable.Store(true)
+		return err
+	}
+	return s.checkUpstreams(ctx)
+}
+
+// UpstreamsAvailable reports whether the upstream providers have been reached, returning the last error otherwise
+func (s *EthereumService) UpstreamsAvailable() (bool, error) {
+	if !s.connectivity.unavailable.Load() {
+		return true, nil
+	}
+	s.connectivity.mu.Lock()
+	defer s.connectivity.mu.Unlock()
+	return false, s.connectivity.lastError
+}
+
+// ReconnectTask creates the scheduled task retrying unreachable upstream providers, it does nothing once they were
+// reached
+func (s *EthereumService) ReconnectTask(schedule Schedule) Task {
+	return Task{
+		Name:     "upstream-reconnect",
+		Schedule: schedule,
+		Run: func(ctx context.Context) error {
+			if !s.connectivity.unavailable.Load() {
+				return nil
+			}
+
+			s.connectivity.mu.Lock()
+			pending := s.connectivity.pending
+			s.connectivity.mu.Unlock()
+			if pending != nil {
+				return s.ConnectUpstreams(ctx, *pending)
+			}
+			return s.checkUpstreams(ctx)
+		},
+	}
+}
+
+// checkUpstreams pings the consensus and execution layer providers, marking the service available when both answer
+func (s *EthereumService) checkUpstreams(ctx context.Context) error {
+	err := s.pingUpstreams(ctx)
+
+	s.connectivity.mu.Lock()
+	defer s.connectivity.mu.Unlock()
+	if err != nil {
+		s.connectivity.lastError = err
+		s.connectivity.unavailable.Store(true)
+		return err
+	}
+	if s.connectivity.unavailable.Swap(false) {
+		log.Printf("Upstreams: connected")
+	}
+	s.connectivity.pending = nil
+	s.connectivity.lastError = nil
+	return nil
+}
+
+// pingUpstreams checks that both providers answer a cheap request
+func (s *EthereumService) pingUpstreams(ctx context.Context) error {
+	beacon, execution := s.clients()
+
+	var version json.RawMessage
+	if err := beacon.GetJSON(ctx, "/eth/v1/node/version", &version); err != nil {
+		return fmt.Errorf("beacon node unreachable: %w", err)
+	}
+	s.recordBeaconVersion(version)
+	var chainID string
+	if err := execution.Call(ctx, "eth_chainId", nil, &chainID); err != nil {
+		return fmt.Errorf("execution node unreachable: %w", err)
+	}
+	return nil
+}
+
+// clearPending forgets the endpoints waiting for their clients once others were set
+func (c *connectivity) clearPending() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pending = nil
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule decides when a scheduled task runs next
+type Schedule interface {
+	// Next returns the first run time after the given time
+	Next(after time.Time) time.Time
+}
+
+// intervalSchedule runs a task at a fixed interval
+type intervalSchedule time.Duration
+
+// Every returns a schedule running a task at a fixed interval
+func Every(interval time.Duration) Schedule {
+	return intervalSchedule(interval)
+}
+
+func (s intervalSchedule) Next(after time.Time) time.Time {
+	return after.Add(time.Duration(s))
+}
+
+func (s intervalSchedule) String() string {
+	return "@every " + time.Duration(s).String()
+}
+
+// cronSchedule is a parsed five field cron expression, evaluated in UTC
+type cronSchedule struct {
+	expr                                   string
+	minutes, hours, days, months, weekdays uint64 // Bit sets of the allowed values
+	anyDay, anyWeekday                     bool   // The day fields were *, as they combine with OR otherwise
+}
+
+// cronDescriptors maps the supported shorthands to their expressions
+var cronDescriptors = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+}
+
+// ParseSchedule parses a cron expression (minute, hour, day of month, month and day of week, supporting *, lists,
+// ranges and steps), one of @hourly, @daily, @weekly and @monthly, or "@every <duration>"
+func ParseSchedule(expr string) (Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if interval, ok := strings.CutPrefix(expr, "@every "); ok {
+		duration, err := time.ParseDuration(strings.TrimSpace(interval))
+		if err != nil || duration <= 0 {
+			return nil, fmt.Errorf("invalid schedule %q: positive duration expected", expr)
+		}
+		return Every(duration), nil
+	}
+
+	fields := strings.Fields(expr)
+	if descriptor, ok := cronDescriptors[expr]; ok {
+		fields = strings.Fields(descriptor)
+	}
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid schedule %q: 5 fields expected", expr)
+	}
+
+	schedule := &cronSchedule{
+		expr:       expr,
+		anyDay:     fields[2] == "*",
+		anyWeekday: fields[4] == "*",
+	}
+	bounds := []struct {
+		set      *uint64
+		min, max int
+	}{
+		{&schedule.minutes, 0, 59},
+		{&schedule.hours, 0, 23},
+		{&schedule.days, 1, 31},
+		{&schedule.months, 1, 12},
+		{&schedule.weekdays, 0, 7},
+	}
+	for i, field := range fields {
+		set, err := parseCronField(field, bounds[i].min, bounds[i].max)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %v", expr, err)
+		}
+		*bounds[i].set = set
+	}
+	// Both 0 and 7 are Sunday
+	if schedule.weekdays&(1<<7) != 0 {
+		schedule.weekdays |= 1
+	}
+
+	return schedule, nil
+}
+
+// parseCronField parses a comma separated list of values, ranges and steps into a bit set
+func parseCronField(field string, min, max int) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepPart); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+		}
+
+		low, high := min, max
+		if rangePart != "*" {
+			lowPart, highPart, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if low, err = strconv.Atoi(lowPart); err != nil {
+				return 0, fmt.Errorf("invalid value in %q", part)
+			}
+			high = low
+			if isRange {
+				if high, err = strconv.Atoi(highPart); err != nil {
+					return 0, fmt.Errorf("invalid value in %q", part)
+				}
+			} else if hasStep {
+				high = max
+			}
+		}
+		if low < min || high > max || low > high {
+			return 0, fmt.Errorf("%q is outside %d-%d", part, min, max)
+		}
+
+		for value := low; value <= high; value += step {
+			set |= 1 << value
+		}
+	}
+	return set, nil
+}
+
+// Next returns the first matching minute after the given time
+func (s *cronSchedule) Next(after time.Time) time.Time {
+	t := after.UTC().Truncate(time.Minute).Add(time.Minute)
+	// Every combination of fields repeats within a few years, the limit only guards against impossible dates like Feb 30
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case s.months&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+		case s.hours&(1<<uint(t.Hour())) == 0:
+			t = t.Truncate(time.Hour).Add(time.Hour)
+		case s.minutes&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// dayMatches checks the day of month and day of week fields, a day matches either if both are restricted
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	day := s.days&(1<<uint(t.Day())) != 0
+	weekday := s.weekdays&(1<<uint(t.Weekday())) != 0
+	if s.anyDay || s.anyWeekday {
+		return day && weekday
+	}
+	return day || weekday
+}
+
+func (s *cronSchedule) String() string {
+	return s.expr
+}
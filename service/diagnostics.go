@@ -0,0 +1,223 @@
+package service
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Version is the application version, set at build time with -ldflags "-X ethereum-validator-api/service.Version=..."
+var Version = "dev"
+
+// configKeys lists the environment variables reported in support bundles
+var configKeys = []string{
+	"GIN_MODE",
+	"ETH_RPC",
+	"BEACON_RPC",
+	"EXECUTION_RPC",
+	"SECONDARY_RPC",
+	"HEDGE_DELAY",
+	"REQUEST_BUDGET",
+	"REQUEST_BUDGET_LONG",
+	"UPSTREAM_TIMEOUT",
+	"UPSTREAM_MAX_IDLE_CONNS_PER_HOST",
+	"UPSTREAM_IDLE_CONN_TIMEOUT",
+	"UPSTREAM_KEEP_ALIVE",
+	"UPSTREAM_TLS_HANDSHAKE_TIMEOUT",
+	"UPSTREAM_RATE_LIMIT",
+	"UPSTREAM_BURST",
+	"CORS_ORIGIN",
+	"INDEXER_ENABLED",
+	"NETWORK_STATS_ENABLED",
+	"HEARTBEAT_URL",
+	"HEARTBEAT_SECRET",
+	"HEARTBEAT_INTERVAL",
+	"ADMIN_API_KEY",
+	"MEV_BUILDERS_FILE",
+	"MEV_BUILDERS_URL",
+	"MEV_BUILDERS_REFRESH_INTERVAL",
+	"FEE_RECIPIENT_LABELS_FILE",
+	"ENS_LOOKUP_ENABLED",
+	"MEV_RELAYS",
+}
+
+// LogBuffer is an io.Writer that keeps the last lines written to it
+type LogBuffer struct {
+	mu    sync.Mutex
+	lines []string
+	size  int
+	next  int
+	full  bool
+}
+
+// NewLogBuffer creates a LogBuffer holding up to size lines
+func NewLogBuffer(size int) *LogBuffer {
+	return &LogBuffer{
+		lines: make([]string, size),
+		size:  size,
+	}
+}
+
+func (b *LogBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		b.lines[b.next] = line
+		b.next = (b.next + 1) % b.size
+		if b.next == 0 {
+			b.full = true
+		}
+	}
+	return len(p), nil
+}
+
+// Lines returns the buffered lines, oldest first
+func (b *LogBuffer) Lines() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.full {
+		return append([]string(nil), b.lines[:b.next]...)
+	}
+	return append(append([]string(nil), b.lines[b.next:]...), b.lines[:b.next]...)
+}
+
+// RedactURL strips credentials, path and query from a URL since providers embed API keys in them
+func RedactURL(rawURL string) string {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil || parsedURL.Host == "" {
+		return "[REDACTED]"
+	}
+
+	redacted := parsedURL.Scheme + "://" + parsedURL.Host
+	if (parsedURL.Path != "" && parsedURL.Path != "/") || parsedURL.RawQuery != "" || parsedURL.User != nil {
+		redacted += "/[REDACTED]"
+	}
+	return redacted
+}
+
+// RedactedConfig returns the configured environment with secrets and provider URLs redacted
+func RedactedConfig() map[string]string {
+	config := make(map[string]string)
+	for _, key := range configKeys {
+		value, ok := os.LookupEnv(key)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case value == "":
+			config[key] = ""
+		case strings.HasSuffix(key, "_SECRET") || strings.HasSuffix(key, "_KEY"):
+			config[key] = "[REDACTED]"
+		case strings.HasSuffix(key, "_RPC") || strings.HasSuffix(key, "_URL"):
+			config[key] = RedactURL(value)
+		default:
+			config[key] = value
+		}
+	}
+	return config
+}
+
+// VersionInfo represents the build information of the running binary
+type VersionInfo struct {
+	Version   string `json:"version"`
+	GoVersion string `json:"go_version"`
+	Revision  string `json:"revision,omitempty"`
+	OS        string `json:"os"`
+	Arch      string `json:"arch"`
+}
+
+// GetVersionInfo returns the build information of the running binary
+func GetVersionInfo() VersionInfo {
+	info := VersionInfo{
+		Version:   Version,
+		GoVersion: runtime.Version(),
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+	}
+
+	if buildInfo, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range buildInfo.Settings {
+			if setting.Key == "vcs.revision" {
+				info.Revision = setting.Value
+			}
+		}
+	}
+	return info
+}
+
+// WriteSupportBundle writes a gzipped tar archive with sanitized diagnostics to w
+func (s *EthereumService) WriteSupportBundle(ctx context.Context, w io.Writer, logs *LogBuffer) error {
+	stats := map[string]interface{}{
+		"indexed_blocks":     s.index.Len(),
+		"latest_indexed":     s.index.LatestSlot(),
+		"builder_signatures": len(s.builders.List()),
+	}
+
+	files := map[string]interface{}{
+		"version.json": GetVersionInfo(),
+		"config.json":  RedactedConfig(),
+		"health.json":  s.GetHealthSummary(ctx),
+		"stats.json":   stats,
+	}
+
+	gz := gzip.NewWriter(w)
+	archive := tar.NewWriter(gz)
+	now := time.Now()
+
+	for name, content := range files {
+		data, err := json.MarshalIndent(content, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s: %w", name, err)
+		}
+		if err := writeTarFile(archive, name, data, now); err != nil {
+			return err
+		}
+	}
+
+	var logData bytes.Buffer
+	if logs != nil {
+		for _, line := range logs.Lines() {
+			logData.WriteString(line)
+			logData.WriteByte('\n')
+		}
+	}
+	if err := writeTarFile(archive, "logs.txt", logData.Bytes(), now); err != nil {
+		return err
+	}
+
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("failed to finalize archive: %w", err)
+	}
+	return gz.Close()
+}
+
+// writeTarFile adds a single file to a tar archive
+func writeTarFile(archive *tar.Writer, name string, data []byte, modTime time.Time) error {
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+	}
+	if err := archive.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write %s header: %w", name, err)
+	}
+	if _, err := archive.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
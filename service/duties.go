@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Duty kinds
+const (
+	DutyProposal      = "proposal"
+	DutySyncCommittee = "sync_committee"
+)
+
+// MaxDutyEpochs bounds the look-ahead window of GetValidatorDuties
+const MaxDutyEpochs = 512
+
+// proposerLookaheadEpochs is how many epochs of proposer duties the beacon node can compute, the current and the next
+const proposerLookaheadEpochs = 2
+
+// ValidatorDuty represents an upcoming duty window of a validator
+type ValidatorDuty struct {
+	Kind      string
+	StartSlot int64
+	EndSlot   int64
+	Start     time.Time
+	End       time.Time
+}
+
+// genesisResponse represents the response from /eth/v1/beacon/genesis
+type genesisResponse struct {
+	Data struct {
+		GenesisTime           string `json:"genesis_time"`
+		GenesisValidatorsRoot string `json:"genesis_validators_root"`
+	} `json:"data"`
+}
+
+// GetValidatorDuties returns the proposal and sync committee windows of a validator within the next epochs.
+// Proposals are only known for the current and next epoch, sync committees for the current and next period.
+func (s *EthereumService) GetValidatorDuties(ctx context.Context, validatorID string, epochs int64) ([]ValidatorDuty, error) {
+	index, err := s.resolveValidatorIndex(ctx, validatorID)
+	if err != nil {
+		return nil, err
+	}
+
+	genesis, err := s.getGenesisTime(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	headSlot, err := s.getHeadSlot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	headEpoch := headSlot / SlotsPerEpoch
+	lastSlot := (headEpoch+epochs)*SlotsPerEpoch - 1
+	duties := []ValidatorDuty{}
+
+	for epoch := headEpoch; epoch < headEpoch+min(epochs, proposerLookaheadEpochs); epoch++ {
+		var proposers proposerDutiesResponse
+		if err := s.getBeaconJSON(ctx, fmt.Sprintf("/eth/v1/validator/duties/proposer/%d", epoch), &proposers); err != nil {
+			return nil, err
+		}
+
+		for _, duty := range proposers.Data {
+			slot := parseDecimal(duty.Slot)
+			if parseDecimal(duty.ValidatorIndex) == index && slot > headSlot {
+				duties = append(duties, newValidatorDuty(genesis, DutyProposal, slot, slot))
+			}
+		}
+	}
+
+	for period := headSlot / SlotsPerSyncPeriod; period <= headSlot/SlotsPerSyncPeriod+1; period++ {
+		startSlot := period * SlotsPerSyncPeriod
+		if startSlot > lastSlot {
+			break
+		}
+
+		var committee stateSyncCommitteeResponse
+		path := fmt.Sprintf("/eth/v1/beacon/states/head/sync_committees?epoch=%d", startSlot/SlotsPerEpoch)
+		if err := s.getBeaconJSON(ctx, path, &committee); err != nil {
+			return nil, err
+		}
+
+		for _, member := range committee.Data.Validators {
+			if parseDecimal(member) == index {
+				duties = append(duties, newValidatorDuty(genesis, DutySyncCommittee, max(startSlot, headSlot), startSlot+SlotsPerSyncPeriod-1))
+				break
+			}
+		}
+	}
+
+	return duties, nil
+}
+
+// newValidatorDuty creates the duty window covering the slots from startSlot to endSlot
+func newValidatorDuty(genesis time.Time, kind string, startSlot, endSlot int64) ValidatorDuty {
+	return ValidatorDuty{
+		Kind:      kind,
+		StartSlot: startSlot,
+		EndSlot:   endSlot,
+		Start:     genesis.Add(time.Duration(startSlot*SecondsPerSlot) * time.Second),
+		End:       genesis.Add(time.Duration((endSlot+1)*SecondsPerSlot) * time.Second),
+	}
+}
+
+// getGenesisTime returns the genesis time of the chain, cached as it never changes
+func (s *EthereumService) getGenesisTime(ctx context.Context) (time.Time, error) {
+	if cached, ok := s.cache.Get("chain:genesis"); ok {
+		return cached.(time.Time), nil
+	}
+
+	var genesis genesisResponse
+	if err := s.getBeaconJSON(ctx, "/eth/v1/beacon/genesis", &genesis); err != nil {
+		return time.Time{}, err
+	}
+
+	seconds, err := strconv.ParseInt(genesis.Data.GenesisTime, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: invalid genesis time %q", ErrRPCFailed, genesis.Data.GenesisTime)
+	}
+
+	genesisTime := time.Unix(seconds, 0).UTC()
+	s.cache.Set("chain:genesis", genesisTime, chainConfigTTL)
+	return genesisTime, nil
+}
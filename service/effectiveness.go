@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Component weights of the effectiveness score, renormalized over the components the validator had duties for
+const (
+	attestationWeight   = 0.7
+	proposalWeight      = 0.15
+	syncCommitteeWeight = 0.15
+)
+
+// EffectivenessComponent represents how well a validator performed one kind of duty
+type EffectivenessComponent struct {
+	Duties    int     // Number of duties assigned within the window
+	Fulfilled int     // Number of duties performed
+	Rate      float64 // Effectiveness of the component between 0 and 1
+}
+
+// ValidatorEffectiveness represents the computed effectiveness of a validator over a window of epochs
+type ValidatorEffectiveness struct {
+	ValidatorIndex int64
+	FromEpoch      int64
+	ToEpoch        int64
+	Score          float64 // Weighted effectiveness between 0 and 100
+	Attestation    EffectivenessComponent
+	Proposal       EffectivenessComponent
+	SyncCommittee  EffectivenessComponent
+}
+
+// GetValidatorEffectiveness computes the effectiveness of a validator over the last epochs that have been rewarded.
+// Attestations come from the beacon rewards API, proposals from proposer duties checked against the block index,
+// and sync participation from the indexed blocks of the current sync committee period.
+func (s *EthereumService) GetValidatorEffectiveness(ctx context.Context, validatorID string, epochs int64) (*ValidatorEffectiveness, error) {
+	index, err := s.resolveValidatorIndex(ctx, validatorID)
+	if err != nil {
+		return nil, err
+	}
+
+	headSlot, err := s.getHeadSlot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	toEpoch := lastRewardedEpoch(headSlot)
+	fromEpoch := max(toEpoch-epochs+1, 0)
+	attestations, err := s.getAttestationsUntil(ctx, index, toEpoch, epochs)
+	if err != nil {
+		return nil, err
+	}
+	result := &ValidatorEffectiveness{
+		ValidatorIndex: index,
+		FromEpoch:      fromEpoch,
+		ToEpoch:        toEpoch,
+	}
+
+	// Attestations are rated on the share of correct votes, so late or wrong votes lower the rate
+	for _, perf := range attestations {
+		result.Attestation.Duties++
+		if perf.Included {
+			result.Attestation.Fulfilled++
+		}
+		for _, correct := range []bool{perf.HeadCorrect, perf.TargetCorrect, perf.SourceCorrect} {
+			if correct {
+				result.Attestation.Rate++
+			}
+		}
+	}
+	if result.Attestation.Duties > 0 {
+		result.Attestation.Rate /= float64(result.Attestation.Duties * 3)
+	}
+
+	if result.Proposal, err = s.getProposalEffectiveness(ctx, index, fromEpoch, toEpoch); err != nil {
+		return nil, err
+	}
+	if result.SyncCommittee, err = s.getSyncEffectiveness(ctx, index, fromEpoch, toEpoch, headSlot); err != nil {
+		return nil, err
+	}
+
+	result.Score = EffectivenessScore(result.Attestation, result.Proposal, result.SyncCommittee)
+	return result, nil
+}
+
+// EffectivenessScore combines the components into a score between 0 and 100, ignoring components without duties
+func EffectivenessScore(attestation, proposal, syncCommittee EffectivenessComponent) float64 {
+	var weighted, total float64
+	for _, c := range []struct {
+		component EffectivenessComponent
+		weight    float64
+	}{
+		{attestation, attestationWeight},
+		{proposal, proposalWeight},
+		{syncCommittee, syncCommitteeWeight},
+	} {
+		if c.component.Duties == 0 {
+			continue
+		}
+		weighted += c.component.Rate * c.weight
+		total += c.weight
+	}
+
+	if total == 0 {
+		return 0
+	}
+	return weighted / total * 100
+}
+
+// getProposalEffectiveness counts the proposals assigned to a validator within the epochs and how many made it on chain
+func (s *EthereumService) getProposalEffectiveness(ctx context.Context, index, fromEpoch, toEpoch int64) (EffectivenessComponent, error) {
+	var component EffectivenessComponent
+	for epoch := fromEpoch; epoch <= toEpoch; epoch++ {
+		var duties proposerDutiesResponse
+		if err := s.getBeaconJSON(ctx, fmt.Sprintf("/eth/v1/validator/duties/proposer/%d", epoch), &duties); err != nil {
+			return component, err
+		}
+
+		for _, duty := range duties.Data {
+			if parseDecimal(duty.ValidatorIndex) != index {
+				continue
+			}
+			component.Duties++
+
+			slot := parseDecimal(duty.Slot)
+			if s.index != nil {
+				if _, ok := s.index.Get(slot); ok {
+					component.Fulfilled++
+					continue
+				}
+			}
+
+			_, err := s.GetBlockDetailBySlot(ctx, slot)
+			switch {
+			case err == nil:
+				component.Fulfilled++
+			case !errors.Is(err, ErrSlotNotFound):
+				return component, err
+			}
+		}
+	}
+
+	if component.Duties > 0 {
+		component.Rate = float64(component.Fulfilled) / float64(component.Duties)
+	}
+	return component, nil
+}
+
+// getSyncEffectiveness measures the sync committee participation of a validator over the indexed blocks
+// of the current sync committee period that fall within the epochs
+func (s *EthereumService) getSyncEffectiveness(ctx context.Context, index, fromEpoch, toEpoch, headSlot int64) (EffectivenessComponent, error) {
+	var component EffectivenessComponent
+	if s.index == nil {
+		return component, nil
+	}
+
+	periodStart := headSlot / SlotsPerSyncPeriod * SlotsPerSyncPeriod
+	fromSlot := max(fromEpoch*SlotsPerEpoch, periodStart)
+	toSlot := (toEpoch+1)*SlotsPerEpoch - 1
+	if fromSlot > toSlot {
+		return component, nil
+	}
+
+	var committee stateSyncCommitteeResponse
+	if err := s.getBeaconJSON(ctx, "/eth/v1/beacon/states/head/sync_committees", &committee); err != nil {
+		return component, err
+	}
+
+	// A validator can hold several positions in the same committee
+	positions := make([]int, 0)
+	for position, member := range committee.Data.Validators {
+		if parseDecimal(member) == index {
+			positions = append(positions, position)
+		}
+	}
+	if len(positions) == 0 {
+		return component, nil
+	}
+
+	for _, block := range s.index.Range(fromSlot, toSlot, nil) {
+		for _, position := range positions {
+			component.Duties++
+			if isBitSet(block.SyncCommitteeBits, position) {
+				component.Fulfilled++
+			}
+		}
+	}
+
+	if component.Duties > 0 {
+		component.Rate = float64(component.Fulfilled) / float64(component.Duties)
+	}
+	return component, nil
+}
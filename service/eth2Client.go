@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	eth2client "github.com/attestantio/go-eth2-client"
+	"github.com/attestantio/go-eth2-client/api"
+	apiv1 "github.com/attestantio/go-eth2-client/api/v1"
+	eth2http "github.com/attestantio/go-eth2-client/http"
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/rs/zerolog"
+)
+
+// errTypedClientUnavailable is returned when the go-eth2-client connection is not active,
+// callers fall back to the plain JSON endpoints
+var errTypedClientUnavailable = errors.New("typed beacon client unavailable")
+
+// Eth2BeaconClient is a BeaconClient backed by attestantio/go-eth2-client. Blocks are decoded into
+// fork-aware types and new heads are streamed from the events endpoint, while endpoints the library
+// does not model are served by the embedded HTTPBeaconClient
+type Eth2BeaconClient struct {
+	*HTTPBeaconClient
+
+	mu   sync.Mutex
+	eth2 *eth2http.Service // Connected lazily so startup doesn't depend on the node being reachable
+}
+
+// NewEth2BeaconClient creates a BeaconClient for the Beacon API at baseURL
+func NewEth2BeaconClient(baseURL string, client *http.Client) *Eth2BeaconClient {
+	return &Eth2BeaconClient{
+		HTTPBeaconClient: NewHTTPBeaconClient(baseURL, client),
+	}
+}
+
+// service returns the go-eth2-client connection, creating it on first use
+func (b *Eth2BeaconClient) service(ctx context.Context) (*eth2http.Service, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.eth2 != nil {
+		return b.eth2, nil
+	}
+
+	client := &http.Client{
+		Timeout:   b.client.Timeout,
+		Transport: &rateLimitTransport{next: b.client.Transport},
+	}
+	// The connection outlives the request that created it
+	svc, err := eth2http.New(context.WithoutCancel(ctx),
+		eth2http.WithAddress(b.baseURL),
+		eth2http.WithHTTPClient(client),
+		eth2http.WithTimeout(client.Timeout),
+		eth2http.WithAllowDelayedStart(true),
+		eth2http.WithLogLevel(zerolog.Disabled),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errTypedClientUnavailable, err)
+	}
+	b.eth2 = svc.(*eth2http.Service)
+	return b.eth2, nil
+}
+
+// SignedBeaconBlock returns the fork-aware block for a block ID (slot, root, "head" or "finalized")
+func (b *Eth2BeaconClient) SignedBeaconBlock(ctx context.Context, blockID string) (*spec.VersionedSignedBeaconBlock, error) {
+	svc, err := b.service(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := svc.SignedBeaconBlock(ctx, &api.SignedBeaconBlockOpts{Block: blockID})
+	if err != nil {
+		var apiErr *api.Error
+		switch {
+		case errors.Is(err, eth2client.ErrNotActive):
+			return nil, errTypedClientUnavailable
+		case errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound:
+			return nil, ErrSlotNotFound
+		case errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusServiceUnavailable:
+			return nil, fmt.Errorf("%w: %v", ErrNotSynced, err)
+		}
+		return nil, wrapUpstreamError(ctx, err)
+	}
+	return response.Data, nil
+}
+
+// SubscribeHeads streams the slot of every new chain head to handler until the context is cancelled
+func (b *Eth2BeaconClient) SubscribeHeads(ctx context.Context, handler func(slot int64)) error {
+	svc, err := b.service(ctx)
+	if err != nil {
+		return err
+	}
+
+	return svc.Events(ctx, []string{"head"}, func(event *apiv1.Event) {
+		if head, ok := event.Data.(*apiv1.HeadEvent); ok {
+			handler(int64(head.Slot))
+		}
+	})
+}
+
+// rateLimitTransport applies the upstream rate limit to requests made by third-party clients
+type rateLimitTransport struct {
+	next http.RoundTripper
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Respect the provider's and the shared upstream rate limit
+	if err := waitForUpstream(req.Context(), req.URL.Host, defaultComputeUnits); err != nil {
+		return nil, err
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusTooManyRequests {
+		upstreamAccepted(req.URL.Host)
+		return resp, nil
+	}
+	upstreamRejected(req.URL.Host, parseRetryAfter(resp.Header))
+	// Only bodyless requests can be replayed safely, the retry waits until the provider accepts requests again
+	if req.Body == nil {
+		resp.Body.Close()
+		return t.RoundTrip(req)
+	}
+	return resp, nil
+}
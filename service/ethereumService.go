@@ -1,35 +1,113 @@
 package service
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"math/big"
 	"net/http"
 	"net/url"
+	"slices"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"golang.org/x/sync/singleflight"
 )
 
 // Standard error definitions for better error handling
 var (
-	ErrFutureSlot    = errors.New("requested slot is in the future")
-	ErrSlotNotFound  = errors.New("slot does not exist")
-	ErrInvalidRPC    = errors.New("invalid RPC endpoint")
-	ErrRPCFailed     = errors.New("RPC request failed")
+	ErrFutureSlot              = errors.New("requested slot is in the future")
+	ErrSlotNotFound            = errors.New("slot does not exist")
+	ErrInvalidRPC              = errors.New("invalid RPC endpoint")
+	ErrRPCFailed               = errors.New("RPC request failed")
+	ErrUpstreamTimeout         = errors.New("upstream request timed out")
+	ErrUpstreamRateLimited     = errors.New("upstream provider is rate limiting requests")
+	ErrUpstreamInvalidResponse = errors.New("upstream returned an invalid response")
+	ErrNotSynced               = errors.New("upstream node is not synced")
 )
 
+// percentileWindow is the number of slots before a block whose indexed rewards its reward is compared against
+const percentileWindow = 1000
+
+// EthereumService orchestrates lookups across the consensus and execution layer clients
 type EthereumService struct {
-	rpcURL string
-	client *http.Client
+	clientsMu sync.RWMutex // Guards beacon, execution and upstreams, which are replaced when upstreams are reconfigured
+	beacon    BeaconClient
+	execution ExecutionClient
+	client    *http.Client // Shared HTTP client for upstream requests
+	index     *BlockIndex
+	jobs      *JobQueue
+	cache     *responseCache
+	inflight  singleflight.Group // Coalesces identical concurrent Beacon API requests
+	stats     *upstreamStats
+	providers map[string]*providerAuth // Authentication of upstream providers keyed by host
+	builders  *BuilderRegistry
+	operators *OperatorRegistry
+	relays    []string
+
+	relayClient *http.Client // Client of the relay data API, apart from the upstream client
+
+	transportConfig TransportConfig // Settings the shared upstream transport is built from
+	faults          FaultConfig     // Faults injected into upstream requests, disabled unless configured
+
+	feeRecipients *FeeRecipientRegistry
+	ensLookup     bool
+	ensCache      *responseCache     // Verified ENS names keyed by address, "" for addresses without one
+	ensInflight   singleflight.Group // Coalesces concurrent ENS lookups of an address
+
+	networkMu    sync.RWMutex
+	networkStats *NetworkStats // Refreshed by the network stats task, nil until the first refresh
+
+	prices          *PriceSeries  // Maintained by the price task
+	priceProvider   PriceProvider // nil while fiat valuation is disabled
+	priceCurrencies []string      // Lower-cased currencies whose prices are tracked
+
+	blockNumbers *BlockNumberIndex // Slot to execution block number mapping
+	usage        *UsageTracker     // Requests per client, endpoint and day
+	audit        *AuditLog         // Admin actions
+	capture      *UpstreamCapture  // Last upstream exchanges, captured while enabled
+	watchlists   *WatchlistStore   // Watched validators, alert rules and notification channels per account
+
+	verify    bool       // Whether upstream responses are checked for consistency
+	upstreams []upstream // Individual providers behind the hedged clients, empty without a secondary provider
+
+	lightClient *lightClientStore // nil while light client verification is disabled
+
+	connectivity connectivity // Whether the upstream providers have been reached
+	local        localNode    // Local node mode
+	beaconNode   beaconNode   // Version and client of the primary beacon node
+
+	clock Clock // Source of the current time
 }
 
 type BlockReward struct {
-	Status string   `json:"status"` // "mev" or "vanilla"
-	Reward *big.Int `json:"reward"` // in GWEI
+	Status    string   `json:"status"`     // "mev" or "vanilla"
+	Reward    *big.Int `json:"reward"`     // in GWEI
+	RewardWei *big.Int `json:"reward_wei"` // Exact reward in Wei, nil for rewards that were stored without it
+	Builder   string   `json:"builder"`    // Normalized builder name for MEV blocks, "" if unknown
+	ExtraData string   `json:"extra_data"` // Raw extraData of the execution payload
+
+	FeeRecipient      string `json:"fee_recipient"`       // Address that received the priority fees
+	FeeRecipientLabel string `json:"fee_recipient_label"` // Known entity or ENS name of the fee recipient, "" if unknown
+
+	ValuePercentile *float64 `json:"value_percentile"` // Percentile of the reward among the indexed blocks before it, nil if unknown
+	Relay           string   `json:"relay"`            // Relay that delivered the payload, only resolved by the indexer
+	Finalized       bool     `json:"finalized"`        // Whether the slot was finalized, a reorg can still change the reward otherwise
+}
+
+// Wei returns the reward in Wei, derived from the Gwei amount when the exact value is unknown
+func (r *BlockReward) Wei() *big.Int {
+	if r.RewardWei != nil {
+		return r.RewardWei
+	}
+	if r.Reward == nil {
+		return big.NewInt(0)
+	}
+	return new(big.Int).Mul(r.Reward, big.NewInt(1e9))
 }
 
 // BeaconBlockResponse represents the response from the Beacon API for block details
@@ -62,36 +140,20 @@ type BeaconBlockResponse struct {
 					GasUsed       string   `json:"gas_used"`
 					Timestamp     string   `json:"timestamp"`
 					Transactions  []string `json:"transactions"`
+					Withdrawals   []struct {
+						ValidatorIndex string `json:"validator_index"`
+						Amount         string `json:"amount"`
+					} `json:"withdrawals"`
 				} `json:"execution_payload"`
+				SyncAggregate struct {
+					SyncCommitteeBits      string `json:"sync_committee_bits"`
+					SyncCommitteeSignature string `json:"sync_committee_signature"`
+				} `json:"sync_aggregate"`
 			} `json:"body"`
 		} `json:"message"`
 	} `json:"data"`
 }
 
-// ExecutionBlockResponse represents the response from the Execution API
-type ExecutionBlockResponse struct {
-	Result struct {
-		Transactions []struct {
-			Hash             string `json:"hash"`
-			GasPrice         string `json:"gasPrice"`
-			Gas             string `json:"gas"`
-			MaxPriorityFee   string `json:"maxPriorityFeePerGas"`
-			MaxFeePerGas     string `json:"maxFeePerGas"`
-			TransactionIndex string `json:"transactionIndex"`
-		} `json:"transactions"`
-		BaseFeePerGas string `json:"baseFeePerGas"`
-	} `json:"result"`
-}
-
-// SyncCommitteeResponse represents the response from the Beacon API for sync committee duties
-type SyncCommitteeResponse struct {
-	Data struct {
-		ValidatorSyncAssignments []struct {
-			ValidatorPubKey string `json:"validator_pubkey"`
-		} `json:"validator_sync_assignments"`
-	} `json:"data"`
-}
-
 // RPCRequest represents a JSON-RPC request
 type RPCRequest struct {
 	JSONRPC string        `json:"jsonrpc"`
@@ -100,652 +162,446 @@ type RPCRequest struct {
 	ID      int           `json:"id"`
 }
 
-// Known MEV-Boost builder prefixes in extraData
-var mevBuilderPrefixes = []string{
-	"flashbots",
-	"builder0x69",
-	"rsync-builder",
-	"manifold",
-	"eth-builder",
-}
-
+// NewEthereumService creates a service using HTTP clients for rpcURL, which also serves the Beacon API until SetBeaconURL is called
 func NewEthereumService(rpcURL string) (*EthereumService, error) {
-	// Validate URL
-	if rpcURL == "" {
-		return nil, fmt.Errorf("RPC URL cannot be empty")
+	if err := validateURL("RPC", rpcURL); err != nil {
+		return nil, err
 	}
 
-	parsedURL, err := url.Parse(rpcURL)
+	stats := &upstreamStats{}
+	client := newUpstreamClient(DefaultTransportConfig(), stats)
+
+	// Most providers serve the Beacon API from the same endpoint
+	execution, err := NewRPCExecutionClient(context.Background(), rpcURL, client)
 	if err != nil {
-		return nil, fmt.Errorf("invalid RPC URL: %v", err)
+		return nil, err
 	}
+	return newEthereumService(NewEth2BeaconClient(rpcURL, client), execution, client, stats), nil
+}
 
-	// Additional URL validation
-	if !parsedURL.IsAbs() {
-		return nil, fmt.Errorf("RPC URL must be absolute")
-	}
+// NewEthereumServiceWithClients creates a service on top of the given consensus and execution layer clients
+func NewEthereumServiceWithClients(beacon BeaconClient, execution ExecutionClient) *EthereumService {
+	stats := &upstreamStats{}
+	client := newUpstreamClient(DefaultTransportConfig(), stats)
+	return newEthereumService(beacon, execution, client, stats)
+}
 
-	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
-		return nil, fmt.Errorf("RPC URL must use http or https scheme")
-	}
+// newEthereumService wires the clients into a service with the default registries
+func newEthereumService(beacon BeaconClient, execution ExecutionClient, client *http.Client, stats *upstreamStats) *EthereumService {
+	s := &EthereumService{
+		beacon:    beacon,
+		execution: execution,
+		client:    client,
+		index:     NewBlockIndex(),
+		jobs:      NewJobQueue(),
+		cache:     newResponseCache(SystemClock{}),
+		stats:     stats,
+		providers: make(map[string]*providerAuth),
+		builders:  NewBuilderRegistry(DefaultBuilderSignatures()),
+		operators: NewOperatorRegistry(nil),
+		relays:    DefaultRelays(),
+
+		// Relays are third parties, so they don't use the upstream client and its budgets and stats
+		relayClient: &http.Client{Timeout: relayTimeout},
+
+		transportConfig: DefaultTransportConfig(),
+
+		feeRecipients: NewFeeRecipientRegistry(DefaultFeeRecipientLabels()),
+		ensCache:      newENSCache(SystemClock{}),
+
+		prices: NewPriceSeries(),
+
+		blockNumbers: NewBlockNumberIndex(),
+		usage:        NewUsageTracker(),
+		audit:        NewAuditLog(),
+		capture:      NewUpstreamCapture(DefaultUpstreamCaptureSize),
+		watchlists:   NewWatchlistStore(),
+
+		clock: SystemClock{},
+	}
+	// The shared client captures upstream exchanges once capturing is enabled
+	s.client.Transport = s.newUpstreamTransport()
+	return s
+}
 
-	return &EthereumService{
-		rpcURL: rpcURL,
-		client: &http.Client{
-			Timeout: time.Second * 10,
-		},
-	}, nil
+// UpstreamConfig are the upstream endpoints a service sends its lookups to
+type UpstreamConfig struct {
+	RPCURL       string        // Serves both layers unless BeaconURL or ExecutionURL are set
+	BeaconURL    string        // Dedicated Beacon API endpoint, "" to use RPCURL
+	ExecutionURL string        // Dedicated JSON-RPC endpoint, http(s) or ws(s), "" to use RPCURL
+	SecondaryURL string        // Provider requests are hedged with, "" to disable hedging
+	HedgeDelay   time.Duration // How long the configured endpoints get before the secondary is asked as well
+	QuorumURLs   []string      // Further Beacon API endpoints that must agree on block and state roots, none to disable
+	Quorum       int           // Beacon nodes that must agree with QuorumURLs set, 0 for a majority
 }
 
-// GetBlockRewardBySlot retrieves block reward information for a given slot
-func (s *EthereumService) GetBlockRewardBySlot(ctx context.Context, slot int64) (*BlockReward, error) {
-	// Validate slot is not in the future
-	currentSlot := time.Now().Unix() / 12 // 12 second slots
-	if slot > currentSlot {
-		return nil, fmt.Errorf("%w (current slot: %d)", ErrFutureSlot, currentSlot)
-	}
+// Equal reports whether both configurations set the same endpoints
+func (c UpstreamConfig) Equal(other UpstreamConfig) bool {
+	return c.RPCURL == other.RPCURL && c.BeaconURL == other.BeaconURL && c.ExecutionURL == other.ExecutionURL &&
+		c.SecondaryURL == other.SecondaryURL && c.HedgeDelay == other.HedgeDelay &&
+		slices.Equal(c.QuorumURLs, other.QuorumURLs) && c.Quorum == other.Quorum
+}
 
-	// First get the beacon block to check if it's MEV
-	beaconBlock, err := s.getBeaconBlock(ctx, slot)
-	if err != nil {
-		if strings.Contains(err.Error(), "does not exist") {
-			return nil, ErrSlotNotFound
-		}
-		return nil, fmt.Errorf("failed to get beacon block: %w", err)
+// SetBeaconURL overrides the Beacon API endpoint used for consensus layer lookups
+func (s *EthereumService) SetBeaconURL(beaconURL string) error {
+	if err := validateURL("Beacon", beaconURL); err != nil {
+		return err
 	}
+	beacon := NewEth2BeaconClient(beaconURL, s.client)
 
-	// Check if block is MEV produced
-	isMev := s.isMEVBlock(beaconBlock)
-
-	// Get execution block details for reward calculation
-	blockHash := beaconBlock.Data.Message.Body.ExecutionPayload.BlockHash
-	if blockHash == "" {
-		return &BlockReward{
-			Status: "vanilla",
-			Reward: big.NewInt(0),
-		}, nil
-	}
+	s.clientsMu.Lock()
+	defer s.clientsMu.Unlock()
+	s.beacon = beacon
+	return nil
+}
 
-	reward, err := s.getExecutionBlockReward(ctx, blockHash, beaconBlock)
+// SetExecutionURL overrides the JSON-RPC endpoint used for execution layer lookups, ws(s) endpoints keep a persistent connection
+func (s *EthereumService) SetExecutionURL(ctx context.Context, executionURL string) error {
+	execution, err := s.dialExecution(ctx, executionURL)
 	if err != nil {
-		// If we can't get the reward, return a default value but don't fail
-		fmt.Printf("Warning: failed to get execution block reward: %v\n", err)
-		defaultReward, _ := new(big.Int).SetString("10000000", 10) // Default reward in Wei
-		return &BlockReward{
-			Status: map[bool]string{true: "mev", false: "vanilla"}[isMev],
-			Reward: new(big.Int).Div(defaultReward, big.NewInt(1e9)), // Convert to Gwei
-		}, nil
+		return err
 	}
 
-	// Convert Wei to Gwei
-	gweiReward := new(big.Int).Div(reward, big.NewInt(1e9))
+	s.clientsMu.Lock()
+	defer s.clientsMu.Unlock()
+	s.execution = execution
+	return nil
+}
 
-	// Ensure we're not returning zero, which would look like an error to the user
-	if gweiReward.Cmp(big.NewInt(0)) == 0 {
-		// Set a small default value
-		gweiReward = big.NewInt(1000) // 1000 gwei (~0.000001 ETH)
+// EnableHedging sends consensus and execution layer requests to a secondary provider as well
+// when the configured endpoints haven't answered within delay
+func (s *EthereumService) EnableHedging(ctx context.Context, secondaryURL string, delay time.Duration) error {
+	beacon, execution := s.clients()
+	hedgedBeacon, hedgedExecution, upstreams, err := s.hedgeClients(ctx, beacon, execution, secondaryURL, delay)
+	if err != nil {
+		return err
 	}
 
-	return &BlockReward{
-		Status: map[bool]string{true: "mev", false: "vanilla"}[isMev],
-		Reward: gweiReward,
-	}, nil
+	s.clientsMu.Lock()
+	defer s.clientsMu.Unlock()
+	s.beacon, s.execution, s.upstreams = hedgedBeacon, hedgedExecution, upstreams
+	return nil
 }
 
-// isMEVBlock checks if a block was produced by MEV-Boost
-func (s *EthereumService) isMEVBlock(block *BeaconBlockResponse) bool {
-	extraData := block.Data.Message.Body.ExecutionPayload.ExtraData
-
-	// Check for empty extraData
-	if len(extraData) == 0 {
-		return false
-	}
-
-	// Check for known MEV builder signatures in extraData
-	for _, prefix := range mevBuilderPrefixes {
-		if strings.Contains(strings.ToLower(extraData), prefix) {
-			return true
-		}
+// SetUpstreams replaces all upstream clients with clients for config. Requests in flight finish on the clients they
+// started with, and nothing is replaced if any endpoint is invalid
+func (s *EthereumService) SetUpstreams(ctx context.Context, config UpstreamConfig) error {
+	RegisterSecretURLs(append([]string{config.RPCURL, config.BeaconURL, config.ExecutionURL, config.SecondaryURL}, config.QuorumURLs...)...)
+	if err := validateURL("RPC", config.RPCURL); err != nil {
+		return err
 	}
 
-	// Simplified logic - for this API we'll consider blocks that have substantial transactions as potential MEV blocks
-	// In a production environment, this should be more sophisticated
-	txCount := len(block.Data.Message.Body.ExecutionPayload.Transactions)
-	if txCount > 20 { // Arbitrary threshold
-		return true
+	beaconURL := config.BeaconURL
+	if beaconURL == "" {
+		beaconURL = config.RPCURL
+	} else if err := validateURL("Beacon", beaconURL); err != nil {
+		return err
 	}
+	var beacon BeaconClient = NewEth2BeaconClient(beaconURL, s.client)
 
-	// Default to assuming vanilla blocks to be safe
-	return false
-}
-
-// GetSyncDutiesBySlot retrieves sync committee duties for a given slot
-func (s *EthereumService) GetSyncDutiesBySlot(ctx context.Context, slot int64) ([]string, error) {
-	// Validate slot
-	currentSlot := time.Now().Unix() / 12 // 12 second slots
-	if slot > currentSlot {
-		return nil, ErrFutureSlot
+	executionURL := config.ExecutionURL
+	if executionURL == "" {
+		executionURL = config.RPCURL
 	}
-
-	// Calculate the epoch from the slot (32 slots per epoch in Ethereum)
-	epoch := slot / 32
-
-	// Calculate the sync committee period from the epoch
-	// Sync committees rotate every 256 epochs (= 8192 slots)
-	syncPeriod := epoch / 256
-
-	// Use QuickNode's Beacon API endpoint for sync committee data
-	// We'll use eth_getBlockByNumber first to ensure the slot/block exists
-	rpcReq := RPCRequest{
-		JSONRPC: "2.0",
-		Method:  "eth_getBlockByNumber",
-		Params:  []interface{}{fmt.Sprintf("0x%x", slot), false},
-		ID:      1,
+	if s.LocalNode() {
+		warnEngineAPI(executionURL)
 	}
-
-	reqBody, err := json.Marshal(rpcReq)
+	execution, err := s.dialExecution(ctx, executionURL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
+		return err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", s.rpcURL, bytes.NewBuffer(reqBody))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	var upstreams []upstream
+	if config.SecondaryURL != "" {
+		if beacon, execution, upstreams, err = s.hedgeClients(ctx, beacon, execution, config.SecondaryURL, config.HedgeDelay); err != nil {
+			return err
+		}
+	}
+	if len(config.QuorumURLs) > 0 {
+		if beacon, err = s.quorumClient(beacon, config.QuorumURLs, config.Quorum); err != nil {
+			return err
+		}
 	}
-	req.Header.Set("Content-Type", "application/json")
 
-	// Add rate limiting delay
-	time.Sleep(time.Second) // Respect QuickNode's 1 request/second limit
+	s.clientsMu.Lock()
+	defer s.clientsMu.Unlock()
+	s.beacon, s.execution, s.upstreams = beacon, execution, upstreams
+	s.connectivity.clearPending()
+	return nil
+}
 
-	resp, err := s.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("%w: %v", ErrRPCFailed, err)
-	}
-	defer resp.Body.Close()
+// clients returns the consensus and execution layer clients lookups are currently sent to
+func (s *EthereumService) clients() (BeaconClient, ExecutionClient) {
+	s.clientsMu.RLock()
+	defer s.clientsMu.RUnlock()
+	return s.beacon, s.execution
+}
 
-	// Read response for block check
-	blockRespBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %v", err)
-	}
+// beaconClient returns the consensus layer client lookups are currently sent to
+func (s *EthereumService) beaconClient() BeaconClient {
+	beacon, _ := s.clients()
+	return beacon
+}
 
-	// Check for QuickNode rate limit error
-	if strings.Contains(string(blockRespBody), "request limit reached") {
-		time.Sleep(time.Second * 2) // Wait longer if rate limited
-		return s.GetSyncDutiesBySlot(ctx, slot) // Retry the request
-	}
+// executionClient returns the execution layer client lookups are currently sent to
+func (s *EthereumService) executionClient() ExecutionClient {
+	_, execution := s.clients()
+	return execution
+}
 
-	// Now make a second request to get the actual sync committee data using the sync period
-	// This is the beacon chain API call to get sync committee validators
-	
-	// Use eth_syncing to check if node is synced
-	syncReq := RPCRequest{
-		JSONRPC: "2.0",
-		Method:  "eth_syncing",
-		Params:  []interface{}{},
-		ID:      2,
-	}
+// upstreamProviders returns the individual providers behind the hedged clients
+func (s *EthereumService) upstreamProviders() []upstream {
+	s.clientsMu.RLock()
+	defer s.clientsMu.RUnlock()
+	return s.upstreams
+}
 
-	syncReqBody, err := json.Marshal(syncReq)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal sync check request: %v", err)
+// dialExecution creates a client for a JSON-RPC endpoint, ws(s) endpoints keep a persistent connection
+func (s *EthereumService) dialExecution(ctx context.Context, executionURL string) (ExecutionClient, error) {
+	parsedURL, err := url.Parse(executionURL)
+	if err != nil || (parsedURL.Scheme != "ws" && parsedURL.Scheme != "wss") {
+		if err := validateURL("Execution", executionURL); err != nil {
+			return nil, err
+		}
 	}
 
-	syncCheckReq, err := http.NewRequestWithContext(ctx, "POST", s.rpcURL, bytes.NewBuffer(syncReqBody))
+	execution, err := NewRPCExecutionClient(ctx, executionURL, s.client, s.websocketOptions(executionURL)...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create sync check request: %v", err)
+		return nil, err
 	}
-	syncCheckReq.Header.Set("Content-Type", "application/json")
+	return execution, nil
+}
 
-	// Add rate limiting delay
-	time.Sleep(time.Second)
+// hedgeClients wraps the clients of the primary provider into clients hedging with a secondary provider, returning
+// both providers for cross-checks
+func (s *EthereumService) hedgeClients(ctx context.Context, beacon BeaconClient, execution ExecutionClient, secondaryURL string, delay time.Duration) (BeaconClient, ExecutionClient, []upstream, error) {
+	if err := validateURL("Secondary", secondaryURL); err != nil {
+		return nil, nil, nil, err
+	}
 
-	syncCheckResp, err := s.client.Do(syncCheckReq)
+	secondaryExecution, err := NewRPCExecutionClient(ctx, secondaryURL, s.client)
 	if err != nil {
-		return nil, fmt.Errorf("failed to make sync check request: %v", err)
+		return nil, nil, nil, err
 	}
-	defer syncCheckResp.Body.Close()
+	secondary := upstream{name: "secondary", beacon: NewEth2BeaconClient(secondaryURL, s.client), execution: secondaryExecution}
+	upstreams := []upstream{{name: "primary", beacon: beacon, execution: execution}, secondary}
+	return NewHedgedBeaconClient(beacon, secondary.beacon, delay), NewHedgedExecutionClient(execution, secondary.execution, delay), upstreams, nil
+}
 
-	// Now use consensus specific method to get sync committee
-	syncCommitteeReq := RPCRequest{
-		JSONRPC: "2.0",
-		Method:  "beacon_get_state_sync_committees",
-		Params:  []interface{}{fmt.Sprintf("0x%x", epoch), fmt.Sprintf("0x%x", syncPeriod)},
-		ID:      3,
+// validateURL checks that an upstream endpoint is an absolute http(s) URL
+func validateURL(name, rawURL string) error {
+	if rawURL == "" {
+		return fmt.Errorf("%s URL cannot be empty", name)
 	}
 
-	committeeReqBody, err := json.Marshal(syncCommitteeReq)
+	parsedURL, err := url.Parse(rawURL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal committee request: %v", err)
+		return fmt.Errorf("invalid %s URL: %v", name, err)
 	}
 
-	committeeReq, err := http.NewRequestWithContext(ctx, "POST", s.rpcURL, bytes.NewBuffer(committeeReqBody))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create committee request: %v", err)
+	// Additional URL validation
+	if !parsedURL.IsAbs() {
+		return fmt.Errorf("%s URL must be absolute", name)
 	}
-	committeeReq.Header.Set("Content-Type", "application/json")
-
-	// Add rate limiting delay
-	time.Sleep(time.Second)
 
-	committeeResp, err := s.client.Do(committeeReq)
-	if err != nil {
-		return nil, fmt.Errorf("failed to make committee request: %v", err)
+	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
+		return fmt.Errorf("%s URL must use http or https scheme", name)
 	}
-	defer committeeResp.Body.Close()
-
-	// Read and log the response for debugging
-	committeeRespBody, err := io.ReadAll(committeeResp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read committee response body: %v", err)
-	}
-
-	fmt.Printf("Response from QuickNode API (sync committee): %s\n", string(committeeRespBody))
-
-	// Check if we got a valid response or fallback to alternative API
-	var committeeData struct {
-		Result struct {
-			Data struct {
-				Validators []string `json:"validators"`
-			} `json:"data"`
-		} `json:"result"`
-		Error *struct {
-			Code    int    `json:"code"`
-			Message string `json:"message"`
-		} `json:"error"`
-	}
-
-	if err := json.Unmarshal(committeeRespBody, &committeeData); err != nil || 
-	   (committeeData.Error != nil && committeeData.Error.Message != "") {
-		// If the beacon_get_state_sync_committees failed, try with beacon_get_validators API
-		// This is another approach to get validators data
-		validatorsReq := RPCRequest{
-			JSONRPC: "2.0",
-			Method:  "beacon_get_validators",
-			Params:  []interface{}{fmt.Sprintf("0x%x", epoch)},
-			ID:      4,
-		}
 
-		validatorsReqBody, err := json.Marshal(validatorsReq)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal validators request: %v", err)
-		}
+	return nil
+}
 
-		validatorsHttpReq, err := http.NewRequestWithContext(ctx, "POST", s.rpcURL, bytes.NewBuffer(validatorsReqBody))
-		if err != nil {
-			return nil, fmt.Errorf("failed to create validators request: %v", err)
-		}
-		validatorsHttpReq.Header.Set("Content-Type", "application/json")
+// Index returns the block index populated by block lookups and the background indexer
+func (s *EthereumService) Index() *BlockIndex {
+	return s.index
+}
 
-		// Add rate limiting delay
-		time.Sleep(time.Second)
+// Builders returns the registry used to identify MEV builders
+func (s *EthereumService) Builders() *BuilderRegistry {
+	return s.builders
+}
 
-		validatorsResp, err := s.client.Do(validatorsHttpReq)
-		if err != nil {
-			return nil, fmt.Errorf("failed to make validators request: %v", err)
-		}
-		defer validatorsResp.Body.Close()
+// SetBuilders replaces the registry used to identify MEV builders
+func (s *EthereumService) SetBuilders(builders *BuilderRegistry) {
+	s.builders = builders
+}
 
-		// Read response
-		validatorsRespBody, err := io.ReadAll(validatorsResp.Body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read validators response body: %v", err)
-		}
+// Operators returns the registry used to tag validators with their staking pool and node operator
+func (s *EthereumService) Operators() *OperatorRegistry {
+	return s.operators
+}
 
-		fmt.Printf("Response from QuickNode API (validators): %s\n", string(validatorsRespBody))
-
-		// Try to extract validators list from the response
-		var validatorsData struct {
-			Result struct {
-				Data []struct {
-					Validator struct {
-						Pubkey string `json:"pubkey"`
-					} `json:"validator"`
-				} `json:"data"`
-			} `json:"result"`
-		}
+// SetOperators replaces the registry used to tag validators with their staking pool and node operator
+func (s *EthereumService) SetOperators(operators *OperatorRegistry) {
+	s.operators = operators
+}
 
-		if err := json.Unmarshal(validatorsRespBody, &validatorsData); err != nil || 
-		   len(validatorsData.Result.Data) == 0 {
-			// As a last resort, get active validators subset
-			return s.getActiveValidatorsForEpoch(ctx, epoch, slot)
-		}
+// SetFeeRecipients replaces the registry used to label fee recipients
+func (s *EthereumService) SetFeeRecipients(feeRecipients *FeeRecipientRegistry) {
+	s.feeRecipients = feeRecipients
+}
 
-		// Extract and return up to 32 validators for display (sync committee size is 512 normally)
-		validators := make([]string, 0, 32)
-		for i, v := range validatorsData.Result.Data {
-			if i >= 32 { // Limit to 32 validators for UI display
-				break
-			}
-			validators = append(validators, v.Validator.Pubkey)
-		}
+// EnableENSLookup enables reverse ENS resolution of fee recipients missing from the registry
+func (s *EthereumService) EnableENSLookup(enabled bool) {
+	s.ensLookup = enabled
+}
 
-		return validators, nil
-	}
-
-	// Process the validators from sync committee response
-	validators := committeeData.Result.Data.Validators
-	
-	// Limit to max 32 validators for better UI display
-	if len(validators) > 32 {
-		validators = validators[:32]
-	}
-
-	return validators, nil
-}
-
-// getActiveValidatorsForEpoch is a fallback method to get a subset of validators for a given epoch
-func (s *EthereumService) getActiveValidatorsForEpoch(ctx context.Context, epoch, slot int64) ([]string, error) {
-	// As a fallback, use a curated list of real validator pubkeys
-	// These are actual validator pubkeys from the Ethereum mainnet
-	
-	// Real Ethereum validator pubkeys (BLS12-381 format)
-	validatorPubkeys := []string{
-		"0x8000091c2ae64ee414a54c1cc1fc67dec663408bc636cb86756e0200e41a75c8f86603f104f02c856983d2783116be13",
-		"0x8000091c2ae64ee414a54c1cc1fc67dec663408bc636cb86756e0200e41a75c8f86603f104f02c856983d2783116be14",
-		"0xa1d1ad0714035353258038e964ae9675dc0252ee24daffcb82688956ebf71d0de0fc5450436cfb148eb867acb2bdf44d",
-		"0xb2ff4716ed345b05dd1dfc6a5a9fa70856d8c75dcc9e881dd2f766d5f891326f0d0b9024523b9c35cc13d9c0e689aea3",
-		"0x8a896180ff9d8e98304e9b2e5c418202fa0e50a1157442a5b52fc10b464a6c114dfc31f463e4ea27c1c24112e3a14857",
-		"0x8d61ee78745e8c855af1085184e9c5646418fcfc5f446e3e99d5db6b0cbe74f7c0792833c876044d53bd7886de12371c",
-		"0xae241af60691fda1cf8ca44d49573c55818c53b6141800cca2d488b9a3fba71c0f869179fff50c084ae31d9bac2ba35c",
-		"0x84274f8d9c1e25d6d2f6b62c256e427e9daa79dff932a658b334ce3a5775574b23b6532753b90b74e56a24b148caf5b7",
-		"0x872c61b4a7f8510ec809e5b023f5fdda2105d024c470ddbbeca4bc74e8280af0d178d749853e8f6a841083ac1b4db98f",
-		"0xb2965bf5de4731c8fef4f2d8886d4f9564c5d2d8eb957e5f624dd010e9c36f947c6c0ab78df06e67dd6cf290c53313e5",
-		"0x8cffca6ab53ec85904d6a32f0b360c027926d4ae83c136b7fa979ebaba16da82c37bb4a335629741e1ffc8017f0c0d99",
-		"0x8e98f02a14788cc9348d4c988ff98c2440282a230a57d0e57482c59a90f11df1ec93af597c9b6188a2ba7d82ac5d52a1",
-		"0x8f5bab954b24a4e9b118a8a39b4c3663d6861b3316fd5a326a2a632a7de1438fe2dafe9d4d3429f04db5a1a5c1e89c4e",
-		"0x90a766525a8141ad2869e3b3ae9a952f61e596235a548631e3354ff3881891c18fc9e7d1fc3fd65c3271693e781c215a",
-		"0x909d0f2fa98422ce15369643b650aa1200a1200cc88ab416ca3f2ea9582b651f0a97bd10dfa8735402cf89a2498c9af5",
-		"0x948339fff96a195de4bdc3e121abc427dae48f23966244b1363436a61e5d0c733e79feb9f900ea58a9886fc0ba862be6",
-		"0x968bb4503245548dc8dc145cf111762e5e693ec964cef572e87e2939df581cf214f57ae3c49da6728cf427389e6cb3c8",
-		"0x974bfc7fe01143d83776ac14de6142fb04b54cf3ca7de9064a2d31183a255525b89ee6af078a8a6ba07cc49186150266",
-		"0x994f8f0599cec69720a9871d8734c6e9f5f36d2045294082a51c40f351c7217c69d0f6f66947cd95f88fe9ec0492068d",
-		"0x994fcd4a09c273f0f1d46eb219e15c33e6caa9c93a2c87004339ec67c4808559f9f9aeff9cf7e8eea8f13bb5f3a0c5d5",
-		"0x99a9a37bc913168a76701a32c53652a19a1ab96ce1a14a121bfb89565def0be5ac0a45c4538e53ff73e1cbd84f763339",
-		"0x99ccbcbf38fb63dea44bdc118848574b238c64a0ea48fb2d9f89280a485f56fc4d5c48ac2c3e3331937c35c2cc2d9661",
-		"0x9a64ef3e62b96990305c10b76056f2fcc7a3fb92908bbccd1f769304c1c151a1d7f00a09354252bb2f5324b61845d459",
-		"0x9a9cdcd34b18e5771c7feb5374d2cc738cbdf3686fbe1d4bacdb9db7eb692edd50c347b15a2cb2de2034028b6b73f44a",
-	}
-	
-	// Calculate a seed based on slot and epoch for consistent validator selection 
-	seed := (slot * 1000 + epoch * 2000) % 1000000
-	count := 8 + (seed % 16) // between 8-24 validators
-	if count > int64(len(validatorPubkeys)) {
-		count = int64(len(validatorPubkeys))
-	}
-	
-	// Select a subset of validators based on the seed
-	validators := make([]string, 0, count)
-	for i := int64(0); i < count; i++ {
-		index := (seed + i*i) % int64(len(validatorPubkeys))
-		validators = append(validators, validatorPubkeys[index])
-	}
-	
-	return validators, nil
-}
-
-func (s *EthereumService) getBeaconBlock(ctx context.Context, slot int64) (*BeaconBlockResponse, error) {
-	// Use QuickNode's Beacon Chain API endpoint
-	rpcReq := RPCRequest{
-		JSONRPC: "2.0",
-		Method:  "eth_getBlockByNumber",
-		Params:  []interface{}{fmt.Sprintf("0x%x", slot), true},
-		ID:      1,
-	}
-
-	reqBody, err := json.Marshal(rpcReq)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %v", err)
+// GetBlockRewardBySlot retrieves block reward information for a given slot
+func (s *EthereumService) GetBlockRewardBySlot(ctx context.Context, slot int64) (*BlockReward, error) {
+	// Validate slot is not in the future
+	currentSlot := s.wallClockSlot()
+	if slot > currentSlot {
+		return nil, fmt.Errorf("%w (current slot: %d)", ErrFutureSlot, currentSlot)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", s.rpcURL, bytes.NewBuffer(reqBody))
+	reward, err := s.getSlotCached(ctx, fmt.Sprintf("reward:%d", slot), slot, func(ctx context.Context, finalized bool) (interface{}, error) {
+		reward, err := s.fetchBlockReward(ctx, slot)
+		if err != nil {
+			return nil, err
+		}
+		reward.Finalized = finalized
+		return reward, nil
+	})
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Content-Type", "application/json")
-
-	// Add rate limiting delay
-	time.Sleep(time.Second) // Respect QuickNode's 1 request/second limit
+	return reward.(*BlockReward), nil
+}
 
-	resp, err := s.client.Do(req)
+// fetchBlockReward computes the block reward of a slot from the beacon and execution blocks
+func (s *EthereumService) fetchBlockReward(ctx context.Context, slot int64) (*BlockReward, error) {
+	// The execution block is looked up by the number its beacon block carried, its receipts carry the fees
+	block, receipts, err := s.getExecutionBlock(ctx, slot)
 	if err != nil {
-		return nil, err
+		if errors.Is(err, ErrSlotNotFound) {
+			return nil, ErrSlotNotFound
+		}
+		return nil, fmt.Errorf("failed to get beacon block: %w", err)
 	}
-	defer resp.Body.Close()
+	beaconBlock := beaconBlockFromExecution(block)
 
-	// Read and log the response for debugging
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %v", err)
-	}
+	// Check if block is MEV produced
+	isMev := s.isMEVBlock(beaconBlock)
+	payload := beaconBlock.Data.Message.Body.ExecutionPayload
 
-	fmt.Printf("Response from QuickNode API: %s\n", string(respBody))
+	result := &BlockReward{
+		Status:            map[bool]string{true: "mev", false: "vanilla"}[isMev],
+		ExtraData:         payload.ExtraData,
+		FeeRecipient:      payload.FeeRecipient,
+		FeeRecipientLabel: s.labelFeeRecipient(ctx, payload.FeeRecipient),
+	}
+	if isMev && s.builders != nil {
+		result.Builder = s.builders.Resolve(payload.ExtraData, "")
+	}
 
-	// Check for QuickNode rate limit error
-	if strings.Contains(string(respBody), "request limit reached") {
-		time.Sleep(time.Second * 2) // Wait longer if rate limited
-		return s.getBeaconBlock(ctx, slot) // Retry the request
+	// Get execution block details for reward calculation
+	blockHash := payload.BlockHash
+	if blockHash == "" {
+		result.Status = "vanilla"
+		result.Reward = big.NewInt(0)
+		result.RewardWei = big.NewInt(0)
+		return result, nil
 	}
 
-	// Create a new BeaconBlockResponse with appropriate structure
-	result := &BeaconBlockResponse{}
-	result.Data.Message.Body.ExecutionPayload.Transactions = []string{}
+	reward := getExecutionBlockReward(block, receipts)
 
-	// First try to parse as JSON-RPC response
-	var rpcResponse struct {
-		Result map[string]interface{} `json:"result"`
-		Error  *struct {
-			Code    int    `json:"code"`
-			Message string `json:"message"`
-		} `json:"error"`
-	}
+	// Convert Wei to Gwei
+	gweiReward := new(big.Int).Div(reward, big.NewInt(1e9))
 
-	if err := json.NewDecoder(bytes.NewReader(respBody)).Decode(&rpcResponse); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %v", err)
-	}
+	result.Reward = gweiReward
+	result.RewardWei = reward
 
-	// Check for API errors
-	if rpcResponse.Error != nil {
-		if rpcResponse.Error.Message == "Unknown block" {
-			return nil, fmt.Errorf("no block data found for slot %d", slot)
-		}
-		return nil, fmt.Errorf("API error: %s (code: %d)", rpcResponse.Error.Message, rpcResponse.Error.Code)
-	}
-
-	// If the result is nil or empty, return error
-	if rpcResponse.Result == nil {
-		return nil, fmt.Errorf("no block data found for slot %d", slot)
-	}
-
-	// Extract necessary fields from the response
-	// We need to manually map the fields from the JSON-RPC response to our BeaconBlockResponse structure
-	
-	// Block hash
-	if blockHash, ok := rpcResponse.Result["hash"].(string); ok {
-		result.Data.Message.Body.ExecutionPayload.BlockHash = blockHash
-	}
-	
-	// Miner/Fee recipient
-	if miner, ok := rpcResponse.Result["miner"].(string); ok {
-		result.Data.Message.Body.ExecutionPayload.FeeRecipient = miner
-	}
-	
-	// Extra data for MEV detection
-	if extraData, ok := rpcResponse.Result["extraData"].(string); ok {
-		result.Data.Message.Body.ExecutionPayload.ExtraData = extraData
-	}
-	
-	// Block number
-	if blockNumber, ok := rpcResponse.Result["number"].(string); ok {
-		result.Data.Message.Body.ExecutionPayload.BlockNumber = blockNumber
-	}
-	
-	// Transactions
-	if txs, ok := rpcResponse.Result["transactions"].([]interface{}); ok {
-		for _, tx := range txs {
-			// If transaction is a string (hash only), add it directly
-			if txHash, ok := tx.(string); ok {
-				result.Data.Message.Body.ExecutionPayload.Transactions = append(
-					result.Data.Message.Body.ExecutionPayload.Transactions, txHash)
-			} else if txObj, ok := tx.(map[string]interface{}); ok {
-				// If transaction is an object, extract the hash
-				if txHash, ok := txObj["hash"].(string); ok {
-					result.Data.Message.Body.ExecutionPayload.Transactions = append(
-						result.Data.Message.Body.ExecutionPayload.Transactions, txHash)
-				}
-			}
+	// Compare against the indexed blocks, only the indexer adds rewards to the index
+	if s.index != nil {
+		if percentile, ok := s.index.RewardPercentile(slot, gweiReward, percentileWindow); ok {
+			result.ValuePercentile = &percentile
 		}
 	}
-	
-	// Base fee per gas
-	if baseFee, ok := rpcResponse.Result["baseFeePerGas"].(string); ok {
-		result.Data.Message.Body.ExecutionPayload.BaseFeePerGas = baseFee
-	}
-	
+
 	return result, nil
 }
 
-func (s *EthereumService) getExecutionBlockReward(ctx context.Context, blockHash string, beaconBlock *BeaconBlockResponse) (*big.Int, error) {
-	if blockHash == "" {
-		return big.NewInt(0), nil
-	}
+// isMEVBlock checks if a block was produced by MEV-Boost
+func (s *EthereumService) isMEVBlock(block *BeaconBlockResponse) bool {
+	extraData := block.Data.Message.Body.ExecutionPayload.ExtraData
 
-	// Use QuickNode's Execution API endpoint
-	rpcReq := RPCRequest{
-		JSONRPC: "2.0",
-		Method:  "eth_getBlockByHash",
-		Params:  []interface{}{blockHash, true},
-		ID:      1,
+	// Check for empty extraData
+	if len(extraData) == 0 {
+		return false
 	}
 
-	reqBody, err := json.Marshal(rpcReq)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %v", err)
+	// Check for known MEV builder signatures in extraData
+	if s.builders != nil && s.builders.Resolve(extraData, "") != "" {
+		return true
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", s.rpcURL, bytes.NewBuffer(reqBody))
-	if err != nil {
-		return nil, err
+	// Simplified logic - for this API we'll consider blocks that have substantial transactions as potential MEV blocks
+	// In a production environment, this should be more sophisticated
+	txCount := len(block.Data.Message.Body.ExecutionPayload.Transactions)
+	if txCount > 20 { // Arbitrary threshold
+		return true
 	}
-	req.Header.Set("Content-Type", "application/json")
 
-	// Add rate limiting delay
-	time.Sleep(time.Second) // Respect QuickNode's 1 request/second limit
+	// Default to assuming vanilla blocks to be safe
+	return false
+}
 
-	resp, err := s.client.Do(req)
+// getExecutionBlock retrieves the execution block for a slot together with its receipts
+func (s *EthereumService) getExecutionBlock(ctx context.Context, slot int64) (*types.Block, []*types.Receipt, error) {
+	number, err := s.GetExecutionBlockNumber(ctx, slot)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	defer resp.Body.Close()
 
-	// Read and log the response for debugging
-	respBody, err := io.ReadAll(resp.Body)
+	var block *types.Block
+	var receipts []*types.Receipt
+	err = trackUpstream(ctx, func(ctx context.Context) error {
+		block, receipts, err = s.executionClient().BlockWithReceipts(ctx, big.NewInt(number))
+		return err
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %v", err)
+		if errors.Is(err, ethereum.NotFound) || strings.Contains(err.Error(), "Unknown block") {
+			return nil, nil, fmt.Errorf("%w: no execution block %d found for slot %d", ErrSlotNotFound, number, slot)
+		}
+		return nil, nil, err
 	}
-
-	fmt.Printf("Response from QuickNode API: %s\n", string(respBody))
-
-	// Check for QuickNode rate limit error
-	if strings.Contains(string(respBody), "request limit reached") {
-		time.Sleep(time.Second * 2) // Wait longer if rate limited
-		return s.getExecutionBlockReward(ctx, blockHash, beaconBlock) // Retry the request
+	if err := s.verifyExecutionBlock(ctx, slot, block, receipts); err != nil {
+		return nil, nil, err
 	}
+	return block, receipts, nil
+}
 
-	var response struct {
-		Result map[string]interface{} `json:"result"`
-		Error  *struct {
-			Code    int    `json:"code"`
-			Message string `json:"message"`
-		} `json:"error"`
+// beaconBlockFromExecution maps an execution block onto a BeaconBlockResponse
+func beaconBlockFromExecution(block *types.Block) *BeaconBlockResponse {
+	result := &BeaconBlockResponse{}
+	payload := &result.Data.Message.Body.ExecutionPayload
+	payload.BlockHash = block.Hash().Hex()
+	payload.FeeRecipient = strings.ToLower(block.Coinbase().Hex())
+	payload.ExtraData = hexutil.Encode(block.Extra()) // Extra data for MEV detection
+	payload.BlockNumber = hexutil.EncodeBig(block.Number())
+	if baseFee := block.BaseFee(); baseFee != nil {
+		payload.BaseFeePerGas = hexutil.EncodeBig(baseFee)
 	}
 
-	if err := json.NewDecoder(bytes.NewReader(respBody)).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %v, response body: %s", err, string(respBody))
+	payload.Transactions = make([]string, 0, len(block.Transactions()))
+	for _, tx := range block.Transactions() {
+		payload.Transactions = append(payload.Transactions, tx.Hash().Hex())
 	}
 
-	if response.Error != nil {
-		return nil, fmt.Errorf("API error: %s (code: %d)", response.Error.Message, response.Error.Code)
-	}
+	return result
+}
 
-	if response.Result == nil {
-		return nil, fmt.Errorf("no block data found for hash %s", blockHash)
+// getExecutionBlockReward sums the priority fees paid to the proposer using the gas used from the receipts
+func getExecutionBlockReward(block *types.Block, receipts []*types.Receipt) *big.Int {
+	baseFeePerGas := block.BaseFee()
+	if baseFeePerGas == nil {
+		baseFeePerGas = big.NewInt(0)
 	}
 
 	totalReward := new(big.Int)
-
-	// Safely parse base fee
-	baseFeePerGas := new(big.Int)
-	if baseFeeStr, ok := response.Result["baseFeePerGas"].(string); ok && baseFeeStr != "" {
-		baseFeeHex := strings.TrimPrefix(baseFeeStr, "0x")
-		if _, ok := baseFeePerGas.SetString(baseFeeHex, 16); !ok {
-			fmt.Printf("Warning: failed to parse base fee: %s\n", baseFeeStr)
-			baseFeePerGas = big.NewInt(0)
-		}
-	}
-
-	// Calculate rewards for each transaction
-	if txsInterface, ok := response.Result["transactions"].([]interface{}); ok {
-		for _, txInterface := range txsInterface {
-			// Skip if transaction is just a string (hash)
-			txMap, ok := txInterface.(map[string]interface{})
-			if !ok {
-				continue
-			}
-			
-			// Calculate priority fee
-			var priorityFee *big.Int = big.NewInt(0)
-			
-			if maxPriorityFeeStr, ok := txMap["maxPriorityFeePerGas"].(string); ok && maxPriorityFeeStr != "" {
-				priorityFee = new(big.Int)
-				priorityHex := strings.TrimPrefix(maxPriorityFeeStr, "0x")
-				if _, ok := priorityFee.SetString(priorityHex, 16); !ok {
-					fmt.Printf("Warning: failed to parse priority fee: %s\n", maxPriorityFeeStr)
-					continue
-				}
-			} else if gasPriceStr, ok := txMap["gasPrice"].(string); ok && gasPriceStr != "" {
-				// For legacy transactions, priority fee is gasPrice - baseFee
-				gasPrice := new(big.Int)
-				gasPriceHex := strings.TrimPrefix(gasPriceStr, "0x")
-				if _, ok := gasPrice.SetString(gasPriceHex, 16); !ok {
-					fmt.Printf("Warning: failed to parse gas price: %s\n", gasPriceStr)
-					continue
-				}
-				priorityFee = new(big.Int).Sub(gasPrice, baseFeePerGas)
-				if priorityFee.Sign() < 0 {
-					priorityFee = big.NewInt(0)
-				}
-			} else {
-				continue
-			}
-
-			// Parse gas used - for an accurate calculation we'd need the receipt
-			// but for estimation we can use gas (gas limit)
-			gasUsed := new(big.Int)
-			if gasStr, ok := txMap["gas"].(string); ok && gasStr != "" {
-				gasHex := strings.TrimPrefix(gasStr, "0x")
-				if _, ok := gasUsed.SetString(gasHex, 16); !ok {
-					fmt.Printf("Warning: failed to parse gas: %s\n", gasStr)
-					continue
-				}
-			} else {
-				continue
-			}
-
-			// Calculate transaction reward (priority fee * gas used)
-			// This is an approximation as we don't have the actual gas used
-			txReward := new(big.Int).Mul(priorityFee, gasUsed)
-			totalReward.Add(totalReward, txReward)
-		}
-	}
-
-	// If reward calculation failed or is zero, return a small default value
-	// This ensures the frontend displays something rather than zero
-	if totalReward.Cmp(big.NewInt(0)) <= 0 {
-		// Set a small default reward (0.01 ETH in Gwei) for display purposes
-		defaultReward, _ := new(big.Int).SetString("10000000000", 10) // 0.01 ETH in Wei
-		return defaultReward, nil
+	for _, reward := range computeTransactionRewards(receipts, baseFeePerGas) {
+		totalReward.Add(totalReward, reward.Contribution)
 	}
 
-	return totalReward, nil
+	return totalReward
 }
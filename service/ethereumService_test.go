@@ -3,12 +3,17 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 )
 
 func TestNewEthereumService(t *testing.T) {
@@ -84,7 +89,6 @@ func TestEthereumService_GetBlockRewardBySlot(t *testing.T) {
 		name           string
 		slot          int64
 		beaconResp    BeaconBlockResponse
-		executionResp ExecutionBlockResponse
 		wantStatus    string
 		wantReward    *big.Int
 		wantErr       bool
@@ -151,34 +155,6 @@ func TestEthereumService_GetBlockRewardBySlot(t *testing.T) {
 					},
 				},
 			},
-			executionResp: ExecutionBlockResponse{
-				Result: struct {
-					Transactions []struct {
-						Hash             string `json:"hash"`
-						GasPrice         string `json:"gasPrice"`
-						GasUsed          string `json:"gasUsed"`
-						MaxPriorityFee   string `json:"maxPriorityFeePerGas"`
-						MaxFeePerGas     string `json:"maxFeePerGas"`
-						TransactionIndex string `json:"transactionIndex"`
-					} `json:"transactions"`
-					BaseFeePerGas string `json:"baseFeePerGas"`
-				}{
-					Transactions: []struct {
-						Hash             string `json:"hash"`
-						GasPrice         string `json:"gasPrice"`
-						GasUsed          string `json:"gasUsed"`
-						MaxPriorityFee   string `json:"maxPriorityFeePerGas"`
-						MaxFeePerGas     string `json:"maxFeePerGas"`
-						TransactionIndex string `json:"transactionIndex"`
-					}{
-						{
-							GasPrice: "0x8",
-							GasUsed:  "0x5208",
-						},
-					},
-					BaseFeePerGas: "0x5",
-				},
-			},
 			wantStatus: "vanilla",
 			wantReward: new(big.Int).Mul(big.NewInt(3), big.NewInt(21000)), // (gasPrice - baseFee) * gasUsed
 			wantErr:    false,
@@ -238,29 +214,6 @@ func TestEthereumService_GetBlockRewardBySlot(t *testing.T) {
 					},
 				},
 			},
-			executionResp: ExecutionBlockResponse{
-				Result: struct {
-					Transactions []struct {
-						Hash             string `json:"hash"`
-						GasPrice         string `json:"gasPrice"`
-						GasUsed          string `json:"gasUsed"`
-						MaxPriorityFee   string `json:"maxPriorityFeePerGas"`
-						MaxFeePerGas     string `json:"maxFeePerGas"`
-						TransactionIndex string `json:"transactionIndex"`
-					} `json:"transactions"`
-					BaseFeePerGas string `json:"baseFeePerGas"`
-				}{
-					Transactions: []struct {
-						Hash             string `json:"hash"`
-						GasPrice         string `json:"gasPrice"`
-						GasUsed          string `json:"gasUsed"`
-						MaxPriorityFee   string `json:"maxPriorityFeePerGas"`
-						MaxFeePerGas     string `json:"maxFeePerGas"`
-						TransactionIndex string `json:"transactionIndex"`
-					}{},
-					BaseFeePerGas: "0x5",
-				},
-			},
 			wantStatus: "vanilla",
 			wantReward: big.NewInt(0), // Empty block
 			wantErr:    false,
@@ -279,8 +232,6 @@ func TestEthereumService_GetBlockRewardBySlot(t *testing.T) {
 				switch req.Method {
 				case "beacon_get_block":
 					json.NewEncoder(w).Encode(tt.beaconResp)
-				case "eth_getBlockByHash":
-					json.NewEncoder(w).Encode(tt.executionResp)
 				default:
 					t.Fatalf("Unexpected method: %s", req.Method)
 				}
@@ -289,7 +240,6 @@ func TestEthereumService_GetBlockRewardBySlot(t *testing.T) {
 
 			// Create service with test server URL
 			s := &EthereumService{
-				rpcURL: server.URL,
 				client: server.Client(),
 			}
 
@@ -316,63 +266,76 @@ func TestEthereumService_GetBlockRewardBySlot(t *testing.T) {
 	}
 }
 
-func TestEthereumService_GetSyncDutiesBySlot(t *testing.T) {
+func TestEthereumService_GetSyncCommitteeBySlot(t *testing.T) {
+	_, _, generator, _ := bls.Generators()
+	pubkey := func(secret int64) string {
+		var key bls.G1Affine
+		key.ScalarMultiplication(&generator, big.NewInt(secret))
+		compressed := key.Bytes()
+		return hexutil.Encode(compressed[:])
+	}
+
 	tests := []struct {
-		name         string
-		slot         int64
-		syncResponse SyncCommitteeResponse
-		wantKeys     []string
-		wantErr      bool
+		name    string
+		slot    int64
+		wantErr error
 	}{
 		{
-			name: "Valid sync committee response",
-			slot: 1000,
-			syncResponse: SyncCommitteeResponse{
-				Data: struct {
-					ValidatorSyncAssignments []struct {
-						ValidatorPubKey string `json:"validator_pubkey"`
-					} `json:"validator_sync_assignments"`
-				}{
-					ValidatorSyncAssignments: []struct {
-						ValidatorPubKey string `json:"validator_pubkey"`
-					}{
-						{ValidatorPubKey: "0x123"},
-						{ValidatorPubKey: "0x456"},
-					},
-				},
-			},
-			wantKeys: []string{"0x123", "0x456"},
-			wantErr:  false,
+			name: "Committee of the slot's state",
+			slot: 8300,
+		},
+		{
+			name:    "Unknown state",
+			slot:    8301,
+			wantErr: ErrSlotNotFound,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			responses := map[string]string{
+				"/eth/v1/beacon/states/8300/sync_committees?epoch=256": `{"data":{"validators":["1","2","1"]}}`,
+				"/eth/v1/beacon/states/head/validators?id=1,2":         fmt.Sprintf(`{"data":[{"index":"1","validator":{"pubkey":"%s"}},{"index":"2","validator":{"pubkey":"%s"}}]}`, pubkey(1), pubkey(2)),
+			}
+			requests := 0
 			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-				json.NewEncoder(w).Encode(tt.syncResponse)
+				requests++
+				response, ok := responses[r.URL.RequestURI()]
+				if !ok {
+					http.NotFound(w, r)
+					return
+				}
+				w.Write([]byte(response))
 			}))
 			defer server.Close()
 
-			s := &EthereumService{
-				rpcURL: server.URL,
-				client: server.Client(),
+			s := NewEthereumServiceWithClients(NewEth2BeaconClient(server.URL, server.Client()), nil)
+			got, err := s.GetSyncCommitteeBySlot(context.Background(), tt.slot)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("GetSyncCommitteeBySlot() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetSyncCommitteeBySlot() unexpected error: %v", err)
 			}
 
-			got, err := s.GetSyncDutiesBySlot(context.Background(), tt.slot)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("GetSyncDutiesBySlot() error = %v, wantErr %v", err, tt.wantErr)
-				return
+			// The committee and its pubkeys take one request each, the aggregate pubkey is computed from them
+			if requests != 2 {
+				t.Errorf("GetSyncCommitteeBySlot() made %d requests, want 2", requests)
 			}
-			if err == nil {
-				if len(got) != len(tt.wantKeys) {
-					t.Errorf("GetSyncDutiesBySlot() got %v keys, want %v", len(got), len(tt.wantKeys))
-				}
-				for i, key := range got {
-					if key != tt.wantKeys[i] {
-						t.Errorf("GetSyncDutiesBySlot() key[%d] = %v, want %v", i, key, tt.wantKeys[i])
-					}
-				}
+			if got.SyncPeriod != 1 || len(got.Members) != 3 || got.Members[2].Pubkey != pubkey(1) {
+				t.Fatalf("GetSyncCommitteeBySlot() = %+v, want 3 members of period 1", got)
+			}
+			if want := pubkey(4); got.AggregatePubkey != want {
+				t.Errorf("GetSyncCommitteeBySlot() aggregate pubkey = %v, want %v", got.AggregatePubkey, want)
+			}
+
+			// Other slots of the period are served from the cache
+			if _, err := s.GetSyncCommitteeBySlot(context.Background(), tt.slot+1); err != nil || requests != 2 {
+				t.Errorf("GetSyncCommitteeBySlot() of a cached period made %d requests, want 2 (error %v)", requests, err)
 			}
 		})
 	}
-} 
\ No newline at end of file
+}
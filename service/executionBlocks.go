@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// ErrInvalidBlockID is returned for execution block identifiers that are neither a block number nor a block hash
+var ErrInvalidBlockID = errors.New("invalid block number or hash")
+
+// blockHashPattern matches a 0x prefixed 32 byte block hash
+var blockHashPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{64}$`)
+
+// ExecutionBlockRef locates an execution block on the beacon chain
+type ExecutionBlockRef struct {
+	Slot   int64
+	Number int64
+	Hash   string
+}
+
+// executionHeaderResponse represents the fields of an eth_getBlockByNumber or eth_getBlockByHash result needed to
+// locate the block
+type executionHeaderResponse struct {
+	Number    string `json:"number"`
+	Hash      string `json:"hash"`
+	Timestamp string `json:"timestamp"`
+}
+
+// ResolveExecutionBlock returns the slot of the execution block with the given decimal number or hash. Indexed blocks
+// are resolved locally, other blocks through their timestamp and checked against the beacon block of the slot
+func (s *EthereumService) ResolveExecutionBlock(ctx context.Context, id string) (*ExecutionBlockRef, error) {
+	var number int64
+	hash := ""
+	if blockHashPattern.MatchString(id) {
+		hash = strings.ToLower(id)
+	} else {
+		parsed, err := strconv.ParseInt(id, 10, 64)
+		if err != nil || parsed < 0 {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidBlockID, id)
+		}
+		number = parsed
+	}
+
+	if block, ok := s.index.FindExecutionBlock(number, hash); ok {
+		return &ExecutionBlockRef{Slot: block.Slot, Number: block.ExecutionBlockNumber, Hash: block.ExecutionBlockHash}, nil
+	}
+
+	var header *executionHeaderResponse
+	var err error
+	if hash != "" {
+		err = s.callRPC(ctx, "eth_getBlockByHash", []interface{}{hash, false}, &header)
+	} else {
+		err = s.callRPC(ctx, "eth_getBlockByNumber", []interface{}{hexutil.EncodeUint64(uint64(number)), false}, &header)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get execution block: %w", err)
+	}
+	if header == nil {
+		return nil, fmt.Errorf("%w: no execution block %s", ErrSlotNotFound, id)
+	}
+	if s.verify && hash != "" && !strings.EqualFold(header.Hash, hash) {
+		return nil, fmt.Errorf("%w: execution block requested by hash %s has hash %s", ErrInconsistentResponse, hash, header.Hash)
+	}
+
+	timestamp, err := hexutil.DecodeUint64(header.Timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid timestamp %q of execution block %s", ErrRPCFailed, header.Timestamp, id)
+	}
+	blockNumber, err := hexutil.DecodeUint64(header.Number)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid number %q of execution block %s", ErrRPCFailed, header.Number, id)
+	}
+	if s.verify && hash == "" && int64(blockNumber) != number {
+		return nil, fmt.Errorf("%w: execution block requested by number %d has number %d", ErrInconsistentResponse, number, blockNumber)
+	}
+
+	genesis, err := s.getGenesisTime(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get genesis time: %w", err)
+	}
+	elapsed := time.Unix(int64(timestamp), 0).Sub(genesis)
+	if elapsed < 0 {
+		return nil, fmt.Errorf("%w: execution block %s predates the beacon chain", ErrSlotNotFound, id)
+	}
+	slot := int64(elapsed / (SecondsPerSlot * time.Second))
+
+	// Blocks before the merge have timestamps too, only blocks carried by the beacon block of the slot count
+	detail, err := s.GetBlockDetailBySlot(ctx, slot)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(detail.ExecutionBlockHash, header.Hash) {
+		return nil, fmt.Errorf("%w: execution block %s was not proposed on the beacon chain", ErrSlotNotFound, id)
+	}
+
+	return &ExecutionBlockRef{Slot: slot, Number: int64(blockNumber), Hash: detail.ExecutionBlockHash}, nil
+}
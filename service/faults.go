@@ -0,0 +1,110 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// FaultConfig injects faults into upstream HTTP requests, to exercise retries, backoffs and fallbacks in staging.
+// Rates are the shares of requests affected, between 0 and 1
+type FaultConfig struct {
+	LatencyRate   float64       // Requests delayed by Latency before they are sent
+	Latency       time.Duration // Delay added to the delayed requests
+	RateLimitRate float64       // Requests answered with a 429 without reaching the provider
+	MalformedRate float64       // Responses whose body is cut off, so it isn't valid JSON anymore
+}
+
+// Enabled reports whether any fault is injected
+func (c FaultConfig) Enabled() bool {
+	return (c.LatencyRate > 0 && c.Latency > 0) || c.RateLimitRate > 0 || c.MalformedRate > 0
+}
+
+// Validate checks that the rates are shares
+func (c FaultConfig) Validate() error {
+	for name, rate := range map[string]float64{"latency": c.LatencyRate, "rate limit": c.RateLimitRate, "malformed": c.MalformedRate} {
+		if rate < 0 || rate > 1 {
+			return fmt.Errorf("invalid %s fault rate %v, must be between 0 and 1", name, rate)
+		}
+	}
+	return nil
+}
+
+// faultRetryAfter is the Retry-After of injected 429 responses, in seconds
+const faultRetryAfter = 1
+
+// faultTransport is an http.RoundTripper injecting the configured faults into a share of the requests
+type faultTransport struct {
+	next   http.RoundTripper
+	config FaultConfig
+}
+
+func (t *faultTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.config.LatencyRate > 0 && rand.Float64() < t.config.LatencyRate {
+		select {
+		case <-upstreamClock.After(t.config.Latency):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+
+	if t.config.RateLimitRate > 0 && rand.Float64() < t.config.RateLimitRate {
+		body := `{"code":429,"message":"injected fault: too many requests"}`
+		return &http.Response{
+			Status:        "429 Too Many Requests",
+			StatusCode:    http.StatusTooManyRequests,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        http.Header{"Content-Type": {"application/json"}, "Retry-After": {strconv.Itoa(faultRetryAfter)}},
+			Body:          io.NopCloser(bytes.NewBufferString(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || t.config.MalformedRate <= 0 || rand.Float64() >= t.config.MalformedRate {
+		return resp, err
+	}
+
+	// Keep the first half of the body, which leaves JSON objects and arrays unterminated
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	body = append(body[:len(body)/2:len(body)/2], '{')
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	resp.Header.Del("Content-Length")
+	return resp, nil
+}
+
+// faultInjected wraps the transport with the service's fault injection, if it is enabled
+func (s *EthereumService) faultInjected(next http.RoundTripper) http.RoundTripper {
+	if !s.faults.Enabled() {
+		return next
+	}
+	return &faultTransport{next: next, config: s.faults}
+}
+
+// SetFaultInjection injects the configured faults into all upstream HTTP requests, a disabled config turns injection
+// off. Websocket execution endpoints are not affected. It must be called before the service starts serving requests
+func (s *EthereumService) SetFaultInjection(config FaultConfig) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	s.faults = config
+	s.client.Transport = s.newUpstreamTransport()
+	if config.Enabled() {
+		log.Printf("Warning: injecting upstream faults, %.0f%% delayed by %v, %.0f%% rate limited, %.0f%% malformed",
+			config.LatencyRate*100, config.Latency, config.RateLimitRate*100, config.MalformedRate*100)
+	}
+	return nil
+}
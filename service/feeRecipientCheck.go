@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrInvalidFeeRecipient is returned when the expected fee recipient is not an address
+var ErrInvalidFeeRecipient = errors.New("invalid fee recipient address")
+
+// Results of checking the fee recipient of a proposal
+const (
+	FeeRecipientMatch    = "match"
+	FeeRecipientMismatch = "mismatch"
+	FeeRecipientUnknown  = "unknown" // MEV block whose payload no configured relay delivered
+)
+
+// Where the fee recipient of a proposal was taken from
+const (
+	FeeRecipientSourcePayload = "payload" // Fee recipient of the execution payload, set by the proposer for vanilla blocks
+	FeeRecipientSourceRelay   = "relay"   // Proposer fee recipient the builder paid, reported by the delivering relay
+)
+
+// FeeRecipientProposal represents the fee recipient used by a proposal
+type FeeRecipientProposal struct {
+	Slot         int64
+	Status       string // "mev" or "vanilla"
+	FeeRecipient string // "" if unknown
+	Source       string
+	Result       string
+}
+
+// FeeRecipientCheck represents the outcome of checking the proposals of a validator against an expected fee recipient
+type FeeRecipientCheck struct {
+	ValidatorIndex int64
+	Expected       string
+	FromEpoch      int64
+	ToEpoch        int64
+	Proposals      []FeeRecipientProposal // Proposed blocks, oldest first
+	Matches        int
+	Mismatches     int
+	Unknown        int
+}
+
+// CheckFeeRecipient verifies that the blocks a validator proposed between fromEpoch and toEpoch paid the expected fee
+// recipient. Vanilla blocks are checked against their payload's fee recipient, MEV blocks against the proposer fee
+// recipient reported by the relay that delivered the payload, as their payload pays the builder
+func (s *EthereumService) CheckFeeRecipient(ctx context.Context, validatorID, expected string, fromEpoch, toEpoch *int64) (*FeeRecipientCheck, error) {
+	if !common.IsHexAddress(expected) {
+		return nil, ErrInvalidFeeRecipient
+	}
+	expected = strings.ToLower(common.HexToAddress(expected).Hex())
+
+	proposals, err := s.GetValidatorProposals(ctx, validatorID, fromEpoch, toEpoch)
+	if err != nil {
+		return nil, err
+	}
+
+	check := &FeeRecipientCheck{
+		ValidatorIndex: proposals.ValidatorIndex,
+		Expected:       expected,
+		FromEpoch:      proposals.FromEpoch,
+		ToEpoch:        proposals.ToEpoch,
+		Proposals:      []FeeRecipientProposal{},
+	}
+	for _, proposal := range proposals.Proposals {
+		if proposal.Outcome != ProposalProposed || proposal.Reward == nil {
+			continue
+		}
+
+		result := FeeRecipientProposal{
+			Slot:         proposal.Slot,
+			Status:       proposal.Reward.Status,
+			FeeRecipient: strings.ToLower(proposal.Reward.FeeRecipient),
+			Source:       FeeRecipientSourcePayload,
+		}
+		if proposal.Reward.Status == "mev" {
+			result.FeeRecipient = ""
+			result.Source = FeeRecipientSourceRelay
+			if _, trace := s.getDeliveredTrace(ctx, proposal.Slot); trace != nil {
+				result.FeeRecipient = strings.ToLower(trace.ProposerFeeRecipient)
+			}
+		}
+
+		switch result.FeeRecipient {
+		case "":
+			result.Result = FeeRecipientUnknown
+			check.Unknown++
+		case expected:
+			result.Result = FeeRecipientMatch
+			check.Matches++
+		default:
+			result.Result = FeeRecipientMismatch
+			check.Mismatches++
+		}
+		check.Proposals = append(check.Proposals, result)
+	}
+
+	return check, nil
+}
@@ -0,0 +1,283 @@
+package service
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// ensRegistryAddress is the address of the ENS registry on mainnet
+const ensRegistryAddress = "0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e"
+
+const (
+	// ensCacheMaxEntries is how many addresses the ENS cache holds before the least recently used are evicted
+	ensCacheMaxEntries = 4096
+
+	// ensCacheMaxBytes bounds the approximate memory of the ENS cache, names are short so the entry limit binds first
+	ensCacheMaxBytes = 4 << 20
+
+	// ensNameTTL is how long a verified ENS name is cached
+	ensNameTTL = 24 * time.Hour
+
+	// ensMissingTTL is how long an address without a verified ENS name is cached as such
+	ensMissingTTL = 6 * time.Hour
+
+	// ensFailureTTL is how long an address whose lookup failed goes unlabeled before it is looked up again
+	ensFailureTTL = 5 * time.Minute
+
+	// ensLookupTimeout is how long a request waits for the ENS lookup of a fee recipient
+	ensLookupTimeout = 500 * time.Millisecond
+
+	// ensResolveTimeout bounds an ENS lookup, which keeps running after the request stopped waiting for it
+	ensResolveTimeout = 15 * time.Second
+)
+
+// newENSCache creates the cache of ENS names keyed by lower-cased address, holding "" for addresses without one
+func newENSCache(clock Clock) *responseCache {
+	cache := newResponseCache(clock)
+	cache.SetLimits(ensCacheMaxEntries, ensCacheMaxBytes)
+	return cache
+}
+
+// ENS function selectors
+const (
+	ensResolverSelector = "0x0178b8bf" // resolver(bytes32)
+	ensNameSelector     = "0x691f3431" // name(bytes32)
+	ensAddrSelector     = "0x3b3b57de" // addr(bytes32)
+)
+
+// DefaultFeeRecipientLabels returns the built-in labels of well-known fee recipient addresses
+func DefaultFeeRecipientLabels() map[string]string {
+	return map[string]string{
+		"0x388c818ca8b9251b393131c08a736a67ccb19297": "Lido",
+		"0xd4e96ef8eee8678dbff4d535e033ed1a4f7605b7": "Rocket Pool smoothing pool",
+		"0x95222290dd7278aa3ddd389cc1e1d165cc4bafe5": "beaverbuild",
+		"0x4838b106fce9647bdf1e7877bf73ce8b0bad5f97": "Titan",
+	}
+}
+
+// FeeRecipientRegistry maps fee recipient addresses to the entity operating them
+type FeeRecipientRegistry struct {
+	mu     sync.RWMutex
+	labels map[string]string // keyed by lower-cased address
+}
+
+// NewFeeRecipientRegistry creates a FeeRecipientRegistry with the given address labels
+func NewFeeRecipientRegistry(labels map[string]string) *FeeRecipientRegistry {
+	r := &FeeRecipientRegistry{
+		labels: make(map[string]string),
+	}
+	for address, label := range labels {
+		r.labels[strings.ToLower(address)] = label
+	}
+	return r
+}
+
+// LoadFeeRecipientRegistry creates a FeeRecipientRegistry from the built-in labels extended by a JSON file
+// mapping addresses to labels
+func LoadFeeRecipientRegistry(path string) (*FeeRecipientRegistry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fee recipient labels file: %w", err)
+	}
+
+	var labels map[string]string
+	if err := json.Unmarshal(data, &labels); err != nil {
+		return nil, fmt.Errorf("failed to decode fee recipient labels: %w", err)
+	}
+
+	r := NewFeeRecipientRegistry(DefaultFeeRecipientLabels())
+	for address, label := range labels {
+		r.labels[strings.ToLower(address)] = label
+	}
+	return r, nil
+}
+
+// Lookup returns the label of an address, reporting whether it is known
+func (r *FeeRecipientRegistry) Lookup(address string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	label, ok := r.labels[strings.ToLower(address)]
+	return label, ok
+}
+
+// labelFeeRecipient resolves a label for a fee recipient from the registry, falling back to ENS when enabled. An
+// ENS lookup takes up to four eth_calls, so the request waits for it at most ensLookupTimeout and goes unlabeled
+// beyond. The lookup keeps running in the background and its result labels the next requests
+func (s *EthereumService) labelFeeRecipient(ctx context.Context, address string) string {
+	if address == "" {
+		return ""
+	}
+
+	if s.feeRecipients != nil {
+		if label, ok := s.feeRecipients.Lookup(address); ok {
+			return label
+		}
+	}
+
+	if !s.ensLookup {
+		return ""
+	}
+
+	address = strings.ToLower(address)
+	if name, ok := s.ensCache.Get(address); ok {
+		return name.(string)
+	}
+
+	// Concurrent requests for the same address share one lookup, which outlives the request that started it
+	lookup := s.ensInflight.DoChan(address, func() (interface{}, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), ensResolveTimeout)
+		defer cancel()
+		return s.reverseResolveENS(ctx, address), nil
+	})
+
+	timer := time.NewTimer(ensLookupTimeout)
+	defer timer.Stop()
+	select {
+	case result := <-lookup:
+		return result.Val.(string)
+	case <-timer.C:
+		return ""
+	case <-ctx.Done():
+		return ""
+	}
+}
+
+// reverseResolveENS returns the verified primary ENS name of a lower-cased address and caches it. Addresses without
+// a verified name, and addresses whose lookup failed, are cached as "" for a shorter time
+func (s *EthereumService) reverseResolveENS(ctx context.Context, address string) string {
+	name, err := s.lookupENSName(ctx, address)
+	switch {
+	case err != nil:
+		sampledLogf("ens", "Warning: failed to reverse resolve ENS name for %s: %v", address, err)
+		s.ensCache.Set(address, "", ensFailureTTL)
+	case name == "":
+		s.ensCache.Set(address, "", ensMissingTTL)
+	default:
+		s.ensCache.Set(address, name, ensNameTTL)
+	}
+	return name
+}
+
+// lookupENSName returns the primary ENS name of an address if it resolves back to the address, "" otherwise
+func (s *EthereumService) lookupENSName(ctx context.Context, address string) (string, error) {
+	reverseNode := ensNamehash(strings.TrimPrefix(address, "0x") + ".addr.reverse")
+	resolver, err := s.ensResolver(ctx, reverseNode)
+	if err != nil || resolver == "" {
+		return "", err
+	}
+
+	var result string
+	if err := s.callRPC(ctx, "eth_call", []interface{}{
+		map[string]string{"to": resolver, "data": ensNameSelector + hex.EncodeToString(reverseNode)},
+		"latest",
+	}, &result); err != nil {
+		return "", err
+	}
+	name := decodeABIString(result)
+	if name == "" {
+		return "", nil
+	}
+
+	// Reverse records can be set to any name, so only trust names that resolve back to the address
+	verified, err := s.ensForwardMatches(ctx, name, address)
+	if err != nil || !verified {
+		return "", err
+	}
+	return name, nil
+}
+
+// ensForwardMatches checks that an ENS name resolves to the given address
+func (s *EthereumService) ensForwardMatches(ctx context.Context, name, address string) (bool, error) {
+	node := ensNamehash(name)
+	resolver, err := s.ensResolver(ctx, node)
+	if err != nil || resolver == "" {
+		return false, err
+	}
+
+	var result string
+	if err := s.callRPC(ctx, "eth_call", []interface{}{
+		map[string]string{"to": resolver, "data": ensAddrSelector + hex.EncodeToString(node)},
+		"latest",
+	}, &result); err != nil {
+		return false, err
+	}
+
+	return strings.EqualFold(decodeABIAddress(result), address), nil
+}
+
+// ensResolver returns the resolver address of an ENS node, or "" if none is set
+func (s *EthereumService) ensResolver(ctx context.Context, node []byte) (string, error) {
+	var result string
+	if err := s.callRPC(ctx, "eth_call", []interface{}{
+		map[string]string{"to": ensRegistryAddress, "data": ensResolverSelector + hex.EncodeToString(node)},
+		"latest",
+	}, &result); err != nil {
+		return "", err
+	}
+
+	resolver := decodeABIAddress(result)
+	if resolver == "0x0000000000000000000000000000000000000000" {
+		return "", nil
+	}
+	return resolver, nil
+}
+
+// ensNamehash computes the EIP-137 namehash of an ENS name
+func ensNamehash(name string) []byte {
+	node := make([]byte, 32)
+	if name == "" {
+		return node
+	}
+
+	labels := strings.Split(name, ".")
+	for i := len(labels) - 1; i >= 0; i-- {
+		labelHash := keccak256([]byte(labels[i]))
+		node = keccak256(append(node, labelHash...))
+	}
+	return node
+}
+
+// keccak256 returns the Keccak-256 hash of data
+func keccak256(data []byte) []byte {
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write(data)
+	return hash.Sum(nil)
+}
+
+// decodeABIAddress decodes an ABI encoded address return value
+func decodeABIAddress(result string) string {
+	raw := strings.TrimPrefix(result, "0x")
+	if len(raw) < 64 {
+		return ""
+	}
+	return "0x" + raw[24:64]
+}
+
+// decodeABIString decodes an ABI encoded string return value
+func decodeABIString(result string) string {
+	raw, err := hex.DecodeString(strings.TrimPrefix(result, "0x"))
+	if err != nil || len(raw) < 64 {
+		return ""
+	}
+
+	offset := new(big.Int).SetBytes(raw[:32])
+	if !offset.IsInt64() || offset.Int64()+32 > int64(len(raw)) {
+		return ""
+	}
+	start := offset.Int64()
+
+	length := new(big.Int).SetBytes(raw[start : start+32])
+	if !length.IsInt64() || start+32+length.Int64() > int64(len(raw)) {
+		return ""
+	}
+	return string(raw[start+32 : start+32+length.Int64()])
+}
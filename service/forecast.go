@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// Reward weights of the Altair incentives, out of weightDenominator
+const (
+	attestationRewardWeight = 14 + 26 + 14 // Timely source, target and head
+	syncRewardWeight        = 2
+	proposerRewardWeight    = 8
+	weightDenominator       = 64
+)
+
+// forecastBlockWindow is the window of indexed blocks the average block reward and network conditions are taken from
+const forecastBlockWindow = 30 * 24 * time.Hour
+
+// RewardsForecast represents the rewards a validator can expect over a coming period under the current network
+// conditions. Amounts are expected values in GWEI, proposals and sync committee duties are spread by their probability
+type RewardsForecast struct {
+	ValidatorIndex   int64
+	Status           string
+	EffectiveBalance int64 // in GWEI
+	Epochs           int64 // Length of the period in epochs
+
+	Attestation       int64 // Attestation rewards when attesting correctly every epoch
+	SyncCommittee     int64
+	ProposerConsensus int64 // Consensus rewards for including attestations and sync aggregates in proposed blocks
+	Execution         int64 // Priority fees and MEV of proposed blocks
+	Total             int64
+	APR               float64 // Total annualized on the effective balance
+
+	ExpectedProposals        float64
+	ProposalProbability      float64 // Probability of proposing at least one block within the period
+	SyncCommitteeProbability float64 // Probability of being selected for at least one sync committee within the period
+	SyncCommitteePayout      int64   // Rewards of a full sync committee period once selected, in GWEI
+
+	Network            *NetworkAPR // Network conditions the forecast is based on
+	AverageBlockReward int64       // Average execution reward of the indexed blocks of the last 30 days, in GWEI
+	MEVShare           float64     // Share of those blocks built via MEV-Boost
+}
+
+// GetValidatorForecast estimates the rewards of a validator over the coming period from its effective balance, the
+// active balance of the network, the ideal attestation rewards and the average reward of the recently indexed blocks.
+// Validators that are not active are forecast no rewards
+func (s *EthereumService) GetValidatorForecast(ctx context.Context, validatorID string, period time.Duration) (*RewardsForecast, error) {
+	if period <= 0 {
+		return nil, ErrInvalidRange
+	}
+
+	status, err := s.GetValidatorStatus(ctx, validatorID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	network, err := s.GetNetworkAPR(ctx, forecastBlockWindow)
+	if err != nil {
+		return nil, err
+	}
+
+	forecast := &RewardsForecast{
+		ValidatorIndex:   status.Index,
+		Status:           status.Status,
+		EffectiveBalance: status.EffectiveBalance,
+		Epochs:           int64(period / epochDuration),
+		Network:          network,
+	}
+
+	rewards := s.indexedRewards(network.FromSlot, network.ToSlot)
+	values := make([]*big.Int, 0, len(rewards))
+	mevBlocks := 0
+	for _, reward := range rewards {
+		values = append(values, reward.Reward)
+		if reward.Status == "mev" {
+			mevBlocks++
+		}
+	}
+	forecast.AverageBlockReward = averageBig(values).Int64()
+	if len(rewards) > 0 {
+		forecast.MEVShare = float64(mevBlocks) / float64(len(rewards))
+	}
+
+	if !strings.HasPrefix(status.Status, "active") || status.EffectiveBalance <= 0 {
+		return forecast, nil
+	}
+
+	// Rewards are proportional to the effective balance, and so are the chances of being selected
+	scale := float64(status.EffectiveBalance) / principalGwei
+	share := float64(status.EffectiveBalance) / float64(network.ActiveBalance)
+	epochs := float64(forecast.Epochs)
+
+	// The ideal rewards already reflect the participation, the base reward of 32 ETH is recovered from them
+	ideal := float64(network.IdealAttestationReward)
+	baseReward := ideal * weightDenominator / attestationRewardWeight
+	if network.Participation > 0 {
+		baseReward /= network.Participation
+	}
+
+	forecast.Attestation = int64(ideal * scale * epochs)
+	forecast.SyncCommittee = int64(baseReward * syncRewardWeight / weightDenominator * scale * epochs)
+	forecast.ProposerConsensus = int64(baseReward * proposerRewardWeight / weightDenominator * network.Participation * scale * epochs)
+
+	slots := epochs * SlotsPerEpoch
+	forecast.ExpectedProposals = slots * share
+	forecast.ProposalProbability = 1 - math.Pow(1-math.Min(share, 1), slots)
+	forecast.Execution = int64(forecast.ExpectedProposals * float64(forecast.AverageBlockReward))
+
+	selection := syncSelectionProbability(status.EffectiveBalance, network.ActiveBalance)
+	periods := slots / SlotsPerSyncPeriod
+	forecast.SyncCommitteeProbability = 1 - math.Pow(1-selection, periods)
+	if selection > 0 {
+		forecast.SyncCommitteePayout = int64(baseReward * syncRewardWeight / weightDenominator * scale * SlotsPerSyncPeriod / SlotsPerEpoch / selection)
+	}
+
+	forecast.Total = forecast.Attestation + forecast.SyncCommittee + forecast.ProposerConsensus + forecast.Execution
+	forecast.APR = annualize(forecast.Total, status.EffectiveBalance, float64(period)/float64(yearDuration))
+	return forecast, nil
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"math/big"
+	"time"
+)
+
+// baseFeeSeriesPoints bounds the number of points of a base fee series, consecutive blocks are averaged into buckets
+// so that long windows stay small
+const baseFeeSeriesPoints = 720
+
+// BlockGas summarizes the gas usage and base fee of a block
+type BlockGas struct {
+	Slot        int64
+	BlockNumber int64
+	GasUsed     int64
+	GasLimit    int64
+	Utilization float64  // Percentage of the gas limit used
+	BaseFee     *big.Int // Base fee per gas in Wei, nil before Bellatrix
+}
+
+// BaseFeePoint summarizes the base fee of the indexed blocks within a bucket of consecutive slots
+type BaseFeePoint struct {
+	Slot        int64 // First slot of the bucket
+	Timestamp   int64 // Unix time of the first block of the bucket
+	Blocks      int
+	BaseFee     *big.Int // Average base fee per gas in Wei
+	MinBaseFee  *big.Int
+	MaxBaseFee  *big.Int
+	Utilization float64 // Average percentage of the gas limit used
+}
+
+// BaseFeeSeries is the base fee trend of the indexed blocks within a window
+type BaseFeeSeries struct {
+	FromSlot    int64
+	ToSlot      int64
+	Blocks      int
+	BucketSlots int64 // Number of slots averaged into each point
+	MinBaseFee  *big.Int
+	Median      *big.Int
+	MaxBaseFee  *big.Int
+	Points      []BaseFeePoint // Oldest first, buckets without indexed blocks are omitted
+}
+
+// GetBlockGas returns the gas usage and base fee of the block at a slot, served from the index when possible
+func (s *EthereumService) GetBlockGas(ctx context.Context, slot int64) (*BlockGas, error) {
+	block, ok := s.index.Get(slot)
+	// Blocks indexed before the base fee was recorded are fetched again
+	if !ok || (block.BaseFee == nil && block.ExecutionBlockHash != "") {
+		var err error
+		block, err = s.GetBlockDetailBySlot(ctx, slot)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &BlockGas{
+		Slot:        block.Slot,
+		BlockNumber: block.ExecutionBlockNumber,
+		GasUsed:     block.GasUsed,
+		GasLimit:    block.GasLimit,
+		Utilization: gasUtilization(block),
+		BaseFee:     block.BaseFee,
+	}, nil
+}
+
+// GetBaseFeeSeries returns the base fee trend across a window of indexed blocks ending at the latest indexed slot
+func (s *EthereumService) GetBaseFeeSeries(window time.Duration) (*BaseFeeSeries, error) {
+	if window <= 0 {
+		return nil, ErrInvalidRange
+	}
+
+	windowSlots := int64(window / (SecondsPerSlot * time.Second))
+	toSlot := s.index.LatestSlot()
+	fromSlot := toSlot - windowSlots + 1
+	if fromSlot < 0 {
+		fromSlot = 0
+	}
+
+	series := &BaseFeeSeries{
+		FromSlot:    fromSlot,
+		ToSlot:      toSlot,
+		BucketSlots: max(1, (windowSlots+baseFeeSeriesPoints-1)/baseFeeSeriesPoints),
+		Points:      make([]BaseFeePoint, 0),
+	}
+
+	blocks := s.index.Range(fromSlot, toSlot, func(block *BlockDetail) bool {
+		return block.BaseFee != nil
+	})
+	values := make([]*big.Int, 0, len(blocks))
+	var bucket []*BlockDetail
+	for _, block := range blocks {
+		values = append(values, block.BaseFee)
+		if len(bucket) > 0 && (block.Slot-fromSlot)/series.BucketSlots != (bucket[0].Slot-fromSlot)/series.BucketSlots {
+			series.Points = append(series.Points, newBaseFeePoint(bucket, fromSlot, series.BucketSlots))
+			bucket = nil
+		}
+		bucket = append(bucket, block)
+	}
+	if len(bucket) > 0 {
+		series.Points = append(series.Points, newBaseFeePoint(bucket, fromSlot, series.BucketSlots))
+	}
+
+	series.Blocks = len(values)
+	series.MinBaseFee = percentileBig(values, 0)
+	series.Median = percentileBig(values, 50)
+	series.MaxBaseFee = percentileBig(values, 100)
+	return series, nil
+}
+
+// newBaseFeePoint averages the blocks of a bucket, which must not be empty
+func newBaseFeePoint(blocks []*BlockDetail, fromSlot, bucketSlots int64) BaseFeePoint {
+	point := BaseFeePoint{
+		Slot:       fromSlot + (blocks[0].Slot-fromSlot)/bucketSlots*bucketSlots,
+		Timestamp:  blocks[0].Timestamp,
+		Blocks:     len(blocks),
+		BaseFee:    new(big.Int),
+		MinBaseFee: blocks[0].BaseFee,
+		MaxBaseFee: blocks[0].BaseFee,
+	}
+	for _, block := range blocks {
+		point.BaseFee.Add(point.BaseFee, block.BaseFee)
+		if block.BaseFee.Cmp(point.MinBaseFee) < 0 {
+			point.MinBaseFee = block.BaseFee
+		}
+		if block.BaseFee.Cmp(point.MaxBaseFee) > 0 {
+			point.MaxBaseFee = block.BaseFee
+		}
+		point.Utilization += gasUtilization(block)
+	}
+	point.BaseFee.Quo(point.BaseFee, big.NewInt(int64(len(blocks))))
+	point.Utilization /= float64(len(blocks))
+	return point
+}
+
+// gasUtilization returns the percentage of the gas limit a block used, 0 for blocks without execution payload
+func gasUtilization(block *BlockDetail) float64 {
+	if block.GasLimit <= 0 {
+		return 0
+	}
+	return float64(block.GasUsed) / float64(block.GasLimit) * 100
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// errorRateWindow is the period the error rate of the health summary covers
+	errorRateWindow = 5 * time.Minute
+
+	// errorRateBucket is the step the error rate window slides by
+	errorRateBucket = 10 * time.Second
+)
+
+// upstreamStats counts requests made to the upstream providers
+type upstreamStats struct {
+	requests    atomic.Int64
+	failures    atomic.Int64
+	lastSuccess atomic.Int64 // Unix timestamp of the last successful request
+	recent      outcomeWindow
+}
+
+// outcomeWindow counts requests and failures over the last errorRateWindow, in buckets of errorRateBucket
+type outcomeWindow struct {
+	mu      sync.Mutex
+	clock   Clock // nil for the system clock
+	buckets [errorRateWindow / errorRateBucket]outcomeBucket
+}
+
+// outcomeBucket counts the requests and failures of one errorRateBucket
+type outcomeBucket struct {
+	step     int64 // Time of the bucket in errorRateBucket steps since the Unix epoch
+	requests int64
+	failures int64
+}
+
+// setClock replaces the clock the window slides by
+func (w *outcomeWindow) setClock(clock Clock) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.clock = clock
+}
+
+// step returns the current bucket step, the caller must hold the lock
+func (w *outcomeWindow) step() int64 {
+	now := time.Now()
+	if w.clock != nil {
+		now = w.clock.Now()
+	}
+	return now.UnixNano() / int64(errorRateBucket)
+}
+
+// record counts a request and whether it failed
+func (w *outcomeWindow) record(failed bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	step := w.step()
+	bucket := &w.buckets[step%int64(len(w.buckets))]
+	if bucket.step != step {
+		// The bucket last counted a step that left the window
+		*bucket = outcomeBucket{step: step}
+	}
+	bucket.requests++
+	if failed {
+		bucket.failures++
+	}
+}
+
+// counts returns the requests and failures within the window
+func (w *outcomeWindow) counts() (requests, failures int64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	step := w.step()
+	for _, bucket := range w.buckets {
+		if step-bucket.step < int64(len(w.buckets)) {
+			requests += bucket.requests
+			failures += bucket.failures
+		}
+	}
+	return requests, failures
+}
+
+// panicCount counts the panics recovered while serving requests
+var panicCount atomic.Int64
+
+// RecordPanic counts a panic recovered while serving a request
+func RecordPanic() {
+	panicCount.Add(1)
+}
+
+// countingTransport is an http.RoundTripper that records upstream request outcomes
+type countingTransport struct {
+	next  http.RoundTripper
+	stats *upstreamStats
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.stats.requests.Add(1)
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	if debugLogs.Load() {
+		redactedURL, _ := redactUpstreamURL(req.URL)
+		if err != nil {
+			debugLogf("upstream "+req.URL.Host, "Upstream: %s %s failed after %v: %s", req.Method, redactedURL, time.Since(start).Round(time.Millisecond), RedactSecrets(err.Error()))
+		} else {
+			debugLogf("upstream "+req.URL.Host, "Upstream: %s %s %d in %v", req.Method, redactedURL, resp.StatusCode, time.Since(start).Round(time.Millisecond))
+		}
+	}
+	if err != nil || resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests {
+		t.stats.failures.Add(1)
+		t.stats.recent.record(true)
+		return resp, err
+	}
+
+	t.stats.recent.record(false)
+	t.stats.lastSuccess.Store(time.Now().Unix())
+	return resp, nil
+}
+
+// HealthSummary represents an aggregated view of the service health
+type HealthSummary struct {
+	Status           string    `json:"status"`          // "ok" or "degraded"
+	Timestamp        time.Time `json:"timestamp"`       // Time the summary was generated
+	ProviderStatus   string    `json:"provider_status"` // "up" or "down"
+	HeadSlot         int64     `json:"head_slot"`
+	IndexedSlot      int64     `json:"indexed_slot"`
+	IndexerLag       int64     `json:"indexer_lag"`       // Slots between the head and the latest indexed block
+	UpstreamRequests int64     `json:"upstream_requests"` // Upstream requests since startup
+	UpstreamFailures int64     `json:"upstream_failures"` // Failed upstream requests since startup
+	ErrorRate        float64   `json:"error_rate"`        // Share of the upstream requests of the last 5 minutes that failed
+	LastSuccess      time.Time `json:"last_success,omitempty"`
+	Panics           int64     `json:"panics"` // Panics recovered while serving requests since startup
+}
+
+// GetHealthSummary probes the provider and aggregates upstream statistics into a HealthSummary
+func (s *EthereumService) GetHealthSummary(ctx context.Context) *HealthSummary {
+	summary := &HealthSummary{
+		Status:         "ok",
+		Timestamp:      time.Now().UTC(),
+		ProviderStatus: "up",
+		IndexedSlot:    s.index.LatestSlot(),
+	}
+
+	headSlot, err := s.getHeadSlot(ctx)
+	if err != nil {
+		summary.Status = "degraded"
+		summary.ProviderStatus = "down"
+	} else {
+		summary.HeadSlot = headSlot
+		if summary.IndexedSlot >= 0 {
+			summary.IndexerLag = headSlot - summary.IndexedSlot
+		}
+	}
+
+	summary.Panics = panicCount.Load()
+	summary.UpstreamRequests = s.stats.requests.Load()
+	summary.UpstreamFailures = s.stats.failures.Load()
+	// The totals would hide a recent outage behind a long healthy uptime, so the rate only covers the last requests
+	if requests, failures := s.stats.recent.counts(); requests > 0 {
+		summary.ErrorRate = float64(failures) / float64(requests)
+	}
+	if lastSuccess := s.stats.lastSuccess.Load(); lastSuccess > 0 {
+		summary.LastSuccess = time.Unix(lastSuccess, 0).UTC()
+	}
+
+	return summary
+}
+
+// UpstreamsHealth represents the state of the upstream providers and the client of the beacon node
+type UpstreamsHealth struct {
+	Available     bool
+	BeaconVersion string          // "" until the beacon node answered
+	BeaconClient  ConsensusClient // Detected from BeaconVersion
+	Shims         []string        // Workarounds applied for the client's quirks
+}
+
+// GetUpstreamsHealth reports whether the upstreams were reached, asking an available beacon node for its version
+// again so an upgraded or replaced node is noticed
+func (s *EthereumService) GetUpstreamsHealth(ctx context.Context) *UpstreamsHealth {
+	available, _ := s.UpstreamsAvailable()
+	if available {
+		// The version known from the last successful request is reported when the node doesn't answer
+		_ = s.DetectBeaconNode(ctx)
+	}
+	return &UpstreamsHealth{
+		Available:     available,
+		BeaconVersion: s.BeaconNodeVersion(),
+		BeaconClient:  s.ConsensusClient(),
+		Shims:         s.Shims(),
+	}
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// HeartbeatPublisher periodically POSTs a signed health summary to an external monitoring URL
+type HeartbeatPublisher struct {
+	service  *EthereumService
+	url      string
+	secret   string
+	interval time.Duration
+	client   *http.Client
+}
+
+// NewHeartbeatPublisher creates a HeartbeatPublisher; the summary is signed with HMAC-SHA256 when secret is set
+func NewHeartbeatPublisher(service *EthereumService, monitorURL, secret string, interval time.Duration) (*HeartbeatPublisher, error) {
+	if err := validateURL("Heartbeat", monitorURL); err != nil {
+		return nil, err
+	}
+	if interval <= 0 {
+		return nil, fmt.Errorf("heartbeat interval must be positive")
+	}
+
+	return &HeartbeatPublisher{
+		service:  service,
+		url:      monitorURL,
+		secret:   secret,
+		interval: interval,
+		client: &http.Client{
+			Timeout: time.Second * 10,
+		},
+	}, nil
+}
+
+// Run publishes a heartbeat every interval until the context is cancelled
+func (p *HeartbeatPublisher) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := p.publish(ctx); err != nil {
+			log.Printf("Heartbeat: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// publish sends a single health summary to the monitoring URL
+func (p *HeartbeatPublisher) publish(ctx context.Context) error {
+	body, err := json.Marshal(p.service.GetHealthSummary(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to marshal health summary: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.url, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.secret != "" {
+		req.Header.Set("X-Signature-256", "sha256="+SignPayload(p.secret, body))
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to publish heartbeat: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("monitoring URL responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// SignPayload returns the hex encoded HMAC-SHA256 signature of a payload
+func SignPayload(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
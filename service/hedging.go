@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// DefaultHedgeDelay is how long the primary upstream gets before the request is also sent to the secondary
+const DefaultHedgeDelay = 750 * time.Millisecond
+
+// hedgeResult is the outcome of one upstream attempt
+type hedgeResult[T any] struct {
+	value    T
+	err      error
+	provider string // "primary" or "secondary"
+}
+
+// hedge runs call against the primary upstream and, if it hasn't answered within delay or failed,
+// against the secondary as well, returning whichever succeeds first. The provider that answered is recorded in the
+// provenance of ctx
+func hedge[T any](ctx context.Context, delay time.Duration, primary, secondary func(context.Context) (T, error)) (T, error) {
+	provenance := ProvenanceFrom(ctx)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel() // Abandon the slower attempt
+
+	results := make(chan hedgeResult[T], 2)
+	attempt := func(provider string, call func(context.Context) (T, error)) {
+		value, err := call(ctx)
+		results <- hedgeResult[T]{value: value, err: err, provider: provider}
+	}
+	go attempt("primary", primary)
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	pending := 1
+	hedged := false
+	var last hedgeResult[T]
+	for {
+		select {
+		case <-timer.C:
+			if !hedged {
+				hedged = true
+				pending++
+				go attempt("secondary", secondary)
+			}
+		case last = <-results:
+			pending--
+			if last.err == nil {
+				provenance.recordProvider(last.provider)
+				return last.value, nil
+			}
+			// A failed primary shouldn't wait out the delay
+			if !hedged {
+				hedged = true
+				pending++
+				go attempt("secondary", secondary)
+			}
+			if pending == 0 {
+				return last.value, last.err
+			}
+		}
+	}
+}
+
+// HedgedBeaconClient sends Beacon API requests to a secondary provider when the primary is slow
+type HedgedBeaconClient struct {
+	primary   BeaconClient
+	secondary BeaconClient
+	delay     time.Duration
+}
+
+// NewHedgedBeaconClient creates a BeaconClient hedging requests to primary with secondary after delay
+func NewHedgedBeaconClient(primary, secondary BeaconClient, delay time.Duration) *HedgedBeaconClient {
+	return &HedgedBeaconClient{primary: primary, secondary: secondary, delay: delay}
+}
+
+// GetJSON performs a hedged GET request and decodes the first successful response into out
+func (h *HedgedBeaconClient) GetJSON(ctx context.Context, path string, out interface{}) error {
+	// Each attempt decodes into its own buffer so the slower one can't overwrite out
+	raw, err := hedge(ctx, h.delay, func(ctx context.Context) (json.RawMessage, error) {
+		var raw json.RawMessage
+		return raw, h.primary.GetJSON(ctx, path, &raw)
+	}, func(ctx context.Context) (json.RawMessage, error) {
+		var raw json.RawMessage
+		return raw, h.secondary.GetJSON(ctx, path, &raw)
+	})
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, out)
+}
+
+// PostJSON performs a hedged POST request and decodes the first successful response into out
+func (h *HedgedBeaconClient) PostJSON(ctx context.Context, path string, body interface{}, out interface{}) error {
+	raw, err := hedge(ctx, h.delay, func(ctx context.Context) (json.RawMessage, error) {
+		var raw json.RawMessage
+		return raw, h.primary.PostJSON(ctx, path, body, &raw)
+	}, func(ctx context.Context) (json.RawMessage, error) {
+		var raw json.RawMessage
+		return raw, h.secondary.PostJSON(ctx, path, body, &raw)
+	})
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, out)
+}
+
+// SignedBeaconBlock performs a hedged typed block lookup when both providers support it
+func (h *HedgedBeaconClient) SignedBeaconBlock(ctx context.Context, blockID string) (*spec.VersionedSignedBeaconBlock, error) {
+	primary, ok := h.primary.(BlockDecoder)
+	if !ok {
+		return nil, errTypedClientUnavailable
+	}
+	secondary, ok := h.secondary.(BlockDecoder)
+	if !ok {
+		return primary.SignedBeaconBlock(ctx, blockID)
+	}
+
+	return hedge(ctx, h.delay, func(ctx context.Context) (*spec.VersionedSignedBeaconBlock, error) {
+		return primary.SignedBeaconBlock(ctx, blockID)
+	}, func(ctx context.Context) (*spec.VersionedSignedBeaconBlock, error) {
+		return secondary.SignedBeaconBlock(ctx, blockID)
+	})
+}
+
+// SubscribeHeads streams new heads from the primary provider
+func (h *HedgedBeaconClient) SubscribeHeads(ctx context.Context, handler func(slot int64)) error {
+	subscriber, ok := h.primary.(HeadSubscriber)
+	if !ok {
+		return errTypedClientUnavailable
+	}
+	return subscriber.SubscribeHeads(ctx, handler)
+}
+
+// HedgedExecutionClient sends JSON-RPC calls to a secondary provider when the primary is slow
+type HedgedExecutionClient struct {
+	primary   ExecutionClient
+	secondary ExecutionClient
+	delay     time.Duration
+}
+
+// NewHedgedExecutionClient creates an ExecutionClient hedging calls to primary with secondary after delay
+func NewHedgedExecutionClient(primary, secondary ExecutionClient, delay time.Duration) *HedgedExecutionClient {
+	return &HedgedExecutionClient{primary: primary, secondary: secondary, delay: delay}
+}
+
+// Call performs a hedged JSON-RPC call and decodes the first successful result into out
+func (h *HedgedExecutionClient) Call(ctx context.Context, method string, params []interface{}, out interface{}) error {
+	raw, err := hedge(ctx, h.delay, func(ctx context.Context) (json.RawMessage, error) {
+		var raw json.RawMessage
+		return raw, h.primary.Call(ctx, method, params, &raw)
+	}, func(ctx context.Context) (json.RawMessage, error) {
+		var raw json.RawMessage
+		return raw, h.secondary.Call(ctx, method, params, &raw)
+	})
+	if err != nil || out == nil {
+		return err
+	}
+	return json.Unmarshal(raw, out)
+}
+
+// blockWithReceipts pairs a block with its receipts so both can be hedged together
+type blockWithReceipts struct {
+	block    *types.Block
+	receipts []*types.Receipt
+}
+
+// BlockWithReceipts performs a hedged block and receipts lookup
+func (h *HedgedExecutionClient) BlockWithReceipts(ctx context.Context, number *big.Int) (*types.Block, []*types.Receipt, error) {
+	result, err := hedge(ctx, h.delay, func(ctx context.Context) (blockWithReceipts, error) {
+		block, receipts, err := h.primary.BlockWithReceipts(ctx, number)
+		return blockWithReceipts{block: block, receipts: receipts}, err
+	}, func(ctx context.Context) (blockWithReceipts, error) {
+		block, receipts, err := h.secondary.BlockWithReceipts(ctx, number)
+		return blockWithReceipts{block: block, receipts: receipts}, err
+	})
+	return result.block, result.receipts, err
+}
+
+// SubscribeNewHeads streams new heads from the primary provider
+func (h *HedgedExecutionClient) SubscribeNewHeads(ctx context.Context, handler func(number uint64)) error {
+	subscriber, ok := h.primary.(ExecutionHeadSubscriber)
+	if !ok {
+		return errTypedClientUnavailable
+	}
+	return subscriber.SubscribeNewHeads(ctx, handler)
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+)
+
+// indexerCatchUpSlots bounds how many slots behind the head the indexer catches up on before the backfill
+const indexerCatchUpSlots = 32
+
+// indexerBackfillBatch is how many older slots of the index window are backfilled per run, after the new heads
+const indexerBackfillBatch = 64
+
+// RunIndexer follows the chain head and indexes every new block until the context is cancelled. Between new heads it
+// backfills the index window from the head backwards, so statistics over the window don't depend on what clients
+// happened to request. The indexer is the only writer of blocks and rewards to the index
+func (s *EthereumService) RunIndexer(ctx context.Context) {
+	ctx = WithPriority(ctx, PriorityBatch)
+	ticker := time.NewTicker(12 * time.Second) // One tick per slot
+	defer ticker.Stop()
+
+	// Index as soon as a new head arrives when the beacon client can stream them, the ticker remains as a fallback
+	heads := make(chan struct{}, 1)
+	if subscriber, ok := s.beaconClient().(HeadSubscriber); ok {
+		err := subscriber.SubscribeHeads(ctx, func(slot int64) {
+			select {
+			case heads <- struct{}{}:
+			default:
+			}
+		})
+		if err != nil {
+			log.Printf("Indexer: failed to subscribe to head events, polling instead: %v", err)
+		}
+	}
+	// A local execution node announces new heads as soon as it imported them, before the beacon node's head event
+	if subscriber, ok := s.executionClient().(ExecutionHeadSubscriber); ok && s.LocalNode() {
+		err := subscriber.SubscribeNewHeads(ctx, func(uint64) {
+			select {
+			case heads <- struct{}{}:
+			default:
+			}
+		})
+		if err != nil {
+			log.Printf("Indexer: failed to subscribe to execution heads: %v", err)
+		}
+	}
+
+	backfill := int64(-1) // Next older slot to backfill, -1 until the first head is known
+	for {
+		backfill = s.indexNewBlocks(ctx, backfill)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-heads:
+		}
+	}
+}
+
+// indexNewBlocks indexes all slots between the latest indexed slot and the current head, then backfills up to
+// indexerBackfillBatch slots of the index window from backfill downwards. It returns the next slot to backfill
+func (s *EthereumService) indexNewBlocks(ctx context.Context, backfill int64) int64 {
+	headSlot, err := s.getHeadSlot(ctx)
+	if err != nil {
+		log.Printf("Indexer: failed to get head slot: %v", err)
+		return backfill
+	}
+
+	from := s.index.LatestSlot() + 1
+	if from < headSlot-indexerCatchUpSlots {
+		from = headSlot - indexerCatchUpSlots
+	}
+	if backfill < 0 {
+		backfill = from - 1
+	}
+
+	for slot := from; slot <= headSlot; slot++ {
+		if ctx.Err() != nil {
+			return backfill
+		}
+		s.indexSlot(ctx, slot)
+	}
+
+	oldest := max(headSlot-s.index.Window()+1, 0)
+	for n := 0; n < indexerBackfillBatch && backfill >= oldest; n++ {
+		if ctx.Err() != nil {
+			return backfill
+		}
+		s.indexSlot(ctx, backfill)
+		backfill--
+	}
+	return backfill
+}
+
+// indexSlot adds the block and reward of a slot to the index, slots without a block are skipped
+func (s *EthereumService) indexSlot(ctx context.Context, slot int64) {
+	detail, err := s.GetBlockDetailBySlot(ctx, slot)
+	if err != nil {
+		if !errors.Is(err, ErrSlotNotFound) {
+			sampledLogf("indexer slot", "Indexer: failed to index slot %d: %v", slot, err)
+		}
+		return
+	}
+	reward, err := s.GetBlockRewardBySlot(ctx, slot)
+	if err != nil {
+		sampledLogf("indexer reward", "Indexer: failed to index reward of slot %d: %v", slot, err)
+		return
+	}
+
+	// Record the delivering relay so relay market share can be aggregated. The builder pubkey of the delivered
+	// payload is learned under the builder its extraData names, and names the builder of the block
+	if reward.Status == "mev" {
+		indexed := *reward
+		relay, trace := s.getDeliveredTrace(ctx, slot)
+		indexed.Relay = relay
+		if trace != nil && s.builders != nil && strings.EqualFold(trace.BlockHash, detail.ExecutionBlockHash) {
+			s.builders.Learn(reward.ExtraData, trace.BuilderPubkey)
+			if builder := s.builders.Resolve(reward.ExtraData, trace.BuilderPubkey); builder != "" {
+				indexed.Builder = builder
+			}
+		}
+		reward = &indexed
+	}
+	s.index.Put(detail)
+	s.index.PutReward(slot, reward)
+}
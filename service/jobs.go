@@ -0,0 +1,276 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+	"time"
+)
+
+var (
+	ErrJobNotFound         = errors.New("job does not exist")
+	ErrIdempotencyConflict = errors.New("idempotency key was used for a different request")
+	ErrJobQueueFull        = errors.New("job queue is full")
+	ErrTooManyJobs         = errors.New("client has too many unfinished jobs")
+)
+
+const (
+	// MaxJobSlots is the largest slot range a single job may cover
+	MaxJobSlots = 10000
+
+	// jobWorkers is how many jobs fetch rewards at once, further jobs wait queued
+	jobWorkers = 2
+
+	// maxQueuedJobs is how many jobs may wait for a worker, submissions are rejected beyond
+	maxQueuedJobs = 16
+
+	// maxUnfinishedJobsPerClient is how many queued or running jobs a single client may have
+	maxUnfinishedJobsPerClient = 2
+
+	// maxFinishedJobsPerClient is how many finished jobs of a single client are kept, older ones are dropped first
+	maxFinishedJobsPerClient = 8
+
+	// jobRetention is how long finished jobs and their idempotency keys are kept
+	jobRetention = 24 * time.Hour
+)
+
+// JobStatus is the state of an asynchronous job
+type JobStatus string
+
+const (
+	JobQueued    JobStatus = "queued"
+	JobRunning   JobStatus = "running"
+	JobCompleted JobStatus = "completed"
+	JobFailed    JobStatus = "failed"
+)
+
+// BlockRewardJobResult is the outcome of a single slot of a block reward job
+type BlockRewardJobResult struct {
+	Slot   int64
+	Reward *BlockReward // nil if the slot was missed or failed
+	Missed bool         // No block was proposed in the slot
+	Error  string       // Why the reward could not be fetched, "" on success
+}
+
+// BlockRewardJob is an asynchronous computation of the block rewards of a slot range
+type BlockRewardJob struct {
+	ID         string
+	Status     JobStatus
+	FromSlot   int64
+	ToSlot     int64
+	Processed  int // Slots handled so far
+	Results    []BlockRewardJobResult
+	CreatedAt  time.Time
+	FinishedAt time.Time // Zero until the job completed or failed
+	Error      string    // Why the job failed, "" otherwise
+
+	client string // Client that submitted the job
+}
+
+// JobQueue tracks asynchronous jobs and the idempotency keys they were submitted with. A fixed pool of workers
+// runs the jobs waiting in a bounded queue
+type JobQueue struct {
+	mu      sync.Mutex
+	jobs    map[string]*BlockRewardJob
+	keys    map[jobKey]string    // Job IDs keyed by client and idempotency key
+	pending chan *BlockRewardJob // Jobs waiting for a worker
+	workers sync.Once            // Starts the workers with the first job
+}
+
+// jobKey is an idempotency key in the namespace of the client that sent it
+type jobKey struct {
+	client string
+	key    string
+}
+
+// NewJobQueue creates an empty JobQueue
+func NewJobQueue() *JobQueue {
+	return &JobQueue{
+		jobs:    make(map[string]*BlockRewardJob),
+		keys:    make(map[jobKey]string),
+		pending: make(chan *BlockRewardJob, maxQueuedJobs),
+	}
+}
+
+// SubmitBlockRewardJob enqueues a job of client computing the block rewards of the slots from fromSlot to toSlot.
+// A job the client submitted earlier with the same idempotency key is returned instead of starting a new one, created
+// reports which happened. Submissions fail with ErrTooManyJobs while the client has too many unfinished jobs and with
+// ErrJobQueueFull while the queue is full
+func (s *EthereumService) SubmitBlockRewardJob(client string, fromSlot, toSlot int64, idempotencyKey string) (job *BlockRewardJob, created bool, err error) {
+	if fromSlot < 0 || toSlot < fromSlot {
+		return nil, false, fmt.Errorf("%w: from_slot must be between 0 and to_slot", ErrInvalidRange)
+	}
+	if toSlot-fromSlot+1 > MaxJobSlots {
+		return nil, false, fmt.Errorf("%w: at most %d slots per job", ErrInvalidRange, MaxJobSlots)
+	}
+
+	q := s.jobs
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.prune(s.clock.Now())
+
+	key := jobKey{client: client, key: idempotencyKey}
+	if id, ok := q.keys[key]; ok && idempotencyKey != "" {
+		existing := q.jobs[id]
+		if existing.FromSlot != fromSlot || existing.ToSlot != toSlot {
+			return nil, false, ErrIdempotencyConflict
+		}
+		return existing.snapshot(), false, nil
+	}
+
+	if q.unfinished(client) >= maxUnfinishedJobsPerClient {
+		return nil, false, ErrTooManyJobs
+	}
+
+	job = &BlockRewardJob{
+		ID:        newJobID(),
+		Status:    JobQueued,
+		FromSlot:  fromSlot,
+		ToSlot:    toSlot,
+		CreatedAt: s.clock.Now().UTC(),
+		client:    client,
+	}
+	q.workers.Do(func() {
+		for range jobWorkers {
+			go s.runBlockRewardJobs()
+		}
+	})
+	select {
+	case q.pending <- job:
+	default:
+		return nil, false, ErrJobQueueFull
+	}
+
+	q.jobs[job.ID] = job
+	if idempotencyKey != "" {
+		q.keys[key] = job.ID
+	}
+	return job.snapshot(), true, nil
+}
+
+// GetBlockRewardJob returns the current state of a job
+func (s *EthereumService) GetBlockRewardJob(id string) (*BlockRewardJob, error) {
+	q := s.jobs
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	job, ok := q.jobs[id]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	return job.snapshot(), nil
+}
+
+// runBlockRewardJobs is a worker running the queued jobs one after another
+func (s *EthereumService) runBlockRewardJobs() {
+	for job := range s.jobs.pending {
+		s.runBlockRewardJob(job)
+	}
+}
+
+// runBlockRewardJob fetches the rewards of the job's slots one by one, leaving upstream capacity to interactive requests
+func (s *EthereumService) runBlockRewardJob(job *BlockRewardJob) {
+	q := s.jobs
+	q.mu.Lock()
+	job.Status = JobRunning
+	q.mu.Unlock()
+
+	ctx := WithPriority(context.Background(), PriorityBatch)
+	for slot := job.FromSlot; slot <= job.ToSlot; slot++ {
+		result := BlockRewardJobResult{Slot: slot}
+		reward, err := s.GetBlockRewardBySlot(ctx, slot)
+		switch {
+		case err == nil:
+			result.Reward = reward
+		case errors.Is(err, ErrSlotNotFound):
+			result.Missed = true
+		case errors.Is(err, ErrFutureSlot):
+			// Later slots are in the future as well
+			s.finishJob(job, JobFailed, fmt.Sprintf("slot %d is in the future", slot))
+			return
+		default:
+			result.Error = err.Error()
+		}
+
+		q.mu.Lock()
+		job.Results = append(job.Results, result)
+		job.Processed++
+		q.mu.Unlock()
+	}
+
+	s.finishJob(job, JobCompleted, "")
+}
+
+// finishJob records the final status of a job
+func (s *EthereumService) finishJob(job *BlockRewardJob, status JobStatus, reason string) {
+	q := s.jobs
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	job.Status = status
+	job.Error = reason
+	job.FinishedAt = s.clock.Now().UTC()
+	if status == JobFailed {
+		log.Printf("Block reward job %s failed: %s", job.ID, reason)
+	}
+}
+
+// unfinished counts the queued and running jobs of a client, the caller must hold the queue lock
+func (q *JobQueue) unfinished(client string) int {
+	count := 0
+	for _, job := range q.jobs {
+		if job.client == client && job.FinishedAt.IsZero() {
+			count++
+		}
+	}
+	return count
+}
+
+// prune drops jobs that finished more than jobRetention ago and the oldest finished jobs of clients holding more than
+// maxFinishedJobsPerClient, along with their idempotency keys
+func (q *JobQueue) prune(now time.Time) {
+	finished := make(map[string][]*BlockRewardJob)
+	for id, job := range q.jobs {
+		if job.FinishedAt.IsZero() {
+			continue
+		}
+		if now.Sub(job.FinishedAt) > jobRetention {
+			delete(q.jobs, id)
+			continue
+		}
+		finished[job.client] = append(finished[job.client], job)
+	}
+	for _, jobs := range finished {
+		if len(jobs) <= maxFinishedJobsPerClient {
+			continue
+		}
+		slices.SortFunc(jobs, func(a, b *BlockRewardJob) int { return a.FinishedAt.Compare(b.FinishedAt) })
+		for _, job := range jobs[:len(jobs)-maxFinishedJobsPerClient] {
+			delete(q.jobs, job.ID)
+		}
+	}
+	for key, id := range q.keys {
+		if _, ok := q.jobs[id]; !ok {
+			delete(q.keys, key)
+		}
+	}
+}
+
+// snapshot copies the job so it can be read while the job keeps running, the caller must hold the queue lock
+func (j *BlockRewardJob) snapshot() *BlockRewardJob {
+	copied := *j
+	copied.Results = append([]BlockRewardJobResult(nil), j.Results...)
+	return &copied
+}
+
+// newJobID generates a random job ID
+func newJobID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,555 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/bits"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/attestantio/go-eth2-client/spec/altair"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+// ErrLightClientDisabled is returned by light client lookups while no trusted checkpoint is configured
+var ErrLightClientDisabled = errors.New("light client verification is not configured")
+
+const (
+	// lightClientAncestry bounds how many slots below a verified header a block root is verified by walking parent roots
+	lightClientAncestry = 2 * SlotsPerEpoch
+
+	// maxLightClientRoots bounds the number of verified block roots kept, older roots are dropped first
+	maxLightClientRoots = 8192
+
+	// maxLightClientUpdates is the number of sync committee periods requested at once, the MAX_REQUEST_LIGHT_CLIENT_UPDATES
+	// of the Beacon API
+	maxLightClientUpdates = 128
+
+	// Subtree indices of the state fields proven by light client data. The depth of the proofs grew with the number of
+	// state fields in Electra, so it is taken from the length of each branch
+	currentSyncCommitteeIndex = 22
+	nextSyncCommitteeIndex    = 23
+	finalizedRootIndex        = 41
+
+	// syncCommitteeSignatureDST is the domain separation tag of the BLS signatures of the beacon chain
+	syncCommitteeSignatureDST = "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_"
+)
+
+// domainSyncCommittee is the DOMAIN_SYNC_COMMITTEE signature domain type
+var domainSyncCommittee = phase0.DomainType{0x07, 0x00, 0x00, 0x00}
+
+var checkpointPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{64}$`)
+
+// lightClientHeader is the header of a light client object, of which only the beacon block header is verified
+type lightClientHeader struct {
+	Beacon phase0.BeaconBlockHeader `json:"beacon"`
+}
+
+// lightClientBootstrap represents the data of /eth/v1/beacon/light_client/bootstrap/{block_root} with the proof of
+// the sync committee
+type lightClientBootstrap struct {
+	Data struct {
+		Header                     lightClientHeader    `json:"header"`
+		CurrentSyncCommittee       altair.SyncCommittee `json:"current_sync_committee"`
+		CurrentSyncCommitteeBranch []phase0.Root        `json:"current_sync_committee_branch"`
+	} `json:"data"`
+}
+
+// lightClientUpdate is a header signed by a sync committee. Finality and optimistic updates leave out the next sync
+// committee, and optimistic updates the finalized header as well
+type lightClientUpdate struct {
+	AttestedHeader          lightClientHeader     `json:"attested_header"`
+	NextSyncCommittee       *altair.SyncCommittee `json:"next_sync_committee"`
+	NextSyncCommitteeBranch []phase0.Root         `json:"next_sync_committee_branch"`
+	FinalizedHeader         *lightClientHeader    `json:"finalized_header"`
+	FinalityBranch          []phase0.Root         `json:"finality_branch"`
+	SyncAggregate           altair.SyncAggregate  `json:"sync_aggregate"`
+	SignatureSlot           string                `json:"signature_slot"`
+}
+
+// lightClientUpdateResponse represents the response from /eth/v1/beacon/light_client/finality_update and
+// /eth/v1/beacon/light_client/optimistic_update, and each element of /eth/v1/beacon/light_client/updates
+type lightClientUpdateResponse struct {
+	Data lightClientUpdate `json:"data"`
+}
+
+// syncCommitteeKeys is a sync committee whose pubkeys were proven against a verified state
+type syncCommitteeKeys struct {
+	pubkeys []bls.G1Affine
+}
+
+// lightClientStore follows the chain from a trusted checkpoint by verifying the sync committee signatures of light
+// client updates, and keeps the block roots proven along the way
+type lightClientStore struct {
+	mu                    sync.RWMutex
+	checkpoint            string // Trusted block root the store is bootstrapped from
+	bootstrapped          bool
+	genesisValidatorsRoot phase0.Root
+	finalizedSlot         int64
+	current               *syncCommitteeKeys // Committee of the period of the finalized header
+	next                  *syncCommitteeKeys // Committee of the following period, nil until an update proved it
+	roots                 map[string]int64   // Slot of each verified block root, keyed by lower-cased root
+	slots                 map[int64]string   // Verified block root of each slot
+	latest                int64              // Highest slot with a verified block root
+}
+
+// EnableLightClient turns on the verification of beacon data against sync committee signatures, bootstrapped from
+// the block root of a trusted checkpoint. The store is bootstrapped by the first run of the light client task
+func (s *EthereumService) EnableLightClient(checkpoint string) error {
+	if !checkpointPattern.MatchString(checkpoint) {
+		return fmt.Errorf("invalid light client checkpoint %q: expected a 0x-prefixed block root", checkpoint)
+	}
+	s.lightClient = &lightClientStore{
+		checkpoint: strings.ToLower(checkpoint),
+		roots:      make(map[string]int64),
+		slots:      make(map[int64]string),
+	}
+	return nil
+}
+
+// LightClientEnabled reports whether beacon data is verified against sync committee signatures
+func (s *EthereumService) LightClientEnabled() bool {
+	return s.lightClient != nil
+}
+
+// LightClientTask returns the task following the chain with light client updates, run at startup and on the given
+// schedule. Its first run bootstraps the store from the trusted checkpoint
+func (s *EthereumService) LightClientTask(schedule Schedule) Task {
+	return Task{
+		Name:       "light-client",
+		Schedule:   schedule,
+		RunAtStart: true,
+		Run:        s.syncLightClient,
+	}
+}
+
+// syncLightClient bootstraps the store when needed, catches up on the sync committee periods since its finalized
+// header and applies the latest finality and optimistic updates
+func (s *EthereumService) syncLightClient(ctx context.Context) error {
+	store := s.lightClient
+	if store == nil {
+		return ErrLightClientDisabled
+	}
+
+	store.mu.RLock()
+	bootstrapped := store.bootstrapped
+	store.mu.RUnlock()
+	if !bootstrapped {
+		if err := s.bootstrapLightClient(ctx); err != nil {
+			return err
+		}
+	}
+
+	headSlot, err := s.getHeadSlot(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get head slot: %w", err)
+	}
+	store.mu.RLock()
+	period := store.finalizedSlot / SlotsPerSyncPeriod
+	store.mu.RUnlock()
+
+	for headPeriod := headSlot / SlotsPerSyncPeriod; period < headPeriod; period += maxLightClientUpdates {
+		count := min(headPeriod-period, maxLightClientUpdates)
+		var updates []lightClientUpdateResponse
+		path := fmt.Sprintf("/eth/v1/beacon/light_client/updates?start_period=%d&count=%d", period, count)
+		if err := s.getBeaconJSON(ctx, path, &updates); err != nil {
+			return fmt.Errorf("failed to get light client updates: %w", err)
+		}
+		for _, update := range updates {
+			if err := s.applyLightClientUpdate(ctx, &update.Data); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, endpoint := range []string{"finality_update", "optimistic_update"} {
+		var update lightClientUpdateResponse
+		if err := s.getBeaconJSON(ctx, "/eth/v1/beacon/light_client/"+endpoint, &update); err != nil {
+			return fmt.Errorf("failed to get light client %s: %w", strings.ReplaceAll(endpoint, "_", " "), err)
+		}
+		if err := s.applyLightClientUpdate(ctx, &update.Data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bootstrapLightClient initializes the store with the sync committee proven against the trusted checkpoint
+func (s *EthereumService) bootstrapLightClient(ctx context.Context) error {
+	store := s.lightClient
+
+	var genesis genesisResponse
+	if err := s.getBeaconJSON(ctx, "/eth/v1/beacon/genesis", &genesis); err != nil {
+		return fmt.Errorf("failed to get genesis: %w", err)
+	}
+	var bootstrap lightClientBootstrap
+	if err := s.getBeaconJSON(ctx, "/eth/v1/beacon/light_client/bootstrap/"+store.checkpoint, &bootstrap); err != nil {
+		return fmt.Errorf("failed to get light client bootstrap: %w", err)
+	}
+
+	header := &bootstrap.Data.Header.Beacon
+	root, err := header.HashTreeRoot()
+	if err != nil {
+		return fmt.Errorf("failed to hash bootstrap header: %w", err)
+	}
+	if !strings.EqualFold(phase0.Root(root).String(), store.checkpoint) {
+		return fmt.Errorf("%w: bootstrap header hashes to %s, not the checkpoint %s", ErrInconsistentResponse, phase0.Root(root), store.checkpoint)
+	}
+	committee, err := provenSyncCommittee(&bootstrap.Data.CurrentSyncCommittee, bootstrap.Data.CurrentSyncCommitteeBranch, currentSyncCommitteeIndex, header.StateRoot)
+	if err != nil {
+		return fmt.Errorf("invalid bootstrap current sync committee: %w", err)
+	}
+	genesisValidatorsRoot, err := decodeRoot(genesis.Data.GenesisValidatorsRoot)
+	if err != nil {
+		return fmt.Errorf("%w: invalid genesis validators root %q", ErrRPCFailed, genesis.Data.GenesisValidatorsRoot)
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.genesisValidatorsRoot = genesisValidatorsRoot
+	store.finalizedSlot = int64(header.Slot)
+	store.current = committee
+	store.next = nil
+	store.record(int64(header.Slot), phase0.Root(root))
+	store.bootstrapped = true
+	return nil
+}
+
+// applyLightClientUpdate verifies an update against the sync committees of the store, records the headers it proves
+// and moves the store forward to its finalized header, following the update rules of the light client specification
+func (s *EthereumService) applyLightClientUpdate(ctx context.Context, update *lightClientUpdate) error {
+	store := s.lightClient
+	signatureSlot := parseDecimal(update.SignatureSlot)
+	attested := &update.AttestedHeader.Beacon
+
+	participants := int(update.SyncAggregate.SyncCommitteeBits.Count())
+	if participants*3 < SyncCommitteeSize*2 {
+		return fmt.Errorf("light client update of slot %d is signed by %d of %d sync committee members, below the supermajority", attested.Slot, participants, SyncCommitteeSize)
+	}
+	if int64(attested.Slot) >= signatureSlot {
+		return fmt.Errorf("%w: light client update of slot %d is signed at slot %d", ErrInconsistentResponse, attested.Slot, signatureSlot)
+	}
+	if update.FinalizedHeader != nil && update.FinalizedHeader.Beacon.Slot > attested.Slot {
+		return fmt.Errorf("%w: light client update of slot %d finalizes the later slot %d", ErrInconsistentResponse, attested.Slot, update.FinalizedHeader.Beacon.Slot)
+	}
+
+	// The fork schedule and the domain don't depend on the store, so resolve them before locking it
+	domain, err := s.syncCommitteeDomain(ctx, signatureSlot)
+	if err != nil {
+		return err
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	storePeriod := store.finalizedSlot / SlotsPerSyncPeriod
+	signaturePeriod := signatureSlot / SlotsPerSyncPeriod
+	var signers *syncCommitteeKeys
+	switch {
+	case signaturePeriod == storePeriod:
+		signers = store.current
+	case signaturePeriod == storePeriod+1 && store.next != nil:
+		signers = store.next
+	default:
+		return fmt.Errorf("light client update signed at slot %d is outside the sync committee periods known from slot %d", signatureSlot, store.finalizedSlot)
+	}
+
+	attestedRoot, err := attested.HashTreeRoot()
+	if err != nil {
+		return fmt.Errorf("failed to hash attested header: %w", err)
+	}
+
+	var finalizedRoot phase0.Root
+	if update.FinalizedHeader != nil {
+		if finalizedRoot, err = update.FinalizedHeader.Beacon.HashTreeRoot(); err != nil {
+			return fmt.Errorf("failed to hash finalized header: %w", err)
+		}
+		if !isValidMerkleBranch(finalizedRoot, update.FinalityBranch, finalizedRootIndex, attested.StateRoot) {
+			return fmt.Errorf("%w: invalid finality branch in light client update of slot %d", ErrInconsistentResponse, attested.Slot)
+		}
+	}
+
+	var next *syncCommitteeKeys
+	if update.NextSyncCommittee != nil {
+		if next, err = provenSyncCommittee(update.NextSyncCommittee, update.NextSyncCommitteeBranch, nextSyncCommitteeIndex, attested.StateRoot); err != nil {
+			return fmt.Errorf("invalid next sync committee in light client update of slot %d: %w", attested.Slot, err)
+		}
+	}
+
+	signingRoot, err := (&phase0.SigningData{ObjectRoot: attestedRoot, Domain: domain}).HashTreeRoot()
+	if err != nil {
+		return fmt.Errorf("failed to compute signing root: %w", err)
+	}
+	if err := verifySyncAggregate(signers, &update.SyncAggregate, signingRoot[:]); err != nil {
+		return fmt.Errorf("%w: light client update of slot %d: %v", ErrInconsistentResponse, attested.Slot, err)
+	}
+
+	store.record(int64(attested.Slot), attestedRoot)
+	attestedPeriod := int64(attested.Slot) / SlotsPerSyncPeriod
+	if next != nil && store.next == nil && attestedPeriod == storePeriod {
+		store.next = next
+	}
+	if update.FinalizedHeader == nil || int64(update.FinalizedHeader.Beacon.Slot) <= store.finalizedSlot {
+		return nil
+	}
+
+	finalizedSlot := int64(update.FinalizedHeader.Beacon.Slot)
+	store.record(finalizedSlot, finalizedRoot)
+	switch finalizedPeriod := finalizedSlot / SlotsPerSyncPeriod; finalizedPeriod {
+	case storePeriod:
+	case storePeriod + 1:
+		if store.next == nil {
+			return fmt.Errorf("light client update finalizes slot %d before the next sync committee is known", finalizedSlot)
+		}
+		store.current = store.next
+		store.next = nil
+		if next != nil && attestedPeriod == finalizedPeriod {
+			store.next = next
+		}
+	default:
+		return fmt.Errorf("light client update finalizes slot %d, more than a sync committee period after slot %d", finalizedSlot, store.finalizedSlot)
+	}
+	store.finalizedSlot = finalizedSlot
+	return nil
+}
+
+// syncCommitteeDomain returns the signature domain of sync committee messages signed at a slot. Committees sign the
+// block of the previous slot with the fork version of its epoch
+func (s *EthereumService) syncCommitteeDomain(ctx context.Context, signatureSlot int64) (phase0.Domain, error) {
+	forks, err := s.GetForkSchedule(ctx)
+	if err != nil {
+		return phase0.Domain{}, fmt.Errorf("failed to get fork schedule: %w", err)
+	}
+	epoch := (max(signatureSlot, 1) - 1) / SlotsPerEpoch
+	var version phase0.Version
+	found := false
+	for _, fork := range forks {
+		if parseDecimal(fork.Epoch) > epoch {
+			continue
+		}
+		decoded, err := hex.DecodeString(strings.TrimPrefix(fork.CurrentVersion, "0x"))
+		if err != nil || len(decoded) != len(version) {
+			return phase0.Domain{}, fmt.Errorf("%w: invalid fork version %q", ErrRPCFailed, fork.CurrentVersion)
+		}
+		copy(version[:], decoded)
+		found = true
+	}
+	if !found {
+		return phase0.Domain{}, fmt.Errorf("%w: no fork scheduled at epoch %d", ErrRPCFailed, epoch)
+	}
+
+	s.lightClient.mu.RLock()
+	genesisValidatorsRoot := s.lightClient.genesisValidatorsRoot
+	s.lightClient.mu.RUnlock()
+	forkDataRoot, err := (&phase0.ForkData{CurrentVersion: version, GenesisValidatorsRoot: genesisValidatorsRoot}).HashTreeRoot()
+	if err != nil {
+		return phase0.Domain{}, fmt.Errorf("failed to compute fork data root: %w", err)
+	}
+
+	var domain phase0.Domain
+	copy(domain[:4], domainSyncCommittee[:])
+	copy(domain[4:], forkDataRoot[:28])
+	return domain, nil
+}
+
+// record keeps a verified block root, dropping the oldest roots beyond maxLightClientRoots. The caller must hold the
+// lock of the store
+func (l *lightClientStore) record(slot int64, root phase0.Root) {
+	key := strings.ToLower(root.String())
+	if previous, ok := l.slots[slot]; ok {
+		delete(l.roots, previous)
+	}
+	l.roots[key] = slot
+	l.slots[slot] = key
+	l.latest = max(l.latest, slot)
+
+	if len(l.slots) <= maxLightClientRoots {
+		return
+	}
+	for old, oldRoot := range l.slots {
+		if old <= l.latest-maxLightClientRoots {
+			delete(l.slots, old)
+			delete(l.roots, oldRoot)
+		}
+	}
+}
+
+// anchor returns the lowest slot at or above a slot with a verified block root, reporting whether there is one. The
+// caller must hold the lock of the store
+func (l *lightClientStore) anchor(slot int64) (int64, string, bool) {
+	best := int64(-1)
+	for candidate := range l.slots {
+		if candidate >= slot && (best < 0 || candidate < best) {
+			best = candidate
+		}
+	}
+	if best < 0 {
+		return 0, "", false
+	}
+	return best, l.slots[best], true
+}
+
+// VerifyBlockRoot reports whether the block root served for a slot is proven by the light client. Roots of slots
+// shortly before a verified header are proven by walking the parent roots of the headers in between. A root that
+// contradicts the verified chain is reported as ErrInconsistentResponse, while slots the light client can't reach yet
+// are reported as unverified without error
+func (s *EthereumService) VerifyBlockRoot(ctx context.Context, slot int64, root string) (bool, error) {
+	store := s.lightClient
+	if store == nil {
+		return false, ErrLightClientDisabled
+	}
+
+	store.mu.RLock()
+	verified, known := store.slots[slot]
+	anchorSlot, expected, ok := store.anchor(slot)
+	store.mu.RUnlock()
+	if known {
+		if !strings.EqualFold(verified, root) {
+			return false, fmt.Errorf("%w: block root of slot %d is %s, the light client verified %s", ErrInconsistentResponse, slot, root, verified)
+		}
+		return true, nil
+	}
+	if !ok || anchorSlot-slot > lightClientAncestry {
+		return false, nil
+	}
+
+	for {
+		var response blockHeaderResponse
+		if err := s.getBeaconJSON(ctx, "/eth/v1/beacon/headers/"+expected, &response); err != nil {
+			return false, err
+		}
+		header, err := response.Data.Header.Message.beaconBlockHeader()
+		if err != nil {
+			return false, fmt.Errorf("%w: invalid header of block %s", ErrRPCFailed, expected)
+		}
+		headerRoot, err := header.HashTreeRoot()
+		if err != nil {
+			return false, fmt.Errorf("failed to hash header: %w", err)
+		}
+		if !strings.EqualFold(phase0.Root(headerRoot).String(), expected) {
+			return false, fmt.Errorf("%w: header of block %s hashes to %s", ErrInconsistentResponse, expected, phase0.Root(headerRoot))
+		}
+
+		headerSlot := int64(header.Slot)
+		store.mu.Lock()
+		store.record(headerSlot, headerRoot)
+		store.mu.Unlock()
+		switch {
+		case headerSlot == slot && strings.EqualFold(expected, root):
+			return true, nil
+		case headerSlot <= slot:
+			return false, fmt.Errorf("%w: block root of slot %d is %s, the light client verified %s at slot %d", ErrInconsistentResponse, slot, root, expected, headerSlot)
+		}
+		expected = strings.ToLower(header.ParentRoot.String())
+	}
+}
+
+// beaconBlockHeader decodes the header of a Beacon API header response
+func (h *beaconBlockHeaderJSON) beaconBlockHeader() (*phase0.BeaconBlockHeader, error) {
+	header := &phase0.BeaconBlockHeader{
+		Slot:          phase0.Slot(parseDecimal(h.Slot)),
+		ProposerIndex: phase0.ValidatorIndex(parseDecimal(h.ProposerIndex)),
+	}
+	var err error
+	if header.ParentRoot, err = decodeRoot(h.ParentRoot); err != nil {
+		return nil, err
+	}
+	if header.StateRoot, err = decodeRoot(h.StateRoot); err != nil {
+		return nil, err
+	}
+	if header.BodyRoot, err = decodeRoot(h.BodyRoot); err != nil {
+		return nil, err
+	}
+	return header, nil
+}
+
+// decodeRoot decodes a 0x-prefixed 32 byte root
+func decodeRoot(value string) (phase0.Root, error) {
+	var root phase0.Root
+	decoded, err := hex.DecodeString(strings.TrimPrefix(value, "0x"))
+	if err != nil {
+		return root, err
+	}
+	if len(decoded) != len(root) {
+		return root, fmt.Errorf("root has %d bytes", len(decoded))
+	}
+	copy(root[:], decoded)
+	return root, nil
+}
+
+// provenSyncCommittee decodes the pubkeys of a sync committee after checking its Merkle proof against a state root
+func provenSyncCommittee(committee *altair.SyncCommittee, branch []phase0.Root, index uint64, stateRoot phase0.Root) (*syncCommitteeKeys, error) {
+	if len(committee.Pubkeys) != SyncCommitteeSize {
+		return nil, fmt.Errorf("%w: sync committee has %d members", ErrInconsistentResponse, len(committee.Pubkeys))
+	}
+	root, err := committee.HashTreeRoot()
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash sync committee: %w", err)
+	}
+	if !isValidMerkleBranch(root, branch, index, stateRoot) {
+		return nil, fmt.Errorf("%w: sync committee is not part of state %s", ErrInconsistentResponse, stateRoot)
+	}
+
+	keys := &syncCommitteeKeys{pubkeys: make([]bls.G1Affine, len(committee.Pubkeys))}
+	for i, pubkey := range committee.Pubkeys {
+		if _, err := keys.pubkeys[i].SetBytes(pubkey[:]); err != nil {
+			return nil, fmt.Errorf("%w: invalid sync committee pubkey %s", ErrInconsistentResponse, pubkey)
+		}
+	}
+	return keys, nil
+}
+
+// isValidMerkleBranch checks the Merkle proof of a leaf at a subtree index of a root. The depth of the proof is the
+// length of its branch, which must be one of the two depths the light client objects used across forks
+func isValidMerkleBranch(leaf phase0.Root, branch []phase0.Root, index uint64, root phase0.Root) bool {
+	depth := bits.Len64(index)
+	if len(branch) != depth && len(branch) != depth+1 {
+		return false
+	}
+
+	value := leaf
+	for i, sibling := range branch {
+		if index>>i&1 == 1 {
+			value = sha256.Sum256(append(sibling[:], value[:]...))
+		} else {
+			value = sha256.Sum256(append(value[:], sibling[:]...))
+		}
+	}
+	return value == root
+}
+
+// verifySyncAggregate checks the aggregate signature of the participating members of a sync committee
+func verifySyncAggregate(committee *syncCommitteeKeys, aggregate *altair.SyncAggregate, message []byte) error {
+	var aggregatePubkey bls.G1Jac
+	for i := range committee.pubkeys {
+		if aggregate.SyncCommitteeBits.BitAt(uint64(i)) {
+			aggregatePubkey.AddMixed(&committee.pubkeys[i])
+		}
+	}
+	var pubkey bls.G1Affine
+	pubkey.FromJacobian(&aggregatePubkey)
+
+	var signature bls.G2Affine
+	if _, err := signature.SetBytes(aggregate.SyncCommitteeSignature[:]); err != nil {
+		return fmt.Errorf("invalid signature: %v", err)
+	}
+	hash, err := bls.HashToG2(message, []byte(syncCommitteeSignatureDST))
+	if err != nil {
+		return fmt.Errorf("failed to hash message: %v", err)
+	}
+
+	// e(pk, H(m)) == e(g1, sig)
+	_, _, generator, _ := bls.Generators()
+	var negGenerator bls.G1Affine
+	negGenerator.Neg(&generator)
+	ok, err := bls.PairingCheck([]bls.G1Affine{pubkey, negGenerator}, []bls.G2Affine{hash, signature})
+	if err != nil || !ok {
+		return errors.New("sync committee signature does not verify")
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"sync/atomic"
+)
+
+// engineAPIPort is the default port of the Engine API, which serves the consensus client rather than applications
+const engineAPIPort = "8551"
+
+// localNode is the state of the local node mode, for deployments next to their own full node
+type localNode struct {
+	enabled atomic.Bool
+}
+
+// lighthouseInclusionResponse represents the response from Lighthouse's /lighthouse/validator_inclusion/{epoch}/global
+type lighthouseInclusionResponse struct {
+	Data struct {
+		CurrentEpochActiveGwei          json.Number `json:"current_epoch_active_gwei"`
+		CurrentEpochTargetAttestingGwei json.Number `json:"current_epoch_target_attesting_gwei"`
+	} `json:"data"`
+}
+
+// EnableLocalNode turns the local node mode on or off. With a co-located full node the node-specific bulk endpoints
+// of the beacon node are used when it supports them, and the indexer follows new execution heads over websockets. It
+// must be called before the upstreams are connected
+func (s *EthereumService) EnableLocalNode(enabled bool) {
+	s.local.enabled.Store(enabled)
+}
+
+// LocalNode reports whether the local node mode is enabled
+func (s *EthereumService) LocalNode() bool {
+	return s.local.enabled.Load()
+}
+
+// lighthouseAPI reports whether the Lighthouse specific endpoints can be used
+func (s *EthereumService) lighthouseAPI() bool {
+	return s.LocalNode() && s.ConsensusClient() == ClientLighthouse
+}
+
+// warnEngineAPI logs when the execution endpoint looks like the Engine API, which only serves a few eth_ methods and
+// requires the JWT shared with the consensus client
+func warnEngineAPI(executionURL string) {
+	if parsedURL, err := url.Parse(executionURL); err == nil && parsedURL.Port() == engineAPIPort {
+		log.Printf("Warning: the execution endpoint uses the Engine API port %s, use the node's JSON-RPC port instead, e.g. ws://localhost:8546", engineAPIPort)
+	}
+}
+
+// getLighthouseParticipation returns the share of the active stake whose target vote was included in an epoch, from
+// Lighthouse's global validator inclusion instead of the rewards of every validator
+func (s *EthereumService) getLighthouseParticipation(ctx context.Context, epoch int64) (float64, error) {
+	var inclusion lighthouseInclusionResponse
+	if err := s.getBeaconJSON(ctx, fmt.Sprintf("/lighthouse/validator_inclusion/%d/global", epoch), &inclusion); err != nil {
+		return 0, err
+	}
+
+	active, err := inclusion.Data.CurrentEpochActiveGwei.Float64()
+	if err != nil {
+		return 0, fmt.Errorf("%w: active stake %q", ErrUpstreamInvalidResponse, inclusion.Data.CurrentEpochActiveGwei)
+	}
+	attesting, err := inclusion.Data.CurrentEpochTargetAttestingGwei.Float64()
+	if err != nil {
+		return 0, fmt.Errorf("%w: attesting stake %q", ErrUpstreamInvalidResponse, inclusion.Data.CurrentEpochTargetAttestingGwei)
+	}
+	if active == 0 {
+		return 0, nil
+	}
+	return attesting / active, nil
+}
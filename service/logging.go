@@ -0,0 +1,155 @@
+package service
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Log sampling used unless configured otherwise: the first 10 lines of a kind per minute are logged
+const (
+	DefaultLogSampleBurst    = 10
+	DefaultLogSampleInterval = time.Minute
+)
+
+var (
+	// secretURLs are the configured provider URLs, replaced by their redacted form wherever they show up
+	secretURLs   = make(map[string]bool)
+	secretURLsMu sync.RWMutex
+)
+
+// urlPattern matches the URLs within log lines and error messages
+var urlPattern = regexp.MustCompile(`\b(?:https?|wss?)://[^\s"'<>\\]+`)
+
+// RegisterSecretURLs marks provider URLs as secret, as providers embed API keys in them. Registered URLs are
+// replaced by their redacted form in logs and errors, even where a redacted URL could not be recognized as such
+func RegisterSecretURLs(rawURLs ...string) {
+	secretURLsMu.Lock()
+	defer secretURLsMu.Unlock()
+	for _, rawURL := range rawURLs {
+		if rawURL = strings.TrimSuffix(rawURL, "/"); rawURL != "" && RedactURL(rawURL) != rawURL {
+			secretURLs[rawURL] = true
+		}
+	}
+}
+
+// RedactSecrets removes API keys from text: registered provider URLs are redacted like RedactURL, and path segments
+// and query values that look like API keys are redacted from any other URL
+func RedactSecrets(text string) string {
+	secretURLsMu.RLock()
+	for rawURL := range secretURLs {
+		text = strings.ReplaceAll(text, rawURL, RedactURL(rawURL))
+	}
+	secretURLsMu.RUnlock()
+
+	return urlPattern.ReplaceAllStringFunc(text, func(match string) string {
+		parsedURL, err := url.Parse(match)
+		if err != nil || parsedURL.Host == "" {
+			return match
+		}
+		redacted, _ := redactUpstreamURL(parsedURL)
+		return redacted
+	})
+}
+
+// RedactingWriter is an io.Writer removing API keys from what is written to it with RedactSecrets, so secrets never
+// reach the logs
+type RedactingWriter struct {
+	next io.Writer
+}
+
+// NewRedactingWriter creates a RedactingWriter writing to next
+func NewRedactingWriter(next io.Writer) *RedactingWriter {
+	return &RedactingWriter{next: next}
+}
+
+func (w *RedactingWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(w.next, RedactSecrets(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// LogSampler limits how often log lines of the same kind are written: the first burst lines of a kind within an
+// interval are logged, the rest are counted and reported with the first line of the next interval
+type LogSampler struct {
+	mu       sync.Mutex
+	burst    int
+	interval time.Duration // Zero to log every line
+	kinds    map[string]*sampledKind
+}
+
+// sampledKind counts the lines of a kind within the current interval
+type sampledKind struct {
+	start      time.Time
+	logged     int
+	suppressed int
+}
+
+// NewLogSampler creates a LogSampler logging burst lines of a kind per interval, every line if interval is zero
+func NewLogSampler(burst int, interval time.Duration) *LogSampler {
+	return &LogSampler{burst: max(burst, 1), interval: interval, kinds: make(map[string]*sampledKind)}
+}
+
+// Printf logs a line of the given kind unless its kind was already logged burst times within the interval
+func (l *LogSampler) Printf(kind, format string, args ...interface{}) {
+	if l.interval <= 0 {
+		log.Printf(format, args...)
+		return
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	sampled, ok := l.kinds[kind]
+	if !ok || now.Sub(sampled.start) >= l.interval {
+		if ok && sampled.suppressed > 0 {
+			format += fmt.Sprintf(" (%d similar lines suppressed)", sampled.suppressed)
+		}
+		sampled = &sampledKind{start: now}
+		l.kinds[kind] = sampled
+	}
+	if sampled.logged >= l.burst {
+		sampled.suppressed++
+		l.mu.Unlock()
+		return
+	}
+	sampled.logged++
+	l.mu.Unlock()
+
+	log.Printf(format, args...)
+}
+
+var (
+	// logSampler samples the high-volume log lines
+	logSampler = NewLogSampler(DefaultLogSampleBurst, DefaultLogSampleInterval)
+	// debugLogs enables the debug lines, such as one per upstream request
+	debugLogs atomic.Bool
+)
+
+// SetLogSampling configures how many lines of a kind are logged per interval, every line if interval is zero
+func SetLogSampling(burst int, interval time.Duration) {
+	logSampler = NewLogSampler(burst, interval)
+}
+
+// SetDebugLogging turns the sampled debug lines on or off
+func SetDebugLogging(enabled bool) {
+	debugLogs.Store(enabled)
+}
+
+// sampledLogf logs a high-volume line of the given kind through the log sampler
+func sampledLogf(kind, format string, args ...interface{}) {
+	logSampler.Printf(kind, format, args...)
+}
+
+// debugLogf logs a debug line of the given kind through the log sampler while debug logging is enabled
+func debugLogf(kind, format string, args ...interface{}) {
+	if debugLogs.Load() {
+		logSampler.Printf(kind, "Debug: "+format, args...)
+	}
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrUnknownMetric is returned for time series that aren't served
+var ErrUnknownMetric = errors.New("unknown metric")
+
+// Time series of the indexed data. Validator metrics are suffixed with the validator index, e.g.
+// "attestation_reward:123456"
+const (
+	MetricBlockReward       = "block_reward"       // Proposer reward of each block in ETH
+	MetricMEVShare          = "mev_share"          // 1 for blocks built via MEV-Boost, 0 for vanilla blocks, averaged into a share
+	MetricBaseFee           = "base_fee"           // Base fee per gas of each block in Gwei
+	MetricGasUtilization    = "gas_utilization"    // Percentage of the gas limit each block used
+	MetricSyncParticipation = "sync_participation" // Share of the sync committee that signed each block
+	MetricAttestationReward = "attestation_reward" // Attestation reward of a validator in each epoch in Gwei
+)
+
+// DefaultMetricPoints is the number of points a time series is reduced to when the client doesn't ask for another
+const DefaultMetricPoints = 1000
+
+// MetricPoint is the value of a time series at a point in time
+type MetricPoint struct {
+	Time  time.Time
+	Value float64
+}
+
+// Metrics returns the names of the served time series, including the validator metrics of each validator with
+// indexed attestation performance
+func (s *EthereumService) Metrics() []string {
+	metrics := []string{MetricBlockReward, MetricMEVShare, MetricBaseFee, MetricGasUtilization, MetricSyncParticipation}
+	for _, validatorIndex := range s.index.AttestedValidators() {
+		metrics = append(metrics, fmt.Sprintf("%s:%d", MetricAttestationReward, validatorIndex))
+	}
+	return metrics
+}
+
+// GetMetricSeries returns a time series of the indexed data within [from, to], oldest first. Series with more than
+// maxPoints points are reduced by averaging consecutive points into buckets of equal duration
+func (s *EthereumService) GetMetricSeries(ctx context.Context, metric string, from, to time.Time, maxPoints int) ([]MetricPoint, error) {
+	if to.Before(from) || to.Sub(from) > maxStatsEpochs*SlotsPerEpoch*SecondsPerSlot*time.Second {
+		return nil, ErrInvalidRange
+	}
+	if maxPoints <= 0 {
+		maxPoints = DefaultMetricPoints
+	}
+
+	genesis, err := s.getGenesisTime(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get genesis time: %w", err)
+	}
+	slotTime := func(slot int64) time.Time {
+		return genesis.Add(time.Duration(slot*SecondsPerSlot) * time.Second)
+	}
+	fromSlot := max(0, int64(from.Sub(genesis)/(SecondsPerSlot*time.Second)))
+	toSlot := int64(to.Sub(genesis) / (SecondsPerSlot * time.Second))
+	if toSlot < 0 {
+		return make([]MetricPoint, 0), nil
+	}
+
+	var points []MetricPoint
+	name, validator, _ := strings.Cut(metric, ":")
+	switch name {
+	case MetricBlockReward, MetricMEVShare:
+		if validator != "" {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownMetric, metric)
+		}
+		for _, slot := range s.index.RewardSlots(fromSlot, toSlot) {
+			reward, ok := s.index.GetReward(slot)
+			if !ok || reward.Reward == nil {
+				continue
+			}
+			value := 0.0
+			switch {
+			case name == MetricBlockReward:
+				value, _ = new(big.Float).Quo(new(big.Float).SetInt(reward.Wei()), big.NewFloat(1e18)).Float64()
+			case reward.Status == "mev":
+				value = 1
+			}
+			points = append(points, MetricPoint{Time: slotTime(slot), Value: value})
+		}
+	case MetricBaseFee, MetricGasUtilization, MetricSyncParticipation:
+		if validator != "" {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownMetric, metric)
+		}
+		for _, block := range s.index.Range(fromSlot, toSlot, nil) {
+			var value float64
+			switch name {
+			case MetricBaseFee:
+				if block.BaseFee == nil {
+					continue
+				}
+				value, _ = new(big.Float).Quo(new(big.Float).SetInt(block.BaseFee), big.NewFloat(1e9)).Float64()
+			case MetricGasUtilization:
+				if block.GasLimit <= 0 {
+					continue
+				}
+				value = gasUtilization(block)
+			case MetricSyncParticipation:
+				value = float64(block.SyncParticipation) / SyncCommitteeSize
+			}
+			points = append(points, MetricPoint{Time: slotTime(block.Slot), Value: value})
+		}
+	case MetricAttestationReward:
+		validatorIndex, err := strconv.ParseInt(validator, 10, 64)
+		if err != nil || validatorIndex < 0 {
+			return nil, fmt.Errorf("%w: %s needs a validator index, e.g. %s:123456", ErrUnknownMetric, metric, MetricAttestationReward)
+		}
+		for _, perf := range s.index.Attestations(validatorIndex, fromSlot/SlotsPerEpoch, toSlot/SlotsPerEpoch) {
+			points = append(points, MetricPoint{Time: slotTime(perf.Epoch * SlotsPerEpoch), Value: float64(perf.Reward)})
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownMetric, metric)
+	}
+
+	return downsampleMetric(points, from, to, maxPoints), nil
+}
+
+// downsampleMetric averages points into at most maxPoints buckets of equal duration within [from, to], each at the
+// time of its first point. Series short enough are returned as they are
+func downsampleMetric(points []MetricPoint, from, to time.Time, maxPoints int) []MetricPoint {
+	if len(points) <= maxPoints {
+		return append(make([]MetricPoint, 0, len(points)), points...)
+	}
+
+	width := to.Sub(from)/time.Duration(maxPoints) + 1
+	bucketOf := func(point MetricPoint) int64 {
+		return int64(point.Time.Sub(from) / width)
+	}
+
+	reduced := make([]MetricPoint, 0, maxPoints)
+	for start := 0; start < len(points); {
+		end := start + sort.Search(len(points)-start, func(i int) bool {
+			return bucketOf(points[start+i]) != bucketOf(points[start])
+		})
+		sum := 0.0
+		for _, point := range points[start:end] {
+			sum += point.Value
+		}
+		reduced = append(reduced, MetricPoint{Time: points[start].Time, Value: sum / float64(end-start)})
+		start = end
+	}
+	return reduced
+}
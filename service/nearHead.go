@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// nearHeadTTL is how long the result for a slot that isn't finalized yet is served before it is refreshed
+const nearHeadTTL = SlotsPerEpoch * SecondsPerSlot * time.Second
+
+// finalizedSlotTTL is how long the result for a finalized slot is kept
+const finalizedSlotTTL = 10 * time.Minute
+
+// revalidateTimeout bounds background refreshes, which outlive the request that triggered them
+const revalidateTimeout = 30 * time.Second
+
+// slotResult is a cached slot lookup and whether the slot was finalized when it was fetched
+type slotResult struct {
+	value     interface{}
+	finalized bool
+	root      string // Block root of the slot looked up before the value was fetched, "" if unknown
+}
+
+// getSlotCached serves slot lookups from the cache, results of finalized slots are kept for finalizedSlotTTL. Cached
+// results of slots that aren't finalized yet are returned immediately and refreshed in the background once they
+// expire or their slot is finalized, so a reorg near the head is eventually reflected. Expired results are only
+// fetched again when the block root of their slot changed.
+// fetch is told whether the slot is finalized
+func (s *EthereumService) getSlotCached(ctx context.Context, key string, slot int64, fetch func(ctx context.Context, finalized bool) (interface{}, error)) (interface{}, error) {
+	finalized := s.isSlotFinalized(ctx, slot)
+
+	entry, ok := s.cache.getStaleEntry(key)
+	ProvenanceFrom(ctx).recordCache(ok, entry.stored)
+	if ok {
+		fresh := s.clock.Now().Before(entry.expires)
+		result := entry.value.(slotResult)
+		if !result.finalized {
+			if !fresh || finalized {
+				s.cache.Revalidate(key, func() {
+					ctx, cancel := context.WithTimeout(WithPriority(context.Background(), PriorityBatch), revalidateTimeout)
+					defer cancel()
+					if err := s.refreshSlot(ctx, key, slot, result, fetch); err != nil {
+						sampledLogf("cache refresh", "Cache: failed to refresh %s: %v", key, err)
+					}
+				})
+			}
+			return result.value, nil
+		}
+		if fresh {
+			return result.value, nil
+		}
+	}
+
+	// Finalized slots can't change anymore and are kept without a root. The root of slots near the head is
+	// looked up first, so a reorg while the value is fetched makes the next refresh fetch it again
+	if finalized {
+		value, err := fetch(ctx, finalized)
+		if err != nil {
+			return nil, err
+		}
+		s.cache.Set(key, slotResult{value: value, finalized: true}, finalizedSlotTTL)
+		return value, nil
+	}
+	root := s.slotBlockRoot(ctx, slot)
+	value, err := fetch(ctx, finalized)
+	if err != nil {
+		return nil, err
+	}
+	s.cache.Set(key, slotResult{value: value, root: root}, nearHeadTTL)
+	return value, nil
+}
+
+// refreshSlot fetches a cached slot lookup again, keeping the result for longer once the slot is finalized. Results
+// whose block root is unchanged are kept as they are until their slot is finalized, saving the refetch
+func (s *EthereumService) refreshSlot(ctx context.Context, key string, slot int64, previous slotResult, fetch func(ctx context.Context, finalized bool) (interface{}, error)) error {
+	// Providers supporting conditional requests don't send unchanged responses again
+	ctx = WithConditional(ctx)
+
+	finalized := s.isSlotFinalized(ctx, slot)
+	root := s.slotBlockRoot(ctx, slot)
+	if !finalized && root != "" && root == previous.root {
+		s.cache.Set(key, previous, nearHeadTTL)
+		return nil
+	}
+
+	value, err := fetch(ctx, finalized)
+	if err != nil {
+		return err
+	}
+
+	ttl := nearHeadTTL
+	if finalized {
+		ttl = finalizedSlotTTL
+	}
+	s.cache.Set(key, slotResult{value: value, finalized: finalized, root: root}, ttl)
+	return nil
+}
+
+// slotBlockRoot returns the root of the block at a slot, "" if the slot has no block or the lookup failed
+func (s *EthereumService) slotBlockRoot(ctx context.Context, slot int64) string {
+	var root blockRootResponse
+	if err := s.getBeaconJSON(ctx, fmt.Sprintf("/eth/v1/beacon/blocks/%d/root", slot), &root); err != nil {
+		return ""
+	}
+	return root.Data.Root
+}
+
+// isSlotFinalized reports whether slot is at or before the finalized checkpoint, slots are treated
+// as not finalized while the checkpoint is unknown
+func (s *EthereumService) isSlotFinalized(ctx context.Context, slot int64) bool {
+	head, err := s.GetChainHead(ctx)
+	if err != nil {
+		return false
+	}
+	return slot <= head.Finalized.Epoch*SlotsPerEpoch
+}
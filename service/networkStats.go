@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// ErrNetworkStatsUnavailable is returned until the network statistics have been computed once
+var ErrNetworkStatsUnavailable = errors.New("network statistics not available yet")
+
+// networkParticipationEpochs is the number of recent epochs the participation history keeps
+const networkParticipationEpochs = 10
+
+// EpochParticipation represents the attestation participation of an epoch
+type EpochParticipation struct {
+	Epoch int64
+	Rate  float64 // Share of active validators whose target vote was rewarded, between 0 and 1
+}
+
+// NetworkStats summarizes the active validator set and its recent participation
+type NetworkStats struct {
+	Epoch                 int64
+	ActiveValidators      int
+	TotalEffectiveBalance int64                // in GWEI
+	Participation         []EpochParticipation // Oldest epoch first
+	UpdatedAt             time.Time
+}
+
+// GetNetworkStats returns the latest network statistics computed by the network stats task
+func (s *EthereumService) GetNetworkStats() (*NetworkStats, error) {
+	s.networkMu.RLock()
+	defer s.networkMu.RUnlock()
+	if s.networkStats == nil {
+		return nil, ErrNetworkStatsUnavailable
+	}
+	return s.networkStats, nil
+}
+
+// refreshNetworkStats recomputes the active set and the participation of the latest rewarded epoch
+func (s *EthereumService) refreshNetworkStats(ctx context.Context) error {
+	active, err := s.refreshActiveSet(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get active validators: %w", err)
+	}
+
+	headSlot, err := s.getHeadSlot(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get head slot: %w", err)
+	}
+
+	epoch := lastRewardedEpoch(headSlot)
+	stats := &NetworkStats{
+		Epoch:                 headSlot / SlotsPerEpoch,
+		ActiveValidators:      active.Validators,
+		TotalEffectiveBalance: active.TotalEffectiveBalance,
+		UpdatedAt:             time.Now(),
+	}
+
+	// Carry over the participation history, only fetching epochs that are not known yet
+	if previous, err := s.GetNetworkStats(); err == nil {
+		for _, participation := range previous.Participation {
+			if participation.Epoch > epoch-networkParticipationEpochs {
+				stats.Participation = append(stats.Participation, participation)
+			}
+		}
+	}
+	if len(stats.Participation) == 0 || stats.Participation[len(stats.Participation)-1].Epoch < epoch {
+		rate, err := s.getEpochParticipation(ctx, epoch)
+		if err != nil {
+			return fmt.Errorf("failed to get participation of epoch %d: %w", epoch, err)
+		}
+		stats.Participation = append(stats.Participation, EpochParticipation{Epoch: epoch, Rate: rate})
+	}
+
+	s.networkMu.Lock()
+	defer s.networkMu.Unlock()
+	s.networkStats = stats
+	return nil
+}
+
+// getEpochParticipation returns the share of validators whose target vote was rewarded in an epoch, or the share of
+// the active stake on a local Lighthouse node
+func (s *EthereumService) getEpochParticipation(ctx context.Context, epoch int64) (float64, error) {
+	// A local Lighthouse node sums the attesting stake itself, sparing the rewards of the whole validator set
+	if s.lighthouseAPI() {
+		participation, err := s.getLighthouseParticipation(ctx, epoch)
+		if err == nil {
+			return participation, nil
+		}
+		log.Printf("Local node: Lighthouse validator inclusion of epoch %d failed, using the standard API: %v", epoch, err)
+	}
+
+	// An empty validator list requests the rewards of every active validator
+	var rewards attestationRewardsResponse
+	if err := s.postBeaconJSON(ctx, fmt.Sprintf("/eth/v1/beacon/rewards/attestations/%d", epoch), []string{}, &rewards); err != nil {
+		return 0, err
+	}
+	if len(rewards.Data.TotalRewards) == 0 {
+		return 0, nil
+	}
+
+	participating := 0
+	for _, reward := range rewards.Data.TotalRewards {
+		if parseDecimal(reward.Target) > 0 {
+			participating++
+		}
+	}
+	return float64(participating) / float64(len(rewards.Data.TotalRewards)), nil
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// nodeStatusTTL is how long sync status and peer counts are cached, short enough for monitoring to see changes
+const nodeStatusTTL = 5 * time.Second
+
+// nodeVersionTTL is how long node versions are cached, they only change with client upgrades
+const nodeVersionTTL = time.Minute
+
+// NodeSyncing represents the sync status of the beacon and execution nodes
+type NodeSyncing struct {
+	Beacon    BeaconSyncing
+	Execution ExecutionSyncing
+}
+
+// BeaconSyncing represents the sync status reported by the beacon node
+type BeaconSyncing struct {
+	HeadSlot     int64
+	SyncDistance int64 // Slots the node's head is behind the current slot
+	IsSyncing    bool
+	IsOptimistic bool // Whether the head was imported before the execution node validated its payload
+	ELOffline    bool // Whether the beacon node can't reach its execution node
+}
+
+// ExecutionSyncing represents the sync status reported by the execution node
+type ExecutionSyncing struct {
+	IsSyncing    bool
+	CurrentBlock int64 // Latest block number, the block being synced while syncing
+	HighestBlock int64 // Highest block number known while syncing, the latest block otherwise
+}
+
+// NodeVersion represents the client versions of the beacon and execution nodes
+type NodeVersion struct {
+	Beacon    string
+	Execution string
+}
+
+// NodePeers represents the peers of the beacon and execution nodes
+type NodePeers struct {
+	BeaconConnected     int64
+	BeaconConnecting    int64
+	BeaconDisconnected  int64
+	BeaconDisconnecting int64
+	ExecutionConnected  int64
+}
+
+// nodeSyncingResponse represents the response from /eth/v1/node/syncing
+type nodeSyncingResponse struct {
+	Data struct {
+		HeadSlot     string `json:"head_slot"`
+		SyncDistance string `json:"sync_distance"`
+		IsSyncing    bool   `json:"is_syncing"`
+		IsOptimistic bool   `json:"is_optimistic"`
+		ELOffline    bool   `json:"el_offline"`
+	} `json:"data"`
+}
+
+// executionSyncingResult represents the result of eth_syncing while the execution node is syncing
+type executionSyncingResult struct {
+	CurrentBlock hexutil.Uint64 `json:"currentBlock"`
+	HighestBlock hexutil.Uint64 `json:"highestBlock"`
+}
+
+// peerCountResponse represents the response from /eth/v1/node/peer_count
+type peerCountResponse struct {
+	Data struct {
+		Connected     string `json:"connected"`
+		Connecting    string `json:"connecting"`
+		Disconnected  string `json:"disconnected"`
+		Disconnecting string `json:"disconnecting"`
+	} `json:"data"`
+}
+
+// GetNodeSyncing retrieves the sync status of the beacon and execution nodes, cached for a few seconds
+func (s *EthereumService) GetNodeSyncing(ctx context.Context) (*NodeSyncing, error) {
+	if cached, ok := s.cacheGet(ctx, "node:syncing"); ok {
+		return cached.(*NodeSyncing), nil
+	}
+
+	var beacon nodeSyncingResponse
+	if err := s.getBeaconJSON(ctx, "/eth/v1/node/syncing", &beacon); err != nil {
+		return nil, err
+	}
+	syncing := &NodeSyncing{
+		Beacon: BeaconSyncing{
+			HeadSlot:     parseDecimal(beacon.Data.HeadSlot),
+			SyncDistance: parseDecimal(beacon.Data.SyncDistance),
+			IsSyncing:    beacon.Data.IsSyncing,
+			IsOptimistic: beacon.Data.IsOptimistic,
+			ELOffline:    beacon.Data.ELOffline,
+		},
+	}
+
+	// eth_syncing returns false once the node is synced, the latest block is then both the current and highest block
+	var raw json.RawMessage
+	if err := s.callRPC(ctx, "eth_syncing", nil, &raw); err != nil {
+		return nil, err
+	}
+	if string(raw) == "false" {
+		var blockNumber hexutil.Uint64
+		if err := s.callRPC(ctx, "eth_blockNumber", nil, &blockNumber); err != nil {
+			return nil, err
+		}
+		syncing.Execution = ExecutionSyncing{CurrentBlock: int64(blockNumber), HighestBlock: int64(blockNumber)}
+	} else {
+		var result executionSyncingResult
+		if err := json.Unmarshal(raw, &result); err != nil {
+			return nil, fmt.Errorf("%w: eth_syncing result %s", ErrUpstreamInvalidResponse, raw)
+		}
+		syncing.Execution = ExecutionSyncing{IsSyncing: true, CurrentBlock: int64(result.CurrentBlock), HighestBlock: int64(result.HighestBlock)}
+	}
+
+	s.cache.Set("node:syncing", syncing, nodeStatusTTL)
+	return syncing, nil
+}
+
+// GetNodeVersion retrieves the client versions of the beacon and execution nodes, cached for a minute
+func (s *EthereumService) GetNodeVersion(ctx context.Context) (*NodeVersion, error) {
+	if cached, ok := s.cacheGet(ctx, "node:version"); ok {
+		return cached.(*NodeVersion), nil
+	}
+
+	var raw json.RawMessage
+	if err := s.getBeaconJSON(ctx, "/eth/v1/node/version", &raw); err != nil {
+		return nil, err
+	}
+	var beacon nodeVersionResponse
+	if err := json.Unmarshal(raw, &beacon); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUpstreamInvalidResponse, err)
+	}
+	// The detected client follows the node, e.g. after an upgrade
+	s.recordBeaconVersion(raw)
+
+	version := &NodeVersion{Beacon: beacon.Data.Version}
+	if err := s.callRPC(ctx, "web3_clientVersion", nil, &version.Execution); err != nil {
+		return nil, err
+	}
+
+	s.cache.Set("node:version", version, nodeVersionTTL)
+	return version, nil
+}
+
+// GetNodePeers retrieves the peer counts of the beacon and execution nodes, cached for a few seconds
+func (s *EthereumService) GetNodePeers(ctx context.Context) (*NodePeers, error) {
+	if cached, ok := s.cacheGet(ctx, "node:peers"); ok {
+		return cached.(*NodePeers), nil
+	}
+
+	var beacon peerCountResponse
+	if err := s.getBeaconJSON(ctx, "/eth/v1/node/peer_count", &beacon); err != nil {
+		return nil, err
+	}
+	var executionPeers hexutil.Uint64
+	if err := s.callRPC(ctx, "net_peerCount", nil, &executionPeers); err != nil {
+		return nil, err
+	}
+
+	peers := &NodePeers{
+		BeaconConnected:     parseDecimal(beacon.Data.Connected),
+		BeaconConnecting:    parseDecimal(beacon.Data.Connecting),
+		BeaconDisconnected:  parseDecimal(beacon.Data.Disconnected),
+		BeaconDisconnecting: parseDecimal(beacon.Data.Disconnecting),
+		ExecutionConnected:  int64(executionPeers),
+	}
+
+	s.cache.Set("node:peers", peers, nodeStatusTTL)
+	return peers, nil
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultTelegramURL is the Telegram Bot API that messages are sent through
+const DefaultTelegramURL = "https://api.telegram.org"
+
+// Notification is a message delivered to a notification channel
+type Notification struct {
+	Title   string
+	Message string
+}
+
+// text renders the notification as a single plain text message
+func (n Notification) text() string {
+	if n.Title == "" {
+		return n.Message
+	}
+	return n.Title + "\n" + n.Message
+}
+
+// Notifier delivers notifications to a channel
+type Notifier interface {
+	Notify(ctx context.Context, notification Notification) error
+}
+
+// TelegramNotifier sends notifications as messages of a Telegram bot to a chat
+type TelegramNotifier struct {
+	client  *http.Client
+	baseURL string
+	token   string
+	chatID  string
+}
+
+// NewTelegramNotifier creates a TelegramNotifier sending through the Bot API at baseURL with the token of a bot
+func NewTelegramNotifier(client *http.Client, baseURL, token, chatID string) *TelegramNotifier {
+	return &TelegramNotifier{
+		client:  client,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+		chatID:  chatID,
+	}
+}
+
+// Notify sends the notification to the chat
+func (n *TelegramNotifier) Notify(ctx context.Context, notification Notification) error {
+	body := map[string]string{"chat_id": n.chatID, "text": notification.text()}
+	if err := postNotification(ctx, n.client, n.baseURL+"/bot"+n.token+"/sendMessage", body); err != nil {
+		return fmt.Errorf("telegram: %w", err)
+	}
+	return nil
+}
+
+// DiscordNotifier sends notifications to a Discord channel webhook
+type DiscordNotifier struct {
+	client     *http.Client
+	webhookURL string
+}
+
+// NewDiscordNotifier creates a DiscordNotifier for a webhook URL
+func NewDiscordNotifier(client *http.Client, webhookURL string) (*DiscordNotifier, error) {
+	if err := validateURL("Discord webhook", webhookURL); err != nil {
+		return nil, err
+	}
+	return &DiscordNotifier{client: client, webhookURL: webhookURL}, nil
+}
+
+// Notify posts the notification to the webhook
+func (n *DiscordNotifier) Notify(ctx context.Context, notification Notification) error {
+	if err := postNotification(ctx, n.client, n.webhookURL, map[string]string{"content": notification.text()}); err != nil {
+		return fmt.Errorf("discord: %w", err)
+	}
+	return nil
+}
+
+// ParseNotifier creates the Notifier of a channel given as "telegram:<bot token>:<chat id>" or "discord:<webhook URL>"
+func ParseNotifier(client *http.Client, channel string) (Notifier, error) {
+	kind, target, _ := strings.Cut(channel, ":")
+	switch kind {
+	case "telegram":
+		// Bot tokens contain a colon themselves, the chat ID follows the last one
+		i := strings.LastIndex(target, ":")
+		if i <= 0 || i == len(target)-1 || !strings.Contains(target[:i], ":") {
+			return nil, fmt.Errorf("telegram channel must be telegram:<bot token>:<chat id>")
+		}
+		return NewTelegramNotifier(client, DefaultTelegramURL, target[:i], target[i+1:]), nil
+	case "discord":
+		return NewDiscordNotifier(client, target)
+	default:
+		return nil, fmt.Errorf("unknown notification channel %q, expected telegram or discord", kind)
+	}
+}
+
+// postNotification POSTs a JSON body, accepting any 2xx response
+func postNotification(ctx context.Context, client *http.Client, target string, body interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", target, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		// Leave out the URL, it carries the credentials of the channel
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("notification rejected with status %d", resp.StatusCode)
+	}
+	return nil
+}
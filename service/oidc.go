@@ -0,0 +1,335 @@
+package service
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrInvalidToken is returned for JWTs that are malformed, not signed by the provider, expired or not meant for us
+var ErrInvalidToken = errors.New("invalid token")
+
+const (
+	// jwksTTL is how long the signing keys of the provider are used before they are fetched again
+	jwksTTL = time.Hour
+
+	// jwksMinRefresh bounds how often tokens signed with an unknown key make the keys be fetched again, so forged
+	// key IDs can't hammer the provider
+	jwksMinRefresh = time.Minute
+
+	// jwtLeeway is the clock skew tolerated when checking the expiry and not before times of tokens
+	jwtLeeway = time.Minute
+)
+
+// OIDCConfig identifies the OpenID Connect provider whose JWTs are accepted besides API keys
+type OIDCConfig struct {
+	Issuer   string // Expected iss claim
+	Audience string // Expected to be one of the aud claim
+	JWKSURL  string // JSON Web Key Set of the provider's signing keys
+}
+
+// JWTClaims are the verified claims of a JWT
+type JWTClaims struct {
+	Subject   string
+	Issuer    string
+	Audience  []string
+	ExpiresAt time.Time
+}
+
+// JWTVerifier verifies JWTs issued by an OIDC provider against its published signing keys. RSA (RS256, RS384,
+// RS512, PS256, PS384, PS512) and ECDSA (ES256, ES384, ES512) signatures are supported
+type JWTVerifier struct {
+	config OIDCConfig
+	client *http.Client
+	clock  Clock
+
+	mu        sync.Mutex
+	keys      map[string]crypto.PublicKey // Signing keys by key ID
+	fetched   time.Time                   // When the keys were last fetched, zero before
+	attempted time.Time                   // When fetching the keys was last attempted, successfully or not
+}
+
+// NewJWTVerifier creates a JWTVerifier for the given provider, fetching its keys on first use
+func NewJWTVerifier(config OIDCConfig) (*JWTVerifier, error) {
+	if config.Issuer == "" || config.Audience == "" {
+		return nil, fmt.Errorf("OIDC issuer and audience are required")
+	}
+	if err := validateURL("OIDC JWKS", config.JWKSURL); err != nil {
+		return nil, err
+	}
+
+	return &JWTVerifier{
+		config: config,
+		client: &http.Client{
+			Timeout: time.Second * 10,
+		},
+		clock: SystemClock{},
+		keys:  make(map[string]crypto.PublicKey),
+	}, nil
+}
+
+// SetClock replaces the clock tokens are checked against
+func (v *JWTVerifier) SetClock(clock Clock) {
+	v.clock = clock
+}
+
+// jwtHeader is the JOSE header of a JWT
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// jwtAudience is the aud claim, a single audience or a list of them
+type jwtAudience []string
+
+func (a *jwtAudience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = jwtAudience{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*a = list
+	return nil
+}
+
+// jwtPayload is the registered claims of a JWT that are checked
+type jwtPayload struct {
+	Issuer    string      `json:"iss"`
+	Subject   string      `json:"sub"`
+	Audience  jwtAudience `json:"aud"`
+	ExpiresAt *int64      `json:"exp"`
+	NotBefore *int64      `json:"nbf"`
+}
+
+// Verify checks the signature, issuer, audience and validity period of a JWT and returns its claims
+func (v *JWTVerifier) Verify(ctx context.Context, token string) (*JWTClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: not a JWT", ErrInvalidToken)
+	}
+
+	var header jwtHeader
+	if err := decodeJWTPart(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("%w: malformed header", ErrInvalidToken)
+	}
+	hash, ok := jwtHashes[header.Alg]
+	if !ok {
+		return nil, fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidToken, header.Alg)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed signature", ErrInvalidToken)
+	}
+
+	key, err := v.signingKey(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	hasher := hash.New()
+	hasher.Write([]byte(parts[0] + "." + parts[1]))
+	if !verifyJWTSignature(header.Alg, key, hash, hasher.Sum(nil), signature) {
+		return nil, fmt.Errorf("%w: bad signature", ErrInvalidToken)
+	}
+
+	var payload jwtPayload
+	if err := decodeJWTPart(parts[1], &payload); err != nil {
+		return nil, fmt.Errorf("%w: malformed claims", ErrInvalidToken)
+	}
+	now := v.clock.Now()
+	switch {
+	case payload.Issuer != v.config.Issuer:
+		return nil, fmt.Errorf("%w: issued by %q", ErrInvalidToken, payload.Issuer)
+	case !slices.Contains(payload.Audience, v.config.Audience):
+		return nil, fmt.Errorf("%w: not issued for this API", ErrInvalidToken)
+	case payload.ExpiresAt == nil || now.After(time.Unix(*payload.ExpiresAt, 0).Add(jwtLeeway)):
+		return nil, fmt.Errorf("%w: expired", ErrInvalidToken)
+	case payload.NotBefore != nil && now.Before(time.Unix(*payload.NotBefore, 0).Add(-jwtLeeway)):
+		return nil, fmt.Errorf("%w: not valid yet", ErrInvalidToken)
+	}
+
+	return &JWTClaims{
+		Subject:   payload.Subject,
+		Issuer:    payload.Issuer,
+		Audience:  payload.Audience,
+		ExpiresAt: time.Unix(*payload.ExpiresAt, 0).UTC(),
+	}, nil
+}
+
+// signingKey returns the provider key with the given ID, fetching the keys when they expired or when the key is
+// unknown, as the provider may have rotated its keys. Fetches are attempted at most every jwksMinRefresh. Tokens
+// without a key ID are accepted while the provider publishes a single key
+func (v *JWTVerifier) signingKey(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	now := v.clock.Now()
+	key, ok := v.lookupKey(kid)
+	stale := now.Sub(v.fetched) >= jwksTTL
+	if (!ok || stale) && now.Sub(v.attempted) >= jwksMinRefresh {
+		v.attempted = now
+		keys, err := v.fetchKeys(ctx)
+		if err != nil {
+			// Keys fetched before keep being used while the provider is unreachable
+			if !ok {
+				return nil, err
+			}
+			sampledLogf("oidc keys", "OIDC: failed to refresh signing keys: %v", err)
+			return key, nil
+		}
+		v.keys, v.fetched = keys, now
+		key, ok = v.lookupKey(kid)
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown signing key %q", ErrInvalidToken, kid)
+	}
+	return key, nil
+}
+
+// lookupKey returns the cached key with the given ID, or the only cached key for tokens without an ID
+func (v *JWTVerifier) lookupKey(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(v.keys) == 1 {
+		for _, key := range v.keys {
+			return key, true
+		}
+	}
+	key, ok := v.keys[kid]
+	return key, ok
+}
+
+// jsonWebKey is a key of a JSON Web Key Set, only the members of RSA and EC signing keys are read
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// fetchKeys downloads the signing keys of the provider, keys of other types or uses are skipped
+func (v *JWTVerifier) fetchKeys(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.config.JWKSURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create JWKS request: %w", err)
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch JWKS: unexpected status %d", resp.StatusCode)
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("failed to decode JWKS: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		if key, err := jwk.publicKey(); err == nil {
+			keys[jwk.Kid] = key
+		}
+	}
+	return keys, nil
+}
+
+// publicKey decodes an RSA or EC public key
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil || len(e) == 0 || len(e) > 4 {
+			return nil, fmt.Errorf("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		curves := map[string]elliptic.Curve{"P-256": elliptic.P256(), "P-384": elliptic.P384(), "P-521": elliptic.P521()}
+		curve, ok := curves[k.Crv]
+		if !ok {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		key := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(key.X, key.Y) {
+			return nil, fmt.Errorf("point not on curve")
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+// jwtHashes are the hashes of the supported signature algorithms. Unsigned and HMAC tokens are never accepted
+var jwtHashes = map[string]crypto.Hash{
+	"RS256": crypto.SHA256, "RS384": crypto.SHA384, "RS512": crypto.SHA512,
+	"PS256": crypto.SHA256, "PS384": crypto.SHA384, "PS512": crypto.SHA512,
+	"ES256": crypto.SHA256, "ES384": crypto.SHA384, "ES512": crypto.SHA512,
+}
+
+// verifyJWTSignature checks a signature of the given algorithm over a digest, the key must match the algorithm
+func verifyJWTSignature(alg string, key crypto.PublicKey, hash crypto.Hash, digest, signature []byte) bool {
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		switch alg[:2] {
+		case "RS":
+			return rsa.VerifyPKCS1v15(key, hash, digest, signature) == nil
+		case "PS":
+			return rsa.VerifyPSS(key, hash, digest, signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}) == nil
+		}
+	case *ecdsa.PublicKey:
+		// ECDSA signatures are the fixed size big-endian r and s
+		size := (key.Curve.Params().BitSize + 7) / 8
+		if alg[:2] != "ES" || len(signature) != 2*size {
+			return false
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		return ecdsa.Verify(key, digest, r, s)
+	}
+	return false
+}
+
+// decodeJWTPart decodes a base64url encoded JSON part of a JWT
+func decodeJWTPart(part string, out interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
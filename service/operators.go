@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// PoolOther is the pool reported for proposers that are in none of the loaded operator key lists
+const PoolOther = "other"
+
+// OperatorKeyList is a published list of the validator pubkeys run by a node operator of a staking pool
+type OperatorKeyList struct {
+	Pool     string   `json:"pool"`     // Staking pool, e.g. "Lido" or "Rocket Pool"
+	Operator string   `json:"operator"` // Node operator within the pool, "" if the pool does not publish it
+	Pubkeys  []string `json:"pubkeys"`
+}
+
+// ValidatorOperator identifies the staking pool and node operator running a validator
+type ValidatorOperator struct {
+	Pool     string
+	Operator string
+}
+
+// OperatorRegistry maps validator pubkeys to the staking pool and node operator running them
+type OperatorRegistry struct {
+	mu        sync.RWMutex
+	operators map[string]ValidatorOperator // keyed by lower-cased pubkey
+}
+
+// NewOperatorRegistry creates an OperatorRegistry from the given key lists
+func NewOperatorRegistry(lists []OperatorKeyList) *OperatorRegistry {
+	r := &OperatorRegistry{
+		operators: make(map[string]ValidatorOperator),
+	}
+	for _, list := range lists {
+		r.Put(list)
+	}
+	return r
+}
+
+// LoadOperatorRegistry creates an OperatorRegistry from a JSON file containing a list of operator key lists
+func LoadOperatorRegistry(path string) (*OperatorRegistry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open operator keys file: %w", err)
+	}
+	defer file.Close()
+
+	lists, err := decodeOperatorKeyLists(file)
+	if err != nil {
+		return nil, err
+	}
+	return NewOperatorRegistry(lists), nil
+}
+
+// Put tags the pubkeys of a key list with its pool and operator, replacing earlier tags of the same pubkeys
+func (r *OperatorRegistry) Put(list OperatorKeyList) {
+	operator := ValidatorOperator{Pool: list.Pool, Operator: list.Operator}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, pubkey := range list.Pubkeys {
+		r.operators[strings.ToLower(pubkey)] = operator
+	}
+}
+
+// Lookup returns the pool and operator running a validator, reporting whether the pubkey is tagged
+func (r *OperatorRegistry) Lookup(pubkey string) (ValidatorOperator, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	operator, ok := r.operators[strings.ToLower(pubkey)]
+	return operator, ok
+}
+
+// Len returns the number of tagged pubkeys
+func (r *OperatorRegistry) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.operators)
+}
+
+// Refresh fetches a JSON list of operator key lists from a URL and merges it into the registry
+func (r *OperatorRegistry) Refresh(ctx context.Context, client *http.Client, sourceURL string) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", sourceURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch operator keys: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("operator keys URL responded with status %d", resp.StatusCode)
+	}
+
+	lists, err := decodeOperatorKeyLists(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	// Merge rather than replace so validators that left a published list keep their tag for historical blocks
+	for _, list := range lists {
+		r.Put(list)
+	}
+	return nil
+}
+
+// decodeOperatorKeyLists decodes and validates a JSON list of operator key lists
+func decodeOperatorKeyLists(reader io.Reader) ([]OperatorKeyList, error) {
+	var lists []OperatorKeyList
+	if err := json.NewDecoder(reader).Decode(&lists); err != nil {
+		return nil, fmt.Errorf("failed to decode operator keys: %w", err)
+	}
+
+	for _, list := range lists {
+		if strings.TrimSpace(list.Pool) == "" {
+			return nil, fmt.Errorf("operator key list pool cannot be empty")
+		}
+		for _, pubkey := range list.Pubkeys {
+			if !pubkeyPattern.MatchString(pubkey) {
+				return nil, fmt.Errorf("operator key list of %q contains invalid pubkey %q", list.Pool, pubkey)
+			}
+		}
+	}
+	return lists, nil
+}
+
+// lookupOperator returns the pool and operator running a validator, nil if the pubkey is untagged
+func (s *EthereumService) lookupOperator(pubkey string) *ValidatorOperator {
+	if s.operators == nil || pubkey == "" {
+		return nil
+	}
+	operator, ok := s.operators.Lookup(pubkey)
+	if !ok {
+		return nil
+	}
+	return &operator
+}
+
+// proposerPool returns the staking pool of the proposer of an indexed slot, PoolOther for untagged proposers and
+// "unknown" when the block detail is not indexed
+func (s *EthereumService) proposerPool(slot int64) string {
+	block, ok := s.index.Get(slot)
+	if !ok || block.ProposerPubkey == "" {
+		return nameOrUnknown("")
+	}
+	if operator := s.lookupOperator(block.ProposerPubkey); operator != nil {
+		return operator.Pool
+	}
+	return PoolOther
+}
@@ -0,0 +1,291 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrPricesDisabled   = errors.New("fiat valuation is not configured")
+	ErrUnknownCurrency  = errors.New("currency is not tracked")
+	ErrPriceUnavailable = errors.New("price not available")
+)
+
+const (
+	// DefaultCoinGeckoURL is the public CoinGecko API used when no other price API is configured
+	DefaultCoinGeckoURL = "https://api.coingecko.com/api/v3"
+
+	// DefaultPriceHistory is how far back the price series is filled on the first refresh
+	DefaultPriceHistory = 90 * 24 * time.Hour
+
+	// maxPriceGap is the largest distance between a block and the price it is valued at. CoinGecko returns hourly
+	// prices for the last 90 days and daily prices before
+	maxPriceGap = 25 * time.Hour
+)
+
+// PricePoint is the price of one ETH in a currency at a point in time
+type PricePoint struct {
+	Time  time.Time
+	Price float64
+}
+
+// PriceProvider fetches historical ETH prices
+type PriceProvider interface {
+	// FetchPrices returns the prices of one ETH in a currency between two times, oldest first
+	FetchPrices(ctx context.Context, currency string, from, to time.Time) ([]PricePoint, error)
+}
+
+// CoinGeckoProvider fetches ETH prices from the CoinGecko market chart API
+type CoinGeckoProvider struct {
+	client  *http.Client
+	baseURL string
+	apiKey  string // Demo API key sent with each request, "" for anonymous requests
+}
+
+// NewCoinGeckoProvider creates a CoinGeckoProvider for the API at baseURL
+func NewCoinGeckoProvider(client *http.Client, baseURL, apiKey string) *CoinGeckoProvider {
+	return &CoinGeckoProvider{
+		client:  client,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		apiKey:  apiKey,
+	}
+}
+
+// coinGeckoMarketChart represents the response of /coins/{id}/market_chart/range
+type coinGeckoMarketChart struct {
+	Prices [][2]float64 `json:"prices"` // Pairs of unix time in milliseconds and price
+}
+
+// FetchPrices returns the prices of one ETH in a currency between two times, oldest first
+func (p *CoinGeckoProvider) FetchPrices(ctx context.Context, currency string, from, to time.Time) ([]PricePoint, error) {
+	query := url.Values{
+		"vs_currency": {currency},
+		"from":        {strconv.FormatInt(from.Unix(), 10)},
+		"to":          {strconv.FormatInt(to.Unix(), 10)},
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", p.baseURL+"/coins/ethereum/market_chart/range?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if p.apiKey != "" {
+		req.Header.Set("x-cg-demo-api-key", p.apiKey)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch prices: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("price API responded with status %d", resp.StatusCode)
+	}
+
+	var chart coinGeckoMarketChart
+	if err := json.NewDecoder(resp.Body).Decode(&chart); err != nil {
+		return nil, fmt.Errorf("failed to decode prices: %w", err)
+	}
+
+	points := make([]PricePoint, 0, len(chart.Prices))
+	for _, price := range chart.Prices {
+		points = append(points, PricePoint{Time: time.UnixMilli(int64(price[0])).UTC(), Price: price[1]})
+	}
+	return points, nil
+}
+
+// PriceSeries keeps the historical ETH prices per currency
+type PriceSeries struct {
+	mu     sync.RWMutex
+	points map[string][]PricePoint // keyed by lower-cased currency, oldest first
+}
+
+// NewPriceSeries creates an empty PriceSeries
+func NewPriceSeries() *PriceSeries {
+	return &PriceSeries{
+		points: make(map[string][]PricePoint),
+	}
+}
+
+// Put merges prices of a currency into the series, replacing known prices at the same times
+func (p *PriceSeries) Put(currency string, points []PricePoint) {
+	currency = strings.ToLower(currency)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	merged := append(append([]PricePoint(nil), p.points[currency]...), points...)
+	// Stable so that of two prices at the same time the one merged last is kept
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Time.Before(merged[j].Time)
+	})
+
+	deduplicated := merged[:0]
+	for _, point := range merged {
+		if n := len(deduplicated); n > 0 && deduplicated[n-1].Time.Equal(point.Time) {
+			deduplicated[n-1] = point
+			continue
+		}
+		deduplicated = append(deduplicated, point)
+	}
+	p.points[currency] = deduplicated
+}
+
+// Latest returns the time of the newest price of a currency, reporting whether any is known
+func (p *PriceSeries) Latest(currency string) (time.Time, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	points := p.points[strings.ToLower(currency)]
+	if len(points) == 0 {
+		return time.Time{}, false
+	}
+	return points[len(points)-1].Time, true
+}
+
+// PriceAt returns the price of a currency closest to a time, reporting whether one is known within maxPriceGap
+func (p *PriceSeries) PriceAt(currency string, at time.Time) (PricePoint, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	points := p.points[strings.ToLower(currency)]
+
+	i := sort.Search(len(points), func(i int) bool {
+		return !points[i].Time.Before(at)
+	})
+	var closest *PricePoint
+	for _, candidate := range []int{i - 1, i} {
+		if candidate < 0 || candidate >= len(points) {
+			continue
+		}
+		if closest == nil || absDuration(points[candidate].Time.Sub(at)) < absDuration(closest.Time.Sub(at)) {
+			closest = &points[candidate]
+		}
+	}
+	if closest == nil || absDuration(closest.Time.Sub(at)) > maxPriceGap {
+		return PricePoint{}, false
+	}
+	return *closest, true
+}
+
+// absDuration returns the absolute value of a duration
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+// FiatValue is the value of an amount of ETH in a currency
+type FiatValue struct {
+	Currency  string
+	Price     float64   // Price of one ETH
+	PriceTime time.Time // Time of the price the amount was valued at
+	Value     float64
+}
+
+// SetPriceProvider enables fiat valuation in the given currencies with prices from a provider
+func (s *EthereumService) SetPriceProvider(provider PriceProvider, currencies []string) {
+	s.priceProvider = provider
+	s.priceCurrencies = make([]string, 0, len(currencies))
+	for _, currency := range currencies {
+		if currency = strings.ToLower(strings.TrimSpace(currency)); currency != "" {
+			s.priceCurrencies = append(s.priceCurrencies, currency)
+		}
+	}
+}
+
+// EnableCoinGeckoPrices enables fiat valuation in the given currencies with prices from the CoinGecko API at baseURL
+func (s *EthereumService) EnableCoinGeckoPrices(baseURL, apiKey string, currencies []string) {
+	s.SetPriceProvider(NewCoinGeckoProvider(s.client, baseURL, apiKey), currencies)
+}
+
+// Prices returns the price series used for fiat valuation
+func (s *EthereumService) Prices() *PriceSeries {
+	return s.prices
+}
+
+// PriceTask returns the task extending the price series of each tracked currency up to now, run at startup and on
+// the given schedule. The first run fills the series back to history
+func (s *EthereumService) PriceTask(schedule Schedule, history time.Duration) Task {
+	return Task{
+		Name:       "prices",
+		Schedule:   schedule,
+		RunAtStart: true,
+		Run: func(ctx context.Context) error {
+			return s.refreshPrices(ctx, history)
+		},
+	}
+}
+
+// refreshPrices fetches the prices of each tracked currency since its newest known price
+func (s *EthereumService) refreshPrices(ctx context.Context, history time.Duration) error {
+	if s.priceProvider == nil {
+		return ErrPricesDisabled
+	}
+
+	now := time.Now()
+	for _, currency := range s.priceCurrencies {
+		from := now.Add(-history)
+		if latest, ok := s.prices.Latest(currency); ok && latest.After(from) {
+			from = latest
+		}
+
+		points, err := s.priceProvider.FetchPrices(ctx, currency, from, now)
+		if err != nil {
+			return fmt.Errorf("failed to refresh %s prices: %w", currency, err)
+		}
+		s.prices.Put(currency, points)
+	}
+	return nil
+}
+
+// GetFiatValues values an amount of Wei paid at a slot in the given currencies, at the prices closest to the slot
+func (s *EthereumService) GetFiatValues(ctx context.Context, slot int64, wei *big.Int, currencies []string) ([]FiatValue, error) {
+	if s.priceProvider == nil {
+		return nil, ErrPricesDisabled
+	}
+	for _, currency := range currencies {
+		if !s.tracksCurrency(currency) {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownCurrency, currency)
+		}
+	}
+
+	genesis, err := s.getGenesisTime(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get genesis time: %w", err)
+	}
+	slotTime := genesis.Add(time.Duration(slot*SecondsPerSlot) * time.Second)
+	eth, _ := new(big.Float).Quo(new(big.Float).SetInt(wei), big.NewFloat(1e18)).Float64()
+
+	values := make([]FiatValue, 0, len(currencies))
+	for _, currency := range currencies {
+		price, ok := s.prices.PriceAt(currency, slotTime)
+		if !ok {
+			return nil, fmt.Errorf("%w: no %s price around %s", ErrPriceUnavailable, currency, slotTime.Format(time.RFC3339))
+		}
+		values = append(values, FiatValue{
+			Currency:  strings.ToLower(currency),
+			Price:     price.Price,
+			PriceTime: price.Time,
+			Value:     eth * price.Price,
+		})
+	}
+	return values, nil
+}
+
+// tracksCurrency reports whether the price series of a currency is maintained
+func (s *EthereumService) tracksCurrency(currency string) bool {
+	for _, tracked := range s.priceCurrencies {
+		if strings.EqualFold(tracked, currency) {
+			return true
+		}
+	}
+	return false
+}
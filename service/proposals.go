@@ -0,0 +1,272 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Outcomes of an assigned proposal
+const (
+	ProposalProposed = "proposed"
+	ProposalMissed   = "missed"
+	ProposalOrphaned = "orphaned" // A block was seen at the slot, but it is no longer part of the canonical chain
+)
+
+const (
+	// DefaultProposalEpochs is the number of epochs covered when no range is given
+	DefaultProposalEpochs = 100
+
+	// MaxProposalEpochs bounds the range of GetValidatorProposals, each epoch costs a proposer duties lookup
+	MaxProposalEpochs = 1000
+
+	// MaxProposalsPerPage bounds the proposals of a page of GetValidatorProposalsPage
+	MaxProposalsPerPage = 1000
+
+	// finalizedDutiesTTL is how long proposer duties of finalized epochs are cached, they can't change anymore
+	finalizedDutiesTTL = time.Hour
+)
+
+// ValidatorProposal represents a proposal assigned to a validator and its outcome
+type ValidatorProposal struct {
+	Slot      int64
+	Epoch     int64
+	Outcome   string
+	BlockRoot string       // Root of the canonical or orphaned block, "" if missed
+	Reward    *BlockReward // Reward of the canonical block, nil unless proposed
+}
+
+// ValidatorProposals represents the proposal history of a validator within an epoch range
+type ValidatorProposals struct {
+	ValidatorIndex int64
+	FromEpoch      int64
+	ToEpoch        int64
+	Proposals      []ValidatorProposal // Oldest first
+}
+
+// GetValidatorProposals lists the proposals assigned to a validator between fromEpoch and toEpoch, by default the
+// last DefaultProposalEpochs epochs up to the head, with their outcome and reward. Blocks seen by the indexer
+// that are no longer canonical are reported as orphaned
+func (s *EthereumService) GetValidatorProposals(ctx context.Context, validatorID string, fromEpoch, toEpoch *int64) (*ValidatorProposals, error) {
+	proposals := []ValidatorProposal{}
+	result, err := s.StreamValidatorProposals(ctx, validatorID, fromEpoch, toEpoch, func(proposal ValidatorProposal) error {
+		proposals = append(proposals, proposal)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Proposals = proposals
+	return result, nil
+}
+
+// ValidatorProposalsPage represents a page of the proposal history of a validator
+type ValidatorProposalsPage struct {
+	ValidatorProposals
+	NextCursor int64 // Slot the next page continues after
+	HasMore    bool  // Whether slots after NextCursor are known yet, later pages may still be empty
+}
+
+// errPageFull stops streaming proposals once a page is full
+var errPageFull = errors.New("page full")
+
+// GetValidatorProposalsPage lists up to limit proposals of a validator, oldest first, starting after the slot of
+// cursor or at fromEpoch without one. Pages are keyed by slot rather than offset, so a long pull resumed with
+// NextCursor neither skips nor repeats proposals while new epochs are indexed. A page covers up to toEpoch, by
+// default the head epoch but no more than MaxProposalEpochs epochs
+func (s *EthereumService) GetValidatorProposalsPage(ctx context.Context, validatorID string, cursor, fromEpoch, toEpoch *int64, limit int) (*ValidatorProposalsPage, error) {
+	if limit < 1 || limit > MaxProposalsPerPage {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidRange, MaxProposalsPerPage)
+	}
+	if cursor != nil && *cursor < 0 {
+		return nil, fmt.Errorf("%w: cursor must not be negative", ErrInvalidRange)
+	}
+
+	// Pulls end at the last slot of toEpoch when one was requested, otherwise they follow the head
+	requestedTo := toEpoch
+
+	// The page starts at the epoch of the cursor, ending at the head unless that's too many epochs away
+	if cursor != nil {
+		start := *cursor / SlotsPerEpoch
+		if fromEpoch != nil {
+			start = max(start, *fromEpoch)
+		}
+		fromEpoch = &start
+		if toEpoch == nil {
+			head, err := s.GetChainHead(ctx)
+			if err != nil {
+				return nil, err
+			}
+			end := max(min(head.Epoch, start+MaxProposalEpochs-1), start)
+			toEpoch = &end
+		}
+	}
+
+	result, head, err := s.proposalRange(ctx, validatorID, fromEpoch, toEpoch)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &ValidatorProposalsPage{ValidatorProposals: *result}
+	page.Proposals = []ValidatorProposal{}
+	err = s.streamProposals(ctx, result, head, func(proposal ValidatorProposal) error {
+		if cursor != nil && proposal.Slot <= *cursor {
+			return nil
+		}
+		if len(page.Proposals) == limit {
+			return errPageFull
+		}
+		page.Proposals = append(page.Proposals, proposal)
+		return nil
+	})
+	switch {
+	case errors.Is(err, errPageFull):
+		page.NextCursor = page.Proposals[len(page.Proposals)-1].Slot
+		page.HasMore = true
+	case err != nil:
+		return nil, err
+	default:
+		// The whole range was covered, up to the head within the head epoch
+		page.NextCursor = min((result.ToEpoch+1)*SlotsPerEpoch-1, head.Slot)
+		if cursor != nil {
+			page.NextCursor = max(page.NextCursor, *cursor)
+		}
+		end := head.Slot
+		if requestedTo != nil {
+			end = min((*requestedTo+1)*SlotsPerEpoch-1, head.Slot)
+		}
+		page.HasMore = page.NextCursor < end
+	}
+	return page, nil
+}
+
+// StreamValidatorProposals is GetValidatorProposals passing each proposal to emit as soon as its outcome is known,
+// oldest first. The range is validated before the first proposal is emitted, and an error returned by emit stops the
+// lookup. The returned result does not hold the proposals
+func (s *EthereumService) StreamValidatorProposals(ctx context.Context, validatorID string, fromEpoch, toEpoch *int64, emit func(ValidatorProposal) error) (*ValidatorProposals, error) {
+	result, head, err := s.proposalRange(ctx, validatorID, fromEpoch, toEpoch)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.streamProposals(ctx, result, head, emit); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// proposalRange resolves the validator and the epoch range of a proposal lookup, by default the last
+// DefaultProposalEpochs epochs up to the head
+func (s *EthereumService) proposalRange(ctx context.Context, validatorID string, fromEpoch, toEpoch *int64) (*ValidatorProposals, *ChainHead, error) {
+	index, err := s.resolveValidatorIndex(ctx, validatorID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	head, err := s.GetChainHead(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := &ValidatorProposals{ValidatorIndex: index, ToEpoch: head.Epoch}
+	if toEpoch != nil {
+		result.ToEpoch = *toEpoch
+	}
+	result.FromEpoch = max(result.ToEpoch-DefaultProposalEpochs+1, 0)
+	if fromEpoch != nil {
+		result.FromEpoch = *fromEpoch
+	}
+
+	switch {
+	case result.FromEpoch < 0 || result.FromEpoch > result.ToEpoch:
+		return nil, nil, fmt.Errorf("%w: from_epoch must be between 0 and to_epoch", ErrInvalidRange)
+	case result.ToEpoch > head.Epoch:
+		return nil, nil, fmt.Errorf("%w (head epoch: %d)", ErrFutureSlot, head.Epoch)
+	case result.ToEpoch-result.FromEpoch+1 > MaxProposalEpochs:
+		return nil, nil, fmt.Errorf("%w: at most %d epochs", ErrInvalidRange, MaxProposalEpochs)
+	}
+	return result, head, nil
+}
+
+// streamProposals passes the proposals of the validator within the range of result to emit, oldest first
+func (s *EthereumService) streamProposals(ctx context.Context, result *ValidatorProposals, head *ChainHead, emit func(ValidatorProposal) error) error {
+	for epoch := result.FromEpoch; epoch <= result.ToEpoch; epoch++ {
+		duties, err := s.getProposerDuties(ctx, epoch, epoch <= head.Finalized.Epoch)
+		if err != nil {
+			return err
+		}
+
+		for _, duty := range duties.Data {
+			slot := parseDecimal(duty.Slot)
+			if parseDecimal(duty.ValidatorIndex) != result.ValidatorIndex || slot > head.Slot {
+				continue
+			}
+
+			proposal, err := s.getProposalOutcome(ctx, result.ValidatorIndex, slot)
+			if err != nil {
+				return err
+			}
+			if err := emit(*proposal); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// getProposerDuties returns the proposer duties of an epoch, caching them once the epoch is finalized
+func (s *EthereumService) getProposerDuties(ctx context.Context, epoch int64, finalized bool) (*proposerDutiesResponse, error) {
+	key := fmt.Sprintf("duties:proposer:%d", epoch)
+	if cached, ok := s.cacheGet(ctx, key); ok {
+		return cached.(*proposerDutiesResponse), nil
+	}
+
+	var duties proposerDutiesResponse
+	if err := s.getBeaconJSON(ctx, fmt.Sprintf("/eth/v1/validator/duties/proposer/%d", epoch), &duties); err != nil {
+		return nil, err
+	}
+	if finalized {
+		s.cache.Set(key, &duties, finalizedDutiesTTL)
+	}
+	return &duties, nil
+}
+
+// getProposalOutcome determines whether the proposal of a slot made it on chain, comparing the canonical chain with
+// the blocks the indexer has seen
+func (s *EthereumService) getProposalOutcome(ctx context.Context, validatorIndex, slot int64) (*ValidatorProposal, error) {
+	proposal := &ValidatorProposal{Slot: slot, Epoch: slot / SlotsPerEpoch}
+
+	var root blockRootResponse
+	err := s.getBeaconJSON(ctx, fmt.Sprintf("/eth/v1/beacon/blocks/%d/root", slot), &root)
+	if errors.Is(err, ErrSlotNotFound) {
+		proposal.Outcome = ProposalMissed
+		if indexed, ok := s.index.Get(slot); ok && indexed.ProposerIndex == validatorIndex {
+			proposal.Outcome = ProposalOrphaned
+			proposal.BlockRoot = indexed.BlockRoot
+		}
+		return proposal, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	proposal.Outcome = ProposalProposed
+	proposal.BlockRoot = root.Data.Root
+
+	// Rewards of indexed blocks come with the delivering relay
+	if reward, ok := s.index.GetReward(slot); ok && (reward.Status != "mev" || reward.Relay != "") {
+		proposal.Reward = reward
+		return proposal, nil
+	}
+	reward, err := s.GetBlockRewardBySlot(ctx, slot)
+	if err != nil {
+		return nil, err
+	}
+	if reward.Status == "mev" {
+		withRelay := *reward
+		withRelay.Relay = s.getDeliveringRelay(ctx, slot)
+		reward = &withRelay
+	}
+	proposal.Reward = reward
+	return proposal, nil
+}
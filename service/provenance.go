@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// provenanceKey is the context key carrying the Provenance of a request
+type provenanceKey struct{}
+
+// Provenance collects where the data of a request came from: the upstream providers that answered, the lookups
+// served by the response cache and the slot the request was about. All methods are safe on a nil Provenance, which
+// records nothing
+type Provenance struct {
+	mu        sync.Mutex
+	providers map[string]bool // Names of the providers that answered, "primary" or "secondary"
+	upstream  int             // Upstream responses used
+	hits      int             // Lookups served from the response cache
+	misses    int
+	oldest    time.Time // When the oldest cached data used was stored, zero without cache hits
+	slot      *int64
+	finalized bool
+}
+
+// ProvenanceSummary is a snapshot of the Provenance of a request
+type ProvenanceSummary struct {
+	Providers   []string // Sorted names of the providers that answered
+	Upstream    int
+	CacheHits   int
+	CacheMisses int
+	DataAge     time.Duration // Age of the oldest cached data used, 0 when everything was fetched for the request
+	Slot        *int64        // Slot the request was about, nil for requests not about a slot
+	Finalized   bool          // Whether Slot was finalized
+}
+
+// WithProvenance returns a context whose upstream calls and cache lookups are recorded in the returned Provenance
+func WithProvenance(ctx context.Context) (context.Context, *Provenance) {
+	provenance := &Provenance{providers: make(map[string]bool)}
+	return context.WithValue(ctx, provenanceKey{}, provenance), provenance
+}
+
+// ProvenanceFrom returns the Provenance recorded for ctx, nil if none is
+func ProvenanceFrom(ctx context.Context) *Provenance {
+	provenance, _ := ctx.Value(provenanceKey{}).(*Provenance)
+	return provenance
+}
+
+// SetSlot records the slot a request is about and whether it was finalized
+func (p *Provenance) SetSlot(slot int64, finalized bool) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.slot = &slot
+	p.finalized = finalized
+}
+
+// Summary returns a snapshot of what was recorded so far
+func (p *Provenance) Summary() ProvenanceSummary {
+	if p == nil {
+		return ProvenanceSummary{Providers: make([]string, 0)}
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	summary := ProvenanceSummary{
+		Providers:   make([]string, 0, len(p.providers)),
+		Upstream:    p.upstream,
+		CacheHits:   p.hits,
+		CacheMisses: p.misses,
+		Slot:        p.slot,
+		Finalized:   p.finalized,
+	}
+	for name := range p.providers {
+		summary.Providers = append(summary.Providers, name)
+	}
+	sort.Strings(summary.Providers)
+	if !p.oldest.IsZero() {
+		summary.DataAge = time.Since(p.oldest)
+	}
+	return summary
+}
+
+// recordProvider records that a provider answered, used by the hedged clients to tell which of their providers won
+func (p *Provenance) recordProvider(name string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.providers[name] = true
+}
+
+// recordUpstream records a successful upstream call whose providers were recorded in call. Calls no hedged client
+// attributed to a provider were answered by the primary
+func (p *Provenance) recordUpstream(call *Provenance) {
+	if p == nil {
+		return
+	}
+	call.mu.Lock()
+	providers := make([]string, 0, len(call.providers))
+	for name := range call.providers {
+		providers = append(providers, name)
+	}
+	call.mu.Unlock()
+	if len(providers) == 0 {
+		providers = append(providers, "primary")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.upstream++
+	for _, name := range providers {
+		p.providers[name] = true
+	}
+}
+
+// recordCache records a response cache lookup, with when the data of a hit was stored
+func (p *Provenance) recordCache(hit bool, stored time.Time) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !hit {
+		p.misses++
+		return
+	}
+	p.hits++
+	if p.oldest.IsZero() || stored.Before(p.oldest) {
+		p.oldest = stored
+	}
+}
+
+// trackUpstream runs an upstream call, recording in the provenance of ctx which provider answered it
+func trackUpstream(ctx context.Context, call func(ctx context.Context) error) error {
+	provenance := ProvenanceFrom(ctx)
+	if provenance == nil {
+		return call(ctx)
+	}
+	callCtx, callProvenance := WithProvenance(ctx)
+	err := call(callCtx)
+	if err == nil {
+		provenance.recordUpstream(callProvenance)
+	}
+	return err
+}
+
+// cacheGet looks a key up in the response cache, recording the outcome in the provenance of ctx. Expired entries
+// are served while an upstream daily quota is nearly used up, saving its credits for data that isn't cached
+func (s *EthereumService) cacheGet(ctx context.Context, key string) (interface{}, bool) {
+	entry, ok := s.cache.getEntry(key)
+	if !ok && upstreamBudgetLow() {
+		entry, ok = s.cache.getStaleEntry(key)
+	}
+	ProvenanceFrom(ctx).recordCache(ok, entry.stored)
+	return entry.value, ok
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// activeSetTTL is how long the active validator set summary is cached, it only changes slowly between epochs
+const activeSetTTL = 10 * time.Minute
+
+// Churn parameters used when the beacon node's spec does not report them (mainnet Electra values, in GWEI)
+const (
+	defaultMinPerEpochChurnLimit          = 128_000_000_000
+	defaultMaxPerEpochActivationExitChurn = 256_000_000_000
+	defaultChurnLimitQuotient             = 65536
+	defaultEffectiveBalanceIncrement      = 1_000_000_000
+	defaultMaxSeedLookahead               = 4
+	maxEffectiveBalance                   = 32_000_000_000
+)
+
+// ActiveSet summarizes the active validators of the head state
+type ActiveSet struct {
+	Validators            int
+	TotalEffectiveBalance int64 // in GWEI
+}
+
+// ValidatorQueue represents the state of the entry and exit queues and the estimated wait times
+type ValidatorQueue struct {
+	Epoch             int64
+	ActiveValidators  int
+	ActiveBalance     int64 // in GWEI
+	ChurnLimit        int64 // activation and exit churn per epoch in GWEI
+	PendingDeposits   int
+	PendingBalance    int64 // in GWEI
+	ExitingValidators int
+	ExitingBalance    int64 // in GWEI
+	EntryWaitEpochs   int64
+	ExitWaitEpochs    int64
+}
+
+// pendingDepositsResponse represents the response from /eth/v1/beacon/states/{state_id}/pending_deposits
+type pendingDepositsResponse struct {
+	Data []struct {
+		Pubkey string `json:"pubkey"`
+		Amount string `json:"amount"`
+		Slot   string `json:"slot"`
+	} `json:"data"`
+}
+
+// GetValidatorQueue estimates the entry and exit queues from the head state using the Electra balance based churn
+func (s *EthereumService) GetValidatorQueue(ctx context.Context) (*ValidatorQueue, error) {
+	spec, err := s.GetChainSpec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	active, err := s.GetActiveSet(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	headSlot, err := s.getHeadSlot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	queue := &ValidatorQueue{
+		Epoch:            headSlot / SlotsPerEpoch,
+		ActiveValidators: active.Validators,
+		ActiveBalance:    active.TotalEffectiveBalance,
+		ChurnLimit:       activationExitChurnLimit(spec, active.TotalEffectiveBalance),
+	}
+
+	var deposits pendingDepositsResponse
+	err = s.getBeaconJSON(ctx, "/eth/v1/beacon/states/head/pending_deposits", &deposits)
+	switch {
+	case err == nil:
+		queue.PendingDeposits = len(deposits.Data)
+		for _, deposit := range deposits.Data {
+			queue.PendingBalance += parseDecimal(deposit.Amount)
+		}
+	case errors.Is(err, ErrSlotNotFound):
+		// Nodes before Electra have no deposit queue, count validators waiting for activation instead
+		var pending validatorsResponse
+		if err := s.getBeaconJSON(ctx, "/eth/v1/beacon/states/head/validators?status=pending_queued", &pending); err != nil {
+			return nil, err
+		}
+		queue.PendingDeposits = len(pending.Data)
+		queue.PendingBalance = int64(len(pending.Data)) * maxEffectiveBalance
+	default:
+		return nil, err
+	}
+
+	var exiting validatorsResponse
+	if err := s.getBeaconJSON(ctx, "/eth/v1/beacon/states/head/validators?status=active_exiting", &exiting); err != nil {
+		return nil, err
+	}
+
+	// A new exit is scheduled after every exit already in the queue, but never earlier than the seed lookahead allows
+	lastExitEpoch := queue.Epoch + 1 + specInt(spec, "MAX_SEED_LOOKAHEAD", defaultMaxSeedLookahead)
+	for _, validator := range exiting.Data {
+		queue.ExitingValidators++
+		queue.ExitingBalance += parseDecimal(validator.Validator.EffectiveBalance)
+		lastExitEpoch = max(lastExitEpoch, parseDecimal(validator.Validator.ExitEpoch))
+	}
+	queue.ExitWaitEpochs = lastExitEpoch - queue.Epoch
+
+	if queue.ChurnLimit > 0 {
+		queue.EntryWaitEpochs = (queue.PendingBalance + queue.ChurnLimit - 1) / queue.ChurnLimit
+	}
+
+	return queue, nil
+}
+
+// GetActiveSet returns the number and total effective balance of the active validators at the head, cached for a few minutes
+func (s *EthereumService) GetActiveSet(ctx context.Context) (*ActiveSet, error) {
+	if cached, ok := s.cacheGet(ctx, "network:active"); ok {
+		return cached.(*ActiveSet), nil
+	}
+	return s.refreshActiveSet(ctx)
+}
+
+// refreshActiveSet fetches the active validator set summary and updates the cache
+func (s *EthereumService) refreshActiveSet(ctx context.Context) (*ActiveSet, error) {
+	var validators validatorsResponse
+	if err := s.getBeaconJSON(ctx, "/eth/v1/beacon/states/head/validators?status=active", &validators); err != nil {
+		return nil, err
+	}
+
+	active := &ActiveSet{Validators: len(validators.Data)}
+	for _, validator := range validators.Data {
+		active.TotalEffectiveBalance += parseDecimal(validator.Validator.EffectiveBalance)
+	}
+
+	s.cache.Set("network:active", active, activeSetTTL)
+	return active, nil
+}
+
+// activationExitChurnLimit returns the balance that can enter or leave the active set per epoch
+func activationExitChurnLimit(spec map[string]interface{}, totalActiveBalance int64) int64 {
+	increment := specInt(spec, "EFFECTIVE_BALANCE_INCREMENT", defaultEffectiveBalanceIncrement)
+	churn := max(
+		specInt(spec, "MIN_PER_EPOCH_CHURN_LIMIT_ELECTRA", defaultMinPerEpochChurnLimit),
+		totalActiveBalance/specInt(spec, "CHURN_LIMIT_QUOTIENT", defaultChurnLimitQuotient),
+	)
+	churn -= churn % increment
+	return min(specInt(spec, "MAX_PER_EPOCH_ACTIVATION_EXIT_CHURN_LIMIT", defaultMaxPerEpochActivationExitChurn), churn)
+}
+
+// specInt reads an integer parameter from the beacon node's spec, falling back to a default
+func specInt(spec map[string]interface{}, key string, fallback int64) int64 {
+	raw, ok := spec[key].(string)
+	if !ok {
+		return fallback
+	}
+
+	value, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
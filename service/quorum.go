@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/attestantio/go-eth2-client/spec"
+)
+
+// quorumPath matches Beacon API paths about a single block or state, capturing the id the nodes have to agree on
+var quorumPath = regexp.MustCompile(`^(/eth/v\d+/beacon/(blocks|headers|states)/)([^/?]+)(.*)$`)
+
+// quorumNode is one of the beacon nodes behind a QuorumBeaconClient
+type quorumNode struct {
+	name   string // "primary" or "quorum-<n>"
+	client BeaconClient
+}
+
+// quorumVote is the root a beacon node reported for a block or state
+type quorumVote struct {
+	node quorumNode
+	raw  json.RawMessage
+	root string
+	err  error
+}
+
+// QuorumBeaconClient reads from several beacon nodes and only returns data about a block or state once a quorum of
+// them agrees on its root. The data is then read by that root from an agreeing node, so a node moving to a new head
+// in between can't serve another block. Requests about no block or state are answered by the first node that can
+type QuorumBeaconClient struct {
+	nodes  []quorumNode
+	quorum int
+}
+
+// NewQuorumBeaconClient creates a BeaconClient requiring quorum of the nodes to agree on the root of a block or state,
+// the first node is the primary
+func NewQuorumBeaconClient(nodes []BeaconClient, quorum int) (*QuorumBeaconClient, error) {
+	if quorum < 1 || quorum > len(nodes) {
+		return nil, fmt.Errorf("invalid quorum %d, must be between 1 and the %d beacon nodes", quorum, len(nodes))
+	}
+
+	q := &QuorumBeaconClient{quorum: quorum}
+	for i, client := range nodes {
+		name := "primary"
+		if i > 0 {
+			name = fmt.Sprintf("quorum-%d", i)
+		}
+		q.nodes = append(q.nodes, quorumNode{name: name, client: client})
+	}
+	return q, nil
+}
+
+// GetJSON performs a GET request once the nodes agree on the block or state it is about
+func (q *QuorumBeaconClient) GetJSON(ctx context.Context, path string, out interface{}) error {
+	return q.read(ctx, path, out, func(ctx context.Context, client BeaconClient, path string, raw *json.RawMessage) error {
+		return client.GetJSON(ctx, path, raw)
+	})
+}
+
+// PostJSON performs a POST request once the nodes agree on the block or state it is about
+func (q *QuorumBeaconClient) PostJSON(ctx context.Context, path string, body interface{}, out interface{}) error {
+	return q.read(ctx, path, out, func(ctx context.Context, client BeaconClient, path string, raw *json.RawMessage) error {
+		return client.PostJSON(ctx, path, body, raw)
+	})
+}
+
+// SignedBeaconBlock performs a typed block lookup by the block root the nodes agree on
+func (q *QuorumBeaconClient) SignedBeaconBlock(ctx context.Context, blockID string) (*spec.VersionedSignedBeaconBlock, error) {
+	root, _, agreeing, err := q.agree(ctx, "/eth/v1/beacon/blocks/"+blockID+"/root")
+	if err != nil {
+		return nil, err
+	}
+
+	lastErr := errTypedClientUnavailable
+	for _, node := range agreeing {
+		decoder, ok := node.client.(BlockDecoder)
+		if !ok {
+			continue
+		}
+		block, err := decoder.SignedBeaconBlock(ctx, root)
+		if err == nil {
+			ProvenanceFrom(ctx).recordProvider(node.name)
+			return block, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// SubscribeHeads streams new heads from the primary node
+func (q *QuorumBeaconClient) SubscribeHeads(ctx context.Context, handler func(slot int64)) error {
+	subscriber, ok := q.nodes[0].client.(HeadSubscriber)
+	if !ok {
+		return errTypedClientUnavailable
+	}
+	return subscriber.SubscribeHeads(ctx, handler)
+}
+
+// read performs a request through call and decodes the response into out, requests about a block or state are only
+// sent once the nodes agree on its root
+func (q *QuorumBeaconClient) read(ctx context.Context, path string, out interface{}, call func(context.Context, BeaconClient, string, *json.RawMessage) error) error {
+	nodes := q.nodes
+	match := quorumPath.FindStringSubmatch(path)
+	if match != nil {
+		kind := "blocks"
+		if match[2] == "states" {
+			kind = "states"
+		}
+		rootPath := "/eth/v1/beacon/" + kind + "/" + match[3] + "/root"
+		root, raw, agreeing, err := q.agree(ctx, rootPath)
+		if err != nil {
+			return err
+		}
+		if path == rootPath {
+			return decodeQuorumResponse(raw, out)
+		}
+		path, nodes = match[1]+root+match[4], agreeing
+	}
+
+	var err error
+	for _, node := range nodes {
+		var raw json.RawMessage
+		if err = call(ctx, node.client, path, &raw); err == nil {
+			ProvenanceFrom(ctx).recordProvider(node.name)
+			return decodeQuorumResponse(raw, out)
+		}
+		// Nodes agreeing on the root know the block or state, not found is only final for other requests
+		if errors.Is(err, ErrSlotNotFound) && match == nil {
+			return err
+		}
+	}
+	return err
+}
+
+// agree asks every node for the root at rootPath and returns the first root a quorum of them reported, with the
+// response and the nodes that reported it. A quorum of nodes not knowing the block is ErrSlotNotFound, nodes
+// disagreeing ErrInconsistentResponse
+func (q *QuorumBeaconClient) agree(ctx context.Context, rootPath string) (string, json.RawMessage, []quorumNode, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel() // Abandon the nodes still answering once a quorum agrees
+
+	votes := make(chan quorumVote, len(q.nodes))
+	for _, node := range q.nodes {
+		go func() {
+			vote := quorumVote{node: node}
+			vote.err = node.client.GetJSON(ctx, rootPath, &vote.raw)
+			if vote.err == nil {
+				var response blockRootResponse
+				if err := json.Unmarshal(vote.raw, &response); err != nil || response.Data.Root == "" {
+					vote.err = fmt.Errorf("%w: no root in %s", ErrUpstreamInvalidResponse, vote.raw)
+				}
+				vote.root = strings.ToLower(response.Data.Root)
+			}
+			votes <- vote
+		}()
+	}
+
+	agreeing := make(map[string][]quorumVote)
+	answered, notFound := 0, 0
+	var firstErr error
+	for range q.nodes {
+		vote := <-votes
+		switch {
+		case errors.Is(vote.err, ErrSlotNotFound):
+			answered++
+			if notFound++; notFound >= q.quorum {
+				return "", nil, nil, ErrSlotNotFound
+			}
+		case vote.err != nil:
+			if firstErr == nil {
+				firstErr = vote.err
+			}
+		default:
+			answered++
+			agreeing[vote.root] = append(agreeing[vote.root], vote)
+			if len(agreeing[vote.root]) >= q.quorum {
+				nodes := make([]quorumNode, 0, len(agreeing[vote.root]))
+				for _, agreed := range agreeing[vote.root] {
+					nodes = append(nodes, agreed.node)
+				}
+				return vote.root, vote.raw, nodes, nil
+			}
+		}
+	}
+
+	// Too few nodes answered to tell, the failure of one of them explains why
+	if answered < q.quorum && firstErr != nil {
+		return "", nil, nil, firstErr
+	}
+	return "", nil, nil, fmt.Errorf("%w: %d beacon nodes reported %d different roots for %s, %d must agree", ErrInconsistentResponse, answered, len(agreeing)+min(notFound, 1), rootPath, q.quorum)
+}
+
+// decodeQuorumResponse decodes a response read through the quorum into out
+func decodeQuorumResponse(raw json.RawMessage, out interface{}) error {
+	if err := json.Unmarshal(raw, out); err != nil {
+		return fmt.Errorf("%w: %v", ErrUpstreamInvalidResponse, err)
+	}
+	return nil
+}
+
+// quorumClient combines the primary beacon client with clients for the quorum endpoints, a quorum of zero requires
+// a majority of the nodes to agree
+func (s *EthereumService) quorumClient(primary BeaconClient, quorumURLs []string, quorum int) (BeaconClient, error) {
+	nodes := []BeaconClient{primary}
+	for _, quorumURL := range quorumURLs {
+		if err := validateURL("Quorum beacon", quorumURL); err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, NewEth2BeaconClient(quorumURL, s.client))
+	}
+	if quorum == 0 {
+		quorum = len(nodes)/2 + 1
+	}
+	return NewQuorumBeaconClient(nodes, quorum)
+}
@@ -0,0 +1,438 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Upstream rate limit used unless configured otherwise, QuickNode allows 1 request/second
+const (
+	DefaultUpstreamRateLimit = 1
+	DefaultUpstreamBurst     = 1
+)
+
+// upstreamLimiter is shared by all upstream clients since provider rate limits apply to the whole account
+var upstreamLimiter = rate.NewLimiter(DefaultUpstreamRateLimit, DefaultUpstreamBurst)
+
+// SetUpstreamRateLimit configures the shared upstream rate limit, the burst lets the concurrent
+// fetches of a single request go out together
+func SetUpstreamRateLimit(requestsPerSecond float64, burst int) {
+	upstreamLimiter.SetLimit(rate.Limit(requestsPerSecond))
+	upstreamLimiter.SetBurst(burst)
+}
+
+// Priority decides which upstream requests get the shared rate budget first
+type Priority int
+
+const (
+	// PriorityInteractive is used for single lookups a user is waiting for, it is the default
+	PriorityInteractive Priority = iota
+	// PriorityBatch is used for backfills and endpoints covering many epochs, which only get
+	// the rate budget interactive requests leave over
+	PriorityBatch
+)
+
+// priorityKey is the context key carrying the Priority of upstream requests
+type priorityKey struct{}
+
+// WithPriority returns a context whose upstream requests are scheduled with the given priority
+func WithPriority(ctx context.Context, priority Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, priority)
+}
+
+// priorityFrom returns the priority of ctx, PriorityInteractive if none was set
+func priorityFrom(ctx context.Context) Priority {
+	priority, _ := ctx.Value(priorityKey{}).(Priority)
+	return priority
+}
+
+var (
+	// interactiveQueue and batchQueue hand out the tokens of upstreamLimiter, a send succeeds once a token is available
+	interactiveQueue = make(chan struct{})
+	batchQueue       = make(chan struct{})
+	dispatcherOnce   sync.Once
+)
+
+// dispatchUpstream takes tokens from the shared limiter and grants them to interactive waiters
+// before batch waiters
+func dispatchUpstream() {
+	for {
+		// Wait only fails for a zero burst, which SetUpstreamRateLimit's callers reject
+		_ = upstreamLimiter.Wait(context.Background())
+
+		select {
+		case <-interactiveQueue:
+		default:
+			select {
+			case <-interactiveQueue:
+			case <-batchQueue:
+			}
+		}
+	}
+}
+
+// waitForUpstream blocks until the limit of the provider host and the shared rate limit allow another upstream
+// request costing the given compute units, which count against the daily quota of the host. Batch requests wait for
+// the shared limit while interactive requests are queued
+func waitForUpstream(ctx context.Context, host string, cost int) error {
+	limiter := providerLimiterFor(host)
+	if err := limiter.wait(ctx, cost); err != nil {
+		return err
+	}
+	if err := limiter.spend(ctx, cost); err != nil {
+		return err
+	}
+
+	dispatcherOnce.Do(func() {
+		go dispatchUpstream()
+	})
+
+	queue := interactiveQueue
+	if priorityFrom(ctx) == PriorityBatch {
+		queue = batchQueue
+	}
+
+	select {
+	case queue <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// RateLimitError is returned when a request can't be sent because its provider backs off after rate limiting
+// requests, RetryAfter is the remaining backoff
+type RateLimitError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%v, retry in %v", ErrUpstreamRateLimited, e.RetryAfter.Round(time.Millisecond))
+}
+
+func (e *RateLimitError) Unwrap() error {
+	return ErrUpstreamRateLimited
+}
+
+// Backoff of a provider rejecting requests without a Retry-After header, doubled on every consecutive rejection
+const (
+	minUpstreamBackoff = time.Second
+	maxUpstreamBackoff = 30 * time.Second
+)
+
+// Compute units of a request, as counted by CU-based providers such as Alchemy. Methods not listed, including
+// Beacon API requests, cost defaultComputeUnits
+const defaultComputeUnits = 20
+
+var methodComputeUnits = map[string]int{
+	"eth_chainId":               0,
+	"eth_blockNumber":           10,
+	"eth_getBalance":            19,
+	"eth_getCode":               19,
+	"eth_getTransactionCount":   26,
+	"eth_getBlockByNumber":      16,
+	"eth_getBlockByHash":        16,
+	"eth_getTransactionByHash":  17,
+	"eth_getTransactionReceipt": 15,
+	"eth_getBlockReceipts":      500,
+	"eth_call":                  26,
+	"eth_getLogs":               75,
+	"eth_feeHistory":            10,
+}
+
+// computeUnits returns the compute units of a JSON-RPC method
+func computeUnits(method string) int {
+	if units, ok := methodComputeUnits[method]; ok {
+		return units
+	}
+	return defaultComputeUnits
+}
+
+// ProviderRateLimit is the rate limit of a single upstream provider, applied on top of the shared limit
+type ProviderRateLimit struct {
+	RequestsPerSecond     float64 // Requests per second, unlimited if zero
+	Burst                 int     // Requests sent at once, 1 if zero
+	ComputeUnitsPerSecond float64 // Compute units per second for CU-based plans, unlimited if zero
+	DailyQuota            int64   // Credits per UTC day, counted in compute units, unlimited if zero
+}
+
+// quotaReserve is the share of a daily quota after which batch requests are shed and stale cached data is preferred,
+// keeping the rest of the day's credits for interactive requests
+const quotaReserve = 0.9
+
+// providerLimiter throttles the requests to a provider host. Rate limited responses pause the host and halve its
+// rate, which recovers step by step with every accepted request
+type providerLimiter struct {
+	mu           sync.Mutex
+	config       ProviderRateLimit
+	requests     *rate.Limiter // nil without a request limit
+	computeUnits *rate.Limiter // nil without a compute unit limit
+	factor       float64       // Share of the configured rate currently used
+	pausedUntil  time.Time
+	strikes      int       // Consecutive rate limited responses
+	day          time.Time // Start of the UTC day calls and credits are counted for
+	calls        int64
+	credits      int64
+}
+
+// upstreamClock is the clock provider backoffs are timed by
+var upstreamClock Clock = SystemClock{}
+
+// SetUpstreamClock replaces the clock provider backoffs are timed by, so tests can back off without sleeping
+func SetUpstreamClock(clock Clock) {
+	upstreamClock = clock
+}
+
+var (
+	// providerLimiters are keyed by host, like the provider authentication
+	providerLimiters   = make(map[string]*providerLimiter)
+	providerLimitersMu sync.Mutex
+)
+
+// SetProviderRateLimits replaces the rate limits of the upstream providers, keyed by provider URL. Providers sharing
+// a host share their limit, hosts not listed are only subject to the shared limit
+func SetProviderRateLimits(limits map[string]ProviderRateLimit) error {
+	hosts := make(map[string]ProviderRateLimit, len(limits))
+	for providerURL, limit := range limits {
+		parsedURL, err := url.Parse(providerURL)
+		if err != nil || parsedURL.Host == "" {
+			return fmt.Errorf("invalid provider URL: %s", providerURL)
+		}
+		if limit.RequestsPerSecond < 0 || limit.Burst < 0 || limit.ComputeUnitsPerSecond < 0 || limit.DailyQuota < 0 {
+			return fmt.Errorf("invalid rate limit for %s", parsedURL.Host)
+		}
+		hosts[parsedURL.Host] = limit
+	}
+
+	providerLimitersMu.Lock()
+	defer providerLimitersMu.Unlock()
+	for host, limiter := range providerLimiters {
+		if _, ok := hosts[host]; !ok {
+			limiter.configure(ProviderRateLimit{})
+		}
+	}
+	for host, limit := range hosts {
+		providerLimiterLocked(host).configure(limit)
+	}
+	return nil
+}
+
+// providerLimiterFor returns the limiter of a provider host, creating an unlimited one on first use so that
+// rejections of any provider are backed off
+func providerLimiterFor(host string) *providerLimiter {
+	providerLimitersMu.Lock()
+	defer providerLimitersMu.Unlock()
+	return providerLimiterLocked(host)
+}
+
+func providerLimiterLocked(host string) *providerLimiter {
+	limiter, ok := providerLimiters[host]
+	if !ok {
+		limiter = &providerLimiter{factor: 1}
+		providerLimiters[host] = limiter
+	}
+	return limiter
+}
+
+// configure applies a new configuration, resetting the adaptive rate
+func (l *providerLimiter) configure(config ProviderRateLimit) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.config, l.factor = config, 1
+
+	l.requests, l.computeUnits = nil, nil
+	if config.RequestsPerSecond > 0 {
+		l.requests = rate.NewLimiter(rate.Limit(config.RequestsPerSecond), max(config.Burst, 1))
+	}
+	if config.ComputeUnitsPerSecond > 0 {
+		// A second worth of compute units, enough for any single request
+		burst := max(int(math.Ceil(config.ComputeUnitsPerSecond)), methodComputeUnits["eth_getBlockReceipts"]+methodComputeUnits["eth_getBlockByNumber"])
+		l.computeUnits = rate.NewLimiter(rate.Limit(config.ComputeUnitsPerSecond), burst)
+	}
+}
+
+// wait blocks while the provider is paused and until its limits allow a request costing the given compute units.
+// Interactive requests aren't held while the provider is paused, they fail with a RateLimitError right away
+func (l *providerLimiter) wait(ctx context.Context, cost int) error {
+	l.mu.Lock()
+	pausedUntil := l.pausedUntil
+	requests, computeUnits := l.requests, l.computeUnits
+	l.mu.Unlock()
+
+	if pause := pausedUntil.Sub(upstreamClock.Now()); pause > 0 {
+		if priorityFrom(ctx) == PriorityInteractive {
+			return &RateLimitError{RetryAfter: pause}
+		}
+		// Running out of time while the provider is backing off is reported as rate limiting rather than a timeout
+		select {
+		case <-upstreamClock.After(pause):
+		case <-ctx.Done():
+			return &RateLimitError{RetryAfter: max(pausedUntil.Sub(upstreamClock.Now()), 0)}
+		}
+	}
+	if requests != nil {
+		if err := requests.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	if computeUnits != nil && cost > 0 {
+		return computeUnits.WaitN(ctx, min(cost, computeUnits.Burst()))
+	}
+	return nil
+}
+
+// spend counts a request costing the given credits against the daily quota of the provider. Batch requests are
+// shed once the quota is nearly used up and all requests once it is exhausted, with a RateLimitError lasting until
+// the quota resets at UTC midnight
+func (l *providerLimiter) spend(ctx context.Context, cost int) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := upstreamClock.Now().UTC()
+	if today := now.Truncate(24 * time.Hour); !today.Equal(l.day) {
+		l.day, l.calls, l.credits = today, 0, 0
+	}
+	if quota := l.config.DailyQuota; quota > 0 {
+		exhausted := l.credits+int64(cost) > quota
+		if exhausted || (priorityFrom(ctx) == PriorityBatch && float64(l.credits) >= quotaReserve*float64(quota)) {
+			return &RateLimitError{RetryAfter: l.day.Add(24 * time.Hour).Sub(now)}
+		}
+	}
+	l.calls++
+	l.credits += int64(cost)
+	return nil
+}
+
+// budget returns the usage of the provider during the current UTC day
+func (l *providerLimiter) budget(host string) ProviderBudget {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	budget := ProviderBudget{Host: host, Day: upstreamClock.Now().UTC().Truncate(24 * time.Hour), Quota: l.config.DailyQuota}
+	if budget.Day.Equal(l.day) {
+		budget.Calls, budget.Credits = l.calls, l.credits
+	}
+	if budget.Quota > 0 {
+		budget.Remaining = max(budget.Quota-budget.Credits, 0)
+		budget.Low = float64(budget.Credits) >= quotaReserve*float64(budget.Quota)
+	}
+	return budget
+}
+
+// pause keeps requests to the provider from being sent for the given duration
+func (l *providerLimiter) pause(duration time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if until := upstreamClock.Now().Add(duration); until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+}
+
+// rejected backs off after a rate limited response, honouring retryAfter when the provider sent one
+func (l *providerLimiter) rejected(retryAfter time.Duration) {
+	l.mu.Lock()
+	backoff := retryAfter
+	if backoff <= 0 {
+		backoff = min(minUpstreamBackoff<<min(l.strikes, 5), maxUpstreamBackoff)
+	}
+	l.strikes++
+	l.setFactor(max(l.factor/2, 1.0/8))
+	l.mu.Unlock()
+
+	l.pause(backoff)
+}
+
+// accepted resets the backoff and recovers a tenth of the configured rate
+func (l *providerLimiter) accepted() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.strikes = 0
+	if l.factor < 1 {
+		l.setFactor(min(l.factor+0.1, 1))
+	}
+}
+
+// setFactor scales the limits to a share of the configured rate, l.mu must be held
+func (l *providerLimiter) setFactor(factor float64) {
+	l.factor = factor
+	if l.requests != nil {
+		l.requests.SetLimit(rate.Limit(l.config.RequestsPerSecond * factor))
+	}
+	if l.computeUnits != nil {
+		l.computeUnits.SetLimit(rate.Limit(l.config.ComputeUnitsPerSecond * factor))
+	}
+}
+
+// parseRetryAfter returns the delay of a Retry-After header given in seconds or as an HTTP date, zero if absent
+func parseRetryAfter(header http.Header) time.Duration {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return date.Sub(upstreamClock.Now())
+	}
+	return 0
+}
+
+// upstreamRejected backs off a provider host after a rate limited response
+func upstreamRejected(host string, retryAfter time.Duration) {
+	providerLimiterFor(host).rejected(retryAfter)
+}
+
+// upstreamAccepted records that a provider host accepted a request
+func upstreamAccepted(host string) {
+	providerLimiterFor(host).accepted()
+}
+
+// ProviderBudget is the upstream usage of a provider host during the current UTC day
+type ProviderBudget struct {
+	Host      string
+	Day       time.Time // Start of the UTC day
+	Calls     int64
+	Credits   int64 // Compute units of the calls
+	Quota     int64 // Credits per day, unlimited if zero
+	Remaining int64 // Credits left until the quota resets, zero without a quota
+	Low       bool  // Whether the quota is nearly used up, batch requests are then shed and stale data preferred
+}
+
+// ProviderBudgets returns the usage of every provider host requests were sent to or that has a limit, ordered by host
+func ProviderBudgets() []ProviderBudget {
+	providerLimitersMu.Lock()
+	limiters := make(map[string]*providerLimiter, len(providerLimiters))
+	for host, limiter := range providerLimiters {
+		limiters[host] = limiter
+	}
+	providerLimitersMu.Unlock()
+
+	budgets := make([]ProviderBudget, 0, len(limiters))
+	for host, limiter := range limiters {
+		budgets = append(budgets, limiter.budget(host))
+	}
+	sort.Slice(budgets, func(i, j int) bool {
+		return budgets[i].Host < budgets[j].Host
+	})
+	return budgets
+}
+
+// upstreamBudgetLow reports whether the daily quota of any provider is nearly used up
+func upstreamBudgetLow() bool {
+	for _, budget := range ProviderBudgets() {
+		if budget.Low {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// relayTimeout bounds a single request to the relay data API
+const relayTimeout = 10 * time.Second
+
+// DefaultRelays returns the MEV-Boost relays queried for bid data when none are configured
+func DefaultRelays() []string {
+	return []string{
+		"https://boost-relay.flashbots.net",
+		"https://relay.ultrasound.money",
+		"https://bloxroute.max-profit.blxrbdn.com",
+		"https://agnostic-relay.net",
+		"https://aestus.live",
+		"https://titanrelay.xyz",
+	}
+}
+
+// relayBidTrace represents a bid trace returned by the relay data API
+type relayBidTrace struct {
+	Slot                 string `json:"slot"`
+	BlockHash            string `json:"block_hash"`
+	BuilderPubkey        string `json:"builder_pubkey"`
+	ProposerFeeRecipient string `json:"proposer_fee_recipient"`
+	Value                string `json:"value"` // in Wei
+	NumTx                string `json:"num_tx"`
+	TimestampMs          string `json:"timestamp_ms"`
+}
+
+// RelayBid represents a builder bid as seen by a relay
+type RelayBid struct {
+	Relay         string
+	BuilderPubkey string
+	Builder       string // Normalized builder name, "" if unknown
+	BlockHash     string
+	Value         *big.Int // in Wei
+}
+
+// RelaySummary represents the bids a single relay received for a slot
+type RelaySummary struct {
+	Relay     string
+	BidCount  int
+	TopBid    *RelayBid
+	Delivered bool
+	Error     string // Set when the relay could not be queried
+}
+
+// BidComparison compares the delivered payload of a slot with the best bid available across relays
+type BidComparison struct {
+	Slot        int64
+	Delivered   *RelayBid // nil when no configured relay delivered the payload
+	BestBid     *RelayBid // nil when no bids were found
+	MissedValue *big.Int  // Best bid value minus delivered value in Wei
+	Relays      []RelaySummary
+}
+
+// SetRelays replaces the MEV-Boost relays queried for bid data. Spaces around the URLs are trimmed and empty
+// entries are skipped
+func (s *EthereumService) SetRelays(relays []string) error {
+	trimmed := make([]string, 0, len(relays))
+	for _, relay := range relays {
+		relay = strings.TrimSpace(relay)
+		if relay == "" {
+			continue
+		}
+		if err := validateURL("Relay", relay); err != nil {
+			return err
+		}
+		trimmed = append(trimmed, relay)
+	}
+	s.relays = trimmed
+	return nil
+}
+
+// GetBidComparisonBySlot fetches the bids for a slot from all configured relays and compares
+// the delivered payload with the highest available bid
+func (s *EthereumService) GetBidComparisonBySlot(ctx context.Context, slot int64) (*BidComparison, error) {
+	currentSlot := s.wallClockSlot()
+	if slot > currentSlot {
+		return nil, fmt.Errorf("%w (current slot: %d)", ErrFutureSlot, currentSlot)
+	}
+
+	summaries := make([]RelaySummary, len(s.relays))
+	delivered := make([]*RelayBid, len(s.relays))
+
+	// Relays are independent providers, so query them concurrently
+	var wg sync.WaitGroup
+	for i, relay := range s.relays {
+		wg.Add(1)
+		go func(i int, relay string) {
+			defer wg.Done()
+			summaries[i], delivered[i] = s.getRelaySummary(ctx, relay, slot)
+		}(i, relay)
+	}
+	wg.Wait()
+
+	comparison := &BidComparison{
+		Slot:        slot,
+		MissedValue: big.NewInt(0),
+		Relays:      summaries,
+	}
+
+	failures := 0
+	for i, summary := range summaries {
+		if summary.Error != "" {
+			failures++
+		}
+		if delivered[i] != nil && comparison.Delivered == nil {
+			comparison.Delivered = delivered[i]
+		}
+		if summary.TopBid != nil && (comparison.BestBid == nil || summary.TopBid.Value.Cmp(comparison.BestBid.Value) > 0) {
+			comparison.BestBid = summary.TopBid
+		}
+	}
+
+	if len(s.relays) > 0 && failures == len(s.relays) {
+		return nil, fmt.Errorf("%w: all relays failed", ErrRPCFailed)
+	}
+
+	if comparison.Delivered != nil && comparison.BestBid != nil {
+		missed := new(big.Int).Sub(comparison.BestBid.Value, comparison.Delivered.Value)
+		if missed.Sign() > 0 {
+			comparison.MissedValue = missed
+		}
+	}
+
+	return comparison, nil
+}
+
+// getRelaySummary queries a single relay for the delivered payload and received bids of a slot
+func (s *EthereumService) getRelaySummary(ctx context.Context, relay string, slot int64) (RelaySummary, *RelayBid) {
+	summary := RelaySummary{Relay: relay}
+
+	var deliveredTraces []relayBidTrace
+	if err := s.getRelayJSON(ctx, relay, "proposer_payload_delivered", slot, &deliveredTraces); err != nil {
+		summary.Error = err.Error()
+		return summary, nil
+	}
+
+	var receivedTraces []relayBidTrace
+	if err := s.getRelayJSON(ctx, relay, "builder_blocks_received", slot, &receivedTraces); err != nil {
+		summary.Error = err.Error()
+		return summary, nil
+	}
+
+	var delivered *RelayBid
+	if len(deliveredTraces) > 0 {
+		delivered = s.newRelayBid(relay, deliveredTraces[0])
+		summary.Delivered = true
+	}
+
+	summary.BidCount = len(receivedTraces)
+	for _, trace := range receivedTraces {
+		bid := s.newRelayBid(relay, trace)
+		if summary.TopBid == nil || bid.Value.Cmp(summary.TopBid.Value) > 0 {
+			summary.TopBid = bid
+		}
+	}
+
+	// Some relays don't expose received bids, so the delivered payload is the best we know of
+	if summary.TopBid == nil && delivered != nil {
+		summary.TopBid = delivered
+	}
+
+	return summary, delivered
+}
+
+// getRelayJSON fetches a bid trace list from the relay data API
+func (s *EthereumService) getRelayJSON(ctx context.Context, relay, trace string, slot int64, out interface{}) error {
+	endpoint := fmt.Sprintf("%s/relay/v1/data/bidtraces/%s?slot=%d", strings.TrimSuffix(relay, "/"), trace, slot)
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.relayClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrRPCFailed, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: relay responded with status %d", ErrRPCFailed, resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode relay response: %v", err)
+	}
+	return nil
+}
+
+// newRelayBid converts a relay bid trace into a RelayBid
+func (s *EthereumService) newRelayBid(relay string, trace relayBidTrace) *RelayBid {
+	value, ok := new(big.Int).SetString(trace.Value, 10)
+	if !ok {
+		value = big.NewInt(0)
+	}
+
+	bid := &RelayBid{
+		Relay:         relay,
+		BuilderPubkey: trace.BuilderPubkey,
+		BlockHash:     trace.BlockHash,
+		Value:         value,
+	}
+	if s.builders != nil {
+		bid.Builder = s.builders.Resolve("", trace.BuilderPubkey)
+	}
+	return bid
+}
+
+// getDeliveringRelay returns the first configured relay that delivered the payload of a slot, or "" if none did
+func (s *EthereumService) getDeliveringRelay(ctx context.Context, slot int64) string {
+	relay, _ := s.getDeliveredTrace(ctx, slot)
+	return relay
+}
+
+// getDeliveredTrace returns the first configured relay that delivered the payload of a slot and its bid trace,
+// or "" and nil if none did
+func (s *EthereumService) getDeliveredTrace(ctx context.Context, slot int64) (string, *relayBidTrace) {
+	results := make([]*relayBidTrace, len(s.relays))
+
+	var wg sync.WaitGroup
+	for i, relay := range s.relays {
+		wg.Add(1)
+		go func(i int, relay string) {
+			defer wg.Done()
+			var traces []relayBidTrace
+			if err := s.getRelayJSON(ctx, relay, "proposer_payload_delivered", slot, &traces); err == nil && len(traces) > 0 {
+				results[i] = &traces[0]
+			}
+		}(i, relay)
+	}
+	wg.Wait()
+
+	for i, trace := range results {
+		if trace != nil {
+			return s.relays[i], trace
+		}
+	}
+	return "", nil
+}
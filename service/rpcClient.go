@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// RPCExecutionClient is an ExecutionClient backed by go-ethereum's rpc client,
+// supporting http(s) as well as ws(s) endpoints
+type RPCExecutionClient struct {
+	rpc  *rpc.Client
+	eth  *ethclient.Client
+	host string // Provider host the rate limit is tracked for
+}
+
+// NewRPCExecutionClient dials the JSON-RPC endpoint at rpcURL, HTTP endpoints use the given client.
+// Additional options apply to websocket endpoints, e.g. authentication headers
+func NewRPCExecutionClient(ctx context.Context, rpcURL string, client *http.Client, options ...rpc.ClientOption) (*RPCExecutionClient, error) {
+	rpcClient, err := rpc.DialOptions(ctx, rpcURL, append([]rpc.ClientOption{rpc.WithHTTPClient(client)}, options...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial execution endpoint: %w", err)
+	}
+	var host string
+	if parsedURL, err := url.Parse(rpcURL); err == nil {
+		host = parsedURL.Host
+	}
+	return &RPCExecutionClient{
+		rpc:  rpcClient,
+		eth:  ethclient.NewClient(rpcClient),
+		host: host,
+	}, nil
+}
+
+// Call performs a JSON-RPC call against the execution endpoint and decodes the result into out
+func (e *RPCExecutionClient) Call(ctx context.Context, method string, params []interface{}, out interface{}) error {
+	return e.throttle(ctx, computeUnits(method), func() error {
+		return e.rpc.CallContext(ctx, out, method, params...)
+	})
+}
+
+// SubscribeNewHeads streams the number of every new head to handler until the context is cancelled, HTTP endpoints
+// can't stream and return an error
+func (e *RPCExecutionClient) SubscribeNewHeads(ctx context.Context, handler func(number uint64)) error {
+	headers := make(chan *types.Header, 1)
+	subscription, err := e.eth.SubscribeNewHead(ctx, headers)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to new heads: %w", err)
+	}
+
+	go func() {
+		defer subscription.Unsubscribe()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case err := <-subscription.Err():
+				if err != nil {
+					log.Printf("Execution head subscription ended: %v", err)
+				}
+				return
+			case header := <-headers:
+				handler(header.Number.Uint64())
+			}
+		}
+	}()
+	return nil
+}
+
+// BlockByNumber returns the block with its transactions at the given height
+func (e *RPCExecutionClient) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	var block *types.Block
+	err := e.throttle(ctx, computeUnits("eth_getBlockByNumber"), func() (err error) {
+		block, err = e.eth.BlockByNumber(ctx, number)
+		return err
+	})
+	return block, err
+}
+
+// BlockByHash returns the block with its transactions for the given hash
+func (e *RPCExecutionClient) BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
+	var block *types.Block
+	err := e.throttle(ctx, computeUnits("eth_getBlockByHash"), func() (err error) {
+		block, err = e.eth.BlockByHash(ctx, hash)
+		return err
+	})
+	return block, err
+}
+
+// BlockReceipts returns the receipts of all transactions in the block with the given hash
+func (e *RPCExecutionClient) BlockReceipts(ctx context.Context, hash common.Hash) ([]*types.Receipt, error) {
+	var receipts []*types.Receipt
+	err := e.throttle(ctx, computeUnits("eth_getBlockReceipts"), func() (err error) {
+		receipts, err = e.eth.BlockReceipts(ctx, rpc.BlockNumberOrHashWithHash(hash, false))
+		return err
+	})
+	return receipts, err
+}
+
+// BlockWithReceipts returns the block at number together with its receipts, fetched in a single batch request
+func (e *RPCExecutionClient) BlockWithReceipts(ctx context.Context, number *big.Int) (*types.Block, []*types.Receipt, error) {
+	var rawBlock json.RawMessage
+	var receipts []*types.Receipt
+	batch := []rpc.BatchElem{
+		{Method: "eth_getBlockByNumber", Args: []interface{}{hexutil.EncodeBig(number), true}, Result: &rawBlock},
+		{Method: "eth_getBlockReceipts", Args: []interface{}{hexutil.EncodeBig(number)}, Result: &receipts},
+	}
+
+	err := e.throttle(ctx, computeUnits("eth_getBlockByNumber")+computeUnits("eth_getBlockReceipts"), func() error {
+		if err := e.rpc.BatchCallContext(ctx, batch); err != nil {
+			return err
+		}
+		// Surface per-call errors so rate limited batches are retried as a whole
+		for i := range batch {
+			if err := batch[i].Error; err != nil {
+				batch[i].Error = nil
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(rawBlock) == 0 || string(rawBlock) == "null" {
+		return nil, nil, ethereum.NotFound
+	}
+	block, err := decodeBlock(rawBlock)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrUpstreamInvalidResponse, err)
+	}
+	return block, receipts, nil
+}
+
+// rpcBlockBody represents the body fields of a JSON-RPC block with full transactions
+type rpcBlockBody struct {
+	Transactions []*types.Transaction `json:"transactions"`
+	Withdrawals  []*types.Withdrawal  `json:"withdrawals"`
+}
+
+// decodeBlock decodes a JSON-RPC block, the header and body are decoded separately
+// since types.Header implements its own JSON decoding
+func decodeBlock(raw json.RawMessage) (*types.Block, error) {
+	var header types.Header
+	if err := json.Unmarshal(raw, &header); err != nil {
+		return nil, fmt.Errorf("failed to decode block header: %v", err)
+	}
+
+	var body rpcBlockBody
+	if err := json.Unmarshal(raw, &body); err != nil {
+		return nil, fmt.Errorf("failed to decode block body: %v", err)
+	}
+
+	return types.NewBlockWithHeader(&header).WithBody(types.Body{
+		Transactions: body.Transactions,
+		Withdrawals:  body.Withdrawals,
+	}), nil
+}
+
+// throttle runs call, costing the given compute units, under the provider's and the shared upstream rate limit,
+// retrying once the provider accepts requests again when it rejects it. JSON-RPC errors don't carry the response
+// headers, a Retry-After of HTTP endpoints is applied by the upstream transport
+func (e *RPCExecutionClient) throttle(ctx context.Context, cost int, call func() error) error {
+	if err := waitForUpstream(ctx, e.host, cost); err != nil {
+		return wrapUpstreamError(ctx, err)
+	}
+
+	err := call()
+	if isRateLimited(err) {
+		upstreamRejected(e.host, 0)
+		return e.throttle(ctx, cost, call)
+	}
+	upstreamAccepted(e.host)
+
+	// Keep not found distinguishable for callers
+	if err != nil && !errors.Is(err, ethereum.NotFound) {
+		return wrapUpstreamError(ctx, err)
+	}
+	return err
+}
+
+// isRateLimited reports whether err is QuickNode's rate limit error
+func isRateLimited(err error) bool {
+	if err == nil {
+		return false
+	}
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	return strings.Contains(err.Error(), "request limit reached")
+}
+
+// callRPC performs a JSON-RPC call against the configured execution client and decodes the result into out
+func (s *EthereumService) callRPC(ctx context.Context, method string, params []interface{}, out interface{}) error {
+	return trackUpstream(ctx, func(ctx context.Context) error {
+		return s.executionClient().Call(ctx, method, params, out)
+	})
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// Task is a background job run by the Scheduler
+type Task struct {
+	Name       string
+	Schedule   Schedule
+	RunAtStart bool // Also run the task once when the scheduler starts
+	Run        func(ctx context.Context) error
+}
+
+// TaskStatus reports the runs of a scheduled task
+type TaskStatus struct {
+	Name         string
+	Schedule     string
+	Running      bool
+	Runs         int64 // Completed runs, including failed ones
+	Failures     int64
+	Skipped      int64 // Runs skipped as the previous run was still going
+	LastStart    time.Time
+	LastDuration time.Duration
+	LastError    string // Error of the last run, "" if it succeeded
+	NextRun      time.Time
+}
+
+// scheduledTask is a registered task and its run status
+type scheduledTask struct {
+	task   Task
+	clock  Clock
+	mu     sync.Mutex
+	status TaskStatus
+}
+
+// Scheduler runs registered tasks on their schedules. A run is skipped while the previous run of the same task is
+// still going, so slow tasks never pile up
+type Scheduler struct {
+	mu    sync.Mutex
+	tasks []*scheduledTask
+	clock Clock
+}
+
+// NewScheduler creates a scheduler without tasks
+func NewScheduler() *Scheduler {
+	return &Scheduler{clock: SystemClock{}}
+}
+
+// SetClock replaces the clock tasks are scheduled by, it must be called before tasks are registered
+func (s *Scheduler) SetClock(clock Clock) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clock = clock
+}
+
+// Register adds a task, tasks registered after Run are not started
+func (s *Scheduler) Register(task Task) {
+	schedule := fmt.Sprint(task.Schedule)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tasks = append(s.tasks, &scheduledTask{
+		task:   task,
+		clock:  s.clock,
+		status: TaskStatus{Name: task.Name, Schedule: schedule},
+	})
+}
+
+// Run starts all registered tasks and blocks until the context is cancelled
+func (s *Scheduler) Run(ctx context.Context) {
+	s.mu.Lock()
+	tasks := append([]*scheduledTask(nil), s.tasks...)
+	s.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, task := range tasks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			task.loop(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+// Status returns the run status of all registered tasks in registration order
+func (s *Scheduler) Status() []TaskStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statuses := make([]TaskStatus, 0, len(s.tasks))
+	for _, task := range s.tasks {
+		task.mu.Lock()
+		statuses = append(statuses, task.status)
+		task.mu.Unlock()
+	}
+	return statuses
+}
+
+// loop triggers the task at each scheduled time until the context is cancelled
+func (t *scheduledTask) loop(ctx context.Context) {
+	if t.task.RunAtStart {
+		t.trigger(ctx)
+	}
+
+	for {
+		next := t.task.Schedule.Next(t.clock.Now())
+		t.mu.Lock()
+		t.status.NextRun = next
+		t.mu.Unlock()
+		if next.IsZero() {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.clock.After(next.Sub(t.clock.Now())):
+			t.trigger(ctx)
+		}
+	}
+}
+
+// trigger starts a run of the task unless the previous run is still going
+func (t *scheduledTask) trigger(ctx context.Context) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.status.Running {
+		t.status.Skipped++
+		log.Printf("Scheduler: skipping %s, the previous run is still going", t.task.Name)
+		return
+	}
+	t.status.Running = true
+	t.status.LastStart = t.clock.Now().UTC()
+
+	go func() {
+		start := t.clock.Now()
+		err := t.task.Run(ctx)
+
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		t.status.Running = false
+		t.status.Runs++
+		t.status.LastDuration = t.clock.Now().Sub(start)
+		t.status.LastError = ""
+		if err != nil {
+			t.status.Failures++
+			t.status.LastError = err.Error()
+			log.Printf("Scheduler: %s failed: %v", t.task.Name, err)
+		}
+	}()
+}
@@ -0,0 +1,264 @@
+package service
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+	"time"
+)
+
+// Statistics related error definitions
+var (
+	ErrInvalidRange = errors.New("invalid range")
+)
+
+// SlotsPerEpoch is the number of slots in an epoch
+const SlotsPerEpoch = 32
+
+// maxStatsEpochs bounds the range a statistics query may cover
+const maxStatsEpochs = 50000
+
+// ShareStat represents the number of blocks attributed to an entity and its share of the total
+type ShareStat struct {
+	Name   string
+	Blocks int
+	Share  float64 // Fraction of the considered blocks
+}
+
+// MEVStats represents MEV statistics aggregated over a range of epochs
+type MEVStats struct {
+	FromEpoch      int64
+	ToEpoch        int64
+	Blocks         int
+	MEVBlocks      int
+	VanillaBlocks  int
+	MEVShare       float64
+	AverageReward  *big.Int    // in Wei
+	MedianReward   *big.Int    // in Wei
+	AverageMEV     *big.Int    // Average reward of MEV blocks in Wei
+	AverageVanilla *big.Int    // Average reward of vanilla blocks in Wei
+	Builders       []ShareStat // Share of MEV blocks per builder
+	Relays         []ShareStat // Share of MEV blocks per relay
+	Pools          []PoolStat  // Blocks per staking pool of the proposer
+}
+
+// PoolStat represents the blocks proposed by validators of a staking pool within a range
+type PoolStat struct {
+	Name          string // Staking pool, PoolOther for untagged proposers or "unknown" if the proposer is not indexed
+	Blocks        int
+	Share         float64 // Fraction of all blocks in the range
+	MEVBlocks     int
+	MEVShare      float64  // Fraction of the pool's blocks built via MEV-Boost
+	AverageReward *big.Int // in Wei
+}
+
+// GetMEVStats aggregates the indexed block rewards within [fromEpoch, toEpoch]
+func (s *EthereumService) GetMEVStats(fromEpoch, toEpoch int64) (*MEVStats, error) {
+	if fromEpoch < 0 || toEpoch < fromEpoch || toEpoch-fromEpoch > maxStatsEpochs {
+		return nil, ErrInvalidRange
+	}
+
+	stats := &MEVStats{
+		FromEpoch: fromEpoch,
+		ToEpoch:   toEpoch,
+	}
+
+	var all, mev, vanilla []*big.Int
+	builders := make(map[string]int)
+	relays := make(map[string]int)
+	pools := make(map[string]*PoolStat)
+	poolRewards := make(map[string][]*big.Int)
+
+	for _, slot := range s.index.RewardSlots(fromEpoch*SlotsPerEpoch, (toEpoch+1)*SlotsPerEpoch-1) {
+		reward, ok := s.index.GetReward(slot)
+		if !ok || reward.Reward == nil {
+			continue
+		}
+		wei := reward.Wei()
+
+		all = append(all, wei)
+		pool := s.proposerPool(slot)
+		if pools[pool] == nil {
+			pools[pool] = &PoolStat{Name: pool}
+		}
+		pools[pool].Blocks++
+		poolRewards[pool] = append(poolRewards[pool], wei)
+		if reward.Status != "mev" {
+			vanilla = append(vanilla, wei)
+			continue
+		}
+
+		mev = append(mev, wei)
+		pools[pool].MEVBlocks++
+		builders[nameOrUnknown(reward.Builder)]++
+		relays[nameOrUnknown(reward.Relay)]++
+	}
+
+	stats.Blocks = len(all)
+	stats.MEVBlocks = len(mev)
+	stats.VanillaBlocks = len(vanilla)
+	if stats.Blocks > 0 {
+		stats.MEVShare = float64(stats.MEVBlocks) / float64(stats.Blocks)
+	}
+	stats.AverageReward = averageBig(all)
+	stats.MedianReward = medianBig(all)
+	stats.AverageMEV = averageBig(mev)
+	stats.AverageVanilla = averageBig(vanilla)
+	stats.Builders = shareStats(builders, stats.MEVBlocks)
+	stats.Relays = shareStats(relays, stats.MEVBlocks)
+	stats.Pools = poolStats(pools, poolRewards, stats.Blocks)
+
+	return stats, nil
+}
+
+// poolStats completes the per-pool block counts with shares and average rewards, ordered by block count
+func poolStats(pools map[string]*PoolStat, rewards map[string][]*big.Int, total int) []PoolStat {
+	stats := make([]PoolStat, 0, len(pools))
+	for name, pool := range pools {
+		pool.Share = float64(pool.Blocks) / float64(total)
+		pool.MEVShare = float64(pool.MEVBlocks) / float64(pool.Blocks)
+		pool.AverageReward = averageBig(rewards[name])
+		stats = append(stats, *pool)
+	}
+	sort.Slice(stats, func(a, b int) bool {
+		if stats[a].Blocks != stats[b].Blocks {
+			return stats[a].Blocks > stats[b].Blocks
+		}
+		return stats[a].Name < stats[b].Name
+	})
+	return stats
+}
+
+// indexedRewards returns the indexed rewards within [fromSlot, toSlot] ordered by slot
+func (s *EthereumService) indexedRewards(fromSlot, toSlot int64) []*BlockReward {
+	slots := s.index.RewardSlots(fromSlot, toSlot)
+	rewards := make([]*BlockReward, 0, len(slots))
+	for _, slot := range slots {
+		if reward, ok := s.index.GetReward(slot); ok && reward.Reward != nil {
+			rewards = append(rewards, reward)
+		}
+	}
+	return rewards
+}
+
+// shareStats converts per-entity block counts into shares ordered by block count
+func shareStats(counts map[string]int, total int) []ShareStat {
+	stats := make([]ShareStat, 0, len(counts))
+	for name, blocks := range counts {
+		stats = append(stats, ShareStat{
+			Name:   name,
+			Blocks: blocks,
+			Share:  float64(blocks) / float64(total),
+		})
+	}
+	sort.Slice(stats, func(a, b int) bool {
+		if stats[a].Blocks != stats[b].Blocks {
+			return stats[a].Blocks > stats[b].Blocks
+		}
+		return stats[a].Name < stats[b].Name
+	})
+	return stats
+}
+
+// averageBig returns the average of values, or zero for an empty slice
+func averageBig(values []*big.Int) *big.Int {
+	if len(values) == 0 {
+		return big.NewInt(0)
+	}
+	sum := new(big.Int)
+	for _, value := range values {
+		sum.Add(sum, value)
+	}
+	return sum.Div(sum, big.NewInt(int64(len(values))))
+}
+
+// medianBig returns the median of values, or zero for an empty slice
+func medianBig(values []*big.Int) *big.Int {
+	return percentileBig(values, 50)
+}
+
+// percentileBig returns the nearest-rank percentile of values, or zero for an empty slice
+func percentileBig(values []*big.Int, percentile float64) *big.Int {
+	if len(values) == 0 {
+		return big.NewInt(0)
+	}
+
+	sorted := append([]*big.Int(nil), values...)
+	sort.Slice(sorted, func(a, b int) bool {
+		return sorted[a].Cmp(sorted[b]) < 0
+	})
+
+	rank := int(percentile/100*float64(len(sorted)) + 0.5)
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+	return new(big.Int).Set(sorted[rank-1])
+}
+
+// nameOrUnknown returns name, or "unknown" when it is empty
+func nameOrUnknown(name string) string {
+	if name == "" {
+		return "unknown"
+	}
+	return name
+}
+
+// SecondsPerSlot is the duration of a slot in seconds
+const SecondsPerSlot = 12
+
+// RewardStats represents proposer reward percentiles over a window of indexed blocks
+type RewardStats struct {
+	FromSlot int64
+	ToSlot   int64
+	Blocks   int
+	Min      *big.Int // in Wei
+	Median   *big.Int // in Wei
+	P90      *big.Int // in Wei
+	P99      *big.Int // in Wei
+	Max      *big.Int // in Wei
+	MEVRatio float64  // Fraction of blocks built via MEV-Boost
+}
+
+// GetRewardStats computes proposer reward percentiles over the indexed blocks of the given
+// window, ending at the latest indexed slot
+func (s *EthereumService) GetRewardStats(window time.Duration) (*RewardStats, error) {
+	if window <= 0 {
+		return nil, ErrInvalidRange
+	}
+
+	toSlot := s.index.LatestRewardSlot()
+	fromSlot := toSlot - int64(window/(SecondsPerSlot*time.Second)) + 1
+	if fromSlot < 0 {
+		fromSlot = 0
+	}
+
+	rewards := s.indexedRewards(fromSlot, toSlot)
+
+	values := make([]*big.Int, 0, len(rewards))
+	mevBlocks := 0
+	for _, reward := range rewards {
+		values = append(values, reward.Wei())
+		if reward.Status == "mev" {
+			mevBlocks++
+		}
+	}
+
+	stats := &RewardStats{
+		FromSlot: fromSlot,
+		ToSlot:   toSlot,
+		Blocks:   len(rewards),
+		Min:      percentileBig(values, 0),
+		Median:   percentileBig(values, 50),
+		P90:      percentileBig(values, 90),
+		P99:      percentileBig(values, 99),
+		Max:      percentileBig(values, 100),
+	}
+	if len(rewards) > 0 {
+		stats.MEVRatio = float64(mevBlocks) / float64(len(rewards))
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// syncCommitteeTTL is how long a sync committee is cached, its members can't change once they were selected
+const syncCommitteeTTL = SlotsPerSyncPeriod * SecondsPerSlot * time.Second
+
+// SyncCommitteeMember represents a validator assigned to a sync committee
+type SyncCommitteeMember struct {
+	Index  int64
+	Pubkey string
+}
+
+// SyncCommittee represents a sync committee and the slots it serves
+type SyncCommittee struct {
+	SyncPeriod      int64
+	StartSlot       int64
+	EndSlot         int64
+	AggregatePubkey string // BLS aggregate of the members' pubkeys, "" if a pubkey could not be resolved
+	Members         []SyncCommitteeMember
+}
+
+// stateSyncCommitteeResponse represents the response from /eth/v1/beacon/states/{state_id}/sync_committees
+type stateSyncCommitteeResponse struct {
+	Data struct {
+		Validators []string `json:"validators"`
+	} `json:"data"`
+}
+
+// GetNextSyncCommittee retrieves the sync committee of the period following the head,
+// taken from the next_sync_committee field of the head state
+func (s *EthereumService) GetNextSyncCommittee(ctx context.Context) (*SyncCommittee, error) {
+	headSlot, err := s.getHeadSlot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	period := headSlot/SlotsPerSyncPeriod + 1
+	if cached, ok := s.cacheGet(ctx, syncCommitteeKey(period)); ok {
+		return cached.(*SyncCommittee), nil
+	}
+
+	result, err := s.getSyncCommittee(ctx, "head", period)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cache.Set(syncCommitteeKey(period), result, syncCommitteeTTL)
+	return result, nil
+}
+
+// syncCommitteeKey returns the cache key of the sync committee of a period
+func syncCommitteeKey(period int64) string {
+	return fmt.Sprintf("sync:period:%d", period)
+}
+
+// GetSyncCommitteeBySlot retrieves the sync committee serving a slot with the members' indices and pubkeys and the
+// committee's aggregate pubkey. The committee is taken from the sync_committees of the slot's state, cached for its
+// period. Committees prefetched by the sync committee prefetch task are served from memory
+func (s *EthereumService) GetSyncCommitteeBySlot(ctx context.Context, slot int64) (*SyncCommittee, error) {
+	period := slot / SlotsPerSyncPeriod
+	if cached, ok := s.cacheGet(ctx, syncCommitteeKey(period)); ok {
+		return cached.(*SyncCommittee), nil
+	}
+
+	committee, err := s.getSyncCommittee(ctx, strconv.FormatInt(slot, 10), period)
+	if err != nil {
+		if errors.Is(err, ErrSlotNotFound) {
+			return nil, fmt.Errorf("%w: no state at slot %d", ErrSlotNotFound, slot)
+		}
+		return nil, err
+	}
+
+	s.cache.Set(syncCommitteeKey(period), committee, syncCommitteeTTL)
+	return committee, nil
+}
+
+// aggregatePubkeys returns the BLS aggregate of the members' pubkeys, which is the aggregate_pubkey of the sync
+// committee in the state, so it needs no further lookup. "" if a pubkey is missing or invalid
+func aggregatePubkeys(members []SyncCommitteeMember) string {
+	if len(members) == 0 {
+		return ""
+	}
+
+	var aggregate bls.G1Jac
+	for _, member := range members {
+		raw, err := hexutil.Decode(member.Pubkey)
+		if err != nil {
+			return ""
+		}
+		var pubkey bls.G1Affine
+		if _, err := pubkey.SetBytes(raw); err != nil {
+			return ""
+		}
+		aggregate.AddMixed(&pubkey)
+	}
+
+	var pubkey bls.G1Affine
+	pubkey.FromJacobian(&aggregate)
+	compressed := pubkey.Bytes()
+	return hexutil.Encode(compressed[:])
+}
+
+// prefetchSyncCommittees caches the sync committees of the head's period and the following one,
+// unless the head is still in the already prefetched period, and returns the head's period
+func (s *EthereumService) prefetchSyncCommittees(ctx context.Context, prefetched int64) (int64, error) {
+	head, err := s.GetChainHead(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get chain head: %w", err)
+	}
+	if head.SyncPeriod == prefetched {
+		return prefetched, nil
+	}
+
+	current, err := s.getSyncCommittee(ctx, "head", head.SyncPeriod)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get sync committee of period %d: %w", head.SyncPeriod, err)
+	}
+	s.cache.Set(syncCommitteeKey(head.SyncPeriod), current, syncCommitteeTTL)
+
+	next, err := s.getSyncCommittee(ctx, "head", head.SyncPeriod+1)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get sync committee of period %d: %w", head.SyncPeriod+1, err)
+	}
+	s.cache.Set(syncCommitteeKey(head.SyncPeriod+1), next, syncCommitteeTTL)
+
+	return head.SyncPeriod, nil
+}
+
+// getSyncCommittee retrieves the members of the sync committee of a period as seen from a state
+func (s *EthereumService) getSyncCommittee(ctx context.Context, stateID string, period int64) (*SyncCommittee, error) {
+	startSlot := period * SlotsPerSyncPeriod
+	var committee stateSyncCommitteeResponse
+	path := fmt.Sprintf("/eth/v1/beacon/states/%s/sync_committees?epoch=%d", stateID, startSlot/SlotsPerEpoch)
+	if err := s.getBeaconJSON(ctx, path, &committee); err != nil {
+		return nil, err
+	}
+
+	pubkeys, err := s.getValidatorPubkeys(ctx, committee.Data.Validators)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SyncCommittee{
+		SyncPeriod: period,
+		StartSlot:  startSlot,
+		EndSlot:    startSlot + SlotsPerSyncPeriod - 1,
+		Members:    make([]SyncCommitteeMember, 0, len(committee.Data.Validators)),
+	}
+	for _, index := range committee.Data.Validators {
+		result.Members = append(result.Members, SyncCommitteeMember{
+			Index:  parseDecimal(index),
+			Pubkey: pubkeys[index],
+		})
+	}
+	result.AggregatePubkey = aggregatePubkeys(result.Members)
+	return result, nil
+}
+
+// getValidatorPubkeys resolves validator indices to public keys at the head state
+func (s *EthereumService) getValidatorPubkeys(ctx context.Context, indices []string) (map[string]string, error) {
+	pubkeys := make(map[string]string, len(indices))
+	if len(indices) == 0 {
+		return pubkeys, nil
+	}
+
+	// A committee can contain the same validator more than once
+	seen := make(map[string]bool, len(indices))
+	unique := make([]string, 0, len(indices))
+	for _, index := range indices {
+		if !seen[index] {
+			seen[index] = true
+			unique = append(unique, index)
+		}
+	}
+
+	var validators validatorsResponse
+	path := "/eth/v1/beacon/states/head/validators?id=" + strings.Join(unique, ",")
+	if err := s.getBeaconJSON(ctx, path, &validators); err != nil {
+		return nil, err
+	}
+
+	for _, validator := range validators.Data {
+		pubkeys[validator.Index] = validator.Validator.Pubkey
+	}
+	return pubkeys, nil
+}
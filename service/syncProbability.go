@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// MaxSyncProbabilityPeriods bounds the number of upcoming sync committee periods a probability is computed for
+const MaxSyncProbabilityPeriods = 100
+
+// SyncPeriodProbability represents the chance of a validator being selected for the sync committee of a period
+type SyncPeriodProbability struct {
+	Period      int64
+	StartSlot   int64
+	EndSlot     int64
+	Probability float64
+	Known       bool // Whether the committee is already selected, the probability is then 0 or 1
+}
+
+// SyncCommitteeProbability represents the chances of a validator being selected for upcoming sync committees
+type SyncCommitteeProbability struct {
+	ValidatorIndex   int64
+	Status           string
+	EffectiveBalance int64 // in GWEI
+	ActiveValidators int
+	ActiveBalance    int64 // in GWEI
+
+	PeriodProbability  float64 // Chance of selection in a period whose committee is not selected yet
+	Probability        float64 // Chance of selection in at least one of the periods
+	ExpectedSelections float64
+	Periods            []SyncPeriodProbability // Upcoming periods starting with the next one
+}
+
+// GetSyncCommitteeProbability computes the probability of a validator being selected for the sync committees of the
+// next periods from the cached active set. The committee of the next period is already part of the head state, so
+// its membership is looked up instead. Validators that are not active are never selected
+func (s *EthereumService) GetSyncCommitteeProbability(ctx context.Context, validatorID string, periods int64) (*SyncCommitteeProbability, error) {
+	if periods < 1 || periods > MaxSyncProbabilityPeriods {
+		return nil, fmt.Errorf("%w: between 1 and %d periods expected", ErrInvalidRange, MaxSyncProbabilityPeriods)
+	}
+
+	status, err := s.GetValidatorStatus(ctx, validatorID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	active, err := s.GetActiveSet(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	next, err := s.GetNextSyncCommittee(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SyncCommitteeProbability{
+		ValidatorIndex:   status.Index,
+		Status:           status.Status,
+		EffectiveBalance: status.EffectiveBalance,
+		ActiveValidators: active.Validators,
+		ActiveBalance:    active.TotalEffectiveBalance,
+		Periods:          make([]SyncPeriodProbability, 0, periods),
+	}
+	if strings.HasPrefix(status.Status, "active") {
+		result.PeriodProbability = syncSelectionProbability(status.EffectiveBalance, active.TotalEffectiveBalance)
+	}
+
+	missed := 1.0
+	for i := int64(0); i < periods; i++ {
+		period := SyncPeriodProbability{
+			Period:      next.SyncPeriod + i,
+			StartSlot:   (next.SyncPeriod + i) * SlotsPerSyncPeriod,
+			Probability: result.PeriodProbability,
+		}
+		period.EndSlot = period.StartSlot + SlotsPerSyncPeriod - 1
+		if i == 0 {
+			period.Known = true
+			period.Probability = 0
+			for _, member := range next.Members {
+				if member.Index == status.Index {
+					period.Probability = 1
+					break
+				}
+			}
+		}
+
+		missed *= 1 - period.Probability
+		result.ExpectedSelections += period.Probability
+		result.Periods = append(result.Periods, period)
+	}
+	result.Probability = 1 - missed
+
+	return result, nil
+}
+
+// syncSelectionProbability returns the chance of a validator being selected at least once for the SyncCommitteeSize
+// seats of a committee. Candidates are sampled with replacement and accepted in proportion to their effective
+// balance, so each seat goes to the validator with its share of the active balance
+func syncSelectionProbability(effectiveBalance, activeBalance int64) float64 {
+	if effectiveBalance <= 0 || activeBalance <= 0 {
+		return 0
+	}
+	share := math.Min(float64(effectiveBalance)/float64(activeBalance), 1)
+	return 1 - math.Pow(1-share, SyncCommitteeSize)
+}
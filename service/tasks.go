@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// epochDuration is the time between two epochs
+const epochDuration = SlotsPerEpoch * SecondsPerSlot * time.Second
+
+// NetworkStatsTask returns the task recomputing the network statistics, run at startup and on the given schedule
+func (s *EthereumService) NetworkStatsTask(schedule Schedule) Task {
+	return Task{
+		Name:       "network-stats",
+		Schedule:   schedule,
+		RunAtStart: true,
+		Run: func(ctx context.Context) error {
+			return s.refreshNetworkStats(WithPriority(ctx, PriorityBatch))
+		},
+	}
+}
+
+// SyncCommitteePrefetchTask returns the task keeping the current and next sync committees cached. It checks once per
+// epoch whether the head entered a new period, warming the cache up right after each period rotation
+func (s *EthereumService) SyncCommitteePrefetchTask() Task {
+	// Runs of a task never overlap, so the closure needs no locking
+	prefetched := int64(-1)
+	return Task{
+		Name:       "sync-committee-prefetch",
+		Schedule:   Every(epochDuration),
+		RunAtStart: true,
+		Run: func(ctx context.Context) error {
+			period, err := s.prefetchSyncCommittees(WithPriority(ctx, PriorityBatch), prefetched)
+			if err != nil {
+				return err
+			}
+			prefetched = period
+			return nil
+		},
+	}
+}
+
+// DailyReportTask returns the task logging a summary of the blocks indexed during the last day
+func (s *EthereumService) DailyReportTask(schedule Schedule) Task {
+	return Task{
+		Name:     "daily-report",
+		Schedule: schedule,
+		Run: func(ctx context.Context) error {
+			rewards, err := s.GetRewardStats(24 * time.Hour)
+			if err != nil {
+				return err
+			}
+			if rewards.Blocks == 0 {
+				return fmt.Errorf("no blocks indexed during the last day")
+			}
+			mev, err := s.GetMEVStats(rewards.FromSlot/SlotsPerEpoch, rewards.ToSlot/SlotsPerEpoch)
+			if err != nil {
+				return err
+			}
+
+			topBuilder := ""
+			if len(mev.Builders) > 0 {
+				topBuilder = mev.Builders[0].Name
+			}
+			slog.Info("Daily report",
+				"from_slot", rewards.FromSlot,
+				"to_slot", rewards.ToSlot,
+				"blocks", rewards.Blocks,
+				"mev_ratio", rewards.MEVRatio,
+				"median_reward_wei", rewards.Median.String(),
+				"p90_reward_wei", rewards.P90.String(),
+				"max_reward_wei", rewards.Max.String(),
+				"top_builder", topBuilder,
+			)
+			return nil
+		},
+	}
+}
+
+// OperatorKeysTask returns the task merging the operator key lists published at a URL into the operator registry,
+// run at startup and on the given schedule
+func (s *EthereumService) OperatorKeysTask(sourceURL string, schedule Schedule) Task {
+	return Task{
+		Name:       "operator-keys",
+		Schedule:   schedule,
+		RunAtStart: true,
+		Run: func(ctx context.Context) error {
+			return s.operators.Refresh(ctx, s.client, sourceURL)
+		},
+	}
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// TransactionReward represents the contribution of a single transaction to the proposer reward
+type TransactionReward struct {
+	Hash                 string
+	Index                int64
+	Type                 int64
+	GasUsed              int64
+	From                 string   // Sender, "" if the transaction is not in the block or its signature can't be recovered
+	To                   string   // Recipient, "" for contract creations
+	EffectivePriorityFee *big.Int // in Wei per gas
+	Contribution         *big.Int // in Wei
+	Share                float64  // Fraction of the priority fees of the block
+}
+
+// GetTransactionRewardsBySlot returns the per-transaction proposer reward breakdown of a slot,
+// ordered by transaction index, or the top N by contribution when top is positive
+func (s *EthereumService) GetTransactionRewardsBySlot(ctx context.Context, slot int64, top int) ([]TransactionReward, error) {
+	// Fetch the block and its receipts in a single batch request
+	block, receipts, err := s.getExecutionBlock(ctx, slot)
+	if err != nil {
+		if errors.Is(err, ErrSlotNotFound) {
+			return nil, ErrSlotNotFound
+		}
+		return nil, fmt.Errorf("failed to get block receipts: %w", err)
+	}
+
+	baseFeePerGas := block.BaseFee()
+	if baseFeePerGas == nil {
+		baseFeePerGas = big.NewInt(0)
+	}
+	rewards := computeTransactionRewards(receipts, baseFeePerGas)
+
+	if top > 0 {
+		sort.SliceStable(rewards, func(a, b int) bool {
+			return rewards[a].Contribution.Cmp(rewards[b].Contribution) > 0
+		})
+		if len(rewards) > top {
+			rewards = rewards[:top]
+		}
+	}
+
+	describeTransactions(block, rewards)
+	return rewards, nil
+}
+
+// describeTransactions fills the sender and recipient of each reward from the transactions of the block
+func describeTransactions(block *types.Block, rewards []TransactionReward) {
+	transactions := block.Transactions()
+	for i := range rewards {
+		if rewards[i].Index < 0 || rewards[i].Index >= int64(len(transactions)) {
+			continue
+		}
+		transaction := transactions[rewards[i].Index]
+
+		if to := transaction.To(); to != nil {
+			rewards[i].To = to.Hex()
+		}
+		if from, err := types.Sender(types.LatestSignerForChainID(transaction.ChainId()), transaction); err == nil {
+			rewards[i].From = from.Hex()
+		}
+	}
+}
+
+// computeTransactionRewards derives each transaction's priority fee contribution from its receipt
+func computeTransactionRewards(receipts []*types.Receipt, baseFeePerGas *big.Int) []TransactionReward {
+	rewards := make([]TransactionReward, 0, len(receipts))
+	total := new(big.Int)
+	for _, receipt := range receipts {
+		effectiveGasPrice := receipt.EffectiveGasPrice
+		if effectiveGasPrice == nil {
+			effectiveGasPrice = big.NewInt(0)
+		}
+
+		// The proposer receives everything paid above the burned base fee
+		priorityFee := new(big.Int).Sub(effectiveGasPrice, baseFeePerGas)
+		if priorityFee.Sign() < 0 {
+			priorityFee = big.NewInt(0)
+		}
+
+		contribution := new(big.Int).Mul(priorityFee, new(big.Int).SetUint64(receipt.GasUsed))
+		total.Add(total, contribution)
+
+		rewards = append(rewards, TransactionReward{
+			Hash:                 receipt.TxHash.Hex(),
+			Index:                int64(receipt.TransactionIndex),
+			Type:                 int64(receipt.Type),
+			GasUsed:              int64(receipt.GasUsed),
+			EffectivePriorityFee: priorityFee,
+			Contribution:         contribution,
+		})
+	}
+
+	if total.Sign() > 0 {
+		for i := range rewards {
+			rewards[i].Share, _ = new(big.Rat).SetFrac(rewards[i].Contribution, total).Float64()
+		}
+	}
+	return rewards
+}
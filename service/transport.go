@@ -0,0 +1,203 @@
+package service
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/gorilla/websocket"
+)
+
+// TransportConfig tunes the HTTP transport shared by all upstream clients
+type TransportConfig struct {
+	MaxIdleConnsPerHost int           // Idle connections kept open per upstream host
+	IdleConnTimeout     time.Duration // How long an idle connection is kept open
+	KeepAlive           time.Duration // TCP keep-alive interval of upstream connections
+	TLSHandshakeTimeout time.Duration // Maximum time to wait for a TLS handshake
+	RequestTimeout      time.Duration // Overall timeout of a single upstream request
+}
+
+// DefaultTransportConfig returns the transport settings used unless configured otherwise
+func DefaultTransportConfig() TransportConfig {
+	return TransportConfig{
+		MaxIdleConnsPerHost: 16,
+		IdleConnTimeout:     90 * time.Second,
+		KeepAlive:           30 * time.Second,
+		TLSHandshakeTimeout: 10 * time.Second,
+		RequestTimeout:      10 * time.Second,
+	}
+}
+
+// newTransport creates an HTTP transport with the configured connection pool settings
+func (c TransportConfig) newTransport() *http.Transport {
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: c.KeepAlive,
+		}).DialContext,
+		ForceAttemptHTTP2:   true,
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: c.MaxIdleConnsPerHost,
+		IdleConnTimeout:     c.IdleConnTimeout,
+		TLSHandshakeTimeout: c.TLSHandshakeTimeout,
+	}
+}
+
+// newUpstreamClient creates the HTTP client shared by all upstream clients of a service
+func newUpstreamClient(config TransportConfig, stats *upstreamStats) *http.Client {
+	return &http.Client{
+		Timeout:   config.RequestTimeout,
+		Transport: &countingTransport{next: config.newTransport(), stats: stats},
+	}
+}
+
+// ConfigureTransport replaces the transport settings of the shared upstream HTTP client,
+// it must be called before the service starts serving requests
+func (s *EthereumService) ConfigureTransport(config TransportConfig) {
+	s.transportConfig = config
+	s.client.Timeout = config.RequestTimeout
+	s.client.Transport = s.newUpstreamTransport()
+}
+
+// ProviderAuth configures how requests to an upstream provider are authenticated
+type ProviderAuth struct {
+	Headers  map[string]string // Added to every request, e.g. a provider API key or a bearer JWT
+	CertFile string            // PEM client certificate for mTLS, requires KeyFile
+	KeyFile  string            // PEM private key of the client certificate
+	CAFile   string            // PEM CA bundle the provider's certificate is verified against, the system roots if empty
+}
+
+// providerAuth is a loaded ProviderAuth
+type providerAuth struct {
+	headers   http.Header
+	tlsConfig *tls.Config // nil unless a client certificate or CA is configured
+}
+
+// load reads the certificates referenced by the ProviderAuth
+func (a ProviderAuth) load() (*providerAuth, error) {
+	auth := &providerAuth{headers: make(http.Header, len(a.Headers))}
+	for name, value := range a.Headers {
+		auth.headers.Set(name, value)
+	}
+
+	if a.CertFile == "" && a.KeyFile == "" && a.CAFile == "" {
+		return auth, nil
+	}
+	auth.tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if a.CertFile != "" || a.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(a.CertFile, a.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		auth.tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if a.CAFile != "" {
+		pem, err := os.ReadFile(a.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA file %s", a.CAFile)
+		}
+		auth.tlsConfig.RootCAs = roots
+	}
+	return auth, nil
+}
+
+// providerTransport adds the configured headers to requests for provider hosts and sends
+// them through a transport presenting the provider's client certificate
+type providerTransport struct {
+	next       http.RoundTripper
+	headers    map[string]http.Header       // Keyed by host
+	transports map[string]http.RoundTripper // Keyed by host, only for providers with TLS settings
+}
+
+func (t *providerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if headers := t.headers[req.URL.Host]; len(headers) > 0 {
+		req = req.Clone(req.Context())
+		for name, values := range headers {
+			req.Header[name] = values
+		}
+	}
+
+	next := t.next
+	if transport, ok := t.transports[req.URL.Host]; ok {
+		next = transport
+	}
+	resp, err := next.RoundTrip(req)
+	// Clients that only see the status, like the JSON-RPC client, back off for at least the requested delay
+	if err == nil && resp.StatusCode == http.StatusTooManyRequests {
+		if retryAfter := parseRetryAfter(resp.Header); retryAfter > 0 {
+			providerLimiterFor(req.URL.Host).pause(retryAfter)
+		}
+	}
+	return resp, err
+}
+
+// newUpstreamTransport creates the transport of the shared upstream client from the service's settings
+func (s *EthereumService) newUpstreamTransport() http.RoundTripper {
+	transport := &providerTransport{
+		next:       s.faultInjected(s.transportConfig.newTransport()),
+		headers:    make(map[string]http.Header, len(s.providers)),
+		transports: make(map[string]http.RoundTripper),
+	}
+	for host, auth := range s.providers {
+		transport.headers[host] = auth.headers
+		if auth.tlsConfig != nil {
+			tlsTransport := s.transportConfig.newTransport()
+			tlsTransport.TLSClientConfig = auth.tlsConfig
+			transport.transports[host] = s.faultInjected(tlsTransport)
+		}
+	}
+	return &countingTransport{next: &captureTransport{next: transport, capture: s.capture}, stats: s.stats}
+}
+
+// SetProviderAuth authenticates upstream requests to the host of providerURL with the given headers and
+// client certificate. Providers sharing a host share their settings. It must be called before the service
+// starts serving requests, and before SetExecutionURL for websocket endpoints
+func (s *EthereumService) SetProviderAuth(providerURL string, auth ProviderAuth) error {
+	parsedURL, err := url.Parse(providerURL)
+	if err != nil || parsedURL.Host == "" {
+		return fmt.Errorf("invalid provider URL: %s", providerURL)
+	}
+
+	loaded, err := auth.load()
+	if err != nil {
+		return err
+	}
+	s.providers[parsedURL.Host] = loaded
+	s.client.Transport = s.newUpstreamTransport()
+	return nil
+}
+
+// websocketOptions returns the options authenticating a websocket connection to the host of rawURL
+func (s *EthereumService) websocketOptions(rawURL string) []rpc.ClientOption {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil
+	}
+	auth, ok := s.providers[parsedURL.Host]
+	if !ok {
+		return nil
+	}
+
+	options := []rpc.ClientOption{rpc.WithHeaders(auth.headers)}
+	if auth.tlsConfig != nil {
+		options = append(options, rpc.WithWebsocketDialer(websocket.Dialer{
+			Proxy:            http.ProxyFromEnvironment,
+			HandshakeTimeout: s.transportConfig.TLSHandshakeTimeout,
+			TLSClientConfig:  auth.tlsConfig,
+		}))
+	}
+	return options
+}
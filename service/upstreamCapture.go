@@ -0,0 +1,265 @@
+package service
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultUpstreamCaptureSize is the number of upstream exchanges kept unless configured otherwise
+const DefaultUpstreamCaptureSize = 100
+
+// maxCapturedBody is the number of bytes of a request or response body kept per exchange
+const maxCapturedBody = 4096
+
+// redactedValue replaces the secrets of captured exchanges
+const redactedValue = "[REDACTED]"
+
+// UpstreamExchange is a captured upstream request with its response, redacted of provider API keys
+type UpstreamExchange struct {
+	Time         time.Time
+	Method       string
+	URL          string        // Provider host and path, with API keys in the path or query redacted
+	Status       int           // HTTP status of the response, zero if the request failed
+	Error        string        // Why the request failed, empty if a response was received
+	Duration     time.Duration // Until the response body was closed
+	RequestBody  string
+	ResponseBody string
+	Truncated    bool // Whether a body was cut off after maxCapturedBody bytes
+}
+
+// UpstreamCapture keeps the last upstream exchanges in a ring buffer to debug provider responses. Capturing is off
+// until it is enabled and can be toggled at runtime
+type UpstreamCapture struct {
+	enabled   atomic.Bool
+	mu        sync.Mutex
+	exchanges []UpstreamExchange
+	size      int
+	next      int
+	full      bool
+}
+
+// NewUpstreamCapture creates a disabled UpstreamCapture holding up to size exchanges, at least one
+func NewUpstreamCapture(size int) *UpstreamCapture {
+	size = max(size, 1)
+	return &UpstreamCapture{
+		exchanges: make([]UpstreamExchange, size),
+		size:      size,
+	}
+}
+
+// SetEnabled turns capturing on or off, exchanges captured so far are kept
+func (c *UpstreamCapture) SetEnabled(enabled bool) {
+	c.enabled.Store(enabled)
+}
+
+// Enabled reports whether upstream exchanges are captured
+func (c *UpstreamCapture) Enabled() bool {
+	return c.enabled.Load()
+}
+
+// Size returns the number of exchanges the buffer holds
+func (c *UpstreamCapture) Size() int {
+	return c.size
+}
+
+// Exchanges returns the captured exchanges, oldest first
+func (c *UpstreamCapture) Exchanges() []UpstreamExchange {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.full {
+		return append([]UpstreamExchange(nil), c.exchanges[:c.next]...)
+	}
+	return append(append([]UpstreamExchange(nil), c.exchanges[c.next:]...), c.exchanges[:c.next]...)
+}
+
+// Clear drops the captured exchanges
+func (c *UpstreamCapture) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.exchanges)
+	c.next, c.full = 0, false
+}
+
+// add stores an exchange, replacing the oldest once the buffer is full
+func (c *UpstreamCapture) add(exchange UpstreamExchange) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.exchanges[c.next] = exchange
+	c.next = (c.next + 1) % c.size
+	if c.next == 0 {
+		c.full = true
+	}
+}
+
+// captureTransport is an http.RoundTripper recording upstream exchanges while capturing is enabled. It runs before
+// provider authentication headers are added, so those never reach the capture
+type captureTransport struct {
+	next    http.RoundTripper
+	capture *UpstreamCapture
+}
+
+func (t *captureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !t.capture.Enabled() {
+		return t.next.RoundTrip(req)
+	}
+
+	redactedURL, secrets := redactUpstreamURL(req.URL)
+	exchange := UpstreamExchange{Time: time.Now().UTC(), Method: req.Method, URL: redactedURL}
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			captured, truncated := readCaptured(body)
+			body.Close()
+			exchange.RequestBody, exchange.Truncated = redactSecrets(captured, secrets), truncated
+		}
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		exchange.Error = redactSecrets(err.Error(), secrets)
+		exchange.Duration = time.Since(exchange.Time)
+		t.capture.add(exchange)
+		return nil, err
+	}
+
+	exchange.Status = resp.StatusCode
+	resp.Body = &capturedBody{ReadCloser: resp.Body, exchange: exchange, secrets: secrets, capture: t.capture}
+	return resp, nil
+}
+
+// capturedBody copies the start of a response body while it is read, and stores the exchange when it is closed
+type capturedBody struct {
+	io.ReadCloser
+	exchange UpstreamExchange
+	secrets  []string
+	capture  *UpstreamCapture
+	body     strings.Builder
+	once     sync.Once
+}
+
+func (b *capturedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	keep := min(n, maxCapturedBody-b.body.Len())
+	b.body.Write(p[:keep])
+	if keep < n {
+		b.exchange.Truncated = true
+	}
+	return n, err
+}
+
+func (b *capturedBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() {
+		b.exchange.ResponseBody = redactSecrets(b.body.String(), b.secrets)
+		b.exchange.Duration = time.Since(b.exchange.Time)
+		b.capture.add(b.exchange)
+	})
+	return err
+}
+
+// readCaptured reads up to maxCapturedBody bytes of a body, reporting whether there was more
+func readCaptured(body io.Reader) (string, bool) {
+	data, err := io.ReadAll(io.LimitReader(body, maxCapturedBody+1))
+	if err != nil {
+		return "", false
+	}
+	if len(data) > maxCapturedBody {
+		return string(data[:maxCapturedBody]), true
+	}
+	return string(data), false
+}
+
+// secretQueryParams are query parameters carrying provider credentials
+var secretQueryParams = []string{"key", "apikey", "api_key", "token", "access_token", "secret", "auth"}
+
+// redactUpstreamURL returns a URL without credentials, replacing path segments and query values that look like
+// API keys, as providers such as QuickNode and Infura embed them. The replaced values are returned so they can be
+// scrubbed from bodies and errors too
+func redactUpstreamURL(u *url.URL) (string, []string) {
+	var secrets []string
+	if u.User != nil {
+		secrets = append(secrets, u.User.String())
+	}
+
+	segments := strings.Split(u.EscapedPath(), "/")
+	for i, segment := range segments {
+		if looksLikeAPIKey(segment) {
+			secrets = append(secrets, segment)
+			segments[i] = redactedValue
+		}
+	}
+	redacted := u.Scheme + "://" + u.Host + strings.Join(segments, "/")
+
+	if u.RawQuery != "" {
+		var querySecrets []string
+		for name, values := range u.Query() {
+			for _, value := range values {
+				if value != "" && (containsFold(secretQueryParams, name) || looksLikeAPIKey(value)) {
+					querySecrets = append(querySecrets, url.QueryEscape(value), value)
+				}
+			}
+		}
+		redacted += "?" + redactSecrets(u.RawQuery, querySecrets)
+		secrets = append(secrets, querySecrets...)
+	}
+	return redacted, secrets
+}
+
+// looksLikeAPIKey reports whether a value looks like an API key: a long run of letters, digits, dashes and
+// underscores mixing letters and digits. Hex values such as roots and public keys are kept
+func looksLikeAPIKey(value string) bool {
+	if len(value) < 16 || strings.HasPrefix(value, "0x") {
+		return false
+	}
+	var letters, digits bool
+	for _, r := range value {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+			letters = true
+		case r >= '0' && r <= '9':
+			digits = true
+		case r == '-' || r == '_':
+		default:
+			return false
+		}
+	}
+	return letters && digits
+}
+
+// containsFold reports whether values contains value, ignoring case
+func containsFold(values []string, value string) bool {
+	for _, candidate := range values {
+		if strings.EqualFold(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactSecrets replaces every occurrence of the secrets in text
+func redactSecrets(text string, secrets []string) string {
+	for _, secret := range secrets {
+		if secret != "" {
+			text = strings.ReplaceAll(text, secret, redactedValue)
+		}
+	}
+	return text
+}
+
+// UpstreamCapture returns the buffer of captured upstream exchanges
+func (s *EthereumService) UpstreamCapture() *UpstreamCapture {
+	return s.capture
+}
+
+// SetUpstreamCapture replaces the buffer upstream exchanges are captured to, e.g. with one of another size. It must
+// be called before the service starts serving requests
+func (s *EthereumService) SetUpstreamCapture(capture *UpstreamCapture) {
+	s.capture = capture
+	s.client.Transport = s.newUpstreamTransport()
+}
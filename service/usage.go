@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultUsageRetention is how long daily usage is kept unless configured otherwise
+const DefaultUsageRetention = 90 * 24 * time.Hour
+
+// usageDayFormat is the format of the UTC days usage is counted for
+const usageDayFormat = time.DateOnly
+
+// UsageRecord counts the requests of a client to an endpoint on a day
+type UsageRecord struct {
+	Day      string `json:"day"`      // UTC day, YYYY-MM-DD
+	Client   string `json:"client"`   // "sub:" followed by the subject of a JWT, "key:" followed by a hash of the API key, or "ip:" followed by the client IP
+	Endpoint string `json:"endpoint"` // Method and route, e.g. "GET /validator/:id"
+	Requests int64  `json:"requests"`
+	Errors   int64  `json:"errors"` // Requests answered with a 4xx or 5xx status
+}
+
+// Fields usage records can be grouped by
+const (
+	UsageByDay      = "day"
+	UsageByClient   = "client"
+	UsageByEndpoint = "endpoint"
+)
+
+// UsageFilter selects usage records, empty fields match every record
+type UsageFilter struct {
+	Client   string
+	Endpoint string
+	From     time.Time // First day included
+	To       time.Time // Last day included
+	GroupBy  string    // UsageByDay, UsageByClient or UsageByEndpoint to sum the records over the other fields, "" to keep them apart
+}
+
+// usageKey identifies the record of a client and endpoint on a day
+type usageKey struct {
+	day      string
+	client   string
+	endpoint string
+}
+
+// UsageTracker counts requests per client, endpoint and day
+type UsageTracker struct {
+	mu      sync.Mutex
+	records map[usageKey]*UsageRecord
+	path    string // File the usage is persisted to, "" to keep it in memory only
+	dirty   bool   // Whether requests were counted since the last save
+}
+
+// NewUsageTracker creates an empty UsageTracker kept in memory only
+func NewUsageTracker() *UsageTracker {
+	return &UsageTracker{records: make(map[usageKey]*UsageRecord)}
+}
+
+// LoadUsageTracker creates a UsageTracker persisted to a JSON file, starting from the usage it already contains. A
+// missing file is created by the first Save
+func LoadUsageTracker(path string) (*UsageTracker, error) {
+	tracker := NewUsageTracker()
+	tracker.path = path
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return tracker, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read usage: %w", err)
+	}
+
+	var records []UsageRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("failed to decode usage: %w", err)
+	}
+	for _, record := range records {
+		tracker.records[usageKey{day: record.Day, client: record.Client, endpoint: record.Endpoint}] = &record
+	}
+	return tracker, nil
+}
+
+// UsageClient identifies the client of a request by its API key, or by its IP address for requests without one.
+// Keys are hashed so they never show up in the usage
+func UsageClient(apiKey, ip string) string {
+	if apiKey == "" {
+		return "ip:" + ip
+	}
+	sum := sha256.Sum256([]byte(apiKey))
+	return "key:" + hex.EncodeToString(sum[:8])
+}
+
+// Record counts a request of a client to an endpoint at the given time
+func (u *UsageTracker) Record(client, endpoint string, at time.Time, failed bool) {
+	key := usageKey{day: at.UTC().Format(usageDayFormat), client: client, endpoint: endpoint}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	record, ok := u.records[key]
+	if !ok {
+		record = &UsageRecord{Day: key.day, Client: client, Endpoint: endpoint}
+		u.records[key] = record
+	}
+	record.Requests++
+	if failed {
+		record.Errors++
+	}
+	u.dirty = true
+}
+
+// Query returns the records matching the filter, grouped if requested, ordered by day, client and endpoint
+func (u *UsageTracker) Query(filter UsageFilter) []UsageRecord {
+	var from, to string
+	if !filter.From.IsZero() {
+		from = filter.From.UTC().Format(usageDayFormat)
+	}
+	if !filter.To.IsZero() {
+		to = filter.To.UTC().Format(usageDayFormat)
+	}
+
+	u.mu.Lock()
+	groups := make(map[usageKey]*UsageRecord)
+	for key, record := range u.records {
+		// Days compare chronologically as strings
+		if (filter.Client != "" && key.client != filter.Client) || (filter.Endpoint != "" && key.endpoint != filter.Endpoint) ||
+			(from != "" && key.day < from) || (to != "" && key.day > to) {
+			continue
+		}
+
+		switch filter.GroupBy {
+		case UsageByDay:
+			key = usageKey{day: key.day}
+		case UsageByClient:
+			key = usageKey{client: key.client}
+		case UsageByEndpoint:
+			key = usageKey{endpoint: key.endpoint}
+		}
+		group, ok := groups[key]
+		if !ok {
+			group = &UsageRecord{Day: key.day, Client: key.client, Endpoint: key.endpoint}
+			groups[key] = group
+		}
+		group.Requests += record.Requests
+		group.Errors += record.Errors
+	}
+	u.mu.Unlock()
+
+	records := make([]UsageRecord, 0, len(groups))
+	for _, group := range groups {
+		records = append(records, *group)
+	}
+
+	sort.Slice(records, func(a, b int) bool {
+		if records[a].Day != records[b].Day {
+			return records[a].Day < records[b].Day
+		}
+		if records[a].Client != records[b].Client {
+			return records[a].Client < records[b].Client
+		}
+		return records[a].Endpoint < records[b].Endpoint
+	})
+	return records
+}
+
+// Prune removes the records of days before the given time, returning the number of removed records
+func (u *UsageTracker) Prune(before time.Time) int {
+	day := before.UTC().Format(usageDayFormat)
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	removed := 0
+	for key := range u.records {
+		if key.day < day {
+			delete(u.records, key)
+			removed++
+		}
+	}
+	if removed > 0 {
+		u.dirty = true
+	}
+	return removed
+}
+
+// Save writes the usage to the file of the tracker when it changed since the last save
+func (u *UsageTracker) Save() error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.path == "" || !u.dirty {
+		return nil
+	}
+
+	records := make([]*UsageRecord, 0, len(u.records))
+	for _, record := range u.records {
+		records = append(records, record)
+	}
+	data, err := json.Marshal(records)
+	if err != nil {
+		return fmt.Errorf("failed to encode usage: %w", err)
+	}
+	if err := writeFileAtomic(u.path, data); err != nil {
+		return fmt.Errorf("failed to save usage: %w", err)
+	}
+
+	u.dirty = false
+	return nil
+}
+
+// Usage returns the request counts per client, endpoint and day
+func (s *EthereumService) Usage() *UsageTracker {
+	return s.usage
+}
+
+// SetUsage replaces the request counts, e.g. with ones loaded from disk
+func (s *EthereumService) SetUsage(usage *UsageTracker) {
+	s.usage = usage
+}
+
+// UsageSaveTask returns the task dropping usage older than retention and persisting the usage on the given schedule
+func (s *EthereumService) UsageSaveTask(schedule Schedule, retention time.Duration) Task {
+	return Task{
+		Name:     "usage-save",
+		Schedule: schedule,
+		Run: func(ctx context.Context) error {
+			s.usage.Prune(s.clock.Now().Add(-retention))
+			return s.usage.Save()
+		},
+	}
+}
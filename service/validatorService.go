@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// Validator related error definitions
+var (
+	ErrInvalidValidatorID = errors.New("invalid validator index or pubkey")
+	ErrValidatorNotFound  = errors.New("validator does not exist")
+	ErrProposerMismatch   = errors.New("validator did not propose the requested slot")
+	ErrNoRecentProposal   = errors.New("no recent proposal found for validator")
+	// ErrProposalLookupIncomplete is returned when the latest proposal lookup ran out of header lookups before it
+	// covered the lookback, the headers it fetched are cached so a retry gets further
+	ErrProposalLookupIncomplete = errors.New("recent proposals could not all be checked")
+)
+
+const (
+	// proposalLookbackEpochs bounds how far back the latest proposal lookup scans
+	proposalLookbackEpochs = 16
+
+	// maxProposalHeaderLookups bounds the upstream header lookups of a single latest proposal lookup, which only
+	// fetches the headers of blocks the index doesn't have
+	maxProposalHeaderLookups = 8
+
+	// blockHeaderTTL is how long a header looked up by its block root is cached, roots commit to the header
+	blockHeaderTTL = time.Hour
+)
+
+var pubkeyPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{96}$`)
+
+// ProposerMismatchError is returned when a slot was proposed by a different validator than requested
+type ProposerMismatchError struct {
+	Slot          int64
+	ProposerIndex int64
+}
+
+func (e *ProposerMismatchError) Error() string {
+	return fmt.Sprintf("%v: slot %d was proposed by validator %d", ErrProposerMismatch, e.Slot, e.ProposerIndex)
+}
+
+func (e *ProposerMismatchError) Unwrap() error {
+	return ErrProposerMismatch
+}
+
+// ValidatorBlockReward represents a block reward attributed to its proposer
+type ValidatorBlockReward struct {
+	Slot           int64
+	ValidatorIndex int64
+	*BlockReward
+}
+
+// blockHeaderResponse represents the response from /eth/v1/beacon/headers/{block_id}
+type blockHeaderResponse struct {
+	Data struct {
+		Root      string `json:"root"`
+		Canonical bool   `json:"canonical"`
+		Header    struct {
+			Message beaconBlockHeaderJSON `json:"message"`
+		} `json:"header"`
+	} `json:"data"`
+}
+
+// beaconBlockHeaderJSON represents a beacon block header as served by the Beacon API
+type beaconBlockHeaderJSON struct {
+	Slot          string `json:"slot"`
+	ProposerIndex string `json:"proposer_index"`
+	ParentRoot    string `json:"parent_root"`
+	StateRoot     string `json:"state_root"`
+	BodyRoot      string `json:"body_root"`
+}
+
+// proposerDutiesResponse represents the response from /eth/v1/validator/duties/proposer/{epoch}
+type proposerDutiesResponse struct {
+	Data []struct {
+		Pubkey         string `json:"pubkey"`
+		ValidatorIndex string `json:"validator_index"`
+		Slot           string `json:"slot"`
+	} `json:"data"`
+}
+
+// GetValidatorBlockRewardBySlot returns the block reward for a slot after verifying the validator proposed it
+func (s *EthereumService) GetValidatorBlockRewardBySlot(ctx context.Context, validatorID string, slot int64) (*ValidatorBlockReward, error) {
+	index, err := s.resolveValidatorIndex(ctx, validatorID)
+	if err != nil {
+		return nil, err
+	}
+
+	var header blockHeaderResponse
+	if err := s.getBeaconJSON(ctx, fmt.Sprintf("/eth/v1/beacon/headers/%d", slot), &header); err != nil {
+		return nil, err
+	}
+
+	proposerIndex := parseDecimal(header.Data.Header.Message.ProposerIndex)
+	if proposerIndex != index {
+		return nil, &ProposerMismatchError{Slot: slot, ProposerIndex: proposerIndex}
+	}
+
+	reward, err := s.GetBlockRewardBySlot(ctx, slot)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ValidatorBlockReward{
+		Slot:           slot,
+		ValidatorIndex: index,
+		BlockReward:    reward,
+	}, nil
+}
+
+// GetValidatorLatestBlockReward returns the block reward of the most recent slot proposed by the validator. Many
+// nodes don't serve the proposer duties of past epochs, so the proposers are read from the blocks of the canonical
+// chain instead, walking back from the head by parent root for proposalLookbackEpochs epochs. Missed proposals have
+// no block and are skipped. Blocks are read from the block index, only the headers of blocks it doesn't have are
+// looked up upstream, at most maxProposalHeaderLookups per call
+func (s *EthereumService) GetValidatorLatestBlockReward(ctx context.Context, validatorID string) (*ValidatorBlockReward, error) {
+	index, err := s.resolveValidatorIndex(ctx, validatorID)
+	if err != nil {
+		return nil, err
+	}
+
+	var head blockHeaderResponse
+	if err := s.getBeaconJSON(ctx, "/eth/v1/beacon/headers/head", &head); err != nil {
+		return nil, err
+	}
+	header := head.Data.Header.Message
+	slot, proposer, parentRoot := parseDecimal(header.Slot), parseDecimal(header.ProposerIndex), header.ParentRoot
+	oldestSlot := slot - proposalLookbackEpochs*SlotsPerEpoch
+
+	// Indexed blocks are found by root, so blocks of a fork the index still holds are never followed
+	indexed := make(map[string]*BlockDetail)
+	for _, block := range s.index.Range(oldestSlot+1, slot, nil) {
+		indexed[block.BlockRoot] = block
+	}
+
+	lookups := 0
+	for slot > oldestSlot {
+		if proposer == index {
+			reward, err := s.GetBlockRewardBySlot(ctx, slot)
+			if err != nil {
+				return nil, err
+			}
+			return &ValidatorBlockReward{Slot: slot, ValidatorIndex: index, BlockReward: reward}, nil
+		}
+		if slot == 0 {
+			break
+		}
+
+		if block, ok := indexed[parentRoot]; ok {
+			slot, proposer, parentRoot = block.Slot, block.ProposerIndex, block.ParentRoot
+			continue
+		}
+		if _, cached := s.cache.Get(blockHeaderKey(parentRoot)); !cached {
+			if lookups == maxProposalHeaderLookups {
+				return nil, fmt.Errorf("%w: checked back to slot %d", ErrProposalLookupIncomplete, slot)
+			}
+			lookups++
+		}
+		parent, err := s.getBlockHeader(ctx, parentRoot)
+		if errors.Is(err, ErrSlotNotFound) {
+			// The node pruned the older blocks
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get header of block %s: %w", parentRoot, err)
+		}
+		slot, proposer, parentRoot = parseDecimal(parent.Slot), parseDecimal(parent.ProposerIndex), parent.ParentRoot
+	}
+
+	return nil, fmt.Errorf("%w in the last %d epochs", ErrNoRecentProposal, proposalLookbackEpochs)
+}
+
+// blockHeaderKey returns the cache key of the header of a block root
+func blockHeaderKey(root string) string {
+	return "header:" + root
+}
+
+// getBlockHeader returns the header of a block by its root, caching it since a root always names the same header
+func (s *EthereumService) getBlockHeader(ctx context.Context, root string) (*beaconBlockHeaderJSON, error) {
+	key := blockHeaderKey(root)
+	if cached, ok := s.cacheGet(ctx, key); ok {
+		return cached.(*beaconBlockHeaderJSON), nil
+	}
+
+	var response blockHeaderResponse
+	if err := s.getBeaconJSON(ctx, "/eth/v1/beacon/headers/"+root, &response); err != nil {
+		return nil, err
+	}
+	header := response.Data.Header.Message
+	s.cache.Set(key, &header, blockHeaderTTL)
+	return &header, nil
+}
+
+// resolveValidatorIndex converts a validator index or pubkey into a validator index
+func (s *EthereumService) resolveValidatorIndex(ctx context.Context, validatorID string) (int64, error) {
+	if err := validateValidatorID(validatorID); err != nil {
+		return 0, err
+	}
+
+	if index, err := strconv.ParseInt(validatorID, 10, 64); err == nil {
+		return index, nil
+	}
+
+	var validator validatorResponse
+	if err := s.getBeaconJSON(ctx, "/eth/v1/beacon/states/head/validators/"+validatorID, &validator); err != nil {
+		if errors.Is(err, ErrSlotNotFound) {
+			return 0, ErrValidatorNotFound
+		}
+		return 0, err
+	}
+
+	return parseDecimal(validator.Data.Index), nil
+}
+
+// getHeadSlot returns the slot of the current chain head
+func (s *EthereumService) getHeadSlot(ctx context.Context) (int64, error) {
+	var header blockHeaderResponse
+	if err := s.getBeaconJSON(ctx, "/eth/v1/beacon/headers/head", &header); err != nil {
+		return 0, err
+	}
+	return parseDecimal(header.Data.Header.Message.Slot), nil
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+)
+
+// validatorData represents a validator entry returned by the Beacon API
+type validatorData struct {
+	Index     string `json:"index"`
+	Balance   string `json:"balance"`
+	Status    string `json:"status"`
+	Validator struct {
+		Pubkey                     string `json:"pubkey"`
+		WithdrawalCredentials      string `json:"withdrawal_credentials"`
+		EffectiveBalance           string `json:"effective_balance"`
+		Slashed                    bool   `json:"slashed"`
+		ActivationEligibilityEpoch string `json:"activation_eligibility_epoch"`
+		ActivationEpoch            string `json:"activation_epoch"`
+		ExitEpoch                  string `json:"exit_epoch"`
+		WithdrawableEpoch          string `json:"withdrawable_epoch"`
+	} `json:"validator"`
+}
+
+// validatorResponse represents the response from /eth/v1/beacon/states/{state_id}/validators/{validator_id}
+type validatorResponse struct {
+	Data validatorData `json:"data"`
+}
+
+// validatorsResponse represents the response from /eth/v1/beacon/states/{state_id}/validators
+type validatorsResponse struct {
+	Data []validatorData `json:"data"`
+}
+
+// ValidatorStatus represents the status-dependent information of a validator at a given state
+type ValidatorStatus struct {
+	Index                      int64
+	Pubkey                     string
+	Status                     string
+	Balance                    int64 // in GWEI
+	EffectiveBalance           int64 // in GWEI
+	Slashed                    bool
+	ActivationEligibilityEpoch string
+	ActivationEpoch            string
+	ExitEpoch                  string
+	WithdrawableEpoch          string
+	QueuePosition              int                // 1-based position in the activation queue, 0 if not queued
+	StateID                    string             // "head" or the slot the status was evaluated at
+	Operator                   *ValidatorOperator // Staking pool and node operator, nil if the pubkey is untagged
+}
+
+// GetValidatorStatus retrieves the status of a validator at the head, or at asOfSlot when it is not nil
+func (s *EthereumService) GetValidatorStatus(ctx context.Context, validatorID string, asOfSlot *int64) (*ValidatorStatus, error) {
+	if err := validateValidatorID(validatorID); err != nil {
+		return nil, err
+	}
+
+	stateID, err := s.resolveStateID(ctx, asOfSlot)
+	if err != nil {
+		return nil, err
+	}
+
+	var validator validatorResponse
+	if err := s.getBeaconJSON(ctx, fmt.Sprintf("/eth/v1/beacon/states/%s/validators/%s", stateID, validatorID), &validator); err != nil {
+		if errors.Is(err, ErrSlotNotFound) {
+			return nil, ErrValidatorNotFound
+		}
+		return nil, err
+	}
+
+	status := s.newValidatorStatus(validator.Data, stateID)
+
+	if status.Status == "pending_queued" {
+		position, err := s.getActivationQueuePosition(ctx, stateID, status.Index)
+		if err != nil {
+			// The queue position is a convenience field, so don't fail the whole request over it
+			sampledLogf("activation queue position", "Warning: failed to get activation queue position: %v", err)
+		} else {
+			status.QueuePosition = position
+		}
+	}
+
+	return status, nil
+}
+
+// newValidatorStatus converts a Beacon API validator entry evaluated at a state into a ValidatorStatus
+func (s *EthereumService) newValidatorStatus(data validatorData, stateID string) *ValidatorStatus {
+	return &ValidatorStatus{
+		Index:                      parseDecimal(data.Index),
+		Pubkey:                     data.Validator.Pubkey,
+		Status:                     data.Status,
+		Balance:                    parseDecimal(data.Balance),
+		EffectiveBalance:           parseDecimal(data.Validator.EffectiveBalance),
+		Slashed:                    data.Validator.Slashed,
+		ActivationEligibilityEpoch: data.Validator.ActivationEligibilityEpoch,
+		ActivationEpoch:            data.Validator.ActivationEpoch,
+		ExitEpoch:                  data.Validator.ExitEpoch,
+		WithdrawableEpoch:          data.Validator.WithdrawableEpoch,
+		StateID:                    stateID,
+		Operator:                   s.lookupOperator(data.Validator.Pubkey),
+	}
+}
+
+// getActivationQueuePosition returns the 1-based position of a validator in the activation queue of a state
+func (s *EthereumService) getActivationQueuePosition(ctx context.Context, stateID string, index int64) (int, error) {
+	var pending validatorsResponse
+	if err := s.getBeaconJSON(ctx, fmt.Sprintf("/eth/v1/beacon/states/%s/validators?status=pending_queued", stateID), &pending); err != nil {
+		return 0, err
+	}
+
+	// The activation queue is ordered by eligibility epoch, then by validator index. The response may be shared with
+	// coalesced requests, so a copy is sorted
+	queue := slices.Clone(pending.Data)
+	sort.Slice(queue, func(a, b int) bool {
+		epochA := parseDecimal(queue[a].Validator.ActivationEligibilityEpoch)
+		epochB := parseDecimal(queue[b].Validator.ActivationEligibilityEpoch)
+		if epochA != epochB {
+			return epochA < epochB
+		}
+		return parseDecimal(queue[a].Index) < parseDecimal(queue[b].Index)
+	})
+
+	for i, validator := range queue {
+		if parseDecimal(validator.Index) == index {
+			return i + 1, nil
+		}
+	}
+
+	return 0, nil
+}
+
+// resolveStateID returns the Beacon API state identifier for an optional historical slot
+func (s *EthereumService) resolveStateID(ctx context.Context, asOfSlot *int64) (string, error) {
+	if asOfSlot == nil {
+		return "head", nil
+	}
+
+	headSlot, err := s.getHeadSlot(ctx)
+	if err != nil {
+		return "", err
+	}
+	if *asOfSlot > headSlot {
+		return "", fmt.Errorf("%w (head slot: %d)", ErrFutureSlot, headSlot)
+	}
+
+	return strconv.FormatInt(*asOfSlot, 10), nil
+}
+
+// validateValidatorID checks that a validator identifier is either an index or a pubkey
+func validateValidatorID(validatorID string) error {
+	if index, err := strconv.ParseInt(validatorID, 10, 64); err == nil {
+		if index < 0 {
+			return ErrInvalidValidatorID
+		}
+		return nil
+	}
+
+	if !pubkeyPattern.MatchString(validatorID) {
+		return ErrInvalidValidatorID
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidValidatorList is returned when a validator list is empty or too long
+var ErrInvalidValidatorList = errors.New("invalid validator list")
+
+const (
+	// MaxSummaryValidators is the largest number of validators a single summary may cover
+	MaxSummaryValidators = 500
+
+	// SummaryAttestationEpochs is the number of recent rewarded epochs the attestation performance of a summary covers
+	SummaryAttestationEpochs = 5
+
+	// summaryDutyEpochs is the look-ahead window of the upcoming duties of a summary, the epochs proposals are known for
+	summaryDutyEpochs = proposerLookaheadEpochs
+)
+
+// ValidatorSummary represents the status, upcoming duties and recent performance of one validator of a list
+type ValidatorSummary struct {
+	ID           string           // Index or pubkey as requested
+	Status       *ValidatorStatus // nil if the validator does not exist
+	Duties       []ValidatorDuty  // Upcoming proposals and sync committee windows
+	Attestations EffectivenessComponent
+}
+
+// ValidatorsSummary represents the summaries of a list of validators at the head
+type ValidatorsSummary struct {
+	HeadSlot   int64
+	FromEpoch  int64              // First epoch of the attestation window
+	ToEpoch    int64              // Last epoch of the attestation window
+	Validators []ValidatorSummary // In request order, without duplicates
+}
+
+// GetValidatorsSummary summarizes the status, balance, upcoming duties and recent attestation performance of up to
+// MaxSummaryValidators validators. Upstream requests are shared by all validators of the list rather than made per
+// validator, so large lists cost about as much as a single one
+func (s *EthereumService) GetValidatorsSummary(ctx context.Context, validatorIDs []string) (*ValidatorsSummary, error) {
+	if len(validatorIDs) == 0 || len(validatorIDs) > MaxSummaryValidators {
+		return nil, fmt.Errorf("%w: between 1 and %d validators expected", ErrInvalidValidatorList, MaxSummaryValidators)
+	}
+
+	ids := make([]string, 0, len(validatorIDs))
+	seen := make(map[string]bool, len(validatorIDs))
+	for _, id := range validatorIDs {
+		if err := validateValidatorID(id); err != nil {
+			return nil, fmt.Errorf("%w: %q", err, id)
+		}
+		if key := strings.ToLower(id); !seen[key] {
+			seen[key] = true
+			ids = append(ids, id)
+		}
+	}
+
+	headSlot, err := s.getHeadSlot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var validators validatorsResponse
+	if err := s.getValidatorsByID(ctx, "head", ids, &validators); err != nil {
+		return nil, err
+	}
+	statuses := make(map[string]*ValidatorStatus, 2*len(validators.Data))
+	indices := make([]string, 0, len(validators.Data))
+	for _, data := range validators.Data {
+		status := s.newValidatorStatus(data, "head")
+		statuses[data.Index] = status
+		statuses[strings.ToLower(data.Validator.Pubkey)] = status
+		indices = append(indices, data.Index)
+	}
+
+	duties, err := s.getUpcomingDuties(ctx, headSlot)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &ValidatorsSummary{
+		HeadSlot:   headSlot,
+		ToEpoch:    lastRewardedEpoch(headSlot),
+		Validators: make([]ValidatorSummary, 0, len(ids)),
+	}
+	summary.FromEpoch = max(summary.ToEpoch-SummaryAttestationEpochs+1, 0)
+	attestations, err := s.getAttestationComponents(ctx, indices, summary.FromEpoch, summary.ToEpoch)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		result := ValidatorSummary{ID: id, Duties: []ValidatorDuty{}}
+		if status, ok := statuses[strings.ToLower(id)]; ok {
+			result.Status = status
+			result.Duties = append(result.Duties, duties[status.Index]...)
+			result.Attestations = attestations[status.Index]
+		}
+		summary.Validators = append(summary.Validators, result)
+	}
+
+	return summary, nil
+}
+
+// getUpcomingDuties returns the upcoming proposals and sync committee windows of all validators having one within the
+// next summaryDutyEpochs epochs, keyed by validator index
+func (s *EthereumService) getUpcomingDuties(ctx context.Context, headSlot int64) (map[int64][]ValidatorDuty, error) {
+	genesis, err := s.getGenesisTime(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	headEpoch := headSlot / SlotsPerEpoch
+	lastSlot := (headEpoch+summaryDutyEpochs)*SlotsPerEpoch - 1
+	duties := make(map[int64][]ValidatorDuty)
+
+	for epoch := headEpoch; epoch < headEpoch+summaryDutyEpochs; epoch++ {
+		var proposers proposerDutiesResponse
+		if err := s.getBeaconJSON(ctx, fmt.Sprintf("/eth/v1/validator/duties/proposer/%d", epoch), &proposers); err != nil {
+			return nil, err
+		}
+
+		for _, duty := range proposers.Data {
+			if slot := parseDecimal(duty.Slot); slot > headSlot {
+				index := parseDecimal(duty.ValidatorIndex)
+				duties[index] = append(duties[index], newValidatorDuty(genesis, DutyProposal, slot, slot))
+			}
+		}
+	}
+
+	for period := headSlot / SlotsPerSyncPeriod; period <= headSlot/SlotsPerSyncPeriod+1; period++ {
+		startSlot := period * SlotsPerSyncPeriod
+		if startSlot > lastSlot {
+			break
+		}
+
+		var committee stateSyncCommitteeResponse
+		path := fmt.Sprintf("/eth/v1/beacon/states/head/sync_committees?epoch=%d", startSlot/SlotsPerEpoch)
+		if err := s.getBeaconJSON(ctx, path, &committee); err != nil {
+			return nil, err
+		}
+
+		// Members can appear more than once in a committee, the window is reported once
+		members := make(map[int64]bool, len(committee.Data.Validators))
+		for _, member := range committee.Data.Validators {
+			members[parseDecimal(member)] = true
+		}
+		for index := range members {
+			duties[index] = append(duties[index], newValidatorDuty(genesis, DutySyncCommittee, max(startSlot, headSlot), startSlot+SlotsPerSyncPeriod-1))
+		}
+	}
+
+	return duties, nil
+}
+
+// getAttestationComponents rates the attestations of validators between fromEpoch and toEpoch with one rewards
+// request per epoch covering all validators, keyed by validator index
+func (s *EthereumService) getAttestationComponents(ctx context.Context, indices []string, fromEpoch, toEpoch int64) (map[int64]EffectivenessComponent, error) {
+	components := make(map[int64]EffectivenessComponent, len(indices))
+	if len(indices) == 0 {
+		return components, nil
+	}
+
+	for epoch := fromEpoch; epoch <= toEpoch; epoch++ {
+		var rewards attestationRewardsResponse
+		path := fmt.Sprintf("/eth/v1/beacon/rewards/attestations/%d", epoch)
+		if err := s.postBeaconJSON(ctx, path, indices, &rewards); err != nil {
+			return nil, err
+		}
+
+		for _, total := range rewards.Data.TotalRewards {
+			index, err := strconv.ParseInt(total.ValidatorIndex, 10, 64)
+			if err != nil {
+				continue
+			}
+			head, target, source := parseDecimal(total.Head), parseDecimal(total.Target), parseDecimal(total.Source)
+
+			component := components[index]
+			component.Duties++
+			if head > 0 || target > 0 || source > 0 {
+				component.Fulfilled++
+			}
+			for _, reward := range []int64{head, target, source} {
+				if reward > 0 {
+					component.Rate++
+				}
+			}
+			components[index] = component
+		}
+	}
+
+	// Rates are accumulated as correct votes, three per duty, like GetValidatorEffectiveness does
+	for index, component := range components {
+		component.Rate /= float64(component.Duties * 3)
+		components[index] = component
+	}
+	return components, nil
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ErrInconsistentResponse is returned while verification is enabled for upstream data contradicting the request or
+// itself, e.g. a block of another slot or an execution block not matching the hash its beacon block committed to
+var ErrInconsistentResponse = errors.New("upstream response is inconsistent")
+
+// upstream is one of the providers behind the hedged clients, kept to cross-check results
+type upstream struct {
+	name      string
+	beacon    BeaconClient
+	execution ExecutionClient
+}
+
+// Divergence is a value a provider disagreed on with the result that was served
+type Divergence struct {
+	Provider string
+	Field    string // "block_root" or "execution_block_hash"
+	Expected string
+	Actual   string // "" when the provider doesn't know the block
+}
+
+// Verification is the outcome of cross-checking a block with each configured provider
+type Verification struct {
+	Providers   int // Providers that answered the cross-check
+	Divergences []Divergence
+}
+
+// EnableVerification turns the integrity checks of upstream responses on or off
+func (s *EthereumService) EnableVerification(enabled bool) {
+	s.verify = enabled
+}
+
+// VerificationEnabled reports whether upstream responses are verified
+func (s *EthereumService) VerificationEnabled() bool {
+	return s.verify
+}
+
+// verifyBeaconBlockSlot checks that the beacon block returned for a slot is the block of that slot
+func (s *EthereumService) verifyBeaconBlockSlot(slot, reported int64) error {
+	if s.verify && reported != slot {
+		return fmt.Errorf("%w: block requested for slot %d is for slot %d", ErrInconsistentResponse, slot, reported)
+	}
+	return nil
+}
+
+// verifyExecutionBlock checks that an execution block hashes to the hash the beacon block of its slot committed to,
+// and that its receipts are those of its transactions
+func (s *EthereumService) verifyExecutionBlock(ctx context.Context, slot int64, block *types.Block, receipts []*types.Receipt) error {
+	if !s.verify {
+		return nil
+	}
+
+	detail, ok := s.index.Get(slot)
+	if !ok {
+		var err error
+		if detail, err = s.getBlockDetail(ctx, slot); err != nil {
+			return err
+		}
+	}
+	if hash := block.Hash().Hex(); !strings.EqualFold(hash, detail.ExecutionBlockHash) {
+		return fmt.Errorf("%w: execution block of slot %d hashes to %s, its beacon block committed to %s", ErrInconsistentResponse, slot, hash, detail.ExecutionBlockHash)
+	}
+	// Receipts belong to the block when they are as many as its transactions and carry its hash
+	if len(receipts) != len(block.Transactions()) {
+		return fmt.Errorf("%w: execution block %s has %d transactions but %d receipts", ErrInconsistentResponse, block.Hash().Hex(), len(block.Transactions()), len(receipts))
+	}
+	for _, receipt := range receipts {
+		if receipt.BlockHash != (common.Hash{}) && receipt.BlockHash != block.Hash() {
+			return fmt.Errorf("%w: receipt %s belongs to execution block %s, not %s", ErrInconsistentResponse, receipt.TxHash.Hex(), receipt.BlockHash.Hex(), block.Hash().Hex())
+		}
+	}
+	return nil
+}
+
+// VerifyBlock cross-checks the block root and execution block hash of a slot with each configured provider. Without a
+// secondary provider there is nothing to compare against, and providers failing to answer are left out
+func (s *EthereumService) VerifyBlock(ctx context.Context, slot int64) (*Verification, error) {
+	detail, ok := s.index.Get(slot)
+	if !ok {
+		var err error
+		if detail, err = s.GetBlockDetailBySlot(ctx, slot); err != nil {
+			return nil, err
+		}
+	}
+
+	verification := &Verification{Divergences: make([]Divergence, 0)}
+	upstreams := s.upstreamProviders()
+	if len(upstreams) == 0 {
+		verification.Providers = 1
+		return verification, nil
+	}
+
+	for _, provider := range upstreams {
+		var root blockRootResponse
+		err := provider.beacon.GetJSON(ctx, fmt.Sprintf("/eth/v1/beacon/blocks/%d/root", slot), &root)
+		if err != nil && !errors.Is(err, ErrSlotNotFound) {
+			continue
+		}
+		verification.Providers++
+		if !strings.EqualFold(root.Data.Root, detail.BlockRoot) {
+			verification.Divergences = append(verification.Divergences, Divergence{
+				Provider: provider.name,
+				Field:    "block_root",
+				Expected: detail.BlockRoot,
+				Actual:   root.Data.Root,
+			})
+		}
+
+		if detail.ExecutionBlockHash == "" {
+			continue
+		}
+		var header *executionHeaderResponse
+		err = provider.execution.Call(ctx, "eth_getBlockByNumber", []interface{}{hexutil.EncodeUint64(uint64(detail.ExecutionBlockNumber)), false}, &header)
+		if err != nil {
+			continue
+		}
+		actual := ""
+		if header != nil {
+			actual = header.Hash
+		}
+		if !strings.EqualFold(actual, detail.ExecutionBlockHash) {
+			verification.Divergences = append(verification.Divergences, Divergence{
+				Provider: provider.name,
+				Field:    "execution_block_hash",
+				Expected: detail.ExecutionBlockHash,
+				Actual:   actual,
+			})
+		}
+	}
+	return verification, nil
+}
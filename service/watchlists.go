@@ -0,0 +1,301 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"sync"
+)
+
+// Watchlist related error definitions
+var (
+	ErrUnknownAccount = errors.New("unknown account")
+	ErrInvalidRule    = errors.New("invalid alert rule")
+	ErrInvalidChannel = errors.New("invalid notification channel")
+)
+
+// MaxWatchedValidators bounds the validators an account can watch
+const MaxWatchedValidators = 1000
+
+// Events alert rules can watch for
+const (
+	AlertMissedAttestation = "missed_attestation"
+	AlertMissedProposal    = "missed_proposal"
+	AlertSlashed           = "slashed"
+)
+
+// AlertRule selects the events of watched validators that are sent to the account's notification channels
+type AlertRule struct {
+	Event      string  `json:"event"`                // One of the Alert constants
+	Validators []int64 `json:"validators,omitempty"` // Watched validators the rule applies to, all of them if empty
+}
+
+// Watchlist is what an account watches: its validators, alert rules and notification channels
+type Watchlist struct {
+	Validators []int64     `json:"validators"`
+	Rules      []AlertRule `json:"rules"`
+	Channels   []string    `json:"channels"` // Channels in the format of ParseNotifier
+}
+
+// WatchlistStore keeps a watchlist per account. Accounts are identified by their API key, which is only stored
+// hashed, and every operation is scoped to the account of the key it is given, so an account can neither read nor
+// change the watchlist of another
+type WatchlistStore struct {
+	mu       sync.RWMutex
+	accounts map[string]*Watchlist // Watchlists by account ID
+	path     string                // JSON file the watchlists are saved to on every change, "" to keep them in memory only
+}
+
+// NewWatchlistStore creates an empty WatchlistStore kept in memory only
+func NewWatchlistStore() *WatchlistStore {
+	return &WatchlistStore{accounts: make(map[string]*Watchlist)}
+}
+
+// LoadWatchlistStore creates a WatchlistStore persisted to a JSON file, starting from the accounts it already
+// contains. A missing file is created by the first change
+func LoadWatchlistStore(path string) (*WatchlistStore, error) {
+	store := NewWatchlistStore()
+	store.path = path
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read watchlists: %w", err)
+	}
+	if err := json.Unmarshal(data, &store.accounts); err != nil {
+		return nil, fmt.Errorf("failed to decode watchlists: %w", err)
+	}
+	return store, nil
+}
+
+// accountID identifies the account of an API key by the key's SHA-256 hash
+func accountID(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:])
+}
+
+// AddAccount creates an empty watchlist for the account of an API key, keeping it if the account exists
+func (w *WatchlistStore) AddAccount(apiKey string) error {
+	if apiKey == "" {
+		return fmt.Errorf("%w: empty API key", ErrUnknownAccount)
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, ok := w.accounts[accountID(apiKey)]; ok {
+		return nil
+	}
+	w.accounts[accountID(apiKey)] = &Watchlist{Validators: []int64{}, Rules: []AlertRule{}, Channels: []string{}}
+	return w.save()
+}
+
+// NewAccount creates an account with an empty watchlist under a new random API key, returning the key and the ID
+// of the account. The key can't be recovered afterwards
+func (w *WatchlistStore) NewAccount() (apiKey, id string, err error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", fmt.Errorf("failed to generate API key: %w", err)
+	}
+	apiKey = hex.EncodeToString(secret)
+	if err := w.AddAccount(apiKey); err != nil {
+		return "", "", err
+	}
+	return apiKey, accountID(apiKey), nil
+}
+
+// HasAccount reports whether an API key belongs to an account
+func (w *WatchlistStore) HasAccount(apiKey string) bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	_, ok := w.accounts[accountID(apiKey)]
+	return ok
+}
+
+// RemoveAccount deletes the account of an API key with its watchlist
+func (w *WatchlistStore) RemoveAccount(apiKey string) error {
+	return w.RemoveAccountID(accountID(apiKey))
+}
+
+// RemoveAccountID deletes an account by its ID, so an admin can revoke a key without knowing it
+func (w *WatchlistStore) RemoveAccountID(id string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, ok := w.accounts[id]; !ok {
+		return ErrUnknownAccount
+	}
+	delete(w.accounts, id)
+	return w.save()
+}
+
+// Len returns the number of accounts
+func (w *WatchlistStore) Len() int {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return len(w.accounts)
+}
+
+// Get returns a copy of the watchlist of the account of an API key
+func (w *WatchlistStore) Get(apiKey string) (Watchlist, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	watchlist, ok := w.accounts[accountID(apiKey)]
+	if !ok {
+		return Watchlist{}, ErrUnknownAccount
+	}
+	rules := make([]AlertRule, 0, len(watchlist.Rules))
+	for _, rule := range watchlist.Rules {
+		rules = append(rules, AlertRule{Event: rule.Event, Validators: slices.Clone(rule.Validators)})
+	}
+	return Watchlist{
+		Validators: slices.Clone(watchlist.Validators),
+		Rules:      rules,
+		Channels:   slices.Clone(watchlist.Channels),
+	}, nil
+}
+
+// Watch adds validators to the watchlist of the account of an API key, ignoring those already watched. An account
+// watches at most MaxWatchedValidators validators
+func (w *WatchlistStore) Watch(apiKey string, indices ...int64) error {
+	return w.update(apiKey, func(watchlist *Watchlist) error {
+		for _, index := range indices {
+			if index < 0 {
+				return fmt.Errorf("%w: %d", ErrInvalidValidatorID, index)
+			}
+			if !slices.Contains(watchlist.Validators, index) {
+				watchlist.Validators = append(watchlist.Validators, index)
+			}
+		}
+		if len(watchlist.Validators) > MaxWatchedValidators {
+			return fmt.Errorf("%w: at most %d validators can be watched", ErrInvalidRange, MaxWatchedValidators)
+		}
+		slices.Sort(watchlist.Validators)
+		return nil
+	})
+}
+
+// Unwatch removes validators from the watchlist of the account of an API key
+func (w *WatchlistStore) Unwatch(apiKey string, indices ...int64) error {
+	return w.update(apiKey, func(watchlist *Watchlist) error {
+		watchlist.Validators = slices.DeleteFunc(watchlist.Validators, func(index int64) bool {
+			return slices.Contains(indices, index)
+		})
+		return nil
+	})
+}
+
+// SetRules replaces the alert rules of the account of an API key
+func (w *WatchlistStore) SetRules(apiKey string, rules []AlertRule) error {
+	for _, rule := range rules {
+		switch rule.Event {
+		case AlertMissedAttestation, AlertMissedProposal, AlertSlashed:
+		default:
+			return fmt.Errorf("%w: unknown event %q", ErrInvalidRule, rule.Event)
+		}
+	}
+	return w.update(apiKey, func(watchlist *Watchlist) error {
+		watchlist.Rules = make([]AlertRule, 0, len(rules))
+		for _, rule := range rules {
+			watchlist.Rules = append(watchlist.Rules, AlertRule{Event: rule.Event, Validators: slices.Clone(rule.Validators)})
+		}
+		return nil
+	})
+}
+
+// SetChannels replaces the notification channels of the account of an API key. Channels are validated with
+// ParseNotifier before they are stored
+func (w *WatchlistStore) SetChannels(apiKey string, channels []string) error {
+	for _, channel := range channels {
+		if _, err := ParseNotifier(http.DefaultClient, channel); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidChannel, err)
+		}
+	}
+	return w.update(apiKey, func(watchlist *Watchlist) error {
+		watchlist.Channels = slices.Clone(channels)
+		return nil
+	})
+}
+
+// Watched returns the validators watched by any account, in ascending order
+func (w *WatchlistStore) Watched() []int64 {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	watched := make([]int64, 0)
+	for _, watchlist := range w.accounts {
+		watched = append(watched, watchlist.Validators...)
+	}
+	slices.Sort(watched)
+	return slices.Compact(watched)
+}
+
+// Watchers returns the notification channels of the accounts with a rule for an event of a validator, so an alert
+// reaches only the accounts watching it
+func (w *WatchlistStore) Watchers(event string, index int64) []string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	channels := make([]string, 0)
+	for _, watchlist := range w.accounts {
+		if !slices.Contains(watchlist.Validators, index) {
+			continue
+		}
+		for _, rule := range watchlist.Rules {
+			if rule.Event == event && (len(rule.Validators) == 0 || slices.Contains(rule.Validators, index)) {
+				channels = append(channels, watchlist.Channels...)
+				break
+			}
+		}
+	}
+	return channels
+}
+
+// update applies a change to the watchlist of the account of an API key and saves the store, leaving the watchlist
+// untouched when the change fails
+func (w *WatchlistStore) update(apiKey string, change func(*Watchlist) error) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	watchlist, ok := w.accounts[accountID(apiKey)]
+	if !ok {
+		return ErrUnknownAccount
+	}
+
+	updated := *watchlist
+	updated.Validators = slices.Clone(watchlist.Validators)
+	if err := change(&updated); err != nil {
+		return err
+	}
+	w.accounts[accountID(apiKey)] = &updated
+	return w.save()
+}
+
+// save writes the watchlists to the file of the store, the caller holds the lock
+func (w *WatchlistStore) save() error {
+	if w.path == "" {
+		return nil
+	}
+	data, err := json.Marshal(w.accounts)
+	if err != nil {
+		return fmt.Errorf("failed to encode watchlists: %w", err)
+	}
+	if err := writeFileAtomic(w.path, data); err != nil {
+		return fmt.Errorf("failed to save watchlists: %w", err)
+	}
+	return nil
+}
+
+// Watchlists returns the watchlists of the accounts
+func (s *EthereumService) Watchlists() *WatchlistStore {
+	return s.watchlists
+}
+
+// SetWatchlists replaces the watchlists of the accounts, e.g. with ones loaded from disk
+func (s *EthereumService) SetWatchlists(watchlists *WatchlistStore) {
+	s.watchlists = watchlists
+}
@@ -0,0 +1,110 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"ethereum-validator-api/service"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+)
+
+func TestEthereumService_AlertTask(t *testing.T) {
+	var mu sync.Mutex
+	var sent []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Failed to decode notification: %v", err)
+		}
+		mu.Lock()
+		sent = append(sent, r.URL.Path+" "+body["content"])
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	received := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		got := sent
+		sent = nil
+		sort.Strings(got)
+		return got
+	}
+
+	// Validator 7 misses its attestation and proposal of epoch 1, validator 8 is slashed by epoch 2
+	beacon := &mockBeaconClient{responses: map[string]string{
+		"/eth/v1/beacon/headers/head":                     `{"data":{"root":"0xhead","header":{"message":{"slot":"100"}}}}`,
+		"/eth/v1/beacon/states/head/finality_checkpoints": `{"data":{"finalized":{"epoch":"0","root":"0x01"}}}`,
+		"/eth/v1/beacon/rewards/attestations/1":           `{"data":{"total_rewards":[{"validator_index":"7","head":"0","target":"0","source":"0"},{"validator_index":"8","head":"10","target":"20","source":"10"}]}}`,
+		"/eth/v1/validator/duties/proposer/1":             `{"data":[{"validator_index":"7","slot":"40"},{"validator_index":"8","slot":"41"}]}`,
+		"/eth/v1/beacon/blocks/41/root":                   `{"data":{"root":"0x41"}}`,
+		"/eth/v1/beacon/states/head/validators?id=7,8":    `{"data":[{"index":"7","validator":{"slashed":false}},{"index":"8","validator":{"slashed":false}}]}`,
+	}}
+	ethService := service.NewEthereumServiceWithClients(beacon, &mockExecutionClient{})
+
+	watchlists := ethService.Watchlists()
+	for _, key := range []string{"alice-key", "bob-key"} {
+		if err := watchlists.AddAccount(key); err != nil {
+			t.Fatalf("AddAccount() error = %v", err)
+		}
+	}
+	if err := watchlists.Watch("alice-key", 7, 8); err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+	rules := []service.AlertRule{{Event: service.AlertMissedAttestation}, {Event: service.AlertMissedProposal}, {Event: service.AlertSlashed}}
+	if err := watchlists.SetRules("alice-key", rules); err != nil {
+		t.Fatalf("SetRules() error = %v", err)
+	}
+	if err := watchlists.SetChannels("alice-key", []string{"discord:" + server.URL + "/alice"}); err != nil {
+		t.Fatalf("SetChannels() error = %v", err)
+	}
+
+	// Bob watches validator 7 without a rule for attestations
+	if err := watchlists.Watch("bob-key", 7); err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+	if err := watchlists.SetRules("bob-key", []service.AlertRule{{Event: service.AlertMissedProposal, Validators: []int64{7}}}); err != nil {
+		t.Fatalf("SetRules() error = %v", err)
+	}
+	if err := watchlists.SetChannels("bob-key", []string{"discord:" + server.URL + "/bob"}); err != nil {
+		t.Fatalf("SetChannels() error = %v", err)
+	}
+
+	task := ethService.AlertTask()
+	if err := task.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	want := []string{
+		"/alice Validator 7 missed a proposal\nValidator 7 was assigned slot 40 in epoch 1, but no block was proposed.",
+		"/alice Validator 7 missed an attestation\nThe attestation of validator 7 for epoch 1 was not rewarded.",
+		"/bob Validator 7 missed a proposal\nValidator 7 was assigned slot 40 in epoch 1, but no block was proposed.",
+	}
+	if got := received(); !reflect.DeepEqual(got, want) {
+		t.Errorf("First check sent %q, want %q", got, want)
+	}
+
+	// An epoch is checked once
+	if err := task.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if got := received(); len(got) != 0 {
+		t.Errorf("Second check of epoch 1 sent %q, want nothing", got)
+	}
+
+	beacon.responses["/eth/v1/beacon/headers/head"] = `{"data":{"root":"0xhead","header":{"message":{"slot":"132"}}}}`
+	beacon.responses["/eth/v1/beacon/rewards/attestations/2"] = `{"data":{"total_rewards":[{"validator_index":"7","head":"10","target":"20","source":"10"}]}}`
+	beacon.responses["/eth/v1/validator/duties/proposer/2"] = `{"data":[]}`
+	beacon.responses["/eth/v1/beacon/states/head/validators?id=7,8"] = `{"data":[{"index":"7","validator":{"slashed":false}},{"index":"8","validator":{"slashed":true}}]}`
+	ethService.ClearCache()
+	if err := task.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	want = []string{"/alice Validator 8 was slashed\nValidator 8 was found slashed in epoch 4."}
+	if got := received(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Check of epoch 2 sent %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,243 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"ethereum-validator-api/api/models/v1"
+	"ethereum-validator-api/handler"
+	"ethereum-validator-api/service"
+	"github.com/gin-gonic/gin"
+	"math"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// aprPubkey is the pubkey of validator 8 in the APR tests
+var aprPubkey = "0x" + strings.Repeat("ab", 48)
+
+// aprService returns a service whose index holds a day of rewards for validators 7 and 8 and their neighbour 9, which
+// has exited since
+func aprService() *service.EthereumService {
+	beacon := &mockBeaconClient{responses: map[string]string{
+		"/eth/v1/beacon/states/head/validators": `{"data":[{"index":"8","balance":"32000000000","status":"active_ongoing","validator":{"pubkey":"` + aprPubkey + `"}}]}`,
+		// Four active validators, half of which voted in the latest rewarded epoch
+		"/eth/v1/beacon/states/head/validators?status=active": `{"data":[` + strings.TrimSuffix(strings.Repeat(`{"validator":{"effective_balance":"32000000000"}},`, 4), ",") + `]}`,
+		"/eth/v1/beacon/headers/head":                         `{"data":{"header":{"message":{"slot":"320"}}}}`,
+		"/eth/v1/beacon/rewards/attestations/8":               `{"data":{"ideal_rewards":[{"effective_balance":"31000000000","head":"1","target":"1","source":"1"},{"effective_balance":"32000000000","head":"3000","target":"6000","source":"5000"}],"total_rewards":[{"target":"10"},{"target":"0"}]}}`,
+		"/eth/v1/beacon/states/head/validators/7":             `{"data":{"index":"7","balance":"32100000000","status":"active_ongoing","validator":{"effective_balance":"32000000000"}}}`,
+		"/eth/v1/beacon/states/head/validators/9":             `{"data":{"index":"9","balance":"0","status":"withdrawal_done","validator":{"effective_balance":"0"}}}`,
+	}}
+	ethService := service.NewEthereumServiceWithClients(beacon, &mockExecutionClient{})
+	index := ethService.Index()
+
+	// Ten epochs of attestations of validator 7 within the window, one before it
+	for epoch := int64(100); epoch < 110; epoch++ {
+		index.PutAttestation(7, &service.AttestationPerformance{Epoch: epoch, Included: true, Reward: 14000})
+	}
+	index.PutAttestation(7, &service.AttestationPerformance{Epoch: 1, Included: true, Reward: 1e9})
+
+	index.Put(&service.BlockDetail{Slot: 100, ProposerIndex: 7}) // Before the window
+	index.PutReward(100, &service.BlockReward{Status: "vanilla", Reward: big.NewInt(1e9)})
+	index.Put(&service.BlockDetail{Slot: 5000, ProposerIndex: 7})
+	index.PutReward(5000, &service.BlockReward{Status: "vanilla", Reward: big.NewInt(50e6)})
+	index.Put(&service.BlockDetail{Slot: 6000, ProposerIndex: 8})
+	index.PutReward(6000, &service.BlockReward{Status: "mev", Reward: big.NewInt(100e6)})
+	index.Put(&service.BlockDetail{Slot: 7000, ProposerIndex: 9})
+	index.PutReward(7000, &service.BlockReward{Status: "mev", Reward: big.NewInt(1e9)})
+	index.Put(&service.BlockDetail{Slot: 8000, ProposerIndex: 9, Withdrawals: []service.Withdrawal{
+		{ValidatorIndex: 7, Amount: 17000000},
+		{ValidatorIndex: 9, Amount: 5},
+	}})
+	index.Put(&service.BlockDetail{Slot: 10000, ProposerIndex: 9})
+	index.PutReward(10000, &service.BlockReward{Status: "vanilla", Reward: big.NewInt(1e6)})
+	return ethService
+}
+
+func TestEthereumService_GetValidatorAPR(t *testing.T) {
+	apr, err := aprService().GetValidatorAPR(context.Background(), []string{"7", aprPubkey, "7"}, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("GetValidatorAPR() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(apr.Validators, []int64{7, 8}) {
+		t.Errorf("Validators = %v, want [7 8]", apr.Validators)
+	}
+	if apr.FromSlot != 2801 || apr.ToSlot != 10000 || apr.Principal != 64e9 {
+		t.Errorf("Window = [%d, %d] principal %d, want [2801, 10000] principal 64e9", apr.FromSlot, apr.ToSlot, apr.Principal)
+	}
+
+	// Ten epochs of 14000 GWEI on 32 ETH, annualized over 3840 seconds
+	if apr.Consensus.Amount != 140000 || apr.AttestationEpochs != 10 || math.Abs(apr.Consensus.APR-0.03594) > 1e-4 {
+		t.Errorf("Consensus = %+v over %d epochs, want 140000 GWEI at 3.59%% over 10 epochs", apr.Consensus, apr.AttestationEpochs)
+	}
+	// Proposals of the members on 64 ETH, annualized over a day
+	if apr.ExecutionTips.Amount != 50e6 || math.Abs(apr.ExecutionTips.APR-0.2852) > 1e-3 {
+		t.Errorf("ExecutionTips = %+v, want 50e6 GWEI at 28.5%%", apr.ExecutionTips)
+	}
+	if apr.MEV.Amount != 100e6 || math.Abs(apr.MEV.APR-0.5703) > 1e-3 {
+		t.Errorf("MEV = %+v, want 100e6 GWEI at 57.0%%", apr.MEV)
+	}
+	if apr.Total.Amount != 150140000 || math.Abs(apr.Total.APR-(apr.Consensus.APR+apr.ExecutionTips.APR+apr.MEV.APR)) > 1e-9 {
+		t.Errorf("Total = %+v, want the sum of the sources", apr.Total)
+	}
+	if apr.Withdrawals != 17000000 || apr.WithdrawalCount != 1 {
+		t.Errorf("Withdrawals = %d in %d, want 17000000 in 1", apr.Withdrawals, apr.WithdrawalCount)
+	}
+	if math.Abs(apr.BlockCoverage-4.0/7200) > 1e-9 {
+		t.Errorf("BlockCoverage = %v, want 4 of 7200 slots", apr.BlockCoverage)
+	}
+	if apr.Network != nil {
+		t.Errorf("Network = %+v before the network statistics are computed, want nil", apr.Network)
+	}
+}
+
+func TestEthereumService_GetNetworkAPR(t *testing.T) {
+	ethService := aprService()
+	if _, err := ethService.GetNetworkAPR(context.Background(), 24*time.Hour); !errors.Is(err, service.ErrNetworkStatsUnavailable) {
+		t.Fatalf("GetNetworkAPR() before the network statistics error = %v, want ErrNetworkStatsUnavailable", err)
+	}
+	if err := ethService.NetworkStatsTask(service.Every(0)).Run(context.Background()); err != nil {
+		t.Fatalf("NetworkStatsTask.Run() error = %v", err)
+	}
+
+	network, err := ethService.GetNetworkAPR(context.Background(), 24*time.Hour)
+	if err != nil {
+		t.Fatalf("GetNetworkAPR() error = %v", err)
+	}
+	if network.Epoch != 8 || network.ActiveValidators != 4 || network.ActiveBalance != 128e9 || network.FromSlot != 2801 || network.ToSlot != 10000 {
+		t.Errorf("GetNetworkAPR() = %+v, want epoch 8, 4 validators of 32 ETH and the slots [2801, 10000]", network)
+	}
+	// 14000 GWEI per epoch on 32 ETH at 50% participation
+	if network.IdealAttestationReward != 14000 || network.Participation != 0.5 || math.Abs(network.Consensus-0.01796) > 1e-4 {
+		t.Errorf("Consensus = %v from %d GWEI at %v participation, want 1.80%%", network.Consensus, network.IdealAttestationReward, network.Participation)
+	}
+	// All indexed blocks of the window count, whoever proposed them
+	if network.ExecutionRewards != 1151e6 || math.Abs(network.ExecutionTips-0.1454) > 1e-3 || math.Abs(network.MEV-3.1367) > 1e-3 {
+		t.Errorf("Execution = %d GWEI, tips %v, MEV %v, want 1151e6 GWEI at 14.5%% and 313.7%%", network.ExecutionRewards, network.ExecutionTips, network.MEV)
+	}
+	if math.Abs(network.Total-(network.Consensus+network.ExecutionTips+network.MEV)) > 1e-9 {
+		t.Errorf("Total = %v, want the sum of the sources", network.Total)
+	}
+
+	apr, err := ethService.GetValidatorAPR(context.Background(), []string{"7"}, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("GetValidatorAPR() error = %v", err)
+	}
+	if apr.Network == nil || apr.Network.Total != network.Total {
+		t.Errorf("GetValidatorAPR() network = %+v, want %+v", apr.Network, network)
+	}
+}
+
+func TestEthereumService_GetValidatorAPR_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		ids     []string
+		wantErr error
+	}{
+		{"Empty list", []string{}, service.ErrInvalidValidatorList},
+		{"Invalid validator", []string{"7", "abc"}, service.ErrInvalidValidatorID},
+		{"Unknown pubkey", []string{"0x" + strings.Repeat("cd", 48)}, service.ErrValidatorNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := aprService().GetValidatorAPR(context.Background(), tt.ids, 24*time.Hour)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("GetValidatorAPR(%v) error = %v, want %v", tt.ids, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHandler_GetValidatorAPR(t *testing.T) {
+	h := handler.NewHandler(aprService())
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/validator/:id/apr", h.GetValidatorAPR)
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+	}{
+		{"Validator set", "/validator/7," + aprPubkey + "/apr?window=1d", http.StatusOK},
+		{"Default window", "/validator/7/apr", http.StatusOK},
+		{"Invalid window", "/validator/7/apr?window=2d", http.StatusBadRequest},
+		{"Invalid validator", "/validator/7,abc/apr", http.StatusBadRequest},
+		{"Unknown pubkey", "/validator/0x" + strings.Repeat("cd", 48) + "/apr", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if w.Code != tt.wantStatus {
+				t.Fatalf("GET %s = %d %s, want %d", tt.path, w.Code, w.Body.String(), tt.wantStatus)
+			}
+		})
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/validator/7,"+aprPubkey+"/apr?window=1d", nil))
+	var response v1.ValidatorAPRResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if response.Window != "1d" || response.Rewards != 150140000 || response.Sources.MEV.Amount != 100e6 || response.Withdrawals.Count != 1 {
+		t.Errorf("GET /validator/{id}/apr = %+v", response)
+	}
+	if math.Abs(response.APR-(response.Sources.Consensus.APR+response.Sources.ExecutionTips.APR+response.Sources.MEV.APR)) > 1e-9 {
+		t.Errorf("APR = %v, want the sum of the sources %+v", response.APR, response.Sources)
+	}
+	if response.VsNetwork != nil {
+		t.Errorf("VsNetwork = %+v before the network statistics are computed, want it left out", response.VsNetwork)
+	}
+}
+
+func TestHandler_GetNetworkAPR(t *testing.T) {
+	ethService := aprService()
+	h := handler.NewHandler(ethService)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/stats/apr", h.GetNetworkAPR)
+	router.GET("/validator/:id/apr", h.GetValidatorAPR)
+
+	get := func(path string, out interface{}) int {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if err := json.Unmarshal(w.Body.Bytes(), out); err != nil {
+			t.Fatalf("Failed to unmarshal response of %s: %v", path, err)
+		}
+		return w.Code
+	}
+
+	var errorResponse v1.ErrorResponse
+	if code := get("/stats/apr", &errorResponse); code != http.StatusServiceUnavailable {
+		t.Errorf("GET /stats/apr before the network statistics = %d, want 503", code)
+	}
+	if code := get("/stats/apr?window=2d", &errorResponse); code != http.StatusBadRequest {
+		t.Errorf("GET /stats/apr?window=2d = %d, want 400", code)
+	}
+
+	if err := ethService.NetworkStatsTask(service.Every(0)).Run(context.Background()); err != nil {
+		t.Fatalf("NetworkStatsTask.Run() error = %v", err)
+	}
+	var network v1.NetworkAPRResponse
+	if code := get("/stats/apr?window=1d", &network); code != http.StatusOK || network.Window != "1d" || network.IdealAttestationReward != 14000 {
+		t.Errorf("GET /stats/apr?window=1d = %d %+v", code, network)
+	}
+
+	var validator v1.ValidatorAPRResponse
+	if code := get("/validator/7/apr?window=1d", &validator); code != http.StatusOK || validator.VsNetwork == nil {
+		t.Fatalf("GET /validator/7/apr?window=1d = %d %+v, want a comparison with the network", code, validator)
+	}
+	if validator.VsNetwork.NetworkAPR != network.APR || math.Abs(validator.VsNetwork.Difference-(validator.APR-network.APR)) > 1e-9 {
+		t.Errorf("VsNetwork = %+v, want the network APR %v and the difference to it", validator.VsNetwork, network.APR)
+	}
+}
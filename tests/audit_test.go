@@ -0,0 +1,95 @@
+package tests
+
+import (
+	"encoding/json"
+	"ethereum-validator-api/api/models/v1"
+	"ethereum-validator-api/handler"
+	"ethereum-validator-api/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAuditLogPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	audit, err := service.LoadAuditLog(path)
+	if err != nil {
+		t.Fatalf("LoadAuditLog() error for a missing file: %v", err)
+	}
+	if err := audit.Record("ip:203.0.113.7", service.AuditCacheDelete, "all", nil, map[string]int{"removed": 3}); err != nil {
+		t.Fatalf("Record() error: %v", err)
+	}
+	if err := audit.Record("signal:SIGHUP", service.AuditConfigReload, "", nil, nil); err != nil {
+		t.Fatalf("Record() error: %v", err)
+	}
+
+	loaded, err := service.LoadAuditLog(path)
+	if err != nil {
+		t.Fatalf("LoadAuditLog() error: %v", err)
+	}
+	entries := loaded.Query(service.AuditFilter{})
+	if len(entries) != 2 || entries[0].Action != service.AuditConfigReload {
+		t.Fatalf("Expected 2 entries newest first, got %+v", entries)
+	}
+	if string(entries[1].After) != `{"removed":3}` || entries[1].Before != nil {
+		t.Errorf("Expected the after value to be kept, got before %s and after %s", entries[1].Before, entries[1].After)
+	}
+	if entries := loaded.Query(service.AuditFilter{Actor: "ip:203.0.113.7"}); len(entries) != 1 || entries[0].Target != "all" {
+		t.Errorf("Query() by actor = %+v, want the cache invalidation", entries)
+	}
+}
+
+func TestGetAuditLog(t *testing.T) {
+	ethService := service.NewEthereumServiceWithClients(&mockBeaconClient{}, &mockExecutionClient{})
+	h := handler.NewHandler(ethService)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	admin := router.Group("/admin", handler.APIKeyAuth("admin-key"))
+	admin.POST("/builders", h.PutBuilder)
+	admin.DELETE("/builders/:name", h.DeleteBuilder)
+	admin.GET("/audit", h.GetAuditLog)
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-API-Key", "admin-key")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	send(http.MethodPost, "/admin/builders", `{"name":"Audited","extra_data":["first"]}`)
+	send(http.MethodPost, "/admin/builders", `{"name":"Audited","extra_data":["second"]}`)
+	send(http.MethodDelete, "/admin/builders/Audited", "")
+	// Failed actions change nothing and aren't recorded
+	send(http.MethodDelete, "/admin/builders/Missing", "")
+
+	w := send(http.MethodGet, "/admin/audit", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var entries []v1.AuditEntryResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &entries); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d", len(entries))
+	}
+	if entries[0].Action != service.AuditBuilderDelete || entries[0].After != nil || !strings.Contains(string(entries[0].Before), "second") {
+		t.Errorf("Expected the removal with the removed signature first, got %+v", entries[0])
+	}
+	if !strings.Contains(string(entries[1].Before), "first") || !strings.Contains(string(entries[1].After), "second") {
+		t.Errorf("Expected the replacement to record both signatures, got before %s and after %s", entries[1].Before, entries[1].After)
+	}
+	if entries[1].Actor != service.UsageClient("admin-key", "") {
+		t.Errorf("Expected the actor to be the hashed API key, got %q", entries[1].Actor)
+	}
+
+	for _, path := range []string{"/admin/audit?since=yesterday", "/admin/audit?limit=0"} {
+		if w := send(http.MethodGet, path, ""); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for %s, got %d", path, w.Code)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"ethereum-validator-api/api/models/v1"
+	"ethereum-validator-api/handler"
+	"ethereum-validator-api/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestDetectConsensusClient(t *testing.T) {
+	tests := []struct {
+		version string
+		want    service.ConsensusClient
+	}{
+		{"Lighthouse/v5.3.0-d6ba8c3/x86_64-linux", service.ClientLighthouse},
+		{"Prysm/v5.1.0/0b94b7b3 (linux amd64)", service.ClientPrysm},
+		{"teku/v24.10.0/linux-x86_64/-eclipseadoptium-openjdk64bitservervm-java-21", service.ClientTeku},
+		{"Nimbus/v24.9.0-2b9d9b-stateofus", service.ClientNimbus},
+		{"Lodestar/v1.22.0/0a7e4b2", service.ClientLodestar},
+		{"Grandine/1.0.0", service.ClientUnknown},
+		{"", service.ClientUnknown},
+	}
+
+	for _, tt := range tests {
+		if got := service.DetectConsensusClient(tt.version); got != tt.want {
+			t.Errorf("DetectConsensusClient(%q) = %q, want %q", tt.version, got, tt.want)
+		}
+	}
+}
+
+// quirkyBeaconServer serves the version of a client, answers committee requests with Prysm's 500 for unknown states
+// and records how validators were requested
+func quirkyBeaconServer(t *testing.T, version string) (*httptest.Server, func() string) {
+	t.Helper()
+	var mu sync.Mutex
+	var validatorsRequest string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/eth/v1/node/version":
+			w.Write([]byte(`{"data":{"version":"` + version + `"}}`))
+		case "/eth/v1/beacon/headers/head":
+			w.Write([]byte(`{"data":{"header":{"message":{"slot":"320"}}}}`))
+		case "/eth/v1/beacon/states/head/validators":
+			mu.Lock()
+			validatorsRequest = r.Method + " " + r.URL.RawQuery
+			mu.Unlock()
+			w.Write([]byte(`{"data":[]}`))
+		case "/eth/v1/beacon/states/1000000000/committees":
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"code":500,"message":"Could not get state: could not find state at slot 1000000000"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, func() string {
+		mu.Lock()
+		defer mu.Unlock()
+		return validatorsRequest
+	}
+}
+
+func TestEthereumService_ClientShims(t *testing.T) {
+	service.SetUpstreamRateLimit(100, 10)
+	defer service.SetUpstreamRateLimit(1, 1)
+
+	tests := []struct {
+		name          string
+		version       string
+		wantShims     []string
+		wantNotFound  bool
+		wantValidator string
+	}{
+		{"Prysm", "Prysm/v5.1.0/0b94b7b3 (linux amd64)", []string{"not-found-errors"}, true, "POST "},
+		{"Nimbus", "Nimbus/v24.9.0-2b9d9b-stateofus", []string{"validators-by-query"}, false, "GET id=1,2"},
+		{"Teku", "teku/v24.10.0/linux-x86_64", []string{}, false, "POST "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, validatorsRequest := quirkyBeaconServer(t, tt.version)
+			ethService := service.NewEthereumServiceWithClients(service.NewHTTPBeaconClient(server.URL, http.DefaultClient), &mockExecutionClient{})
+			if err := ethService.DetectBeaconNode(context.Background()); err != nil {
+				t.Fatalf("DetectBeaconNode() error = %v", err)
+			}
+			if got := ethService.Shims(); !reflect.DeepEqual(got, tt.wantShims) {
+				t.Errorf("Shims() = %v, want %v", got, tt.wantShims)
+			}
+
+			_, err := ethService.GetCommitteesBySlot(context.Background(), 1000000000)
+			if notFound := errors.Is(err, service.ErrSlotNotFound); notFound != tt.wantNotFound {
+				t.Errorf("GetCommitteesBySlot() of an unknown state error = %v, want ErrSlotNotFound %v", err, tt.wantNotFound)
+			}
+
+			// Only the validators request matters, the summary fails later without duties
+			ethService.GetValidatorsSummary(context.Background(), []string{"1", "2"})
+			if got := validatorsRequest(); got != tt.wantValidator {
+				t.Errorf("Validators requested with %q, want %q", got, tt.wantValidator)
+			}
+		})
+	}
+}
+
+func TestHandler_GetUpstreamsHealth(t *testing.T) {
+	service.SetUpstreamRateLimit(100, 10)
+	defer service.SetUpstreamRateLimit(1, 1)
+
+	server := localNodeServer(t, "Nimbus/v24.9.0-2b9d9b-stateofus", false, new(atomic.Int32), new(atomic.Int32))
+	ethService := service.NewDisconnectedEthereumService()
+	h := handler.NewHandler(ethService)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/health/upstreams", h.GetUpstreamsHealth)
+
+	get := func() (int, v1.UpstreamsHealthResponse) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health/upstreams", nil))
+		var response v1.UpstreamsHealthResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+		return w.Code, response
+	}
+
+	if code, response := get(); code != http.StatusServiceUnavailable || response.Available || response.Beacon.Client != "unknown" {
+		t.Errorf("Before connecting got %d %+v, want 503 unavailable with an unknown client", code, response)
+	}
+
+	if err := ethService.ConnectUpstreams(context.Background(), service.UpstreamConfig{RPCURL: server.URL}); err != nil {
+		t.Fatalf("ConnectUpstreams() error = %v", err)
+	}
+	code, response := get()
+	if code != http.StatusOK || !response.Available || response.Beacon.Client != "nimbus" || response.Beacon.Version != "Nimbus/v24.9.0-2b9d9b-stateofus" {
+		t.Errorf("After connecting got %d %+v, want 200 available with the Nimbus client", code, response)
+	}
+	if !reflect.DeepEqual(response.Beacon.Shims, []string{"validators-by-query"}) {
+		t.Errorf("Shims = %v, want [validators-by-query]", response.Beacon.Shims)
+	}
+}
@@ -0,0 +1,227 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"ethereum-validator-api/service"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// benchmarkTransactions is the size of the blocks benchmarked, in the range of busy mainnet blocks
+const benchmarkTransactions = 600
+
+// largeBlock builds a signed execution block of n dynamic fee transactions with their receipts
+func largeBlock(b *testing.B, number int64, n int) (*types.Block, []*types.Receipt) {
+	b.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		b.Fatalf("Failed to generate key: %v", err)
+	}
+	signer := types.LatestSignerForChainID(big.NewInt(1))
+	recipient := common.HexToAddress("0x3fc91a3afd70395cd496c647d5a6cc9d4b2b7fad")
+
+	transactions := make([]*types.Transaction, n)
+	receipts := make([]*types.Receipt, n)
+	for i := range transactions {
+		tip := big.NewInt(int64(1e9 + i*1e6))
+		transactions[i] = types.MustSignNewTx(key, signer, &types.DynamicFeeTx{
+			ChainID:   big.NewInt(1),
+			Nonce:     uint64(i),
+			To:        &recipient,
+			Gas:       100000,
+			GasFeeCap: big.NewInt(100e9),
+			GasTipCap: tip,
+			Data:      make([]byte, 68),
+		})
+		receipts[i] = &types.Receipt{
+			TxHash:            transactions[i].Hash(),
+			TransactionIndex:  uint(i),
+			Type:              types.DynamicFeeTxType,
+			Status:            types.ReceiptStatusSuccessful,
+			GasUsed:           uint64(21000 + i%50*1000),
+			EffectiveGasPrice: new(big.Int).Add(big.NewInt(10e9), tip),
+			Logs:              []*types.Log{},
+		}
+	}
+
+	header := &types.Header{Number: big.NewInt(number), BaseFee: big.NewInt(10e9), Extra: []byte("beaverbuild.org")}
+	return types.NewBlockWithHeader(header).WithBody(types.Body{Transactions: transactions}), receipts
+}
+
+// benchmarkService returns a service serving the given block at slot 100 from mocked clients. The slot isn't finalized,
+// so its lookups are cached
+func benchmarkService(block *types.Block, receipts []*types.Receipt) *service.EthereumService {
+	execution := &mockExecutionClient{
+		blocks:   []*types.Block{block},
+		receipts: map[common.Hash][]*types.Receipt{block.Hash(): receipts},
+	}
+	beacon := &mockBeaconClient{responses: map[string]string{
+		"/eth/v1/beacon/blocks/100/root":                  `{"data":{"root":"0x01"}}`,
+		"/eth/v2/beacon/blocks/100":                       beaconBlockWithPayload(block.Number().Int64()),
+		"/eth/v1/beacon/headers/head":                     `{"data":{"root":"0xabc","header":{"message":{"slot":"128"}}}}`,
+		"/eth/v1/beacon/states/head/finality_checkpoints": `{"data":{"finalized":{"epoch":"2","root":"0x01"}}}`,
+	}}
+	return service.NewEthereumServiceWithClients(beacon, execution)
+}
+
+func BenchmarkBlockReward_Compute(b *testing.B) {
+	block, receipts := largeBlock(b, 100, benchmarkTransactions)
+	ethService := benchmarkService(block, receipts)
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		ethService.ClearCache()
+		b.StartTimer()
+
+		if _, err := ethService.GetBlockRewardBySlot(ctx, 100); err != nil {
+			b.Fatalf("GetBlockRewardBySlot() unexpected error: %v", err)
+		}
+	}
+}
+
+func BenchmarkBlockReward_Cached(b *testing.B) {
+	block, receipts := largeBlock(b, 100, benchmarkTransactions)
+	ethService := benchmarkService(block, receipts)
+	ctx := context.Background()
+	if _, err := ethService.GetBlockRewardBySlot(ctx, 100); err != nil {
+		b.Fatalf("GetBlockRewardBySlot() unexpected error: %v", err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := ethService.GetBlockRewardBySlot(ctx, 100); err != nil {
+				b.Errorf("GetBlockRewardBySlot() unexpected error: %v", err)
+				return
+			}
+		}
+	})
+}
+
+func BenchmarkTransactionRewards(b *testing.B) {
+	block, receipts := largeBlock(b, 100, benchmarkTransactions)
+	ethService := benchmarkService(block, receipts)
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		ethService.ClearCache()
+		b.StartTimer()
+
+		if _, err := ethService.GetTransactionRewardsBySlot(ctx, 100, 10); err != nil {
+			b.Fatalf("GetTransactionRewardsBySlot() unexpected error: %v", err)
+		}
+	}
+}
+
+func BenchmarkReceiptsJSON(b *testing.B) {
+	_, receipts := largeBlock(b, 100, benchmarkTransactions)
+	data, err := json.Marshal(receipts)
+	if err != nil {
+		b.Fatalf("Failed to encode receipts: %v", err)
+	}
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var decoded []*types.Receipt
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			b.Fatalf("Failed to decode receipts: %v", err)
+		}
+	}
+}
+
+func BenchmarkBeaconBlockJSON(b *testing.B) {
+	block, _ := largeBlock(b, 100, benchmarkTransactions)
+	transactions := make([]string, 0, len(block.Transactions()))
+	for _, tx := range block.Transactions() {
+		encoded, err := tx.MarshalBinary()
+		if err != nil {
+			b.Fatalf("Failed to encode transaction: %v", err)
+		}
+		transactions = append(transactions, fmt.Sprintf(`"0x%x"`, encoded))
+	}
+	data := []byte(fmt.Sprintf(`{"data":{"message":{"slot":"100","proposer_index":"7","body":{"graffiti":"0x00","execution_payload":{`+
+		`"fee_recipient":"0x388c818ca8b9251b393131c08a736a67ccb19297","block_hash":"%s","block_number":"100","extra_data":"0x%x",`+
+		`"base_fee_per_gas":"10000000000","transactions":[%s]}}}}}`, block.Hash().Hex(), block.Extra(), strings.Join(transactions, ",")))
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var decoded service.BeaconBlockResponse
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			b.Fatalf("Failed to decode beacon block: %v", err)
+		}
+	}
+}
+
+func BenchmarkCache_Hit(b *testing.B) {
+	beacon := &mockBeaconClient{responses: map[string]string{
+		"/eth/v1/beacon/headers/head":                     `{"data":{"root":"0xabc","header":{"message":{"slot":"8192"}}}}`,
+		"/eth/v1/beacon/states/head/finality_checkpoints": `{"data":{"finalized":{"epoch":"254","root":"0x01"}}}`,
+	}}
+	ethService := service.NewEthereumServiceWithClients(beacon, &mockExecutionClient{})
+	ctx := context.Background()
+	if _, err := ethService.GetChainHead(ctx); err != nil {
+		b.Fatalf("GetChainHead() unexpected error: %v", err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := ethService.GetChainHead(ctx); err != nil {
+				b.Errorf("GetChainHead() unexpected error: %v", err)
+				return
+			}
+		}
+	})
+}
+
+func BenchmarkCache_Stats(b *testing.B) {
+	// Fill the cache with the committees of 1024 slots, 64 committees of 100 validators each
+	validators := make([]string, 100)
+	for i := range validators {
+		validators[i] = fmt.Sprintf(`"%d"`, i)
+	}
+	committees := make([]string, 64)
+	for i := range committees {
+		committees[i] = fmt.Sprintf(`{"index":"%d","slot":"0","validators":[%s]}`, i, strings.Join(validators, ","))
+	}
+	response := `{"data":[` + strings.Join(committees, ",") + `]}`
+
+	beacon := &mockBeaconClient{responses: map[string]string{}}
+	for slot := int64(0); slot < 1024; slot++ {
+		beacon.responses[fmt.Sprintf("/eth/v1/beacon/states/%d/committees?slot=%d", slot/32*32, slot)] = response
+	}
+	ethService := service.NewEthereumServiceWithClients(beacon, &mockExecutionClient{})
+	ctx := context.Background()
+	for slot := int64(0); slot < 1024; slot++ {
+		if _, err := ethService.GetCommitteesBySlot(ctx, slot); err != nil {
+			b.Fatalf("GetCommitteesBySlot() unexpected error: %v", err)
+		}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if stats := ethService.CacheStats(); stats.Entries != 1024 {
+			b.Fatalf("Expected 1024 cached entries, got %d", stats.Entries)
+		}
+	}
+}
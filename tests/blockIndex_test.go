@@ -0,0 +1,78 @@
+package tests
+
+import (
+	"ethereum-validator-api/service"
+	"math/big"
+	"testing"
+)
+
+func TestBlockIndex_RewardPercentile(t *testing.T) {
+	index := service.NewBlockIndex()
+
+	if _, ok := index.RewardPercentile(100, big.NewInt(10), 1000); ok {
+		t.Error("RewardPercentile() on empty index returned ok, want false")
+	}
+
+	for slot := int64(1); slot <= 4; slot++ {
+		index.PutReward(slot, &service.BlockReward{Reward: big.NewInt(slot * 10)})
+	}
+
+	tests := []struct {
+		name   string
+		slot   int64
+		reward int64
+		window int
+		want   float64
+	}{
+		{name: "Above all rewards", slot: 5, reward: 100, window: 1000, want: 100},
+		{name: "Below all rewards", slot: 5, reward: 1, window: 1000, want: 0},
+		{name: "Equal counts as half", slot: 5, reward: 20, window: 1000, want: 37.5},
+		{name: "Excludes own slot", slot: 4, reward: 40, window: 1000, want: 100},
+		{name: "Window covers the slots before", slot: 5, reward: 25, window: 2, want: 0},
+		{name: "Ignores later slots", slot: 3, reward: 15, window: 1000, want: 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := index.RewardPercentile(tt.slot, big.NewInt(tt.reward), tt.window)
+			if !ok || got != tt.want {
+				t.Errorf("RewardPercentile() = (%v, %v), want (%v, true)", got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestBlockIndex_Window(t *testing.T) {
+	index := service.NewBlockIndex()
+	index.SetWindow(64)
+
+	for slot := int64(0); slot < 200; slot++ {
+		index.Put(&service.BlockDetail{Slot: slot})
+		index.PutReward(slot, &service.BlockReward{Reward: big.NewInt(slot)})
+	}
+	index.PutAttestation(1, &service.AttestationPerformance{Epoch: 0})
+	index.PutAttestation(1, &service.AttestationPerformance{Epoch: 5})
+
+	// Entries behind the window are pruned once per epoch, so at most an epoch more than the window is kept
+	if got := index.Len(); got < 64 || got > 64+service.SlotsPerEpoch {
+		t.Errorf("Len() = %d, want between 64 and %d", got, 64+service.SlotsPerEpoch)
+	}
+	if _, ok := index.Get(10); ok {
+		t.Error("Get(10) found a block behind the window")
+	}
+	if _, ok := index.GetReward(199); !ok {
+		t.Error("GetReward(199) missing the latest reward")
+	}
+
+	// Slots behind the window aren't added again
+	index.PutReward(5, &service.BlockReward{Reward: big.NewInt(5)})
+	if _, ok := index.GetReward(5); ok {
+		t.Error("GetReward(5) found a reward put behind the window")
+	}
+	if _, ok := index.GetAttestation(1, 0); ok {
+		t.Error("GetAttestation(1, 0) found an epoch behind the window")
+	}
+	if _, ok := index.GetAttestation(1, 5); !ok {
+		t.Error("GetAttestation(1, 5) missing an epoch within the window")
+	}
+}
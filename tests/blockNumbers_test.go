@@ -0,0 +1,140 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"ethereum-validator-api/api/models/v1"
+	"ethereum-validator-api/handler"
+	"ethereum-validator-api/service"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/gin-gonic/gin"
+)
+
+func TestBlockNumberIndexPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "block_numbers.json")
+
+	index, err := service.LoadBlockNumberIndex(path)
+	if err != nil {
+		t.Fatalf("LoadBlockNumberIndex() error for a missing file: %v", err)
+	}
+	index.Put(200, 150)
+	index.Put(201, 151)
+	// The slot was reorged onto another block, the old block no longer maps to it
+	index.Put(201, 152)
+	if err := index.Save(); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	loaded, err := service.LoadBlockNumberIndex(path)
+	if err != nil {
+		t.Fatalf("LoadBlockNumberIndex() error: %v", err)
+	}
+	if loaded.Len() != 2 {
+		t.Errorf("Expected 2 mappings, got %d", loaded.Len())
+	}
+	if number, ok := loaded.BlockNumber(201); !ok || number != 152 {
+		t.Errorf("BlockNumber(201) = %d, %v, want 152", number, ok)
+	}
+	if slot, ok := loaded.Slot(150); !ok || slot != 200 {
+		t.Errorf("Slot(150) = %d, %v, want 200", slot, ok)
+	}
+	if _, ok := loaded.Slot(151); ok {
+		t.Error("Expected the reorged block 151 to be unmapped")
+	}
+}
+
+func TestBlockRewardUsesExecutionBlockNumber(t *testing.T) {
+	// Slot 200 carried execution block 150, a block 200 exists too and must not be used
+	carried := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(150), BaseFee: big.NewInt(10)})
+	other := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(200), BaseFee: big.NewInt(10)})
+	execution := &mockExecutionClient{
+		blocks: []*types.Block{carried, other},
+		receipts: map[common.Hash][]*types.Receipt{
+			carried.Hash(): {{TxHash: common.HexToHash("0x01"), GasUsed: 1000000, EffectiveGasPrice: big.NewInt(1000000010)}},
+			other.Hash():   {{TxHash: common.HexToHash("0x02"), GasUsed: 1000000, EffectiveGasPrice: big.NewInt(5000000010)}},
+		},
+	}
+	beacon := &mockBeaconClient{responses: map[string]string{
+		"/eth/v1/beacon/blocks/200/root": `{"data":{"root":"0x01"}}`,
+		"/eth/v2/beacon/blocks/200":      beaconBlockWithPayload(150),
+	}}
+	ethService := service.NewEthereumServiceWithClients(beacon, execution)
+
+	reward, err := ethService.GetBlockRewardBySlot(context.Background(), 200)
+	if err != nil {
+		t.Fatalf("GetBlockRewardBySlot() unexpected error: %v", err)
+	}
+	if reward.Reward.Int64() != 1000000 {
+		t.Errorf("GetBlockRewardBySlot() reward = %s gwei, want 1000000 from block 150", reward.Reward)
+	}
+	if number, ok := ethService.BlockNumbers().BlockNumber(200); !ok || number != 150 {
+		t.Errorf("Expected slot 200 to be mapped to block 150, got %d, %v", number, ok)
+	}
+}
+
+func TestConvertEndpoints(t *testing.T) {
+	beacon := &mockBeaconClient{responses: map[string]string{
+		"/eth/v1/beacon/blocks/200/root":                  `{"data":{"root":"0x01"}}`,
+		"/eth/v2/beacon/blocks/200":                       beaconBlockWithPayload(150),
+		"/eth/v1/beacon/headers/head":                     `{"data":{"root":"0xabc","header":{"message":{"slot":"256"}}}}`,
+		"/eth/v1/beacon/states/head/finality_checkpoints": `{"data":{"finalized":{"epoch":"2","root":"0x01"}}}`,
+	}}
+	h := handler.NewHandler(service.NewEthereumServiceWithClients(beacon, &mockExecutionClient{}))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/convert/block/:number", h.ConvertBlockNumber)
+	router.GET("/convert/slot/:slot/block", h.ConvertSlot)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/convert/slot/200/block", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var response v1.BlockNumberConversionResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Slot != 200 || response.BlockNumber != 150 {
+		t.Errorf("Expected slot 200 to carry block 150, got %+v", response)
+	}
+
+	// The block is resolved from the mapping learned by the previous lookup
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/convert/block/150", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Slot != 200 || response.BlockNumber != 150 {
+		t.Errorf("Expected block 150 at slot 200, got %+v", response)
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+	}{
+		{"Invalid block number", "/convert/block/0x96", http.StatusBadRequest},
+		{"Negative block number", "/convert/block/-1", http.StatusBadRequest},
+		{"Missed slot", "/convert/slot/201/block", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
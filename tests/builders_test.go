@@ -0,0 +1,124 @@
+package tests
+
+import (
+	"ethereum-validator-api/service"
+	"testing"
+)
+
+func TestBuilderRegistry_Resolve(t *testing.T) {
+	registry := service.NewBuilderRegistry(service.DefaultBuilderSignatures())
+
+	tests := []struct {
+		name      string
+		extraData string
+		want      string
+	}{
+		{
+			name:      "Flashbots hex extraData",
+			extraData: "0x496c6c756d696e61746520446d6f63726174697a6520447374726962757465", // "Illuminate Dmocratize Dstribute"
+			want:      "Flashbots",
+		},
+		{
+			name:      "Titan hex extraData",
+			extraData: "0x546974616e2028746974616e6275696c6465722e78797a29", // "Titan (titanbuilder.xyz)"
+			want:      "Titan",
+		},
+		{
+			name:      "beaverbuild hex extraData",
+			extraData: "0x6265617665726275696c642e6f7267", // "beaverbuild.org"
+			want:      "beaverbuild",
+		},
+		{
+			name:      "rsync-builder plain extraData",
+			extraData: "rsync-builder.xyz",
+			want:      "rsync-builder",
+		},
+		{
+			name:      "Eden phrase",
+			extraData: "Eden Network",
+			want:      "Eden",
+		},
+		{
+			name:      "Signature inside a word",
+			extraData: "golden eden-like titanbuilders",
+			want:      "",
+		},
+		{
+			name:      "Bare builder names in graffiti",
+			extraData: "rsync my titan eden node",
+			want:      "",
+		},
+		{
+			name:      "Vanilla client extraData",
+			extraData: "0x6765746820676f312e32312e35", // "geth go1.21.5"
+			want:      "",
+		},
+		{
+			name:      "Empty extraData",
+			extraData: "0x",
+			want:      "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := registry.Resolve(tt.extraData, ""); got != tt.want {
+				t.Errorf("Resolve() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuilderRegistry_PutRemove(t *testing.T) {
+	registry := service.NewBuilderRegistry(nil)
+	extraData := "0x6e65772d6275696c646572" // "new-builder"
+
+	if got := registry.Resolve(extraData, ""); got != "" {
+		t.Errorf("Resolve() before Put = %q, want empty", got)
+	}
+
+	registry.Put(service.BuilderSignature{Name: "New Builder", ExtraData: []string{"NEW-BUILDER"}})
+	if got := registry.Resolve(extraData, ""); got != "New Builder" {
+		t.Errorf("Resolve() after Put = %q, want %q", got, "New Builder")
+	}
+
+	if !registry.Remove("new builder") {
+		t.Error("Remove() = false, want true")
+	}
+	if got := registry.Resolve(extraData, ""); got != "" {
+		t.Errorf("Resolve() after Remove = %q, want empty", got)
+	}
+}
+
+func TestBuilderRegistry_PublicKeys(t *testing.T) {
+	registry := service.NewBuilderRegistry(service.DefaultBuilderSignatures())
+	titan := "0x546974616e2028746974616e6275696c6465722e78797a29" // "Titan (titanbuilder.xyz)"
+
+	registry.Put(service.BuilderSignature{Name: "Keyed Builder", PublicKeys: []string{"0xAAAA"}})
+	if got := registry.Resolve("", "0xaaaa"); got != "Keyed Builder" {
+		t.Errorf("Resolve() by a listed pubkey = %q, want %q", got, "Keyed Builder")
+	}
+	// A listed pubkey wins over extraData naming another builder
+	if got := registry.Resolve(titan, "0xAAAA"); got != "Keyed Builder" {
+		t.Errorf("Resolve() by a listed pubkey with Titan extraData = %q, want %q", got, "Keyed Builder")
+	}
+
+	if got := registry.Resolve("", "0xbbbb"); got != "" {
+		t.Errorf("Resolve() by an unknown pubkey = %q, want empty", got)
+	}
+	// Payloads whose extraData names no builder teach nothing
+	registry.Learn("0x6765746820676f312e32312e35", "0xcccc")
+	registry.Learn(titan, "0xBBBB")
+	if got := registry.Resolve("", "0xcccc"); got != "" {
+		t.Errorf("Resolve() by a pubkey of a vanilla payload = %q, want empty", got)
+	}
+	if got := registry.Resolve("", "0xbbbb"); got != "Titan" {
+		t.Errorf("Resolve() by a learned pubkey = %q, want Titan", got)
+	}
+
+	// Removing a builder forgets its learned pubkeys
+	registry.Remove("Titan")
+	if got := registry.Resolve("", "0xbbbb"); got != "" {
+		t.Errorf("Resolve() by a pubkey of a removed builder = %q, want empty", got)
+	}
+}
@@ -0,0 +1,827 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"ethereum-validator-api/api/models/v1"
+	"ethereum-validator-api/handler"
+	"ethereum-validator-api/service"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/altair"
+	"github.com/attestantio/go-eth2-client/spec/bellatrix"
+	"github.com/attestantio/go-eth2-client/spec/deneb"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/gin-gonic/gin"
+	"github.com/holiman/uint256"
+	"github.com/prysmaticlabs/go-bitfield"
+)
+
+// mockBeaconClient serves canned Beacon API responses keyed by path
+type mockBeaconClient struct {
+	responses map[string]string
+}
+
+func (m *mockBeaconClient) GetJSON(ctx context.Context, path string, out interface{}) error {
+	response, ok := m.responses[path]
+	if !ok {
+		return service.ErrSlotNotFound
+	}
+	return json.Unmarshal([]byte(response), out)
+}
+
+func (m *mockBeaconClient) PostJSON(ctx context.Context, path string, body interface{}, out interface{}) error {
+	return m.GetJSON(ctx, path, out)
+}
+
+// beaconBlockWithPayload returns a beacon block response carrying the execution block with the given number
+func beaconBlockWithPayload(blockNumber int64) string {
+	return fmt.Sprintf(`{"data":{"message":{"proposer_index":"7","body":{"execution_payload":{"block_hash":"0x%064x","block_number":"%d"}}}}}`, blockNumber, blockNumber)
+}
+
+// mockExecutionClient serves canned JSON-RPC results keyed by method, and typed blocks and receipts
+type mockExecutionClient struct {
+	results  map[string]string
+	blocks   []*types.Block
+	receipts map[common.Hash][]*types.Receipt
+}
+
+func (m *mockExecutionClient) Call(ctx context.Context, method string, params []interface{}, out interface{}) error {
+	result, ok := m.results[method]
+	if !ok {
+		return service.ErrRPCFailed
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal([]byte(result), out)
+}
+
+func (m *mockExecutionClient) BlockWithReceipts(ctx context.Context, number *big.Int) (*types.Block, []*types.Receipt, error) {
+	for _, block := range m.blocks {
+		if block.Number().Cmp(number) == 0 {
+			return block, m.receipts[block.Hash()], nil
+		}
+	}
+	return nil, nil, ethereum.NotFound
+}
+
+func TestEthereumService_GetChainHeadWithMockClients(t *testing.T) {
+	beacon := &mockBeaconClient{responses: map[string]string{
+		"/eth/v1/beacon/headers/head": `{"data":{"root":"0xabc","header":{"message":{"slot":"8192"}}}}`,
+		"/eth/v1/beacon/states/head/finality_checkpoints": `{"data":{
+			"previous_justified":{"epoch":"254","root":"0x01"},
+			"current_justified":{"epoch":"255","root":"0x02"},
+			"finalized":{"epoch":"254","root":"0x01"}}}`,
+	}}
+	ethService := service.NewEthereumServiceWithClients(beacon, &mockExecutionClient{})
+
+	head, err := ethService.GetChainHead(context.Background())
+	if err != nil {
+		t.Fatalf("GetChainHead() unexpected error: %v", err)
+	}
+
+	if head.Slot != 8192 || head.Epoch != 256 || head.SyncPeriod != 1 {
+		t.Errorf("GetChainHead() slot/epoch/period = %d/%d/%d, want 8192/256/1", head.Slot, head.Epoch, head.SyncPeriod)
+	}
+	if head.Root != "0xabc" {
+		t.Errorf("GetChainHead() root = %s, want 0xabc", head.Root)
+	}
+	if head.Finalized.Epoch != 254 || head.CurrentJustified.Epoch != 255 {
+		t.Errorf("GetChainHead() finalized/justified = %d/%d, want 254/255", head.Finalized.Epoch, head.CurrentJustified.Epoch)
+	}
+}
+
+func TestEthereumService_GetBlockDetailNotFoundWithMockClients(t *testing.T) {
+	ethService := service.NewEthereumServiceWithClients(&mockBeaconClient{}, &mockExecutionClient{})
+
+	_, err := ethService.GetBlockDetailBySlot(context.Background(), 100)
+	if !errors.Is(err, service.ErrSlotNotFound) {
+		t.Errorf("GetBlockDetailBySlot() error = %v, want %v", err, service.ErrSlotNotFound)
+	}
+}
+
+func TestEthereumService_GetTransactionRewardsWithMockClients(t *testing.T) {
+	header := &types.Header{Number: big.NewInt(100), BaseFee: big.NewInt(10)}
+	block := types.NewBlockWithHeader(header)
+	receipts := []*types.Receipt{
+		{TxHash: common.HexToHash("0x01"), TransactionIndex: 0, Type: types.DynamicFeeTxType, GasUsed: 21000, EffectiveGasPrice: big.NewInt(12)},
+		{TxHash: common.HexToHash("0x02"), TransactionIndex: 1, Type: types.LegacyTxType, GasUsed: 50000, EffectiveGasPrice: big.NewInt(15)},
+		{TxHash: common.HexToHash("0x03"), TransactionIndex: 2, Type: types.DynamicFeeTxType, GasUsed: 30000, EffectiveGasPrice: big.NewInt(10)},
+	}
+	execution := &mockExecutionClient{
+		blocks:   []*types.Block{block},
+		receipts: map[common.Hash][]*types.Receipt{block.Hash(): receipts},
+	}
+	beacon := &mockBeaconClient{responses: map[string]string{
+		"/eth/v1/beacon/blocks/100/root": `{"data":{"root":"0x01"}}`,
+		"/eth/v2/beacon/blocks/100":      beaconBlockWithPayload(100),
+	}}
+	ethService := service.NewEthereumServiceWithClients(beacon, execution)
+
+	rewards, err := ethService.GetTransactionRewardsBySlot(context.Background(), 100, 2)
+	if err != nil {
+		t.Fatalf("GetTransactionRewardsBySlot() unexpected error: %v", err)
+	}
+
+	if len(rewards) != 2 {
+		t.Fatalf("GetTransactionRewardsBySlot() returned %d rewards, want 2", len(rewards))
+	}
+	if rewards[0].Index != 1 || rewards[0].Contribution.Int64() != 250000 {
+		t.Errorf("GetTransactionRewardsBySlot() top reward = index %d contribution %s, want index 1 contribution 250000", rewards[0].Index, rewards[0].Contribution)
+	}
+	if rewards[1].Index != 0 || rewards[1].EffectivePriorityFee.Int64() != 2 {
+		t.Errorf("GetTransactionRewardsBySlot() second reward = index %d priority fee %s, want index 0 priority fee 2", rewards[1].Index, rewards[1].EffectivePriorityFee)
+	}
+}
+
+// mockDecodingBeaconClient additionally serves fork-aware blocks keyed by block ID
+type mockDecodingBeaconClient struct {
+	mockBeaconClient
+	blocks map[string]*spec.VersionedSignedBeaconBlock
+}
+
+func (m *mockDecodingBeaconClient) SignedBeaconBlock(ctx context.Context, blockID string) (*spec.VersionedSignedBeaconBlock, error) {
+	block, ok := m.blocks[blockID]
+	if !ok {
+		return nil, service.ErrSlotNotFound
+	}
+	return block, nil
+}
+
+func TestEthereumService_GetBlockDetailWithTypedBlocks(t *testing.T) {
+	graffiti := [32]byte{}
+	copy(graffiti[:], "lighthouse")
+	syncBits := bitfield.NewBitvector512()
+	syncBits.SetBitAt(0, true)
+	syncBits.SetBitAt(9, true)
+
+	// A contract creation, a EIP-1559 transfer and a transaction that cannot be decoded
+	creation, _ := types.NewTx(&types.LegacyTx{Nonce: 1, Gas: 100000, GasPrice: big.NewInt(1)}).MarshalBinary()
+	to := common.HexToAddress("0x01")
+	transfer, _ := types.NewTx(&types.DynamicFeeTx{ChainID: big.NewInt(1), To: &to, Gas: 21000, GasFeeCap: big.NewInt(2), GasTipCap: big.NewInt(1)}).MarshalBinary()
+
+	block := &spec.VersionedSignedBeaconBlock{
+		Version: spec.DataVersionDeneb,
+		Deneb: &deneb.SignedBeaconBlock{
+			Message: &deneb.BeaconBlock{
+				Slot:          100,
+				ProposerIndex: 42,
+				Body: &deneb.BeaconBlockBody{
+					ETH1Data: &phase0.ETH1Data{BlockHash: make([]byte, 32)},
+					Graffiti: graffiti,
+					SyncAggregate: &altair.SyncAggregate{
+						SyncCommitteeBits: syncBits,
+					},
+					ExecutionPayload: &deneb.ExecutionPayload{
+						FeeRecipient:  bellatrix.ExecutionAddress{0xab},
+						BlockNumber:   1234,
+						GasLimit:      30000000,
+						GasUsed:       15000000,
+						BaseFeePerGas: uint256.NewInt(7),
+						Transactions:  []bellatrix.Transaction{creation, transfer, {0x02}},
+					},
+				},
+			},
+		},
+	}
+	beacon := &mockDecodingBeaconClient{blocks: map[string]*spec.VersionedSignedBeaconBlock{"100": block}}
+	ethService := service.NewEthereumServiceWithClients(beacon, &mockExecutionClient{})
+
+	detail, err := ethService.GetBlockDetailBySlot(context.Background(), 100)
+	if err != nil {
+		t.Fatalf("GetBlockDetailBySlot() unexpected error: %v", err)
+	}
+
+	if detail.ProposerIndex != 42 || detail.Graffiti != "lighthouse" {
+		t.Errorf("GetBlockDetailBySlot() proposer/graffiti = %d/%q, want 42/\"lighthouse\"", detail.ProposerIndex, detail.Graffiti)
+	}
+	if detail.ExecutionBlockNumber != 1234 || detail.TxCount != 3 || detail.GasUsed != 15000000 {
+		t.Errorf("GetBlockDetailBySlot() block number/txs/gas used = %d/%d/%d, want 1234/3/15000000", detail.ExecutionBlockNumber, detail.TxCount, detail.GasUsed)
+	}
+	if want := (service.TransactionTypes{Legacy: 1, DynamicFee: 1, ContractCreation: 1, Unknown: 1}); detail.TransactionTypes != want {
+		t.Errorf("GetBlockDetailBySlot() transaction types = %+v, want %+v", detail.TransactionTypes, want)
+	}
+	if detail.BaseFee == nil || detail.BaseFee.Int64() != 7 {
+		t.Errorf("GetBlockDetailBySlot() base fee = %v, want 7", detail.BaseFee)
+	}
+	if detail.FeeRecipient != "0xab00000000000000000000000000000000000000" {
+		t.Errorf("GetBlockDetailBySlot() fee recipient = %s", detail.FeeRecipient)
+	}
+	if detail.SyncParticipation != 2 {
+		t.Errorf("GetBlockDetailBySlot() sync participation = %d, want 2", detail.SyncParticipation)
+	}
+	if detail.BlockRoot == "" {
+		t.Error("GetBlockDetailBySlot() block root is empty")
+	}
+}
+
+func TestEthereumService_GetBlockRewardWithMockClients(t *testing.T) {
+	header := &types.Header{Number: big.NewInt(100), BaseFee: big.NewInt(10), Extra: []byte("builder")}
+	block := types.NewBlockWithHeader(header)
+	receipts := []*types.Receipt{
+		{TxHash: common.HexToHash("0x01"), GasUsed: 1000000, EffectiveGasPrice: big.NewInt(2000000010)},
+	}
+	execution := &mockExecutionClient{
+		blocks:   []*types.Block{block},
+		receipts: map[common.Hash][]*types.Receipt{block.Hash(): receipts},
+	}
+
+	tests := []struct {
+		name      string
+		responses map[string]string
+		wantErr   error
+	}{
+		{
+			name: "Proposed slot",
+			responses: map[string]string{
+				"/eth/v1/beacon/blocks/100/root": `{"data":{"root":"0x01"}}`,
+				"/eth/v2/beacon/blocks/100":      beaconBlockWithPayload(100),
+			},
+		},
+		{
+			name:      "Missed slot",
+			responses: map[string]string{},
+			wantErr:   service.ErrSlotNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ethService := service.NewEthereumServiceWithClients(&mockBeaconClient{responses: tt.responses}, execution)
+
+			reward, err := ethService.GetBlockRewardBySlot(context.Background(), 100)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("GetBlockRewardBySlot() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetBlockRewardBySlot() unexpected error: %v", err)
+			}
+
+			// 2 gwei priority fee for 1M gas
+			if reward.Reward.Int64() != 2000000 {
+				t.Errorf("GetBlockRewardBySlot() reward = %s gwei, want 2000000", reward.Reward)
+			}
+		})
+	}
+}
+
+func TestEthereumService_GetBlockRewardServesNearHeadFromCache(t *testing.T) {
+	header := &types.Header{Number: big.NewInt(100), BaseFee: big.NewInt(10)}
+	block := types.NewBlockWithHeader(header)
+	execution := &mockExecutionClient{blocks: []*types.Block{block}}
+	beacon := &mockBeaconClient{responses: map[string]string{
+		"/eth/v1/beacon/blocks/100/root":                  `{"data":{"root":"0x01"}}`,
+		"/eth/v2/beacon/blocks/100":                       beaconBlockWithPayload(100),
+		"/eth/v1/beacon/headers/head":                     `{"data":{"root":"0xabc","header":{"message":{"slot":"128"}}}}`,
+		"/eth/v1/beacon/states/head/finality_checkpoints": `{"data":{"finalized":{"epoch":"2","root":"0x01"}}}`,
+	}}
+	ethService := service.NewEthereumServiceWithClients(beacon, execution)
+
+	reward, err := ethService.GetBlockRewardBySlot(context.Background(), 100)
+	if err != nil {
+		t.Fatalf("GetBlockRewardBySlot() unexpected error: %v", err)
+	}
+	if reward.Finalized {
+		t.Error("GetBlockRewardBySlot() finalized = true, want false after the finalized checkpoint")
+	}
+
+	// The slot isn't finalized yet, so the cached reward is served without going upstream
+	execution.blocks = nil
+	cached, err := ethService.GetBlockRewardBySlot(context.Background(), 100)
+	if err != nil {
+		t.Fatalf("GetBlockRewardBySlot() unexpected error for cached slot: %v", err)
+	}
+	if cached != reward {
+		t.Error("GetBlockRewardBySlot() did not serve the cached reward")
+	}
+}
+
+func TestEthereumService_GetBlockRewardCachesFinalizedSlot(t *testing.T) {
+	header := &types.Header{Number: big.NewInt(100), BaseFee: big.NewInt(10)}
+	execution := &mockExecutionClient{blocks: []*types.Block{types.NewBlockWithHeader(header)}}
+	beacon := &mockBeaconClient{responses: map[string]string{
+		"/eth/v1/beacon/blocks/100/root":                  `{"data":{"root":"0x01"}}`,
+		"/eth/v2/beacon/blocks/100":                       beaconBlockWithPayload(100),
+		"/eth/v1/beacon/headers/head":                     `{"data":{"root":"0xabc","header":{"message":{"slot":"200"}}}}`,
+		"/eth/v1/beacon/states/head/finality_checkpoints": `{"data":{"finalized":{"epoch":"4","root":"0x01"}}}`,
+	}}
+	ethService := service.NewEthereumServiceWithClients(beacon, execution)
+
+	reward, err := ethService.GetBlockRewardBySlot(context.Background(), 100)
+	if err != nil {
+		t.Fatalf("GetBlockRewardBySlot() unexpected error: %v", err)
+	}
+	if !reward.Finalized {
+		t.Error("GetBlockRewardBySlot() finalized = false, want true before the finalized checkpoint")
+	}
+
+	// The finalized reward is fetched upstream only once
+	execution.blocks = nil
+	cached, err := ethService.GetBlockRewardBySlot(context.Background(), 100)
+	if err != nil {
+		t.Fatalf("GetBlockRewardBySlot() unexpected error for finalized slot: %v", err)
+	}
+	if cached != reward {
+		t.Error("GetBlockRewardBySlot() did not serve the cached finalized reward")
+	}
+}
+
+func TestEthereumService_CacheStatsAndInvalidation(t *testing.T) {
+	header := &types.Header{Number: big.NewInt(100), BaseFee: big.NewInt(10)}
+	execution := &mockExecutionClient{blocks: []*types.Block{types.NewBlockWithHeader(header)}}
+	beacon := &mockBeaconClient{responses: map[string]string{
+		"/eth/v1/beacon/blocks/100/root": `{"data":{"root":"0x01"}}`,
+		"/eth/v2/beacon/blocks/100":      beaconBlockWithPayload(100),
+	}}
+	ethService := service.NewEthereumServiceWithClients(beacon, execution)
+
+	for i := 0; i < 2; i++ {
+		if _, err := ethService.GetBlockRewardBySlot(context.Background(), 100); err != nil {
+			t.Fatalf("GetBlockRewardBySlot() unexpected error: %v", err)
+		}
+	}
+
+	stats := ethService.CacheStats()
+	if stats.Entries != 1 || stats.Hits == 0 || stats.MemoryBytes == 0 {
+		t.Errorf("CacheStats() = %+v, want 1 entry with hits and memory", stats)
+	}
+
+	if removed := ethService.InvalidateSlot(100); removed != 1 {
+		t.Errorf("InvalidateSlot() removed = %d, want 1", removed)
+	}
+
+	// The invalidated reward has to be fetched upstream again
+	execution.blocks = nil
+	if _, err := ethService.GetBlockRewardBySlot(context.Background(), 100); err == nil {
+		t.Error("GetBlockRewardBySlot() served an invalidated reward")
+	}
+}
+
+func TestEthereumService_SyncCommitteePrefetchTask(t *testing.T) {
+	beacon := &mockBeaconClient{responses: map[string]string{
+		"/eth/v1/beacon/headers/head":                          `{"data":{"root":"0xabc","header":{"message":{"slot":"8200"}}}}`,
+		"/eth/v1/beacon/states/head/finality_checkpoints":      `{"data":{"finalized":{"epoch":"254","root":"0x01"}}}`,
+		"/eth/v1/beacon/states/head/sync_committees?epoch=256": `{"data":{"validators":["1","2"]}}`,
+		"/eth/v1/beacon/states/head/sync_committees?epoch=512": `{"data":{"validators":["3"]}}`,
+		"/eth/v1/beacon/states/head/validators?id=1,2":         `{"data":[{"index":"1","validator":{"pubkey":"0x01"}},{"index":"2","validator":{"pubkey":"0x02"}}]}`,
+		"/eth/v1/beacon/states/head/validators?id=3":           `{"data":[{"index":"3","validator":{"pubkey":"0x03"}}]}`,
+	}}
+	ethService := service.NewEthereumServiceWithClients(beacon, &mockExecutionClient{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	scheduler := service.NewScheduler()
+	scheduler.Register(ethService.SyncCommitteePrefetchTask())
+	go scheduler.Run(ctx)
+
+	// Wait for the first prefetch, the mock has no per-slot state to fall back to
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, err := ethService.GetSyncCommitteeBySlot(context.Background(), 16400); err == nil || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Slot lookups of both periods are served from memory, the mock pubkeys aren't BLS keys so there's no aggregate
+	tests := []struct {
+		slot       int64
+		wantPeriod int64
+		wantFirst  string
+		wantAgg    string
+	}{
+		{slot: 8300, wantPeriod: 1, wantFirst: "0x01"},
+		{slot: 16400, wantPeriod: 2, wantFirst: "0x03"},
+	}
+	for _, tt := range tests {
+		committee, err := ethService.GetSyncCommitteeBySlot(context.Background(), tt.slot)
+		if err != nil {
+			t.Fatalf("GetSyncCommitteeBySlot(%d) unexpected error: %v", tt.slot, err)
+		}
+		if committee.SyncPeriod != tt.wantPeriod || committee.Members[0].Pubkey != tt.wantFirst || committee.AggregatePubkey != tt.wantAgg {
+			t.Errorf("GetSyncCommitteeBySlot(%d) = period %d, first member %s, aggregate %q, want %d, %s, %q",
+				tt.slot, committee.SyncPeriod, committee.Members[0].Pubkey, committee.AggregatePubkey, tt.wantPeriod, tt.wantFirst, tt.wantAgg)
+		}
+	}
+}
+
+func TestHTTPBeaconClient_RequestBudgetExceeded(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	client := service.NewHTTPBeaconClient(server.URL, server.Client())
+	var out json.RawMessage
+	if err := client.GetJSON(ctx, "/eth/v1/beacon/genesis", &out); !errors.Is(err, service.ErrUpstreamTimeout) {
+		t.Errorf("GetJSON() error = %v, want %v", err, service.ErrUpstreamTimeout)
+	}
+}
+
+func TestEthereumService_GetValidatorProposals(t *testing.T) {
+	duties := func(slots ...int) string {
+		entries := make([]string, 0, len(slots))
+		for _, slot := range slots {
+			entries = append(entries, fmt.Sprintf(`{"validator_index":"7","slot":"%d"}`, slot))
+		}
+		return `{"data":[` + strings.Join(entries, ",") + `,{"validator_index":"8","slot":"1"}]}`
+	}
+	beacon := &mockBeaconClient{responses: map[string]string{
+		"/eth/v1/beacon/headers/head":                     `{"data":{"root":"0xhead","header":{"message":{"slot":"100"}}}}`,
+		"/eth/v1/beacon/states/head/finality_checkpoints": `{"data":{"finalized":{"epoch":"1","root":"0x01"}}}`,
+		"/eth/v1/validator/duties/proposer/0":             duties(5),
+		"/eth/v1/validator/duties/proposer/1":             duties(40),
+		"/eth/v1/validator/duties/proposer/2":             duties(70),
+		"/eth/v1/validator/duties/proposer/3":             duties(110),
+		"/eth/v1/beacon/blocks/5/root":                    `{"data":{"root":"0x05"}}`,
+	}}
+	ethService := service.NewEthereumServiceWithClients(beacon, &mockExecutionClient{})
+
+	// The indexer saw a block at slot 70 that was reorged out since, and the reward of slot 5
+	ethService.Index().Put(&service.BlockDetail{Slot: 70, ProposerIndex: 7, BlockRoot: "0x70"})
+	ethService.Index().PutReward(5, &service.BlockReward{Status: "vanilla", Reward: big.NewInt(1000)})
+
+	from, to := int64(0), int64(3)
+	proposals, err := ethService.GetValidatorProposals(context.Background(), "7", &from, &to)
+	if err != nil {
+		t.Fatalf("GetValidatorProposals() error = %v", err)
+	}
+
+	want := []struct {
+		slot    int64
+		outcome string
+		root    string
+	}{
+		{5, service.ProposalProposed, "0x05"},
+		{40, service.ProposalMissed, ""},
+		{70, service.ProposalOrphaned, "0x70"},
+	}
+	if len(proposals.Proposals) != len(want) {
+		t.Fatalf("Expected %d proposals, got %+v", len(want), proposals.Proposals)
+	}
+	for i, w := range want {
+		got := proposals.Proposals[i]
+		if got.Slot != w.slot || got.Outcome != w.outcome || got.BlockRoot != w.root {
+			t.Errorf("Proposal %d = %+v, want slot %d %s %s", i, got, w.slot, w.outcome, w.root)
+		}
+	}
+	if reward := proposals.Proposals[0].Reward; reward == nil || reward.Reward.Int64() != 1000 {
+		t.Errorf("Expected the indexed reward for slot 5, got %+v", reward)
+	}
+
+	// Epochs past the head and reversed ranges are rejected
+	future := int64(10)
+	if _, err := ethService.GetValidatorProposals(context.Background(), "7", nil, &future); !errors.Is(err, service.ErrFutureSlot) {
+		t.Errorf("Expected ErrFutureSlot, got %v", err)
+	}
+	from, to = 3, 2
+	if _, err := ethService.GetValidatorProposals(context.Background(), "7", &from, &to); !errors.Is(err, service.ErrInvalidRange) {
+		t.Errorf("Expected ErrInvalidRange, got %v", err)
+	}
+}
+
+func TestEthereumService_GetValidatorProposalsPage(t *testing.T) {
+	duties := func(slots ...int) string {
+		entries := make([]string, 0, len(slots))
+		for _, slot := range slots {
+			entries = append(entries, fmt.Sprintf(`{"validator_index":"7","slot":"%d"}`, slot))
+		}
+		return `{"data":[` + strings.Join(entries, ",") + `]}`
+	}
+	beacon := &mockBeaconClient{responses: map[string]string{
+		"/eth/v1/beacon/headers/head":                     `{"data":{"root":"0xhead","header":{"message":{"slot":"100"}}}}`,
+		"/eth/v1/beacon/states/head/finality_checkpoints": `{"data":{"finalized":{"epoch":"1","root":"0x01"}}}`,
+		"/eth/v1/validator/duties/proposer/0":             duties(5),
+		"/eth/v1/validator/duties/proposer/1":             duties(40),
+		"/eth/v1/validator/duties/proposer/2":             duties(70),
+		"/eth/v1/validator/duties/proposer/3":             duties(110),
+	}}
+	ethService := service.NewEthereumServiceWithClients(beacon, &mockExecutionClient{})
+
+	slots := func(page *service.ValidatorProposalsPage) []int64 {
+		var slots []int64
+		for _, proposal := range page.Proposals {
+			slots = append(slots, proposal.Slot)
+		}
+		return slots
+	}
+
+	from := int64(0)
+	page, err := ethService.GetValidatorProposalsPage(context.Background(), "7", nil, &from, nil, 2)
+	if err != nil {
+		t.Fatalf("GetValidatorProposalsPage() error = %v", err)
+	}
+	if got := slots(page); !reflect.DeepEqual(got, []int64{5, 40}) || page.NextCursor != 40 || !page.HasMore {
+		t.Fatalf("First page = %v, cursor %d, more %v, want [5 40], cursor 40 and more", got, page.NextCursor, page.HasMore)
+	}
+
+	// The last page ends at the head, slot 110 isn't reached yet
+	cursor := page.NextCursor
+	page, err = ethService.GetValidatorProposalsPage(context.Background(), "7", &cursor, nil, nil, 2)
+	if err != nil {
+		t.Fatalf("GetValidatorProposalsPage() error = %v", err)
+	}
+	if got := slots(page); !reflect.DeepEqual(got, []int64{70}) || page.NextCursor != 100 || page.HasMore || page.FromEpoch != 1 {
+		t.Fatalf("Last page = %v, cursor %d, more %v, from epoch %d, want [70], cursor 100, no more, from epoch 1", got, page.NextCursor, page.HasMore, page.FromEpoch)
+	}
+
+	// Once the chain advanced, the pull resumes where it stopped
+	beacon.responses["/eth/v1/beacon/headers/head"] = `{"data":{"root":"0xhead","header":{"message":{"slot":"140"}}}}`
+	beacon.responses["/eth/v1/validator/duties/proposer/4"] = duties(130)
+	ethService.ClearCache()
+	cursor = page.NextCursor
+	page, err = ethService.GetValidatorProposalsPage(context.Background(), "7", &cursor, nil, nil, 2)
+	if err != nil {
+		t.Fatalf("GetValidatorProposalsPage() error = %v", err)
+	}
+	if got := slots(page); !reflect.DeepEqual(got, []int64{110, 130}) {
+		t.Errorf("Resumed page = %v, want [110 130]", got)
+	}
+
+	// A pull up to to_epoch ends at its last slot, not at the head
+	cursor, to := int64(5), int64(1)
+	page, err = ethService.GetValidatorProposalsPage(context.Background(), "7", &cursor, nil, &to, 2)
+	if err != nil {
+		t.Fatalf("GetValidatorProposalsPage() error = %v", err)
+	}
+	if got := slots(page); !reflect.DeepEqual(got, []int64{40}) || page.NextCursor != 63 || page.HasMore {
+		t.Errorf("Page up to epoch 1 = %v, cursor %d, more %v, want [40], cursor 63 and no more", got, page.NextCursor, page.HasMore)
+	}
+
+	if _, err := ethService.GetValidatorProposalsPage(context.Background(), "7", nil, nil, nil, service.MaxProposalsPerPage+1); !errors.Is(err, service.ErrInvalidRange) {
+		t.Errorf("Expected ErrInvalidRange for a too large limit, got %v", err)
+	}
+
+	// The handler returns the cursor of the next page
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/validator/:id/proposals", handler.NewHandler(ethService).GetValidatorProposals)
+	get := func(target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		return w
+	}
+
+	w := get("/validator/7/proposals?from_epoch=0&limit=2")
+	var response v1.ValidatorProposalsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil || w.Code != http.StatusOK {
+		t.Fatalf("GET with a limit = %d %s", w.Code, w.Body.String())
+	}
+	if len(response.Proposals) != 2 || response.NextCursor != "40" || response.HasMore == nil || !*response.HasMore {
+		t.Errorf("GET with a limit = %s, want 2 proposals and the cursor 40", w.Body.String())
+	}
+	if w := get("/validator/7/proposals?cursor=abc"); w.Code != http.StatusBadRequest {
+		t.Errorf("GET with an invalid cursor = %d, want 400", w.Code)
+	}
+	if w := get("/validator/7/proposals?from_epoch=0&to_epoch=1"); strings.Contains(w.Body.String(), "next_cursor") {
+		t.Errorf("GET without a cursor or limit = %s, want no cursor", w.Body.String())
+	}
+}
+
+func TestEthereumService_CheckFeeRecipient(t *testing.T) {
+	const expected = "0x388c818ca8b9251b393131c08a736a67ccb19297"
+	beacon := &mockBeaconClient{responses: map[string]string{
+		"/eth/v1/beacon/headers/head":                     `{"data":{"root":"0xhead","header":{"message":{"slot":"100"}}}}`,
+		"/eth/v1/beacon/states/head/finality_checkpoints": `{"data":{"finalized":{"epoch":"1","root":"0x01"}}}`,
+		"/eth/v1/validator/duties/proposer/0":             `{"data":[{"validator_index":"7","slot":"5"},{"validator_index":"7","slot":"6"}]}`,
+		"/eth/v1/validator/duties/proposer/1":             `{"data":[{"validator_index":"7","slot":"40"},{"validator_index":"7","slot":"41"}]}`,
+		"/eth/v1/beacon/blocks/5/root":                    `{"data":{"root":"0x05"}}`,
+		"/eth/v1/beacon/blocks/6/root":                    `{"data":{"root":"0x06"}}`,
+		"/eth/v1/beacon/blocks/40/root":                   `{"data":{"root":"0x40"}}`,
+		"/eth/v1/beacon/blocks/41/root":                   `{"data":{"root":"0x41"}}`,
+	}}
+	ethService := service.NewEthereumServiceWithClients(beacon, &mockExecutionClient{})
+
+	// The relay reports the proposer fee recipient of the MEV blocks at slots 40 and 41
+	relay := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("slot") {
+		case "40":
+			w.Write([]byte(`[{"slot":"40","proposer_fee_recipient":"0x388C818CA8B9251b393131C08a736A67ccb19297"}]`))
+		case "41":
+			w.Write([]byte(`[{"slot":"41","proposer_fee_recipient":"0x000000000000000000000000000000000000dead"}]`))
+		default:
+			w.Write([]byte(`[]`))
+		}
+	}))
+	defer relay.Close()
+	if err := ethService.SetRelays([]string{relay.URL}); err != nil {
+		t.Fatalf("SetRelays() error = %v", err)
+	}
+
+	ethService.Index().PutReward(5, &service.BlockReward{Status: "vanilla", Reward: big.NewInt(1000), FeeRecipient: expected})
+	ethService.Index().PutReward(6, &service.BlockReward{Status: "vanilla", Reward: big.NewInt(1000), FeeRecipient: "0x000000000000000000000000000000000000beef"})
+	ethService.Index().PutReward(40, &service.BlockReward{Status: "mev", Reward: big.NewInt(1000), FeeRecipient: "0xbuilder", Relay: relay.URL})
+	ethService.Index().PutReward(41, &service.BlockReward{Status: "mev", Reward: big.NewInt(1000), FeeRecipient: "0xbuilder", Relay: relay.URL})
+
+	from, to := int64(0), int64(1)
+	check, err := ethService.CheckFeeRecipient(context.Background(), "7", "0x388C818CA8B9251b393131C08a736A67ccb19297", &from, &to)
+	if err != nil {
+		t.Fatalf("CheckFeeRecipient() error = %v", err)
+	}
+
+	wantResults := map[int64]string{
+		5:  service.FeeRecipientMatch,
+		6:  service.FeeRecipientMismatch,
+		40: service.FeeRecipientMatch,
+		41: service.FeeRecipientMismatch,
+	}
+	if len(check.Proposals) != len(wantResults) {
+		t.Fatalf("Expected %d checked proposals, got %+v", len(wantResults), check.Proposals)
+	}
+	for _, proposal := range check.Proposals {
+		if proposal.Result != wantResults[proposal.Slot] {
+			t.Errorf("Slot %d: result = %s, want %s", proposal.Slot, proposal.Result, wantResults[proposal.Slot])
+		}
+	}
+	if check.Matches != 2 || check.Mismatches != 2 {
+		t.Errorf("Expected 2 matches and 2 mismatches, got %d and %d", check.Matches, check.Mismatches)
+	}
+
+	if _, err := ethService.CheckFeeRecipient(context.Background(), "7", "not-an-address", &from, &to); !errors.Is(err, service.ErrInvalidFeeRecipient) {
+		t.Errorf("Expected ErrInvalidFeeRecipient, got %v", err)
+	}
+}
+
+func TestEthereumService_GetValidatorsSummary(t *testing.T) {
+	pubkey := "0x" + strings.Repeat("ab", 48)
+	rewards := `{"data":{"total_rewards":[` +
+		`{"validator_index":"7","head":"10","target":"20","source":"10"},` +
+		`{"validator_index":"8","head":"0","target":"20","source":"10"}]}}`
+	beacon := &mockBeaconClient{responses: map[string]string{
+		"/eth/v1/beacon/headers/head": `{"data":{"root":"0xhead","header":{"message":{"slot":"100"}}}}`,
+		"/eth/v1/beacon/genesis":      `{"data":{"genesis_time":"1606824023"}}`,
+		"/eth/v1/beacon/states/head/validators": `{"data":[` +
+			`{"index":"7","balance":"32000000001","status":"active_ongoing","validator":{"pubkey":"0x07","effective_balance":"32000000000"}},` +
+			`{"index":"8","balance":"31000000000","status":"active_ongoing","validator":{"pubkey":"` + pubkey + `","effective_balance":"31000000000"}}]}`,
+		"/eth/v1/validator/duties/proposer/3":                `{"data":[{"validator_index":"7","slot":"99"},{"validator_index":"7","slot":"101"}]}`,
+		"/eth/v1/validator/duties/proposer/4":                `{"data":[{"validator_index":"9","slot":"130"}]}`,
+		"/eth/v1/beacon/states/head/sync_committees?epoch=0": `{"data":{"validators":["8","8","10"]}}`,
+		"/eth/v1/beacon/rewards/attestations/0":              rewards,
+		"/eth/v1/beacon/rewards/attestations/1":              rewards,
+	}}
+	ethService := service.NewEthereumServiceWithClients(beacon, &mockExecutionClient{})
+
+	summary, err := ethService.GetValidatorsSummary(context.Background(), []string{"7", pubkey, "7", "12"})
+	if err != nil {
+		t.Fatalf("GetValidatorsSummary() error = %v", err)
+	}
+
+	if summary.HeadSlot != 100 || summary.FromEpoch != 0 || summary.ToEpoch != 1 {
+		t.Errorf("Expected head slot 100 and attestation epochs 0-1, got %d and %d-%d", summary.HeadSlot, summary.FromEpoch, summary.ToEpoch)
+	}
+	if len(summary.Validators) != 3 {
+		t.Fatalf("Expected 3 summaries without the duplicate, got %+v", summary.Validators)
+	}
+
+	first := summary.Validators[0]
+	if first.Status == nil || first.Status.Balance != 32000000001 {
+		t.Errorf("Expected the status of validator 7, got %+v", first.Status)
+	}
+	if len(first.Duties) != 1 || first.Duties[0].Kind != service.DutyProposal || first.Duties[0].StartSlot != 101 {
+		t.Errorf("Expected only the upcoming proposal at slot 101, got %+v", first.Duties)
+	}
+	if first.Attestations.Duties != 2 || first.Attestations.Fulfilled != 2 || first.Attestations.Rate != 1 {
+		t.Errorf("Expected 2 perfect attestations, got %+v", first.Attestations)
+	}
+
+	second := summary.Validators[1]
+	if second.ID != pubkey || second.Status == nil || second.Status.Index != 8 {
+		t.Errorf("Expected the pubkey to resolve to validator 8, got %+v", second)
+	}
+	if len(second.Duties) != 1 || second.Duties[0].Kind != service.DutySyncCommittee {
+		t.Errorf("Expected a single sync committee window, got %+v", second.Duties)
+	}
+	if rate := second.Attestations.Rate; rate < 0.66 || rate > 0.67 {
+		t.Errorf("Expected two out of three correct votes, got %v", rate)
+	}
+
+	if missing := summary.Validators[2]; missing.ID != "12" || missing.Status != nil || len(missing.Duties) != 0 {
+		t.Errorf("Expected validator 12 to be reported as not found, got %+v", missing)
+	}
+
+	if _, err := ethService.GetValidatorsSummary(context.Background(), nil); !errors.Is(err, service.ErrInvalidValidatorList) {
+		t.Errorf("Expected ErrInvalidValidatorList for an empty list, got %v", err)
+	}
+	if _, err := ethService.GetValidatorsSummary(context.Background(), []string{"7", "0x1234"}); !errors.Is(err, service.ErrInvalidValidatorID) {
+		t.Errorf("Expected ErrInvalidValidatorID for a malformed pubkey, got %v", err)
+	}
+}
+
+func TestEthereumService_GetValidatorLatestBlockReward(t *testing.T) {
+	header := &types.Header{Number: big.NewInt(97), BaseFee: big.NewInt(10)}
+	execution := &mockExecutionClient{blocks: []*types.Block{types.NewBlockWithHeader(header)}}
+
+	// Slot 98 was missed, the proposers of the other slots are only known from their headers
+	beacon := &mockBeaconClient{responses: map[string]string{
+		"/eth/v1/beacon/headers/head":   `{"data":{"root":"0x100","header":{"message":{"slot":"100","proposer_index":"5","parent_root":"0x99"}}}}`,
+		"/eth/v1/beacon/headers/0x99":   `{"data":{"root":"0x99","header":{"message":{"slot":"99","proposer_index":"9","parent_root":"0x97"}}}}`,
+		"/eth/v1/beacon/headers/0x97":   `{"data":{"root":"0x97","header":{"message":{"slot":"97","proposer_index":"42","parent_root":"0x96"}}}}`,
+		"/eth/v1/beacon/blocks/97/root": `{"data":{"root":"0x97"}}`,
+		"/eth/v2/beacon/blocks/97":      beaconBlockWithPayload(97),
+	}}
+	ethService := service.NewEthereumServiceWithClients(beacon, execution)
+
+	reward, err := ethService.GetValidatorLatestBlockReward(context.Background(), "42")
+	if err != nil {
+		t.Fatalf("GetValidatorLatestBlockReward() unexpected error: %v", err)
+	}
+	if reward.Slot != 97 || reward.ValidatorIndex != 42 {
+		t.Errorf("GetValidatorLatestBlockReward() = slot %d of validator %d, want slot 97 of validator 42", reward.Slot, reward.ValidatorIndex)
+	}
+	// The block has no transactions, so it paid no fees
+	if reward.Wei().Sign() != 0 || reward.Reward.Sign() != 0 {
+		t.Errorf("GetValidatorLatestBlockReward() reward = %s Wei (%s GWEI), want 0", reward.Wei(), reward.Reward)
+	}
+
+	// The walk ends at the oldest block the node serves
+	if _, err := ethService.GetValidatorLatestBlockReward(context.Background(), "77"); !errors.Is(err, service.ErrNoRecentProposal) {
+		t.Errorf("GetValidatorLatestBlockReward() error = %v, want %v", err, service.ErrNoRecentProposal)
+	}
+}
+
+func TestEthereumService_GetValidatorLatestBlockReward_Index(t *testing.T) {
+	header := &types.Header{Number: big.NewInt(55), BaseFee: big.NewInt(10)}
+	execution := &mockExecutionClient{blocks: []*types.Block{types.NewBlockWithHeader(header)}}
+
+	// Headers are only served below the indexed blocks
+	beacon := &mockBeaconClient{responses: map[string]string{
+		"/eth/v1/beacon/headers/head":   `{"data":{"root":"0x100","header":{"message":{"slot":"100","proposer_index":"5","parent_root":"0x99"}}}}`,
+		"/eth/v1/beacon/blocks/55/root": `{"data":{"root":"0x55"}}`,
+		"/eth/v2/beacon/blocks/55":      beaconBlockWithPayload(55),
+	}}
+	for slot := 1; slot < 50; slot++ {
+		beacon.responses[fmt.Sprintf("/eth/v1/beacon/headers/0x%d", slot)] = fmt.Sprintf(`{"data":{"root":"0x%d","header":{"message":{"slot":"%d","proposer_index":"%d","parent_root":"0x%d"}}}}`, slot, slot, 1000+slot, slot-1)
+	}
+	ethService := service.NewEthereumServiceWithClients(beacon, execution)
+
+	// The index has the blocks of slots 50 to 99, slots 60 to 69 were missed
+	for slot := int64(50); slot < 100; slot++ {
+		if slot >= 60 && slot < 70 {
+			continue
+		}
+		parent := slot - 1
+		if slot == 70 {
+			parent = 59
+		}
+		ethService.Index().Put(&service.BlockDetail{Slot: slot, ProposerIndex: 2000 + slot, BlockRoot: fmt.Sprintf("0x%d", slot), ParentRoot: fmt.Sprintf("0x%d", parent),
+			ExecutionBlockNumber: slot, ExecutionBlockHash: fmt.Sprintf("0x%064x", slot)})
+	}
+
+	tests := []struct {
+		name      string
+		validator string
+		wantSlot  int64
+		wantErr   error
+	}{
+		{
+			name:      "Proposal found in the index",
+			validator: "2055",
+			wantSlot:  55,
+		},
+		{
+			name:      "Gap of the index runs out of header lookups",
+			validator: "1040",
+			wantErr:   service.ErrProposalLookupIncomplete,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reward, err := ethService.GetValidatorLatestBlockReward(context.Background(), tt.validator)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("GetValidatorLatestBlockReward() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetValidatorLatestBlockReward() unexpected error: %v", err)
+			}
+			if reward.Slot != tt.wantSlot {
+				t.Errorf("GetValidatorLatestBlockReward() slot = %d, want %d", reward.Slot, tt.wantSlot)
+			}
+		})
+	}
+}
@@ -0,0 +1,300 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"ethereum-validator-api/handler"
+	"ethereum-validator-api/service"
+	"github.com/gin-gonic/gin"
+)
+
+// mockClock is a service.Clock that only moves when advanced
+type mockClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []mockWaiter
+}
+
+type mockWaiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+func newMockClock(now time.Time) *mockClock {
+	return &mockClock{now: now}
+}
+
+func (c *mockClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *mockClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.waiters = append(c.waiters, mockWaiter{at: c.now.Add(d), ch: ch})
+	return ch
+}
+
+// Advance moves the clock forward and fires the waiters that are due
+func (c *mockClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	pending := c.waiters[:0]
+	for _, waiter := range c.waiters {
+		if waiter.at.After(c.now) {
+			pending = append(pending, waiter)
+			continue
+		}
+		waiter.ch <- c.now
+	}
+	c.waiters = pending
+}
+
+// waitForWaiters blocks until n goroutines are waiting on the clock
+func (c *mockClock) waitForWaiters(t *testing.T, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		c.mu.Lock()
+		waiting := len(c.waiters)
+		c.mu.Unlock()
+		if waiting >= n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %d waiters on the clock, got %d", n, waiting)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestClock_FutureSlot(t *testing.T) {
+	clock := newMockClock(time.Unix(12*1000, 0))
+	ethService := service.NewEthereumServiceWithClients(&mockBeaconClient{}, &mockExecutionClient{})
+	ethService.SetClock(clock)
+
+	if _, err := ethService.GetBlockRewardBySlot(context.Background(), 1001); !errors.Is(err, service.ErrFutureSlot) {
+		t.Fatalf("GetBlockRewardBySlot(1001) error = %v, want ErrFutureSlot", err)
+	}
+
+	// Once the slot starts it is no longer rejected as a future slot
+	clock.Advance(12 * time.Second)
+	if _, err := ethService.GetBlockRewardBySlot(context.Background(), 1001); errors.Is(err, service.ErrFutureSlot) {
+		t.Errorf("GetBlockRewardBySlot(1001) still rejected as a future slot after it started")
+	}
+}
+
+func TestClock_CacheTTL(t *testing.T) {
+	beacon := &mockBeaconClient{responses: map[string]string{
+		"/eth/v1/beacon/headers/head":                     `{"data":{"root":"0xabc","header":{"message":{"slot":"8192"}}}}`,
+		"/eth/v1/beacon/states/head/finality_checkpoints": `{"data":{}}`,
+	}}
+	clock := newMockClock(time.Now())
+	ethService := service.NewEthereumServiceWithClients(beacon, &mockExecutionClient{})
+	ethService.SetClock(clock)
+
+	if _, err := ethService.GetChainHead(context.Background()); err != nil {
+		t.Fatalf("GetChainHead() unexpected error: %v", err)
+	}
+	beacon.responses["/eth/v1/beacon/headers/head"] = `{"data":{"root":"0xdef","header":{"message":{"slot":"8193"}}}}`
+
+	head, err := ethService.GetChainHead(context.Background())
+	if err != nil || head.Slot != 8192 {
+		t.Fatalf("GetChainHead() = %+v, %v, want the cached slot 8192", head, err)
+	}
+
+	clock.Advance(5 * time.Second)
+	if stats := ethService.CacheStats(); stats.Expired != 1 {
+		t.Errorf("CacheStats() expired = %d, want 1", stats.Expired)
+	}
+	head, err = ethService.GetChainHead(context.Background())
+	if err != nil || head.Slot != 8193 {
+		t.Errorf("GetChainHead() = %+v, %v, want the refreshed slot 8193", head, err)
+	}
+}
+
+func TestClock_CacheSweepAndEviction(t *testing.T) {
+	beacon := &mockBeaconClient{responses: map[string]string{
+		"/eth/v1/beacon/headers/head":                     `{"data":{"root":"0xabc","header":{"message":{"slot":"8192"}}}}`,
+		"/eth/v1/beacon/states/head/finality_checkpoints": `{"data":{}}`,
+		"/eth/v1/config/spec":                             `{"data":{"SECONDS_PER_SLOT":"12"}}`,
+		"/eth/v1/config/fork_schedule":                    `{"data":[]}`,
+	}}
+	clock := newMockClock(time.Now())
+	ethService := service.NewEthereumServiceWithClients(beacon, &mockExecutionClient{})
+	ethService.SetClock(clock)
+	if err := ethService.SetCacheLimits(2, 1<<20); err != nil {
+		t.Fatalf("SetCacheLimits() unexpected error: %v", err)
+	}
+	sweep := ethService.CacheSweepTask()
+
+	if _, err := ethService.GetChainHead(context.Background()); err != nil {
+		t.Fatalf("GetChainHead() unexpected error: %v", err)
+	}
+	if _, err := ethService.GetChainSpec(context.Background()); err != nil {
+		t.Fatalf("GetChainSpec() unexpected error: %v", err)
+	}
+
+	// Expired entries are kept for a while to be served stale
+	clock.Advance(5 * time.Second)
+	if err := sweep.Run(context.Background()); err != nil {
+		t.Fatalf("Sweep unexpected error: %v", err)
+	}
+	if stats := ethService.CacheStats(); stats.Entries != 2 || stats.Expired != 1 {
+		t.Errorf("CacheStats() = %+v, want 2 entries with 1 expired", stats)
+	}
+
+	// The head expired more than an hour ago, the spec just now
+	clock.Advance(time.Hour)
+	if err := sweep.Run(context.Background()); err != nil {
+		t.Fatalf("Sweep unexpected error: %v", err)
+	}
+	if stats := ethService.CacheStats(); stats.Entries != 1 {
+		t.Errorf("CacheStats() entries = %d after the sweep, want 1", stats.Entries)
+	}
+
+	// Beyond the entry limit the least recently used spec is evicted
+	if _, err := ethService.GetForkSchedule(context.Background()); err != nil {
+		t.Fatalf("GetForkSchedule() unexpected error: %v", err)
+	}
+	if _, err := ethService.GetChainHead(context.Background()); err != nil {
+		t.Fatalf("GetChainHead() unexpected error: %v", err)
+	}
+	if stats := ethService.CacheStats(); stats.Entries != 2 || stats.Evictions != 1 {
+		t.Errorf("CacheStats() = %+v, want 2 entries after 1 eviction", stats)
+	}
+	beacon.responses["/eth/v1/config/spec"] = `{"data":{"SECONDS_PER_SLOT":"6"}}`
+	spec, err := ethService.GetChainSpec(context.Background())
+	if err != nil || spec["SECONDS_PER_SLOT"] != "6" {
+		t.Errorf("GetChainSpec() = %v, %v, want the spec fetched again after its eviction", spec, err)
+	}
+
+	if err := ethService.SetCacheLimits(0, 1); err == nil {
+		t.Error("SetCacheLimits() accepted a limit of 0 entries")
+	}
+}
+
+func TestClock_UsageAndAudit(t *testing.T) {
+	clock := newMockClock(time.Date(2024, 1, 1, 23, 59, 30, 0, time.UTC))
+	ethService := service.NewEthereumServiceWithClients(&mockBeaconClient{}, &mockExecutionClient{})
+	ethService.SetClock(clock)
+	ethService.SetAudit(service.NewAuditLog())
+	h := handler.NewHandler(ethService)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(h.TrackUsage())
+	router.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	// Requests are counted for the day of the service clock
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ping", nil))
+	clock.Advance(time.Minute)
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ping", nil))
+	for _, day := range []time.Time{clock.Now().AddDate(0, 0, -1), clock.Now()} {
+		if records := ethService.Usage().Query(service.UsageFilter{From: day, To: day}); len(records) != 1 || records[0].Requests != 1 {
+			t.Errorf("Query() for %s = %+v, want 1 request", day.Format(time.DateOnly), records)
+		}
+	}
+
+	if err := ethService.Audit().Record("signal:SIGHUP", service.AuditConfigReload, "", nil, nil); err != nil {
+		t.Fatalf("Record() unexpected error: %v", err)
+	}
+	if entries := ethService.Audit().Query(service.AuditFilter{}); len(entries) != 1 || !entries[0].Time.Equal(clock.Now()) {
+		t.Errorf("Query() = %+v, want 1 entry stamped %s", entries, clock.Now())
+	}
+}
+
+func TestClock_Scheduler(t *testing.T) {
+	clock := newMockClock(time.Now())
+	var runs atomic.Int32
+
+	scheduler := service.NewScheduler()
+	scheduler.SetClock(clock)
+	scheduler.Register(service.Task{
+		Name:     "minutely",
+		Schedule: service.Every(time.Minute),
+		Run: func(ctx context.Context) error {
+			runs.Add(1)
+			return nil
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go scheduler.Run(ctx)
+
+	clock.waitForWaiters(t, 1)
+	clock.Advance(59 * time.Second)
+	if runs.Load() != 0 {
+		t.Fatalf("Expected no run before the interval elapsed, got %d", runs.Load())
+	}
+
+	clock.Advance(time.Second)
+	deadline := time.Now().Add(2 * time.Second)
+	for scheduler.Status()[0].Runs != 1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected one run after the interval elapsed, got %+v", scheduler.Status()[0])
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestClock_RetryAfterBackoff(t *testing.T) {
+	clock := newMockClock(time.Now())
+	service.SetUpstreamClock(clock)
+	defer service.SetUpstreamClock(service.SystemClock{})
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	service.SetUpstreamRateLimit(100, 10)
+	defer service.SetUpstreamRateLimit(1, 1)
+	client := service.NewHTTPBeaconClient(server.URL, server.Client())
+
+	done := make(chan error, 1)
+	go func() {
+		var out struct{}
+		done <- client.GetJSON(service.WithPriority(context.Background(), service.PriorityBatch), "/eth/v1/node/version", &out)
+	}()
+
+	// The batch retry waits out the 30s Retry-After on the clock instead of sleeping
+	clock.waitForWaiters(t, 1)
+	if requests.Load() != 1 {
+		t.Fatalf("Expected the retry to wait for the Retry-After, got %d requests", requests.Load())
+	}
+	clock.Advance(30 * time.Second)
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("GetJSON() unexpected error: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("GetJSON() did not retry after the clock advanced")
+	}
+	if requests.Load() != 2 {
+		t.Errorf("Expected the rate limited request to be retried once, got %d requests", requests.Load())
+	}
+}
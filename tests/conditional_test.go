@@ -0,0 +1,124 @@
+package tests
+
+import (
+	"context"
+	"ethereum-validator-api/service"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// countingExecutionClient counts the blocks fetched from the wrapped client
+type countingExecutionClient struct {
+	*mockExecutionClient
+	blockFetches atomic.Int32
+}
+
+func (c *countingExecutionClient) BlockWithReceipts(ctx context.Context, number *big.Int) (*types.Block, []*types.Receipt, error) {
+	c.blockFetches.Add(1)
+	return c.mockExecutionClient.BlockWithReceipts(ctx, number)
+}
+
+func TestHTTPBeaconClient_ConditionalRequests(t *testing.T) {
+	var mu sync.Mutex
+	var requests, notModified int
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests++
+		if r.Header.Get("If-None-Match") == `"root-1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"root-1"`)
+		w.Write([]byte(`{"data":{"root":"0x01"}}`))
+	}))
+	defer upstream.Close()
+
+	service.SetUpstreamRateLimit(100, 10)
+	defer service.SetUpstreamRateLimit(1, 1)
+	client := service.NewHTTPBeaconClient(upstream.URL, http.DefaultClient)
+
+	var root struct {
+		Data struct {
+			Root string `json:"root"`
+		} `json:"data"`
+	}
+
+	// Plain requests neither keep the response nor send validators
+	if err := client.GetJSON(context.Background(), "/eth/v1/beacon/blocks/100/root", &root); err != nil {
+		t.Fatalf("GetJSON() unexpected error: %v", err)
+	}
+	ctx := service.WithConditional(context.Background())
+	for i := 0; i < 3; i++ {
+		root.Data.Root = ""
+		if err := client.GetJSON(ctx, "/eth/v1/beacon/blocks/100/root", &root); err != nil {
+			t.Fatalf("Conditional GetJSON() unexpected error: %v", err)
+		}
+		if root.Data.Root != "0x01" {
+			t.Errorf("Conditional GetJSON() root = %q, want 0x01", root.Data.Root)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if requests != 4 || notModified != 2 {
+		t.Errorf("Upstream got %d requests, %d answered 304, want 4 requests with the last 2 answered 304", requests, notModified)
+	}
+}
+
+func TestEthereumService_NearHeadRefreshSkipsUnchangedRoot(t *testing.T) {
+	header := &types.Header{Number: big.NewInt(100), BaseFee: big.NewInt(10)}
+	block := types.NewBlockWithHeader(header)
+	execution := &countingExecutionClient{mockExecutionClient: &mockExecutionClient{blocks: []*types.Block{block}}}
+	beacon := &mockBeaconClient{responses: map[string]string{
+		"/eth/v1/beacon/blocks/100/root":                  `{"data":{"root":"0x01"}}`,
+		"/eth/v2/beacon/blocks/100":                       beaconBlockWithPayload(100),
+		"/eth/v1/beacon/headers/head":                     `{"data":{"root":"0xabc","header":{"message":{"slot":"128"}}}}`,
+		"/eth/v1/beacon/states/head/finality_checkpoints": `{"data":{"finalized":{"epoch":"2","root":"0x01"}}}`,
+	}}
+	clock := newMockClock(time.Now())
+	ethService := service.NewEthereumServiceWithClients(beacon, execution)
+	ethService.SetClock(clock)
+
+	// refresh expires the cached reward and waits for its background refresh
+	refresh := func() {
+		t.Helper()
+		clock.Advance(time.Duration(service.SlotsPerEpoch*service.SecondsPerSlot+1) * time.Second)
+		if _, err := ethService.GetBlockRewardBySlot(context.Background(), 100); err != nil {
+			t.Fatalf("GetBlockRewardBySlot() unexpected error: %v", err)
+		}
+		deadline := time.Now().Add(2 * time.Second)
+		for ethService.CacheStats().Expired != 0 {
+			if time.Now().After(deadline) {
+				t.Fatal("Expected the cached reward to be refreshed")
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	if _, err := ethService.GetBlockRewardBySlot(context.Background(), 100); err != nil {
+		t.Fatalf("GetBlockRewardBySlot() unexpected error: %v", err)
+	}
+	fetches := execution.blockFetches.Load()
+
+	// The block root didn't change, the cached reward is kept without fetching the block again
+	refresh()
+	if got := execution.blockFetches.Load(); got != fetches {
+		t.Errorf("Block fetched %d times after refreshing an unchanged root, want %d", got, fetches)
+	}
+
+	// A reorg replaced the block, the reward is fetched again
+	beacon.responses["/eth/v1/beacon/blocks/100/root"] = `{"data":{"root":"0x02"}}`
+	refresh()
+	if got := execution.blockFetches.Load(); got <= fetches {
+		t.Errorf("Block fetched %d times after the root changed, want more than %d", got, fetches)
+	}
+}
@@ -0,0 +1,88 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"ethereum-validator-api/handler"
+	"ethereum-validator-api/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestEthereumService_ConnectUpstreams(t *testing.T) {
+	// One endpoint serving both layers, failing until it is switched up
+	var up atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !up.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.Method == http.MethodGet {
+			w.Write([]byte(`{"data":{"version":"Lighthouse/v5.0.0"}}`))
+			return
+		}
+		var call struct {
+			ID json.RawMessage `json:"id"`
+		}
+		json.NewDecoder(r.Body).Decode(&call)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": call.ID, "result": "0x1"})
+	}))
+	defer server.Close()
+
+	ethService := service.NewDisconnectedEthereumService()
+	h := handler.NewHandler(ethService)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/chain/head", h.RequireUpstreams(), h.GetChainHead)
+
+	if ok, _ := ethService.UpstreamsAvailable(); ok {
+		t.Fatal("Expected a service without endpoints to be unavailable")
+	}
+	if _, err := ethService.GetChainHead(context.Background()); !errors.Is(err, service.ErrUpstreamUnavailable) {
+		t.Errorf("GetChainHead() error = %v, want ErrUpstreamUnavailable", err)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/chain/head", nil))
+	if w.Code != http.StatusServiceUnavailable || w.Header().Get("Retry-After") == "" {
+		t.Errorf("Expected status 503 with Retry-After before the upstreams were reached, got %d", w.Code)
+	}
+
+	// An empty URL can't be connected, the endpoints are retried once set
+	if err := ethService.ConnectUpstreams(context.Background(), service.UpstreamConfig{}); err == nil {
+		t.Error("ConnectUpstreams() expected error for an empty RPC URL")
+	}
+
+	config := service.UpstreamConfig{RPCURL: server.URL}
+	if err := ethService.ConnectUpstreams(context.Background(), config); err == nil {
+		t.Error("ConnectUpstreams() expected error while the provider is down")
+	}
+	reconnect := ethService.ReconnectTask(service.Every(0))
+	if err := reconnect.Run(context.Background()); err == nil {
+		t.Error("ReconnectTask() expected error while the provider is down")
+	}
+
+	up.Store(true)
+	if err := reconnect.Run(context.Background()); err != nil {
+		t.Fatalf("ReconnectTask() unexpected error once the provider is up: %v", err)
+	}
+	if ok, err := ethService.UpstreamsAvailable(); !ok {
+		t.Fatalf("Expected the upstreams to be available after reconnecting, got %v", err)
+	}
+
+	// Once reached, later outages are left to the lookups, which report the provider's 503 as not synced
+	up.Store(false)
+	if err := reconnect.Run(context.Background()); err != nil {
+		t.Errorf("ReconnectTask() error = %v once connected, want nothing to do", err)
+	}
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/chain/head", nil))
+	if strings.Contains(w.Body.String(), "Upstream providers are unavailable") {
+		t.Errorf("Expected the middleware to let requests through once connected, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,293 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"ethereum-validator-api/api/models/v1"
+	"flag"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+var updateContracts = flag.Bool("update", false, "Rewrite the golden files of the contract tests")
+
+// contractsDir holds the golden JSON of every model, one file per type
+const contractsDir = "testdata/contracts"
+
+// contractModels are the request and response models of the public API, every exported struct of api/models/v1 must be
+// listed so its JSON is pinned by a golden file
+var contractModels = []interface{}{
+	v1.APRComparisonResponse{},
+	v1.AccountResponse{},
+	v1.AlertRuleResponse{},
+	v1.APRSourceResponse{},
+	v1.APRSourcesResponse{},
+	v1.AttestationPerformanceResponse{},
+	v1.AttestationResponse{},
+	v1.AuditEntryResponse{},
+	v1.BaseFeePointResponse{},
+	v1.BaseFeeStatsResponse{},
+	v1.BeaconNodeResponse{},
+	v1.BeaconPeersResponse{},
+	v1.BeaconSyncingResponse{},
+	v1.BidComparisonResponse{},
+	v1.BlockDetailResponse{},
+	v1.BlockGasResponse{},
+	v1.BlockNumberConversionResponse{},
+	v1.BlockRewardJobRequest{},
+	v1.BlockRewardJobResponse{},
+	v1.BlockRewardJobResultResponse{},
+	v1.BlockRewardResponse{},
+	v1.CacheInvalidationResponse{},
+	v1.CacheStatsResponse{},
+	v1.ChainHeadResponse{},
+	v1.ChainSpecResponse{},
+	v1.CheckpointResponse{},
+	v1.CommitteeResponse{},
+	v1.CommitteesResponse{},
+	v1.DivergenceResponse{},
+	v1.DutyResponse{},
+	v1.EffectivenessComponentResponse{},
+	v1.EffectivenessResponse{},
+	v1.EnvelopeLinksResponse{},
+	v1.EnvelopeMetaResponse{},
+	v1.EnvelopeResponse{},
+	v1.EpochParticipationResponse{},
+	v1.ErrorResponse{},
+	v1.ExecutionBlockRewardResponse{},
+	v1.ExecutionPeersResponse{},
+	v1.ExecutionSyncingResponse{},
+	v1.FeeRecipientCheckResponse{},
+	v1.FeeRecipientProposalResponse{},
+	v1.FiatValueResponse{},
+	v1.ForecastNetworkResponse{},
+	v1.ForecastProposalsResponse{},
+	v1.ForecastResponse{},
+	v1.ForecastRewardsResponse{},
+	v1.ForecastSyncCommitteeResponse{},
+	v1.ForkResponse{},
+	v1.ForkScheduleResponse{},
+	v1.GrafanaColumnResponse{},
+	v1.GrafanaQueryRequest{},
+	v1.GrafanaSearchRequest{},
+	v1.GrafanaSeriesResponse{},
+	v1.GrafanaTableResponse{},
+	v1.GrafanaTargetQuery{},
+	v1.GraffitiMatch{},
+	v1.GraffitiSearchResponse{},
+	v1.MEVStatsResponse{},
+	v1.MetaResponse{},
+	v1.NetworkAPRResponse{},
+	v1.NetworkAPRSourcesResponse{},
+	v1.NetworkStatsResponse{},
+	v1.NextSyncCommitteeResponse{},
+	v1.NodePeersResponse{},
+	v1.NodeSyncingResponse{},
+	v1.NodeVersionResponse{},
+	v1.PaginationResponse{},
+	v1.PoolResponse{},
+	v1.ProblemResponse{},
+	v1.ProposerMismatchResponse{},
+	v1.QueueResponse{},
+	v1.RelayBidResponse{},
+	v1.RelaySummaryResponse{},
+	v1.ReloadResponse{},
+	v1.RewardStatsResponse{},
+	v1.ScheduledTaskResponse{},
+	v1.ShareResponse{},
+	v1.SyncCommitteeMemberResponse{},
+	v1.SyncDutiesResponse{},
+	v1.SyncPeriodProbabilityResponse{},
+	v1.SyncProbabilityResponse{},
+	v1.TopTransactionsResponse{},
+	v1.TransactionRewardResponse{},
+	v1.TransactionTypesResponse{},
+	v1.UpstreamBudgetResponse{},
+	v1.UpstreamBudgetsResponse{},
+	v1.UpstreamCaptureRequest{},
+	v1.UpstreamCaptureResponse{},
+	v1.UpstreamExchangeResponse{},
+	v1.UpstreamsHealthResponse{},
+	v1.UsageRecordResponse{},
+	v1.UsageResponse{},
+	v1.ValidatorAPRResponse{},
+	v1.ValidatorBlockRewardResponse{},
+	v1.ValidatorProposalResponse{},
+	v1.ValidatorProposalsResponse{},
+	v1.ValidatorStatusResponse{},
+	v1.ValidatorSummaryResponse{},
+	v1.ValidatorsSummaryRequest{},
+	v1.ValidatorsSummaryResponse{},
+	v1.VerificationResponse{},
+	v1.WatchlistChannelsRequest{},
+	v1.WatchlistResponse{},
+	v1.WatchlistRulesRequest{},
+	v1.WithdrawalsResponse{},
+}
+
+// TestResponseContracts renders every model with all its fields set and compares the JSON with its golden file, so
+// renamed, retyped, added or removed fields fail here. Run `make contract-update` to accept intended changes
+func TestResponseContracts(t *testing.T) {
+	for _, model := range contractModels {
+		modelType := reflect.TypeOf(model)
+		t.Run(modelType.Name(), func(t *testing.T) {
+			value := reflect.New(modelType).Elem()
+			fillContract(value, modelType.Name(), 0)
+			got, err := json.MarshalIndent(value.Interface(), "", "  ")
+			if err != nil {
+				t.Fatalf("json.MarshalIndent() error: %v", err)
+			}
+			got = append(got, '\n')
+
+			path := filepath.Join(contractsDir, modelType.Name()+".json")
+			if *updateContracts {
+				if err := os.MkdirAll(contractsDir, 0o755); err != nil {
+					t.Fatalf("Failed to create %s: %v", contractsDir, err)
+				}
+				if err := os.WriteFile(path, got, 0o644); err != nil {
+					t.Fatalf("Failed to write %s: %v", path, err)
+				}
+				return
+			}
+
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("No golden file for %s, create it with `make contract-update`: %v", modelType.Name(), err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("JSON contract of %s changed, run `make contract-update` if intended\n got: %s\nwant: %s", modelType.Name(), got, want)
+			}
+		})
+	}
+}
+
+// TestResponseContractsComplete fails when a model of api/models/v1 has no golden file or a golden file no model
+func TestResponseContractsComplete(t *testing.T) {
+	packages, err := parser.ParseDir(token.NewFileSet(), filepath.Join("..", "api", "models", "v1"), func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		t.Fatalf("Failed to parse api/models/v1: %v", err)
+	}
+
+	var declared []string
+	for _, pkg := range packages {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				gen, ok := decl.(*ast.GenDecl)
+				if !ok || gen.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range gen.Specs {
+					typeSpec := spec.(*ast.TypeSpec)
+					if _, isStruct := typeSpec.Type.(*ast.StructType); isStruct && typeSpec.Name.IsExported() {
+						declared = append(declared, typeSpec.Name.Name)
+					}
+				}
+			}
+		}
+	}
+
+	registered := make(map[string]bool)
+	for _, model := range contractModels {
+		registered[reflect.TypeOf(model).Name()] = true
+	}
+	sort.Strings(declared)
+	for _, name := range declared {
+		if !registered[name] {
+			t.Errorf("Model %s is not covered by the contract tests, add it to contractModels", name)
+		}
+		delete(registered, name)
+	}
+	for name := range registered {
+		t.Errorf("Model %s is listed in contractModels but not declared in api/models/v1", name)
+	}
+
+	goldens, err := filepath.Glob(filepath.Join(contractsDir, "*.json"))
+	if err != nil {
+		t.Fatalf("Failed to list golden files: %v", err)
+	}
+	for _, golden := range goldens {
+		name := strings.TrimSuffix(filepath.Base(golden), ".json")
+		if !contains(declared, name) {
+			t.Errorf("Golden file %s has no model, remove it", golden)
+		}
+	}
+}
+
+// fillContract sets every field of v to a fixed value, strings to their JSON name so the golden files read like a
+// schema. Lists and maps get a single element, nesting stops at a depth of 8 to cut recursive types
+func fillContract(v reflect.Value, name string, depth int) {
+	if depth > 8 {
+		return
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		if v.Type() == reflect.TypeOf(time.Time{}) {
+			v.Set(reflect.ValueOf(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)))
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			fieldName := field.Name
+			if tag, _, _ := strings.Cut(field.Tag.Get("json"), ","); tag != "" && tag != "-" {
+				fieldName = tag
+			}
+			fillContract(v.Field(i), fieldName, depth+1)
+		}
+	case reflect.String:
+		v.SetString(name)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(1)
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(1.5)
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Pointer:
+		v.Set(reflect.New(v.Type().Elem()))
+		fillContract(v.Elem(), name, depth+1)
+	case reflect.Slice:
+		if v.Type() == reflect.TypeOf(json.RawMessage{}) {
+			v.SetBytes([]byte(`{"` + name + `":true}`))
+			return
+		}
+		v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+		fillContract(v.Index(0), name, depth+1)
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			fillContract(v.Index(i), name, depth+1)
+		}
+	case reflect.Map:
+		v.Set(reflect.MakeMap(v.Type()))
+		key := reflect.New(v.Type().Key()).Elem()
+		fillContract(key, name, depth+1)
+		value := reflect.New(v.Type().Elem()).Elem()
+		fillContract(value, name, depth+1)
+		v.SetMapIndex(key, value)
+	case reflect.Interface:
+		v.Set(reflect.ValueOf(name))
+	}
+}
+
+// contains reports whether values holds value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
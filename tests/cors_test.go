@@ -0,0 +1,117 @@
+package tests
+
+import (
+	"ethereum-validator-api/utils"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+)
+
+func TestCORSConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name        string
+		origins     string
+		credentials string
+		origin      string
+		wantAllowed string
+	}{
+		{
+			name:        "Exact origin",
+			origins:     "https://app.example.com",
+			origin:      "https://app.example.com",
+			wantAllowed: "https://app.example.com",
+		},
+		{
+			name:    "Unlisted origin",
+			origins: "https://app.example.com",
+			origin:  "https://evil.example.org",
+		},
+		{
+			name:        "Wildcard subdomain",
+			origins:     "https://*.example.com",
+			origin:      "https://staging.app.example.com",
+			wantAllowed: "https://staging.app.example.com",
+		},
+		{
+			name:    "Wildcard doesn't match the apex or other domains",
+			origins: "https://*.example.com",
+			origin:  "https://example.com.evil.org",
+		},
+		{
+			name:        "Regular expression",
+			origins:     `regex:https://pr-[0-9]+\.preview\.example\.com`,
+			origin:      "https://pr-42.preview.example.com",
+			wantAllowed: "https://pr-42.preview.example.com",
+		},
+		{
+			name:        "Any origin",
+			origins:     "*",
+			credentials: "false",
+			origin:      "https://anything.example.org",
+			wantAllowed: "*",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("CORS_ALLOWED_ORIGINS", tt.origins)
+			t.Setenv("CORS_ALLOW_CREDENTIALS", tt.credentials)
+
+			config, err := utils.CORSConfigFromEnv()
+			if err != nil {
+				t.Fatalf("CORSConfigFromEnv() unexpected error: %v", err)
+			}
+
+			router := gin.New()
+			router.Use(cors.New(config))
+			router.GET("/", func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Origin", tt.origin)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != tt.wantAllowed {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.wantAllowed)
+			}
+		})
+	}
+}
+
+func TestCORSConfigFromEnv_ConfigFile(t *testing.T) {
+	file, err := os.CreateTemp(t.TempDir(), "cors-*.json")
+	if err != nil {
+		t.Fatalf("Failed to create CORS config file: %v", err)
+	}
+	file.WriteString(`{"allow_origins":["https://app.example.com"],"allow_methods":["GET"],"allow_credentials":false,"max_age":"1h"}`)
+	file.Close()
+
+	t.Setenv("CORS_CONFIG_FILE", file.Name())
+	t.Setenv("CORS_ALLOWED_METHODS", "GET,OPTIONS")
+
+	config, err := utils.CORSConfigFromEnv()
+	if err != nil {
+		t.Fatalf("CORSConfigFromEnv() unexpected error: %v", err)
+	}
+	if config.AllowCredentials || config.MaxAge.Hours() != 1 {
+		t.Errorf("CORSConfigFromEnv() credentials = %v, max age = %s, want false, 1h", config.AllowCredentials, config.MaxAge)
+	}
+	// The environment takes precedence over the file
+	if len(config.AllowMethods) != 2 {
+		t.Errorf("CORSConfigFromEnv() methods = %v, want GET,OPTIONS", config.AllowMethods)
+	}
+	if !config.AllowOriginFunc("https://app.example.com") {
+		t.Error("CORSConfigFromEnv() rejected the origin from the config file")
+	}
+
+	t.Setenv("CORS_ALLOWED_ORIGINS", "app.example.com")
+	if _, err := utils.CORSConfigFromEnv(); err == nil {
+		t.Error("CORSConfigFromEnv() expected error for an origin without scheme")
+	}
+}
@@ -0,0 +1,113 @@
+package tests
+
+import (
+	"ethereum-validator-api/utils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRegisterDebugEndpoints(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		path     string
+		apiKey   string
+		user     string
+		password string
+		wantCode int
+	}{
+		{
+			name:     "pprof disabled by default",
+			env:      map[string]string{"ADMIN_API_KEY": "key"},
+			path:     "/debug/pprof/",
+			apiKey:   "key",
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "pprof without credentials",
+			env:      map[string]string{"PPROF_ENABLED": "true", "DEBUG_USER": "ops", "DEBUG_PASSWORD": "pw"},
+			path:     "/debug/pprof/",
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "pprof with basic auth",
+			env:      map[string]string{"PPROF_ENABLED": "true", "DEBUG_USER": "ops", "DEBUG_PASSWORD": "pw"},
+			path:     "/debug/pprof/",
+			user:     "ops",
+			password: "pw",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "pprof with wrong password",
+			env:      map[string]string{"PPROF_ENABLED": "true", "DEBUG_USER": "ops", "DEBUG_PASSWORD": "pw"},
+			path:     "/debug/pprof/",
+			user:     "ops",
+			password: "guess",
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "pprof with admin API key",
+			env:      map[string]string{"PPROF_ENABLED": "true", "ADMIN_API_KEY": "key"},
+			path:     "/debug/pprof/",
+			apiKey:   "key",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "Public swagger",
+			env:      map[string]string{},
+			path:     "/swagger/index.html",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "Protected swagger",
+			env:      map[string]string{"SWAGGER_PROTECTED": "true", "ADMIN_API_KEY": "key"},
+			path:     "/swagger/index.html",
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "Disabled swagger",
+			env:      map[string]string{"SWAGGER_ENABLED": "false"},
+			path:     "/swagger/index.html",
+			wantCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"PPROF_ENABLED", "SWAGGER_ENABLED", "SWAGGER_PROTECTED", "ADMIN_API_KEY", "DEBUG_USER", "DEBUG_PASSWORD"} {
+				t.Setenv(key, tt.env[key])
+			}
+
+			router := gin.New()
+			if err := utils.RegisterDebugEndpoints(router); err != nil {
+				t.Fatalf("RegisterDebugEndpoints() unexpected error: %v", err)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.apiKey != "" {
+				req.Header.Set("X-API-Key", tt.apiKey)
+			}
+			if tt.user != "" {
+				req.SetBasicAuth(tt.user, tt.password)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantCode {
+				t.Errorf("GET %s status = %d, want %d", tt.path, w.Code, tt.wantCode)
+			}
+		})
+	}
+
+	t.Run("pprof requires credentials", func(t *testing.T) {
+		t.Setenv("PPROF_ENABLED", "true")
+		t.Setenv("ADMIN_API_KEY", "")
+		t.Setenv("DEBUG_USER", "")
+		if err := utils.RegisterDebugEndpoints(gin.New()); err == nil {
+			t.Error("RegisterDebugEndpoints() expected error without credentials")
+		}
+	})
+}
@@ -0,0 +1,62 @@
+package tests
+
+import (
+	"ethereum-validator-api/service"
+	"fmt"
+	"testing"
+)
+
+func TestRedactURL(t *testing.T) {
+	tests := []struct {
+		name   string
+		rawURL string
+		want   string
+	}{
+		{
+			name:   "API key in path",
+			rawURL: "https://example.quiknode.pro/0123456789abcdef/",
+			want:   "https://example.quiknode.pro/[REDACTED]",
+		},
+		{
+			name:   "API key in query",
+			rawURL: "https://rpc.example.com?apikey=secret",
+			want:   "https://rpc.example.com/[REDACTED]",
+		},
+		{
+			name:   "Plain host",
+			rawURL: "http://localhost:5052",
+			want:   "http://localhost:5052",
+		},
+		{
+			name:   "Not a URL",
+			rawURL: "not-a-url",
+			want:   "[REDACTED]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := service.RedactURL(tt.rawURL); got != tt.want {
+				t.Errorf("RedactURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogBuffer(t *testing.T) {
+	logs := service.NewLogBuffer(3)
+	for i := 1; i <= 5; i++ {
+		fmt.Fprintf(logs, "line %d\n", i)
+	}
+
+	got := logs.Lines()
+	want := []string{"line 3", "line 4", "line 5"}
+	if len(got) != len(want) {
+		t.Fatalf("Lines() returned %d lines, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Lines()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package tests
+
+import (
+	"ethereum-validator-api/service"
+	"math"
+	"testing"
+)
+
+func TestEffectivenessScore(t *testing.T) {
+	tests := []struct {
+		name          string
+		attestation   service.EffectivenessComponent
+		proposal      service.EffectivenessComponent
+		syncCommittee service.EffectivenessComponent
+		want          float64
+	}{
+		{
+			name: "No duties",
+			want: 0,
+		},
+		{
+			name:        "Attestations only",
+			attestation: service.EffectivenessComponent{Duties: 10, Fulfilled: 10, Rate: 0.9},
+			want:        90,
+		},
+		{
+			name:        "Missed proposal",
+			attestation: service.EffectivenessComponent{Duties: 10, Fulfilled: 10, Rate: 1},
+			proposal:    service.EffectivenessComponent{Duties: 1, Fulfilled: 0, Rate: 0},
+			want:        70 / 0.85,
+		},
+		{
+			name:          "All components",
+			attestation:   service.EffectivenessComponent{Duties: 10, Fulfilled: 10, Rate: 1},
+			proposal:      service.EffectivenessComponent{Duties: 1, Fulfilled: 1, Rate: 1},
+			syncCommittee: service.EffectivenessComponent{Duties: 100, Fulfilled: 50, Rate: 0.5},
+			want:          92.5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := service.EffectivenessScore(tt.attestation, tt.proposal, tt.syncCommittee)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("EffectivenessScore() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
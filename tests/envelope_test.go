@@ -0,0 +1,105 @@
+package tests
+
+import (
+	"encoding/json"
+	"ethereum-validator-api/api/models/v1"
+	"ethereum-validator-api/handler"
+	"ethereum-validator-api/service"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestHandler_Envelope(t *testing.T) {
+	beacon := &mockBeaconClient{responses: map[string]string{
+		"/eth/v1/beacon/headers/head":                     `{"data":{"root":"0xabc","header":{"message":{"slot":"8200"}}}}`,
+		"/eth/v1/beacon/states/head/finality_checkpoints": `{"data":{"finalized":{"epoch":"254","root":"0x01"}}}`,
+		"/eth/v1/beacon/states/8192/committees?slot=8200": `{"data":[
+			{"index":"0","slot":"8200","validators":["1","2"]},
+			{"index":"1","slot":"8200","validators":["3","4"]},
+			{"index":"2","slot":"8200","validators":["5"]}]}`,
+	}}
+	h := handler.NewHandler(service.NewEthereumServiceWithClients(beacon, &mockExecutionClient{}))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/committees/:slot", h.GetCommittees)
+
+	get := func(target string) (int, []byte) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		return w.Code, w.Body.Bytes()
+	}
+
+	// Without the envelope the endpoint's own response is kept
+	code, body := get("/committees/8200?limit=2")
+	var plain v1.CommitteesResponse
+	if err := json.Unmarshal(body, &plain); err != nil || code != http.StatusOK || len(plain.Committees) != 2 || plain.Total != 3 {
+		t.Fatalf("GET without envelope = %d %s, want the first 2 of 3 committees", code, body)
+	}
+
+	tests := []struct {
+		name       string
+		target     string
+		wantCount  int
+		wantNext   string
+		wantFields bool
+	}{
+		{
+			name:      "First page",
+			target:    "/committees/8200?envelope=true&limit=2",
+			wantCount: 2,
+			wantNext:  "/committees/8200?envelope=true&limit=2&offset=2",
+		},
+		{
+			name:      "Last page",
+			target:    "/committees/8200?envelope=true&limit=2&offset=2",
+			wantCount: 1,
+		},
+		{
+			name:       "Selected fields apply to the entries",
+			target:     "/committees/8200?envelope=true&fields=index",
+			wantCount:  3,
+			wantFields: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, body := get(tt.target)
+			if code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d: %s", code, body)
+			}
+
+			var envelope struct {
+				Data  []map[string]interface{} `json:"data"`
+				Meta  v1.EnvelopeMetaResponse  `json:"meta"`
+				Links v1.EnvelopeLinksResponse `json:"links"`
+			}
+			if err := json.Unmarshal(body, &envelope); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if len(envelope.Data) != tt.wantCount || envelope.Meta.Pagination.Count != tt.wantCount || envelope.Meta.Pagination.Total != 3 {
+				t.Errorf("Envelope = %s, want %d of 3 committees", body, tt.wantCount)
+			}
+			if envelope.Links.Next != tt.wantNext {
+				t.Errorf("Next link = %q, want %q", envelope.Links.Next, tt.wantNext)
+			}
+			if _, ok := envelope.Data[0]["validators"]; ok == tt.wantFields {
+				t.Errorf("Entry = %v, want validators only without selected fields", envelope.Data[0])
+			}
+			if envelope.Meta.Provenance != nil {
+				t.Error("Expected no provenance without meta=true")
+			}
+		})
+	}
+
+	if code, _ := get("/committees/8200?envelope=true&fields=unknown"); code != http.StatusBadRequest {
+		t.Errorf("GET with an unknown field = %d, want 400", code)
+	}
+	if code, _ := get("/committees/8200?envelope=true&limit=65"); code != http.StatusBadRequest {
+		t.Errorf("GET with a too large limit = %d, want 400", code)
+	}
+}
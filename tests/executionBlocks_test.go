@@ -0,0 +1,80 @@
+package tests
+
+import (
+	"encoding/json"
+	"ethereum-validator-api/api/models/v1"
+	"ethereum-validator-api/handler"
+	"ethereum-validator-api/service"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/gin-gonic/gin"
+)
+
+func TestBlockRewardByBlock(t *testing.T) {
+	header := &types.Header{Number: big.NewInt(100), BaseFee: big.NewInt(10), Time: 1200}
+	block := types.NewBlockWithHeader(header)
+	receipts := []*types.Receipt{
+		{TxHash: common.HexToHash("0x01"), GasUsed: 1000000, EffectiveGasPrice: big.NewInt(2000000010)},
+	}
+	hash := block.Hash().Hex()
+
+	// Block 100 was produced 1200 seconds after genesis, in slot 100
+	execution := &mockExecutionClient{
+		results: map[string]string{
+			"eth_getBlockByNumber": `{"number":"0x64","hash":"` + hash + `","timestamp":"0x4b0"}`,
+			"eth_getBlockByHash":   `{"number":"0x64","hash":"` + hash + `","timestamp":"0x4b0"}`,
+		},
+		blocks:   []*types.Block{block},
+		receipts: map[common.Hash][]*types.Receipt{block.Hash(): receipts},
+	}
+	beacon := &mockBeaconClient{responses: map[string]string{
+		"/eth/v1/beacon/genesis":                          `{"data":{"genesis_time":"0"}}`,
+		"/eth/v1/beacon/headers/head":                     `{"data":{"root":"0xabc","header":{"message":{"slot":"128"}}}}`,
+		"/eth/v1/beacon/states/head/finality_checkpoints": `{"data":{"finalized":{"epoch":"2","root":"0x01"}}}`,
+		"/eth/v1/beacon/blocks/100/root":                  `{"data":{"root":"0x01"}}`,
+		"/eth/v2/beacon/blocks/100":                       `{"data":{"message":{"proposer_index":"7","body":{"execution_payload":{"block_hash":"` + hash + `","block_number":"100"}}}}}`,
+	}}
+	ethService := service.NewEthereumServiceWithClients(beacon, execution)
+	h := handler.NewHandler(ethService)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/blockreward/:slot", h.GetBlockReward)
+	router.GET("/blockreward/by-block/:number_or_hash", h.GetBlockRewardByBlock)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	// Blocks the indexer hasn't seen are resolved through their timestamp, by number and by hash
+	for _, id := range []string{"100", hash} {
+		w := get("/blockreward/by-block/" + id)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200 for %s, got %d: %s", id, w.Code, w.Body.String())
+		}
+		var response v1.ExecutionBlockRewardResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if response.Slot != 100 || response.BlockNumber != 100 || response.BlockHash != hash || response.Reward != "2000000" {
+			t.Errorf("Expected the reward of slot 100 for %s, got %+v", id, response)
+		}
+	}
+
+	if w := get("/blockreward/by-block/latest"); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an invalid identifier, got %d", w.Code)
+	}
+
+	// A block whose slot carried another execution block was not proposed on the beacon chain
+	execution.results["eth_getBlockByNumber"] = `{"number":"0x65","hash":"0x` + common.Bytes2Hex(make([]byte, 32)) + `","timestamp":"0x4b0"}`
+	if w := get("/blockreward/by-block/101"); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for a block missing from the beacon chain, got %d: %s", w.Code, w.Body.String())
+	}
+}
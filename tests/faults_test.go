@@ -0,0 +1,91 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"ethereum-validator-api/service"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFaultInjection(t *testing.T) {
+	var requests atomic.Int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch r.URL.Path {
+		case "/eth/v1/beacon/headers/head":
+			w.Write([]byte(`{"data":{"root":"0xabc","header":{"message":{"slot":"8192"}}}}`))
+		case "/eth/v1/beacon/states/head/finality_checkpoints":
+			w.Write([]byte(`{"data":{"finalized":{"epoch":"254","root":"0x01"}}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer upstream.Close()
+
+	service.SetUpstreamRateLimit(100, 10)
+	defer service.SetUpstreamRateLimit(1, 1)
+
+	newService := func(t *testing.T, faults service.FaultConfig) *service.EthereumService {
+		t.Helper()
+		ethService, err := service.NewEthereumService(upstream.URL)
+		if err != nil {
+			t.Fatalf("NewEthereumService() unexpected error: %v", err)
+		}
+		if err := ethService.SetFaultInjection(faults); err != nil {
+			t.Fatalf("SetFaultInjection() unexpected error: %v", err)
+		}
+		return ethService
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		if _, err := newService(t, service.FaultConfig{}).GetChainHead(context.Background()); err != nil {
+			t.Errorf("GetChainHead() unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Latency", func(t *testing.T) {
+		ethService := newService(t, service.FaultConfig{LatencyRate: 1, Latency: 50 * time.Millisecond})
+		start := time.Now()
+		if _, err := ethService.GetChainHead(context.Background()); err != nil {
+			t.Fatalf("GetChainHead() unexpected error: %v", err)
+		}
+		// Both the header and the checkpoints request are delayed
+		if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+			t.Errorf("Expected two delayed requests to take at least 100ms, took %v", elapsed)
+		}
+	})
+
+	t.Run("Malformed", func(t *testing.T) {
+		ethService := newService(t, service.FaultConfig{MalformedRate: 1})
+		if _, err := ethService.GetChainHead(context.Background()); !errors.Is(err, service.ErrUpstreamInvalidResponse) {
+			t.Errorf("GetChainHead() error = %v, want an invalid response error", err)
+		}
+	})
+
+	// Last, as the provider stays paused for the Retry-After of the injected 429
+	t.Run("Rate limited", func(t *testing.T) {
+		ethService := newService(t, service.FaultConfig{RateLimitRate: 1})
+		before := requests.Load()
+
+		// The injected 429 pauses the provider for its Retry-After, the retry fails right away instead of waiting
+		_, err := ethService.GetChainHead(context.Background())
+		var rateLimitErr *service.RateLimitError
+		if !errors.As(err, &rateLimitErr) || rateLimitErr.RetryAfter <= 0 || rateLimitErr.RetryAfter > time.Second {
+			t.Errorf("GetChainHead() error = %v, want a RateLimitError within the 1s Retry-After", err)
+		}
+		if requests.Load() != before {
+			t.Errorf("Expected rate limited requests not to reach the provider, got %d", requests.Load()-before)
+		}
+	})
+
+	t.Run("Invalid rate", func(t *testing.T) {
+		ethService := newService(t, service.FaultConfig{})
+		if err := ethService.SetFaultInjection(service.FaultConfig{MalformedRate: 1.5}); err == nil {
+			t.Error("SetFaultInjection() expected an error for a rate above 1")
+		}
+	})
+}
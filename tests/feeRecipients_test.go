@@ -0,0 +1,186 @@
+package tests
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"ethereum-validator-api/service"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestLoadFeeRecipientRegistry(t *testing.T) {
+	err := os.WriteFile("labels.json", []byte(`{"0xAbC0000000000000000000000000000000000001": "Solo Staker"}`), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create labels file: %v", err)
+	}
+	defer os.Remove("labels.json")
+
+	registry, err := service.LoadFeeRecipientRegistry("labels.json")
+	if err != nil {
+		t.Fatalf("LoadFeeRecipientRegistry() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		address string
+		want    string
+		wantOk  bool
+	}{
+		{
+			name:    "Label from file is case-insensitive",
+			address: "0xabc0000000000000000000000000000000000001",
+			want:    "Solo Staker",
+			wantOk:  true,
+		},
+		{
+			name:    "Built-in label",
+			address: "0x388C818CA8B9251b393131C08a736A67ccB19297",
+			want:    "Lido",
+			wantOk:  true,
+		},
+		{
+			name:    "Unknown address",
+			address: "0x0000000000000000000000000000000000000002",
+			wantOk:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := registry.Lookup(tt.address)
+			if ok != tt.wantOk || got != tt.want {
+				t.Errorf("Lookup() = (%q, %v), want (%q, %v)", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+// ensExecutionClient answers the ENS registry, resolver and reverse record calls of an address with a primary name,
+// blocking them until release is closed when it is set
+type ensExecutionClient struct {
+	*mockExecutionClient
+	name    string // Primary name of every address, "" for none
+	release chan struct{}
+
+	mu    sync.Mutex
+	calls int
+}
+
+func (m *ensExecutionClient) Call(ctx context.Context, method string, params []interface{}, out interface{}) error {
+	if method != "eth_call" {
+		return m.mockExecutionClient.Call(ctx, method, params, out)
+	}
+	m.mu.Lock()
+	m.calls++
+	m.mu.Unlock()
+	if m.release != nil {
+		select {
+		case <-m.release:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	data := params[0].(map[string]string)["data"]
+	var result string
+	switch {
+	case m.name == "":
+		result = "0x" + strings.Repeat("0", 64)
+	case strings.HasPrefix(data, "0x0178b8bf"): // resolver(bytes32)
+		result = fmt.Sprintf("0x%064x", 0xe5)
+	case strings.HasPrefix(data, "0x691f3431"): // name(bytes32)
+		result = fmt.Sprintf("0x%064x%064x%s", 32, len(m.name), hex.EncodeToString([]byte(m.name)))
+		result += strings.Repeat("0", 64-len(result[2:])%64)
+	default: // addr(bytes32)
+		result = "0x" + strings.Repeat("0", 24) + strings.Repeat("aa", 20)
+	}
+	return json.Unmarshal([]byte(fmt.Sprintf("%q", result)), out)
+}
+
+func (m *ensExecutionClient) Calls() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.calls
+}
+
+// newENSService creates a service with ENS lookup enabled, whose block 100 pays an unlabeled fee recipient
+func newENSService(execution *ensExecutionClient) *service.EthereumService {
+	header := &types.Header{Number: big.NewInt(100), BaseFee: big.NewInt(10), Coinbase: common.HexToAddress("0x" + strings.Repeat("aa", 20))}
+	execution.mockExecutionClient = &mockExecutionClient{blocks: []*types.Block{types.NewBlockWithHeader(header)}}
+	beacon := &mockBeaconClient{responses: map[string]string{
+		"/eth/v1/beacon/blocks/100/root": `{"data":{"root":"0x01"}}`,
+		"/eth/v2/beacon/blocks/100":      beaconBlockWithPayload(100),
+	}}
+	ethService := service.NewEthereumServiceWithClients(beacon, execution)
+	ethService.EnableENSLookup(true)
+	return ethService
+}
+
+func TestFeeRecipientLabel_ENSCachesMissingNames(t *testing.T) {
+	execution := &ensExecutionClient{}
+	ethService := newENSService(execution)
+
+	for i := 0; i < 2; i++ {
+		reward, err := ethService.GetBlockRewardBySlot(context.Background(), 100)
+		if err != nil {
+			t.Fatalf("GetBlockRewardBySlot() unexpected error: %v", err)
+		}
+		if reward.FeeRecipientLabel != "" {
+			t.Errorf("GetBlockRewardBySlot() label = %q, want none", reward.FeeRecipientLabel)
+		}
+		ethService.ClearCache()
+	}
+
+	// The reverse record is looked up once, the second reward finds the address cached without a name
+	if calls := execution.Calls(); calls != 1 {
+		t.Errorf("Expected 1 eth_call, got %d", calls)
+	}
+}
+
+func TestFeeRecipientLabel_ENSResolvesInBackground(t *testing.T) {
+	execution := &ensExecutionClient{name: "alice.eth", release: make(chan struct{})}
+	ethService := newENSService(execution)
+
+	// A slow lookup doesn't hold up the request, which goes unlabeled
+	start := time.Now()
+	reward, err := ethService.GetBlockRewardBySlot(context.Background(), 100)
+	if err != nil {
+		t.Fatalf("GetBlockRewardBySlot() unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("GetBlockRewardBySlot() took %s waiting for ENS", elapsed)
+	}
+	if reward.FeeRecipientLabel != "" {
+		t.Errorf("GetBlockRewardBySlot() label = %q while ENS is slow, want none", reward.FeeRecipientLabel)
+	}
+
+	// The lookup finishes after the request and labels the next ones
+	close(execution.release)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		ethService.ClearCache()
+		reward, err := ethService.GetBlockRewardBySlot(context.Background(), 100)
+		if err != nil {
+			t.Fatalf("GetBlockRewardBySlot() unexpected error: %v", err)
+		}
+		if reward.FeeRecipientLabel == "alice.eth" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("GetBlockRewardBySlot() label = %q, want alice.eth once the lookup finished", reward.FeeRecipientLabel)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if calls := execution.Calls(); calls != 4 {
+		t.Errorf("Expected 4 eth_calls for one lookup, got %d", calls)
+	}
+}
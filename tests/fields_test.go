@@ -0,0 +1,73 @@
+package tests
+
+import (
+	"encoding/json"
+	"ethereum-validator-api/handler"
+	"ethereum-validator-api/service"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestSparseFieldsets(t *testing.T) {
+	ethService := service.NewEthereumServiceWithClients(&mockBeaconClient{}, &mockExecutionClient{})
+	ethService.Index().Put(&service.BlockDetail{Slot: 10, ProposerIndex: 7, Graffiti: "Lighthouse", BlockRoot: "0x10"})
+	ethService.Index().Put(&service.BlockDetail{Slot: 11, ProposerIndex: 8, Graffiti: "lighthouse/v5", BlockRoot: "0x11"})
+	h := handler.NewHandler(ethService)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/graffiti/search", h.SearchGraffiti)
+
+	get := func(path string) (*httptest.ResponseRecorder, map[string]interface{}) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		var body map[string]interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("Failed to decode response of %s: %v", path, err)
+		}
+		return w, body
+	}
+
+	// Without fields the whole response is returned
+	if _, body := get("/graffiti/search?q=lighthouse"); len(body) != 3 {
+		t.Errorf("Expected the full response, got %v", body)
+	}
+
+	w, body := get("/graffiti/search?q=lighthouse&fields=count,results.slot,results.graffiti")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if _, ok := body["query"]; ok || body["count"] != float64(2) {
+		t.Errorf("Expected only count and results, got %v", body)
+	}
+	results, _ := body["results"].([]interface{})
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %v", body["results"])
+	}
+	first, _ := results[0].(map[string]interface{})
+	if len(first) != 2 || first["slot"] != float64(10) || first["graffiti"] != "Lighthouse" {
+		t.Errorf("Expected results reduced to slot and graffiti, got %v", first)
+	}
+
+	tests := []struct {
+		name   string
+		fields string
+	}{
+		{"Unknown field", "count,slots"},
+		{"Unknown nested field", "results.proposer"},
+		{"Nested field of a scalar", "count.value"},
+		{"Empty field", "results..slot"},
+		{"No fields", ","},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, body := get("/graffiti/search?q=lighthouse&fields=" + tt.fields)
+			if w.Code != http.StatusBadRequest || body["details"] == nil {
+				t.Errorf("Expected status 400 with details, got %d: %s", w.Code, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,313 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Modes of the upstream fixtures, selected with the UPSTREAM_FIXTURES environment variable
+const (
+	fixturesReplay = "replay" // Serve the recorded responses, the default
+	fixturesRecord = "record" // Forward to ETH_RPC and record its responses
+	fixturesLive   = "live"   // Talk to ETH_RPC directly
+)
+
+// fixturesDir holds the recorded upstream interactions, one file per fixture name
+var fixturesDir = "testdata/upstream"
+
+// upstreamInteraction is a recorded request and its response. JSON-RPC requests are stored without their ids, the
+// ids of batch responses are replaced by the position of their request
+type upstreamInteraction struct {
+	Method   string          `json:"method"`
+	URI      string          `json:"uri"`
+	Body     json.RawMessage `json:"body,omitempty"`
+	Status   int             `json:"status"`
+	Response json.RawMessage `json:"response,omitempty"`
+}
+
+// upstreamFixtures returns the upstream URL a test talking to the Beacon and JSON-RPC APIs should use. By default it
+// is a server replaying the interactions recorded under the name, set UPSTREAM_FIXTURES=record with ETH_RPC to record
+// them again or UPSTREAM_FIXTURES=live to use ETH_RPC without fixtures
+func upstreamFixtures(t *testing.T, name string) string {
+	t.Helper()
+	path := filepath.Join(fixturesDir, name+".json")
+	rpcURL := strings.TrimSuffix(os.Getenv("ETH_RPC"), "/")
+
+	switch mode := fixturesMode(); mode {
+	case fixturesLive:
+		if rpcURL == "" {
+			t.Skip("ETH_RPC environment variable not set, skipping live test")
+		}
+		return rpcURL
+
+	case fixturesRecord:
+		if rpcURL == "" {
+			t.Fatal("Recording upstream fixtures needs ETH_RPC")
+		}
+		var mu sync.Mutex
+		var interactions []upstreamInteraction
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			request, err := http.NewRequestWithContext(r.Context(), r.Method, rpcURL+r.URL.RequestURI(), bytes.NewReader(body))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadGateway)
+				return
+			}
+			request.Header = r.Header.Clone()
+			// Fixtures are kept readable, the clients fall back to JSON when SSZ isn't offered
+			request.Header.Set("Accept", "application/json")
+
+			response, err := http.DefaultClient.Do(request)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadGateway)
+				return
+			}
+			defer response.Body.Close()
+			responseBody, _ := io.ReadAll(response.Body)
+
+			normalized, ids := normalizeRPCRequest(body)
+			mu.Lock()
+			interactions = append(interactions, upstreamInteraction{
+				Method:   r.Method,
+				URI:      r.URL.RequestURI(),
+				Body:     normalized,
+				Status:   response.StatusCode,
+				Response: rawJSON(positionRPCResponse(responseBody, ids)),
+			})
+			mu.Unlock()
+
+			w.Header().Set("Content-Type", response.Header.Get("Content-Type"))
+			w.WriteHeader(response.StatusCode)
+			w.Write(responseBody)
+		}))
+		t.Cleanup(func() {
+			server.Close()
+			mu.Lock()
+			defer mu.Unlock()
+			data, err := json.MarshalIndent(interactions, "", "  ")
+			if err == nil {
+				err = os.MkdirAll(fixturesDir, 0o755)
+			}
+			if err == nil {
+				err = os.WriteFile(path, append(data, '\n'), 0o644)
+			}
+			if err != nil {
+				t.Errorf("Failed to save upstream fixtures %s: %v", path, err)
+			}
+		})
+		return server.URL
+
+	case fixturesReplay:
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			t.Skipf("No upstream fixtures recorded in %s, record them with UPSTREAM_FIXTURES=record and ETH_RPC", path)
+		}
+		if err != nil {
+			t.Fatalf("Failed to read upstream fixtures: %v", err)
+		}
+		var interactions []upstreamInteraction
+		if err := json.Unmarshal(data, &interactions); err != nil {
+			t.Fatalf("Failed to decode upstream fixtures %s: %v", path, err)
+		}
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			normalized, ids := normalizeRPCRequest(body)
+			for _, interaction := range interactions {
+				if interaction.Method == r.Method && interaction.URI == r.URL.RequestURI() && bytes.Equal(compactJSON(interaction.Body), compactJSON(normalized)) {
+					w.Header().Set("Content-Type", "application/json")
+					w.WriteHeader(interaction.Status)
+					w.Write(restoreRPCResponse(responseBody(interaction.Response), ids))
+					return
+				}
+			}
+			t.Errorf("No recorded upstream response for %s %s %s", r.Method, r.URL.RequestURI(), body)
+			http.Error(w, "no recorded response", http.StatusNotImplemented)
+		}))
+		t.Cleanup(server.Close)
+		return server.URL
+
+	default:
+		t.Fatalf("Unknown UPSTREAM_FIXTURES mode %q, expected %s, %s or %s", mode, fixturesReplay, fixturesRecord, fixturesLive)
+		return ""
+	}
+}
+
+// fixturesMode returns the mode of the upstream fixtures
+func fixturesMode() string {
+	if mode := os.Getenv("UPSTREAM_FIXTURES"); mode != "" {
+		return mode
+	}
+	return fixturesReplay
+}
+
+// normalizeRPCRequest removes the ids of a JSON-RPC request or batch, which change from run to run, and returns them in
+// request order. Other bodies are returned unchanged
+func normalizeRPCRequest(body []byte) (json.RawMessage, []json.RawMessage) {
+	var batch []map[string]json.RawMessage
+	if err := json.Unmarshal(body, &batch); err == nil {
+		ids := make([]json.RawMessage, len(batch))
+		for i, call := range batch {
+			ids[i] = call["id"]
+			delete(call, "id")
+		}
+		normalized, _ := json.Marshal(batch)
+		return normalized, ids
+	}
+
+	var call map[string]json.RawMessage
+	if err := json.Unmarshal(body, &call); err == nil && call["jsonrpc"] != nil {
+		id := call["id"]
+		delete(call, "id")
+		normalized, _ := json.Marshal(call)
+		return normalized, []json.RawMessage{id}
+	}
+	return rawJSON(body), nil
+}
+
+// positionRPCResponse replaces the ids of a JSON-RPC response by the position of their request
+func positionRPCResponse(body []byte, ids []json.RawMessage) []byte {
+	return mapRPCResponseIDs(body, func(id json.RawMessage) json.RawMessage {
+		for i, requestID := range ids {
+			if bytes.Equal(requestID, id) {
+				position, _ := json.Marshal(i)
+				return position
+			}
+		}
+		return id
+	})
+}
+
+// restoreRPCResponse replaces the request positions of a recorded JSON-RPC response by the ids of the replayed request
+func restoreRPCResponse(body []byte, ids []json.RawMessage) []byte {
+	return mapRPCResponseIDs(body, func(id json.RawMessage) json.RawMessage {
+		var position int
+		if err := json.Unmarshal(id, &position); err == nil && position >= 0 && position < len(ids) {
+			return ids[position]
+		}
+		return id
+	})
+}
+
+// mapRPCResponseIDs rewrites the ids of a JSON-RPC response or batch response, other bodies are returned unchanged
+func mapRPCResponseIDs(body []byte, mapID func(json.RawMessage) json.RawMessage) []byte {
+	var batch []map[string]json.RawMessage
+	if err := json.Unmarshal(body, &batch); err == nil {
+		for _, response := range batch {
+			if id, ok := response["id"]; ok {
+				response["id"] = mapID(id)
+			}
+		}
+		mapped, _ := json.Marshal(batch)
+		return mapped
+	}
+
+	var response map[string]json.RawMessage
+	if err := json.Unmarshal(body, &response); err == nil && response["jsonrpc"] != nil {
+		response["id"] = mapID(response["id"])
+		mapped, _ := json.Marshal(response)
+		return mapped
+	}
+	return body
+}
+
+// rawJSON returns the body as raw JSON, quoting it as a string when it isn't JSON
+func rawJSON(body []byte) json.RawMessage {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+	if json.Valid(body) {
+		return body
+	}
+	quoted, _ := json.Marshal(string(body))
+	return quoted
+}
+
+// responseBody returns the body of a recorded response, unquoting bodies that were recorded as strings
+func responseBody(response json.RawMessage) []byte {
+	var text string
+	if err := json.Unmarshal(response, &text); err == nil {
+		return []byte(text)
+	}
+	return response
+}
+
+// compactJSON removes insignificant whitespace so recorded and replayed bodies compare equal
+func compactJSON(body []byte) []byte {
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, body); err != nil {
+		return body
+	}
+	return compacted.Bytes()
+}
+
+func TestUpstreamFixtures_RecordAndReplay(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.Write([]byte(`{"data":{"genesis_time":"1606824023"}}`))
+			return
+		}
+		// Answer the batch out of order, as providers may
+		var calls []struct {
+			ID json.RawMessage `json:"id"`
+		}
+		json.NewDecoder(r.Body).Decode(&calls)
+		fmt.Fprintf(w, `[{"jsonrpc":"2.0","id":%s,"result":"0x2"},{"jsonrpc":"2.0","id":%s,"result":"0x1"}]`, calls[1].ID, calls[0].ID)
+	}))
+	defer upstream.Close()
+
+	dir := fixturesDir
+	fixturesDir = t.TempDir()
+	defer func() { fixturesDir = dir }()
+	t.Setenv("ETH_RPC", upstream.URL)
+
+	batch := func(url string, first, second int) string {
+		body := fmt.Sprintf(`[{"jsonrpc":"2.0","id":%d,"method":"eth_chainId","params":[]},{"jsonrpc":"2.0","id":%d,"method":"eth_blockNumber","params":[]}]`, first, second)
+		response, err := http.Post(url, "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("Batch request failed: %v", err)
+		}
+		defer response.Body.Close()
+		data, _ := io.ReadAll(response.Body)
+		return string(compactJSON(data))
+	}
+	genesis := func(url string) string {
+		response, err := http.Get(url + "/eth/v1/beacon/genesis")
+		if err != nil {
+			t.Fatalf("Beacon request failed: %v", err)
+		}
+		defer response.Body.Close()
+		data, _ := io.ReadAll(response.Body)
+		return string(compactJSON(data))
+	}
+
+	t.Run("Record", func(t *testing.T) {
+		t.Setenv("UPSTREAM_FIXTURES", fixturesRecord)
+		url := upstreamFixtures(t, "roundtrip")
+		genesis(url)
+		batch(url, 1, 2)
+	})
+
+	t.Run("Replay", func(t *testing.T) {
+		upstream.Close()
+		url := upstreamFixtures(t, "roundtrip")
+
+		if got := genesis(url); got != `{"data":{"genesis_time":"1606824023"}}` {
+			t.Errorf("Unexpected replayed beacon response: %s", got)
+		}
+		// The replayed batch carries the ids of this request, in the order the provider answered
+		want := `[{"id":11,"jsonrpc":"2.0","result":"0x2"},{"id":10,"jsonrpc":"2.0","result":"0x1"}]`
+		if got := batch(url, 10, 11); got != want {
+			t.Errorf("Unexpected replayed batch response: %s, want %s", got, want)
+		}
+	})
+}
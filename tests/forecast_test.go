@@ -0,0 +1,139 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"ethereum-validator-api/api/models/v1"
+	"ethereum-validator-api/handler"
+	"ethereum-validator-api/service"
+	"github.com/gin-gonic/gin"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestEthereumService_GetValidatorForecast(t *testing.T) {
+	ethService := aprService()
+	if _, err := ethService.GetValidatorForecast(context.Background(), "7", 7*24*time.Hour); !errors.Is(err, service.ErrNetworkStatsUnavailable) {
+		t.Fatalf("GetValidatorForecast() before the network statistics error = %v, want ErrNetworkStatsUnavailable", err)
+	}
+	if err := ethService.NetworkStatsTask(service.Every(0)).Run(context.Background()); err != nil {
+		t.Fatalf("NetworkStatsTask.Run() error = %v", err)
+	}
+
+	forecast, err := ethService.GetValidatorForecast(context.Background(), "7", 7*24*time.Hour)
+	if err != nil {
+		t.Fatalf("GetValidatorForecast() error = %v", err)
+	}
+	if forecast.ValidatorIndex != 7 || forecast.Status != "active_ongoing" || forecast.EffectiveBalance != 32e9 || forecast.Epochs != 1575 {
+		t.Errorf("GetValidatorForecast() = %+v, want 1575 epochs of validator 7 with 32 ETH", forecast)
+	}
+
+	// 14000 GWEI per epoch at 50% participation, from which the base reward of 33185 GWEI is recovered
+	if forecast.Attestation != 22050000 {
+		t.Errorf("Attestation = %d, want 1575 epochs of 14000 GWEI", forecast.Attestation)
+	}
+	if forecast.SyncCommittee != 1633333 || forecast.ProposerConsensus != 3266666 {
+		t.Errorf("SyncCommittee = %d, ProposerConsensus = %d, want 1633333 and 3266666", forecast.SyncCommittee, forecast.ProposerConsensus)
+	}
+
+	// A quarter of the active balance proposes a quarter of the slots, at the average reward of the 5 indexed blocks
+	if forecast.ExpectedProposals != 12600 || forecast.ProposalProbability != 1 {
+		t.Errorf("ExpectedProposals = %v with probability %v, want 12600 and 1", forecast.ExpectedProposals, forecast.ProposalProbability)
+	}
+	if forecast.AverageBlockReward != 430200000 || forecast.MEVShare != 0.4 || forecast.Execution != 12600*430200000 {
+		t.Errorf("Execution = %d from an average of %d GWEI at %v MEV share, want 12600 blocks of 430200000 GWEI at 0.4", forecast.Execution, forecast.AverageBlockReward, forecast.MEVShare)
+	}
+	if forecast.SyncCommitteeProbability != 1 || forecast.SyncCommitteePayout != 265481 {
+		t.Errorf("SyncCommittee probability %v payout %d, want every period paying 265481 GWEI", forecast.SyncCommitteeProbability, forecast.SyncCommitteePayout)
+	}
+
+	total := forecast.Attestation + forecast.SyncCommittee + forecast.ProposerConsensus + forecast.Execution
+	if forecast.Total != total || math.Abs(forecast.APR-float64(total)/32e9*365/7) > 1e-9 {
+		t.Errorf("Total = %d at %v APR, want %d annualized on 32 ETH", forecast.Total, forecast.APR, total)
+	}
+
+	// An exited validator earns nothing, the network conditions are still reported
+	exited, err := ethService.GetValidatorForecast(context.Background(), "9", 30*24*time.Hour)
+	if err != nil {
+		t.Fatalf("GetValidatorForecast() of an exited validator error = %v", err)
+	}
+	if exited.Total != 0 || exited.ExpectedProposals != 0 || exited.Epochs != 6750 || exited.Network == nil || exited.AverageBlockReward != 430200000 {
+		t.Errorf("GetValidatorForecast() of an exited validator = %+v, want no rewards over 6750 epochs", exited)
+	}
+}
+
+func TestHandler_GetValidatorForecast(t *testing.T) {
+	ethService := aprService()
+	h := handler.NewHandler(ethService)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/validator/:id/forecast", h.GetValidatorForecast)
+
+	get := func(path string, out interface{}) int {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if err := json.Unmarshal(w.Body.Bytes(), out); err != nil {
+			t.Fatalf("Failed to unmarshal response of %s: %v", path, err)
+		}
+		return w.Code
+	}
+
+	var errorResponse v1.ErrorResponse
+	if code := get("/validator/7/forecast", &errorResponse); code != http.StatusServiceUnavailable {
+		t.Errorf("GET /validator/7/forecast before the network statistics = %d, want 503", code)
+	}
+	if err := ethService.NetworkStatsTask(service.Every(0)).Run(context.Background()); err != nil {
+		t.Fatalf("NetworkStatsTask.Run() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+	}{
+		{"Week", "/validator/7/forecast", http.StatusOK},
+		{"Month", "/validator/7/forecast?period=30d", http.StatusOK},
+		{"Invalid period", "/validator/7/forecast?period=1y", http.StatusBadRequest},
+		{"Invalid unit", "/validator/7/forecast?unit=finney", http.StatusBadRequest},
+		{"Invalid validator", "/validator/abc/forecast", http.StatusBadRequest},
+		{"Unknown validator", "/validator/12/forecast", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var response json.RawMessage
+			if code := get(tt.path, &response); code != tt.wantStatus {
+				t.Errorf("GET %s = %d %s, want %d", tt.path, code, response, tt.wantStatus)
+			}
+		})
+	}
+
+	var forecast v1.ForecastResponse
+	get("/validator/7/forecast?period=30d", &forecast)
+	if forecast.Period != "30d" || forecast.Epochs != 6750 || forecast.Unit != "gwei" || forecast.Rewards.Attestation != "94500000" || forecast.Network.ActiveValidators != 4 {
+		t.Errorf("GET /validator/7/forecast?period=30d = %+v", forecast)
+	}
+	// Forecasts are whole GWEI, so the default unit has no fraction
+	var sum int64
+	for _, amount := range []string{forecast.Rewards.Attestation, forecast.Rewards.SyncCommittee, forecast.Rewards.ProposerConsensus, forecast.Rewards.Execution} {
+		value, err := strconv.ParseInt(amount, 10, 64)
+		if err != nil {
+			t.Fatalf("Reward amount %q is not a whole amount of GWEI: %v", amount, err)
+		}
+		sum += value
+	}
+	if forecast.Total != strconv.FormatInt(sum, 10) {
+		t.Errorf("Total = %s, want the sum of %+v", forecast.Total, forecast.Rewards)
+	}
+
+	var inWei v1.ForecastResponse
+	get("/validator/7/forecast?period=30d&unit=wei", &inWei)
+	if inWei.Unit != "wei" || inWei.Rewards.Attestation != "94500000000000000" {
+		t.Errorf("GET /validator/7/forecast?period=30d&unit=wei attestation = %s %s, want 94500000000000000 wei", inWei.Rewards.Attestation, inWei.Unit)
+	}
+}
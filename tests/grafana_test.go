@@ -0,0 +1,144 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"ethereum-validator-api/api/models/v1"
+	"ethereum-validator-api/handler"
+	"ethereum-validator-api/service"
+	"github.com/gin-gonic/gin"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEthereumService_GetMetricSeries(t *testing.T) {
+	beacon := &mockBeaconClient{responses: map[string]string{
+		"/eth/v1/beacon/genesis": `{"data":{"genesis_time":"0"}}`,
+	}}
+	ethService := service.NewEthereumServiceWithClients(beacon, &mockExecutionClient{})
+
+	index := ethService.Index()
+	index.PutReward(10, &service.BlockReward{Status: "mev", Reward: big.NewInt(2e9)})
+	index.PutReward(11, &service.BlockReward{Status: "vanilla", Reward: big.NewInt(1e9), RewardWei: big.NewInt(1e18)})
+	index.PutReward(500, &service.BlockReward{Status: "mev", Reward: big.NewInt(1e9)}) // Outside the range
+	index.Put(&service.BlockDetail{Slot: 10, BaseFee: big.NewInt(20e9), GasUsed: 15000000, GasLimit: 30000000, SyncParticipation: 256})
+	index.PutAttestation(42, &service.AttestationPerformance{Epoch: 0, Included: true, Reward: 14000})
+	index.PutAttestation(42, &service.AttestationPerformance{Epoch: 1, Included: true, Reward: -3000})
+
+	ctx, from, to := context.Background(), time.Unix(0, 0), time.Unix(12*400, 0)
+
+	rewards, err := ethService.GetMetricSeries(ctx, service.MetricBlockReward, from, to, 0)
+	if err != nil {
+		t.Fatalf("GetMetricSeries() error = %v", err)
+	}
+	if len(rewards) != 2 || rewards[0].Value != 2 || rewards[1].Value != 1 || !rewards[0].Time.Equal(time.Unix(120, 0)) {
+		t.Errorf("GetMetricSeries(block_reward) = %+v, want 2 and 1 ETH from slot 10", rewards)
+	}
+
+	// Averaging both blocks into one point yields the MEV share
+	share, err := ethService.GetMetricSeries(ctx, service.MetricMEVShare, from, to, 1)
+	if err != nil {
+		t.Fatalf("GetMetricSeries() error = %v", err)
+	}
+	if len(share) != 1 || share[0].Value != 0.5 {
+		t.Errorf("GetMetricSeries(mev_share) = %+v, want a single point of 0.5", share)
+	}
+
+	for metric, want := range map[string]float64{service.MetricBaseFee: 20, service.MetricGasUtilization: 50, service.MetricSyncParticipation: 0.5} {
+		series, err := ethService.GetMetricSeries(ctx, metric, from, to, 0)
+		if err != nil {
+			t.Fatalf("GetMetricSeries(%s) error = %v", metric, err)
+		}
+		if len(series) != 1 || series[0].Value != want {
+			t.Errorf("GetMetricSeries(%s) = %+v, want %v", metric, series, want)
+		}
+	}
+
+	attestations, err := ethService.GetMetricSeries(ctx, "attestation_reward:42", from, to, 0)
+	if err != nil {
+		t.Fatalf("GetMetricSeries() error = %v", err)
+	}
+	if len(attestations) != 2 || attestations[0].Value != 14000 || attestations[1].Value != -3000 || !attestations[1].Time.Equal(time.Unix(384, 0)) {
+		t.Errorf("GetMetricSeries(attestation_reward:42) = %+v, want 14000 and -3000 Gwei", attestations)
+	}
+
+	if metrics := ethService.Metrics(); metrics[len(metrics)-1] != "attestation_reward:42" {
+		t.Errorf("Metrics() = %v, want attestation_reward:42 last", metrics)
+	}
+	for _, metric := range []string{"unknown", "attestation_reward", "block_reward:42"} {
+		if _, err := ethService.GetMetricSeries(ctx, metric, from, to, 0); !errors.Is(err, service.ErrUnknownMetric) {
+			t.Errorf("GetMetricSeries(%s) error = %v, want ErrUnknownMetric", metric, err)
+		}
+	}
+	if _, err := ethService.GetMetricSeries(ctx, service.MetricBlockReward, to, from, 0); !errors.Is(err, service.ErrInvalidRange) {
+		t.Errorf("GetMetricSeries() with inverted range error = %v, want ErrInvalidRange", err)
+	}
+}
+
+func TestHandler_Grafana(t *testing.T) {
+	beacon := &mockBeaconClient{responses: map[string]string{
+		"/eth/v1/beacon/genesis": `{"data":{"genesis_time":"0"}}`,
+	}}
+	ethService := service.NewEthereumServiceWithClients(beacon, &mockExecutionClient{})
+	ethService.Index().PutReward(10, &service.BlockReward{Status: "mev", Reward: big.NewInt(2e9)})
+	ethService.Index().PutAttestation(42, &service.AttestationPerformance{Epoch: 0, Reward: 14000})
+	h := handler.NewHandler(ethService)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/grafana", h.GrafanaTest)
+	router.POST("/grafana/search", h.GrafanaSearch)
+	router.POST("/grafana/query", h.GrafanaQuery)
+
+	post := func(path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+		return w
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/grafana", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 for the connection test, got %d", w.Code)
+	}
+
+	w = post("/grafana/search", `{"target":"attestation"}`)
+	var metrics []string
+	if err := json.Unmarshal(w.Body.Bytes(), &metrics); err != nil || len(metrics) != 1 || metrics[0] != "attestation_reward:42" {
+		t.Errorf("Expected only attestation_reward:42 to be found, got %s", w.Body.String())
+	}
+
+	query := `{"range":{"from":"1970-01-01T00:00:00Z","to":"1970-01-01T01:00:00Z"},"targets":[{"target":"block_reward","refId":"A"},{"target":"attestation_reward:42","refId":"B","type":"table"}]}`
+	w = post("/grafana/query", query)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var response []json.RawMessage
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil || len(response) != 2 {
+		t.Fatalf("Expected two series, got %s", w.Body.String())
+	}
+	var series v1.GrafanaSeriesResponse
+	if err := json.Unmarshal(response[0], &series); err != nil {
+		t.Fatalf("Failed to decode series: %v", err)
+	}
+	if series.Target != "block_reward" || len(series.Datapoints) != 1 || series.Datapoints[0] != [2]float64{2, 120000} {
+		t.Errorf("Expected block_reward to be [[2, 120000]], got %+v", series)
+	}
+	var table v1.GrafanaTableResponse
+	if err := json.Unmarshal(response[1], &table); err != nil {
+		t.Fatalf("Failed to decode table: %v", err)
+	}
+	if table.Type != "table" || table.RefID != "B" || len(table.Rows) != 1 || table.Rows[0] != [2]float64{0, 14000} {
+		t.Errorf("Expected attestation_reward:42 as a table with row [0, 14000], got %+v", table)
+	}
+
+	w = post("/grafana/query", strings.Replace(query, "block_reward", "unknown", 1))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an unknown metric, got %d", w.Code)
+	}
+}
@@ -0,0 +1,55 @@
+package tests
+
+import (
+	"ethereum-validator-api/service"
+	"testing"
+)
+
+func TestDecodeGraffiti(t *testing.T) {
+	tests := []struct {
+		name     string
+		graffiti string
+		want     string
+	}{
+		{
+			name:     "Zero padded text",
+			graffiti: "0x4c69676874686f7573652f76342e352e30000000000000000000000000000000",
+			want:     "Lighthouse/v4.5.0",
+		},
+		{
+			name:     "Empty graffiti",
+			graffiti: "0x0000000000000000000000000000000000000000000000000000000000000000",
+			want:     "",
+		},
+		{
+			name:     "Invalid hex",
+			graffiti: "0xzz",
+			want:     "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := service.DecodeGraffiti(tt.graffiti); got != tt.want {
+				t.Errorf("DecodeGraffiti() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBlockIndex_SearchGraffiti(t *testing.T) {
+	index := service.NewBlockIndex()
+	index.Put(&service.BlockDetail{Slot: 100, Graffiti: "Lighthouse/v4.5.0"})
+	index.Put(&service.BlockDetail{Slot: 101, Graffiti: "solo staker"})
+	index.Put(&service.BlockDetail{Slot: 102, Graffiti: "lighthouse"})
+
+	got := index.SearchGraffiti("LIGHTHOUSE", 0, 1000)
+	if len(got) != 2 || got[0].Slot != 100 || got[1].Slot != 102 {
+		t.Errorf("SearchGraffiti() returned %d blocks, want slots 100 and 102", len(got))
+	}
+
+	got = index.SearchGraffiti("lighthouse", 101, 1000)
+	if len(got) != 1 || got[0].Slot != 102 {
+		t.Errorf("SearchGraffiti() with range returned %d blocks, want slot 102", len(got))
+	}
+}
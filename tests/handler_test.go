@@ -0,0 +1,398 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"ethereum-validator-api/api/models/v1"
+	"ethereum-validator-api/handler"
+	"ethereum-validator-api/service"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// mockDataSource serves canned results to the handlers, methods it doesn't override panic through the nil interface
+type mockDataSource struct {
+	handler.EthereumDataSource
+	head         *service.ChainHead
+	reward       *service.BlockReward
+	rewardErr    error
+	committee    *service.SyncCommittee
+	committeeErr error
+
+	validatorReward    *service.ValidatorBlockReward
+	validatorRewardErr error
+
+	duties []service.ValidatorDuty
+}
+
+func (m *mockDataSource) GetChainHead(ctx context.Context) (*service.ChainHead, error) {
+	return m.head, nil
+}
+
+func (m *mockDataSource) GetBlockRewardBySlot(ctx context.Context, slot int64) (*service.BlockReward, error) {
+	return m.reward, m.rewardErr
+}
+
+func (m *mockDataSource) GetSyncCommitteeBySlot(ctx context.Context, slot int64) (*service.SyncCommittee, error) {
+	return m.committee, m.committeeErr
+}
+
+func (m *mockDataSource) GetValidatorBlockRewardBySlot(ctx context.Context, validatorID string, slot int64) (*service.ValidatorBlockReward, error) {
+	return m.validatorReward, m.validatorRewardErr
+}
+
+func (m *mockDataSource) GetValidatorLatestBlockReward(ctx context.Context, validatorID string) (*service.ValidatorBlockReward, error) {
+	return m.validatorReward, m.validatorRewardErr
+}
+
+func (m *mockDataSource) GetValidatorDuties(ctx context.Context, validatorID string, epochs int64) ([]service.ValidatorDuty, error) {
+	return m.duties, nil
+}
+
+func (m *mockDataSource) VerificationEnabled() bool {
+	return false
+}
+
+func TestHandler_GetBlockReward(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		slot       string
+		reward     *service.BlockReward
+		rewardErr  error
+		wantCode   int
+		wantError  string
+		wantRetry  string
+		wantStatus string
+	}{
+		{
+			name:       "Reward",
+			slot:       "8000",
+			reward:     &service.BlockReward{Status: "mev", Reward: big.NewInt(123456), Builder: "Flashbots", Finalized: true},
+			wantCode:   http.StatusOK,
+			wantStatus: "mev",
+		},
+		{name: "Invalid slot", slot: "abc", wantCode: http.StatusBadRequest, wantError: "Invalid slot number"},
+		{name: "Future slot", slot: "8000", rewardErr: service.ErrFutureSlot, wantCode: http.StatusBadRequest, wantError: "Slot is in the future"},
+		{name: "Missed slot", slot: "8000", rewardErr: service.ErrSlotNotFound, wantCode: http.StatusNotFound, wantError: "Slot does not exist"},
+		{name: "Upstream rate limited", slot: "8000", rewardErr: fmt.Errorf("%w: backing off", service.ErrUpstreamRateLimited), wantCode: http.StatusTooManyRequests, wantError: "Upstream provider is rate limiting requests, retry later"},
+		{name: "Upstream backing off", slot: "8000", rewardErr: fmt.Errorf("%w", &service.RateLimitError{RetryAfter: 2500 * time.Millisecond}), wantCode: http.StatusTooManyRequests, wantError: "Upstream provider is rate limiting requests, retry later", wantRetry: "3"},
+		{name: "Upstream invalid response", slot: "8000", rewardErr: fmt.Errorf("%w: unexpected EOF", service.ErrUpstreamInvalidResponse), wantCode: http.StatusBadGateway, wantError: "Upstream returned an invalid response"},
+		{name: "Upstream not synced", slot: "8000", rewardErr: fmt.Errorf("%w: node is syncing", service.ErrNotSynced), wantCode: http.StatusServiceUnavailable, wantError: "Upstream node is not synced, retry later"},
+		{name: "Upstream failure", slot: "8000", rewardErr: errors.New("connection refused"), wantCode: http.StatusInternalServerError, wantError: "Internal server error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler.NewHandler(&mockDataSource{
+				head:      &service.ChainHead{Slot: 8192},
+				reward:    tt.reward,
+				rewardErr: tt.rewardErr,
+			})
+			router := gin.New()
+			router.GET("/blockreward/:slot", h.GetBlockReward)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/blockreward/"+tt.slot, nil))
+			if w.Code != tt.wantCode {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantCode, w.Code, w.Body.String())
+			}
+
+			if tt.wantCode != http.StatusOK {
+				var response v1.ErrorResponse
+				if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+					t.Fatalf("Failed to decode response: %v", err)
+				}
+				if response.Error != tt.wantError {
+					t.Errorf("Expected error %q, got %q", tt.wantError, response.Error)
+				}
+				if tt.wantRetry != "" && w.Header().Get("Retry-After") != tt.wantRetry {
+					t.Errorf("Expected Retry-After %q, got %q", tt.wantRetry, w.Header().Get("Retry-After"))
+				}
+				return
+			}
+
+			var response v1.BlockRewardResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if response.Status != tt.wantStatus || response.Reward != "123456" || response.Builder != "Flashbots" || !response.Finalized {
+				t.Errorf("Unexpected response: %+v", response)
+			}
+		})
+	}
+}
+
+func TestHandler_GetValidatorBlockReward(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	reward := &service.ValidatorBlockReward{
+		Slot:           8000,
+		ValidatorIndex: 42,
+		BlockReward:    &service.BlockReward{Status: "vanilla", Reward: big.NewInt(123456)},
+	}
+
+	tests := []struct {
+		name      string
+		path      string
+		rewardErr error
+		wantCode  int
+		wantError string
+	}{
+		{name: "Reward", path: "/validator/42/blockreward/8000", wantCode: http.StatusOK},
+		{name: "Latest reward", path: "/validator/42/blockreward/latest", wantCode: http.StatusOK},
+		{name: "Invalid slot", path: "/validator/42/blockreward/abc", wantCode: http.StatusBadRequest, wantError: "Invalid slot number"},
+		{name: "Invalid validator", path: "/validator/abc/blockreward/8000", rewardErr: service.ErrInvalidValidatorID, wantCode: http.StatusBadRequest, wantError: "Invalid validator index or pubkey"},
+		{name: "Proposed by another validator", path: "/validator/42/blockreward/8000", rewardErr: fmt.Errorf("%w", &service.ProposerMismatchError{Slot: 8000, ProposerIndex: 7}), wantCode: http.StatusNotFound, wantError: "Validator did not propose this slot"},
+		{name: "Unknown validator", path: "/validator/0x" + strings.Repeat("ab", 48) + "/blockreward/8000", rewardErr: service.ErrValidatorNotFound, wantCode: http.StatusNotFound, wantError: "Validator does not exist"},
+		{name: "Missed slot", path: "/validator/42/blockreward/8000", rewardErr: service.ErrSlotNotFound, wantCode: http.StatusNotFound, wantError: "Slot does not exist"},
+		{name: "Unknown validator of the latest reward", path: "/validator/0x" + strings.Repeat("ab", 48) + "/blockreward/latest", rewardErr: service.ErrValidatorNotFound, wantCode: http.StatusNotFound, wantError: "Validator does not exist"},
+		{name: "No recent proposal", path: "/validator/42/blockreward/latest", rewardErr: fmt.Errorf("%w in the last 16 epochs", service.ErrNoRecentProposal), wantCode: http.StatusNotFound, wantError: "No recent proposal found for validator"},
+		{name: "Recent proposals not indexed", path: "/validator/42/blockreward/latest", rewardErr: fmt.Errorf("%w: checked back to slot 7900", service.ErrProposalLookupIncomplete), wantCode: http.StatusServiceUnavailable, wantError: "Recent proposals are not indexed yet, retry later"},
+		{name: "Upstream failure", path: "/validator/42/blockreward/latest", rewardErr: errors.New("connection refused"), wantCode: http.StatusInternalServerError, wantError: "Internal server error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler.NewHandler(&mockDataSource{
+				head:               &service.ChainHead{Slot: 8192},
+				validatorReward:    reward,
+				validatorRewardErr: tt.rewardErr,
+			})
+			router := gin.New()
+			router.GET("/validator/:id/blockreward/latest", h.GetValidatorLatestBlockReward)
+			router.GET("/validator/:id/blockreward/:slot", h.GetValidatorBlockReward)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if w.Code != tt.wantCode {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantCode, w.Code, w.Body.String())
+			}
+
+			if tt.wantCode != http.StatusOK {
+				var response v1.ProposerMismatchResponse
+				if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+					t.Fatalf("Failed to decode response: %v", err)
+				}
+				if response.Error != tt.wantError {
+					t.Errorf("Expected error %q, got %q", tt.wantError, response.Error)
+				}
+				var mismatch *service.ProposerMismatchError
+				if errors.As(tt.rewardErr, &mismatch) && (response.Slot != 8000 || response.ProposerIndex != 7) {
+					t.Errorf("Expected slot 8000 proposed by validator 7, got %+v", response)
+				}
+				return
+			}
+
+			var response v1.ValidatorBlockRewardResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if response.Slot != 8000 || response.ValidatorIndex != 42 || response.Reward != "123456" {
+				t.Errorf("Unexpected response: %+v", response)
+			}
+		})
+	}
+}
+
+func TestHandler_GetSyncDuties(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	committee := &service.SyncCommittee{
+		SyncPeriod: 1,
+		StartSlot:  8192,
+		EndSlot:    16383,
+		Members: []service.SyncCommitteeMember{
+			{Index: 1, Pubkey: "0xaa"},
+			{Index: 2, Pubkey: "0xbb"},
+			{Index: 3, Pubkey: "0xcc"},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		query        string
+		committeeErr error
+		wantCode     int
+		wantIndices  []int64
+		wantTotal    int
+		wantOffset   int
+	}{
+		{name: "Committee", query: "", wantCode: http.StatusOK, wantIndices: []int64{1, 2, 3}, wantTotal: 3, wantOffset: 0},
+		{name: "Filtered and paginated", query: "?validators=0xAA,0xcc&offset=1", wantCode: http.StatusOK, wantIndices: []int64{3}, wantTotal: 2, wantOffset: 1},
+		{name: "Filtered with spaces", query: "?validators=0xbb,%200xCC%20", wantCode: http.StatusOK, wantIndices: []int64{2, 3}, wantTotal: 2, wantOffset: 0},
+		{name: "Filter without members", query: "?validators=0xdd", wantCode: http.StatusOK, wantIndices: []int64{}, wantTotal: 0, wantOffset: 0},
+		{name: "Limited", query: "?limit=2", wantCode: http.StatusOK, wantIndices: []int64{1, 2}, wantTotal: 3, wantOffset: 0},
+		{name: "Offset and limit", query: "?offset=1&limit=1", wantCode: http.StatusOK, wantIndices: []int64{2}, wantTotal: 3, wantOffset: 1},
+		{name: "Offset past the end", query: "?offset=10", wantCode: http.StatusOK, wantIndices: []int64{}, wantTotal: 3, wantOffset: 3},
+		{name: "Largest limit", query: "?limit=512", wantCode: http.StatusOK, wantIndices: []int64{1, 2, 3}, wantTotal: 3, wantOffset: 0},
+		{name: "Invalid limit", query: "?limit=0", wantCode: http.StatusBadRequest},
+		{name: "Limit above the committee size", query: "?limit=513", wantCode: http.StatusBadRequest},
+		{name: "Limit not a number", query: "?limit=ten", wantCode: http.StatusBadRequest},
+		{name: "Negative offset", query: "?offset=-1", wantCode: http.StatusBadRequest},
+		{name: "Offset not a number", query: "?offset=one", wantCode: http.StatusBadRequest},
+		{name: "Missed slot", committeeErr: service.ErrSlotNotFound, wantCode: http.StatusNotFound},
+		{name: "Upstream timeout", committeeErr: service.ErrUpstreamTimeout, wantCode: http.StatusGatewayTimeout},
+		{name: "Upstream rate limited", committeeErr: service.ErrUpstreamRateLimited, wantCode: http.StatusTooManyRequests},
+		{name: "Upstream not synced", committeeErr: service.ErrNotSynced, wantCode: http.StatusServiceUnavailable},
+		{name: "Upstream failure", committeeErr: errors.New("connection refused"), wantCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler.NewHandler(&mockDataSource{
+				head:         &service.ChainHead{Slot: 8192},
+				committee:    committee,
+				committeeErr: tt.committeeErr,
+			})
+			router := gin.New()
+			router.GET("/syncduties/:slot", h.GetSyncDuties)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/syncduties/8192"+tt.query, nil))
+			if w.Code != tt.wantCode {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantCode, w.Code, w.Body.String())
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+
+			var response v1.SyncDutiesResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if len(response.ValidatorIndices) != len(tt.wantIndices) {
+				t.Fatalf("Expected indices %v, got %v", tt.wantIndices, response.ValidatorIndices)
+			}
+			for i, index := range tt.wantIndices {
+				if response.ValidatorIndices[i] != index {
+					t.Errorf("Expected indices %v, got %v", tt.wantIndices, response.ValidatorIndices)
+				}
+			}
+			if response.Total != tt.wantTotal || response.Offset != tt.wantOffset {
+				t.Errorf("Expected total %d and offset %d, got %d and %d", tt.wantTotal, tt.wantOffset, response.Total, response.Offset)
+			}
+			if response.SyncInfo.CommitteeSize != 3 || response.SyncInfo.StartSlot != 8192 {
+				t.Errorf("Unexpected sync info: %+v", response.SyncInfo)
+			}
+		})
+	}
+}
+
+func TestHandler_GetValidatorDutiesCalendar(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	proposal := service.ValidatorDuty{Kind: service.DutyProposal, StartSlot: 9000, EndSlot: 9000, Start: start, End: start.Add(12 * time.Second)}
+	// Times are rendered in UTC whatever their location
+	syncStart := time.Date(2024, 5, 2, 2, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	sync := service.ValidatorDuty{Kind: service.DutySyncCommittee, StartSlot: 16384, EndSlot: 24575, Start: syncStart, End: syncStart.Add(27 * time.Hour)}
+	header := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//ethereum-validator-api//duties//EN",
+		"CALSCALE:GREGORIAN",
+		"X-WR-CALNAME:Validator 42 duties",
+	}
+
+	tests := []struct {
+		name      string
+		query     string
+		duties    []service.ValidatorDuty
+		wantCode  int
+		wantLines []string // Lines of the calendar without DTSTAMP
+	}{
+		{
+			name:      "No duties",
+			wantCode:  http.StatusOK,
+			wantLines: append(slices.Clone(header), "END:VCALENDAR"),
+		},
+		{
+			name:     "Proposal",
+			duties:   []service.ValidatorDuty{proposal},
+			wantCode: http.StatusOK,
+			wantLines: append(slices.Clone(header),
+				"BEGIN:VEVENT",
+				"UID:42-proposal-9000@ethereum-validator-api",
+				"DTSTART:20240501T120000Z",
+				"DTEND:20240501T120012Z",
+				"SUMMARY:Block proposal (slot 9000)",
+				"END:VEVENT",
+				"END:VCALENDAR",
+			),
+		},
+		{
+			name:     "Proposal and sync committee",
+			query:    "?epochs=512",
+			duties:   []service.ValidatorDuty{proposal, sync},
+			wantCode: http.StatusOK,
+			wantLines: append(slices.Clone(header),
+				"BEGIN:VEVENT",
+				"UID:42-proposal-9000@ethereum-validator-api",
+				"DTSTART:20240501T120000Z",
+				"DTEND:20240501T120012Z",
+				"SUMMARY:Block proposal (slot 9000)",
+				"END:VEVENT",
+				"BEGIN:VEVENT",
+				"UID:42-sync_committee-16384@ethereum-validator-api",
+				"DTSTART:20240502T000000Z",
+				"DTEND:20240503T030000Z",
+				"SUMMARY:Sync committee (slots 16384-24575)",
+				"END:VEVENT",
+				"END:VCALENDAR",
+			),
+		},
+		{name: "No epochs", query: "?epochs=0", wantCode: http.StatusBadRequest},
+		{name: "Too many epochs", query: "?epochs=513", wantCode: http.StatusBadRequest},
+		{name: "Epochs not a number", query: "?epochs=two", wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler.NewHandler(&mockDataSource{duties: tt.duties})
+			router := gin.New()
+			router.GET("/validator/:id/duties.ics", h.GetValidatorDutiesCalendar)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/validator/42/duties.ics"+tt.query, nil))
+			if w.Code != tt.wantCode {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantCode, w.Code, w.Body.String())
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			if contentType := w.Header().Get("Content-Type"); contentType != "text/calendar; charset=utf-8" {
+				t.Errorf("Expected a text/calendar content type, got %q", contentType)
+			}
+
+			// RFC 5545 lines end with CRLF
+			body := w.Body.String()
+			if !strings.HasSuffix(body, "\r\n") || strings.Contains(strings.ReplaceAll(body, "\r\n", ""), "\n") {
+				t.Fatalf("Expected every line to end with CRLF, got %q", body)
+			}
+			var lines []string
+			for _, line := range strings.Split(strings.TrimSuffix(body, "\r\n"), "\r\n") {
+				if stamp, ok := strings.CutPrefix(line, "DTSTAMP:"); ok {
+					if _, err := time.Parse("20060102T150405Z", stamp); err != nil {
+						t.Errorf("Expected a UTC DTSTAMP, got %q", line)
+					}
+					continue
+				}
+				lines = append(lines, line)
+			}
+			if !slices.Equal(lines, tt.wantLines) {
+				t.Errorf("Expected calendar\n%s\ngot\n%s", strings.Join(tt.wantLines, "\n"), strings.Join(lines, "\n"))
+			}
+			if stamps := strings.Count(body, "DTSTAMP:"); stamps != len(tt.duties) {
+				t.Errorf("Expected a DTSTAMP per event, got %d for %d events", stamps, len(tt.duties))
+			}
+		})
+	}
+}
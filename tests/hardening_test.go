@@ -0,0 +1,73 @@
+package tests
+
+import (
+	"ethereum-validator-api/utils"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestApplyHardening(t *testing.T) {
+	t.Setenv("MAX_BODY_BYTES", "16")
+	t.Setenv("HSTS_ENABLED", "")
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	if err := utils.ApplyHardening(router); err != nil {
+		t.Fatalf("ApplyHardening failed: %v", err)
+	}
+	router.GET("/resource", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	router.POST("/resource", func(c *gin.Context) {
+		if _, err := io.ReadAll(c.Request.Body); err != nil {
+			c.Status(http.StatusRequestEntityTooLarge)
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+
+	tests := []struct {
+		name        string
+		method      string
+		body        string
+		contentType string
+		chunked     bool
+		wantCode    int
+	}{
+		{name: "GET without body", method: http.MethodGet, wantCode: http.StatusOK},
+		{name: "JSON body", method: http.MethodPost, body: `{"a":1}`, contentType: "application/json; charset=utf-8", wantCode: http.StatusOK},
+		{name: "Form body", method: http.MethodPost, body: "a=1", contentType: "application/x-www-form-urlencoded", wantCode: http.StatusUnsupportedMediaType},
+		{name: "Body too large", method: http.MethodPost, body: `{"a":"0123456789abcdef"}`, contentType: "application/json", wantCode: http.StatusRequestEntityTooLarge},
+		{name: "Chunked body too large", method: http.MethodPost, body: `{"a":"0123456789abcdef"}`, contentType: "application/json", chunked: true, wantCode: http.StatusRequestEntityTooLarge},
+		{name: "Method not allowed", method: http.MethodDelete, wantCode: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/resource", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			if tt.chunked {
+				req.ContentLength = -1
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("Expected status %d, got %d", tt.wantCode, w.Code)
+			}
+			if got := w.Header().Get("X-Content-Type-Options"); got != "nosniff" {
+				t.Errorf("Expected X-Content-Type-Options nosniff, got %q", got)
+			}
+			if got := w.Header().Get("Strict-Transport-Security"); got != "" {
+				t.Errorf("Expected no HSTS header on plain HTTP, got %q", got)
+			}
+		})
+	}
+}
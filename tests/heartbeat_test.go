@@ -0,0 +1,96 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"ethereum-validator-api/service"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHeartbeatPublisher(t *testing.T) {
+	// Mock beacon node reporting a head slot
+	beacon := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{"header":{"message":{"slot":"4700000"}}}}`))
+	}))
+	defer beacon.Close()
+
+	ethService, err := service.NewEthereumService(beacon.URL)
+	if err != nil {
+		t.Fatalf("Failed to create EthereumService: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	received := make(chan *http.Request, 1)
+	var summary service.HealthSummary
+	monitor := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if got, want := r.Header.Get("X-Signature-256"), "sha256="+service.SignPayload("secret", body); got != want {
+			t.Errorf("X-Signature-256 = %s, want %s", got, want)
+		}
+		if err := json.Unmarshal(body, &summary); err != nil {
+			t.Errorf("Failed to decode heartbeat: %v", err)
+		}
+		received <- r
+		cancel()
+	}))
+	defer monitor.Close()
+
+	publisher, err := service.NewHeartbeatPublisher(ethService, monitor.URL, "secret", time.Hour)
+	if err != nil {
+		t.Fatalf("Failed to create HeartbeatPublisher: %v", err)
+	}
+	publisher.Run(ctx)
+
+	select {
+	case <-received:
+	default:
+		t.Fatal("Heartbeat was not published")
+	}
+	if summary.Status != "ok" || summary.HeadSlot != 4700000 {
+		t.Errorf("Heartbeat summary = %+v, want status ok at head slot 4700000", summary)
+	}
+}
+
+func TestHealthSummary_ErrorRateWindow(t *testing.T) {
+	// The node serves its head, every other request fails
+	beacon := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/eth/v1/beacon/headers/head" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{"data":{"header":{"message":{"slot":"4700000"}}}}`))
+	}))
+	defer beacon.Close()
+
+	ethService, err := service.NewEthereumService(beacon.URL)
+	if err != nil {
+		t.Fatalf("Failed to create EthereumService: %v", err)
+	}
+	clock := newMockClock(time.Now())
+	ethService.SetClock(clock)
+
+	if _, err := ethService.GetChainSpec(context.Background()); err == nil {
+		t.Fatal("GetChainSpec() succeeded against a failing node")
+	}
+	summary := ethService.GetHealthSummary(context.Background())
+	if summary.UpstreamFailures == 0 || summary.ErrorRate <= 0 || summary.ErrorRate >= 1 {
+		t.Fatalf("GetHealthSummary() = %d failures at an error rate of %v, want failures at a rate between 0 and 1", summary.UpstreamFailures, summary.ErrorRate)
+	}
+	failures := summary.UpstreamFailures
+
+	// Once the failures left the window only the recent requests count, the totals still cover them
+	clock.Advance(6 * time.Minute)
+	summary = ethService.GetHealthSummary(context.Background())
+	if summary.ErrorRate != 0 {
+		t.Errorf("GetHealthSummary() error rate = %v after the failures left the window, want 0", summary.ErrorRate)
+	}
+	if summary.UpstreamFailures != failures {
+		t.Errorf("GetHealthSummary() failures = %d, want the %d failures since startup", summary.UpstreamFailures, failures)
+	}
+}
@@ -0,0 +1,68 @@
+package tests
+
+import (
+	"context"
+	"ethereum-validator-api/service"
+	"testing"
+	"time"
+)
+
+// slowBeaconClient delays every response of the wrapped client
+type slowBeaconClient struct {
+	mockBeaconClient
+	latency time.Duration
+}
+
+func (m *slowBeaconClient) GetJSON(ctx context.Context, path string, out interface{}) error {
+	select {
+	case <-time.After(m.latency):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return m.mockBeaconClient.GetJSON(ctx, path, out)
+}
+
+func TestHedgedBeaconClient_GetJSON(t *testing.T) {
+	const path = "/eth/v1/beacon/genesis"
+	provider := func(latency time.Duration, answer string) *slowBeaconClient {
+		responses := map[string]string{}
+		if answer != "" {
+			responses[path] = `{"data":{"genesis_time":"` + answer + `"}}`
+		}
+		return &slowBeaconClient{mockBeaconClient: mockBeaconClient{responses: responses}, latency: latency}
+	}
+
+	tests := []struct {
+		name      string
+		primary   *slowBeaconClient
+		secondary *slowBeaconClient
+		want      string
+	}{
+		{name: "Fast primary", primary: provider(0, "1"), secondary: provider(0, "2"), want: "1"},
+		{name: "Slow primary", primary: provider(time.Second, "1"), secondary: provider(0, "2"), want: "2"},
+		{name: "Failing primary", primary: provider(0, ""), secondary: provider(0, "2"), want: "2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := service.NewHedgedBeaconClient(tt.primary, tt.secondary, 50*time.Millisecond)
+
+			var genesis struct {
+				Data struct {
+					GenesisTime string `json:"genesis_time"`
+				} `json:"data"`
+			}
+			start := time.Now()
+			if err := client.GetJSON(context.Background(), path, &genesis); err != nil {
+				t.Fatalf("GetJSON() unexpected error: %v", err)
+			}
+
+			if genesis.Data.GenesisTime != tt.want {
+				t.Errorf("GetJSON() answered by provider %s, want %s", genesis.Data.GenesisTime, tt.want)
+			}
+			if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+				t.Errorf("GetJSON() took %v, want the fastest provider's latency", elapsed)
+			}
+		})
+	}
+}
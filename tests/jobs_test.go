@@ -0,0 +1,166 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"ethereum-validator-api/api/models/v1"
+	"ethereum-validator-api/handler"
+	"ethereum-validator-api/service"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestBlockRewardJobs(t *testing.T) {
+	h := handler.NewHandler(service.NewEthereumServiceWithClients(&mockBeaconClient{}, &mockExecutionClient{}))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/jobs/blockrewards", h.PostBlockRewardJob)
+	router.GET("/jobs/:id", h.GetJob)
+
+	submit := func(body, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/jobs/blockrewards", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if key != "" {
+			req.Header.Set("Idempotency-Key", key)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := submit(`{"from_slot":100,"to_slot":103}`, "key-1")
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("Expected status 202, got %d: %s", w.Code, w.Body.String())
+	}
+	var job v1.BlockRewardJobResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &job); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if w.Header().Get("Location") != "/jobs/"+job.ID {
+		t.Errorf("Expected Location /jobs/%s, got %q", job.ID, w.Header().Get("Location"))
+	}
+
+	// Repeating the request returns the same job
+	w = submit(`{"from_slot":100,"to_slot":103}`, "key-1")
+	var repeated v1.BlockRewardJobResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &repeated); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if w.Code != http.StatusOK || repeated.ID != job.ID {
+		t.Errorf("Expected the existing job %s with status 200, got %s with %d", job.ID, repeated.ID, w.Code)
+	}
+
+	if w := submit(`{"from_slot":100,"to_slot":200}`, "key-1"); w.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 for a reused key, got %d", w.Code)
+	}
+	if w := submit(`{"from_slot":200,"to_slot":100}`, ""); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for a reversed range, got %d", w.Code)
+	}
+	if w := submit(`{"from_slot":0,"to_slot":20000}`, ""); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for a too large range, got %d", w.Code)
+	}
+
+	// The mock beacon node knows no blocks, so every slot is reported as missed
+	deadline := time.Now().Add(5 * time.Second)
+	for job.Status != "completed" {
+		if time.Now().After(deadline) {
+			t.Fatalf("Job did not complete, status %s", job.Status)
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/jobs/"+job.ID, nil))
+		if err := json.Unmarshal(w.Body.Bytes(), &job); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+	}
+	if job.Processed != 4 || len(job.Results) != 4 || job.Progress != 1 {
+		t.Fatalf("Expected 4 processed slots, got %+v", job)
+	}
+	for _, result := range job.Results {
+		if !result.Missed {
+			t.Errorf("Expected slot %d to be missed, got %+v", result.Slot, result)
+		}
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/jobs/unknown", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for an unknown job, got %d", w.Code)
+	}
+}
+
+// blockingBeaconClient holds every request until release is closed
+type blockingBeaconClient struct {
+	mockBeaconClient
+	release chan struct{}
+}
+
+func (b *blockingBeaconClient) GetJSON(ctx context.Context, path string, out interface{}) error {
+	<-b.release
+	return b.mockBeaconClient.GetJSON(ctx, path, out)
+}
+
+func TestBlockRewardJobLimits(t *testing.T) {
+	beacon := &blockingBeaconClient{release: make(chan struct{})}
+	defer close(beacon.release)
+	h := handler.NewHandler(service.NewEthereumServiceWithClients(beacon, &mockExecutionClient{}))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/jobs/blockrewards", h.PostBlockRewardJob)
+
+	submit := func(clientIP, key string) (*httptest.ResponseRecorder, v1.BlockRewardJobResponse) {
+		req := httptest.NewRequest(http.MethodPost, "/jobs/blockrewards", strings.NewReader(`{"from_slot":100,"to_slot":101}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = clientIP + ":1234"
+		if key != "" {
+			req.Header.Set("Idempotency-Key", key)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		var job v1.BlockRewardJobResponse
+		json.Unmarshal(w.Body.Bytes(), &job)
+		return w, job
+	}
+
+	w, first := submit("192.0.2.1", "shared-key")
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("Expected status 202, got %d: %s", w.Code, w.Body.String())
+	}
+	if w, _ := submit("192.0.2.1", ""); w.Code != http.StatusAccepted {
+		t.Fatalf("Expected status 202 for the second job, got %d", w.Code)
+	}
+	if w, _ := submit("192.0.2.1", ""); w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") == "" {
+		t.Errorf("Expected status 429 with Retry-After for a third unfinished job, got %d", w.Code)
+	}
+
+	// Idempotency keys are scoped to the client
+	w, other := submit("192.0.2.2", "shared-key")
+	if w.Code != http.StatusAccepted || other.ID == first.ID {
+		t.Errorf("Expected a new job for another client reusing the key, got %d with job %s", w.Code, other.ID)
+	}
+
+	// The queue takes a bounded number of jobs while the workers are busy
+	full := false
+	for i := 1; i <= 40 && !full; i++ {
+		w, _ := submit(fmt.Sprintf("198.51.100.%d", i), "")
+		switch w.Code {
+		case http.StatusAccepted:
+		case http.StatusTooManyRequests:
+			full = true
+		default:
+			t.Fatalf("Expected status 202 or 429, got %d: %s", w.Code, w.Body.String())
+		}
+	}
+	if !full {
+		t.Error("Expected submissions to be rejected once the job queue is full")
+	}
+}
@@ -0,0 +1,267 @@
+package tests
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"ethereum-validator-api/api/models/v1"
+	"ethereum-validator-api/handler"
+	"ethereum-validator-api/service"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/altair"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/gin-gonic/gin"
+	"github.com/prysmaticlabs/go-bitfield"
+)
+
+const lightClientGenesisValidatorsRoot = "0x4b363db94e286120d76eb905340fdd4e54bfe9f06bf33ff6cf5ad27f511bfe95"
+
+// hashHeader returns the hash tree root of a beacon block header
+func hashHeader(t *testing.T, header *phase0.BeaconBlockHeader) phase0.Root {
+	t.Helper()
+	root, err := header.HashTreeRoot()
+	if err != nil {
+		t.Fatalf("Failed to hash header: %v", err)
+	}
+	return root
+}
+
+// proveLeaf returns a Merkle branch for a leaf at a subtree index of the given depth and the root it proves
+func proveLeaf(leaf phase0.Root, index uint64, depth int) (phase0.Root, []phase0.Root) {
+	branch := make([]phase0.Root, depth)
+	value := leaf
+	for i := range branch {
+		branch[i] = phase0.Root{byte(i + 1)}
+		if index>>i&1 == 1 {
+			value = sha256.Sum256(append(branch[i][:], value[:]...))
+		} else {
+			value = sha256.Sum256(append(value[:], branch[i][:]...))
+		}
+	}
+	return value, branch
+}
+
+// signSyncCommitteeMessage signs a block root as the sync committee members with the given summed secret keys would
+func signSyncCommitteeMessage(t *testing.T, root phase0.Root, secret *big.Int) phase0.BLSSignature {
+	t.Helper()
+	genesisValidatorsRoot := phase0.Root{}
+	json.Unmarshal([]byte(`"`+lightClientGenesisValidatorsRoot+`"`), &genesisValidatorsRoot)
+	forkDataRoot, err := (&phase0.ForkData{CurrentVersion: phase0.Version{0x04}, GenesisValidatorsRoot: genesisValidatorsRoot}).HashTreeRoot()
+	if err != nil {
+		t.Fatalf("Failed to hash fork data: %v", err)
+	}
+	domain := phase0.Domain{0x07}
+	copy(domain[4:], forkDataRoot[:28])
+	signingRoot, err := (&phase0.SigningData{ObjectRoot: root, Domain: domain}).HashTreeRoot()
+	if err != nil {
+		t.Fatalf("Failed to hash signing data: %v", err)
+	}
+
+	hash, err := bls.HashToG2(signingRoot[:], []byte("BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_"))
+	if err != nil {
+		t.Fatalf("Failed to hash to curve: %v", err)
+	}
+	var signature bls.G2Affine
+	signature.ScalarMultiplication(&hash, secret)
+	return phase0.BLSSignature(signature.Bytes())
+}
+
+// lightClientBeacon returns a beacon serving a bootstrap for a committee whose members have the secret keys 1 to 512,
+// a finality update and an optimistic update signed by all members with the given summed secret keys, and the headers
+// between the optimistic header and the block at slot 8294. It returns the beacon, the checkpoint and the root of the
+// header at slot 8294
+func lightClientBeacon(t *testing.T, secret *big.Int) (*mockBeaconClient, string, phase0.Root) {
+	t.Helper()
+	_, _, generator, _ := bls.Generators()
+	committee := &altair.SyncCommittee{Pubkeys: make([]phase0.BLSPubKey, service.SyncCommitteeSize)}
+	sum := new(big.Int)
+	for i := range committee.Pubkeys {
+		key := big.NewInt(int64(i + 1))
+		sum.Add(sum, key)
+		var pubkey bls.G1Affine
+		pubkey.ScalarMultiplication(&generator, key)
+		committee.Pubkeys[i] = phase0.BLSPubKey(pubkey.Bytes())
+	}
+	var aggregate bls.G1Affine
+	aggregate.ScalarMultiplication(&generator, sum)
+	committee.AggregatePubkey = phase0.BLSPubKey(aggregate.Bytes())
+	committeeRoot, err := committee.HashTreeRoot()
+	if err != nil {
+		t.Fatalf("Failed to hash sync committee: %v", err)
+	}
+
+	// Bootstrap from a checkpoint in period 1
+	bootstrapState, committeeBranch := proveLeaf(committeeRoot, 22, 5)
+	bootstrapHeader := &phase0.BeaconBlockHeader{Slot: 8224, ProposerIndex: 1, StateRoot: bootstrapState}
+	checkpoint := hashHeader(t, bootstrapHeader)
+
+	// The finality update finalizes slot 8256 with a header attested at slot 8290
+	finalizedHeader := &phase0.BeaconBlockHeader{Slot: 8256, ProposerIndex: 2, ParentRoot: checkpoint}
+	finalityState, finalityBranch := proveLeaf(hashHeader(t, finalizedHeader), 41, 6)
+	attestedHeader := &phase0.BeaconBlockHeader{Slot: 8290, ProposerIndex: 3, StateRoot: finalityState}
+
+	// The optimistic header at slot 8296 descends from the block at slot 8294, slot 8295 was missed
+	targetHeader := &phase0.BeaconBlockHeader{Slot: 8294, ProposerIndex: 4, ParentRoot: hashHeader(t, attestedHeader), BodyRoot: phase0.Root{0x94}}
+	target := hashHeader(t, targetHeader)
+	optimisticHeader := &phase0.BeaconBlockHeader{Slot: 8296, ProposerIndex: 5, ParentRoot: target, BodyRoot: phase0.Root{0x96}}
+	optimistic := hashHeader(t, optimisticHeader)
+
+	bits := bitfield.NewBitvector512()
+	for i := uint64(0); i < service.SyncCommitteeSize; i++ {
+		bits.SetBitAt(i, true)
+	}
+	encode := func(value interface{}) string {
+		data, err := json.Marshal(value)
+		if err != nil {
+			t.Fatalf("Failed to encode: %v", err)
+		}
+		return string(data)
+	}
+	headerResponse := func(root phase0.Root, header *phase0.BeaconBlockHeader) string {
+		return encode(map[string]interface{}{"data": map[string]interface{}{
+			"root":   root.String(),
+			"header": map[string]interface{}{"message": header},
+		}})
+	}
+
+	beacon := &mockBeaconClient{responses: map[string]string{
+		"/eth/v1/beacon/genesis":                        `{"data":{"genesis_time":"1606824023","genesis_validators_root":"` + lightClientGenesisValidatorsRoot + `"}}`,
+		"/eth/v1/config/fork_schedule":                  `{"data":[{"previous_version":"0x00000000","current_version":"0x00000000","epoch":"0"},{"previous_version":"0x00000000","current_version":"0x04000000","epoch":"200"}]}`,
+		"/eth/v1/beacon/headers/head":                   `{"data":{"root":"0xabc","header":{"message":{"slot":"8297"}}}}`,
+		"/eth/v1/beacon/headers/" + optimistic.String(): headerResponse(optimistic, optimisticHeader),
+		"/eth/v1/beacon/headers/" + target.String():     headerResponse(target, targetHeader),
+		"/eth/v1/beacon/light_client/bootstrap/" + checkpoint.String(): encode(map[string]interface{}{"data": map[string]interface{}{
+			"header":                        map[string]interface{}{"beacon": bootstrapHeader},
+			"current_sync_committee":        committee,
+			"current_sync_committee_branch": committeeBranch,
+		}}),
+		"/eth/v1/beacon/light_client/finality_update": encode(map[string]interface{}{"data": map[string]interface{}{
+			"attested_header":  map[string]interface{}{"beacon": attestedHeader},
+			"finalized_header": map[string]interface{}{"beacon": finalizedHeader},
+			"finality_branch":  finalityBranch,
+			"sync_aggregate":   &altair.SyncAggregate{SyncCommitteeBits: bits, SyncCommitteeSignature: signSyncCommitteeMessage(t, hashHeader(t, attestedHeader), secret)},
+			"signature_slot":   "8291",
+		}}),
+		"/eth/v1/beacon/light_client/optimistic_update": encode(map[string]interface{}{"data": map[string]interface{}{
+			"attested_header": map[string]interface{}{"beacon": optimisticHeader},
+			"sync_aggregate":  &altair.SyncAggregate{SyncCommitteeBits: bits, SyncCommitteeSignature: signSyncCommitteeMessage(t, optimistic, secret)},
+			"signature_slot":  "8297",
+		}}),
+	}}
+	return beacon, checkpoint.String(), target
+}
+
+func TestLightClientVerifiesBlockRoots(t *testing.T) {
+	// Members have the secret keys 1 to 512
+	beacon, checkpoint, target := lightClientBeacon(t, big.NewInt(512*513/2))
+
+	ethService := service.NewEthereumServiceWithClients(beacon, &mockExecutionClient{})
+	if _, err := ethService.VerifyBlockRoot(context.Background(), 8294, target.String()); !errors.Is(err, service.ErrLightClientDisabled) {
+		t.Errorf("VerifyBlockRoot() error = %v, want %v", err, service.ErrLightClientDisabled)
+	}
+	if err := ethService.EnableLightClient("0x1234"); err == nil {
+		t.Error("EnableLightClient() expected error for an invalid checkpoint")
+	}
+	if err := ethService.EnableLightClient(checkpoint); err != nil {
+		t.Fatalf("EnableLightClient() unexpected error: %v", err)
+	}
+	if err := ethService.LightClientTask(service.Every(time.Minute)).Run(context.Background()); err != nil {
+		t.Fatalf("LightClientTask() unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		slot         int64
+		root         string
+		wantVerified bool
+		wantErr      error
+	}{
+		{"Checkpoint", 8224, checkpoint, true, nil},
+		{"Ancestor of the optimistic header", 8294, target.String(), true, nil},
+		{"Root contradicting the verified chain", 8294, "0x" + fmt.Sprintf("%064x", 1), false, service.ErrInconsistentResponse},
+		{"Block in a missed slot", 8295, "0x" + fmt.Sprintf("%064x", 1), false, service.ErrInconsistentResponse},
+		{"Slot out of reach", 8000, "0x" + fmt.Sprintf("%064x", 1), false, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verified, err := ethService.VerifyBlockRoot(context.Background(), tt.slot, tt.root)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("VerifyBlockRoot() error = %v, want %v", err, tt.wantErr)
+			}
+			if verified != tt.wantVerified {
+				t.Errorf("VerifyBlockRoot() = %v, want %v", verified, tt.wantVerified)
+			}
+		})
+	}
+}
+
+func TestLightClientRejectsInvalidSignatures(t *testing.T) {
+	// One member signed with another key
+	beacon, checkpoint, _ := lightClientBeacon(t, big.NewInt(512*513/2+1))
+
+	ethService := service.NewEthereumServiceWithClients(beacon, &mockExecutionClient{})
+	if err := ethService.EnableLightClient(checkpoint); err != nil {
+		t.Fatalf("EnableLightClient() unexpected error: %v", err)
+	}
+	if err := ethService.LightClientTask(service.Every(time.Minute)).Run(context.Background()); !errors.Is(err, service.ErrInconsistentResponse) {
+		t.Errorf("LightClientTask() error = %v, want %v", err, service.ErrInconsistentResponse)
+	}
+}
+
+func TestGetBlockReportsLightClientVerification(t *testing.T) {
+	beacon, checkpoint, target := lightClientBeacon(t, big.NewInt(512*513/2))
+	beacon.responses["/eth/v1/beacon/states/head/finality_checkpoints"] = `{"data":{"finalized":{"epoch":"258","root":"0x01"}}}`
+	beacon.responses["/eth/v2/beacon/blocks/8294"] = beaconBlockAt(8294, 150, "0x"+fmt.Sprintf("%064x", 150))
+	beacon.responses["/eth/v1/beacon/blocks/8294/root"] = `{"data":{"root":"` + target.String() + `"}}`
+
+	ethService := service.NewEthereumServiceWithClients(beacon, &mockExecutionClient{})
+	h := handler.NewHandler(ethService)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/block/:slot", h.GetBlock)
+
+	// The flag is only reported while the light client is enabled
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/block/8294", nil))
+	var response v1.BlockDetailResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Verified != nil {
+		t.Errorf("Expected no verified flag while disabled, got %v", *response.Verified)
+	}
+
+	if err := ethService.EnableLightClient(checkpoint); err != nil {
+		t.Fatalf("EnableLightClient() unexpected error: %v", err)
+	}
+	if err := ethService.LightClientTask(service.Every(time.Minute)).Run(context.Background()); err != nil {
+		t.Fatalf("LightClientTask() unexpected error: %v", err)
+	}
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/block/8294", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Verified == nil || !*response.Verified {
+		t.Errorf("Expected the block to be verified, got %v", response.Verified)
+	}
+
+	// A block root the verified chain doesn't contain is rejected
+	beacon.responses["/eth/v1/beacon/blocks/8294/root"] = `{"data":{"root":"0x` + fmt.Sprintf("%064x", 1) + `"}}`
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/block/8294", nil))
+	if w.Code != http.StatusBadGateway {
+		t.Errorf("Expected status 502, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,114 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"ethereum-validator-api/service"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// localNodeServer serves the Beacon API and JSON-RPC of a node reporting version, counting the Lighthouse and standard
+// participation requests. Without inclusion the Lighthouse endpoint is missing
+func localNodeServer(t *testing.T, version string, inclusion bool, lighthouseCalls, rewardCalls *atomic.Int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/eth/v1/node/version":
+			w.Write([]byte(`{"data":{"version":"` + version + `"}}`))
+		case r.URL.Path == "/eth/v1/beacon/states/head/validators":
+			w.Write([]byte(`{"data":[{"validator":{"effective_balance":"32000000000"}},{"validator":{"effective_balance":"32000000000"}}]}`))
+		case r.URL.Path == "/eth/v1/beacon/headers/head":
+			w.Write([]byte(`{"data":{"header":{"message":{"slot":"320"}}}}`))
+		case strings.HasPrefix(r.URL.Path, "/lighthouse/validator_inclusion/"):
+			lighthouseCalls.Add(1)
+			if !inclusion {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"code":404,"message":"NOT_FOUND"}`))
+				return
+			}
+			w.Write([]byte(`{"data":{"current_epoch_active_gwei":"64000000000","current_epoch_target_attesting_gwei":"48000000000"}}`))
+		case strings.HasPrefix(r.URL.Path, "/eth/v1/beacon/rewards/attestations/"):
+			rewardCalls.Add(1)
+			w.Write([]byte(`{"data":{"total_rewards":[{"target":"10"},{"target":"0"}]}}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/":
+			var call struct {
+				ID json.RawMessage `json:"id"`
+			}
+			json.NewDecoder(r.Body).Decode(&call)
+			json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": call.ID, "result": "0x1"})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestEthereumService_LocalNode(t *testing.T) {
+	// A local node is not rate limited
+	service.SetUpstreamRateLimit(math.MaxFloat64, 1)
+	defer service.SetUpstreamRateLimit(1, 1)
+
+	tests := []struct {
+		name           string
+		version        string
+		local          bool
+		inclusion      bool
+		wantRate       float64
+		wantLighthouse int32
+		wantRewards    int32
+	}{
+		{"Lighthouse inclusion", "Lighthouse/v5.3.0-d6ba8c3/x86_64-linux", true, true, 0.75, 1, 0},
+		{"Lighthouse inclusion missing", "Lighthouse/v5.3.0-d6ba8c3/x86_64-linux", true, false, 0.5, 1, 1},
+		{"Other client", "teku/v24.10.0/linux-x86_64", true, true, 0.5, 0, 1},
+		{"Remote provider", "Lighthouse/v5.3.0-d6ba8c3/x86_64-linux", false, true, 0.5, 0, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var lighthouseCalls, rewardCalls atomic.Int32
+			server := localNodeServer(t, tt.version, tt.inclusion, &lighthouseCalls, &rewardCalls)
+
+			ethService := service.NewDisconnectedEthereumService()
+			ethService.EnableLocalNode(tt.local)
+			if err := ethService.ConnectUpstreams(context.Background(), service.UpstreamConfig{RPCURL: server.URL, BeaconURL: server.URL}); err != nil {
+				t.Fatalf("ConnectUpstreams() error = %v", err)
+			}
+			if got := ethService.BeaconNodeVersion(); got != tt.version {
+				t.Errorf("BeaconNodeVersion() = %q, want %q", got, tt.version)
+			}
+
+			if err := ethService.NetworkStatsTask(service.Every(0)).Run(context.Background()); err != nil {
+				t.Fatalf("Network stats task error = %v", err)
+			}
+			stats, err := ethService.GetNetworkStats()
+			if err != nil {
+				t.Fatalf("GetNetworkStats() error = %v", err)
+			}
+			if len(stats.Participation) != 1 || stats.Participation[0].Rate != tt.wantRate {
+				t.Errorf("Participation = %+v, want rate %v", stats.Participation, tt.wantRate)
+			}
+			if lighthouseCalls.Load() != tt.wantLighthouse || rewardCalls.Load() != tt.wantRewards {
+				t.Errorf("Lighthouse/standard requests = %d/%d, want %d/%d", lighthouseCalls.Load(), rewardCalls.Load(), tt.wantLighthouse, tt.wantRewards)
+			}
+		})
+	}
+}
+
+func TestEthereumService_LocalNodeExecutionHeads(t *testing.T) {
+	// HTTP endpoints can't stream new heads, the indexer keeps following the beacon node and the ticker
+	server := localNodeServer(t, "Lighthouse/v5.3.0", true, new(atomic.Int32), new(atomic.Int32))
+	client, err := service.NewRPCExecutionClient(context.Background(), server.URL, http.DefaultClient)
+	if err != nil {
+		t.Fatalf("NewRPCExecutionClient() error = %v", err)
+	}
+	if err := client.SubscribeNewHeads(context.Background(), func(uint64) {}); err == nil {
+		t.Error("SubscribeNewHeads() over HTTP expected error")
+	}
+}
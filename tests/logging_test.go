@@ -0,0 +1,118 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"ethereum-validator-api/service"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRedactSecrets(t *testing.T) {
+	// Registered provider URLs are redacted even where their API key isn't recognizable
+	service.RegisterSecretURLs("https://example.quiknode.pro/short/key/")
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "Registered provider URL",
+			text: `Get "https://example.quiknode.pro/short/key/eth/v1/node/version": dial tcp: i/o timeout`,
+			want: `Get "https://example.quiknode.pro/[REDACTED]/eth/v1/node/version": dial tcp: i/o timeout`,
+		},
+		{
+			name: "API key in path",
+			text: "Upstreams: failed to reach https://mainnet.infura.io/v3/9aa3d95b3bc440fa88ea12eaa4456161",
+			want: "Upstreams: failed to reach https://mainnet.infura.io/v3/[REDACTED]",
+		},
+		{
+			name: "API key in query",
+			text: "dial wss://rpc.example.com/ws?apikey=secret&network=mainnet failed",
+			want: "dial wss://rpc.example.com/ws?apikey=[REDACTED]&network=mainnet failed",
+		},
+		{
+			name: "Beacon API path",
+			text: "http://localhost:5052/eth/v1/beacon/states/head/validators/0x8f2a3b4c5d6e7f8091a2b3c4d5e6f708?status=active",
+			want: "http://localhost:5052/eth/v1/beacon/states/head/validators/0x8f2a3b4c5d6e7f8091a2b3c4d5e6f708?status=active",
+		},
+		{
+			name: "No URL",
+			text: "Indexer: failed to index slot 4700000",
+			want: "Indexer: failed to index slot 4700000",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := service.RedactSecrets(tt.text); got != tt.want {
+				t.Errorf("RedactSecrets() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactingWriter(t *testing.T) {
+	var out bytes.Buffer
+	logger := log.New(service.NewRedactingWriter(&out), "", 0)
+	logger.Printf("Heartbeat: failed to post to https://monitor.example.com/push/a1b2c3d4e5f6a7b8c9d0")
+	if got := out.String(); got != "Heartbeat: failed to post to https://monitor.example.com/push/[REDACTED]\n" {
+		t.Errorf("Logged %q, want the API key redacted", got)
+	}
+}
+
+func TestUpstreamErrorRedacted(t *testing.T) {
+	service.SetUpstreamRateLimit(100, 10)
+	defer service.SetUpstreamRateLimit(1, 1)
+
+	// Nothing listens on port 1, the transport error names the URL
+	client := service.NewHTTPBeaconClient("http://127.0.0.1:1/9aa3d95b3bc440fa88ea12eaa4456161", http.DefaultClient)
+	var out struct{}
+	err := client.GetJSON(context.Background(), "/eth/v1/node/version", &out)
+	if err == nil {
+		t.Fatal("GetJSON() expected an error")
+	}
+	if strings.Contains(err.Error(), "9aa3d95b3bc440fa88ea12eaa4456161") || !strings.Contains(err.Error(), "127.0.0.1:1/[REDACTED]/eth/v1/node/version") {
+		t.Errorf("GetJSON() error = %v, want the API key redacted", err)
+	}
+}
+
+func TestLogSampler(t *testing.T) {
+	var out bytes.Buffer
+	log.SetOutput(&out)
+	log.SetFlags(0)
+	defer log.SetFlags(log.LstdFlags)
+	defer log.SetOutput(os.Stderr)
+
+	sampler := service.NewLogSampler(2, 50*time.Millisecond)
+	for i := 1; i <= 5; i++ {
+		sampler.Printf("cache refresh", "Cache: failed to refresh slot:%d", i)
+	}
+	sampler.Printf("indexer slot", "Indexer: failed to index slot 1")
+	if got := out.String(); got != "Cache: failed to refresh slot:1\nCache: failed to refresh slot:2\nIndexer: failed to index slot 1\n" {
+		t.Fatalf("Logged %q, want the first 2 lines of each kind", got)
+	}
+
+	// The next interval reports how many lines were dropped
+	out.Reset()
+	time.Sleep(60 * time.Millisecond)
+	sampler.Printf("cache refresh", "Cache: failed to refresh slot:%d", 6)
+	if got := out.String(); got != "Cache: failed to refresh slot:6 (3 similar lines suppressed)\n" {
+		t.Errorf("Logged %q, want the suppressed lines counted", got)
+	}
+
+	// Without an interval every line is logged
+	out.Reset()
+	unsampled := service.NewLogSampler(1, 0)
+	for i := 0; i < 3; i++ {
+		unsampled.Printf("cache refresh", "Cache: failed")
+	}
+	if got := strings.Count(out.String(), "\n"); got != 3 {
+		t.Errorf("Logged %d lines without sampling, want 3", got)
+	}
+}
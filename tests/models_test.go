@@ -0,0 +1,72 @@
+package tests
+
+import (
+	"encoding/json"
+	"ethereum-validator-api/api/models/v1"
+	"ethereum-validator-api/service"
+	"math/big"
+	"testing"
+)
+
+// TestModelsContract pins the JSON of the v1 models built from service types, so internal changes that would alter
+// the public contract fail here instead of reaching clients
+func TestModelsContract(t *testing.T) {
+	percentile := 87.5
+	reward := &service.BlockReward{
+		Status:          "mev",
+		Reward:          big.NewInt(123456),
+		RewardWei:       big.NewInt(123456789000000),
+		Builder:         "Flashbots",
+		ExtraData:       "0x496c6c756d696e617465",
+		FeeRecipient:    "0x388c818ca8b9251b393131c08a736a67ccb19297",
+		ValuePercentile: &percentile,
+		Relay:           "https://boost-relay.flashbots.net",
+		Finalized:       true,
+	}
+
+	tests := []struct {
+		name  string
+		model interface{}
+		want  string
+	}{
+		{
+			name:  "block reward",
+			model: v1.NewBlockRewardResponse(reward, v1.UnitGwei),
+			want: `{"status":"mev","reward":"123456.789","unit":"gwei","reward_gwei":123456,"builder":"Flashbots",` +
+				`"extra_data":"0x496c6c756d696e617465","fee_recipient":"0x388c818ca8b9251b393131c08a736a67ccb19297",` +
+				`"value_percentile":87.5,"finalized":true,"block_info":{"proposer_payment":"123456.789",` +
+				`"proposer_payment_gwei":123456,"is_mev_boost":true}}`,
+		},
+		{
+			name:  "block reward without fees",
+			model: v1.NewBlockRewardResponse(&service.BlockReward{Status: "vanilla", Reward: big.NewInt(0), RewardWei: big.NewInt(0)}, v1.UnitGwei),
+			want: `{"status":"vanilla","reward":"0","unit":"gwei","reward_gwei":0,"extra_data":"","fee_recipient":"",` +
+				`"finalized":false,"block_info":{"proposer_payment":"0","proposer_payment_gwei":0,"is_mev_boost":false}}`,
+		},
+		{
+			name: "validator proposal",
+			model: v1.NewValidatorProposalResponse(service.ValidatorProposal{
+				Slot: 4700000, Epoch: 146875, Outcome: "proposed", BlockRoot: "0xabc", Reward: reward,
+			}, v1.UnitGwei),
+			want: `{"slot":4700000,"epoch":146875,"outcome":"proposed","block_root":"0xabc","reward":"123456.789","unit":"gwei",` +
+				`"status":"mev","builder":"Flashbots","relay":"https://boost-relay.flashbots.net","reward_gwei":123456}`,
+		},
+		{
+			name:  "relay bid",
+			model: v1.NewRelayBidResponse(&service.RelayBid{Relay: "https://relay.example", BuilderPubkey: "0xa1", BlockHash: "0x5e", Value: big.NewInt(52064115720813510)}),
+			want:  `{"relay":"https://relay.example","builder_pubkey":"0xa1","block_hash":"0x5e","value":"52064115720813510"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.model)
+			if err != nil {
+				t.Fatalf("json.Marshal() error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("JSON contract changed\n got: %s\nwant: %s", got, tt.want)
+			}
+		})
+	}
+}
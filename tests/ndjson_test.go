@@ -0,0 +1,81 @@
+package tests
+
+import (
+	"encoding/json"
+	"ethereum-validator-api/api/models/v1"
+	"ethereum-validator-api/handler"
+	"ethereum-validator-api/service"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestNDJSONStreaming(t *testing.T) {
+	beacon := &mockBeaconClient{responses: map[string]string{
+		"/eth/v1/beacon/headers/head":                     `{"data":{"root":"0xhead","header":{"message":{"slot":"100"}}}}`,
+		"/eth/v1/beacon/states/head/finality_checkpoints": `{"data":{"finalized":{"epoch":"1","root":"0x01"}}}`,
+		"/eth/v1/validator/duties/proposer/0":             `{"data":[{"validator_index":"7","slot":"5"}]}`,
+		"/eth/v1/validator/duties/proposer/1":             `{"data":[{"validator_index":"7","slot":"40"}]}`,
+	}}
+	ethService := service.NewEthereumServiceWithClients(beacon, &mockExecutionClient{})
+	ethService.Index().Put(&service.BlockDetail{Slot: 10, Graffiti: "Lighthouse"})
+	ethService.Index().Put(&service.BlockDetail{Slot: 11, Graffiti: "lighthouse/v5"})
+	h := handler.NewHandler(ethService)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/graffiti/search", h.SearchGraffiti)
+	router.GET("/validator/:id/proposals", h.GetValidatorProposals)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Accept", "application/x-ndjson")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	lines := func(w *httptest.ResponseRecorder) []string {
+		return strings.Split(strings.TrimSuffix(w.Body.String(), "\n"), "\n")
+	}
+
+	w := get("/graffiti/search?q=lighthouse")
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "application/x-ndjson" {
+		t.Fatalf("Expected an NDJSON stream, got %d %q", w.Code, w.Header().Get("Content-Type"))
+	}
+	rows := lines(w)
+	if len(rows) != 2 {
+		t.Fatalf("Expected one line per match, got %q", w.Body.String())
+	}
+	var match v1.GraffitiMatch
+	if err := json.Unmarshal([]byte(rows[1]), &match); err != nil || match.Slot != 11 {
+		t.Errorf("Expected the second line to be the match at slot 11, got %q (%v)", rows[1], err)
+	}
+
+	// The empty stream is still a successful response
+	if w := get("/graffiti/search?q=prysm"); w.Code != http.StatusOK || w.Body.Len() != 0 {
+		t.Errorf("Expected an empty stream, got %d %q", w.Code, w.Body.String())
+	}
+
+	// Errors before the first row keep their status code
+	if w := get("/validator/7/proposals?from_epoch=3&to_epoch=2"); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for a reversed range, got %d", w.Code)
+	}
+
+	// Errors after the first row end the stream with an error line, the missed proposal at slot 5 was sent already
+	w = get("/validator/7/proposals?from_epoch=0&to_epoch=2")
+	rows = lines(w)
+	if w.Code != http.StatusOK || len(rows) != 3 {
+		t.Fatalf("Expected two proposals and an error line, got %d %q", w.Code, w.Body.String())
+	}
+	var proposal v1.ValidatorProposalResponse
+	if err := json.Unmarshal([]byte(rows[0]), &proposal); err != nil || proposal.Slot != 5 || proposal.Outcome != service.ProposalMissed {
+		t.Errorf("Expected the missed proposal at slot 5 first, got %q (%v)", rows[0], err)
+	}
+	var failure v1.ErrorResponse
+	if err := json.Unmarshal([]byte(rows[2]), &failure); err != nil || failure.Error == "" {
+		t.Errorf("Expected a final error line, got %q (%v)", rows[2], err)
+	}
+}
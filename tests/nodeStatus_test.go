@@ -0,0 +1,123 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"ethereum-validator-api/api/models/v1"
+	"ethereum-validator-api/handler"
+	"ethereum-validator-api/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// nodeBeaconClient serves the node endpoints of a synced Lighthouse beacon node
+func nodeBeaconClient() *mockBeaconClient {
+	return &mockBeaconClient{responses: map[string]string{
+		"/eth/v1/node/syncing":    `{"data":{"head_slot":"4700000","sync_distance":"1","is_syncing":false,"is_optimistic":true,"el_offline":false}}`,
+		"/eth/v1/node/version":    `{"data":{"version":"Lighthouse/v5.3.0-d6ba8c3/x86_64-linux"}}`,
+		"/eth/v1/node/peer_count": `{"data":{"connected":"80","connecting":"2","disconnected":"120","disconnecting":"1"}}`,
+	}}
+}
+
+func TestEthereumService_GetNodeSyncing(t *testing.T) {
+	tests := []struct {
+		name    string
+		syncing string
+		want    service.ExecutionSyncing
+	}{
+		{"Synced", `false`, service.ExecutionSyncing{CurrentBlock: 0x12a05f2, HighestBlock: 0x12a05f2}},
+		{"Syncing", `{"startingBlock":"0x0","currentBlock":"0x100","highestBlock":"0x200"}`, service.ExecutionSyncing{IsSyncing: true, CurrentBlock: 0x100, HighestBlock: 0x200}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			execution := &mockExecutionClient{results: map[string]string{"eth_syncing": tt.syncing, "eth_blockNumber": `"0x12a05f2"`}}
+			ethService := service.NewEthereumServiceWithClients(nodeBeaconClient(), execution)
+
+			got, err := ethService.GetNodeSyncing(context.Background())
+			if err != nil {
+				t.Fatalf("GetNodeSyncing() error = %v", err)
+			}
+			if got.Beacon != (service.BeaconSyncing{HeadSlot: 4700000, SyncDistance: 1, IsOptimistic: true}) {
+				t.Errorf("GetNodeSyncing() beacon = %+v", got.Beacon)
+			}
+			if got.Execution != tt.want {
+				t.Errorf("GetNodeSyncing() execution = %+v, want %+v", got.Execution, tt.want)
+			}
+		})
+	}
+}
+
+func TestEthereumService_GetNodeVersionAndPeers(t *testing.T) {
+	execution := &mockExecutionClient{results: map[string]string{
+		"web3_clientVersion": `"Geth/v1.14.11-stable/linux-amd64/go1.23.2"`,
+		"net_peerCount":      `"0x32"`,
+	}}
+	ethService := service.NewEthereumServiceWithClients(nodeBeaconClient(), execution)
+
+	version, err := ethService.GetNodeVersion(context.Background())
+	if err != nil {
+		t.Fatalf("GetNodeVersion() error = %v", err)
+	}
+	if version.Beacon != "Lighthouse/v5.3.0-d6ba8c3/x86_64-linux" || version.Execution != "Geth/v1.14.11-stable/linux-amd64/go1.23.2" {
+		t.Errorf("GetNodeVersion() = %+v", version)
+	}
+	if got := ethService.ConsensusClient(); got != service.ClientLighthouse {
+		t.Errorf("ConsensusClient() after GetNodeVersion() = %q, want lighthouse", got)
+	}
+
+	peers, err := ethService.GetNodePeers(context.Background())
+	if err != nil {
+		t.Fatalf("GetNodePeers() error = %v", err)
+	}
+	want := service.NodePeers{BeaconConnected: 80, BeaconConnecting: 2, BeaconDisconnected: 120, BeaconDisconnecting: 1, ExecutionConnected: 50}
+	if *peers != want {
+		t.Errorf("GetNodePeers() = %+v, want %+v", *peers, want)
+	}
+
+	// Peer counts are cached briefly, a node that stopped answering is still reported from the cache
+	delete(execution.results, "net_peerCount")
+	if _, err := ethService.GetNodePeers(context.Background()); err != nil {
+		t.Errorf("GetNodePeers() expected a cached result, got %v", err)
+	}
+}
+
+func TestHandler_NodeEndpoints(t *testing.T) {
+	execution := &mockExecutionClient{results: map[string]string{
+		"eth_syncing":        `false`,
+		"eth_blockNumber":    `"0x10"`,
+		"web3_clientVersion": `"Geth/v1.14.11-stable/linux-amd64/go1.23.2"`,
+	}}
+	h := handler.NewHandler(service.NewEthereumServiceWithClients(nodeBeaconClient(), execution))
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/node/syncing", h.GetNodeSyncing)
+	router.GET("/node/version", h.GetNodeVersion)
+	router.GET("/node/peers", h.GetNodePeers)
+
+	get := func(path string, out interface{}) int {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if err := json.Unmarshal(w.Body.Bytes(), out); err != nil {
+			t.Fatalf("Failed to unmarshal response of %s: %v", path, err)
+		}
+		return w.Code
+	}
+
+	var syncing v1.NodeSyncingResponse
+	if code := get("/node/syncing", &syncing); code != http.StatusOK || syncing.Beacon.HeadSlot != 4700000 || syncing.Execution.CurrentBlock != 16 {
+		t.Errorf("GET /node/syncing = %d %+v", code, syncing)
+	}
+	var version v1.NodeVersionResponse
+	if code := get("/node/version", &version); code != http.StatusOK || version.Execution != "Geth/v1.14.11-stable/linux-amd64/go1.23.2" {
+		t.Errorf("GET /node/version = %d %+v", code, version)
+	}
+
+	// A failing execution node fails the request like other upstream errors
+	var errorResponse v1.ErrorResponse
+	if code := get("/node/peers", &errorResponse); code != http.StatusInternalServerError {
+		t.Errorf("GET /node/peers without net_peerCount = %d %+v, want 500", code, errorResponse)
+	}
+}
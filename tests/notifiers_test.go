@@ -0,0 +1,78 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"ethereum-validator-api/service"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNotifiers(t *testing.T) {
+	var path string
+	var body map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		body = nil
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Failed to decode notification: %v", err)
+		}
+		if strings.Contains(path, "rejected") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	notification := service.Notification{Title: "Validator 42 missed a proposal", Message: "Slot 4700000 was missed"}
+	want := "Validator 42 missed a proposal\nSlot 4700000 was missed"
+
+	telegram := service.NewTelegramNotifier(server.Client(), server.URL, "123:secret", "-100200")
+	if err := telegram.Notify(context.Background(), notification); err != nil {
+		t.Fatalf("Telegram Notify() error = %v", err)
+	}
+	if path != "/bot123:secret/sendMessage" || body["chat_id"] != "-100200" || body["text"] != want {
+		t.Errorf("Telegram sent %v to %s, want the message to chat -100200 via sendMessage", body, path)
+	}
+
+	discord, err := service.NewDiscordNotifier(server.Client(), server.URL+"/api/webhooks/1/token")
+	if err != nil {
+		t.Fatalf("NewDiscordNotifier() error = %v", err)
+	}
+	if err := discord.Notify(context.Background(), notification); err != nil {
+		t.Fatalf("Discord Notify() error = %v", err)
+	}
+	if path != "/api/webhooks/1/token" || body["content"] != want {
+		t.Errorf("Discord sent %v to %s, want the message as content", body, path)
+	}
+
+	rejected := service.NewTelegramNotifier(server.Client(), server.URL, "rejected", "1")
+	if err := rejected.Notify(context.Background(), notification); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("Notify() error = %v, want the rejection reported", err)
+	}
+
+	// Errors must not leak the bot token of the URL
+	unreachable := service.NewTelegramNotifier(server.Client(), "http://127.0.0.1:1", "123:secret", "1")
+	if err := unreachable.Notify(context.Background(), notification); err == nil || strings.Contains(err.Error(), "secret") {
+		t.Errorf("Notify() error = %v, want an error without the token", err)
+	}
+}
+
+func TestParseNotifier(t *testing.T) {
+	for channel, ok := range map[string]bool{
+		"telegram:123456:ABC-def:-100200":               true,
+		"discord:https://discord.com/api/webhooks/1/ab": true,
+		"telegram:-100200":                              false,
+		"telegram:123456:ABC-def:":                      false,
+		"discord:not-a-url":                             false,
+		"slack:https://hooks.slack.com/x":               false,
+	} {
+		_, err := service.ParseNotifier(http.DefaultClient, channel)
+		if (err == nil) != ok {
+			t.Errorf("ParseNotifier(%q) error = %v, want ok %v", channel, err, ok)
+		}
+	}
+}
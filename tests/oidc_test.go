@@ -0,0 +1,226 @@
+package tests
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"ethereum-validator-api/handler"
+	"ethereum-validator-api/service"
+	"github.com/gin-gonic/gin"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const (
+	oidcIssuer   = "https://login.example.com/"
+	oidcAudience = "validator-api"
+)
+
+// oidcProvider serves a JSON Web Key Set and signs tokens with its keys
+type oidcProvider struct {
+	server  *httptest.Server
+	fetches atomic.Int32
+	mu      sync.Mutex
+	keys    []map[string]string
+}
+
+func newOIDCProvider(t *testing.T) *oidcProvider {
+	provider := &oidcProvider{}
+	provider.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		provider.fetches.Add(1)
+		provider.mu.Lock()
+		defer provider.mu.Unlock()
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": provider.keys})
+	}))
+	t.Cleanup(provider.server.Close)
+	return provider
+}
+
+// publishRSA adds an RSA key to the key set
+func (p *oidcProvider) publishRSA(kid string, key *rsa.PrivateKey) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.keys = append(p.keys, map[string]string{
+		"kty": "RSA", "kid": kid, "use": "sig",
+		"n": base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	})
+}
+
+// publishEC adds a P-256 key to the key set
+func (p *oidcProvider) publishEC(kid string, key *ecdsa.PrivateKey) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.keys = append(p.keys, map[string]string{
+		"kty": "EC", "kid": kid, "crv": "P-256",
+		"x": base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, 32))),
+		"y": base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, 32))),
+	})
+}
+
+// signJWT creates a token with the given header and claims, signed with an RSA or P-256 key
+func signJWT(t *testing.T, header, claims map[string]interface{}, key crypto.Signer) string {
+	encode := func(value interface{}) string {
+		data, err := json.Marshal(value)
+		if err != nil {
+			t.Fatalf("Failed to encode token part: %v", err)
+		}
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	signed := encode(header) + "." + encode(claims)
+	digest := sha256.Sum256([]byte(signed))
+
+	var signature []byte
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		var err error
+		if signature, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:]); err != nil {
+			t.Fatalf("Failed to sign token: %v", err)
+		}
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+		if err != nil {
+			t.Fatalf("Failed to sign token: %v", err)
+		}
+		signature = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestAuthenticate_JWT(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate RSA key: %v", err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate EC key: %v", err)
+	}
+	rotatedKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate RSA key: %v", err)
+	}
+
+	provider := newOIDCProvider(t)
+	provider.publishRSA("rsa-1", rsaKey)
+	provider.publishEC("ec-1", ecKey)
+
+	verifier, err := service.NewJWTVerifier(service.OIDCConfig{Issuer: oidcIssuer, Audience: oidcAudience, JWKSURL: provider.server.URL})
+	if err != nil {
+		t.Fatalf("NewJWTVerifier() unexpected error: %v", err)
+	}
+	clock := newMockClock(time.Now())
+	verifier.SetClock(clock)
+
+	ethService := service.NewEthereumServiceWithClients(&mockBeaconClient{}, &mockExecutionClient{})
+	h := handler.NewHandler(ethService)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(h.TrackUsage())
+	router.GET("/admin/ping", handler.Authenticate("secret", verifier), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	send := func(authorization string) int {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/admin/ping", nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	claims := func(changes map[string]interface{}) map[string]interface{} {
+		claims := map[string]interface{}{
+			"iss": oidcIssuer,
+			"sub": "alice@example.com",
+			"aud": []string{"other-api", oidcAudience},
+			"exp": clock.Now().Add(time.Hour).Unix(),
+		}
+		for name, value := range changes {
+			claims[name] = value
+		}
+		return claims
+	}
+	rs256 := map[string]interface{}{"alg": "RS256", "kid": "rsa-1"}
+
+	tests := []struct {
+		name     string
+		token    string
+		wantCode int
+	}{
+		{name: "RS256", token: signJWT(t, rs256, claims(nil), rsaKey), wantCode: http.StatusOK},
+		{name: "ES256", token: signJWT(t, map[string]interface{}{"alg": "ES256", "kid": "ec-1"}, claims(map[string]interface{}{"aud": oidcAudience}), ecKey), wantCode: http.StatusOK},
+		{name: "Expired", token: signJWT(t, rs256, claims(map[string]interface{}{"exp": clock.Now().Add(-time.Hour).Unix()}), rsaKey), wantCode: http.StatusUnauthorized},
+		{name: "Not valid yet", token: signJWT(t, rs256, claims(map[string]interface{}{"nbf": clock.Now().Add(time.Hour).Unix()}), rsaKey), wantCode: http.StatusUnauthorized},
+		{name: "Without expiry", token: signJWT(t, rs256, claims(map[string]interface{}{"exp": nil}), rsaKey), wantCode: http.StatusUnauthorized},
+		{name: "Other issuer", token: signJWT(t, rs256, claims(map[string]interface{}{"iss": "https://evil.example.com/"}), rsaKey), wantCode: http.StatusUnauthorized},
+		{name: "Other audience", token: signJWT(t, rs256, claims(map[string]interface{}{"aud": "other-api"}), rsaKey), wantCode: http.StatusUnauthorized},
+		{name: "Signed with another key", token: signJWT(t, rs256, claims(nil), rotatedKey), wantCode: http.StatusUnauthorized},
+		{name: "Key of another algorithm", token: signJWT(t, map[string]interface{}{"alg": "RS256", "kid": "ec-1"}, claims(nil), rsaKey), wantCode: http.StatusUnauthorized},
+		{name: "Unsigned", token: signJWT(t, map[string]interface{}{"alg": "none", "kid": "rsa-1"}, claims(nil), rsaKey), wantCode: http.StatusUnauthorized},
+		{name: "Malformed", token: "not-a-token", wantCode: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := send("Bearer " + tt.token); code != tt.wantCode {
+				t.Errorf("Expected status %d, got %d", tt.wantCode, code)
+			}
+		})
+	}
+
+	// The API key keeps working, and requests without credentials are rejected
+	if code := send("Bearer secret"); code != http.StatusOK {
+		t.Errorf("Expected the API key to be accepted, got %d", code)
+	}
+	if code := send(""); code != http.StatusUnauthorized {
+		t.Errorf("Expected requests without credentials to be rejected, got %d", code)
+	}
+
+	// Usage is counted per token subject
+	records := ethService.Usage().Query(service.UsageFilter{GroupBy: service.UsageByClient})
+	var subjectRequests int64
+	for _, record := range records {
+		if record.Client == "sub:alice@example.com" {
+			subjectRequests = record.Requests
+		}
+	}
+	if subjectRequests != 2 {
+		t.Errorf("Usage = %+v, want 2 requests of sub:alice@example.com", records)
+	}
+
+	// Unknown keys are looked up again at most once a minute, picking up rotated keys
+	fetches := provider.fetches.Load()
+	provider.publishRSA("rsa-2", rotatedKey)
+	rotated := signJWT(t, map[string]interface{}{"alg": "RS256", "kid": "rsa-2"}, claims(nil), rotatedKey)
+	if code := send("Bearer " + rotated); code != http.StatusUnauthorized || provider.fetches.Load() != fetches {
+		t.Errorf("Token of a rotated key right after a fetch = %d with %d fetches, want 401 without fetching", code, provider.fetches.Load()-fetches)
+	}
+	clock.Advance(time.Minute)
+	if code := send("Bearer " + rotated); code != http.StatusOK || provider.fetches.Load() != fetches+1 {
+		t.Errorf("Token of a rotated key a minute later = %d with %d fetches, want 200 after one fetch", code, provider.fetches.Load()-fetches)
+	}
+}
+
+func TestNewJWTVerifier_RequiresConfig(t *testing.T) {
+	configs := []service.OIDCConfig{
+		{Audience: oidcAudience, JWKSURL: "https://login.example.com/keys"},
+		{Issuer: oidcIssuer, JWKSURL: "https://login.example.com/keys"},
+		{Issuer: oidcIssuer, Audience: oidcAudience},
+	}
+	for _, config := range configs {
+		if _, err := service.NewJWTVerifier(config); err == nil {
+			t.Errorf("NewJWTVerifier(%+v) expected an error", config)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package tests
+
+import (
+	"context"
+	"ethereum-validator-api/service"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+const (
+	lidoPubkey       = "0x" + "a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1"
+	rocketPoolPubkey = "0x" + "b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2b2"
+	untaggedPubkey   = "0x" + "c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3c3"
+	operatorKeyLists = `[
+		{"pool": "Lido", "operator": "Staking Facilities", "pubkeys": ["` + lidoPubkey + `"]},
+		{"pool": "Rocket Pool", "pubkeys": ["` + rocketPoolPubkey + `"]}
+	]`
+)
+
+func TestLoadOperatorRegistry(t *testing.T) {
+	if err := os.WriteFile("operators.json", []byte(operatorKeyLists), 0644); err != nil {
+		t.Fatalf("Failed to create operator keys file: %v", err)
+	}
+	defer os.Remove("operators.json")
+
+	registry, err := service.LoadOperatorRegistry("operators.json")
+	if err != nil {
+		t.Fatalf("LoadOperatorRegistry() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		pubkey string
+		want   service.ValidatorOperator
+		wantOk bool
+	}{
+		{
+			name:   "Pool and operator",
+			pubkey: lidoPubkey,
+			want:   service.ValidatorOperator{Pool: "Lido", Operator: "Staking Facilities"},
+			wantOk: true,
+		},
+		{
+			name:   "Pool without operator is case-insensitive",
+			pubkey: "0x" + strings.ToUpper(rocketPoolPubkey[2:]),
+			want:   service.ValidatorOperator{Pool: "Rocket Pool"},
+			wantOk: true,
+		},
+		{
+			name:   "Untagged pubkey",
+			pubkey: untaggedPubkey,
+			wantOk: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := registry.Lookup(tt.pubkey)
+			if ok != tt.wantOk || got != tt.want {
+				t.Errorf("Lookup() = (%+v, %v), want (%+v, %v)", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+
+	if err := os.WriteFile("operators.json", []byte(`[{"pool": "Lido", "pubkeys": ["0x1234"]}]`), 0644); err != nil {
+		t.Fatalf("Failed to create operator keys file: %v", err)
+	}
+	if _, err := service.LoadOperatorRegistry("operators.json"); err == nil {
+		t.Error("LoadOperatorRegistry() with an invalid pubkey error = nil, want error")
+	}
+}
+
+func TestOperatorRegistry_Refresh(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(operatorKeyLists))
+	}))
+	defer server.Close()
+
+	registry := service.NewOperatorRegistry([]service.OperatorKeyList{
+		{Pool: "Solo", Pubkeys: []string{untaggedPubkey}},
+	})
+	if err := registry.Refresh(context.Background(), server.Client(), server.URL); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+
+	if registry.Len() != 3 {
+		t.Errorf("Len() = %d, want 3 as refreshed lists are merged", registry.Len())
+	}
+	if got, _ := registry.Lookup(lidoPubkey); got.Pool != "Lido" {
+		t.Errorf("Lookup() pool = %q, want Lido", got.Pool)
+	}
+}
+
+func TestEthereumService_GetMEVStats_Pools(t *testing.T) {
+	ethService, err := service.NewEthereumService("https://example.com")
+	if err != nil {
+		t.Fatalf("Failed to create EthereumService: %v", err)
+	}
+	ethService.SetOperators(service.NewOperatorRegistry([]service.OperatorKeyList{
+		{Pool: "Lido", Operator: "Staking Facilities", Pubkeys: []string{lidoPubkey}},
+		{Pool: "Rocket Pool", Pubkeys: []string{rocketPoolPubkey}},
+	}))
+
+	index := ethService.Index()
+	blocks := []struct {
+		slot   int64
+		pubkey string
+		status string
+		reward int64
+	}{
+		{32, lidoPubkey, "mev", 300},
+		{33, lidoPubkey, "vanilla", 100},
+		{34, rocketPoolPubkey, "mev", 200},
+		{35, untaggedPubkey, "mev", 50},
+	}
+	for _, block := range blocks {
+		index.Put(&service.BlockDetail{Slot: block.slot, ProposerPubkey: block.pubkey})
+		index.PutReward(block.slot, &service.BlockReward{Status: block.status, Reward: big.NewInt(block.reward)})
+	}
+	index.PutReward(36, &service.BlockReward{Status: "vanilla", Reward: big.NewInt(10)}) // Block detail not indexed
+
+	got, err := ethService.GetMEVStats(1, 1)
+	if err != nil {
+		t.Fatalf("GetMEVStats() error = %v", err)
+	}
+
+	if len(got.Pools) != 4 {
+		t.Fatalf("GetMEVStats() pools = %+v, want 4 pools", got.Pools)
+	}
+	lido := got.Pools[0]
+	if lido.Name != "Lido" || lido.Blocks != 2 || lido.MEVBlocks != 1 || lido.Share != 0.4 || lido.MEVShare != 0.5 || lido.AverageReward.Int64() != 200e9 {
+		t.Errorf("GetMEVStats() first pool = %+v, want Lido with 2 blocks, 1 MEV block and an average of 200 GWEI", lido)
+	}
+
+	names := make(map[string]bool)
+	for _, pool := range got.Pools {
+		names[pool.Name] = true
+	}
+	for _, name := range []string{"Rocket Pool", service.PoolOther, "unknown"} {
+		if !names[name] {
+			t.Errorf("GetMEVStats() pools = %+v, want an entry for %q", got.Pools, name)
+		}
+	}
+}
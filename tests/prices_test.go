@@ -0,0 +1,146 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"ethereum-validator-api/api/models/v1"
+	"ethereum-validator-api/handler"
+	"ethereum-validator-api/service"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/gin-gonic/gin"
+)
+
+// mockPriceProvider serves a fixed price series per currency
+type mockPriceProvider struct {
+	prices map[string][]service.PricePoint
+}
+
+func (m *mockPriceProvider) FetchPrices(ctx context.Context, currency string, from, to time.Time) ([]service.PricePoint, error) {
+	return m.prices[currency], nil
+}
+
+func TestPriceSeries(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	prices := service.NewPriceSeries()
+	prices.Put("USD", []service.PricePoint{{Time: start, Price: 2000}, {Time: start.Add(2 * time.Hour), Price: 2200}})
+	// Newer prices replace known prices at the same time
+	prices.Put("usd", []service.PricePoint{{Time: start.Add(time.Hour), Price: 2100}, {Time: start.Add(2 * time.Hour), Price: 2250}})
+
+	tests := []struct {
+		name      string
+		at        time.Time
+		wantPrice float64
+		wantOK    bool
+	}{
+		{"Exact time", start.Add(time.Hour), 2100, true},
+		{"Closest earlier price", start.Add(80 * time.Minute), 2100, true},
+		{"Closest later price", start.Add(100 * time.Minute), 2250, true},
+		{"Replaced price", start.Add(3 * time.Hour), 2250, true},
+		{"Too long before the series", start.Add(-48 * time.Hour), 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price, ok := prices.PriceAt("USD", tt.at)
+			if ok != tt.wantOK || price.Price != tt.wantPrice {
+				t.Errorf("PriceAt() = %v %v, want %v %v", price.Price, ok, tt.wantPrice, tt.wantOK)
+			}
+		})
+	}
+
+	if latest, ok := prices.Latest("usd"); !ok || !latest.Equal(start.Add(2*time.Hour)) {
+		t.Errorf("Latest() = %v %v, want %v", latest, ok, start.Add(2*time.Hour))
+	}
+}
+
+func TestCoinGeckoProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/coins/ethereum/market_chart/range" || r.URL.Query().Get("vs_currency") != "eur" || r.Header.Get("x-cg-demo-api-key") != "key" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"prices":[[1704067200000,2050.5],[1704070800000,2060.25]]}`))
+	}))
+	defer server.Close()
+
+	provider := service.NewCoinGeckoProvider(server.Client(), server.URL+"/", "key")
+	points, err := provider.FetchPrices(context.Background(), "eur", time.Unix(1704067200, 0), time.Unix(1704070800, 0))
+	if err != nil {
+		t.Fatalf("FetchPrices() unexpected error: %v", err)
+	}
+	if len(points) != 2 || points[1].Price != 2060.25 || !points[1].Time.Equal(time.Unix(1704070800, 0)) {
+		t.Errorf("FetchPrices() = %v, want two hourly prices", points)
+	}
+}
+
+func TestBlockRewardFiatValues(t *testing.T) {
+	header := &types.Header{Number: big.NewInt(100), BaseFee: big.NewInt(10)}
+	block := types.NewBlockWithHeader(header)
+	// 0.002 ETH of priority fees
+	receipts := []*types.Receipt{
+		{TxHash: common.HexToHash("0x01"), GasUsed: 1000000, EffectiveGasPrice: big.NewInt(2000000010)},
+	}
+	execution := &mockExecutionClient{
+		blocks:   []*types.Block{block},
+		receipts: map[common.Hash][]*types.Receipt{block.Hash(): receipts},
+	}
+	beacon := &mockBeaconClient{responses: map[string]string{
+		"/eth/v1/beacon/blocks/100/root":                  `{"data":{"root":"0x01"}}`,
+		"/eth/v2/beacon/blocks/100":                       beaconBlockWithPayload(100),
+		"/eth/v1/beacon/headers/head":                     `{"data":{"root":"0xabc","header":{"message":{"slot":"128"}}}}`,
+		"/eth/v1/beacon/states/head/finality_checkpoints": `{"data":{"finalized":{"epoch":"2","root":"0x01"}}}`,
+		"/eth/v1/beacon/genesis":                          `{"data":{"genesis_time":"1704067200"}}`,
+	}}
+	ethService := service.NewEthereumServiceWithClients(beacon, execution)
+	h := handler.NewHandler(ethService)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/blockreward/:slot", h.GetBlockReward)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	if w := get("/blockreward/100?currency=usd"); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503 while valuation is disabled, got %d", w.Code)
+	}
+
+	// Slot 100 is 20 minutes after genesis, closest to the second price
+	genesis := time.Unix(1704067200, 0).UTC()
+	ethService.SetPriceProvider(&mockPriceProvider{prices: map[string][]service.PricePoint{
+		"usd": {{Time: genesis, Price: 2000}, {Time: genesis.Add(30 * time.Minute), Price: 2500}},
+	}}, []string{"USD"})
+
+	if w := get("/blockreward/100?currency=usd"); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503 before the prices were fetched, got %d", w.Code)
+	}
+
+	if err := ethService.PriceTask(service.Every(time.Hour), service.DefaultPriceHistory).Run(context.Background()); err != nil {
+		t.Fatalf("PriceTask() unexpected error: %v", err)
+	}
+
+	w := get("/blockreward/100?currency=USD")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var response v1.BlockRewardResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(response.Fiat) != 1 || response.Fiat[0].Currency != "usd" || response.Fiat[0].Price != 2500 || response.Fiat[0].Value != 5 {
+		t.Errorf("Expected the reward valued at 5 usd, got %+v", response.Fiat)
+	}
+
+	if w := get("/blockreward/100?currency=usd,jpy"); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an untracked currency, got %d", w.Code)
+	}
+}
@@ -0,0 +1,96 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"ethereum-validator-api/api/models/v1"
+	"ethereum-validator-api/handler"
+	"ethereum-validator-api/service"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestGetBlockReportsProvenance(t *testing.T) {
+	beacon := &mockBeaconClient{responses: map[string]string{
+		"/eth/v1/beacon/blocks/200/root":                  `{"data":{"root":"0x01"}}`,
+		"/eth/v2/beacon/blocks/200":                       beaconBlockAt(200, 150, "0x"+fmt.Sprintf("%064x", 150)),
+		"/eth/v1/beacon/blocks/40/root":                   `{"data":{"root":"0x02"}}`,
+		"/eth/v2/beacon/blocks/40":                        beaconBlockAt(40, 30, "0x"+fmt.Sprintf("%064x", 30)),
+		"/eth/v1/beacon/headers/head":                     `{"data":{"root":"0xabc","header":{"message":{"slot":"256"}}}}`,
+		"/eth/v1/beacon/states/head/finality_checkpoints": `{"data":{"finalized":{"epoch":"2","root":"0x01"}}}`,
+	}}
+	h := handler.NewHandler(service.NewEthereumServiceWithClients(beacon, &mockExecutionClient{}))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(handler.Provenance())
+	router.GET("/block/:slot", h.GetBlock)
+
+	get := func(path string) map[string]json.RawMessage {
+		t.Helper()
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200 for %s, got %d: %s", path, w.Code, w.Body.String())
+		}
+		var response map[string]json.RawMessage
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return response
+	}
+	meta := func(response map[string]json.RawMessage) v1.MetaResponse {
+		t.Helper()
+		var meta v1.MetaResponse
+		if err := json.Unmarshal(response["meta"], &meta); err != nil {
+			t.Fatalf("Failed to decode meta %s: %v", response["meta"], err)
+		}
+		return meta
+	}
+
+	if response := get("/block/200"); response["meta"] != nil {
+		t.Errorf("Expected no meta without meta=true, got %s", response["meta"])
+	}
+
+	// The head was cached by the previous request, the block is fetched again
+	got := meta(get("/block/200?meta=true"))
+	if !reflect.DeepEqual(got.Providers, []string{"primary"}) || got.Cache != "partial" {
+		t.Errorf("Expected a partial cache hit served by the primary, got %+v", got)
+	}
+	if got.Slot == nil || *got.Slot != 200 || got.Finalized == nil || *got.Finalized {
+		t.Errorf("Expected unfinalized slot 200, got slot %v finalized %v", got.Slot, got.Finalized)
+	}
+	if got.DataAgeSeconds <= 0 {
+		t.Errorf("Expected the age of the cached head, got %v", got.DataAgeSeconds)
+	}
+
+	// Meta is kept when fields are selected
+	response := get("/block/40?meta=true&fields=slot")
+	if len(response) != 2 || response["slot"] == nil {
+		t.Errorf("Expected slot and meta only, got %v", response)
+	}
+	if got := meta(response); got.Finalized == nil || !*got.Finalized {
+		t.Errorf("Expected slot 40 to be finalized, got %v", got.Finalized)
+	}
+}
+
+func TestHedgedClientsRecordProvider(t *testing.T) {
+	primary := &mockBeaconClient{responses: map[string]string{}}
+	secondary := &mockBeaconClient{responses: map[string]string{"/eth/v1/node/version": `{"data":{"version":"test"}}`}}
+	client := service.NewHedgedBeaconClient(primary, secondary, time.Second)
+
+	ctx, provenance := service.WithProvenance(context.Background())
+	var out json.RawMessage
+	if err := client.GetJSON(ctx, "/eth/v1/node/version", &out); err != nil {
+		t.Fatalf("GetJSON() unexpected error: %v", err)
+	}
+	if providers := provenance.Summary().Providers; !reflect.DeepEqual(providers, []string{"secondary"}) {
+		t.Errorf("Expected the secondary to be recorded, got %v", providers)
+	}
+}
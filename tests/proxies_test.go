@@ -0,0 +1,90 @@
+package tests
+
+import (
+	"ethereum-validator-api/utils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestConfigureTrustedProxies(t *testing.T) {
+	tests := []struct {
+		name       string
+		proxies    string
+		platform   string
+		remoteAddr string
+		headers    map[string]string
+		wantIP     string
+	}{
+		{
+			name:       "No trusted proxies ignores forwarding headers",
+			remoteAddr: "10.1.2.3:4000",
+			headers:    map[string]string{"X-Forwarded-For": "203.0.113.7"},
+			wantIP:     "10.1.2.3",
+		},
+		{
+			name:       "Trusted proxy forwards the client",
+			proxies:    "10.0.0.0/8",
+			remoteAddr: "10.1.2.3:4000",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.1, 203.0.113.7, 10.1.2.4"},
+			wantIP:     "203.0.113.7",
+		},
+		{
+			name:       "Untrusted peer can't spoof its address",
+			proxies:    "10.0.0.0/8",
+			remoteAddr: "192.0.2.10:4000",
+			headers:    map[string]string{"X-Forwarded-For": "203.0.113.7"},
+			wantIP:     "192.0.2.10",
+		},
+		{
+			name:       "X-Real-IP fallback",
+			proxies:    "10.1.2.3",
+			remoteAddr: "10.1.2.3:4000",
+			headers:    map[string]string{"X-Real-IP": "203.0.113.7"},
+			wantIP:     "203.0.113.7",
+		},
+		{
+			name:       "Cloudflare platform header",
+			platform:   "cloudflare",
+			remoteAddr: "10.1.2.3:4000",
+			headers:    map[string]string{"CF-Connecting-IP": "203.0.113.7"},
+			wantIP:     "203.0.113.7",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TRUSTED_PROXIES", tt.proxies)
+			t.Setenv("TRUSTED_PLATFORM", tt.platform)
+
+			router := gin.New()
+			if err := utils.ConfigureTrustedProxies(router); err != nil {
+				t.Fatalf("ConfigureTrustedProxies() unexpected error: %v", err)
+			}
+			router.GET("/ip", func(c *gin.Context) {
+				c.String(http.StatusOK, c.ClientIP())
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/ip", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Body.String() != tt.wantIP {
+				t.Errorf("ClientIP() = %s, want %s", w.Body.String(), tt.wantIP)
+			}
+		})
+	}
+
+	t.Run("Invalid proxy", func(t *testing.T) {
+		t.Setenv("TRUSTED_PROXIES", "not-an-ip")
+		if err := utils.ConfigureTrustedProxies(gin.New()); err == nil {
+			t.Error("ConfigureTrustedProxies() expected error for invalid proxy")
+		}
+	})
+}
@@ -0,0 +1,90 @@
+package tests
+
+import (
+	"context"
+	"ethereum-validator-api/service"
+	"fmt"
+	"testing"
+)
+
+func TestEthereumService_GetValidatorQueueChurnLimit(t *testing.T) {
+	tests := []struct {
+		name          string
+		spec          string
+		activeBalance int64 // in GWEI
+		wantChurn     int64
+	}{
+		{
+			name:          "Minimum churn of a small active set",
+			spec:          `{}`,
+			activeBalance: 64_000_000_000,
+			wantChurn:     128_000_000_000,
+		},
+		{
+			name:          "Balance churn rounded down to the increment",
+			spec:          `{}`,
+			activeBalance: 65536 * 200_500_000_000,
+			wantChurn:     200_000_000_000,
+		},
+		{
+			name:          "Balance churn capped at the maximum",
+			spec:          `{}`,
+			activeBalance: 65536 * 300_000_000_000,
+			wantChurn:     256_000_000_000,
+		},
+		{
+			name:          "Spec overrides the minimum",
+			spec:          `{"MIN_PER_EPOCH_CHURN_LIMIT_ELECTRA":"64000000000"}`,
+			activeBalance: 64_000_000_000,
+			wantChurn:     64_000_000_000,
+		},
+		{
+			name:          "Spec overrides the quotient and increment",
+			spec:          `{"CHURN_LIMIT_QUOTIENT":"1000","EFFECTIVE_BALANCE_INCREMENT":"1"}`,
+			activeBalance: 200_500_000_123_000,
+			wantChurn:     200_500_000_123,
+		},
+		{
+			name:          "Spec overrides the maximum",
+			spec:          `{"MAX_PER_EPOCH_ACTIVATION_EXIT_CHURN_LIMIT":"150000000000"}`,
+			activeBalance: 65536 * 200_000_000_000,
+			wantChurn:     150_000_000_000,
+		},
+		{
+			name:          "Invalid spec values fall back to the defaults",
+			spec:          `{"MIN_PER_EPOCH_CHURN_LIMIT_ELECTRA":"abc","CHURN_LIMIT_QUOTIENT":"0","EFFECTIVE_BALANCE_INCREMENT":"-1","MAX_PER_EPOCH_ACTIVATION_EXIT_CHURN_LIMIT":""}`,
+			activeBalance: 65536 * 200_500_000_000,
+			wantChurn:     200_000_000_000,
+		},
+		{
+			name:          "Numbers not given as strings fall back to the defaults",
+			spec:          `{"MIN_PER_EPOCH_CHURN_LIMIT_ELECTRA":64000000000}`,
+			activeBalance: 64_000_000_000,
+			wantChurn:     128_000_000_000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			beacon := &mockBeaconClient{responses: map[string]string{
+				"/eth/v1/config/spec": `{"data":` + tt.spec + `}`,
+				"/eth/v1/beacon/states/head/validators?status=active":         fmt.Sprintf(`{"data":[{"index":"1","validator":{"effective_balance":"%d"}}]}`, tt.activeBalance),
+				"/eth/v1/beacon/headers/head":                                 `{"data":{"root":"0xabc","header":{"message":{"slot":"320"}}}}`,
+				"/eth/v1/beacon/states/head/pending_deposits":                 `{"data":[{"amount":"32000000000000"}]}`,
+				"/eth/v1/beacon/states/head/validators?status=active_exiting": `{"data":[]}`,
+			}}
+			ethService := service.NewEthereumServiceWithClients(beacon, &mockExecutionClient{})
+
+			queue, err := ethService.GetValidatorQueue(context.Background())
+			if err != nil {
+				t.Fatalf("GetValidatorQueue() unexpected error: %v", err)
+			}
+			if queue.ChurnLimit != tt.wantChurn {
+				t.Errorf("GetValidatorQueue() churn limit = %d, want %d", queue.ChurnLimit, tt.wantChurn)
+			}
+			if want := (int64(32_000_000_000_000) + tt.wantChurn - 1) / tt.wantChurn; queue.EntryWaitEpochs != want {
+				t.Errorf("GetValidatorQueue() entry wait = %d epochs, want %d", queue.EntryWaitEpochs, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"ethereum-validator-api/service"
+	"strings"
+	"testing"
+)
+
+// failingBeaconClient fails every request with err
+type failingBeaconClient struct {
+	err error
+}
+
+func (f failingBeaconClient) GetJSON(ctx context.Context, path string, out interface{}) error {
+	return f.err
+}
+
+func (f failingBeaconClient) PostJSON(ctx context.Context, path string, body interface{}, out interface{}) error {
+	return f.err
+}
+
+// quorumNode returns a beacon node reporting root for slot 100 and serving its header by root
+func quorumNode(root string) *mockBeaconClient {
+	return &mockBeaconClient{responses: map[string]string{
+		"/eth/v1/node/version":           `{"data":{"version":"Lighthouse/v5.0.0"}}`,
+		"/eth/v1/beacon/blocks/100/root": `{"data":{"root":"` + root + `"}}`,
+		"/eth/v1/beacon/headers/" + root: `{"data":{"root":"` + root + `","header":{"message":{"slot":"100"}}}}`,
+	}}
+}
+
+func TestQuorumBeaconClient(t *testing.T) {
+	agreeing, other := "0x"+strings.Repeat("aa", 32), "0x"+strings.Repeat("bb", 32)
+
+	tests := []struct {
+		name     string
+		nodes    []service.BeaconClient
+		quorum   int
+		path     string
+		wantRoot string
+		wantErr  error
+	}{
+		{name: "Majority agrees", nodes: []service.BeaconClient{quorumNode(other), quorumNode(agreeing), quorumNode(agreeing)}, quorum: 2, path: "/eth/v1/beacon/headers/100", wantRoot: agreeing},
+		{name: "Root lookup", nodes: []service.BeaconClient{quorumNode(agreeing), quorumNode(agreeing)}, quorum: 2, path: "/eth/v1/beacon/blocks/100/root", wantRoot: agreeing},
+		{name: "Disagreement", nodes: []service.BeaconClient{quorumNode(agreeing), quorumNode(agreeing), quorumNode(other)}, quorum: 3, path: "/eth/v1/beacon/headers/100", wantErr: service.ErrInconsistentResponse},
+		{name: "Unknown block", nodes: []service.BeaconClient{quorumNode(agreeing), quorumNode(agreeing)}, quorum: 2, path: "/eth/v1/beacon/headers/101", wantErr: service.ErrSlotNotFound},
+		{name: "Failing node outvoted", nodes: []service.BeaconClient{failingBeaconClient{service.ErrRPCFailed}, quorumNode(agreeing), quorumNode(agreeing)}, quorum: 2, path: "/eth/v1/beacon/headers/100", wantRoot: agreeing},
+		{name: "Too few nodes answer", nodes: []service.BeaconClient{quorumNode(agreeing), failingBeaconClient{service.ErrUpstreamTimeout}, failingBeaconClient{service.ErrUpstreamTimeout}}, quorum: 2, path: "/eth/v1/beacon/headers/100", wantErr: service.ErrUpstreamTimeout},
+		{name: "Not about a block", nodes: []service.BeaconClient{failingBeaconClient{service.ErrRPCFailed}, quorumNode(agreeing)}, quorum: 2, path: "/eth/v1/node/version"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := service.NewQuorumBeaconClient(tt.nodes, tt.quorum)
+			if err != nil {
+				t.Fatalf("NewQuorumBeaconClient() unexpected error: %v", err)
+			}
+
+			var response struct {
+				Data struct {
+					Root    string `json:"root"`
+					Version string `json:"version"`
+				} `json:"data"`
+			}
+			err = client.GetJSON(context.Background(), tt.path, &response)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("GetJSON() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetJSON() unexpected error: %v", err)
+			}
+			if tt.wantRoot != "" && response.Data.Root != tt.wantRoot {
+				t.Errorf("Expected the block %s the quorum agreed on, got %s", tt.wantRoot, response.Data.Root)
+			}
+		})
+	}
+
+	if _, err := service.NewQuorumBeaconClient([]service.BeaconClient{quorumNode(agreeing)}, 2); err == nil {
+		t.Error("NewQuorumBeaconClient() expected error for a quorum above the number of nodes")
+	}
+}
+
+func TestQuorumBeaconClient_ChainHead(t *testing.T) {
+	// Every node is asked for the roots of the head block and state, then the data is read by those roots
+	node := func(blockRoot string) *mockBeaconClient {
+		return &mockBeaconClient{responses: map[string]string{
+			"/eth/v1/beacon/blocks/head/root":                 `{"data":{"root":"` + blockRoot + `"}}`,
+			"/eth/v1/beacon/headers/" + blockRoot:             `{"data":{"root":"` + blockRoot + `","header":{"message":{"slot":"8192"}}}}`,
+			"/eth/v1/beacon/states/head/root":                 `{"data":{"root":"0x5a"}}`,
+			"/eth/v1/beacon/states/0x5a/finality_checkpoints": `{"data":{"finalized":{"epoch":"254","root":"0x01"}}}`,
+		}}
+	}
+	client, err := service.NewQuorumBeaconClient([]service.BeaconClient{node("0xabc"), node("0xabc"), node("0xdef")}, 2)
+	if err != nil {
+		t.Fatalf("NewQuorumBeaconClient() unexpected error: %v", err)
+	}
+
+	head, err := service.NewEthereumServiceWithClients(client, &mockExecutionClient{}).GetChainHead(context.Background())
+	if err != nil {
+		t.Fatalf("GetChainHead() unexpected error: %v", err)
+	}
+	if head.Slot != 8192 || head.Root != "0xabc" || head.Finalized.Epoch != 254 {
+		t.Errorf("Unexpected chain head: %+v", head)
+	}
+}
@@ -0,0 +1,365 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"ethereum-validator-api/api/models/v1"
+	"ethereum-validator-api/handler"
+	"ethereum-validator-api/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestUpstreamScheduler_InteractiveBeforeBatch(t *testing.T) {
+	var mu sync.Mutex
+	var served []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		served = append(served, r.URL.Path)
+		mu.Unlock()
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	service.SetUpstreamRateLimit(10, 1)
+	defer service.SetUpstreamRateLimit(1, 1)
+	client := service.NewHTTPBeaconClient(server.URL, server.Client())
+
+	// Queue up a backfill, then a single lookup a user is waiting for
+	var wg sync.WaitGroup
+	batchCtx := service.WithPriority(context.Background(), service.PriorityBatch)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var out struct{}
+			client.GetJSON(batchCtx, "/batch", &out)
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	var out struct{}
+	if err := client.GetJSON(context.Background(), "/interactive", &out); err != nil {
+		t.Fatalf("GetJSON() unexpected error: %v", err)
+	}
+	wg.Wait()
+
+	// At most the batch requests granted before the lookup was queued may go first
+	for i, path := range served {
+		if path == "/interactive" {
+			if i > 2 {
+				t.Errorf("interactive request served at position %d, want it ahead of the queued batch requests", i)
+			}
+			return
+		}
+	}
+	t.Error("interactive request was not served")
+}
+
+func TestProviderRateLimit_RetryAfter(t *testing.T) {
+	var mu sync.Mutex
+	var requests []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests = append(requests, time.Now())
+		if len(requests) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	service.SetUpstreamRateLimit(100, 10)
+	defer service.SetUpstreamRateLimit(1, 1)
+	client := service.NewHTTPBeaconClient(server.URL, server.Client())
+
+	// Interactive requests are answered with the remaining backoff instead of waiting
+	var out struct{}
+	err := client.GetJSON(context.Background(), "/eth/v1/node/version", &out)
+	var rateLimitErr *service.RateLimitError
+	if !errors.As(err, &rateLimitErr) || rateLimitErr.RetryAfter <= 0 || rateLimitErr.RetryAfter > time.Second {
+		t.Fatalf("GetJSON() error = %v, want a RateLimitError within the 1s Retry-After", err)
+	}
+	if len(requests) != 1 {
+		t.Fatalf("Expected the interactive request not to be retried, got %d requests", len(requests))
+	}
+
+	// Batch requests wait for the provider to accept requests again
+	if err := client.GetJSON(service.WithPriority(context.Background(), service.PriorityBatch), "/eth/v1/node/version", &out); err != nil {
+		t.Fatalf("GetJSON() unexpected error: %v", err)
+	}
+	if len(requests) != 2 {
+		t.Fatalf("Expected the batch request to be sent once, got %d requests", len(requests))
+	}
+	if wait := requests[1].Sub(requests[0]); wait < 900*time.Millisecond {
+		t.Errorf("Expected the batch request to wait for the Retry-After of 1s, it waited %v", wait)
+	}
+}
+
+func TestProviderRateLimit_RequestsPerSecond(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	service.SetUpstreamRateLimit(100, 10)
+	defer service.SetUpstreamRateLimit(1, 1)
+	if err := service.SetProviderRateLimits(map[string]service.ProviderRateLimit{server.URL: {RequestsPerSecond: 5, Burst: 1}}); err != nil {
+		t.Fatalf("SetProviderRateLimits() unexpected error: %v", err)
+	}
+	defer service.SetProviderRateLimits(nil)
+	client := service.NewHTTPBeaconClient(server.URL, server.Client())
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		var out struct{}
+		if err := client.GetJSON(context.Background(), "/eth/v1/node/version", &out); err != nil {
+			t.Fatalf("GetJSON() unexpected error: %v", err)
+		}
+	}
+	// One request goes out right away, the others every 200ms
+	if elapsed := time.Since(start); elapsed < 550*time.Millisecond {
+		t.Errorf("Expected 4 requests at 5 requests per second to take at least 600ms, took %v", elapsed)
+	}
+
+	if err := service.SetProviderRateLimits(map[string]service.ProviderRateLimit{"not a url": {RequestsPerSecond: 1}}); err == nil {
+		t.Error("SetProviderRateLimits() expected error for an invalid provider URL")
+	}
+}
+
+func TestProviderRateLimit_DailyQuota(t *testing.T) {
+	clock := newMockClock(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC))
+	service.SetUpstreamClock(clock)
+	defer service.SetUpstreamClock(service.SystemClock{})
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	service.SetUpstreamRateLimit(100, 10)
+	defer service.SetUpstreamRateLimit(1, 1)
+	// Beacon API requests cost 20 credits, so the quota covers 10 requests
+	if err := service.SetProviderRateLimits(map[string]service.ProviderRateLimit{server.URL: {DailyQuota: 200}}); err != nil {
+		t.Fatalf("SetProviderRateLimits() unexpected error: %v", err)
+	}
+	defer service.SetProviderRateLimits(nil)
+	client := service.NewHTTPBeaconClient(server.URL, server.Client())
+	batchCtx := service.WithPriority(context.Background(), service.PriorityBatch)
+
+	var out struct{}
+	for i := 0; i < 9; i++ {
+		if err := client.GetJSON(batchCtx, "/eth/v1/node/version", &out); err != nil {
+			t.Fatalf("GetJSON() of batch request %d unexpected error: %v", i, err)
+		}
+	}
+
+	// With 90% of the quota used, batch requests are shed and the rest is kept for interactive requests
+	var rateLimitErr *service.RateLimitError
+	if err := client.GetJSON(batchCtx, "/eth/v1/node/version", &out); !errors.As(err, &rateLimitErr) || rateLimitErr.RetryAfter != 12*time.Hour {
+		t.Fatalf("GetJSON() of a batch request near the quota error = %v, want a RateLimitError until midnight", err)
+	}
+	if err := client.GetJSON(context.Background(), "/eth/v1/node/version", &out); err != nil {
+		t.Fatalf("GetJSON() of an interactive request near the quota unexpected error: %v", err)
+	}
+	if err := client.GetJSON(context.Background(), "/eth/v1/node/version", &out); !errors.Is(err, service.ErrUpstreamRateLimited) {
+		t.Fatalf("GetJSON() with the quota exhausted error = %v, want ErrUpstreamRateLimited", err)
+	}
+	if requests.Load() != 10 {
+		t.Errorf("Expected 10 requests within the quota, got %d", requests.Load())
+	}
+
+	budget := providerBudget(t, host)
+	want := service.ProviderBudget{Host: host, Day: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), Calls: 10, Credits: 200, Quota: 200, Low: true}
+	if budget != want {
+		t.Errorf("ProviderBudgets() = %+v, want %+v", budget, want)
+	}
+
+	// The quota resets at UTC midnight
+	clock.Advance(12 * time.Hour)
+	if err := client.GetJSON(batchCtx, "/eth/v1/node/version", &out); err != nil {
+		t.Fatalf("GetJSON() after midnight unexpected error: %v", err)
+	}
+	if budget := providerBudget(t, host); budget.Calls != 1 || budget.Remaining != 180 || budget.Low {
+		t.Errorf("ProviderBudgets() after midnight = %+v, want 1 call with 180 credits remaining", budget)
+	}
+
+	if err := service.SetProviderRateLimits(map[string]service.ProviderRateLimit{server.URL: {DailyQuota: -1}}); err == nil {
+		t.Error("SetProviderRateLimits() expected error for a negative quota")
+	}
+}
+
+// providerBudget returns the budget of a provider host
+func providerBudget(t *testing.T, host string) service.ProviderBudget {
+	t.Helper()
+	for _, budget := range service.ProviderBudgets() {
+		if budget.Host == host {
+			return budget
+		}
+	}
+	t.Fatalf("ProviderBudgets() has no budget for %s", host)
+	return service.ProviderBudget{}
+}
+
+func TestProviderRateLimit_QuotaPrefersCache(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	service.SetUpstreamRateLimit(100, 10)
+	defer service.SetUpstreamRateLimit(1, 1)
+	if err := service.SetProviderRateLimits(map[string]service.ProviderRateLimit{server.URL: {DailyQuota: 20}}); err != nil {
+		t.Fatalf("SetProviderRateLimits() unexpected error: %v", err)
+	}
+	defer service.SetProviderRateLimits(nil)
+
+	beacon := &mockBeaconClient{responses: map[string]string{
+		"/eth/v1/beacon/headers/head":                     `{"data":{"root":"0xabc","header":{"message":{"slot":"8192"}}}}`,
+		"/eth/v1/beacon/states/head/finality_checkpoints": `{"data":{}}`,
+	}}
+	clock := newMockClock(time.Now())
+	ethService := service.NewEthereumServiceWithClients(beacon, &mockExecutionClient{})
+	ethService.SetClock(clock)
+	if _, err := ethService.GetChainHead(context.Background()); err != nil {
+		t.Fatalf("GetChainHead() unexpected error: %v", err)
+	}
+	beacon.responses["/eth/v1/beacon/headers/head"] = `{"data":{"root":"0xdef","header":{"message":{"slot":"8193"}}}}`
+	clock.Advance(5 * time.Second)
+
+	// Using up the quota of a provider makes expired entries be served
+	var out struct{}
+	if err := service.NewHTTPBeaconClient(server.URL, server.Client()).GetJSON(context.Background(), "/eth/v1/node/version", &out); err != nil {
+		t.Fatalf("GetJSON() unexpected error: %v", err)
+	}
+	if head, err := ethService.GetChainHead(context.Background()); err != nil || head.Slot != 8192 {
+		t.Errorf("GetChainHead() near the quota = %+v, %v, want the expired slot 8192", head, err)
+	}
+
+	h := handler.NewHandler(ethService)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/admin/upstreams", h.GetUpstreamBudgets)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/upstreams", nil))
+	var response v1.UpstreamBudgetsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	host := strings.TrimPrefix(server.URL, "http://")
+	index := slices.IndexFunc(response.Upstreams, func(upstream v1.UpstreamBudgetResponse) bool {
+		return upstream.Host == host
+	})
+	if w.Code != http.StatusOK || index < 0 {
+		t.Fatalf("GET /admin/upstreams = %d %s, want the budget of %s", w.Code, w.Body.String(), host)
+	}
+	if upstream := response.Upstreams[index]; upstream.Calls != 1 || upstream.Credits != 20 || upstream.Quota != 20 || upstream.Remaining == nil || *upstream.Remaining != 0 || !upstream.Low {
+		t.Errorf("GET /admin/upstreams budget = %+v, want the quota used up", upstream)
+	}
+
+	// Without a quota running low, expired entries are refreshed again
+	service.SetProviderRateLimits(nil)
+	if head, err := ethService.GetChainHead(context.Background()); err != nil || head.Slot != 8193 {
+		t.Errorf("GetChainHead() = %+v, %v, want the refreshed slot 8193", head, err)
+	}
+}
+
+// gatedBeaconClient counts the requests of each path and holds them until release is closed or they are canceled
+type gatedBeaconClient struct {
+	mockBeaconClient
+	release chan struct{}
+
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func (g *gatedBeaconClient) GetJSON(ctx context.Context, path string, out interface{}) error {
+	g.mu.Lock()
+	g.calls[path]++
+	g.mu.Unlock()
+	select {
+	case <-g.release:
+		return g.mockBeaconClient.GetJSON(ctx, path, out)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (g *gatedBeaconClient) count(path string) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.calls[path]
+}
+
+func TestEthereumService_CoalescedBeaconRequests(t *testing.T) {
+	const headPath = "/eth/v1/beacon/headers/head"
+	beacon := &gatedBeaconClient{
+		mockBeaconClient: mockBeaconClient{responses: map[string]string{
+			headPath: `{"data":{"root":"0xhead","header":{"message":{"slot":"100"}}}}`,
+			"/eth/v1/beacon/states/head/finality_checkpoints": `{"data":{"finalized":{"epoch":"1","root":"0x01"}}}`,
+		}},
+		release: make(chan struct{}),
+		calls:   make(map[string]int),
+	}
+	ethService := service.NewEthereumServiceWithClients(beacon, &mockExecutionClient{})
+	waitForCalls := func(want int) {
+		t.Helper()
+		deadline := time.Now().Add(time.Second)
+		for beacon.count(headPath) < want {
+			if time.Now().After(deadline) {
+				t.Fatalf("Beacon API got %d head requests, want %d", beacon.count(headPath), want)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	// The first caller gives up while a second interactive caller waits on the same request
+	firstCtx, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := ethService.GetChainHead(firstCtx)
+		firstErr <- err
+	}()
+	waitForCalls(1)
+
+	results := make(chan error, 2)
+	go func() {
+		_, err := ethService.GetChainHead(context.Background())
+		results <- err
+	}()
+	// A batch caller doesn't join the interactive request, it would hold it back behind the batch budget
+	go func() {
+		_, err := ethService.GetChainHead(service.WithPriority(context.Background(), service.PriorityBatch))
+		results <- err
+	}()
+	waitForCalls(2)
+	time.Sleep(20 * time.Millisecond)
+
+	cancel()
+	if err := <-firstErr; err == nil {
+		t.Error("GetChainHead() of the canceled caller succeeded, want an error")
+	}
+	close(beacon.release)
+	for i := 0; i < 2; i++ {
+		if err := <-results; err != nil {
+			t.Errorf("GetChainHead() error = %v, want the shared response", err)
+		}
+	}
+	if got := beacon.count(headPath); got != 2 {
+		t.Errorf("Beacon API got %d head requests, want one per priority", got)
+	}
+}
@@ -0,0 +1,58 @@
+package tests
+
+import (
+	"encoding/json"
+	"ethereum-validator-api/api/models/v1"
+	"ethereum-validator-api/handler"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRecovery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(handler.RequestID(), handler.Recovery())
+	router.GET("/panic", func(c *gin.Context) {
+		panic("boom")
+	})
+	router.GET("/ok", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	t.Run("Panic becomes problem details", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/panic", nil)
+		req.Header.Set("X-Request-ID", "req-123")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusInternalServerError {
+			t.Fatalf("Expected status 500, got %d", w.Code)
+		}
+		if got := w.Header().Get("Content-Type"); got != "application/problem+json" {
+			t.Errorf("Expected problem+json content type, got %q", got)
+		}
+
+		var problem v1.ProblemResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if problem.Status != http.StatusInternalServerError || problem.RequestID != "req-123" || problem.Instance != "/panic" {
+			t.Errorf("Unexpected problem details: %+v", problem)
+		}
+	})
+
+	t.Run("Malformed request ID is replaced", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/ok", nil)
+		req.Header.Set("X-Request-ID", "bad id\n")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		id := w.Header().Get("X-Request-ID")
+		if id == "" || id == "bad id\n" {
+			t.Errorf("Expected a generated request ID, got %q", id)
+		}
+	})
+}
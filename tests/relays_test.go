@@ -0,0 +1,79 @@
+package tests
+
+import (
+	"context"
+	"ethereum-validator-api/service"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// newMockRelay serves fixed delivered and received bid traces
+func newMockRelay(delivered, received string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/proposer_payload_delivered"):
+			w.Write([]byte(delivered))
+		case strings.HasSuffix(r.URL.Path, "/builder_blocks_received"):
+			w.Write([]byte(received))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestEthereumService_GetBidComparisonBySlot(t *testing.T) {
+	winningRelay := newMockRelay(
+		`[{"slot":"100","block_hash":"0xaa","builder_pubkey":"0x01","value":"1000"}]`,
+		`[{"slot":"100","block_hash":"0xaa","builder_pubkey":"0x01","value":"1000"},{"slot":"100","block_hash":"0xab","builder_pubkey":"0x01","value":"900"}]`,
+	)
+	defer winningRelay.Close()
+
+	otherRelay := newMockRelay(
+		`[]`,
+		`[{"slot":"100","block_hash":"0xbb","builder_pubkey":"0x02","value":"1500"}]`,
+	)
+	defer otherRelay.Close()
+
+	var beaconRequests atomic.Int64
+	beacon := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		beaconRequests.Add(1)
+		http.NotFound(w, r)
+	}))
+	defer beacon.Close()
+
+	ethService, err := service.NewEthereumService(beacon.URL)
+	if err != nil {
+		t.Fatalf("Failed to create EthereumService: %v", err)
+	}
+	// MEV_RELAYS is split on commas, leaving spaces and empty entries around the URLs
+	if err := ethService.SetRelays(strings.Split(" "+winningRelay.URL+" , ,"+otherRelay.URL+",", ",")); err != nil {
+		t.Fatalf("SetRelays() error = %v", err)
+	}
+
+	got, err := ethService.GetBidComparisonBySlot(context.Background(), 100)
+	if err != nil {
+		t.Fatalf("GetBidComparisonBySlot() error = %v", err)
+	}
+
+	if got.Delivered == nil || got.Delivered.BlockHash != "0xaa" {
+		t.Errorf("GetBidComparisonBySlot() delivered = %+v, want block 0xaa", got.Delivered)
+	}
+	if got.BestBid == nil || got.BestBid.BlockHash != "0xbb" {
+		t.Errorf("GetBidComparisonBySlot() best bid = %+v, want block 0xbb", got.BestBid)
+	}
+	if got.MissedValue.String() != "500" {
+		t.Errorf("GetBidComparisonBySlot() missed value = %s, want 500", got.MissedValue)
+	}
+	if len(got.Relays) != 2 || got.Relays[0].BidCount != 2 || !got.Relays[0].Delivered {
+		t.Errorf("GetBidComparisonBySlot() relays = %+v, want 2 bids delivered by the first relay", got.Relays)
+	}
+
+	// Relay requests don't count as upstream requests
+	health := ethService.GetHealthSummary(context.Background())
+	if health.UpstreamRequests != beaconRequests.Load() {
+		t.Errorf("GetHealthSummary() upstream requests = %d, want the %d beacon requests", health.UpstreamRequests, beaconRequests.Load())
+	}
+}
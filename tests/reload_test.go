@@ -0,0 +1,104 @@
+package tests
+
+import (
+	"context"
+	"encoding/hex"
+	"ethereum-validator-api/service"
+	"ethereum-validator-api/utils"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestReloader_Reload(t *testing.T) {
+	// Restore every variable the reload may set once the test is done
+	for _, key := range []string{"ETH_RPC", "BEACON_RPC", "EXECUTION_RPC", "SECONDARY_RPC", "HEDGE_DELAY", "UPSTREAM_RATE_LIMIT",
+		"UPSTREAM_BURST", "CORS_CONFIG_FILE", "CORS_ALLOWED_ORIGINS", "CORS_ORIGIN", "MEV_BUILDERS_FILE", "QUORUM_BEACON_NODES", "BEACON_QUORUM"} {
+		t.Setenv(key, "")
+	}
+	t.Setenv("ETH_RPC", "http://primary.invalid")
+	t.Setenv("CORS_ALLOWED_ORIGINS", "https://old.example.com")
+
+	var beaconPath string
+	beacon := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		beaconPath = r.URL.Path
+		w.Write([]byte(`{"data":{"SECONDS_PER_SLOT":"12"}}`))
+	}))
+	defer beacon.Close()
+
+	dir := t.TempDir()
+	buildersFile := filepath.Join(dir, "builders.json")
+	os.WriteFile(buildersFile, []byte(`[{"name":"Reloaded Builder","extra_data":["reloaded"]}]`), 0o600)
+	envFile := filepath.Join(dir, ".env")
+	writeEnv := func(content string) {
+		if err := os.WriteFile(envFile, []byte(content), 0o600); err != nil {
+			t.Fatalf("Failed to write .env: %v", err)
+		}
+	}
+
+	ethService, err := service.NewEthereumService("http://primary.invalid")
+	if err != nil {
+		t.Fatalf("Failed to create EthereumService: %v", err)
+	}
+	corsConfig, err := utils.CORSConfigFromEnv()
+	if err != nil {
+		t.Fatalf("CORSConfigFromEnv() unexpected error: %v", err)
+	}
+	corsMiddleware := utils.NewCORSMiddleware(corsConfig)
+	reloader := utils.NewReloader(envFile)
+	reloader.SetCORS(corsMiddleware)
+	reloader.SetService(ethService)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(corsMiddleware.Handle)
+	router.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+	allowedOrigin := func(origin string) string {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+		req.Header.Set("Origin", origin)
+		router.ServeHTTP(w, req)
+		return w.Header().Get("Access-Control-Allow-Origin")
+	}
+	if allowedOrigin("https://new.example.com") != "" {
+		t.Fatal("Expected the new origin to be rejected before the reload")
+	}
+
+	writeEnv("BEACON_RPC=" + beacon.URL + "\nCORS_ALLOWED_ORIGINS=https://new.example.com\nMEV_BUILDERS_FILE=" + buildersFile + "\n")
+	applied, err := reloader.Reload(context.Background())
+	if err != nil {
+		t.Fatalf("Reload() unexpected error: %v", err)
+	}
+	if !slices.Equal(applied, []string{"upstreams", "cors", "builders"}) {
+		t.Errorf("Reload() applied %v, want upstreams, cors and builders", applied)
+	}
+
+	if allowedOrigin("https://new.example.com") != "https://new.example.com" || allowedOrigin("https://old.example.com") != "" {
+		t.Error("Expected only the reloaded origin to be allowed")
+	}
+	if got := ethService.Builders().Resolve("0x"+hex.EncodeToString([]byte("reloaded")), ""); got != "Reloaded Builder" {
+		t.Errorf("Expected the reloaded builder signature to be resolved, got %q", got)
+	}
+	if _, err := ethService.GetChainSpec(context.Background()); err != nil || beaconPath != "/eth/v1/config/spec" {
+		t.Errorf("Expected the spec to be fetched from the reloaded Beacon API, got path %q and error %v", beaconPath, err)
+	}
+
+	// Unchanged upstreams aren't replaced again
+	applied, err = reloader.Reload(context.Background())
+	if err != nil || slices.Contains(applied, "upstreams") {
+		t.Errorf("Reload() applied %v with error %v, want upstreams left alone", applied, err)
+	}
+
+	// An invalid setting keeps the whole current configuration
+	writeEnv("CORS_ALLOWED_ORIGINS=https://other.example.com\nUPSTREAM_RATE_LIMIT=fast\n")
+	if _, err := reloader.Reload(context.Background()); err == nil {
+		t.Error("Reload() expected error for an invalid rate limit")
+	}
+	if allowedOrigin("https://new.example.com") != "https://new.example.com" {
+		t.Error("Expected the CORS configuration to be kept after a failed reload")
+	}
+}
@@ -0,0 +1,72 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"ethereum-validator-api/service"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestRPCExecutionClient_BlockWithReceiptsBatches(t *testing.T) {
+	header := &types.Header{Number: big.NewInt(100), BaseFee: big.NewInt(10), Difficulty: big.NewInt(0)}
+	rawHeader, _ := json.Marshal(header)
+	var block map[string]interface{}
+	json.Unmarshal(rawHeader, &block)
+	block["transactions"] = []interface{}{}
+	receipts := []*types.Receipt{
+		{Status: types.ReceiptStatusSuccessful, TxHash: common.HexToHash("0x01"), GasUsed: 21000, EffectiveGasPrice: big.NewInt(12), Logs: []*types.Log{}},
+	}
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		var batch []struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			t.Errorf("request is not a batch: %v", err)
+			return
+		}
+
+		responses := make([]map[string]interface{}, 0, len(batch))
+		for _, call := range batch {
+			var result interface{} = block
+			if call.Method == "eth_getBlockReceipts" {
+				result = receipts
+			}
+			responses = append(responses, map[string]interface{}{"jsonrpc": "2.0", "id": call.ID, "result": result})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(responses)
+	}))
+	defer server.Close()
+
+	client, err := service.NewRPCExecutionClient(context.Background(), server.URL, server.Client())
+	if err != nil {
+		t.Fatalf("NewRPCExecutionClient() unexpected error: %v", err)
+	}
+
+	gotBlock, gotReceipts, err := client.BlockWithReceipts(context.Background(), big.NewInt(100))
+	if err != nil {
+		t.Fatalf("BlockWithReceipts() unexpected error: %v", err)
+	}
+
+	if requests.Load() != 1 {
+		t.Errorf("BlockWithReceipts() made %d requests, want 1", requests.Load())
+	}
+	if gotBlock.Hash() != header.Hash() {
+		t.Errorf("BlockWithReceipts() block hash = %s, want %s", gotBlock.Hash(), header.Hash())
+	}
+	if len(gotReceipts) != 1 || gotReceipts[0].GasUsed != 21000 {
+		t.Errorf("BlockWithReceipts() receipts = %+v, want one receipt using 21000 gas", gotReceipts)
+	}
+}
@@ -0,0 +1,101 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"ethereum-validator-api/service"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	after := time.Date(2024, 1, 31, 22, 30, 0, 0, time.UTC) // A Wednesday
+
+	tests := []struct {
+		expr    string
+		want    time.Time
+		wantErr bool
+	}{
+		{expr: "@hourly", want: time.Date(2024, 1, 31, 23, 0, 0, 0, time.UTC)},
+		{expr: "@daily", want: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{expr: "*/15 * * * *", want: time.Date(2024, 1, 31, 22, 45, 0, 0, time.UTC)},
+		{expr: "0 9-17 * * 1-5", want: time.Date(2024, 2, 1, 9, 0, 0, 0, time.UTC)},
+		{expr: "0 0 * * 0", want: time.Date(2024, 2, 4, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 29 2 *", want: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{expr: "30 6 1,15 * *", want: time.Date(2024, 2, 1, 6, 30, 0, 0, time.UTC)},
+		{expr: "@every 90s", want: after.Add(90 * time.Second)},
+		{expr: "* * *", wantErr: true},
+		{expr: "60 * * * *", wantErr: true},
+		{expr: "*/0 * * * *", wantErr: true},
+		{expr: "@every soon", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			schedule, err := service.ParseSchedule(tt.expr)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error for %q", tt.expr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseSchedule() error = %v", err)
+			}
+			if got := schedule.Next(after); !got.Equal(tt.want) {
+				t.Errorf("Next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduler(t *testing.T) {
+	release := make(chan struct{})
+	var slowRuns atomic.Int32
+
+	scheduler := service.NewScheduler()
+	scheduler.Register(service.Task{
+		Name:     "slow",
+		Schedule: service.Every(10 * time.Millisecond),
+		Run: func(ctx context.Context) error {
+			slowRuns.Add(1)
+			<-release
+			return nil
+		},
+	})
+	scheduler.Register(service.Task{
+		Name:       "failing",
+		Schedule:   service.Every(time.Hour),
+		RunAtStart: true,
+		Run: func(ctx context.Context) error {
+			return errors.New("upstream unavailable")
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go scheduler.Run(ctx)
+
+	// The slow task is skipped while its first run is going
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		statuses := scheduler.Status()
+		if statuses[0].Skipped > 0 && statuses[1].Runs > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Tasks did not run as expected: %+v", statuses)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	close(release)
+
+	statuses := scheduler.Status()
+	if statuses[0].Name != "slow" || !statuses[0].Running || slowRuns.Load() != 1 {
+		t.Errorf("Expected a single running slow task, got %+v with %d runs", statuses[0], slowRuns.Load())
+	}
+	if statuses[1].Failures != 1 || statuses[1].LastError != "upstream unavailable" || statuses[1].Schedule != "@every 1h0m0s" {
+		t.Errorf("Unexpected failing task status: %+v", statuses[1])
+	}
+}
@@ -0,0 +1,50 @@
+package tests
+
+import (
+	"ethereum-validator-api/utils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTPSRedirectHandler(t *testing.T) {
+	tests := []struct {
+		name      string
+		httpsPort string
+		url       string
+		want      string
+	}{
+		{
+			name:      "Default port",
+			httpsPort: "443",
+			url:       "http://api.example.com/blockreward/4700000?include=transactions",
+			want:      "https://api.example.com/blockreward/4700000?include=transactions",
+		},
+		{
+			name:      "Custom port",
+			httpsPort: "8443",
+			url:       "http://api.example.com:8080/chain/head",
+			want:      "https://api.example.com:8443/chain/head",
+		},
+		{
+			name:      "IPv6 host",
+			httpsPort: "443",
+			url:       "http://[::1]:80/chain/head",
+			want:      "https://[::1]/chain/head",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			utils.HTTPSRedirectHandler(tt.httpsPort).ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.url, nil))
+
+			if w.Code != http.StatusMovedPermanently {
+				t.Errorf("HTTPSRedirectHandler() status = %d, want %d", w.Code, http.StatusMovedPermanently)
+			}
+			if got := w.Header().Get("Location"); got != tt.want {
+				t.Errorf("HTTPSRedirectHandler() location = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
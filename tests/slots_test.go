@@ -0,0 +1,73 @@
+package tests
+
+import (
+	"encoding/json"
+	"ethereum-validator-api/api/models/v1"
+	"ethereum-validator-api/handler"
+	"ethereum-validator-api/service"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestSlotParam(t *testing.T) {
+	beacon := &mockBeaconClient{responses: map[string]string{
+		"/eth/v1/beacon/headers/head": `{"data":{"root":"0xabc","header":{"message":{"slot":"8200"}}}}`,
+		"/eth/v1/beacon/states/head/finality_checkpoints": `{"data":{
+			"current_justified":{"epoch":"255","root":"0x02"},
+			"finalized":{"epoch":"254","root":"0x01"}}}`,
+		"/eth/v1/beacon/states/8192/committees?slot=8200": `{"data":[{"index":"0","slot":"8200","validators":["1","2"]}]}`,
+		"/eth/v1/beacon/states/8128/committees?slot=8128": `{"data":[{"index":"0","slot":"8128","validators":["3"]}]}`,
+	}}
+	h := handler.NewHandler(service.NewEthereumServiceWithClients(beacon, &mockExecutionClient{}))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/committees/:slot", h.GetCommittees)
+
+	tests := []struct {
+		name     string
+		slot     string
+		wantCode int
+		wantSlot int64
+	}{
+		{name: "Slot number", slot: "8200", wantCode: http.StatusOK, wantSlot: 8200},
+		{name: "Head alias", slot: "head", wantCode: http.StatusOK, wantSlot: 8200},
+		{name: "Finalized alias", slot: "finalized", wantCode: http.StatusOK, wantSlot: 8128},
+		{name: "Not a number", slot: "abc", wantCode: http.StatusBadRequest},
+		{name: "Negative slot", slot: "-1", wantCode: http.StatusBadRequest},
+		{name: "Too far past the head", slot: "9000", wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/committees/"+tt.slot, nil))
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantCode, w.Code, w.Body.String())
+			}
+
+			if tt.wantCode != http.StatusOK {
+				var response v1.ErrorResponse
+				if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+					t.Fatalf("Failed to decode response: %v", err)
+				}
+				if response.Details == "" {
+					t.Error("Expected error details")
+				}
+				return
+			}
+
+			var response v1.CommitteesResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if response.Slot != tt.wantSlot {
+				t.Errorf("Expected slot %d, got %d", tt.wantSlot, response.Slot)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"ethereum-validator-api/service"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestEthereumService_GetMEVStats(t *testing.T) {
+	ethService, err := service.NewEthereumService("https://example.com")
+	if err != nil {
+		t.Fatalf("Failed to create EthereumService: %v", err)
+	}
+
+	index := ethService.Index()
+	index.PutReward(32, &service.BlockReward{Status: "mev", Reward: big.NewInt(300), Builder: "Titan", Relay: "ultrasound"})
+	index.PutReward(33, &service.BlockReward{Status: "mev", Reward: big.NewInt(100), Builder: "Titan", Relay: "flashbots"})
+	index.PutReward(34, &service.BlockReward{Status: "mev", Reward: big.NewInt(200), Builder: "beaverbuild", Relay: "ultrasound"})
+	index.PutReward(35, &service.BlockReward{Status: "vanilla", Reward: big.NewInt(40)})
+	index.PutReward(64, &service.BlockReward{Status: "vanilla", Reward: big.NewInt(1000)}) // Outside the range
+
+	got, err := ethService.GetMEVStats(1, 1)
+	if err != nil {
+		t.Fatalf("GetMEVStats() error = %v", err)
+	}
+
+	if got.Blocks != 4 || got.MEVBlocks != 3 || got.VanillaBlocks != 1 || got.MEVShare != 0.75 {
+		t.Errorf("GetMEVStats() counts = %d/%d/%d share %v, want 4/3/1 share 0.75", got.Blocks, got.MEVBlocks, got.VanillaBlocks, got.MEVShare)
+	}
+	// Rewards are aggregated in Wei
+	if got.AverageReward.Int64() != 160e9 || got.MedianReward.Int64() != 100e9 {
+		t.Errorf("GetMEVStats() average/median = %v/%v, want 160/100 GWEI", got.AverageReward, got.MedianReward)
+	}
+	if got.AverageMEV.Int64() != 200e9 || got.AverageVanilla.Int64() != 40e9 {
+		t.Errorf("GetMEVStats() mev/vanilla average = %v/%v, want 200/40 GWEI", got.AverageMEV, got.AverageVanilla)
+	}
+	if len(got.Builders) != 2 || got.Builders[0].Name != "Titan" || got.Builders[0].Blocks != 2 {
+		t.Errorf("GetMEVStats() builders = %+v, want Titan first with 2 blocks", got.Builders)
+	}
+	if len(got.Relays) != 2 || got.Relays[0].Name != "ultrasound" {
+		t.Errorf("GetMEVStats() relays = %+v, want ultrasound first", got.Relays)
+	}
+
+	if _, err := ethService.GetMEVStats(2, 1); !errors.Is(err, service.ErrInvalidRange) {
+		t.Errorf("GetMEVStats() with inverted range error = %v, want ErrInvalidRange", err)
+	}
+}
+
+func TestEthereumService_GetRewardStats(t *testing.T) {
+	ethService, err := service.NewEthereumService("https://example.com")
+	if err != nil {
+		t.Fatalf("Failed to create EthereumService: %v", err)
+	}
+
+	// 100 blocks with rewards 1..100, every fourth built via MEV-Boost
+	for slot := int64(1); slot <= 100; slot++ {
+		status := "vanilla"
+		if slot%4 == 0 {
+			status = "mev"
+		}
+		ethService.Index().PutReward(slot, &service.BlockReward{Status: status, Reward: big.NewInt(slot)})
+	}
+
+	got, err := ethService.GetRewardStats(24 * time.Hour)
+	if err != nil {
+		t.Fatalf("GetRewardStats() error = %v", err)
+	}
+
+	if got.Blocks != 100 || got.ToSlot != 100 {
+		t.Errorf("GetRewardStats() blocks = %d up to slot %d, want 100 up to slot 100", got.Blocks, got.ToSlot)
+	}
+	if got.Min.Int64() != 1e9 || got.Median.Int64() != 50e9 || got.P90.Int64() != 90e9 || got.P99.Int64() != 99e9 || got.Max.Int64() != 100e9 {
+		t.Errorf("GetRewardStats() percentiles = %v/%v/%v/%v/%v, want 1/50/90/99/100 GWEI in Wei", got.Min, got.Median, got.P90, got.P99, got.Max)
+	}
+	if got.MEVRatio != 0.25 {
+		t.Errorf("GetRewardStats() MEV ratio = %v, want 0.25", got.MEVRatio)
+	}
+}
+
+func TestEthereumService_GetBaseFeeSeries(t *testing.T) {
+	ethService, err := service.NewEthereumService("https://example.com")
+	if err != nil {
+		t.Fatalf("Failed to create EthereumService: %v", err)
+	}
+
+	// 20 half full blocks with base fees 10..29 at the end of a day of slots, and blocks outside the window or without
+	// execution payload that are ignored
+	index := ethService.Index()
+	for slot := int64(7200); slot < 7220; slot++ {
+		index.Put(&service.BlockDetail{Slot: slot, GasUsed: 15000000, GasLimit: 30000000, BaseFee: big.NewInt(slot - 7190), Timestamp: 1000 + slot})
+	}
+	index.Put(&service.BlockDetail{Slot: 19, BaseFee: big.NewInt(1000)})
+	index.Put(&service.BlockDetail{Slot: 7000})
+
+	got, err := ethService.GetBaseFeeSeries(24 * time.Hour)
+	if err != nil {
+		t.Fatalf("GetBaseFeeSeries() error = %v", err)
+	}
+
+	if got.FromSlot != 20 || got.ToSlot != 7219 || got.Blocks != 20 || got.BucketSlots != 10 {
+		t.Errorf("GetBaseFeeSeries() range = %d-%d with %d blocks in buckets of %d, want 20-7219 with 20 blocks in buckets of 10", got.FromSlot, got.ToSlot, got.Blocks, got.BucketSlots)
+	}
+	if got.MinBaseFee.Int64() != 10 || got.Median.Int64() != 19 || got.MaxBaseFee.Int64() != 29 {
+		t.Errorf("GetBaseFeeSeries() min/median/max = %v/%v/%v, want 10/19/29", got.MinBaseFee, got.Median, got.MaxBaseFee)
+	}
+	if len(got.Points) != 2 {
+		t.Fatalf("GetBaseFeeSeries() points = %+v, want 2", got.Points)
+	}
+	first := got.Points[0]
+	if first.Slot != 7200 || first.Timestamp != 8200 || first.Blocks != 10 || first.BaseFee.Int64() != 14 || first.MinBaseFee.Int64() != 10 || first.MaxBaseFee.Int64() != 19 || first.Utilization != 50 {
+		t.Errorf("GetBaseFeeSeries() first point = %+v, want slots 7200-7209 averaging 14", first)
+	}
+
+	gas, err := ethService.GetBlockGas(context.Background(), 7205)
+	if err != nil {
+		t.Fatalf("GetBlockGas() error = %v", err)
+	}
+	if gas.Utilization != 50 || gas.BaseFee.Int64() != 15 {
+		t.Errorf("GetBlockGas() = %+v, want 50%% utilization and base fee 15", gas)
+	}
+}
@@ -0,0 +1,118 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"ethereum-validator-api/api/models/v1"
+	"ethereum-validator-api/handler"
+	"ethereum-validator-api/service"
+	"github.com/gin-gonic/gin"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// syncProbabilityService returns a service in sync period 3 with 1024 active validators of 32 ETH, of which validator 7
+// sits in the next committee and validator 8 does not
+func syncProbabilityService() *service.EthereumService {
+	return service.NewEthereumServiceWithClients(&mockBeaconClient{responses: map[string]string{
+		"/eth/v1/beacon/headers/head":                           `{"data":{"header":{"message":{"slot":"24581"}}}}`,
+		"/eth/v1/beacon/states/head/validators?status=active":   `{"data":[` + strings.TrimSuffix(strings.Repeat(`{"validator":{"effective_balance":"32000000000"}},`, 1024), ",") + `]}`,
+		"/eth/v1/beacon/states/head/sync_committees?epoch=1024": `{"data":{"validators":["11","7","11"]}}`,
+		"/eth/v1/beacon/states/head/validators?id=11,7":         `{"data":[]}`,
+		"/eth/v1/beacon/states/head/validators/7":               `{"data":{"index":"7","status":"active_ongoing","validator":{"effective_balance":"32000000000"}}}`,
+		"/eth/v1/beacon/states/head/validators/8":               `{"data":{"index":"8","status":"active_ongoing","validator":{"effective_balance":"32000000000"}}}`,
+		"/eth/v1/beacon/states/head/validators/9":               `{"data":{"index":"9","status":"exited_unslashed","validator":{"effective_balance":"32000000000"}}}`,
+	}}, &mockExecutionClient{})
+}
+
+func TestEthereumService_GetSyncCommitteeProbability(t *testing.T) {
+	// Each of the 512 seats goes to a validator with a 1 in 1024 chance
+	period := 1 - math.Pow(1-1.0/1024, service.SyncCommitteeSize)
+
+	tests := []struct {
+		name        string
+		validatorID string
+		periods     int64
+		wantNext    float64
+		wantPeriod  float64
+		want        float64
+		wantExpect  float64
+	}{
+		{"In the next committee", "7", 3, 1, period, 1, 1 + 2*period},
+		{"Not in the next committee", "8", 2, 0, period, period, period},
+		{"Exited", "9", 5, 0, 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := syncProbabilityService().GetSyncCommitteeProbability(context.Background(), tt.validatorID, tt.periods)
+			if err != nil {
+				t.Fatalf("GetSyncCommitteeProbability() error = %v", err)
+			}
+			if got.ActiveValidators != 1024 || got.ActiveBalance != 1024*32e9 || len(got.Periods) != int(tt.periods) {
+				t.Fatalf("GetSyncCommitteeProbability() = %+v, want %d periods over 1024 validators", got, tt.periods)
+			}
+
+			next := got.Periods[0]
+			if next != (service.SyncPeriodProbability{Period: 4, StartSlot: 32768, EndSlot: 40959, Probability: tt.wantNext, Known: true}) {
+				t.Errorf("Next period = %+v, want the known period 4 with probability %v", next, tt.wantNext)
+			}
+			if following := got.Periods[1]; following.Known || following.Period != 5 || math.Abs(following.Probability-tt.wantPeriod) > 1e-12 {
+				t.Errorf("Following period = %+v, want the unknown period 5 with probability %v", following, tt.wantPeriod)
+			}
+			if math.Abs(got.PeriodProbability-tt.wantPeriod) > 1e-12 || math.Abs(got.Probability-tt.want) > 1e-12 || math.Abs(got.ExpectedSelections-tt.wantExpect) > 1e-12 {
+				t.Errorf("Probability per period %v, overall %v, expected %v, want %v, %v and %v", got.PeriodProbability, got.Probability, got.ExpectedSelections, tt.wantPeriod, tt.want, tt.wantExpect)
+			}
+		})
+	}
+
+	if _, err := syncProbabilityService().GetSyncCommitteeProbability(context.Background(), "7", 0); !errors.Is(err, service.ErrInvalidRange) {
+		t.Errorf("GetSyncCommitteeProbability() of 0 periods error = %v, want ErrInvalidRange", err)
+	}
+}
+
+func TestHandler_GetValidatorSyncProbability(t *testing.T) {
+	h := handler.NewHandler(syncProbabilityService())
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/validator/:id/syncprobability", h.GetValidatorSyncProbability)
+
+	tests := []struct {
+		name        string
+		path        string
+		wantStatus  int
+		wantPeriods int
+	}{
+		{"Default periods", "/validator/8/syncprobability", http.StatusOK, 10},
+		{"Periods", "/validator/8/syncprobability?periods=4", http.StatusOK, 4},
+		{"Too many periods", "/validator/8/syncprobability?periods=101", http.StatusBadRequest, 0},
+		{"Invalid periods", "/validator/8/syncprobability?periods=abc", http.StatusBadRequest, 0},
+		{"Invalid validator", "/validator/abc/syncprobability", http.StatusBadRequest, 0},
+		{"Unknown validator", "/validator/12/syncprobability", http.StatusNotFound, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if w.Code != tt.wantStatus {
+				t.Fatalf("GET %s = %d %s, want %d", tt.path, w.Code, w.Body.String(), tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var response v1.SyncProbabilityResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+			if len(response.Periods) != tt.wantPeriods || response.Periods[0].SyncPeriod != 4 || !response.Periods[0].Known || response.Periods[1].Known {
+				t.Errorf("GET %s = %+v, want %d periods starting with the known period 4", tt.path, response, tt.wantPeriods)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package tests
+
+import (
+	"encoding/json"
+	"ethereum-validator-api/api/models/v1"
+	"ethereum-validator-api/handler"
+	"ethereum-validator-api/service"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gin-gonic/gin"
+)
+
+func TestTopTransactions(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	signer := types.LatestSignerForChainID(big.NewInt(1))
+	recipient := common.HexToAddress("0x3fc91a3afd70395cd496c647d5a6cc9d4b2b7fad")
+	transfer := types.MustSignNewTx(key, signer, &types.DynamicFeeTx{ChainID: big.NewInt(1), To: &recipient, Gas: 21000, GasFeeCap: big.NewInt(20), GasTipCap: big.NewInt(5)})
+	creation := types.MustSignNewTx(key, signer, &types.DynamicFeeTx{ChainID: big.NewInt(1), Nonce: 1, Gas: 150000, GasFeeCap: big.NewInt(20), GasTipCap: big.NewInt(1)})
+
+	header := &types.Header{Number: big.NewInt(100), BaseFee: big.NewInt(10)}
+	block := types.NewBlockWithHeader(header).WithBody(types.Body{Transactions: []*types.Transaction{transfer, creation}})
+	// 21000 gas at 5 wei and 150000 gas at 1 wei of priority fee
+	receipts := []*types.Receipt{
+		{TxHash: transfer.Hash(), TransactionIndex: 0, Type: types.DynamicFeeTxType, GasUsed: 21000, EffectiveGasPrice: big.NewInt(15)},
+		{TxHash: creation.Hash(), TransactionIndex: 1, Type: types.DynamicFeeTxType, GasUsed: 150000, EffectiveGasPrice: big.NewInt(11)},
+	}
+	execution := &mockExecutionClient{
+		blocks:   []*types.Block{block},
+		receipts: map[common.Hash][]*types.Receipt{block.Hash(): receipts},
+	}
+	beacon := &mockBeaconClient{responses: map[string]string{
+		"/eth/v1/beacon/blocks/100/root":                  `{"data":{"root":"0x01"}}`,
+		"/eth/v2/beacon/blocks/100":                       beaconBlockWithPayload(100),
+		"/eth/v1/beacon/headers/head":                     `{"data":{"root":"0xabc","header":{"message":{"slot":"128"}}}}`,
+		"/eth/v1/beacon/states/head/finality_checkpoints": `{"data":{"finalized":{"epoch":"2","root":"0x01"}}}`,
+	}}
+	h := handler.NewHandler(service.NewEthereumServiceWithClients(beacon, execution))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/block/:slot/toptxs", h.GetTopTransactions)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/block/100/toptxs?n=1&unit=wei", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var response v1.TopTransactionsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(response.Transactions) != 1 {
+		t.Fatalf("Expected the top transaction only, got %+v", response.Transactions)
+	}
+	top := response.Transactions[0]
+	if top.Index != 1 || top.Contribution != "150000" || top.EffectivePriorityFee != "1" || top.To != "" {
+		t.Errorf("Expected the contract creation contributing 150000 wei first, got %+v", top)
+	}
+	if top.From != crypto.PubkeyToAddress(key.PublicKey).Hex() {
+		t.Errorf("Expected the sender %s, got %s", crypto.PubkeyToAddress(key.PublicKey).Hex(), top.From)
+	}
+	if top.Share < 0.588 || top.Share > 0.589 {
+		t.Errorf("Expected a share of 150000/255000, got %v", top.Share)
+	}
+
+	for _, n := range []string{"0", "101", "ten"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/block/100/toptxs?n="+n, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for n=%s, got %d", n, w.Code)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package tests
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"ethereum-validator-api/service"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEthereumService_SetProviderAuth(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "secret" || len(r.TLS.PeerCertificates) == 0 {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"code":401,"message":"unauthorized"}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/eth/v1/beacon/headers/head":
+			w.Write([]byte(`{"data":{"root":"0xabc","header":{"message":{"slot":"8192"}}}}`))
+		default:
+			w.Write([]byte(`{"data":{"finalized":{"epoch":"254","root":"0x01"}}}`))
+		}
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	// The test server's certificate doubles as the CA and the client certificate
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	key, err := x509.MarshalPKCS8PrivateKey(server.TLS.Certificates[0].PrivateKey)
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %v", err)
+	}
+	os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0600)
+	os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: key}), 0600)
+
+	ethService, err := service.NewEthereumService(server.URL)
+	if err != nil {
+		t.Fatalf("NewEthereumService() unexpected error: %v", err)
+	}
+	if _, err := ethService.GetChainHead(context.Background()); err == nil {
+		t.Fatal("GetChainHead() succeeded without provider authentication")
+	}
+
+	err = ethService.SetProviderAuth(server.URL, service.ProviderAuth{
+		Headers:  map[string]string{"X-Api-Key": "secret"},
+		CertFile: certFile,
+		KeyFile:  keyFile,
+		CAFile:   certFile,
+	})
+	if err != nil {
+		t.Fatalf("SetProviderAuth() unexpected error: %v", err)
+	}
+
+	head, err := ethService.GetChainHead(context.Background())
+	if err != nil {
+		t.Fatalf("GetChainHead() unexpected error: %v", err)
+	}
+	if head.Slot != 8192 {
+		t.Errorf("GetChainHead() slot = %d, want 8192", head.Slot)
+	}
+
+	if err := ethService.SetProviderAuth(server.URL, service.ProviderAuth{CertFile: certFile}); err == nil {
+		t.Error("SetProviderAuth() expected error for a certificate without key")
+	}
+}
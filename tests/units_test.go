@@ -0,0 +1,135 @@
+package tests
+
+import (
+	"encoding/json"
+	"ethereum-validator-api/api/models/v1"
+	"ethereum-validator-api/handler"
+	"ethereum-validator-api/service"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/gin-gonic/gin"
+)
+
+func TestBlockRewardUnits(t *testing.T) {
+	header := &types.Header{Number: big.NewInt(100), BaseFee: big.NewInt(10)}
+	block := types.NewBlockWithHeader(header)
+	// 2000000001 wei priority fee for 1M gas, not a whole amount of gwei
+	receipts := []*types.Receipt{
+		{TxHash: common.HexToHash("0x01"), GasUsed: 1000000, EffectiveGasPrice: big.NewInt(2000000011)},
+	}
+	execution := &mockExecutionClient{
+		blocks:   []*types.Block{block},
+		receipts: map[common.Hash][]*types.Receipt{block.Hash(): receipts},
+	}
+	beacon := &mockBeaconClient{responses: map[string]string{
+		"/eth/v1/beacon/blocks/100/root":                  `{"data":{"root":"0x01"}}`,
+		"/eth/v2/beacon/blocks/100":                       beaconBlockWithPayload(100),
+		"/eth/v1/beacon/headers/head":                     `{"data":{"root":"0xabc","header":{"message":{"slot":"128"}}}}`,
+		"/eth/v1/beacon/states/head/finality_checkpoints": `{"data":{"finalized":{"epoch":"2","root":"0x01"}}}`,
+	}}
+	h := handler.NewHandler(service.NewEthereumServiceWithClients(beacon, execution))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/blockreward/:slot", h.GetBlockReward)
+
+	tests := []struct {
+		name       string
+		query      string
+		wantUnit   string
+		wantReward string
+	}{
+		{"Default unit", "", "gwei", "2000000.001"},
+		{"Wei", "?unit=wei", "wei", "2000000001000000"},
+		{"ETH", "?unit=ETH", "eth", "0.002000000001"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/blockreward/100"+tt.query, nil))
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+			}
+			var response v1.BlockRewardResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if response.Unit != tt.wantUnit || response.Reward != tt.wantReward || response.BlockInfo.ProposerPayment != tt.wantReward {
+				t.Errorf("Expected reward %s %s, got %s %s", tt.wantReward, tt.wantUnit, response.Reward, response.Unit)
+			}
+			// The deprecated aliases stay in whole GWEI whatever the unit
+			if response.RewardGwei != 2000000 || response.BlockInfo.ProposerPaymentGwei != 2000000 {
+				t.Errorf("Expected deprecated reward of 2000000 gwei, got %d and %d", response.RewardGwei, response.BlockInfo.ProposerPaymentGwei)
+			}
+		})
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/blockreward/100?unit=finney", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an unknown unit, got %d", w.Code)
+	}
+}
+
+func TestRewardStatsUnits(t *testing.T) {
+	ethService, err := service.NewEthereumService("https://example.com")
+	if err != nil {
+		t.Fatalf("Failed to create EthereumService: %v", err)
+	}
+	ethService.Index().PutReward(32, &service.BlockReward{Status: "mev", Reward: big.NewInt(1), RewardWei: big.NewInt(1500000001)})
+	h := handler.NewHandler(ethService)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/stats/rewards", h.GetRewardStats)
+	router.GET("/stats/mev", h.GetMEVStats)
+
+	tests := []struct {
+		name       string
+		query      string
+		wantUnit   string
+		wantReward string
+	}{
+		{"Default unit", "", "gwei", "1.500000001"},
+		{"Wei", "?unit=wei", "wei", "1500000001"},
+		{"ETH", "?unit=eth", "eth", "0.000000001500000001"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stats/rewards"+tt.query, nil))
+			var rewards v1.RewardStatsResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &rewards); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if rewards.Unit != tt.wantUnit || rewards.Min != tt.wantReward || rewards.Max != tt.wantReward {
+				t.Errorf("Expected reward statistics of %s %s, got %s to %s %s", tt.wantReward, tt.wantUnit, rewards.Min, rewards.Max, rewards.Unit)
+			}
+
+			w = httptest.NewRecorder()
+			sep := "?"
+			if tt.query != "" {
+				sep = "&"
+			}
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stats/mev"+tt.query+sep+"from_epoch=1&to_epoch=1", nil))
+			var mev v1.MEVStatsResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &mev); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if mev.Unit != tt.wantUnit || mev.AverageMEV != tt.wantReward || len(mev.Pools) != 1 || mev.Pools[0].AverageReward != tt.wantReward {
+				t.Errorf("Expected MEV statistics of %s %s, got %+v", tt.wantReward, tt.wantUnit, mev)
+			}
+		})
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stats/rewards?unit=finney", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an unknown unit, got %d", w.Code)
+	}
+}
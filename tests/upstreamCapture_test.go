@@ -0,0 +1,142 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"ethereum-validator-api/api/models/v1"
+	"ethereum-validator-api/handler"
+	"ethereum-validator-api/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// captureAPIKey is embedded in the provider URL like QuickNode does
+const captureAPIKey = "a1b2c3d4e5f6a7b8c9d0"
+
+// captureUpstream serves the chain head under the API key path, the head root is longRoot long
+func captureUpstream(longRoot int) *httptest.Server {
+	root := "0x" + strings.Repeat("ab", longRoot/2)
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch strings.TrimPrefix(r.URL.Path, "/"+captureAPIKey) {
+		case "/eth/v1/beacon/headers/head":
+			w.Write([]byte(`{"data":{"root":"` + root + `","header":{"message":{"slot":"8192"}}}}`))
+		case "/eth/v1/beacon/states/head/finality_checkpoints":
+			w.Write([]byte(`{"data":{"finalized":{"epoch":"254","root":"0x01"}},"echo":"` + captureAPIKey + `"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestUpstreamCapture(t *testing.T) {
+	upstream := captureUpstream(5000)
+	defer upstream.Close()
+
+	service.SetUpstreamRateLimit(100, 10)
+	defer service.SetUpstreamRateLimit(1, 1)
+	ethService, err := service.NewEthereumService(upstream.URL + "/" + captureAPIKey)
+	if err != nil {
+		t.Fatalf("NewEthereumService() unexpected error: %v", err)
+	}
+
+	// Nothing is captured until capturing is enabled
+	if _, err := ethService.GetChainHead(context.Background()); err != nil {
+		t.Fatalf("GetChainHead() unexpected error: %v", err)
+	}
+	if exchanges := ethService.UpstreamCapture().Exchanges(); len(exchanges) != 0 {
+		t.Fatalf("Exchanges() while disabled = %+v, want none", exchanges)
+	}
+
+	ethService.UpstreamCapture().SetEnabled(true)
+	ethService.ClearCache()
+	if _, err := ethService.GetChainHead(context.Background()); err != nil {
+		t.Fatalf("GetChainHead() unexpected error: %v", err)
+	}
+	exchanges := ethService.UpstreamCapture().Exchanges()
+	if len(exchanges) != 2 {
+		t.Fatalf("Exchanges() = %+v, want the header and checkpoints requests", exchanges)
+	}
+	for _, exchange := range exchanges {
+		if exchange.Method != http.MethodGet || exchange.Status != http.StatusOK || !strings.HasPrefix(exchange.URL, upstream.URL+"/[REDACTED]/eth/v1/beacon/") {
+			t.Errorf("Exchange = %+v, want a successful GET with the API key redacted", exchange)
+		}
+		if strings.Contains(exchange.URL+exchange.ResponseBody, captureAPIKey) {
+			t.Errorf("Exchange %s contains the API key: %s", exchange.URL, exchange.ResponseBody)
+		}
+
+		// The head response is cut off after 4 KiB
+		if strings.HasSuffix(exchange.URL, "/headers/head") && (len(exchange.ResponseBody) != 4096 || !exchange.Truncated) {
+			t.Errorf("Head exchange body of %d bytes truncated %v, want 4096 bytes truncated", len(exchange.ResponseBody), exchange.Truncated)
+		}
+		if strings.HasSuffix(exchange.URL, "/finality_checkpoints") && (exchange.Truncated || !strings.Contains(exchange.ResponseBody, `"echo":"[REDACTED]"`)) {
+			t.Errorf("Checkpoints exchange body = %s, want the whole body with the API key redacted", exchange.ResponseBody)
+		}
+	}
+
+	// A smaller buffer keeps only the last exchanges
+	ethService.SetUpstreamCapture(service.NewUpstreamCapture(1))
+	ethService.UpstreamCapture().SetEnabled(true)
+	ethService.ClearCache()
+	if _, err := ethService.GetChainHead(context.Background()); err != nil {
+		t.Fatalf("GetChainHead() unexpected error: %v", err)
+	}
+	if exchanges := ethService.UpstreamCapture().Exchanges(); len(exchanges) != 1 {
+		t.Errorf("Exchanges() of a buffer of 1 = %d exchanges, want 1", len(exchanges))
+	}
+}
+
+func TestHandler_UpstreamCapture(t *testing.T) {
+	upstream := captureUpstream(64)
+	defer upstream.Close()
+
+	service.SetUpstreamRateLimit(100, 10)
+	defer service.SetUpstreamRateLimit(1, 1)
+	ethService, err := service.NewEthereumService(upstream.URL + "/" + captureAPIKey)
+	if err != nil {
+		t.Fatalf("NewEthereumService() unexpected error: %v", err)
+	}
+
+	h := handler.NewHandler(ethService)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/admin/debug/upstream", h.GetUpstreamCapture)
+	router.PUT("/admin/debug/upstream", h.PutUpstreamCapture)
+
+	send := func(method, body string) (int, v1.UpstreamCaptureResponse) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, "/admin/debug/upstream", strings.NewReader(body)))
+		var response v1.UpstreamCaptureResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return w.Code, response
+	}
+
+	if code, response := send(http.MethodPut, `{"enabled":true}`); code != http.StatusOK || !response.Enabled || response.Size != service.DefaultUpstreamCaptureSize {
+		t.Fatalf("PUT enabled = %d %+v, want the capture enabled", code, response)
+	}
+	if _, err := ethService.GetChainHead(context.Background()); err != nil {
+		t.Fatalf("GetChainHead() unexpected error: %v", err)
+	}
+
+	code, response := send(http.MethodGet, "")
+	if code != http.StatusOK || len(response.Exchanges) != 2 || response.Exchanges[0].Status != http.StatusOK || response.Exchanges[0].ResponseBody == "" {
+		t.Fatalf("GET = %d %+v, want the two exchanges of the chain head", code, response)
+	}
+	if response.Exchanges[0].Time < response.Exchanges[1].Time {
+		t.Errorf("GET exchanges at %s and %s, want the newest first", response.Exchanges[0].Time, response.Exchanges[1].Time)
+	}
+
+	if code, response := send(http.MethodPut, `{"enabled":false,"clear":true}`); code != http.StatusOK || response.Enabled || len(response.Exchanges) != 0 {
+		t.Errorf("PUT disabled and cleared = %d %+v, want an empty disabled capture", code, response)
+	}
+	if code, _ := send(http.MethodPut, `{}`); code != http.StatusBadRequest {
+		t.Errorf("PUT without enabled = %d, want 400", code)
+	}
+
+	entries := ethService.Audit().Query(service.AuditFilter{Action: service.AuditCapturePut})
+	if len(entries) != 2 {
+		t.Errorf("Audit log has %d capture.put entries, want 2", len(entries))
+	}
+}
@@ -0,0 +1,72 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"ethereum-validator-api/service"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestUpstreamErrors(t *testing.T) {
+	service.SetUpstreamRateLimit(100, 10)
+	defer service.SetUpstreamRateLimit(1, 1)
+
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr error
+	}{
+		{name: "Not synced", status: http.StatusServiceUnavailable, body: `{"code":503,"message":"Beacon node is currently syncing"}`, wantErr: service.ErrNotSynced},
+		{name: "Truncated body", status: http.StatusOK, body: `{"data":{"root":"0xabc","header":{"mess`, wantErr: service.ErrUpstreamInvalidResponse},
+		{name: "Wrong types", status: http.StatusOK, body: `{"data":[1,2,3]}`, wantErr: service.ErrUpstreamInvalidResponse},
+		{name: "Server error", status: http.StatusInternalServerError, body: `{"code":500,"message":"internal error"}`, wantErr: service.ErrRPCFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer upstream.Close()
+
+			ethService, err := service.NewEthereumService(upstream.URL)
+			if err != nil {
+				t.Fatalf("NewEthereumService() unexpected error: %v", err)
+			}
+			if _, err := ethService.GetChainHead(context.Background()); !errors.Is(err, tt.wantErr) {
+				t.Errorf("GetChainHead() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("Rate limited", func(t *testing.T) {
+		clock := newMockClock(time.Now())
+		service.SetUpstreamClock(clock)
+		defer service.SetUpstreamClock(service.SystemClock{})
+
+		upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", "60")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer upstream.Close()
+
+		// The interactive request isn't held for the Retry-After, it fails with the backoff left
+		var out interface{}
+		err := service.NewHTTPBeaconClient(upstream.URL, upstream.Client()).GetJSON(context.Background(), "/eth/v1/node/syncing", &out)
+		var rateLimitErr *service.RateLimitError
+		if !errors.As(err, &rateLimitErr) || rateLimitErr.RetryAfter != time.Minute {
+			t.Fatalf("GetJSON() error = %v, want a RateLimitError retrying in 1m", err)
+		}
+
+		clock.Advance(45 * time.Second)
+		err = service.NewHTTPBeaconClient(upstream.URL, upstream.Client()).GetJSON(context.Background(), "/eth/v1/node/syncing", &out)
+		if !errors.As(err, &rateLimitErr) || rateLimitErr.RetryAfter != 15*time.Second {
+			t.Errorf("GetJSON() error = %v, want a RateLimitError retrying in 15s", err)
+		}
+	})
+}
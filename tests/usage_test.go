@@ -0,0 +1,115 @@
+package tests
+
+import (
+	"encoding/json"
+	"ethereum-validator-api/api/models/v1"
+	"ethereum-validator-api/handler"
+	"ethereum-validator-api/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUsageTrackerPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usage.json")
+	tracker, err := service.LoadUsageTracker(path)
+	if err != nil {
+		t.Fatalf("LoadUsageTracker() error for a missing file: %v", err)
+	}
+
+	day := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tracker.Record("ip:203.0.113.7", "GET /chain/head", day, false)
+	tracker.Record("ip:203.0.113.7", "GET /chain/head", day, true)
+	tracker.Record("ip:203.0.113.7", "GET /chain/head", day.AddDate(0, 0, 1), false)
+	tracker.Record("key:abc", "GET /validator/:id", day.AddDate(0, 0, -40), false)
+	if removed := tracker.Prune(day.AddDate(0, 0, -30)); removed != 1 {
+		t.Errorf("Prune() removed %d records, want the one from 40 days ago", removed)
+	}
+	if err := tracker.Save(); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	loaded, err := service.LoadUsageTracker(path)
+	if err != nil {
+		t.Fatalf("LoadUsageTracker() error: %v", err)
+	}
+	records := loaded.Query(service.UsageFilter{From: day, To: day})
+	if len(records) != 1 || records[0].Requests != 2 || records[0].Errors != 1 || records[0].Day != "2024-01-01" {
+		t.Errorf("Query() for 2024-01-01 = %+v, want 2 requests with 1 error", records)
+	}
+	grouped := loaded.Query(service.UsageFilter{GroupBy: service.UsageByClient})
+	if len(grouped) != 1 || grouped[0].Requests != 3 || grouped[0].Day != "" || grouped[0].Endpoint != "" {
+		t.Errorf("Query() grouped by client = %+v, want 3 requests of one client", grouped)
+	}
+}
+
+func TestGetUsage(t *testing.T) {
+	ethService := service.NewEthereumServiceWithClients(&mockBeaconClient{}, &mockExecutionClient{})
+	h := handler.NewHandler(ethService)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(h.TrackUsage())
+	router.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/private", handler.APIKeyAuth("secret"), func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/admin/usage", h.GetUsage)
+
+	send := func(path, apiKey string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if apiKey != "" {
+			req.Header.Set("X-API-Key", apiKey)
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+	send("/private", "secret")
+	send("/private", "secret")
+	send("/private", "guess")
+	send("/ping", "made-up")
+	send("/ping", "")
+	send("/unknown", "")
+
+	query := func(path string) v1.UsageResponse {
+		t.Helper()
+		w := send(path, "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var response v1.UsageResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+		return response
+	}
+
+	// Requests with the validated key are counted for the key, the rejected one for the IP address
+	response := query("/admin/usage?endpoint=GET%20/private&group_by=client")
+	if response.Requests != 3 || len(response.Records) != 2 {
+		t.Fatalf("Expected 3 requests of 2 clients, got %+v", response)
+	}
+	keyClient := service.UsageClient("secret", "")
+	for _, record := range response.Records {
+		if record.Client == keyClient && record.Requests != 2 {
+			t.Errorf("Expected 2 requests with the API key, got %d", record.Requests)
+		}
+		if record.Client == "secret" || record.Client == "key:secret" {
+			t.Error("Expected the API key to be hashed")
+		}
+	}
+
+	// Keys no middleware validated don't identify a client
+	response = query("/admin/usage?endpoint=GET%20/ping&group_by=client")
+	if response.Requests != 2 || len(response.Records) != 1 || !strings.HasPrefix(response.Records[0].Client, "ip:") {
+		t.Errorf("Expected 2 requests of one IP address, got %+v", response)
+	}
+
+	for _, path := range []string{"/admin/usage?from=01-01-2024", "/admin/usage?group_by=week"} {
+		if w := send(path, ""); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for %s, got %d", path, w.Code)
+		}
+	}
+}
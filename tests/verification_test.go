@@ -0,0 +1,151 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"ethereum-validator-api/api/models/v1"
+	"ethereum-validator-api/handler"
+	"ethereum-validator-api/service"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/gin-gonic/gin"
+)
+
+// beaconBlockAt returns a beacon block response for a slot carrying the execution block with the given number and hash
+func beaconBlockAt(slot, blockNumber int64, blockHash string) string {
+	return fmt.Sprintf(`{"data":{"message":{"slot":"%d","proposer_index":"7","body":{"execution_payload":{"block_hash":"%s","block_number":"%d"}}}}}`, slot, blockHash, blockNumber)
+}
+
+func TestVerificationRejectsInconsistentBlocks(t *testing.T) {
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(150), BaseFee: big.NewInt(10)})
+	execution := &mockExecutionClient{blocks: []*types.Block{block}}
+
+	tests := []struct {
+		name        string
+		beaconBlock string
+		wantErr     error
+	}{
+		{"Consistent block", beaconBlockAt(200, 150, block.Hash().Hex()), nil},
+		{"Block of another slot", beaconBlockAt(199, 150, block.Hash().Hex()), service.ErrInconsistentResponse},
+		{"Execution block not committed to", beaconBlockAt(200, 150, "0x"+fmt.Sprintf("%064x", 1)), service.ErrInconsistentResponse},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			beacon := &mockBeaconClient{responses: map[string]string{
+				"/eth/v1/beacon/blocks/200/root": `{"data":{"root":"0x01"}}`,
+				"/eth/v2/beacon/blocks/200":      tt.beaconBlock,
+			}}
+
+			// Without verification the block is used as returned
+			ethService := service.NewEthereumServiceWithClients(beacon, execution)
+			if _, err := ethService.GetBlockRewardBySlot(context.Background(), 200); err != nil {
+				t.Fatalf("GetBlockRewardBySlot() unexpected error without verification: %v", err)
+			}
+
+			ethService = service.NewEthereumServiceWithClients(beacon, execution)
+			ethService.EnableVerification(true)
+			_, err := ethService.GetBlockRewardBySlot(context.Background(), 200)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("GetBlockRewardBySlot() unexpected error: %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("GetBlockRewardBySlot() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerificationCrossChecksProviders(t *testing.T) {
+	hash := "0x" + fmt.Sprintf("%064x", 150)
+	beacon := &mockBeaconClient{responses: map[string]string{
+		"/eth/v1/beacon/blocks/200/root":                  `{"data":{"root":"0x01"}}`,
+		"/eth/v2/beacon/blocks/200":                       beaconBlockAt(200, 150, hash),
+		"/eth/v1/beacon/headers/head":                     `{"data":{"root":"0xabc","header":{"message":{"slot":"256"}}}}`,
+		"/eth/v1/beacon/states/head/finality_checkpoints": `{"data":{"finalized":{"epoch":"2","root":"0x01"}}}`,
+	}}
+	execution := &mockExecutionClient{results: map[string]string{
+		"eth_getBlockByNumber": `{"number":"0x96","hash":"` + hash + `","timestamp":"0x0"}`,
+	}}
+
+	// The secondary provider agrees on the execution block but saw another beacon block at the slot
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/eth/v1/beacon/blocks/200/root":
+			w.Write([]byte(`{"data":{"root":"0x02"}}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/":
+			var call struct {
+				ID json.RawMessage `json:"id"`
+			}
+			json.NewDecoder(r.Body).Decode(&call)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"jsonrpc": "2.0",
+				"id":      call.ID,
+				"result":  map[string]string{"number": "0x96", "hash": hash, "timestamp": "0x0"},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer secondary.Close()
+
+	ethService := service.NewEthereumServiceWithClients(beacon, execution)
+	if err := ethService.EnableHedging(context.Background(), secondary.URL, time.Second); err != nil {
+		t.Fatalf("EnableHedging() unexpected error: %v", err)
+	}
+	h := handler.NewHandler(ethService)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/block/:slot", h.GetBlock)
+
+	// The field is only reported while verification is enabled
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/block/200", nil))
+	var response v1.BlockDetailResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Verification != nil {
+		t.Errorf("Expected no verification while disabled, got %+v", response.Verification)
+	}
+
+	ethService.EnableVerification(true)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/block/200", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	verification := response.Verification
+	if verification == nil || verification.Providers != 2 || !verification.Divergent {
+		t.Fatalf("Expected a divergence between 2 providers, got %+v", verification)
+	}
+	want := v1.DivergenceResponse{Provider: "secondary", Field: "block_root", Expected: "0x01", Actual: "0x02"}
+	if len(verification.Divergences) != 1 || verification.Divergences[0] != want {
+		t.Errorf("Expected divergence %+v, got %+v", want, verification.Divergences)
+	}
+}
+
+func TestVerificationRejectsMismatchedExecutionBlocks(t *testing.T) {
+	requested := "0x" + fmt.Sprintf("%064x", 150)
+	execution := &mockExecutionClient{results: map[string]string{
+		"eth_getBlockByHash": `{"number":"0x96","hash":"0x` + fmt.Sprintf("%064x", 151) + `","timestamp":"0x0"}`,
+	}}
+	ethService := service.NewEthereumServiceWithClients(&mockBeaconClient{}, execution)
+	ethService.EnableVerification(true)
+
+	if _, err := ethService.ResolveExecutionBlock(context.Background(), requested); !errors.Is(err, service.ErrInconsistentResponse) {
+		t.Errorf("ResolveExecutionBlock() error = %v, want %v", err, service.ErrInconsistentResponse)
+	}
+}
@@ -0,0 +1,244 @@
+package tests
+
+import (
+	"encoding/json"
+	"errors"
+	"ethereum-validator-api/api/models/v1"
+	"ethereum-validator-api/handler"
+	"ethereum-validator-api/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWatchlistStore_Isolation(t *testing.T) {
+	store := service.NewWatchlistStore()
+	for _, key := range []string{"alice-key", "bob-key"} {
+		if err := store.AddAccount(key); err != nil {
+			t.Fatalf("AddAccount(%q) error = %v", key, err)
+		}
+	}
+
+	if err := store.Watch("alice-key", 42, 7, 42); err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+	if err := store.Watch("bob-key", 9); err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+	if err := store.SetRules("alice-key", []service.AlertRule{{Event: service.AlertMissedProposal}}); err != nil {
+		t.Fatalf("SetRules() error = %v", err)
+	}
+	if err := store.SetChannels("alice-key", []string{"discord:https://discord.com/api/webhooks/1/alice"}); err != nil {
+		t.Fatalf("SetChannels() error = %v", err)
+	}
+
+	alice, err := store.Get("alice-key")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if !reflect.DeepEqual(alice.Validators, []int64{7, 42}) || len(alice.Rules) != 1 || len(alice.Channels) != 1 {
+		t.Errorf("Get(alice) = %+v, want validators [7 42] with a rule and a channel", alice)
+	}
+	bob, err := store.Get("bob-key")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if !reflect.DeepEqual(bob.Validators, []int64{9}) || len(bob.Rules) != 0 || len(bob.Channels) != 0 {
+		t.Errorf("Get(bob) = %+v, want only validator 9", bob)
+	}
+
+	// Changing a returned watchlist doesn't change the store
+	alice.Validators[0] = 1000
+	if again, _ := store.Get("alice-key"); again.Validators[0] != 7 {
+		t.Errorf("Get() returned the stored watchlist instead of a copy")
+	}
+
+	// Unknown keys can't read or change any watchlist
+	if _, err := store.Get("mallory-key"); !errors.Is(err, service.ErrUnknownAccount) {
+		t.Errorf("Get() of an unknown key error = %v, want ErrUnknownAccount", err)
+	}
+	if err := store.Unwatch("mallory-key", 42); !errors.Is(err, service.ErrUnknownAccount) {
+		t.Errorf("Unwatch() of an unknown key error = %v, want ErrUnknownAccount", err)
+	}
+
+	// Alerts only reach the accounts watching the validator with a rule for the event
+	if got := store.Watchers(service.AlertMissedProposal, 42); !reflect.DeepEqual(got, []string{"discord:https://discord.com/api/webhooks/1/alice"}) {
+		t.Errorf("Watchers(missed_proposal, 42) = %v, want alice's channel", got)
+	}
+	if got := store.Watchers(service.AlertMissedProposal, 9); len(got) != 0 {
+		t.Errorf("Watchers(missed_proposal, 9) = %v, want none as bob has no rule", got)
+	}
+	if got := store.Watchers(service.AlertSlashed, 42); len(got) != 0 {
+		t.Errorf("Watchers(slashed, 42) = %v, want none as alice has no slashing rule", got)
+	}
+
+	if err := store.RemoveAccount("bob-key"); err != nil {
+		t.Fatalf("RemoveAccount() error = %v", err)
+	}
+	if _, err := store.Get("bob-key"); !errors.Is(err, service.ErrUnknownAccount) || store.Len() != 1 {
+		t.Errorf("After RemoveAccount() Get() error = %v with %d accounts, want ErrUnknownAccount and 1", err, store.Len())
+	}
+}
+
+func TestWatchlistStore_Validation(t *testing.T) {
+	store := service.NewWatchlistStore()
+	store.AddAccount("key")
+	store.Watch("key", 1)
+
+	if err := store.Watch("key", 2, -1); !errors.Is(err, service.ErrInvalidValidatorID) {
+		t.Errorf("Watch(-1) error = %v, want ErrInvalidValidatorID", err)
+	}
+	if watchlist, _ := store.Get("key"); !reflect.DeepEqual(watchlist.Validators, []int64{1}) {
+		t.Errorf("Validators after a failed Watch() = %v, want [1] unchanged", watchlist.Validators)
+	}
+	if err := store.SetRules("key", []service.AlertRule{{Event: "price_drop"}}); !errors.Is(err, service.ErrInvalidRule) {
+		t.Errorf("SetRules(price_drop) error = %v, want ErrInvalidRule", err)
+	}
+	if err := store.SetChannels("key", []string{"email:me@example.com"}); !errors.Is(err, service.ErrInvalidChannel) {
+		t.Errorf("SetChannels(email) error = %v, want ErrInvalidChannel", err)
+	}
+	if err := store.AddAccount(""); !errors.Is(err, service.ErrUnknownAccount) {
+		t.Errorf("AddAccount(\"\") error = %v, want ErrUnknownAccount", err)
+	}
+}
+
+func TestLoadWatchlistStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "watchlists.json")
+	store, err := service.LoadWatchlistStore(path)
+	if err != nil {
+		t.Fatalf("LoadWatchlistStore() of a missing file error = %v", err)
+	}
+	store.AddAccount("secret-key")
+	store.Watch("secret-key", 42)
+
+	// Keys are only stored hashed
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read saved watchlists: %v", err)
+	}
+	if strings.Contains(string(data), "secret-key") {
+		t.Errorf("Saved watchlists contain the API key: %s", data)
+	}
+
+	loaded, err := service.LoadWatchlistStore(path)
+	if err != nil {
+		t.Fatalf("LoadWatchlistStore() error = %v", err)
+	}
+	if watchlist, err := loaded.Get("secret-key"); err != nil || !reflect.DeepEqual(watchlist.Validators, []int64{42}) {
+		t.Errorf("Get() after loading = %+v, %v, want validator 42", watchlist, err)
+	}
+}
+
+func TestWatchlistEndpoints(t *testing.T) {
+	ethService := service.NewEthereumServiceWithClients(&mockBeaconClient{}, &mockExecutionClient{})
+	h := handler.NewHandler(ethService)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	admin := router.Group("/admin", handler.APIKeyAuth("admin-key"))
+	admin.POST("/accounts", h.PostAccount)
+	admin.DELETE("/accounts/:id", h.DeleteAccount)
+	watchlist := router.Group("/watchlist", h.AccountAuth())
+	watchlist.GET("", h.GetWatchlist)
+	watchlist.DELETE("", h.DeleteWatchlist)
+	watchlist.PUT("/validators/:index", h.PutWatchedValidator)
+	watchlist.DELETE("/validators/:index", h.DeleteWatchedValidator)
+	watchlist.PUT("/rules", h.PutWatchlistRules)
+	watchlist.PUT("/channels", h.PutWatchlistChannels)
+
+	send := func(key, method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if key != "" {
+			req.Header.Set("X-API-Key", key)
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+	createAccount := func() v1.AccountResponse {
+		w := send("admin-key", http.MethodPost, "/admin/accounts", "")
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201 creating an account, got %d: %s", w.Code, w.Body.String())
+		}
+		var account v1.AccountResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &account); err != nil {
+			t.Fatalf("Failed to unmarshal account: %v", err)
+		}
+		return account
+	}
+	alice, bob := createAccount(), createAccount()
+
+	// Only the keys of accounts are accepted, the admin key included
+	for _, key := range []string{"", "unknown-key", "admin-key"} {
+		if w := send(key, http.MethodGet, "/watchlist", ""); w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status 401 for key %q, got %d", key, w.Code)
+		}
+	}
+	if w := send(alice.APIKey, http.MethodPost, "/admin/accounts", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 creating an account with an account key, got %d", w.Code)
+	}
+
+	send(alice.APIKey, http.MethodPut, "/watchlist/validators/42", "")
+	send(alice.APIKey, http.MethodPut, "/watchlist/validators/7", "")
+	send(alice.APIKey, http.MethodDelete, "/watchlist/validators/42", "")
+	send(alice.APIKey, http.MethodPut, "/watchlist/rules", `{"rules":[{"event":"slashed","validators":[7]}]}`)
+	w := send(alice.APIKey, http.MethodPut, "/watchlist/channels", `{"channels":["discord:https://discord.com/api/webhooks/1/alice"]}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var got v1.WatchlistResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("Failed to unmarshal watchlist: %v", err)
+	}
+	want := v1.WatchlistResponse{
+		Validators: []int64{7},
+		Rules:      []v1.AlertRuleResponse{{Event: service.AlertSlashed, Validators: []int64{7}}},
+		Channels:   []string{"discord:https://discord.com/api/webhooks/1/alice"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Watchlist of alice = %+v, want %+v", got, want)
+	}
+
+	// Accounts don't see each other's watchlists
+	w = send(bob.APIKey, http.MethodGet, "/watchlist", "")
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"validators":[]`) {
+		t.Errorf("Expected the empty watchlist of bob, got %d: %s", w.Code, w.Body.String())
+	}
+
+	invalid := []struct {
+		method, path, body string
+	}{
+		{http.MethodPut, "/watchlist/validators/-1", ""},
+		{http.MethodPut, "/watchlist/validators/abc", ""},
+		{http.MethodPut, "/watchlist/rules", `{"rules":[{"event":"offline"}]}`},
+		{http.MethodPut, "/watchlist/rules", `{"rules":"slashed"}`},
+		{http.MethodPut, "/watchlist/channels", `{"channels":["email:alice@example.com"]}`},
+	}
+	for _, tt := range invalid {
+		if w := send(bob.APIKey, tt.method, tt.path, tt.body); w.Code != http.StatusBadRequest {
+			t.Errorf("%s %s %s: expected status 400, got %d", tt.method, tt.path, tt.body, w.Code)
+		}
+	}
+
+	// An admin revokes an account by its ID, an account closes itself with its key
+	if w := send("admin-key", http.MethodDelete, "/admin/accounts/"+alice.ID, ""); w.Code != http.StatusNoContent {
+		t.Errorf("Expected status 204 deleting an account, got %d", w.Code)
+	}
+	if w := send("admin-key", http.MethodDelete, "/admin/accounts/"+alice.ID, ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 deleting a deleted account, got %d", w.Code)
+	}
+	if w := send(alice.APIKey, http.MethodGet, "/watchlist", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 for a revoked key, got %d", w.Code)
+	}
+	if w := send(bob.APIKey, http.MethodDelete, "/watchlist", ""); w.Code != http.StatusNoContent {
+		t.Errorf("Expected status 204 closing an account, got %d", w.Code)
+	}
+	if w := send(bob.APIKey, http.MethodGet, "/watchlist", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 for a closed account, got %d", w.Code)
+	}
+}
@@ -0,0 +1,178 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// corsSettings represents the CORS settings of CORS_CONFIG_FILE, unset fields keep their defaults
+type corsSettings struct {
+	AllowOrigins     []string `json:"allow_origins"`
+	AllowMethods     []string `json:"allow_methods"`
+	AllowHeaders     []string `json:"allow_headers"`
+	ExposeHeaders    []string `json:"expose_headers"`
+	AllowCredentials *bool    `json:"allow_credentials"`
+	MaxAge           string   `json:"max_age"`
+}
+
+// CORSConfigFromEnv builds the CORS configuration from CORS_CONFIG_FILE and the CORS_* environment variables,
+// which take precedence over the file. Allowed origins are exact origins, "*" for any origin, wildcard
+// subdomains such as https://*.example.com, or regular expressions prefixed with "regex:"
+func CORSConfigFromEnv() (cors.Config, error) {
+	settings := corsSettings{
+		AllowMethods:  []string{"GET", "POST", "DELETE", "OPTIONS", "HEAD"},
+		AllowHeaders:  []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Requested-With", "X-API-Key"},
+		ExposeHeaders: []string{"Content-Length"},
+		MaxAge:        "12h",
+	}
+	allowCredentials := true
+	settings.AllowCredentials = &allowCredentials
+
+	if path := os.Getenv("CORS_CONFIG_FILE"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return cors.Config{}, fmt.Errorf("failed to read CORS config file: %v", err)
+		}
+		if err := json.Unmarshal(data, &settings); err != nil {
+			return cors.Config{}, fmt.Errorf("failed to parse CORS config file: %v", err)
+		}
+	}
+
+	if origins := listFromEnv("CORS_ALLOWED_ORIGINS"); origins != nil {
+		settings.AllowOrigins = origins
+	}
+	if methods := listFromEnv("CORS_ALLOWED_METHODS"); methods != nil {
+		settings.AllowMethods = methods
+	}
+	if headers := listFromEnv("CORS_ALLOWED_HEADERS"); headers != nil {
+		settings.AllowHeaders = headers
+	}
+	if headers := listFromEnv("CORS_EXPOSED_HEADERS"); headers != nil {
+		settings.ExposeHeaders = headers
+	}
+	if credentialsEnv := os.Getenv("CORS_ALLOW_CREDENTIALS"); credentialsEnv != "" {
+		credentials, err := strconv.ParseBool(credentialsEnv)
+		if err != nil {
+			return cors.Config{}, fmt.Errorf("invalid CORS_ALLOW_CREDENTIALS: %s", credentialsEnv)
+		}
+		settings.AllowCredentials = &credentials
+	}
+	if maxAge := os.Getenv("CORS_MAX_AGE"); maxAge != "" {
+		settings.MaxAge = maxAge
+	}
+
+	// Deployments configured before the full origin list was configurable only set CORS_ORIGIN
+	if len(settings.AllowOrigins) == 0 {
+		corsOrigin := os.Getenv("CORS_ORIGIN")
+		if corsOrigin == "" {
+			corsOrigin = "https://sf.dogukangun.de"
+		}
+		settings.AllowOrigins = []string{corsOrigin, "http://localhost:3003", "https://sf-api.dogukangun.de", "https://sf.dogukangun.de"}
+	}
+
+	maxAge, err := time.ParseDuration(settings.MaxAge)
+	if err != nil || maxAge < 0 {
+		return cors.Config{}, fmt.Errorf("invalid CORS max age: %s", settings.MaxAge)
+	}
+
+	config := cors.Config{
+		AllowMethods:     settings.AllowMethods,
+		AllowHeaders:     settings.AllowHeaders,
+		ExposeHeaders:    settings.ExposeHeaders,
+		AllowCredentials: settings.AllowCredentials != nil && *settings.AllowCredentials,
+		MaxAge:           maxAge,
+	}
+
+	matchers := make([]*regexp.Regexp, 0, len(settings.AllowOrigins))
+	for _, origin := range settings.AllowOrigins {
+		if origin == "*" {
+			config.AllowAllOrigins = true
+			continue
+		}
+		matcher, err := originMatcher(origin)
+		if err != nil {
+			return cors.Config{}, err
+		}
+		matchers = append(matchers, matcher)
+	}
+	if !config.AllowAllOrigins {
+		config.AllowOriginFunc = func(origin string) bool {
+			for _, matcher := range matchers {
+				if matcher.MatchString(origin) {
+					return true
+				}
+			}
+			return false
+		}
+	}
+
+	if err := config.Validate(); err != nil {
+		return cors.Config{}, fmt.Errorf("invalid CORS configuration: %v", err)
+	}
+	return config, nil
+}
+
+// CORSMiddleware applies a CORS configuration that can be replaced while the server is running
+type CORSMiddleware struct {
+	handler atomic.Pointer[gin.HandlerFunc]
+}
+
+// NewCORSMiddleware creates a CORSMiddleware applying config
+func NewCORSMiddleware(config cors.Config) *CORSMiddleware {
+	middleware := &CORSMiddleware{}
+	middleware.Update(config)
+	return middleware
+}
+
+// Update replaces the configuration applied to requests from now on
+func (m *CORSMiddleware) Update(config cors.Config) {
+	handler := cors.New(config)
+	m.handler.Store(&handler)
+}
+
+// Handle applies the current configuration to a request
+func (m *CORSMiddleware) Handle(c *gin.Context) {
+	(*m.handler.Load())(c)
+}
+
+// originMatcher compiles an allowed origin into a case-insensitive expression matching the whole origin,
+// a "*" matches one or more subdomain labels
+func originMatcher(origin string) (*regexp.Regexp, error) {
+	if pattern, ok := strings.CutPrefix(origin, "regex:"); ok {
+		matcher, err := regexp.Compile("(?i)^(?:" + pattern + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid CORS origin pattern %q: %v", pattern, err)
+		}
+		return matcher, nil
+	}
+
+	if !strings.HasPrefix(origin, "http://") && !strings.HasPrefix(origin, "https://") {
+		return nil, fmt.Errorf("invalid CORS origin %q: must start with http:// or https://", origin)
+	}
+	pattern := strings.ReplaceAll(regexp.QuoteMeta(strings.TrimSuffix(origin, "/")), `\*`, `[a-z0-9-]+(?:\.[a-z0-9-]+)*`)
+	return regexp.MustCompile("(?i)^" + pattern + "$"), nil
+}
+
+// listFromEnv splits a comma separated environment variable, nil if it is unset
+func listFromEnv(key string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"errors"
+	"ethereum-validator-api/handler"
+	"github.com/gin-contrib/pprof"
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+	"log"
+	"net/http"
+	"os"
+)
+
+// RegisterDebugEndpoints registers the pprof profiling endpoints when PPROF_ENABLED=true and the Swagger UI unless
+// SWAGGER_ENABLED=false. pprof always requires ADMIN_API_KEY or the DEBUG_USER and DEBUG_PASSWORD basic auth
+// credentials, the Swagger UI only with SWAGGER_PROTECTED=true
+func RegisterDebugEndpoints(router *gin.Engine) error {
+	apiKey, user, password := os.Getenv("ADMIN_API_KEY"), os.Getenv("DEBUG_USER"), os.Getenv("DEBUG_PASSWORD")
+	hasCredentials := apiKey != "" || (user != "" && password != "")
+	auth := handler.DebugAuth(apiKey, user, password)
+
+	if os.Getenv("PPROF_ENABLED") == "true" {
+		if !hasCredentials {
+			return errors.New("PPROF_ENABLED requires ADMIN_API_KEY or DEBUG_USER and DEBUG_PASSWORD")
+		}
+		pprof.Register(router.Group("", auth))
+		log.Println("pprof endpoints enabled at /debug/pprof/")
+	}
+
+	if os.Getenv("SWAGGER_ENABLED") == "false" {
+		return nil
+	}
+	docs := router.Group("")
+	if os.Getenv("SWAGGER_PROTECTED") == "true" {
+		if !hasCredentials {
+			return errors.New("SWAGGER_PROTECTED requires ADMIN_API_KEY or DEBUG_USER and DEBUG_PASSWORD")
+		}
+		docs.Use(auth)
+	}
+
+	// Redirect /docs to /swagger/index.html for better UX
+	docs.GET("/docs", func(c *gin.Context) {
+		c.Redirect(http.StatusMovedPermanently, "/swagger/index.html")
+	})
+	docs.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	log.Println("Swagger UI available at /swagger/index.html")
+	return nil
+}
@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"ethereum-validator-api/handler"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"os"
+	"strconv"
+)
+
+// defaultMaxBodyBytes bounds request bodies, the largest expected body is an admin builder signature
+const defaultMaxBodyBytes = 1 << 20
+
+// ApplyHardening installs the middlewares protecting the internet-facing API: security headers, with
+// Strict-Transport-Security on all requests if HSTS_ENABLED is true, a request body limit of MAX_BODY_BYTES
+// (default 1 MiB) and the rejection of bodies not sent as JSON. Requests using a method a path doesn't serve
+// are answered with 405 and the allowed methods
+func ApplyHardening(router *gin.Engine) error {
+	maxBodyBytes := int64(defaultMaxBodyBytes)
+	if value := os.Getenv("MAX_BODY_BYTES"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || parsed <= 0 {
+			return fmt.Errorf("invalid MAX_BODY_BYTES %q", value)
+		}
+		maxBodyBytes = parsed
+	}
+
+	router.HandleMethodNotAllowed = true
+	router.Use(
+		handler.SecurityHeaders(os.Getenv("HSTS_ENABLED") == "true"),
+		handler.LimitBody(maxBodyBytes),
+		handler.RequireJSON(),
+	)
+	return nil
+}
@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"os"
+	"strings"
+)
+
+// ConfigureTrustedProxies sets which proxies the router takes the client IP from. Only requests arriving from an
+// address in TRUSTED_PROXIES (comma separated IPs or CIDRs) have their X-Forwarded-For or X-Real-IP header honoured,
+// all other requests use the peer address. TRUSTED_PLATFORM takes the client IP from a header set by the
+// hosting platform instead: cloudflare, google or the name of a custom header
+func ConfigureTrustedProxies(router *gin.Engine) error {
+	var proxies []string
+	if trusted := os.Getenv("TRUSTED_PROXIES"); trusted != "" {
+		for _, proxy := range strings.Split(trusted, ",") {
+			if proxy = strings.TrimSpace(proxy); proxy != "" {
+				proxies = append(proxies, proxy)
+			}
+		}
+	}
+	// Gin trusts every proxy by default, which lets any client spoof its address
+	if err := router.SetTrustedProxies(proxies); err != nil {
+		return fmt.Errorf("invalid TRUSTED_PROXIES: %v", err)
+	}
+
+	// The first untrusted address from the right of X-Forwarded-For is the client, X-Real-IP is the fallback
+	router.ForwardedByClientIP = true
+	router.RemoteIPHeaders = []string{"X-Forwarded-For", "X-Real-IP"}
+
+	switch platform := os.Getenv("TRUSTED_PLATFORM"); strings.ToLower(platform) {
+	case "":
+	case "cloudflare":
+		router.TrustedPlatform = gin.PlatformCloudflare
+	case "google":
+		router.TrustedPlatform = gin.PlatformGoogleAppEngine
+	default:
+		router.TrustedPlatform = platform
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"ethereum-validator-api/service"
+	"github.com/joho/godotenv"
+	"io/fs"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// Reloader re-reads the .env file and applies the settings that can change without a restart: the upstream rate
+// limit, the upstream endpoints, the CORS configuration and the MEV builder signatures file. Other settings keep the
+// values they were started with
+type Reloader struct {
+	mu      sync.Mutex
+	envFile string
+	cors    *CORSMiddleware
+	service *service.EthereumService
+
+	// Settings last applied, changing upstreams drops their connections so they are only replaced when they changed
+	rateLimit upstreamRateLimit
+	upstreams service.UpstreamConfig
+}
+
+// NewReloader creates a Reloader reading envFile
+func NewReloader(envFile string) *Reloader {
+	return &Reloader{envFile: envFile}
+}
+
+// SetCORS sets the middleware whose configuration is reloaded
+func (r *Reloader) SetCORS(cors *CORSMiddleware) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cors = cors
+}
+
+// SetService sets the service whose upstreams and builder signatures are reloaded, taking the settings of the
+// environment as applied
+func (r *Reloader) SetService(ethService *service.EthereumService) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.service = ethService
+	r.rateLimit, _ = upstreamRateLimitFromEnv()
+	r.upstreams, _ = upstreamConfigFromEnv()
+}
+
+// Reload re-reads the .env file, overriding variables it sets, and applies the reloadable settings. It returns the
+// settings that were applied: rate_limit and upstreams when they changed, cors and builders. Nothing is applied when
+// a setting is invalid
+func (r *Reloader) Reload(ctx context.Context) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// A missing file leaves the environment as it is, as on startup
+	if err := godotenv.Overload(r.envFile); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	// Read every setting before applying any
+	rateLimit, err := upstreamRateLimitFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	upstreams, err := upstreamConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	corsConfig, err := CORSConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	var builders *service.BuilderRegistry
+	if buildersFile := os.Getenv("MEV_BUILDERS_FILE"); buildersFile != "" {
+		if builders, err = service.LoadBuilderRegistry(buildersFile); err != nil {
+			return nil, err
+		}
+	}
+
+	applied := make([]string, 0, 4)
+	// Upstreams go first as dialing them is the only step that can still fail
+	if r.service != nil && !upstreams.Equal(r.upstreams) {
+		if err := r.service.SetUpstreams(ctx, upstreams); err != nil {
+			return nil, err
+		}
+		r.upstreams = upstreams
+		applied = append(applied, "upstreams")
+	}
+	if !rateLimit.equal(r.rateLimit) {
+		if err := rateLimit.apply(); err != nil {
+			return nil, err
+		}
+		r.rateLimit = rateLimit
+		applied = append(applied, "rate_limit")
+	}
+	if r.cors != nil {
+		r.cors.Update(corsConfig)
+		applied = append(applied, "cors")
+	}
+	// Merge rather than replace so signatures added through the admin API survive a reload
+	if r.service != nil && builders != nil {
+		for _, signature := range builders.List() {
+			r.service.Builders().Put(signature)
+		}
+		applied = append(applied, "builders")
+	}
+	return applied, nil
+}
+
+// WatchSignals reloads the configuration on every SIGHUP until the context is cancelled
+func (r *Reloader) WatchSignals(ctx context.Context) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	defer signal.Stop(signals)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-signals:
+			applied, err := r.Reload(ctx)
+			if err != nil {
+				log.Printf("Reload: keeping the current configuration: %v", err)
+				continue
+			}
+			log.Printf("Reload: applied %v", applied)
+			r.audit(applied)
+		}
+	}
+}
+
+// audit records a reload triggered by a signal in the audit log of the service, like reloads through the admin API
+func (r *Reloader) audit(applied []string) {
+	r.mu.Lock()
+	ethService := r.service
+	r.mu.Unlock()
+	if ethService == nil {
+		return
+	}
+	if err := ethService.Audit().Record("signal:SIGHUP", service.AuditConfigReload, "", nil, map[string][]string{"applied": applied}); err != nil {
+		log.Printf("Reload: %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"crypto/tls"
+	"fmt"
+	"golang.org/x/crypto/acme/autocert"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Serve runs the API until the server fails. Without TLS settings it listens on plain HTTP. HTTPS is served from
+// TLS_CERT_FILE and TLS_KEY_FILE, or with certificates obtained from Let's Encrypt for TLS_AUTOCERT_DOMAINS,
+// for deployments without a reverse proxy terminating TLS
+func Serve(handler http.Handler) error {
+	certFile, keyFile := os.Getenv("TLS_CERT_FILE"), os.Getenv("TLS_KEY_FILE")
+	domains := listFromEnv("TLS_AUTOCERT_DOMAINS")
+	if certFile == "" && keyFile == "" && len(domains) == 0 {
+		addr := envOrDefault("HTTP_ADDR", ":3004")
+		log.Printf("Server starting at http://localhost%s", addr)
+		return http.ListenAndServe(addr, handler)
+	}
+	if (certFile == "") != (keyFile == "") {
+		return fmt.Errorf("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+	if certFile != "" && len(domains) > 0 {
+		return fmt.Errorf("TLS_CERT_FILE and TLS_AUTOCERT_DOMAINS are mutually exclusive")
+	}
+
+	httpsAddr := envOrDefault("HTTPS_ADDR", ":443")
+	server := &http.Server{
+		Addr:      httpsAddr,
+		Handler:   handler,
+		TLSConfig: &tls.Config{MinVersion: tls.VersionTLS12},
+	}
+
+	// Plain HTTP is only needed for redirects and, with autocert, the ACME HTTP-01 challenge
+	var httpHandler http.Handler
+	if os.Getenv("HTTPS_REDIRECT") == "true" {
+		_, httpsPort, err := net.SplitHostPort(httpsAddr)
+		if err != nil {
+			return fmt.Errorf("invalid HTTPS_ADDR: %v", err)
+		}
+		httpHandler = HTTPSRedirectHandler(httpsPort)
+	}
+
+	if len(domains) > 0 {
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(domains...),
+			Cache:      autocert.DirCache(envOrDefault("TLS_AUTOCERT_CACHE_DIR", "certs")),
+			Email:      os.Getenv("TLS_AUTOCERT_EMAIL"),
+		}
+		server.TLSConfig = manager.TLSConfig()
+		server.TLSConfig.MinVersion = tls.VersionTLS12
+		// A nil fallback answers requests other than challenges with a redirect to HTTPS
+		httpHandler = manager.HTTPHandler(httpHandler)
+	}
+
+	if httpHandler != nil {
+		httpAddr := envOrDefault("HTTP_ADDR", ":80")
+		go func() {
+			log.Printf("HTTP listener starting at %s", httpAddr)
+			if err := http.ListenAndServe(httpAddr, httpHandler); err != nil {
+				log.Printf("HTTP listener stopped: %v", err)
+			}
+		}()
+	}
+
+	log.Printf("Server starting at https://localhost%s", httpsAddr)
+	return server.ListenAndServeTLS(certFile, keyFile)
+}
+
+// HTTPSRedirectHandler redirects every request to the same URL on HTTPS, httpsPort is omitted from the URL when it is 443
+func HTTPSRedirectHandler(httpsPort string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		host = strings.Trim(host, "[]")
+		if httpsPort != "" && httpsPort != "443" {
+			host = net.JoinHostPort(host, httpsPort)
+		} else if strings.Contains(host, ":") {
+			host = "[" + host + "]" // IPv6 literal
+		}
+
+		target := "https://" + host + r.URL.RequestURI()
+		http.Redirect(w, r, target, http.StatusMovedPermanently)
+	})
+}
+
+// envOrDefault returns the environment variable key, or fallback if it is unset
+func envOrDefault(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
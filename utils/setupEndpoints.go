@@ -1,25 +1,762 @@
 package utils
 
 import (
+	"cmp"
+	"context"
 	"ethereum-validator-api/handler"
 	"ethereum-validator-api/service"
+	"fmt"
 	"github.com/gin-gonic/gin"
+	"io"
+	"log"
+	"maps"
+	"math"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
-// SetupEndpoints configures the API endpoints for the Ethereum validator service
-func SetupEndpoints(router *gin.Engine) error {
-	rpcURL := os.Getenv("ETH_RPC")
-	ethService, err := service.NewEthereumService(rpcURL)
+// SetupEndpoints configures the API endpoints for the Ethereum validator service, the reloader, if any, reloads the
+// configuration of the service
+func SetupEndpoints(router *gin.Engine, reloader *Reloader) error {
+	// Keep recent log lines in memory so they can be included in support bundles, provider API keys are redacted
+	// from every line before it is written
+	logs := service.NewLogBuffer(500)
+	log.SetOutput(service.NewRedactingWriter(io.MultiWriter(os.Stderr, logs)))
+	if err := configureLogging(); err != nil {
+		return err
+	}
+
+	// Optionally raise the shared upstream rate limit for paid provider plans and limit single providers
+	rateLimit, err := upstreamRateLimitFromEnv()
+	if err != nil {
+		return err
+	}
+	if err := rateLimit.apply(); err != nil {
+		return err
+	}
+
+	upstreams, err := upstreamConfigFromEnv()
+	if err != nil {
+		return err
+	}
+	// Upstreams are connected once the transport and provider authentication are configured
+	ethService := service.NewDisconnectedEthereumService()
+
+	// Tune the connection pool shared by all upstream clients
+	transportConfig, err := transportConfigFromEnv()
 	if err != nil {
 		return err
 	}
+	ethService.ConfigureTransport(transportConfig)
+
+	// Authenticate upstream providers with custom headers or client certificates, before websocket endpoints are dialed
+	for _, provider := range []string{"ETH_RPC", "BEACON_RPC", "EXECUTION_RPC", "SECONDARY_RPC"} {
+		providerURL := os.Getenv(provider)
+		auth, ok, err := providerAuthFromEnv(provider)
+		if err != nil {
+			return err
+		}
+		if !ok || providerURL == "" {
+			continue
+		}
+		if err := ethService.SetProviderAuth(providerURL, auth); err != nil {
+			return fmt.Errorf("invalid %s authentication: %w", provider, err)
+		}
+	}
+
+	// Optionally inject upstream faults to exercise retries and fallbacks, never enable this in production
+	if os.Getenv("FAULT_INJECTION_ENABLED") == "true" {
+		faults, err := faultConfigFromEnv()
+		if err != nil {
+			return err
+		}
+		if err := ethService.SetFaultInjection(faults); err != nil {
+			return err
+		}
+	}
+
+	// Optionally run next to a full node, using its client specific endpoints and following its new heads
+	ethService.EnableLocalNode(os.Getenv("LOCAL_NODE") == "true")
+
+	// Optionally use dedicated Beacon API and execution endpoints, and hedge slow upstream requests with a secondary
+	// provider. Unreachable or missing endpoints don't keep the server from starting, data endpoints answer 503 until
+	// the reconnect task reaches them
+	pingCtx, cancelPing := context.WithTimeout(context.Background(), upstreamPingTimeout)
+	err = ethService.ConnectUpstreams(pingCtx, upstreams)
+	cancelPing()
+	if err != nil {
+		log.Printf("Upstreams: starting degraded, retrying in the background: %v", err)
+	}
+
+	// Optionally check upstream responses for consistency, and cross-check blocks with both providers when hedging
+	ethService.EnableVerification(os.Getenv("VERIFY_RESPONSES") == "true")
+
+	// Optionally load MEV builder signatures from a file instead of the built-in list
+	if buildersFile := os.Getenv("MEV_BUILDERS_FILE"); buildersFile != "" {
+		builders, err := service.LoadBuilderRegistry(buildersFile)
+		if err != nil {
+			return err
+		}
+		ethService.SetBuilders(builders)
+	}
+
+	// Optionally refresh MEV builder signatures periodically from a URL
+	if buildersURL := os.Getenv("MEV_BUILDERS_URL"); buildersURL != "" {
+		interval := time.Hour
+		if intervalEnv := os.Getenv("MEV_BUILDERS_REFRESH_INTERVAL"); intervalEnv != "" {
+			interval, err = time.ParseDuration(intervalEnv)
+			if err != nil {
+				return err
+			}
+		}
+		go ethService.Builders().RunRefresher(context.Background(), buildersURL, interval)
+	}
+
+	// Optionally extend the fee recipient labels and resolve unknown recipients via ENS
+	if labelsFile := os.Getenv("FEE_RECIPIENT_LABELS_FILE"); labelsFile != "" {
+		feeRecipients, err := service.LoadFeeRecipientRegistry(labelsFile)
+		if err != nil {
+			return err
+		}
+		ethService.SetFeeRecipients(feeRecipients)
+	}
+	ethService.EnableENSLookup(os.Getenv("ENS_LOOKUP_ENABLED") == "true")
+
+	// Optionally tag validators with their staking pool and node operator from published key lists
+	if operatorKeysFile := os.Getenv("OPERATOR_KEYS_FILE"); operatorKeysFile != "" {
+		operators, err := service.LoadOperatorRegistry(operatorKeysFile)
+		if err != nil {
+			return err
+		}
+		ethService.SetOperators(operators)
+	}
+
+	// Optionally persist the slot to execution block number mapping across restarts
+	blockNumbersFile := os.Getenv("BLOCK_NUMBERS_FILE")
+	if blockNumbersFile != "" {
+		blockNumbers, err := service.LoadBlockNumberIndex(blockNumbersFile)
+		if err != nil {
+			return err
+		}
+		ethService.SetBlockNumbers(blockNumbers)
+	}
+
+	// Optionally persist the request counts per client, endpoint and day across restarts
+	usageFile := os.Getenv("USAGE_FILE")
+	if usageFile != "" {
+		usage, err := service.LoadUsageTracker(usageFile)
+		if err != nil {
+			return err
+		}
+		ethService.SetUsage(usage)
+	}
+
+	// Optionally persist the watchlists of the accounts across restarts
+	if watchlistsFile := os.Getenv("WATCHLISTS_FILE"); watchlistsFile != "" {
+		watchlists, err := service.LoadWatchlistStore(watchlistsFile)
+		if err != nil {
+			return err
+		}
+		ethService.SetWatchlists(watchlists)
+	}
+
+	// Optionally append the admin actions to a file so they survive restarts
+	if auditFile := os.Getenv("AUDIT_LOG_FILE"); auditFile != "" {
+		audit, err := service.LoadAuditLog(auditFile)
+		if err != nil {
+			return err
+		}
+		ethService.SetAudit(audit)
+	}
+
+	// Optionally capture the last upstream exchanges for debugging, capturing can also be toggled through the admin API
+	if sizeEnv := os.Getenv("UPSTREAM_CAPTURE_SIZE"); sizeEnv != "" {
+		size, err := strconv.Atoi(sizeEnv)
+		if err != nil || size <= 0 {
+			return fmt.Errorf("invalid UPSTREAM_CAPTURE_SIZE: %s", sizeEnv)
+		}
+		ethService.SetUpstreamCapture(service.NewUpstreamCapture(size))
+	}
+	ethService.UpstreamCapture().SetEnabled(os.Getenv("UPSTREAM_CAPTURE_ENABLED") == "true")
+
+	// Optionally change the limits of the response cache, the least recently used entries are evicted beyond them
+	maxCacheEntries, err := positiveIntFromEnv("CACHE_MAX_ENTRIES", service.DefaultCacheMaxEntries)
+	if err != nil {
+		return err
+	}
+	maxCacheBytes, err := positiveIntFromEnv("CACHE_MAX_BYTES", service.DefaultCacheMaxBytes)
+	if err != nil {
+		return err
+	}
+	if err := ethService.SetCacheLimits(int(maxCacheEntries), maxCacheBytes); err != nil {
+		return err
+	}
+
+	// Optionally override the MEV-Boost relays queried for bid data
+	if relays := os.Getenv("MEV_RELAYS"); relays != "" {
+		if err := ethService.SetRelays(strings.Split(relays, ",")); err != nil {
+			return err
+		}
+	}
+
+	// Optionally follow the chain head and index new blocks in the background, keeping a window of slots behind it
+	indexWindow, err := positiveIntFromEnv("INDEX_WINDOW_SLOTS", service.DefaultIndexWindow)
+	if err != nil {
+		return err
+	}
+	ethService.Index().SetWindow(indexWindow)
+	if os.Getenv("INDEXER_ENABLED") == "true" {
+		go ethService.RunIndexer(context.Background())
+	}
+
+	// Recurring background tasks, their runs are reported at /admin/jobs
+	scheduler := service.NewScheduler()
+
+	// Remove cached responses that expired too long ago to be served stale
+	scheduler.Register(ethService.CacheSweepTask())
+
+	// Retry upstream providers that weren't reached at startup
+	reconnectSchedule, err := scheduleFromEnv("UPSTREAM_RECONNECT_SCHEDULE", "@every 10s")
+	if err != nil {
+		return err
+	}
+	scheduler.Register(ethService.ReconnectTask(reconnectSchedule))
+
+	// Keep the current and next sync committees in memory unless disabled
+	if os.Getenv("SYNC_COMMITTEE_PREFETCH_ENABLED") != "false" {
+		scheduler.Register(ethService.SyncCommitteePrefetchTask())
+	}
+
+	// Optionally compute network statistics in the background, they require fetching the whole active set
+	if os.Getenv("NETWORK_STATS_ENABLED") == "true" {
+		schedule, err := scheduleFromEnv("NETWORK_STATS_SCHEDULE", "@every 6m24s")
+		if err != nil {
+			return err
+		}
+		scheduler.Register(ethService.NetworkStatsTask(schedule))
+	}
+
+	// Summarize the blocks indexed during the last day, only useful while the indexer runs
+	if os.Getenv("INDEXER_ENABLED") == "true" {
+		schedule, err := scheduleFromEnv("DAILY_REPORT_SCHEDULE", "@daily")
+		if err != nil {
+			return err
+		}
+		scheduler.Register(ethService.DailyReportTask(schedule))
+	}
+
+	// Optionally keep the operator key lists up to date from a published URL
+	if operatorKeysURL := os.Getenv("OPERATOR_KEYS_URL"); operatorKeysURL != "" {
+		schedule, err := scheduleFromEnv("OPERATOR_KEYS_SCHEDULE", "@daily")
+		if err != nil {
+			return err
+		}
+		scheduler.Register(ethService.OperatorKeysTask(operatorKeysURL, schedule))
+	}
+
+	// Optionally value rewards in fiat currencies, keeping their price series up to date in the background
+	if currencies := os.Getenv("PRICE_CURRENCIES"); currencies != "" {
+		priceURL := os.Getenv("PRICE_API_URL")
+		if priceURL == "" {
+			priceURL = service.DefaultCoinGeckoURL
+		}
+		ethService.EnableCoinGeckoPrices(priceURL, os.Getenv("PRICE_API_KEY"), strings.Split(currencies, ","))
+
+		schedule, err := scheduleFromEnv("PRICE_SCHEDULE", "@hourly")
+		if err != nil {
+			return err
+		}
+		history, err := durationFromEnv("PRICE_HISTORY", service.DefaultPriceHistory)
+		if err != nil {
+			return err
+		}
+		scheduler.Register(ethService.PriceTask(schedule, history))
+	}
+
+	// Save the mappings learned since the last save when the mapping is persisted
+	if blockNumbersFile != "" {
+		schedule, err := scheduleFromEnv("BLOCK_NUMBERS_SAVE_SCHEDULE", "@every 5m")
+		if err != nil {
+			return err
+		}
+		scheduler.Register(ethService.BlockNumbersSaveTask(schedule))
+	}
+
+	// Drop usage past its retention, and save the usage counted since the last save when it is persisted
+	usageSchedule, err := scheduleFromEnv("USAGE_SAVE_SCHEDULE", "@every 5m")
+	if err != nil {
+		return err
+	}
+	usageRetention, err := durationFromEnv("USAGE_RETENTION", service.DefaultUsageRetention)
+	if err != nil {
+		return err
+	}
+	scheduler.Register(ethService.UsageSaveTask(usageSchedule, usageRetention))
+
+	// Notify the accounts about missed duties and slashings of the validators they watch, once per epoch
+	scheduler.Register(ethService.AlertTask())
+
+	// Optionally verify block roots against sync committee signatures, following the chain from a trusted checkpoint
+	if checkpoint := os.Getenv("LIGHT_CLIENT_CHECKPOINT"); checkpoint != "" {
+		if err := ethService.EnableLightClient(checkpoint); err != nil {
+			return err
+		}
+		schedule, err := scheduleFromEnv("LIGHT_CLIENT_SCHEDULE", "@every 1m")
+		if err != nil {
+			return err
+		}
+		scheduler.Register(ethService.LightClientTask(schedule))
+	}
+	go scheduler.Run(context.Background())
+
+	// Optionally publish a signed health summary to an external uptime monitor
+	if heartbeatURL := os.Getenv("HEARTBEAT_URL"); heartbeatURL != "" {
+		interval := time.Minute
+		if intervalEnv := os.Getenv("HEARTBEAT_INTERVAL"); intervalEnv != "" {
+			interval, err = time.ParseDuration(intervalEnv)
+			if err != nil {
+				return err
+			}
+		}
+
+		publisher, err := service.NewHeartbeatPublisher(ethService, heartbeatURL, os.Getenv("HEARTBEAT_SECRET"), interval)
+		if err != nil {
+			return err
+		}
+		go publisher.Run(context.Background())
+	}
 
 	h := handler.NewHandler(ethService)
+	h.SetLogBuffer(logs)
+	h.SetScheduler(scheduler)
+	if reloader != nil {
+		reloader.SetService(ethService)
+		h.SetReloader(reloader.Reload)
+	}
+
+	// Bound the time a request may spend on upstream calls, endpoints covering many epochs get a longer budget
+	requestBudget, err := durationFromEnv("REQUEST_BUDGET", 8*time.Second)
+	if err != nil {
+		return err
+	}
+	longRequestBudget, err := durationFromEnv("REQUEST_BUDGET_LONG", 2*time.Minute)
+	if err != nil {
+		return err
+	}
+	budget, longBudget := handler.RequestBudget(requestBudget), handler.RequestBudget(longRequestBudget)
+
+	// Endpoints covering many epochs only get the upstream rate budget single lookups leave over
+	batch := handler.BatchPriority()
 
-	// Register API endpoints
-	router.GET("/blockreward/:slot", h.GetBlockReward)
-	router.GET("/syncduties/:slot", h.GetSyncDuties)
+	// Record where the data came from for requests asking for it with meta=true
+	router.Use(handler.Provenance())
+
+	// Count the requests per client, endpoint and day for GET /admin/usage
+	router.Use(h.TrackUsage())
+
+	// Report the upstream state and the detected beacon node client, also while the providers are unreachable
+	router.GET("/health/upstreams", h.GetUpstreamsHealth)
+
+	// Register API endpoints, answering 503 until the upstream providers were reached
+	api := router.Group("/", h.RequireUpstreams())
+	api.GET("/blockreward/:slot", budget, h.GetBlockReward)
+	api.GET("/blockreward/:slot/bids", budget, h.GetBlockRewardBids)
+	api.GET("/blockreward/by-block/:number_or_hash", budget, h.GetBlockRewardByBlock)
+	api.GET("/syncduties/next", budget, h.GetNextSyncCommittee)
+	api.GET("/syncduties/:slot", budget, h.GetSyncDuties)
+	api.GET("/block/:slot", budget, h.GetBlock)
+	api.GET("/block/:slot/gas", budget, h.GetBlockGas)
+	api.GET("/block/:slot/toptxs", budget, h.GetTopTransactions)
+	api.GET("/committees/:slot", budget, h.GetCommittees)
+	api.GET("/convert/block/:number", budget, h.ConvertBlockNumber)
+	api.GET("/convert/slot/:slot/block", budget, h.ConvertSlot)
+	api.GET("/graffiti/search", budget, h.SearchGraffiti)
+	api.GET("/stats/mev", budget, h.GetMEVStats)
+	api.GET("/stats/rewards", budget, h.GetRewardStats)
+	api.GET("/stats/basefee", budget, h.GetBaseFeeStats)
+	api.GET("/stats/network", budget, h.GetNetworkStats)
+	api.GET("/stats/apr", budget, h.GetNetworkAPR)
+	api.GET("/queue", longBudget, batch, h.GetValidatorQueue)
+	api.GET("/chain/head", budget, h.GetChainHead)
+	api.GET("/chain/spec", budget, h.GetChainSpec)
+	api.GET("/chain/forks", budget, h.GetForkSchedule)
+	api.GET("/node/syncing", budget, h.GetNodeSyncing)
+	api.GET("/node/version", budget, h.GetNodeVersion)
+	api.GET("/node/peers", budget, h.GetNodePeers)
+	api.GET("/validator/:id", budget, h.GetValidator)
+	api.GET("/validator/:id/attestations", longBudget, batch, h.GetValidatorAttestations)
+	api.GET("/validator/:id/effectiveness", longBudget, batch, h.GetValidatorEffectiveness)
+	api.GET("/validator/:id/apr", budget, h.GetValidatorAPR)
+	api.GET("/validator/:id/forecast", budget, h.GetValidatorForecast)
+	api.GET("/validator/:id/syncprobability", budget, h.GetValidatorSyncProbability)
+	api.GET("/validator/:id/duties.ics", longBudget, batch, h.GetValidatorDutiesCalendar)
+	api.GET("/validator/:id/proposals", longBudget, batch, h.GetValidatorProposals)
+	api.GET("/validator/:id/feerecipient/check", longBudget, batch, h.CheckFeeRecipient)
+	api.POST("/validators/summary", longBudget, batch, h.PostValidatorsSummary)
+	api.GET("/validator/:id/blockreward/latest", budget, h.GetValidatorLatestBlockReward)
+	api.GET("/validator/:id/blockreward/:slot", budget, h.GetValidatorBlockReward)
+	api.POST("/jobs/blockrewards", h.PostBlockRewardJob)
+	api.GET("/jobs/:id", h.GetJob)
+	api.GET("/grafana", h.GrafanaTest)
+	api.POST("/grafana/search", budget, h.GrafanaSearch)
+	api.POST("/grafana/query", longBudget, h.GrafanaQuery)
+
+	// Watchlists are served from the store without upstream calls, under the API keys of the accounts admins create
+	watchlist := router.Group("/watchlist", h.AccountAuth())
+	watchlist.GET("", h.GetWatchlist)
+	watchlist.DELETE("", h.DeleteWatchlist)
+	watchlist.PUT("/validators/:index", h.PutWatchedValidator)
+	watchlist.DELETE("/validators/:index", h.DeleteWatchedValidator)
+	watchlist.PUT("/rules", h.PutWatchlistRules)
+	watchlist.PUT("/channels", h.PutWatchlistChannels)
+
+	// Register admin endpoints only when an admin API key or an OIDC provider is configured, JWTs of the provider are
+	// accepted besides the key
+	verifier, err := jwtVerifierFromEnv()
+	if err != nil {
+		return err
+	}
+	if adminKey := os.Getenv("ADMIN_API_KEY"); adminKey != "" || verifier != nil {
+		admin := router.Group("/admin", handler.Authenticate(adminKey, verifier))
+		admin.GET("/builders", h.ListBuilders)
+		admin.POST("/builders", h.PutBuilder)
+		admin.DELETE("/builders/:name", h.DeleteBuilder)
+		admin.GET("/support-bundle", h.GetSupportBundle)
+		admin.GET("/cache/stats", h.GetCacheStats)
+		admin.GET("/upstreams", h.GetUpstreamBudgets)
+		admin.DELETE("/cache", h.DeleteCache)
+		admin.GET("/jobs", h.ListScheduledTasks)
+		admin.POST("/reload", h.ReloadConfig)
+		admin.GET("/usage", h.GetUsage)
+		admin.GET("/audit", h.GetAuditLog)
+		admin.POST("/accounts", h.PostAccount)
+		admin.DELETE("/accounts/:id", h.DeleteAccount)
+		admin.GET("/debug/upstream", h.GetUpstreamCapture)
+		admin.PUT("/debug/upstream", h.PutUpstreamCapture)
+	}
 
 	return nil
 }
+
+// jwtVerifierFromEnv creates the verifier of the JWTs of the OIDC provider configured with OIDC_ISSUER,
+// OIDC_AUDIENCE and OIDC_JWKS_URL, nil when none is configured
+func jwtVerifierFromEnv() (*service.JWTVerifier, error) {
+	config := service.OIDCConfig{
+		Issuer:   os.Getenv("OIDC_ISSUER"),
+		Audience: os.Getenv("OIDC_AUDIENCE"),
+		JWKSURL:  os.Getenv("OIDC_JWKS_URL"),
+	}
+	if config == (service.OIDCConfig{}) {
+		return nil, nil
+	}
+	verifier, err := service.NewJWTVerifier(config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OIDC configuration, OIDC_ISSUER, OIDC_AUDIENCE and OIDC_JWKS_URL are required: %w", err)
+	}
+	return verifier, nil
+}
+
+// transportConfigFromEnv reads the upstream transport settings, keeping the defaults for unset variables
+func transportConfigFromEnv() (service.TransportConfig, error) {
+	config := service.DefaultTransportConfig()
+
+	if maxIdle := os.Getenv("UPSTREAM_MAX_IDLE_CONNS_PER_HOST"); maxIdle != "" {
+		value, err := strconv.Atoi(maxIdle)
+		if err != nil || value <= 0 {
+			return config, fmt.Errorf("invalid UPSTREAM_MAX_IDLE_CONNS_PER_HOST: %s", maxIdle)
+		}
+		config.MaxIdleConnsPerHost = value
+	}
+
+	durations := map[string]*time.Duration{
+		"UPSTREAM_IDLE_CONN_TIMEOUT":     &config.IdleConnTimeout,
+		"UPSTREAM_KEEP_ALIVE":            &config.KeepAlive,
+		"UPSTREAM_TLS_HANDSHAKE_TIMEOUT": &config.TLSHandshakeTimeout,
+		"UPSTREAM_TIMEOUT":               &config.RequestTimeout,
+	}
+	for key, target := range durations {
+		if value := os.Getenv(key); value != "" {
+			duration, err := time.ParseDuration(value)
+			if err != nil || duration <= 0 {
+				return config, fmt.Errorf("invalid %s: %s", key, value)
+			}
+			*target = duration
+		}
+	}
+
+	return config, nil
+}
+
+// defaultFaultLatency is the latency injected into delayed upstream requests unless FAULT_LATENCY is set
+const defaultFaultLatency = 2 * time.Second
+
+// faultConfigFromEnv reads the upstream faults to inject from FAULT_LATENCY_PERCENT, FAULT_LATENCY,
+// FAULT_RATE_LIMIT_PERCENT and FAULT_MALFORMED_PERCENT
+func faultConfigFromEnv() (service.FaultConfig, error) {
+	var config service.FaultConfig
+	rates := map[string]*float64{
+		"FAULT_LATENCY_PERCENT":    &config.LatencyRate,
+		"FAULT_RATE_LIMIT_PERCENT": &config.RateLimitRate,
+		"FAULT_MALFORMED_PERCENT":  &config.MalformedRate,
+	}
+	for key, target := range rates {
+		percent, err := positiveFloatFromEnv(key)
+		if err != nil || percent > 100 {
+			return config, fmt.Errorf("invalid %s: %s", key, os.Getenv(key))
+		}
+		*target = percent / 100
+	}
+
+	latency, err := durationFromEnv("FAULT_LATENCY", defaultFaultLatency)
+	if err != nil {
+		return config, err
+	}
+	config.Latency = latency
+	return config, nil
+}
+
+// upstreamPingTimeout bounds how long startup waits for the upstream providers to answer
+const upstreamPingTimeout = 10 * time.Second
+
+// configureLogging applies the log level and the sampling of high-volume log lines
+func configureLogging() error {
+	switch level := os.Getenv("LOG_LEVEL"); level {
+	case "", "info":
+		service.SetDebugLogging(false)
+	case "debug":
+		service.SetDebugLogging(true)
+	default:
+		return fmt.Errorf("invalid LOG_LEVEL: %s, use info or debug", level)
+	}
+
+	burst := service.DefaultLogSampleBurst
+	if burstEnv := os.Getenv("LOG_SAMPLE_BURST"); burstEnv != "" {
+		var err error
+		burst, err = strconv.Atoi(burstEnv)
+		if err != nil || burst <= 0 {
+			return fmt.Errorf("invalid LOG_SAMPLE_BURST: %s", burstEnv)
+		}
+	}
+	// An interval of 0 logs every line
+	interval := time.Duration(0)
+	if os.Getenv("LOG_SAMPLE_INTERVAL") != "0" {
+		var err error
+		if interval, err = durationFromEnv("LOG_SAMPLE_INTERVAL", service.DefaultLogSampleInterval); err != nil {
+			return err
+		}
+	}
+	service.SetLogSampling(burst, interval)
+	return nil
+}
+
+// upstreamRateLimit is the shared upstream rate limit and the limits of single providers
+type upstreamRateLimit struct {
+	requestsPerSecond float64
+	burst             int
+	providers         map[string]service.ProviderRateLimit // Keyed by provider URL
+}
+
+// equal reports whether both rate limits are the same
+func (l upstreamRateLimit) equal(other upstreamRateLimit) bool {
+	return l.requestsPerSecond == other.requestsPerSecond && l.burst == other.burst && maps.Equal(l.providers, other.providers)
+}
+
+// apply configures the shared and the provider rate limits
+func (l upstreamRateLimit) apply() error {
+	if err := service.SetProviderRateLimits(l.providers); err != nil {
+		return err
+	}
+	service.SetUpstreamRateLimit(l.requestsPerSecond, l.burst)
+	return nil
+}
+
+// upstreamRateLimitFromEnv reads the shared upstream rate limit from UPSTREAM_RATE_LIMIT and UPSTREAM_BURST, the
+// default limit if UPSTREAM_RATE_LIMIT is unset, and the limits of the configured providers. A local node is not
+// rate limited
+func upstreamRateLimitFromEnv() (upstreamRateLimit, error) {
+	if os.Getenv("LOCAL_NODE") == "true" {
+		return upstreamRateLimit{requestsPerSecond: math.MaxFloat64, burst: 1}, nil
+	}
+
+	limit := upstreamRateLimit{requestsPerSecond: service.DefaultUpstreamRateLimit, burst: service.DefaultUpstreamBurst}
+	providers, err := providerRateLimitsFromEnv()
+	if err != nil {
+		return limit, err
+	}
+	limit.providers = providers
+
+	rateLimit := os.Getenv("UPSTREAM_RATE_LIMIT")
+	if rateLimit == "" {
+		return limit, nil
+	}
+
+	requestsPerSecond, err := strconv.ParseFloat(rateLimit, 64)
+	if err != nil || requestsPerSecond <= 0 {
+		return limit, fmt.Errorf("invalid UPSTREAM_RATE_LIMIT: %s", rateLimit)
+	}
+	limit.requestsPerSecond, limit.burst = requestsPerSecond, 1
+	if burstEnv := os.Getenv("UPSTREAM_BURST"); burstEnv != "" {
+		limit.burst, err = strconv.Atoi(burstEnv)
+		if err != nil || limit.burst <= 0 {
+			return limit, fmt.Errorf("invalid UPSTREAM_BURST: %s", burstEnv)
+		}
+	}
+	return limit, nil
+}
+
+// providerRateLimitsFromEnv reads the limits of the providers set in ETH_RPC, BEACON_RPC, EXECUTION_RPC and
+// SECONDARY_RPC from <provider>_RATE_LIMIT, <provider>_BURST and <provider>_CU_PER_SECOND
+func providerRateLimitsFromEnv() (map[string]service.ProviderRateLimit, error) {
+	limits := make(map[string]service.ProviderRateLimit)
+	for _, provider := range []string{"ETH_RPC", "BEACON_RPC", "EXECUTION_RPC", "SECONDARY_RPC"} {
+		providerURL := os.Getenv(provider)
+		if providerURL == "" {
+			continue
+		}
+
+		var limit service.ProviderRateLimit
+		var err error
+		if limit.RequestsPerSecond, err = positiveFloatFromEnv(provider + "_RATE_LIMIT"); err != nil {
+			return nil, err
+		}
+		if limit.ComputeUnitsPerSecond, err = positiveFloatFromEnv(provider + "_CU_PER_SECOND"); err != nil {
+			return nil, err
+		}
+		if burstEnv := os.Getenv(provider + "_BURST"); burstEnv != "" {
+			limit.Burst, err = strconv.Atoi(burstEnv)
+			if err != nil || limit.Burst <= 0 {
+				return nil, fmt.Errorf("invalid %s_BURST: %s", provider, burstEnv)
+			}
+		}
+		if quotaEnv := os.Getenv(provider + "_DAILY_QUOTA"); quotaEnv != "" {
+			limit.DailyQuota, err = strconv.ParseInt(quotaEnv, 10, 64)
+			if err != nil || limit.DailyQuota <= 0 {
+				return nil, fmt.Errorf("invalid %s_DAILY_QUOTA: %s", provider, quotaEnv)
+			}
+		}
+		if limit != (service.ProviderRateLimit{}) {
+			limits[providerURL] = limit
+		}
+	}
+	return limits, nil
+}
+
+// positiveFloatFromEnv reads a positive number from the environment, zero if it is unset
+func positiveFloatFromEnv(key string) (float64, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return 0, nil
+	}
+
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil || number <= 0 {
+		return 0, fmt.Errorf("invalid %s: %s", key, value)
+	}
+	return number, nil
+}
+
+// positiveIntFromEnv reads a positive integer from the environment, fallback if it is unset
+func positiveIntFromEnv(key string, fallback int64) (int64, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+
+	number, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || number <= 0 {
+		return 0, fmt.Errorf("invalid %s: %s", key, value)
+	}
+	return number, nil
+}
+
+// Default endpoints of a node on the same host, used when LOCAL_NODE is set
+const (
+	localRPCURL       = "http://localhost:8545"
+	localBeaconURL    = "http://localhost:5052"
+	localExecutionURL = "ws://localhost:8546"
+)
+
+// upstreamConfigFromEnv reads the upstream endpoints from ETH_RPC, BEACON_RPC, EXECUTION_RPC, SECONDARY_RPC,
+// HEDGE_DELAY, QUORUM_BEACON_NODES and BEACON_QUORUM. With LOCAL_NODE set, unset endpoints default to the usual ports of
+// a node on the same host
+func upstreamConfigFromEnv() (service.UpstreamConfig, error) {
+	config := service.UpstreamConfig{
+		RPCURL:       os.Getenv("ETH_RPC"),
+		BeaconURL:    os.Getenv("BEACON_RPC"),
+		ExecutionURL: os.Getenv("EXECUTION_RPC"),
+		SecondaryURL: os.Getenv("SECONDARY_RPC"),
+		HedgeDelay:   service.DefaultHedgeDelay,
+		QuorumURLs:   listFromEnv("QUORUM_BEACON_NODES"),
+	}
+	if delayEnv := os.Getenv("HEDGE_DELAY"); delayEnv != "" {
+		delay, err := time.ParseDuration(delayEnv)
+		if err != nil {
+			return config, err
+		}
+		config.HedgeDelay = delay
+	}
+	if quorumEnv := os.Getenv("BEACON_QUORUM"); quorumEnv != "" {
+		quorum, err := strconv.Atoi(quorumEnv)
+		if err != nil || quorum < 1 || quorum > len(config.QuorumURLs)+1 {
+			return config, fmt.Errorf("invalid BEACON_QUORUM: %s, must be between 1 and the %d beacon nodes", quorumEnv, len(config.QuorumURLs)+1)
+		}
+		config.Quorum = quorum
+	}
+	if os.Getenv("LOCAL_NODE") == "true" {
+		config.RPCURL = cmp.Or(config.RPCURL, localRPCURL)
+		config.BeaconURL = cmp.Or(config.BeaconURL, localBeaconURL)
+		config.ExecutionURL = cmp.Or(config.ExecutionURL, localExecutionURL)
+	}
+	return config, nil
+}
+
+// durationFromEnv reads a positive duration from the environment, returning fallback when it is unset
+func durationFromEnv(key string, fallback time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("invalid %s: %s", key, value)
+	}
+	return duration, nil
+}
+
+// providerAuthFromEnv reads the authentication of an upstream provider from <provider>_HEADERS, a comma separated
+// list of "Name: Value" pairs, and <provider>_TLS_CERT, <provider>_TLS_KEY and <provider>_TLS_CA.
+// ok is false if none of them is set
+func providerAuthFromEnv(provider string) (auth service.ProviderAuth, ok bool, err error) {
+	auth = service.ProviderAuth{
+		CertFile: os.Getenv(provider + "_TLS_CERT"),
+		KeyFile:  os.Getenv(provider + "_TLS_KEY"),
+		CAFile:   os.Getenv(provider + "_TLS_CA"),
+	}
+
+	headers := listFromEnv(provider + "_HEADERS")
+	if len(headers) > 0 {
+		auth.Headers = make(map[string]string, len(headers))
+	}
+	for _, header := range headers {
+		name, value, found := strings.Cut(header, ":")
+		if !found || strings.TrimSpace(name) == "" {
+			return auth, false, fmt.Errorf("invalid %s_HEADERS entry: %s", provider, header)
+		}
+		auth.Headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+
+	ok = len(auth.Headers) > 0 || auth.CertFile != "" || auth.KeyFile != "" || auth.CAFile != ""
+	return auth, ok, nil
+}
+
+// scheduleFromEnv parses the task schedule set in an environment variable, falling back to a default
+func scheduleFromEnv(key, fallback string) (service.Schedule, error) {
+	schedule, err := service.ParseSchedule(envOrDefault(key, fallback))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %v", key, err)
+	}
+	return schedule, nil
+}